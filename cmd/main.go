@@ -20,26 +20,144 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
 
 	"github.com/kiagnose/kiagnose/kiagnose/environment"
 
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg"
 )
 
+const errMessagePrefix = "kubevirt-dpdk-checkup failed"
+
 func main() {
-	log.Println("kubevirt-dpdk-checkup starting...")
-	rawEnv := environment.EnvToMap(os.Environ())
+	if err := newRootCommand().Execute(); err != nil {
+		log.Printf("%s: %v\n", errMessagePrefix, err)
+		os.Exit(pkg.ExitCode(err))
+	}
+}
+
+// newRootCommand returns the checkup command. Without a subcommand, it runs as a kiagnose checkup inside the cluster,
+// reading its params from the checkup ConfigMap.
+func newRootCommand() *cobra.Command {
+	var runLocalSim bool
+	var simConfigPath string
+	var kubeconfigPath string
+	var namespace string
+
+	rootCmd := &cobra.Command{
+		Use:           "kubevirt-dpdk-checkup",
+		Short:         "Checks DPDK traffic between KubeVirt VMs",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			log.Println("kubevirt-dpdk-checkup starting...")
+
+			if runLocalSim {
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+				defer stop()
+
+				return pkg.RunLocalSim(ctx, simConfigPath)
+			}
+
+			if namespace == "" {
+				var err error
+				if namespace, err = environment.ReadNamespaceFile(); err != nil {
+					return err
+				}
+			}
+
+			// Deleting the checkup Job terminates its Pod, the run is cancelled so its resources are torn down.
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, os.Interrupt)
+			defer stop()
+
+			return pkg.Run(ctx, environment.EnvToMap(os.Environ()), namespace, kubeconfigPath)
+		},
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return pkg.ConfigError(err)
+	})
 
-	const errMessagePrefix = "kubevirt-dpdk-checkup failed"
+	rootCmd.Flags().BoolVar(&runLocalSim, "run-local-sim", false,
+		"run the checkup on an in-memory simulated cluster with scripted guests, without KubeVirt")
+	rootCmd.Flags().StringVar(&simConfigPath, "sim-config", "",
+		"checkup ConfigMap manifest whose params are applied to the simulated run (default: a short run)")
+	rootCmd.Flags().StringVar(&kubeconfigPath, "kubeconfig", "",
+		"path of the kubeconfig file of the cluster (default: the in-cluster config, $KUBECONFIG or ~/.kube/config)")
+	rootCmd.Flags().StringVarP(&namespace, "namespace", "n", "",
+		"namespace to create the checkup VMs in (default: the namespace of the checkup Pod)")
 
-	namespace, err := environment.ReadNamespaceFile()
-	if err != nil {
-		log.Fatalf("%s: %v\n", errMessagePrefix, err)
+	rootCmd.AddCommand(
+		newStandaloneCommand("run", "Runs the checkup against a cluster, printing the results to stdout", pkg.RunStandalone),
+		newStandaloneCommand("preflight", "Runs the pre-flight checks of the checkup against a cluster, without creating any resource",
+			pkg.RunPreflight),
+		newVersionCommand(),
+	)
+
+	return rootCmd
+}
+
+// newStandaloneCommand returns a command running outside of kiagnose, with its params given as flags or environment
+// variables rather than in the checkup ConfigMap.
+func newStandaloneCommand(use, short string, runFn func(context.Context, pkg.StandaloneOptions) error) *cobra.Command {
+	var opts pkg.StandaloneOptions
+	var rawParams []string
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long: short + ".\n\nParams are set with --param <name>=<value>, e.g. --param networkAttachmentDefinitionName=dpdk-net,\n" +
+			"or with " + pkg.ParamsEnvPrefix + "<name> environment variables. Flags take precedence over environment variables.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			params, err := pkg.ParseParams(os.Environ(), rawParams)
+			if err != nil {
+				return err
+			}
+			opts.Params = params
+
+			if opts.Namespace == "" {
+				return pkg.ConfigError(fmt.Errorf("--namespace or %s must be set", namespaceEnvVarName))
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, os.Interrupt)
+			defer stop()
+
+			return runFn(ctx, opts)
+		},
 	}
 
-	if err = pkg.Run(rawEnv, namespace); err != nil {
-		log.Fatalf("%s: %v\n", errMessagePrefix, err)
+	cmd.Flags().StringVar(&opts.Kubeconfig, "kubeconfig", "",
+		"path of the kubeconfig file of the cluster (default: the in-cluster config, $KUBECONFIG or ~/.kube/config)")
+	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", os.Getenv(namespaceEnvVarName),
+		"namespace to create the checkup VMs in (default: $"+namespaceEnvVarName+")")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", defaultStandaloneTimeout, "timeout of the whole run")
+	cmd.Flags().StringArrayVarP(&rawParams, "param", "p", nil, "checkup param as <name>=<value>, can be repeated")
+
+	return cmd
+}
+
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Prints the checkup version",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			fmt.Fprintln(cmd.OutOrStdout(), pkg.Version())
+		},
 	}
 }
+
+const (
+	namespaceEnvVarName      = "DPDK_CHECKUP_NAMESPACE"
+	defaultStandaloneTimeout = 30 * time.Minute
+)
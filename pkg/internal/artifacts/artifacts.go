@@ -0,0 +1,74 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	k8scorev1 "k8s.io/api/core/v1"
+)
+
+var (
+	ErrNotRunningInPod = errors.New("the artifacts PVC requires the checkup to run in a Pod")
+	ErrPVCNotMounted   = errors.New("the artifacts PVC is not mounted to the checkup container")
+)
+
+type podGetter interface {
+	GetPod(ctx context.Context, namespace, name string) (*k8scorev1.Pod, error)
+}
+
+// PVCMountPath returns the path the given PVC is mounted at, with write access, in the checkup Pod.
+// The checkup cannot mount volumes to its own running Pod, so the PVC is expected to be mounted to the checkup Job.
+func PVCMountPath(ctx context.Context, client podGetter, namespace, podName, pvcName string) (string, error) {
+	if podName == "" {
+		return "", ErrNotRunningInPod
+	}
+
+	pod, err := client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the checkup Pod \"%s/%s\": %w", namespace, podName, err)
+	}
+
+	volumeName := pvcVolumeName(pod, pvcName)
+	if volumeName == "" {
+		return "", fmt.Errorf("%w: Pod %q has no volume of PVC %q", ErrPVCNotMounted, podName, pvcName)
+	}
+
+	for i := range pod.Spec.Containers {
+		for _, volumeMount := range pod.Spec.Containers[i].VolumeMounts {
+			if volumeMount.Name == volumeName && !volumeMount.ReadOnly {
+				return volumeMount.MountPath, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: volume %q of PVC %q is not mounted with write access", ErrPVCNotMounted, volumeName, pvcName)
+}
+
+func pvcVolumeName(pod *k8scorev1.Pod, pvcName string) string {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvcName {
+			return volume.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package artifacts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/artifacts"
+)
+
+const (
+	testNamespace = "target-ns"
+	testPodName   = "dpdk-checkup-pod"
+	testPVCName   = "dpdk-checkup-artifacts"
+	testMountPath = "/artifacts"
+)
+
+func TestPVCMountPathShouldReturnTheMountPathOfThePVC(t *testing.T) {
+	client := podGetterStub{pod: newCheckupPod(false)}
+
+	mountPath, err := artifacts.PVCMountPath(context.Background(), client, testNamespace, testPodName, testPVCName)
+	assert.NoError(t, err)
+	assert.Equal(t, testMountPath, mountPath)
+}
+
+func TestPVCMountPathShouldFailWhen(t *testing.T) {
+	t.Run("not running in a Pod", func(t *testing.T) {
+		client := podGetterStub{pod: newCheckupPod(false)}
+
+		_, err := artifacts.PVCMountPath(context.Background(), client, testNamespace, "", testPVCName)
+		assert.ErrorIs(t, err, artifacts.ErrNotRunningInPod)
+	})
+
+	t.Run("the Pod cannot be read", func(t *testing.T) {
+		expectedErr := errors.New("failed to get Pod")
+		client := podGetterStub{failure: expectedErr}
+
+		_, err := artifacts.PVCMountPath(context.Background(), client, testNamespace, testPodName, testPVCName)
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("the PVC is not a volume of the Pod", func(t *testing.T) {
+		client := podGetterStub{pod: newCheckupPod(false)}
+
+		_, err := artifacts.PVCMountPath(context.Background(), client, testNamespace, testPodName, "other-pvc")
+		assert.ErrorIs(t, err, artifacts.ErrPVCNotMounted)
+	})
+
+	t.Run("the PVC is mounted read-only", func(t *testing.T) {
+		client := podGetterStub{pod: newCheckupPod(true)}
+
+		_, err := artifacts.PVCMountPath(context.Background(), client, testNamespace, testPodName, testPVCName)
+		assert.ErrorIs(t, err, artifacts.ErrPVCNotMounted)
+	})
+}
+
+type podGetterStub struct {
+	pod     *k8scorev1.Pod
+	failure error
+}
+
+func (s podGetterStub) GetPod(_ context.Context, _, _ string) (*k8scorev1.Pod, error) {
+	if s.failure != nil {
+		return nil, s.failure
+	}
+	return s.pod, nil
+}
+
+func newCheckupPod(readOnly bool) *k8scorev1.Pod {
+	const volumeName = "artifacts"
+	pod := &k8scorev1.Pod{}
+	pod.Name = testPodName
+	pod.Spec.Volumes = []k8scorev1.Volume{
+		{
+			Name: volumeName,
+			VolumeSource: k8scorev1.VolumeSource{
+				PersistentVolumeClaim: &k8scorev1.PersistentVolumeClaimVolumeSource{ClaimName: testPVCName},
+			},
+		},
+	}
+	pod.Spec.Containers = []k8scorev1.Container{
+		{
+			Name:         "dpdk-checkup",
+			VolumeMounts: []k8scorev1.VolumeMount{{Name: volumeName, MountPath: testMountPath, ReadOnly: readOnly}},
+		},
+	}
+	return pod
+}
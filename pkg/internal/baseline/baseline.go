@@ -0,0 +1,94 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package baseline compares the results of a run with the results of an earlier run, as reported to its ConfigMap.
+package baseline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kiagnose/kiagnose/kiagnose/types"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/reporter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// Tolerance is the relative change of a result, beyond which a worse result is considered a regression.
+const Tolerance = 0.05
+
+const (
+	VerdictNoRegression = "no regression"
+	VerdictRegressed    = "regressed"
+)
+
+// Compare compares the results with the baseline results, as found in the data of the baseline ConfigMap.
+// The average RX rate regresses when it decreased beyond the tolerance, and the dropped packets regress when they
+// increased beyond the tolerance, or at all when there were none in the baseline.
+func Compare(data map[string]string, results status.Results) (*status.BaselineComparison, error) {
+	baselineAvgRxPps, err := parseResult(data, reporter.TrafficGenAvgRxPpsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var baselineDroppedPackets int64
+	for _, key := range []string{reporter.VMUnderTestRxDroppedPacketsKey, reporter.VMUnderTestTxDroppedPacketsKey} {
+		dropped, err := parseResult(data, key)
+		if err != nil {
+			return nil, err
+		}
+		baselineDroppedPackets += int64(dropped)
+	}
+
+	comparison := &status.BaselineComparison{}
+	var regressions []string
+	if baselineAvgRxPps > 0 && results.TrafficGenAvgRxPps > 0 {
+		comparison.AvgRxPpsDeltaPercent = 100 * (results.TrafficGenAvgRxPps - baselineAvgRxPps) / baselineAvgRxPps
+		if comparison.AvgRxPpsDeltaPercent < -100*Tolerance {
+			regressions = append(regressions, fmt.Sprintf("average RX rate %.1f%%", comparison.AvgRxPpsDeltaPercent))
+		}
+	}
+
+	droppedPackets := results.VMUnderTestRxDroppedPackets + results.VMUnderTestTxDroppedPackets
+	comparison.DroppedPacketsDelta = droppedPackets - baselineDroppedPackets
+	if comparison.DroppedPacketsDelta > 0 && float64(droppedPackets) > float64(baselineDroppedPackets)*(1+Tolerance) {
+		regressions = append(regressions, fmt.Sprintf("dropped packets %+d", comparison.DroppedPacketsDelta))
+	}
+
+	comparison.Verdict = VerdictNoRegression
+	if len(regressions) > 0 {
+		comparison.Verdict = VerdictRegressed + ": " + strings.Join(regressions, ", ")
+	}
+
+	return comparison, nil
+}
+
+func parseResult(data map[string]string, key string) (float64, error) {
+	rawVal, exists := data[types.ResultsPrefix+key]
+	if !exists {
+		return 0, fmt.Errorf("the baseline has no %s result", key)
+	}
+
+	value, err := strconv.ParseFloat(rawVal, 64)
+	if err != nil {
+		return 0, fmt.Errorf("the baseline %s result %q is invalid: %w", key, rawVal, err)
+	}
+	return value, nil
+}
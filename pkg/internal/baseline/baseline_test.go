@@ -0,0 +1,92 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package baseline_test
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/baseline"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+func TestCompareShouldReportTheDeltas(t *testing.T) {
+	tests := map[string]struct {
+		results            status.Results
+		expectedComparison status.BaselineComparison
+	}{
+		"when the results did not regress": {
+			results: status.Results{TrafficGenAvgRxPps: 7_800_000, VMUnderTestRxDroppedPackets: 10},
+			expectedComparison: status.BaselineComparison{
+				AvgRxPpsDeltaPercent: -2.5,
+				DroppedPacketsDelta:  0,
+				Verdict:              baseline.VerdictNoRegression,
+			},
+		},
+		"when the average RX rate regressed": {
+			results: status.Results{TrafficGenAvgRxPps: 7_200_000, VMUnderTestRxDroppedPackets: 10},
+			expectedComparison: status.BaselineComparison{
+				AvgRxPpsDeltaPercent: -10,
+				DroppedPacketsDelta:  0,
+				Verdict:              "regressed: average RX rate -10.0%",
+			},
+		},
+		"when the dropped packets regressed": {
+			results: status.Results{TrafficGenAvgRxPps: 8_000_000, VMUnderTestRxDroppedPackets: 10, VMUnderTestTxDroppedPackets: 5},
+			expectedComparison: status.BaselineComparison{
+				AvgRxPpsDeltaPercent: 0,
+				DroppedPacketsDelta:  5,
+				Verdict:              "regressed: dropped packets +5",
+			},
+		},
+	}
+
+	baselineData := map[string]string{
+		"status.result.trafficGenAvgRxPps":          "8000000",
+		"status.result.vmUnderTestRxDroppedPackets": "10",
+		"status.result.vmUnderTestTxDroppedPackets": "0",
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			comparison, err := baseline.Compare(baselineData, testCase.results)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedComparison, *comparison)
+		})
+	}
+}
+
+func TestCompareShouldNotCompareTheRatesWhenNotSampled(t *testing.T) {
+	baselineData := map[string]string{
+		"status.result.trafficGenAvgRxPps":          "0",
+		"status.result.vmUnderTestRxDroppedPackets": "0",
+		"status.result.vmUnderTestTxDroppedPackets": "0",
+	}
+
+	comparison, err := baseline.Compare(baselineData, status.Results{TrafficGenAvgRxPps: 8_000_000})
+	assert.NoError(t, err)
+	assert.Equal(t, status.BaselineComparison{Verdict: baseline.VerdictNoRegression}, *comparison)
+}
+
+func TestCompareShouldFailWhenTheBaselineHasNoResults(t *testing.T) {
+	_, err := baseline.Compare(map[string]string{"status.succeeded": "false"}, status.Results{})
+	assert.ErrorContains(t, err, "the baseline has no trafficGenAvgRxPps result")
+}
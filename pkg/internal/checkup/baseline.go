@@ -0,0 +1,51 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"log"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/baseline"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// compareToBaseline compares the results with the results reported to the baseline ConfigMap by an earlier run.
+// The comparison is informative, so failing to read the baseline results is reported rather than failing the checkup.
+func (c *Checkup) compareToBaseline(ctx context.Context) *status.BaselineComparison {
+	name := c.params.BaselineConfigMapName
+	comparison, err := c.readBaseline(ctx, name)
+	if err != nil {
+		log.Printf("Warning: failed to compare the results to baseline ConfigMap %q: %v", ObjectFullName(c.namespace, name), err)
+		return &status.BaselineComparison{ConfigMapName: name, Verdict: "undetermined, " + err.Error()}
+	}
+
+	comparison.ConfigMapName = name
+	log.Printf("Results compared to baseline ConfigMap %q: %s", ObjectFullName(c.namespace, name), comparison.Verdict)
+	return comparison
+}
+
+func (c *Checkup) readBaseline(ctx context.Context, name string) (*status.BaselineComparison, error) {
+	configMap, err := c.client.GetConfigMap(ctx, c.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return baseline.Compare(configMap.Data, c.results)
+}
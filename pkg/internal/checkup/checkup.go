@@ -21,32 +21,69 @@ package checkup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"path"
 	"strings"
+	"sync"
 	"time"
 
 	k8scorev1 "k8s.io/api/core/v1"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/util/rand"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 
 	kvcorev1 "kubevirt.io/api/core/v1"
 
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/configmap"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/criteria"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/vmi"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/events"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
 )
 
+// The checkup phases recorded in the results timeline.
+const (
+	ConfigMapsCreationPhase = "configmaps creation"
+	VMIsCreationPhase       = "VMIs creation"
+	VMIsBootPhase           = "VMIs boot"
+	TeardownPhase           = "teardown"
+)
+
+// ErrVMIsOnTheSameNode is returned by Setup when different nodes are required, and both VMIs were scheduled on one node.
+var ErrVMIsOnTheSameNode = errors.New("VMIs were scheduled on the same node")
+
+type kubeVirtVMClient interface {
+	CreateVirtualMachine(ctx context.Context, namespace string, vm *kvcorev1.VirtualMachine) (*kvcorev1.VirtualMachine, error)
+	ListVirtualMachines(ctx context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineList, error)
+	DeleteVirtualMachine(ctx context.Context, namespace, name string) error
+}
+
 type kubeVirtVMIClient interface {
+	kubeVirtVMClient
 	CreateVirtualMachineInstance(ctx context.Context,
 		namespace string,
 		vmi *kvcorev1.VirtualMachineInstance) (*kvcorev1.VirtualMachineInstance, error)
 	GetVirtualMachineInstance(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstance, error)
+	ListVirtualMachineInstances(ctx context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineInstanceList, error)
 	DeleteVirtualMachineInstance(ctx context.Context, namespace, name string) error
+	WatchVirtualMachineInstance(ctx context.Context, namespace, name, resourceVersion string) (watch.Interface, error)
 	CreateConfigMap(ctx context.Context, namespace string, configMap *k8scorev1.ConfigMap) (*k8scorev1.ConfigMap, error)
+	GetConfigMap(ctx context.Context, namespace, name string) (*k8scorev1.ConfigMap, error)
+	ListConfigMaps(ctx context.Context, namespace, labelSelector string) (*k8scorev1.ConfigMapList, error)
 	DeleteConfigMap(ctx context.Context, namespace, name string) error
+	GetPod(ctx context.Context, namespace, name string) (*k8scorev1.Pod, error)
+	ListPods(ctx context.Context, namespace, labelSelector string) (*k8scorev1.PodList, error)
+	GetNode(ctx context.Context, name string) (*k8scorev1.Node, error)
+	ListNodes(ctx context.Context, labelSelector string) (*k8scorev1.NodeList, error)
+	GetNetworkAttachmentDefinition(ctx context.Context, namespace, name string) (*netattdefv1.NetworkAttachmentDefinition, error)
+	GetKubeVirtVersion(ctx context.Context) (string, error)
+	CreateEvent(ctx context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error)
 }
 
 type testExecutor interface {
@@ -63,6 +100,8 @@ type Checkup struct {
 	vmiUnderTestConfigMap *k8scorev1.ConfigMap
 	results               status.Results
 	executor              testExecutor
+	events                events.Recorder
+	failed                bool
 }
 
 const (
@@ -71,111 +110,421 @@ const (
 )
 
 func New(client kubeVirtVMIClient, namespace string, checkupConfig config.Config, executor testExecutor) *Checkup {
-	const randomStringLen = 5
-	randomSuffix := rand.String(randomStringLen)
-
-	trafficGenCMName := trafficGenConfigMapName(randomSuffix)
-	vmiUnderTestCMName := vmiUnderTestConfigMapName(randomSuffix)
-
 	return &Checkup{
 		client:                client,
 		namespace:             namespace,
 		params:                checkupConfig,
-		vmiUnderTest:          newVMIUnderTest(vmiUnderTestName(randomSuffix), checkupConfig, vmiUnderTestCMName),
-		vmiUnderTestConfigMap: newVMIUnderTestConfigMap(vmiUnderTestCMName, checkupConfig),
-		trafficGen:            newTrafficGen(trafficGenName(randomSuffix), checkupConfig, trafficGenCMName),
-		trafficGenConfigMap:   newTrafficGenConfigMap(trafficGenCMName, checkupConfig),
+		vmiUnderTest:          newVMIUnderTest(checkupConfig),
+		vmiUnderTestConfigMap: newVMIUnderTestConfigMap(checkupConfig),
+		trafficGen:            newTrafficGen(checkupConfig),
+		trafficGenConfigMap:   newTrafficGenConfigMap(checkupConfig),
 		executor:              executor,
+		events:                events.New(client, namespace, checkupConfig.PodName, checkupConfig.PodUID),
 	}
 }
 
 func (c *Checkup) Setup(ctx context.Context) (setupErr error) {
-	const setupTimeout = 15 * time.Minute
-	setupCtx, cancel := context.WithTimeout(ctx, setupTimeout)
+	setupCtx, cancel := context.WithTimeout(ctx, c.setupTimeout())
 	defer cancel()
 
+	// The teardown is skipped once the setup fails, and in the label ownership mode nothing else deletes the ConfigMaps
+	defer func() {
+		if setupErr != nil {
+			c.cleanupCreatedConfigMaps()
+		}
+	}()
+
 	const errMessagePrefix = "setup"
 	var err error
 
+	if err = c.preflight(setupCtx); err != nil {
+		return fmt.Errorf("%s: %w", errMessagePrefix, err)
+	}
+
+	if err = c.checkOtherRuns(setupCtx); err != nil {
+		return fmt.Errorf("%s: %w", errMessagePrefix, err)
+	}
+
+	configMapsCreationStart := time.Now()
 	if err = c.createConfigmap(setupCtx, c.trafficGenConfigMap); err != nil {
 		return fmt.Errorf("%s: %w", errMessagePrefix, err)
 	}
+	setConfigMapVolumesName(c.trafficGen, c.trafficGenConfigMap.Name)
 
 	if err = c.createConfigmap(setupCtx, c.vmiUnderTestConfigMap); err != nil {
 		return fmt.Errorf("%s: %w", errMessagePrefix, err)
 	}
+	setConfigMapVolumesName(c.vmiUnderTest, c.vmiUnderTestConfigMap.Name)
+	c.results.Timeline.Record(ConfigMapsCreationPhase, configMapsCreationStart)
+
+	c.adjustToKubeVirtVersion(setupCtx)
+	c.detectEnvironment(setupCtx)
+
+	if err = c.setupVMIs(setupCtx); err != nil {
+		return err
+	}
 
-	if err = c.createVMI(setupCtx, c.vmiUnderTest); err != nil {
+	c.collectLaunchersSecurityPosture(setupCtx)
+
+	if err = c.verifyLaunchersRuntimeClass(setupCtx); err != nil {
+		c.cleanupCreatedVMIs()
 		return fmt.Errorf("%s: %w", errMessagePrefix, err)
 	}
-	defer func() {
-		if setupErr != nil {
-			c.cleanupVMI(c.vmiUnderTest.Name)
+
+	return nil
+}
+
+// Preflight validates the cluster is able to run the checkup, without creating any resource.
+// Its outcome is reported in the results, as the node readiness and resource footprint.
+func (c *Checkup) Preflight(ctx context.Context) error {
+	preflightCtx, cancel := context.WithTimeout(ctx, c.setupTimeout())
+	defer cancel()
+
+	if err := c.preflight(preflightCtx); err != nil {
+		return fmt.Errorf("preflight: %w", err)
+	}
+
+	return nil
+}
+
+// preflight checks the nodes readiness, and validates the Network-Attachment-Definitions, the CPU assignments and
+// the TRex config file, before any resource is created.
+func (c *Checkup) preflight(ctx context.Context) error {
+	var readinessErr error
+	if c.results.NodeReadiness, readinessErr = c.checkNodesReadiness(ctx); readinessErr != nil {
+		log.Printf("Skipping node readiness checks: %v", readinessErr)
+	} else {
+		logNodesReadiness(c.results.NodeReadiness)
+	}
+
+	if err := c.validateNetworkAttachmentDefinitions(ctx); err != nil {
+		return err
+	}
+
+	if readinessErr == nil {
+		if err := c.verifyNodesCapability(c.results.NodeReadiness); err != nil {
+			return err
 		}
-	}()
+	}
 
-	if err = c.createVMI(setupCtx, c.trafficGen); err != nil {
-		return fmt.Errorf("%s: %w", errMessagePrefix, err)
+	c.results.ResourceFootprint = resourceFootprint(c.vmiUnderTest, c.trafficGen)
+
+	if err := c.validateCPUAssignments(); err != nil {
+		return exitcode.Classify(exitcode.ConfigError, err)
 	}
-	defer func() {
-		if setupErr != nil {
-			c.cleanupVMI(c.trafficGen.Name)
+
+	return trex.ValidateCfgFile(c.trafficGenConfigMap.Data[trex.CfgFileName])
+}
+
+// createVMIsAndWaitForReadiness creates both VMIs, and waits for them to be ready.
+// On failure, the VMIs that were created are left in place, for the caller to clean up.
+func (c *Checkup) createVMIsAndWaitForReadiness(ctx context.Context) error {
+	const errMessagePrefix = "setup"
+
+	// The VMIs are created and booted concurrently, as waiting for each of them in turn dominates the setup time.
+	var (
+		mutex           sync.Mutex
+		vmisCreationEnd time.Time
+		scheduledNodes  = map[string]string{}
+	)
+	// A co-located pair is detected once both VMIs are scheduled, rather than after both of them booted
+	checkPlacement := func(vmiName, nodeName string) error {
+		if !c.params.RequireDifferentNodes {
+			return nil
 		}
-	}()
 
-	var updatedVMIUnderTest *kvcorev1.VirtualMachineInstance
-	updatedVMIUnderTest, err = c.waitForVMIToBeReady(setupCtx, c.vmiUnderTest.Name)
-	if err != nil {
-		return err
+		mutex.Lock()
+		defer mutex.Unlock()
+		scheduledNodes[vmiName] = nodeName
+		for otherVMIName, otherNodeName := range scheduledNodes {
+			if otherVMIName != vmiName && otherNodeName == nodeName {
+				return fmt.Errorf("%w, both on %q", ErrVMIsOnTheSameNode, nodeName)
+			}
+		}
+		return nil
+	}
+	createVMIAndWaitForReadiness := func(ctx context.Context,
+		vmiToCreate *kvcorev1.VirtualMachineInstance) (*kvcorev1.VirtualMachineInstance, error) {
+		if err := c.createVMI(ctx, vmiToCreate); err != nil {
+			return nil, fmt.Errorf("%s: %w", errMessagePrefix, err)
+		}
+
+		mutex.Lock()
+		vmisCreationEnd = time.Now()
+		mutex.Unlock()
+
+		return c.waitForVMIToBeReady(ctx, vmiToCreate.Name, checkPlacement)
 	}
 
-	c.vmiUnderTest = updatedVMIUnderTest
-	var updatedTrafficGen *kvcorev1.VirtualMachineInstance
-	updatedTrafficGen, err = c.waitForVMIToBeReady(setupCtx, c.trafficGen.Name)
+	vmisCreationStart := time.Now()
+	var updatedVMIUnderTest, updatedTrafficGen *kvcorev1.VirtualMachineInstance
+	err := runConcurrently(ctx,
+		func(ctx context.Context) (err error) {
+			updatedVMIUnderTest, err = createVMIAndWaitForReadiness(ctx, c.vmiUnderTest)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			updatedTrafficGen, err = createVMIAndWaitForReadiness(ctx, c.trafficGen)
+			return err
+		},
+	)
 	if err != nil {
 		return err
 	}
 
-	c.trafficGen = updatedTrafficGen
+	c.vmiUnderTest, c.trafficGen = updatedVMIUnderTest, updatedTrafficGen
+	c.results.Timeline = append(c.results.Timeline,
+		status.PhaseTiming{Phase: VMIsCreationPhase, Start: vmisCreationStart, End: vmisCreationEnd})
+	c.results.Timeline.Record(VMIsBootPhase, vmisCreationEnd)
+
+	// The pod affinity cannot hold when both VMIs are scheduled before either of them is bound to a node
+	if c.params.SameNodePlacement && c.vmiUnderTest.Status.NodeName != c.trafficGen.Status.NodeName {
+		return fmt.Errorf("%s: VMIs were scheduled on different nodes, VM under test on %q and traffic generator on %q",
+			errMessagePrefix, c.vmiUnderTest.Status.NodeName, c.trafficGen.Status.NodeName)
+	}
 
 	return nil
 }
 
-func (c *Checkup) Run(ctx context.Context) error {
-	var err error
+// runConcurrently runs the given functions concurrently, and returns the first error any of them has returned.
+// Once any of them fails, the context the others were given is cancelled.
+func runConcurrently(ctx context.Context, funcs ...func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, f := range funcs {
+		wg.Add(1)
+		go func(f func(context.Context) error) {
+			defer wg.Done()
+			if err := f(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(f)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func (c *Checkup) Run(ctx context.Context) (runErr error) {
+	defer func() {
+		c.failed = runErr != nil
+	}()
 
-	c.results, err = c.executor.Execute(ctx, c.vmiUnderTest.Name, c.trafficGen.Name)
+	results, err := c.executor.Execute(ctx, c.vmiUnderTest.Name, c.trafficGen.Name)
+	results.NodeReadiness = c.results.NodeReadiness
+	results.TrafficGenLauncherSecurity = c.results.TrafficGenLauncherSecurity
+	results.VMUnderTestLauncherSecurity = c.results.VMUnderTestLauncherSecurity
+	results.ResourceFootprint = c.results.ResourceFootprint
+	results.Environment = withClusterEnvironment(results.Environment, c.results.Environment)
+	results.SetupRetries = c.results.SetupRetries
+	results.Timeline = append(c.results.Timeline, results.Timeline...)
+	c.results = results
 	if err != nil {
-		return err
+		return exitcode.Classify(exitcode.Inconclusive, err)
 	}
 	c.results.VMUnderTestActualNodeName = c.vmiUnderTest.Status.NodeName
 	c.results.TrafficGenActualNodeName = c.trafficGen.Status.NodeName
+	c.results.OwnershipMode = c.params.OwnershipMode()
+	if c.params.BaselineConfigMapName != "" {
+		c.results.Baseline = c.compareToBaseline(ctx)
+	}
+	if c.params.SameNodePlacement && len(c.results.Scenarios) == 0 {
+		c.results.Scenarios = []status.ScenarioResults{{ID: status.SameNodeScenarioID, Counters: packetCounters(c.results)}}
+	}
 
-	if c.results.TrafficGenSentPackets == 0 {
-		return fmt.Errorf("no packets were sent from the traffic generator")
+	// The stats collected until the abort are reported as is, as no verdict can be reached on a partial run
+	if c.results.AbortRequested {
+		return exitcode.Classify(exitcode.Cancelled, fmt.Errorf("the run was aborted by the %q ConfigMap key", config.AbortKey))
 	}
 
-	if c.results.TrafficGenOutputErrorPackets != 0 || c.results.TrafficGenInputErrorPackets != 0 {
-		return fmt.Errorf("detected Error Packets on the traffic generator's side: Oerrors %d Ierrors %d",
-			c.results.TrafficGenOutputErrorPackets, c.results.TrafficGenInputErrorPackets)
+	if err = c.verifyResults(); err != nil {
+		return exitcode.Classify(exitcode.PerformanceFailure, err)
 	}
 
-	if c.results.VMUnderTestRxDroppedPackets != 0 || c.results.VMUnderTestTxDroppedPackets != 0 {
-		return fmt.Errorf("detected packets dropped on the VM-Under-Test's side: RX: %d; TX: %d",
-			c.results.VMUnderTestRxDroppedPackets, c.results.VMUnderTestTxDroppedPackets)
+	if err = criteria.Evaluate(criteria.Registered(), c.results); err != nil {
+		return exitcode.Classify(exitcode.PerformanceFailure, err)
 	}
 
-	if c.results.TrafficGenSentPackets != c.results.VMUnderTestReceivedPackets {
-		return fmt.Errorf("not all generated packets had reached VM-Under-Test: Sent from traffic generator: %d; Received on VM-Under-Test: %d",
-			c.results.TrafficGenSentPackets, c.results.VMUnderTestReceivedPackets)
+	if err = verifySoak(c.results.Soak); err != nil {
+		return exitcode.Classify(exitcode.PerformanceFailure, err)
 	}
 
-	return nil
+	return exitcode.Classify(exitcode.Inconclusive, verifyTxDuration(c.results.TxDuration))
+}
+
+// verifySoak fails when the traffic was aborted by the soak snapshots, even though the partial counters may pass.
+func verifySoak(soak *status.Soak) error {
+	if soak == nil || soak.AbortReason == "" {
+		return nil
+	}
+
+	return fmt.Errorf("the traffic was aborted early: %s", soak.AbortReason)
+}
+
+// verifyTxDuration fails when the traffic generator transmitted for much shorter or longer than requested,
+// as the traffic was throttled or its streams ended early, thus the counters cannot be trusted to pass the checkup.
+func verifyTxDuration(txDuration *status.TxDuration) error {
+	const maxTxDurationDeltaPercent = 10
+	if txDuration == nil || math.Abs(txDuration.DeltaPercent) <= maxTxDurationDeltaPercent {
+		return nil
+	}
+
+	return fmt.Errorf("the traffic generator transmitted for %.1fs out of the requested %.0fs (%+.1f%%), "+
+		"the traffic was throttled or ended early", txDuration.ActualSeconds, txDuration.RequestedSeconds, txDuration.DeltaPercent)
+}
+
+func (c *Checkup) verifyResults() error {
+	var outcomes []status.CriterionOutcome
+	var err error
+	if len(c.results.Scenarios) > 0 {
+		outcomes, err = c.verifyScenarios(c.results.Scenarios)
+		c.results.DirectionsSummary = summarizeDirections(c.results.Scenarios)
+	} else {
+		outcomes, err = c.verifyPacketCounters(packetCounters(c.results))
+	}
+
+	if c.params.MaxAllowedDropRateBps > 0 {
+		dropRateOutcomes, dropRateErr := c.evaluateChecks(checkDropRate(c.results.MaxDropRateBps, c.params.MaxAllowedDropRateBps))
+		outcomes = append(outcomes, dropRateOutcomes...)
+		if err == nil {
+			err = dropRateErr
+		}
+	}
+
+	if len(outcomes) > 0 {
+		c.results.Criteria = &status.CriteriaResults{Outcomes: outcomes, Score: score(outcomes)}
+	}
+
+	return err
+}
+
+func packetCounters(results status.Results) status.PacketCounters {
+	return status.PacketCounters{
+		TrafficGenSentPackets:        results.TrafficGenSentPackets,
+		TrafficGenOutputErrorPackets: results.TrafficGenOutputErrorPackets,
+		TrafficGenInputErrorPackets:  results.TrafficGenInputErrorPackets,
+		VMUnderTestReceivedPackets:   results.VMUnderTestReceivedPackets,
+		VMUnderTestRxDroppedPackets:  results.VMUnderTestRxDroppedPackets,
+		VMUnderTestTxDroppedPackets:  results.VMUnderTestTxDroppedPackets,
+	}
+}
+
+// verifyScenarios sets the verdict of each scenario, and fails when any of them has failed.
+// A criterion outcome is a pass only when it has passed in all the scenarios.
+func (c *Checkup) verifyScenarios(scenarios []status.ScenarioResults) ([]status.CriterionOutcome, error) {
+	var failedScenarios []string
+	var outcomes []status.CriterionOutcome
+	for i := range scenarios {
+		scenarioOutcomes, err := c.verifyPacketCounters(scenarios[i].Counters)
+		outcomes = mergeOutcomes(outcomes, scenarios[i].ID, scenarioOutcomes)
+		if err != nil {
+			scenarios[i].Succeeded = false
+			scenarios[i].FailureReason = err.Error()
+			failedScenarios = append(failedScenarios, fmt.Sprintf("%s: %v", scenarios[i].ID, err))
+			continue
+		}
+		scenarios[i].Succeeded = true
+	}
+
+	if len(failedScenarios) > 0 {
+		return outcomes, fmt.Errorf("%d out of %d scenarios failed: %s",
+			len(failedScenarios), len(scenarios), strings.Join(failedScenarios, "; "))
+	}
+
+	return outcomes, nil
+}
+
+// summarizeDirections compares the verdicts of per-direction runs.
+// When a single direction fails, the fault is narrowed down to the interfaces that only that direction is received on.
+func summarizeDirections(scenarios []status.ScenarioResults) string {
+	var eastToWest, westToEast *status.ScenarioResults
+	for i := range scenarios {
+		switch scenarios[i].ID {
+		case status.EastToWestScenarioID:
+			eastToWest = &scenarios[i]
+		case status.WestToEastScenarioID:
+			westToEast = &scenarios[i]
+		}
+	}
+	if eastToWest == nil || westToEast == nil {
+		return ""
+	}
+
+	switch {
+	case eastToWest.Succeeded && westToEast.Succeeded:
+		return "both directions succeeded"
+	case !eastToWest.Succeeded && !westToEast.Succeeded:
+		return "both directions failed, the fault is common to both directions"
+	case !eastToWest.Succeeded:
+		return fmt.Sprintf("only the %s direction failed, suspect the VM under test east and the traffic generator west "+
+			"interfaces (their Network-Attachment-Definition, VF and switch port)", status.EastToWestScenarioID)
+	default:
+		return fmt.Sprintf("only the %s direction failed, suspect the VM under test west and the traffic generator east "+
+			"interfaces (their Network-Attachment-Definition, VF and switch port)", status.WestToEastScenarioID)
+	}
+}
+
+// verifyPacketCounters evaluates the built-in criteria over the packet counters, and fails when a blocker criterion
+// is not met. Failing criteria of lower severities are only reported in their outcomes.
+func (c *Checkup) verifyPacketCounters(counters status.PacketCounters) ([]status.CriterionOutcome, error) {
+	if counters.TrafficGenSentPackets == 0 {
+		return nil, fmt.Errorf("no packets were sent from the traffic generator")
+	}
+
+	return c.evaluateChecks(checkPacketCounters(counters)...)
+}
+
+// evaluateChecks sets the severity of each of the checked criteria, and fails when a blocker criterion is not met.
+func (c *Checkup) evaluateChecks(checks ...criterionCheck) ([]status.CriterionOutcome, error) {
+	var outcomes []status.CriterionOutcome
+	var blockerErr error
+	for _, check := range checks {
+		outcome := status.CriterionOutcome{
+			Name:     check.name,
+			Severity: c.params.CriterionSeverity(check.name),
+			Passed:   check.err == nil,
+		}
+		if check.err != nil {
+			outcome.FailureReason = check.err.Error()
+			if outcome.Severity != config.SeverityBlocker {
+				log.Printf("%s criterion of %s severity was not met: %v", check.name, outcome.Severity, check.err)
+			} else if blockerErr == nil {
+				blockerErr = check.err
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes, blockerErr
 }
 
 func (c *Checkup) Teardown(ctx context.Context) error {
 	const errMessagePrefix = "teardown"
 
+	if c.skipTeardown(c.failed) {
+		log.Printf("Skipping teardown, keeping VMIs %q, %q and ConfigMaps %q, %q for inspection",
+			ObjectFullName(c.namespace, c.vmiUnderTest.Name), ObjectFullName(c.namespace, c.trafficGen.Name),
+			ObjectFullName(c.namespace, c.vmiUnderTestConfigMap.Name), ObjectFullName(c.namespace, c.trafficGenConfigMap.Name))
+		return nil
+	}
+
+	teardownStart := time.Now()
+	defer func() {
+		c.results.Timeline.Record(TeardownPhase, teardownStart)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.params.TeardownTimeout)
+	defer cancel()
+
 	var teardownErrors []string
 	if err := c.deleteVMI(ctx, c.vmiUnderTest.Name); err != nil {
 		teardownErrors = append(teardownErrors, fmt.Sprintf("%s: %v", errMessagePrefix, err))
@@ -212,11 +561,18 @@ func (c *Checkup) Results() status.Results {
 	return c.results
 }
 
+// createConfigmap creates the ConfigMap, and sets its name to the one generated by the API server.
 func (c *Checkup) createConfigmap(ctx context.Context, configMap *k8scorev1.ConfigMap) error {
-	log.Printf("Creating ConfigMap %q...", ObjectFullName(c.namespace, configMap.Name))
+	log.Printf("Creating ConfigMap %q...", ObjectFullName(c.namespace, configMap.GenerateName))
 
-	_, err := c.client.CreateConfigMap(ctx, c.namespace, configMap)
-	return err
+	createdConfigMap, err := c.client.CreateConfigMap(ctx, c.namespace, configMap)
+	if err != nil {
+		return err
+	}
+	configMap.Name = createdConfigMap.Name
+	log.Printf("ConfigMap %q was created", ObjectFullName(c.namespace, configMap.Name))
+
+	return nil
 }
 
 func (c *Checkup) deleteConfigmap(ctx context.Context, configMap *k8scorev1.ConfigMap) error {
@@ -225,36 +581,81 @@ func (c *Checkup) deleteConfigmap(ctx context.Context, configMap *k8scorev1.Conf
 	return c.client.DeleteConfigMap(ctx, c.namespace, configMap.Name)
 }
 
+// createVMI creates the VMI, or its VM, and sets its name to the one generated by the API server.
+// The VM controller names the VMI after its VM.
 func (c *Checkup) createVMI(ctx context.Context, vmiToCreate *kvcorev1.VirtualMachineInstance) error {
-	log.Printf("Creating VMI %q...", ObjectFullName(c.namespace, vmiToCreate.Name))
+	if c.params.UseVirtualMachines {
+		log.Printf("Creating VM %q...", ObjectFullName(c.namespace, vmiToCreate.GenerateName))
 
-	_, err := c.client.CreateVirtualMachineInstance(ctx, c.namespace, vmiToCreate)
-	return err
+		createdVM, err := c.client.CreateVirtualMachine(ctx, c.namespace, vmi.NewVirtualMachine(vmiToCreate))
+		if err != nil {
+			return err
+		}
+		vmiToCreate.Name = createdVM.Name
+		log.Printf("VM %q was created", ObjectFullName(c.namespace, vmiToCreate.Name))
+		c.events.Normal(events.ReasonVMICreated, "VM %q was created", ObjectFullName(c.namespace, vmiToCreate.Name))
+
+		return nil
+	}
+
+	log.Printf("Creating VMI %q...", ObjectFullName(c.namespace, vmiToCreate.GenerateName))
+
+	createdVMI, err := c.client.CreateVirtualMachineInstance(ctx, c.namespace, vmiToCreate)
+	if err != nil {
+		return err
+	}
+	vmiToCreate.Name = createdVMI.Name
+	log.Printf("VMI %q was created", ObjectFullName(c.namespace, vmiToCreate.Name))
+	c.events.Normal(events.ReasonVMICreated, "VMI %q was created", ObjectFullName(c.namespace, vmiToCreate.Name))
+
+	return nil
 }
 
-func (c *Checkup) waitForVMIToBeReady(ctx context.Context, name string) (*kvcorev1.VirtualMachineInstance, error) {
+// waitForVMIToBeReady waits for the VMI to be ready, passing the node it is scheduled on to onScheduled.
+// An onScheduled error stops the wait.
+func (c *Checkup) waitForVMIToBeReady(ctx context.Context, name string,
+	onScheduled func(vmiName, nodeName string) error) (*kvcorev1.VirtualMachineInstance, error) {
 	vmiFullName := ObjectFullName(c.namespace, name)
 	log.Printf("Waiting for VMI %q to be ready...", vmiFullName)
 	var updatedVMI *kvcorev1.VirtualMachineInstance
 
-	conditionFn := func(ctx context.Context) (bool, error) {
-		var err error
-		updatedVMI, err = c.client.GetVirtualMachineInstance(ctx, c.namespace, name)
-		if err != nil {
-			return false, err
-		}
+	bootCtx := ctx
+	if c.params.VMIBootTimeout > 0 {
+		var cancel context.CancelFunc
+		bootCtx, cancel = context.WithTimeout(ctx, c.params.VMIBootTimeout)
+		defer cancel()
+	}
 
-		for _, condition := range updatedVMI.Status.Conditions {
-			if condition.Type == kvcorev1.VirtualMachineInstanceReady && condition.Status == k8scorev1.ConditionTrue {
-				return true, nil
+	isReady := func(vmi *kvcorev1.VirtualMachineInstance) (bool, error) {
+		if vmi == nil {
+			// A VMI that was not seen yet may not be readable yet, and the VM controller creates the VMI of a VM only
+			// after the VM is created, re-creating it once deleted
+			if updatedVMI == nil || c.params.UseVirtualMachines {
+				return false, nil
 			}
+			return false, fmt.Errorf("VMI %q was deleted", vmiFullName)
 		}
-
-		return false, nil
+		updatedVMI = vmi
+		if vmi.Status.NodeName != "" {
+			if err := onScheduled(name, vmi.Status.NodeName); err != nil {
+				return false, err
+			}
+		}
+		return isVMIReady(vmi), nil
 	}
-	const pollInterval = 5 * time.Second
-	if err := wait.PollImmediateUntilWithContext(ctx, pollInterval, conditionFn); err != nil {
-		return nil, fmt.Errorf("failed to wait for VMI %q to be ready: %v", vmiFullName, err)
+	if err := c.waitForVMI(bootCtx, name, isReady); err != nil {
+		if errors.Is(err, ErrVMIsOnTheSameNode) {
+			return nil, fmt.Errorf("setup: %w", err)
+		}
+		if cancelErr := exitcode.CheckCancelled(ctx, fmt.Sprintf("waiting for VMI %q to be ready", vmiFullName)); cancelErr != nil {
+			return nil, cancelErr
+		}
+		if bootCtx.Err() != nil {
+			c.events.Warning(events.ReasonVMIBootTimeout, "VMI %q was not ready within the %s VMI boot timeout",
+				vmiFullName, c.params.VMIBootTimeout)
+			return nil, fmt.Errorf("VMI %q was not ready within the %s VMI boot timeout", vmiFullName, c.params.VMIBootTimeout)
+		}
+		return nil, fmt.Errorf("failed to wait for VMI %q to be ready: %w", vmiFullName, err)
 	}
 
 	log.Printf("VMI %q has successfully reached ready condition", vmiFullName)
@@ -265,6 +666,16 @@ func (c *Checkup) waitForVMIToBeReady(ctx context.Context, name string) (*kvcore
 func (c *Checkup) deleteVMI(ctx context.Context, name string) error {
 	vmiFullName := ObjectFullName(c.namespace, name)
 
+	if c.params.UseVirtualMachines {
+		log.Printf("Trying to delete VM: %q", vmiFullName)
+		if err := c.client.DeleteVirtualMachine(ctx, c.namespace, name); err != nil {
+			log.Printf("Failed to delete VM: %q", vmiFullName)
+			return err
+		}
+
+		return nil
+	}
+
 	log.Printf("Trying to delete VMI: %q", vmiFullName)
 	if err := c.client.DeleteVirtualMachineInstance(ctx, c.namespace, name); err != nil {
 		log.Printf("Failed to delete VMI: %q", vmiFullName)
@@ -278,15 +689,10 @@ func (c *Checkup) waitForVMIDeletion(ctx context.Context, name string) error {
 	vmiFullName := ObjectFullName(c.namespace, name)
 	log.Printf("Waiting for VMI %q to be deleted...", vmiFullName)
 
-	conditionFn := func(ctx context.Context) (bool, error) {
-		_, err := c.client.GetVirtualMachineInstance(ctx, c.namespace, name)
-		if k8serrors.IsNotFound(err) {
-			return true, nil
-		}
-		return false, err
+	isDeleted := func(vmi *kvcorev1.VirtualMachineInstance) (bool, error) {
+		return vmi == nil, nil
 	}
-	const pollInterval = 5 * time.Second
-	if err := wait.PollImmediateUntilWithContext(ctx, pollInterval, conditionFn); err != nil {
+	if err := c.waitForVMI(ctx, name, isDeleted); err != nil {
 		return fmt.Errorf("failed to wait for VMI %q to be deleted: %v", vmiFullName, err)
 	}
 
@@ -299,6 +705,10 @@ func (c *Checkup) cleanupVMI(name string) {
 	const setupCleanupTimeout = 30 * time.Second
 
 	vmiFullName := ObjectFullName(c.namespace, name)
+	if c.skipTeardown(true) {
+		log.Printf("setup failed, keeping VMI %q for inspection", vmiFullName)
+		return
+	}
 	log.Printf("setup failed, cleanup VMI %q", vmiFullName)
 
 	delCtx, cancel := context.WithTimeout(context.Background(), setupCleanupTimeout)
@@ -311,53 +721,88 @@ func (c *Checkup) cleanupVMI(name string) {
 	}
 }
 
+// cleanupCreatedConfigMaps cleans up the ConfigMaps of a failed setup, unless they are kept for inspection.
+func (c *Checkup) cleanupCreatedConfigMaps() {
+	const setupCleanupTimeout = 30 * time.Second
+
+	for _, configMap := range []*k8scorev1.ConfigMap{c.trafficGenConfigMap, c.vmiUnderTestConfigMap} {
+		if configMap.Name == "" {
+			continue
+		}
+
+		configMapFullName := ObjectFullName(c.namespace, configMap.Name)
+		if c.skipTeardown(true) {
+			log.Printf("setup failed, keeping ConfigMap %q for inspection", configMapFullName)
+			continue
+		}
+		log.Printf("setup failed, cleanup ConfigMap %q", configMapFullName)
+
+		delCtx, cancel := context.WithTimeout(context.Background(), setupCleanupTimeout)
+		if err := c.deleteConfigmap(delCtx, configMap); err != nil {
+			log.Printf("Failed to delete ConfigMap %q: %v", configMapFullName, err)
+		}
+		cancel()
+	}
+}
+
+// skipTeardown reports whether the checkup resources are kept in place for inspection, given whether the checkup has failed.
+func (c *Checkup) skipTeardown(failed bool) bool {
+	switch c.params.SkipTeardown {
+	case config.SkipTeardownAlways:
+		return true
+	case config.SkipTeardownOnFailure:
+		return failed
+	default:
+		return false
+	}
+}
+
 func ObjectFullName(namespace, name string) string {
 	return fmt.Sprintf("%s/%s", namespace, name)
 }
 
-func newVMIUnderTestConfigMap(name string, checkupConfig config.Config) *k8scorev1.ConfigMap {
+func newVMIUnderTestConfigMap(checkupConfig config.Config) *k8scorev1.ConfigMap {
 	vmiUnderTestConfigData := map[string]string{
-		config.BootScriptName: generateBootScript(),
+		config.BootScriptName: generateBootScript(vmUnderTestMacAddresses(checkupConfig.TestInterfaces()),
+			checkupConfig.NICDriverMode, ""),
 	}
 
 	return configmap.New(
-		name,
+		vmiUnderTestConfigMapNamePrefix+"-",
 		checkupConfig.PodName,
 		checkupConfig.PodUID,
+		map[string]string{DPDKCheckupUIDLabelKey: checkupConfig.CheckupUID},
 		vmiUnderTestConfigData,
 	)
 }
 
-func newTrafficGenConfigMap(name string, checkupConfig config.Config) *k8scorev1.ConfigMap {
+func newTrafficGenConfigMap(checkupConfig config.Config) *k8scorev1.ConfigMap {
 	trexConfig := trex.NewConfig(checkupConfig)
 	trafficGenConfigData := map[string]string{
-		trex.SystemdUnitFileName:        trex.GenerateSystemdUnitFile(),
+		trex.SystemdUnitFileName:        trexConfig.GenerateSystemdUnitFile(),
 		trex.ExecutionScriptName:        trexConfig.GenerateExecutionScript(),
 		trex.CfgFileName:                trexConfig.GenerateCfgFile(),
 		trex.StreamPyFileName:           trexConfig.GenerateStreamPyFile(),
+		trex.MulticastStreamPyFileName:  trexConfig.GenerateMulticastStreamPyFile(),
+		trex.BurstStreamPyFileName:      trexConfig.GenerateBurstStreamPyFile(),
 		trex.StreamPeerParamsPyFileName: trexConfig.GenerateStreamAddrPyFile(),
-		config.BootScriptName:           generateBootScript(),
+		config.BootScriptName: generateBootScript(trafficGenMacAddresses(checkupConfig.TestInterfaces()),
+			checkupConfig.NICDriverMode, path.Join(trafficGenCfgDirectory, trex.CfgFileName)),
 	}
 	return configmap.New(
-		name,
+		TrafficGenConfigMapNamePrefix+"-",
 		checkupConfig.PodName,
 		checkupConfig.PodUID,
+		map[string]string{DPDKCheckupUIDLabelKey: checkupConfig.CheckupUID},
 		trafficGenConfigData,
 	)
 }
 
-func vmiUnderTestName(suffix string) string {
-	return VMIUnderTestNamePrefix + "-" + suffix
-}
-
-func trafficGenName(suffix string) string {
-	return TrafficGenNamePrefix + "-" + suffix
-}
-
-func trafficGenConfigMapName(suffix string) string {
-	return TrafficGenConfigMapNamePrefix + "-" + suffix
-}
-
-func vmiUnderTestConfigMapName(suffix string) string {
-	return vmiUnderTestConfigMapNamePrefix + "-" + suffix
+// setConfigMapVolumesName points the VMI ConfigMap volumes to the given ConfigMap, once its name is generated.
+func setConfigMapVolumesName(vmiToUpdate *kvcorev1.VirtualMachineInstance, configMapName string) {
+	for i := range vmiToUpdate.Spec.Volumes {
+		if configMapSource := vmiToUpdate.Spec.Volumes[i].ConfigMap; configMapSource != nil {
+			configMapSource.Name = configMapName
+		}
+	}
 }
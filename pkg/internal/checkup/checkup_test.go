@@ -24,21 +24,32 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	assert "github.com/stretchr/testify/require"
 
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
 	k8scorev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/watch"
 
 	kvcorev1 "kubevirt.io/api/core/v1"
 
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/events"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
 )
 
@@ -51,6 +62,9 @@ const (
 	trafficGeneratorWestMacAddress      = "DE:AD:BE:EF:01:00"
 	vmiUnderTestEastMacAddress          = "DE:AD:BE:EF:00:02"
 	vmiUnderTestWestMacAddress          = "DE:AD:BE:EF:02:00"
+	sriovResourceName                   = "openshift.io/dpdk_nic"
+	testNodeName                        = "dpdk-node01"
+	testKubeVirtVersion                 = "v1.0.0"
 )
 
 func TestCheckupShouldSucceed(t *testing.T) {
@@ -58,7 +72,14 @@ func TestCheckupShouldSucceed(t *testing.T) {
 	testConfig := newTestConfig()
 
 	expectedResults := successfulRunResults()
+	const executorPhase = "traffic iteration 1/1"
+	expectedResults.Timeline.Record(executorPhase, time.Now())
 	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: expectedResults})
+	expectedResults.OwnershipMode = config.OwnershipModeOwnerReference
+	expectedResults.NodeReadiness = testNodeReadiness()
+	expectedResults.ResourceFootprint = expectedResourceFootprint()
+	expectedResults.Environment = &status.Environment{KubeVirtVersion: testKubeVirtVersion}
+	expectedResults.Criteria = &status.CriteriaResults{Outcomes: blockerOutcomes(nil), Score: 100}
 
 	assert.NoError(t, testCheckup.Setup(context.Background()))
 
@@ -77,9 +98,54 @@ func TestCheckupShouldSucceed(t *testing.T) {
 	assert.Empty(t, testClient.createdConfigMaps)
 
 	actualResults := testCheckup.Results()
+	assert.Equal(t, []string{
+		checkup.ConfigMapsCreationPhase,
+		checkup.VMIsCreationPhase,
+		checkup.VMIsBootPhase,
+		executorPhase,
+		checkup.TeardownPhase,
+	}, timelinePhases(actualResults.Timeline))
+	for _, phase := range actualResults.Timeline {
+		assert.False(t, phase.End.Before(phase.Start), phase.Phase)
+	}
+
+	expectedResults.Timeline = nil
+	actualResults.Timeline = nil
 	assert.Equal(t, expectedResults, actualResults)
 }
 
+func TestSetupShouldReferTheGeneratedConfigMapsNames(t *testing.T) {
+	testClient := newClientStub()
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: successfulRunResults()})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	for _, vmiNamePrefix := range []string{checkup.VMIUnderTestNamePrefix, checkup.TrafficGenNamePrefix} {
+		vmiName := testClient.VMIName(vmiNamePrefix)
+		assert.True(t, strings.HasPrefix(vmiName, vmiNamePrefix+"-"), vmiName)
+
+		var configMapNames []string
+		for _, volume := range testClient.createdVMIs[checkup.ObjectFullName(testNamespace, vmiName)].Spec.Volumes {
+			if volume.ConfigMap != nil {
+				configMapNames = append(configMapNames, volume.ConfigMap.Name)
+			}
+		}
+		assert.Len(t, configMapNames, 1)
+		assert.Contains(t, testClient.createdConfigMaps, checkup.ObjectFullName(testNamespace, configMapNames[0]))
+	}
+
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+	assert.Empty(t, testClient.createdConfigMaps)
+}
+
+func timelinePhases(timeline status.Timeline) []string {
+	var phases []string
+	for _, phase := range timeline {
+		phases = append(phases, phase.Phase)
+	}
+	return phases
+}
+
 func TestVMIAffinity(t *testing.T) {
 	t.Run("when node names are not specified", func(t *testing.T) {
 		testClient := newClientStub()
@@ -93,10 +159,10 @@ func TestVMIAffinity(t *testing.T) {
 		trafficGenName := testClient.VMIName(checkup.TrafficGenNamePrefix)
 		assert.NotEmpty(t, trafficGenName)
 
-		assertPodAntiAffinityExists(t, testClient, vmiUnderTestName, testConfig.PodUID)
+		assertPodAntiAffinityExists(t, testClient, vmiUnderTestName, testConfig.CheckupUID)
 		assertNodeAffinityDoesNotExist(t, testClient, vmiUnderTestName)
 
-		assertPodAntiAffinityExists(t, testClient, trafficGenName, testConfig.PodUID)
+		assertPodAntiAffinityExists(t, testClient, trafficGenName, testConfig.CheckupUID)
 		assertNodeAffinityDoesNotExist(t, testClient, trafficGenName)
 	})
 
@@ -126,184 +192,1683 @@ func TestVMIAffinity(t *testing.T) {
 		assertNodeAffinityExists(t, testClient, trafficGenName, trafficGenNodeName)
 		assertPodAntiAffinityDoesNotExist(t, testClient, trafficGenName)
 	})
-}
-
-func TestSetupShouldFail(t *testing.T) {
-	t.Run("when Traffic gen ConfigMap creation fails", func(t *testing.T) {
-		expectedConfigMapCreationError := errors.New("failed to create ConfigMap")
 
+	t.Run("when node label selectors are specified", func(t *testing.T) {
 		testClient := newClientStub()
 		testConfig := newTestConfig()
-		testClient.configMapCreationFailure = expectedConfigMapCreationError
+		testConfig.TrafficGenNodeLabelSelector = "node-role.kubernetes.io/worker-dpdk"
+		testConfig.VMUnderTestNodeLabelSelector = "node-role.kubernetes.io/worker-dpdk,zone=a"
+
 		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+		assert.NoError(t, testCheckup.Setup(context.Background()))
 
-		assert.ErrorContains(t, testCheckup.Setup(context.Background()), expectedConfigMapCreationError.Error())
-		assert.Empty(t, testClient.createdVMIs)
+		for vmiName, nodeLabelSelector := range map[string]string{
+			testClient.VMIName(checkup.VMIUnderTestNamePrefix): testConfig.VMUnderTestNodeLabelSelector,
+			testClient.VMIName(checkup.TrafficGenNamePrefix):   testConfig.TrafficGenNodeLabelSelector,
+		} {
+			actualVMI, err := testClient.GetVirtualMachineInstance(context.Background(), testNamespace, vmiName)
+			assert.NoError(t, err)
+			assert.Equal(t, checkup.Affinity("", nodeLabelSelector, testConfig.CheckupUID, false, false), actualVMI.Spec.Affinity)
+		}
 	})
 
-	t.Run("when VMI creation fails", func(t *testing.T) {
-		expectedVMICreationFailure := errors.New("failed to create VMI")
+	t.Run("when same node placement is requested", func(t *testing.T) {
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+		testConfig.SameNodePlacement = true
+
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+
+		for _, vmiName := range []string{
+			testClient.VMIName(checkup.VMIUnderTestNamePrefix),
+			testClient.VMIName(checkup.TrafficGenNamePrefix),
+		} {
+			actualVMI, err := testClient.GetVirtualMachineInstance(context.Background(), testNamespace, vmiName)
+			assert.NoError(t, err)
+			assert.Equal(t, checkup.Affinity("", "", testConfig.CheckupUID, true, false), actualVMI.Spec.Affinity)
+		}
+	})
+}
 
+func TestSetupShouldFailWhenTheVMIsAreScheduledOnTheSameNodeWhileDifferentNodesAreRequired(t *testing.T) {
+	t.Run("before the VMIs are ready", func(t *testing.T) {
 		testClient := newClientStub()
+		testClient.vmiNodeName = testNodeName
+		testClient.vmiNeverReady = true
 		testConfig := newTestConfig()
-		testClient.vmiCreationFailure = expectedVMICreationFailure
+		testConfig.RequireDifferentNodes = true
 		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
 
-		assert.ErrorContains(t, testCheckup.Setup(context.Background()), expectedVMICreationFailure.Error())
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		assert.ErrorIs(t, testCheckup.Setup(ctx), checkup.ErrVMIsOnTheSameNode)
 		assert.Empty(t, testClient.createdVMIs)
 	})
 
-	t.Run("when wait for VMI to boot fails", func(t *testing.T) {
-		expectedVMIReadFailure := errors.New("failed to read VMI")
-
+	t.Run("when retrying, the VM under test avoids their node", func(t *testing.T) {
 		testClient := newClientStub()
+		testClient.vmiNodeName = testNodeName
 		testConfig := newTestConfig()
-		testClient.vmiReadFailure = expectedVMIReadFailure
+		testConfig.RequireDifferentNodes = true
+		testConfig.SetupRetries = 1
+		testConfig.SetupAttemptTimeout = time.Minute
+		testConfig.SetupRetryBackoff = time.Millisecond
+		testConfig.SkipTeardown = config.SkipTeardownOnFailure
 		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
 
-		assert.ErrorContains(t, testCheckup.Setup(context.Background()), expectedVMIReadFailure.Error())
-		assert.Empty(t, testClient.createdVMIs)
+		assert.ErrorIs(t, testCheckup.Setup(context.Background()), checkup.ErrVMIsOnTheSameNode)
+
+		vmiUnderTest, err := testClient.GetVirtualMachineInstance(context.Background(), testNamespace,
+			testClient.VMIName(checkup.VMIUnderTestNamePrefix))
+		assert.NoError(t, err)
+		expectedNodeAffinity := &k8scorev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &k8scorev1.NodeSelector{
+				NodeSelectorTerms: []k8scorev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []k8scorev1.NodeSelectorRequirement{
+							{
+								Key:      k8scorev1.LabelHostname,
+								Operator: k8scorev1.NodeSelectorOpNotIn,
+								Values:   []string{testNodeName}},
+						},
+					},
+				},
+			},
+		}
+		assert.Equal(t, expectedNodeAffinity, vmiUnderTest.Spec.Affinity.NodeAffinity)
+
+		trafficGen, err := testClient.GetVirtualMachineInstance(context.Background(), testNamespace,
+			testClient.VMIName(checkup.TrafficGenNamePrefix))
+		assert.NoError(t, err)
+		assert.Nil(t, trafficGen.Spec.Affinity.NodeAffinity)
 	})
 }
 
-func TestTeardownShouldFailWhen(t *testing.T) {
-	t.Run("VMI deletion fails", func(t *testing.T) {
-		testClient := newClientStub()
+func TestCheckupWithoutOwnerPod(t *testing.T) {
+	const checkupUID = "9876543210-9876543210"
+
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+	testConfig.PodUID = ""
+	testConfig.CheckupUID = checkupUID
+
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	for _, configMap := range testClient.createdConfigMaps {
+		assert.Empty(t, configMap.OwnerReferences)
+	}
+
+	for _, vmi := range testClient.createdVMIs {
+		assert.Empty(t, vmi.OwnerReferences)
+		assert.Equal(t, checkupUID, vmi.Labels[checkup.DPDKCheckupUIDLabelKey])
+	}
+
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+	assert.Equal(t, config.OwnershipModeLabel, testCheckup.Results().OwnershipMode)
+}
+
+func TestSetupShouldCleanupTheConfigMapsOnFailure(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiCreationFailure = errors.New("failed to create VMI")
+
+	// Standalone runs, whose resources are not garbage collected along with an owner Pod
+	for run := 1; run <= 2; run++ {
 		testConfig := newTestConfig()
+		testConfig.PodName = ""
+		testConfig.PodUID = ""
+		testConfig.CheckupUID = fmt.Sprintf("standalone-run-%d", run)
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
 
-		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+		err := testCheckup.Setup(context.Background())
+		assert.NotErrorIs(t, err, checkup.ErrAnotherCheckupRunning, "run %d", run)
+		assert.ErrorIs(t, err, testClient.vmiCreationFailure, "run %d", run)
+		assert.Empty(t, testClient.createdConfigMaps, "run %d", run)
+	}
+}
+
+func TestSetupShouldRecordTheVMIsCreationEventsOnTheCheckupPod(t *testing.T) {
+	testClient := newClientStub()
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	assert.Equal(t,
+		[]string{events.ReasonVMICreated, events.ReasonVMICreated},
+		recordedEventReasons(testClient, k8scorev1.EventTypeNormal),
+	)
+	for _, event := range testClient.recordedEvents {
+		assert.Equal(t, "Pod", event.InvolvedObject.Kind)
+		assert.Equal(t, testPodName, event.InvolvedObject.Name)
+		assert.Equal(t, types.UID(testPodUID), event.InvolvedObject.UID)
+		assert.Equal(t, events.Component, event.Source.Component)
+	}
+}
+
+func recordedEventReasons(testClient *clientStub, eventType string) []string {
+	var reasons []string
+	for _, event := range testClient.recordedEvents {
+		if event.Type == eventType {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	return reasons
+}
+
+func TestSetupShouldDeleteTheLeftoversOfCrashedRuns(t *testing.T) {
+	const (
+		runningPodName = "running-checkup-pod"
+		failedPodName  = "failed-checkup-pod"
+		deletedPodName = "deleted-checkup-pod"
+	)
+
+	testClient := newClientStub()
+	testClient.pods = []k8scorev1.Pod{
+		newCheckupPod(runningPodName, k8scorev1.PodRunning),
+		newCheckupPod(failedPodName, k8scorev1.PodFailed),
+	}
+	leftoverVMI := newLeftoverVMI("leftover-vmi", deletedPodName)
+	concurrentVMI := newLeftoverVMI("concurrent-vmi", runningPodName)
+	unownedVMI := newLeftoverVMI("unowned-vmi", "")
+	testClient.createdVMIs = map[string]*kvcorev1.VirtualMachineInstance{
+		checkup.ObjectFullName(testNamespace, leftoverVMI.Name):   leftoverVMI,
+		checkup.ObjectFullName(testNamespace, concurrentVMI.Name): concurrentVMI,
+		checkup.ObjectFullName(testNamespace, unownedVMI.Name):    unownedVMI,
+	}
+	leftoverConfigMap := &k8scorev1.ConfigMap{ObjectMeta: newLeftoverVMI("leftover-configmap", failedPodName).ObjectMeta}
+	testClient.createdConfigMaps = map[string]*k8scorev1.ConfigMap{
+		checkup.ObjectFullName(testNamespace, leftoverConfigMap.Name): leftoverConfigMap,
+	}
+
+	testConfig := newTestConfig()
+	testConfig.AllowConcurrentRuns = true
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	assert.NotContains(t, testClient.createdVMIs, checkup.ObjectFullName(testNamespace, leftoverVMI.Name))
+	assert.Contains(t, testClient.createdVMIs, checkup.ObjectFullName(testNamespace, concurrentVMI.Name))
+	assert.Contains(t, testClient.createdVMIs, checkup.ObjectFullName(testNamespace, unownedVMI.Name))
+	assert.NotContains(t, testClient.createdConfigMaps, checkup.ObjectFullName(testNamespace, leftoverConfigMap.Name))
+	assert.NotEmpty(t, testClient.VMIName(checkup.VMIUnderTestNamePrefix))
+	assert.NotEmpty(t, testClient.VMIName(checkup.TrafficGenNamePrefix))
+}
+
+func TestSetupShouldFailWhenAnotherCheckupIsRunning(t *testing.T) {
+	const runningPodName = "running-checkup-pod"
+
+	testCases := map[string]*kvcorev1.VirtualMachineInstance{
+		"owned by a running Pod": newLeftoverVMI("concurrent-vmi", runningPodName),
+		"without an owner Pod":   newLeftoverVMI("unowned-vmi", ""),
+	}
+
+	for description, otherRunVMI := range testCases {
+		t.Run(description, func(t *testing.T) {
+			testClient := newClientStub()
+			testClient.pods = []k8scorev1.Pod{newCheckupPod(runningPodName, k8scorev1.PodRunning)}
+			testClient.createdVMIs = map[string]*kvcorev1.VirtualMachineInstance{
+				checkup.ObjectFullName(testNamespace, otherRunVMI.Name): otherRunVMI,
+			}
+
+			testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: successfulRunResults()})
+			err := testCheckup.Setup(context.Background())
+
+			assert.ErrorIs(t, err, checkup.ErrAnotherCheckupRunning)
+			assert.ErrorContains(t, err, "VMI "+checkup.ObjectFullName(testNamespace, otherRunVMI.Name))
+			assert.Len(t, testClient.createdVMIs, 1)
+			assert.Empty(t, testClient.createdConfigMaps)
+		})
+	}
+}
+
+func newCheckupPod(name string, phase k8scorev1.PodPhase) k8scorev1.Pod {
+	return k8scorev1.Pod{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: types.UID(name + "-uid")},
+		Status:     k8scorev1.PodStatus{Phase: phase},
+	}
+}
+
+// newLeftoverVMI returns a VMI of another checkup run, owned by the given Pod, if any.
+func newLeftoverVMI(name, ownerPodName string) *kvcorev1.VirtualMachineInstance {
+	leftoverVMI := &kvcorev1.VirtualMachineInstance{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{checkup.DPDKCheckupUIDLabelKey: "another-checkup-uid"},
+		},
+	}
+	if ownerPodName != "" {
+		leftoverVMI.OwnerReferences = []k8smetav1.OwnerReference{
+			{APIVersion: "v1", Kind: "Pod", Name: ownerPodName, UID: types.UID(ownerPodName + "-uid")},
+		}
+	}
+
+	return leftoverVMI
+}
+
+func TestSetupShouldReportNodeReadiness(t *testing.T) {
+	const (
+		readyNodeName   = "ready-node"
+		unreadyNodeName = "unready-node"
+	)
+
+	newTestClient := func() *clientStub {
+		testClient := newClientStub()
+		testClient.nodes = map[string]*k8scorev1.Node{
+			readyNodeName: newReadyNode(readyNodeName, sriovResourceName),
+			unreadyNodeName: {
+				ObjectMeta: k8smetav1.ObjectMeta{Name: unreadyNodeName},
+				Spec:       k8scorev1.NodeSpec{Unschedulable: true},
+			},
+		}
+		return testClient
+	}
+
+	expectedReadiness := []status.NodeReadiness{
+		{
+			NodeName: readyNodeName,
+			Score:    100,
+			Checks: []status.PreflightOutcome{
+				{Name: checkup.NodeReadyCheckName, Passed: true},
+				{Name: checkup.HugepagesCheckName, Passed: true},
+				{Name: checkup.CPUManagerCheckName, Passed: true},
+				{Name: checkup.SRIOVVFsCheckName, Passed: true},
+			},
+		},
+		{
+			NodeName: unreadyNodeName,
+			Score:    0,
+			Checks: []status.PreflightOutcome{
+				{Name: checkup.NodeReadyCheckName, Reason: "node is cordoned"},
+				{Name: checkup.HugepagesCheckName, Reason: "hugepages-1Gi allocatable 0 is less than the required 4Gi"},
+				{Name: checkup.CPUManagerCheckName, Reason: "node is not labeled with cpumanager=true"},
+				{Name: checkup.SRIOVVFsCheckName, Reason: "openshift.io/dpdk_nic allocatable 0 is less than the required 2"},
+			},
+		},
+	}
+
+	t.Run("when setup succeeds", func(t *testing.T) {
+		testClient := newTestClient()
+		testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: successfulRunResults()})
 
 		assert.NoError(t, testCheckup.Setup(context.Background()))
 		assert.NoError(t, testCheckup.Run(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
 
-		testCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
-		defer cancel()
+		assert.Equal(t, expectedReadiness, testCheckup.Results().NodeReadiness)
+	})
 
-		const vmiDeletionFailureErrMsg = "failed to delete VMI"
-		testClient.vmiDeletionFailure = errors.New(vmiDeletionFailureErrMsg)
-		assert.ErrorContains(t, testCheckup.Teardown(testCtx), vmiDeletionFailureErrMsg)
+	t.Run("when setup fails", func(t *testing.T) {
+		testClient := newTestClient()
+		testClient.vmiCreationFailure = errors.New("failed to create VMI")
+		testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+		assert.ErrorContains(t, testCheckup.Setup(context.Background()), testClient.vmiCreationFailure.Error())
+
+		assert.Equal(t, expectedReadiness, testCheckup.Results().NodeReadiness)
 	})
-	t.Run("VMIs were not disposed before timeout expiration", func(t *testing.T) {
+
+	t.Run("when target nodes are specified", func(t *testing.T) {
+		testClient := newTestClient()
+		testConfig := newTestConfig()
+		testConfig.TrafficGenTargetNodeName = readyNodeName
+		testConfig.VMUnderTestTargetNodeName = readyNodeName
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+
+		assert.Equal(t, expectedReadiness[:1], testCheckup.Results().NodeReadiness)
+	})
+}
+
+func TestSetupShouldFailWhenNoNodeIsCapable(t *testing.T) {
+	const incapableNodeName = "incapable-node"
+	incapableNode := &k8scorev1.Node{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: incapableNodeName},
+		Status: k8scorev1.NodeStatus{
+			Conditions: []k8scorev1.NodeCondition{{Type: k8scorev1.NodeReady, Status: k8scorev1.ConditionTrue}},
+		},
+	}
+
+	testCases := []struct {
+		description         string
+		nodes               map[string]*k8scorev1.Node
+		targetNodeName      string
+		expectedErrorReason string
+	}{
+		{
+			description:         "no node is schedulable",
+			nodes:               map[string]*k8scorev1.Node{},
+			expectedErrorReason: "no node is labeled with kubevirt.io/schedulable=true",
+		},
+		{
+			description: "all nodes fail the checks",
+			nodes:       map[string]*k8scorev1.Node{incapableNodeName: incapableNode},
+			expectedErrorReason: "incapable-node: " +
+				"hugepages (hugepages-1Gi allocatable 0 is less than the required 4Gi), " +
+				"cpuManager (node is not labeled with cpumanager=true), " +
+				"sriovVFs (openshift.io/dpdk_nic allocatable 0 is less than the required 2)",
+		},
+		{
+			description: "the target node fails the checks",
+			nodes: map[string]*k8scorev1.Node{
+				testNodeName:      newReadyNode(testNodeName, sriovResourceName),
+				incapableNodeName: incapableNode,
+			},
+			targetNodeName:      incapableNodeName,
+			expectedErrorReason: "incapable-node: hugepages",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			testClient := newClientStub()
+			testClient.nodes = testCase.nodes
+			testConfig := newTestConfig()
+			testConfig.TrafficGenTargetNodeName = testCase.targetNodeName
+			testConfig.VMUnderTestTargetNodeName = testCase.targetNodeName
+			testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+			err := testCheckup.Setup(context.Background())
+			assert.ErrorIs(t, err, checkup.ErrNoCapableNode)
+			assert.ErrorContains(t, err, testCase.expectedErrorReason)
+
+			assert.Empty(t, testClient.createdConfigMaps)
+			assert.Empty(t, testClient.createdVMIs)
+		})
+	}
+}
+
+func TestSetupShouldFailOnIllegalCPUAssignment(t *testing.T) {
+	testCases := map[string]func(cfg *config.Config){
+		"testpmd CPU out of the vCPUs": func(cfg *config.Config) {
+			cfg.TestpmdCPUs = []int{2, 8}
+		},
+		"TRex CPU out of the vCPUs": func(cfg *config.Config) {
+			cfg.TrexCPUs = &config.TrexCPUs{Master: 0, Latency: 1, Traffic: []int{6, 7, 8, 9}}
+		},
+		"TRex traffic CPUs not a multiple of the ports pairs": func(cfg *config.Config) {
+			for idx := 0; idx < 4; idx++ {
+				cfg.Interfaces = append(cfg.Interfaces, config.Interface{
+					Name:                            fmt.Sprintf("nic-%d", idx),
+					NetworkAttachmentDefinitionName: testNetworkAttachmentDefinitionName,
+					PCIAddress:                      fmt.Sprintf("0000:%02x:00.0", 6+idx),
+				})
+			}
+			cfg.TrexCPUs = &config.TrexCPUs{Master: 0, Latency: 1, Traffic: []int{2, 3, 4}}
+		},
+	}
+
+	for description, applyCPUs := range testCases {
+		t.Run(description, func(t *testing.T) {
+			testClient := newClientStub()
+			testConfig := newTestConfig()
+			applyCPUs(&testConfig)
+			testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+			err := testCheckup.Setup(context.Background())
+			assert.ErrorIs(t, err, checkup.ErrIllegalCPUAssignment)
+			assert.Equal(t, exitcode.ConfigError, exitcode.Of(err))
+
+			assert.Empty(t, testClient.createdConfigMaps)
+			assert.Empty(t, testClient.createdVMIs)
+		})
+	}
+}
+
+func TestSetupShouldRequireRealtimeKernel(t *testing.T) {
+	const (
+		realtimeNodeName   = "realtime-node"
+		lowLatencyNodeName = "lowlatency-node"
+		standardNodeName   = "standard-node"
+	)
+
+	realtimeNode := newReadyNode(realtimeNodeName, sriovResourceName)
+	realtimeNode.Status.NodeInfo.KernelVersion = "5.14.0-284.11.1.rt14.296.el9_2.x86_64"
+	lowLatencyNode := newReadyNode(lowLatencyNodeName, sriovResourceName)
+	lowLatencyNode.Labels["feature.node.kubernetes.io/kernel-config.PREEMPT"] = "true"
+	standardNode := newReadyNode(standardNodeName, sriovResourceName)
+	standardNode.Status.NodeInfo.KernelVersion = "5.14.0-284.11.1.el9_2.x86_64"
+
+	newTestClient := func() *clientStub {
 		testClient := newClientStub()
+		testClient.nodes = map[string]*k8scorev1.Node{
+			realtimeNodeName:   realtimeNode,
+			lowLatencyNodeName: lowLatencyNode,
+			standardNodeName:   standardNode,
+		}
+		return testClient
+	}
+
+	t.Run("the kernel flavor is recorded per node", func(t *testing.T) {
 		testConfig := newTestConfig()
+		testConfig.RequireRealtimeKernel = true
+		testCheckup := checkup.New(newTestClient(), testNamespace, testConfig, executorStub{})
 
-		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+
+		flavors := map[string]string{}
+		for _, nodeReadiness := range testCheckup.Results().NodeReadiness {
+			flavors[nodeReadiness.NodeName] = nodeReadiness.KernelFlavor
+		}
+		assert.Equal(t, map[string]string{
+			realtimeNodeName:   checkup.KernelFlavorRealtime,
+			lowLatencyNodeName: checkup.KernelFlavorLowLatency,
+			standardNodeName:   checkup.KernelFlavorStandard,
+		}, flavors)
+	})
+
+	t.Run("the target node runs a standard kernel", func(t *testing.T) {
+		testClient := newTestClient()
+		testConfig := newTestConfig()
+		testConfig.RequireRealtimeKernel = true
+		testConfig.TrafficGenTargetNodeName = realtimeNodeName
+		testConfig.VMUnderTestTargetNodeName = standardNodeName
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		err := testCheckup.Setup(context.Background())
+		assert.ErrorIs(t, err, checkup.ErrNoCapableNode)
+		assert.ErrorContains(t, err,
+			`standard-node: realtimeKernel (kernel "5.14.0-284.11.1.el9_2.x86_64" is neither realtime nor low-latency)`)
+		assert.Empty(t, testClient.createdVMIs)
+	})
+
+	t.Run("the requirement is disabled", func(t *testing.T) {
+		testConfig := newTestConfig()
+		testConfig.TrafficGenTargetNodeName = standardNodeName
+		testConfig.VMUnderTestTargetNodeName = standardNodeName
+		testCheckup := checkup.New(newTestClient(), testNamespace, testConfig, executorStub{})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+	})
+}
+
+// expectedResourceFootprint returns the footprint of both VMIs: 8 vCPUs and an isolated emulator thread,
+// 4GiB of hugepages and two VFs each.
+func expectedResourceFootprint() *status.ResourceFootprint {
+	return &status.ResourceFootprint{DedicatedCPUs: 18, HugepagesGiB: 8, VFs: 4}
+}
+
+// testNodeReadiness returns the readiness of the node the client stub lists by default.
+func testNodeReadiness() []status.NodeReadiness {
+	return []status.NodeReadiness{
+		{
+			NodeName: testNodeName,
+			Score:    100,
+			Checks: []status.PreflightOutcome{
+				{Name: checkup.NodeReadyCheckName, Passed: true},
+				{Name: checkup.HugepagesCheckName, Passed: true},
+				{Name: checkup.CPUManagerCheckName, Passed: true},
+				{Name: checkup.SRIOVVFsCheckName, Passed: true},
+			},
+		},
+	}
+}
+
+func newReadyNode(name, sriovResourceName string) *k8scorev1.Node {
+	return &k8scorev1.Node{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kvcorev1.CPUManager: "true"},
+		},
+		Status: k8scorev1.NodeStatus{
+			Allocatable: k8scorev1.ResourceList{
+				"hugepages-1Gi": resource.MustParse("8Gi"),
+				k8scorev1.ResourceName(sriovResourceName): resource.MustParse("4"),
+			},
+			Conditions: []k8scorev1.NodeCondition{
+				{Type: k8scorev1.NodeReady, Status: k8scorev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func TestSetupShouldReportLauncherSecurityPosture(t *testing.T) {
+	const qemuUser = int64(107)
+	runAsUser := qemuUser
+	privileged := false
+
+	testClient := newClientStub()
+	testClient.launcherPodSpec = &k8scorev1.PodSpec{
+		SecurityContext: &k8scorev1.PodSecurityContext{
+			SELinuxOptions: &k8scorev1.SELinuxOptions{Type: "spc_t", Level: "s0"},
+			SeccompProfile: &k8scorev1.SeccompProfile{Type: k8scorev1.SeccompProfileTypeRuntimeDefault},
+			RunAsUser:      &runAsUser,
+		},
+		Containers: []k8scorev1.Container{
+			{
+				Name: "compute",
+				SecurityContext: &k8scorev1.SecurityContext{
+					SELinuxOptions: &k8scorev1.SELinuxOptions{Type: "container_t"},
+					Privileged:     &privileged,
+					Capabilities: &k8scorev1.Capabilities{
+						Add:  []k8scorev1.Capability{"SYS_NICE", "NET_BIND_SERVICE"},
+						Drop: []k8scorev1.Capability{"ALL"},
+					},
+				},
+			},
+		},
+	}
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: successfulRunResults()})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.NoError(t, testCheckup.Run(context.Background()))
+
+	expectedPosture := func(vmiName string) *status.LauncherSecurityPosture {
+		return &status.LauncherSecurityPosture{
+			PodName:             "virt-launcher-" + vmiName,
+			SELinuxType:         "container_t",
+			SELinuxLevel:        "s0",
+			SeccompProfile:      "RuntimeDefault",
+			RunAsUser:           &runAsUser,
+			AddedCapabilities:   []string{"NET_BIND_SERVICE", "SYS_NICE"},
+			DroppedCapabilities: []string{"ALL"},
+		}
+	}
+	results := testCheckup.Results()
+	assert.Equal(t, expectedPosture(testClient.VMIName(checkup.TrafficGenNamePrefix)), results.TrafficGenLauncherSecurity)
+	assert.Equal(t, expectedPosture(testClient.VMIName(checkup.VMIUnderTestNamePrefix)), results.VMUnderTestLauncherSecurity)
+
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+}
+
+func TestCheckupWithSeparateNetworkAttachmentDefinitions(t *testing.T) {
+	const (
+		eastNetworkAttachmentDefinitionName = "dpdk-network-pf0"
+		westNetworkAttachmentDefinitionName = "dpdk-network-pf1"
+		eastSRIOVResourceName               = "openshift.io/dpdk_nic_pf0"
+		westSRIOVResourceName               = "openshift.io/dpdk_nic_pf1"
+	)
+
+	newNetworkAttachmentDef := func(name, resourceName string) *netattdefv1.NetworkAttachmentDefinition {
+		return &netattdefv1.NetworkAttachmentDefinition{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:        name,
+				Namespace:   testNamespace,
+				Annotations: map[string]string{"k8s.v1.cni.cncf.io/resourceName": resourceName},
+			},
+			Spec: netattdefv1.NetworkAttachmentDefinitionSpec{
+				Config: `{"cniVersion": "0.4.0", "name": "` + name + `", "type": "sriov"}`,
+			},
+		}
+	}
+
+	testClient := newClientStub()
+	testClient.networkAttachmentDef = nil
+	testClient.networkAttachmentDefs = map[string]*netattdefv1.NetworkAttachmentDefinition{
+		eastNetworkAttachmentDefinitionName: newNetworkAttachmentDef(eastNetworkAttachmentDefinitionName, eastSRIOVResourceName),
+		westNetworkAttachmentDefinitionName: newNetworkAttachmentDef(westNetworkAttachmentDefinitionName, westSRIOVResourceName),
+	}
+
+	// A single VF of each resource is required per VMI.
+	node := newReadyNode(testNodeName, eastSRIOVResourceName)
+	node.Status.Allocatable[westSRIOVResourceName] = resource.MustParse("1")
+	testClient.nodes = map[string]*k8scorev1.Node{testNodeName: node}
+
+	testConfig := newTestConfig()
+	testConfig.NetworkAttachmentDefinitionNameEast = eastNetworkAttachmentDefinitionName
+	testConfig.NetworkAttachmentDefinitionNameWest = westNetworkAttachmentDefinitionName
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.Equal(t, testNodeReadiness(), testCheckup.Results().NodeReadiness)
+
+	for _, vmiNamePrefix := range []string{checkup.VMIUnderTestNamePrefix, checkup.TrafficGenNamePrefix} {
+		vmiFullName := checkup.ObjectFullName(testNamespace, testClient.VMIName(vmiNamePrefix))
+		var networkNames []string
+		for _, network := range testClient.createdVMIs[vmiFullName].Spec.Networks {
+			networkNames = append(networkNames, network.Multus.NetworkName)
+		}
+		assert.Equal(t, []string{eastNetworkAttachmentDefinitionName, westNetworkAttachmentDefinitionName}, networkNames)
+	}
+
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+}
+
+func TestCheckupWithFourInterfaces(t *testing.T) {
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+	for idx := 0; idx < 4; idx++ {
+		testConfig.Interfaces = append(testConfig.Interfaces, config.Interface{
+			Name:                            fmt.Sprintf("nic-%d", idx),
+			NetworkAttachmentDefinitionName: testNetworkAttachmentDefinitionName,
+			TrafficGenMacAddress:            net.HardwareAddr{0x50, 0, 0, 0, 0, byte(idx + 1)},
+			VMUnderTestMacAddress:           net.HardwareAddr{0x60, 0, 0, 0, 0, byte(idx + 1)},
+			PCIAddress:                      fmt.Sprintf("0000:%02x:00.0", 6+idx),
+		})
+	}
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	for _, vmiNamePrefix := range []string{checkup.VMIUnderTestNamePrefix, checkup.TrafficGenNamePrefix} {
+		vmiFullName := checkup.ObjectFullName(testNamespace, testClient.VMIName(vmiNamePrefix))
+		var interfacesPCIAddresses []string
+		for _, iface := range testClient.createdVMIs[vmiFullName].Spec.Domain.Devices.Interfaces {
+			interfacesPCIAddresses = append(interfacesPCIAddresses, iface.PciAddress)
+		}
+		assert.Equal(t, []string{"0000:06:00.0", "0000:07:00.0", "0000:08:00.0", "0000:09:00.0"}, interfacesPCIAddresses)
+		assert.Len(t, testClient.createdVMIs[vmiFullName].Spec.Networks, 4)
+	}
+
+	for _, configMap := range testClient.createdConfigMaps {
+		assert.Equal(t, 4, strings.Count(configMap.Data[config.BootScriptName], "nic_pci_address=$(pci_address_of "))
+	}
+
+	assert.Equal(t, 8, testCheckup.Results().ResourceFootprint.VFs)
+
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+}
+
+func TestSetupShouldBindTheNICsPerNICDriverMode(t *testing.T) {
+	const vendorCheck = "if [ \"$(cat /sys/bus/pci/devices/"
+
+	tests := []struct {
+		nicDriverMode    string
+		expectedOverride string
+		expectedCheck    bool
+	}{
+		{nicDriverMode: config.NICDriverModeAuto, expectedOverride: "\n    driverctl set-override ", expectedCheck: true},
+		{nicDriverMode: config.NICDriverModeVFIO, expectedOverride: "\n  driverctl set-override ", expectedCheck: false},
+		{nicDriverMode: config.NICDriverModeMLX5, expectedOverride: "", expectedCheck: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nicDriverMode, func(t *testing.T) {
+			testClient := newClientStub()
+			testConfig := newTestConfig()
+			testConfig.NICDriverMode = tt.nicDriverMode
+			testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+			assert.NoError(t, testCheckup.Setup(context.Background()))
+
+			assert.Len(t, testClient.createdConfigMaps, 2)
+			for _, configMap := range testClient.createdConfigMaps {
+				bootScript := configMap.Data[config.BootScriptName]
+				if tt.expectedOverride == "" {
+					assert.NotContains(t, bootScript, "driverctl")
+				} else {
+					assert.Contains(t, bootScript, tt.expectedOverride)
+				}
+				assert.Equal(t, tt.expectedCheck, strings.Contains(bootScript, vendorCheck))
+			}
+		})
+	}
+}
+
+func TestCheckupWithVirtualMachines(t *testing.T) {
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+	testConfig.UseVirtualMachines = true
+
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	assert.Len(t, testClient.createdVMs, 2)
+	for _, vm := range testClient.createdVMs {
+		assert.Equal(t, kvcorev1.RunStrategyAlways, *vm.Spec.RunStrategy)
+		assert.Equal(t, testPodUID, string(vm.OwnerReferences[0].UID))
+	}
+
+	vmiUnderTestName := testClient.VMIName(checkup.VMIUnderTestNamePrefix)
+	assert.NotEmpty(t, vmiUnderTestName)
+	assert.Contains(t, testClient.createdVMs, checkup.ObjectFullName(testNamespace, vmiUnderTestName))
+
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+	assert.Empty(t, testClient.createdVMs)
+	assert.Empty(t, testClient.createdVMIs)
+}
+
+func TestSetupShouldFail(t *testing.T) {
+	t.Run("when Traffic gen ConfigMap creation fails", func(t *testing.T) {
+		expectedConfigMapCreationError := errors.New("failed to create ConfigMap")
+
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+		testClient.configMapCreationFailure = expectedConfigMapCreationError
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.ErrorContains(t, testCheckup.Setup(context.Background()), expectedConfigMapCreationError.Error())
+		assert.Empty(t, testClient.createdVMIs)
+	})
+
+	t.Run("when TRex config file is invalid", func(t *testing.T) {
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+		testConfig.PortBandwidthGbps = 0
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.ErrorContains(t, testCheckup.Setup(context.Background()), "port_bandwidth_gb must be positive")
+		assert.Empty(t, testClient.createdConfigMaps)
+		assert.Empty(t, testClient.createdVMIs)
+	})
+
+	t.Run("when Network-Attachment-Definition is invalid", func(t *testing.T) {
+		testCases := map[string]struct {
+			networkAttachmentDef *netattdefv1.NetworkAttachmentDefinition
+			expectedErr          string
+		}{
+			"does not exist": {
+				networkAttachmentDef: nil,
+				expectedErr:          "not found",
+			},
+			"uses a non SR-IOV CNI": {
+				networkAttachmentDef: &netattdefv1.NetworkAttachmentDefinition{
+					ObjectMeta: k8smetav1.ObjectMeta{
+						Annotations: map[string]string{"k8s.v1.cni.cncf.io/resourceName": sriovResourceName},
+					},
+					Spec: netattdefv1.NetworkAttachmentDefinitionSpec{
+						Config: `{"cniVersion": "0.4.0", "plugins": [{"type": "bridge"}, {"type": "tuning"}]}`,
+					},
+				},
+				expectedErr: `CNI type "bridge,tuning" is not SR-IOV capable`,
+			},
+			"has a malformed config": {
+				networkAttachmentDef: &netattdefv1.NetworkAttachmentDefinition{
+					Spec: netattdefv1.NetworkAttachmentDefinitionSpec{Config: `{"type": `},
+				},
+				expectedErr: "failed to parse its config",
+			},
+			"lacks the resource name annotation": {
+				networkAttachmentDef: &netattdefv1.NetworkAttachmentDefinition{
+					Spec: netattdefv1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "0.4.0", "type": "sriov"}`},
+				},
+				expectedErr: `missing "k8s.v1.cni.cncf.io/resourceName" annotation`,
+			},
+		}
+
+		for name, testCase := range testCases {
+			t.Run(name, func(t *testing.T) {
+				testClient := newClientStub()
+				testClient.networkAttachmentDef = testCase.networkAttachmentDef
+				testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+				err := testCheckup.Setup(context.Background())
+				assert.ErrorIs(t, err, checkup.ErrInvalidNetworkAttachmentDefinition)
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				assert.Empty(t, testClient.createdConfigMaps)
+				assert.Empty(t, testClient.createdVMIs)
+			})
+		}
+	})
+
+	t.Run("when VMI creation fails", func(t *testing.T) {
+		expectedVMICreationFailure := errors.New("failed to create VMI")
+
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+		testClient.vmiCreationFailure = expectedVMICreationFailure
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.ErrorContains(t, testCheckup.Setup(context.Background()), expectedVMICreationFailure.Error())
+		assert.Empty(t, testClient.createdVMIs)
+	})
+
+	t.Run("when wait for VMI to boot fails", func(t *testing.T) {
+		expectedVMIReadFailure := errors.New("failed to read VMI")
+
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+		testClient.vmiReadFailure = expectedVMIReadFailure
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.ErrorContains(t, testCheckup.Setup(context.Background()), expectedVMIReadFailure.Error())
+		assert.Empty(t, testClient.createdVMIs)
+	})
+}
+
+func TestSetupShouldCreateTheVMIsConcurrently(t *testing.T) {
+	testClient := newClientStub()
+	testClient.concurrentVMICreations = &sync.WaitGroup{}
+	testClient.concurrentVMICreations.Add(2)
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.Len(t, testClient.createdVMIs, 2)
+}
+
+func TestSetupShouldRetryTheVMIsSetup(t *testing.T) {
+	newRetryingTestConfig := func() config.Config {
+		testConfig := newTestConfig()
+		testConfig.SetupRetries = 1
+		testConfig.SetupAttemptTimeout = time.Minute
+		testConfig.SetupRetryBackoff = time.Millisecond
+		return testConfig
+	}
+
+	t.Run("when a retry succeeds", func(t *testing.T) {
+		testClient := newClientStub()
+		testClient.failingVMICreations = map[int]bool{1: true}
+		testCheckup := checkup.New(testClient, testNamespace, newRetryingTestConfig(), executorStub{results: successfulRunResults()})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.Len(t, testClient.createdVMIs, 2)
+		assert.NoError(t, testCheckup.Run(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+		assert.Equal(t, 1, testCheckup.Results().SetupRetries)
+	})
+
+	t.Run("when the VMIs of a failed attempt were created", func(t *testing.T) {
+		testClient := newClientStub()
+		// One of the VMIs is created, while the creation of the other fails
+		testClient.failingVMICreations = map[int]bool{2: true}
+		testCheckup := checkup.New(testClient, testNamespace, newRetryingTestConfig(), executorStub{})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.Len(t, testClient.createdVMIs, 2)
+	})
+
+	t.Run("when all the attempts fail", func(t *testing.T) {
+		testClient := newClientStub()
+		testClient.failingVMICreations = map[int]bool{1: true, 2: true, 3: true, 4: true}
+		testCheckup := checkup.New(testClient, testNamespace, newRetryingTestConfig(), executorStub{})
+
+		assert.ErrorContains(t, testCheckup.Setup(context.Background()),
+			"setup: transient VMI creation failure (after 2 attempts)")
+		assert.Empty(t, testClient.createdVMIs)
+	})
+}
+
+func TestSetupShouldPassTheNUMATopologyThrough(t *testing.T) {
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+	testConfig.NUMAPassthrough = true
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	assert.Len(t, testClient.createdVMIs, 2)
+	for _, vmi := range testClient.createdVMIs {
+		assert.True(t, vmi.Spec.Domain.CPU.DedicatedCPUPlacement, vmi.Name)
+		assert.NotNil(t, vmi.Spec.Domain.CPU.NUMA.GuestMappingPassthrough, vmi.Name)
+	}
+}
+
+func TestSetupShouldSetThePriorityClassOnTheVMIs(t *testing.T) {
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+	testConfig.PriorityClassName = "dpdk-checkup-critical"
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	assert.Len(t, testClient.createdVMIs, 2)
+	for _, vmi := range testClient.createdVMIs {
+		assert.Equal(t, testConfig.PriorityClassName, vmi.Spec.PriorityClassName, vmi.Name)
+	}
+}
+
+func TestSetupShouldAddTheExtraMetadataAndTolerationsToTheVMIs(t *testing.T) {
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+	testConfig.VMIExtraLabels = map[string]string{"team": "nfv", checkup.DPDKCheckupUIDLabelKey: "overridden"}
+	testConfig.VMIExtraAnnotations = map[string]string{"example.com/owner": "NFV team"}
+	testConfig.VMITolerations = []config.Toleration{
+		{Key: "dedicated", Value: "dpdk", Effect: config.TaintEffectNoSchedule},
+		{Key: "example.com/maintenance"},
+	}
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+
+	expectedTolerations := []k8scorev1.Toleration{
+		{Key: "dedicated", Operator: k8scorev1.TolerationOpEqual, Value: "dpdk", Effect: k8scorev1.TaintEffectNoSchedule},
+		{Key: "example.com/maintenance", Operator: k8scorev1.TolerationOpExists},
+	}
+	assert.Len(t, testClient.createdVMIs, 2)
+	for _, vmi := range testClient.createdVMIs {
+		assert.Equal(t, "nfv", vmi.Labels["team"], vmi.Name)
+		assert.Equal(t, testConfig.CheckupUID, vmi.Labels[checkup.DPDKCheckupUIDLabelKey], vmi.Name)
+		assert.Equal(t, "NFV team", vmi.Annotations["example.com/owner"], vmi.Name)
+		assert.Equal(t, expectedTolerations, vmi.Spec.Tolerations, vmi.Name)
+	}
+}
+
+func TestSetupShouldVerifyTheLaunchersRuntimeClass(t *testing.T) {
+	const runtimeClassName = "performance-dpdk"
+
+	t.Run("when the virt-launcher Pods run with the RuntimeClass", func(t *testing.T) {
+		expectedRuntimeClassName := runtimeClassName
+		testClient := newClientStub()
+		testClient.launcherPodSpec = &k8scorev1.PodSpec{RuntimeClassName: &expectedRuntimeClassName}
+		testConfig := newTestConfig()
+		testConfig.RuntimeClassName = runtimeClassName
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+	})
+
+	t.Run("when the virt-launcher Pods run with another RuntimeClass", func(t *testing.T) {
+		otherRuntimeClassName := "other"
+		testClient := newClientStub()
+		testClient.launcherPodSpec = &k8scorev1.PodSpec{RuntimeClassName: &otherRuntimeClassName}
+		testConfig := newTestConfig()
+		testConfig.RuntimeClassName = runtimeClassName
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		err := testCheckup.Setup(context.Background())
+		assert.ErrorIs(t, err, checkup.ErrUnexpectedRuntimeClass)
+		assert.ErrorContains(t, err, "defaultRuntimeClass")
+		assert.Empty(t, testClient.createdVMIs)
+	})
+
+	t.Run("when the virt-launcher Pods run without a RuntimeClass", func(t *testing.T) {
+		testClient := newClientStub()
+		testClient.launcherPodSpec = &k8scorev1.PodSpec{}
+		testConfig := newTestConfig()
+		testConfig.RuntimeClassName = runtimeClassName
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+		assert.ErrorIs(t, testCheckup.Setup(context.Background()), checkup.ErrUnexpectedRuntimeClass)
+	})
+}
+
+func TestSetupShouldAdjustTheVMIsToTheKubeVirtVersion(t *testing.T) {
+	t.Run("when KubeVirt supports all the VMI fields", func(t *testing.T) {
+		testClient := newClientStub()
+		testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+
+		for _, vmi := range testClient.createdVMIs {
+			assert.NotNil(t, vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue, vmi.Name)
+		}
+	})
+
+	t.Run("when KubeVirt is older than some of the VMI fields", func(t *testing.T) {
+		testClient := newClientStub()
+		testClient.kubeVirtVersion = "v0.25.1"
+		testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+
+		assert.Len(t, testClient.createdVMIs, 2)
+		for _, vmi := range testClient.createdVMIs {
+			assert.Nil(t, vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue, vmi.Name)
+		}
+	})
+
+	t.Run("when the KubeVirt version cannot be detected", func(t *testing.T) {
+		testClient := newClientStub()
+		testClient.kubeVirtVersionFailure = errors.New("kubevirts.kubevirt.io is forbidden")
+		testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+
+		for _, vmi := range testClient.createdVMIs {
+			assert.NotNil(t, vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue, vmi.Name)
+		}
+	})
+}
+
+func TestSetupShouldWatchTheVMIsReadiness(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiNeverReady = true
+	testClient.vmiReadyOnWatch = true
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+	testCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	assert.NoError(t, testCheckup.Setup(testCtx))
+}
+
+func TestSetupShouldWaitForTheVMIsThatAreNotFoundYet(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiFoundLate = true
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+	testCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	assert.NoError(t, testCheckup.Setup(testCtx))
+	assert.Len(t, testClient.createdVMIs, 2)
+}
+
+func TestSetupShouldPollTheVMIsWhenTheyCannotBeWatched(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiNeverReady = true
+	testClient.vmiWatchFailure = k8serrors.NewForbidden(
+		schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachineinstances"}, "", errors.New("watch is not allowed"))
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+	// Long enough for the VMIs to be watched again, had they been watched after a second
+	testCtx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	assert.Error(t, testCheckup.Setup(testCtx))
+	assert.Equal(t, 2, testClient.vmiWatches)
+}
+
+func TestSetupShouldReportCancellationWhileWaitingForVMIs(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiNeverReady = true
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{})
+
+	testCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := testCheckup.Setup(testCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, exitcode.Cancelled, exitcode.Of(err))
+	assert.Contains(t, exitcode.CancelledPhase(err), "to be ready")
+}
+
+func TestSetupShouldFailWhenTheVMIsAreNotReadyWithinTheBootTimeout(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiNeverReady = true
+	testConfig := newTestConfig()
+	testConfig.VMIBootTimeout = 10 * time.Millisecond
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+	err := testCheckup.Setup(context.Background())
+	assert.ErrorContains(t, err, "VMI boot timeout")
+	assert.NotEqual(t, exitcode.Cancelled, exitcode.Of(err))
+	assert.Contains(t, recordedEventReasons(testClient, k8scorev1.EventTypeWarning), events.ReasonVMIBootTimeout)
+}
+
+func TestTeardownShouldFailWhen(t *testing.T) {
+	t.Run("VMI deletion fails", func(t *testing.T) {
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Run(context.Background()))
+
+		testCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		const vmiDeletionFailureErrMsg = "failed to delete VMI"
+		testClient.vmiDeletionFailure = errors.New(vmiDeletionFailureErrMsg)
+		assert.ErrorContains(t, testCheckup.Teardown(testCtx), vmiDeletionFailureErrMsg)
+	})
+	t.Run("VMIs were not disposed before timeout expiration", func(t *testing.T) {
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Run(context.Background()))
+
+		testCtx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+
+		testClient.skipDeletion = true
+		assert.ErrorContains(t, testCheckup.Teardown(testCtx), "timed out waiting for the condition")
+	})
+	t.Run("VMIs were not disposed within the teardown timeout", func(t *testing.T) {
+		testClient := newClientStub()
+		testConfig := newTestConfig()
+		testConfig.TeardownTimeout = time.Nanosecond
+
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Run(context.Background()))
+
+		testClient.skipDeletion = true
+		assert.ErrorContains(t, testCheckup.Teardown(context.Background()), "timed out waiting for the condition")
+	})
+}
+
+func TestTeardownShouldKeepResources(t *testing.T) {
+	testCases := []struct {
+		skipTeardown        string
+		executeErr          error
+		expectResourcesKept bool
+	}{
+		{skipTeardown: config.SkipTeardownNever, executeErr: errors.New("failed to execute"), expectResourcesKept: false},
+		{skipTeardown: config.SkipTeardownOnFailure, executeErr: nil, expectResourcesKept: false},
+		{skipTeardown: config.SkipTeardownOnFailure, executeErr: errors.New("failed to execute"), expectResourcesKept: true},
+		{skipTeardown: config.SkipTeardownAlways, executeErr: nil, expectResourcesKept: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%s when execute fails: %t", testCase.skipTeardown, testCase.executeErr != nil), func(t *testing.T) {
+			testClient := newClientStub()
+			testConfig := newTestConfig()
+			testConfig.SkipTeardown = testCase.skipTeardown
+
+			testCheckup := checkup.New(testClient, testNamespace, testConfig,
+				executorStub{results: successfulRunResults(), executeErr: testCase.executeErr})
+
+			assert.NoError(t, testCheckup.Setup(context.Background()))
+			runErr := testCheckup.Run(context.Background())
+			assert.Equal(t, testCase.executeErr != nil, runErr != nil)
+			assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+			if testCase.expectResourcesKept {
+				assert.Len(t, testClient.createdVMIs, 2)
+				assert.Len(t, testClient.createdConfigMaps, 2)
+			} else {
+				assert.Empty(t, testClient.createdVMIs)
+				assert.Empty(t, testClient.createdConfigMaps)
+			}
+		})
+	}
+}
+
+func TestSetupShouldKeepTheVMIsOnFailureWhenSkippingTeardown(t *testing.T) {
+	testClient := newClientStub()
+	testClient.vmiReadFailure = errors.New("failed to read VMI")
+	testConfig := newTestConfig()
+	testConfig.SkipTeardown = config.SkipTeardownOnFailure
+
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{})
+
+	assert.ErrorContains(t, testCheckup.Setup(context.Background()), testClient.vmiReadFailure.Error())
+	assert.Len(t, testClient.createdVMIs, 2)
+}
+
+func TestRunShouldSetScenariosVerdict(t *testing.T) {
+	const sentPackets = 10
+	testClient := newClientStub()
+	executorResults := status.Results{
+		TrafficGenSentPackets:      2 * sentPackets,
+		VMUnderTestReceivedPackets: 2*sentPackets - 1,
+		Scenarios: []status.ScenarioResults{
+			{
+				ID:       "64B",
+				Counters: status.PacketCounters{TrafficGenSentPackets: sentPackets, VMUnderTestReceivedPackets: sentPackets},
+			},
+			{
+				ID:       "1500B",
+				Counters: status.PacketCounters{TrafficGenSentPackets: sentPackets, VMUnderTestReceivedPackets: sentPackets - 1},
+			},
+		},
+	}
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: executorResults})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.ErrorContains(t, testCheckup.Run(context.Background()), "1 out of 2 scenarios failed: 1500B: not all generated packets")
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+	actualScenarios := testCheckup.Results().Scenarios
+	assert.Len(t, actualScenarios, 2)
+	assert.True(t, actualScenarios[0].Succeeded)
+	assert.Empty(t, actualScenarios[0].FailureReason)
+	assert.False(t, actualScenarios[1].Succeeded)
+	assert.Contains(t, actualScenarios[1].FailureReason, "not all generated packets had reached VM-Under-Test")
+}
+
+func TestRunShouldSummarizeThePerDirectionRuns(t *testing.T) {
+	const sentPackets = 10
+	testClient := newClientStub()
+	executorResults := status.Results{
+		TrafficGenSentPackets:      2 * sentPackets,
+		VMUnderTestReceivedPackets: 2*sentPackets - 1,
+		Scenarios: []status.ScenarioResults{
+			{
+				ID:       status.EastToWestScenarioID,
+				Counters: status.PacketCounters{TrafficGenSentPackets: sentPackets, VMUnderTestReceivedPackets: sentPackets},
+			},
+			{
+				ID:       status.WestToEastScenarioID,
+				Counters: status.PacketCounters{TrafficGenSentPackets: sentPackets, VMUnderTestReceivedPackets: sentPackets - 1},
+			},
+		},
+	}
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: executorResults})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.ErrorContains(t, testCheckup.Run(context.Background()), "1 out of 2 scenarios failed: west-to-east")
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+	assert.Contains(t, testCheckup.Results().DirectionsSummary,
+		"only the west-to-east direction failed, suspect the VM under test west and the traffic generator east interfaces")
+}
+
+func TestVMConfigMapTeardownFailure(t *testing.T) {
+	testClient := newClientStub()
+	testConfig := newTestConfig()
+
+	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.NotEmpty(t, testClient.createdConfigMaps)
+
+	assert.NoError(t, testCheckup.Run(context.Background()))
+
+	expectedCMDeletionFailure := errors.New("failed to delete ConfigMap")
+	testClient.configMapDeletionFailure = expectedCMDeletionFailure
+
+	assert.ErrorContains(t, testCheckup.Teardown(context.Background()), expectedCMDeletionFailure.Error())
+}
+
+func TestRunFailure(t *testing.T) {
+	const (
+		executeFailureMsg               = "failed to execute dpdk checkup"
+		trafficGenNoPacketsSentErrMsg   = "no packets were sent from the traffic generator"
+		trafficGenIOPacketsErrMsg       = "detected Error Packets on the traffic generator's side: Oerrors %d Ierrors %d"
+		vmUnderTestDroppedPacketsErrMsg = "detected packets dropped on the VM-Under-Test's side: RX: %d; TX: %d"
+		packetsDontMatchErrMsg          = "not all generated packets had reached VM-Under-Test: Sent from traffic generator:" +
+			" %d; Received on VM-Under-Test: %d"
+
+		trafficGenSentPackets       = 10
+		trafficGenOutputErrPackets  = 1
+		trafficGenInputErrPackets   = 2
+		vmUnderTestTxDroppedPackets = 3
+		vmUnderTestRxDroppedPackets = 4
+		vmUnderTestReceivedPackets  = trafficGenSentPackets - 1
+	)
+
+	type FailTestCase struct {
+		description      string
+		executorFailure  error
+		results          status.Results
+		expectedRunErr   error
+		expectedExitCode exitcode.Code
+		expectedCriteria *status.CriteriaResults
+	}
+
+	testCases := []FailTestCase{
+		{
+			description:      "Run Execute fails",
+			executorFailure:  errors.New(executeFailureMsg),
+			results:          status.Results{},
+			expectedRunErr:   errors.New(executeFailureMsg),
+			expectedExitCode: exitcode.Inconclusive,
+		},
+		{
+			description: "fail because no packets sent from traffic generator",
+			results: status.Results{
+				TrafficGenSentPackets: 0,
+			},
+			expectedRunErr:   errors.New(trafficGenNoPacketsSentErrMsg),
+			expectedExitCode: exitcode.PerformanceFailure,
+		},
+		{
+			description: "fail because found err packets on traffic generator side",
+			results: status.Results{
+				TrafficGenSentPackets:        trafficGenSentPackets,
+				TrafficGenOutputErrorPackets: trafficGenOutputErrPackets,
+				TrafficGenInputErrorPackets:  trafficGenInputErrPackets,
+			},
+			expectedRunErr:   fmt.Errorf(trafficGenIOPacketsErrMsg, trafficGenOutputErrPackets, trafficGenInputErrPackets),
+			expectedExitCode: exitcode.PerformanceFailure,
+			expectedCriteria: &status.CriteriaResults{
+				Outcomes: blockerOutcomes(map[string]string{
+					config.CriterionErrors: fmt.Sprintf(trafficGenIOPacketsErrMsg, trafficGenOutputErrPackets, trafficGenInputErrPackets),
+					config.CriterionLoss:   fmt.Sprintf(packetsDontMatchErrMsg, trafficGenSentPackets, 0),
+				}),
+				Score: 33,
+			},
+		},
+		{
+			description: "fail because found err packets on VM-under-test side",
+			results: status.Results{
+				TrafficGenSentPackets:       trafficGenSentPackets,
+				VMUnderTestTxDroppedPackets: vmUnderTestTxDroppedPackets,
+				VMUnderTestRxDroppedPackets: vmUnderTestRxDroppedPackets,
+			},
+			expectedRunErr:   fmt.Errorf(vmUnderTestDroppedPacketsErrMsg, vmUnderTestRxDroppedPackets, vmUnderTestTxDroppedPackets),
+			expectedExitCode: exitcode.PerformanceFailure,
+			expectedCriteria: &status.CriteriaResults{
+				Outcomes: blockerOutcomes(map[string]string{
+					config.CriterionDrops: fmt.Sprintf(vmUnderTestDroppedPacketsErrMsg, vmUnderTestRxDroppedPackets, vmUnderTestTxDroppedPackets),
+					config.CriterionLoss:  fmt.Sprintf(packetsDontMatchErrMsg, trafficGenSentPackets, 0),
+				}),
+				Score: 33,
+			},
+		},
+		{
+			description: "fail because packets sent from traffic generator don't equal VM-under-test packets received",
+			results: status.Results{
+				TrafficGenSentPackets:      trafficGenSentPackets,
+				VMUnderTestReceivedPackets: vmUnderTestReceivedPackets,
+			},
+			expectedRunErr:   fmt.Errorf(packetsDontMatchErrMsg, trafficGenSentPackets, vmUnderTestReceivedPackets),
+			expectedExitCode: exitcode.PerformanceFailure,
+			expectedCriteria: &status.CriteriaResults{
+				Outcomes: blockerOutcomes(map[string]string{
+					config.CriterionLoss: fmt.Sprintf(packetsDontMatchErrMsg, trafficGenSentPackets, vmUnderTestReceivedPackets),
+				}),
+				Score: 67,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			testClient := newClientStub()
+			testConfig := newTestConfig()
+
+			testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{
+				results:    testCase.results,
+				executeErr: testCase.executorFailure,
+			})
+
+			assert.NoError(t, testCheckup.Setup(context.Background()))
+
+			runErr := testCheckup.Run(context.Background())
+			assert.ErrorContains(t, runErr, testCase.expectedRunErr.Error())
+			assert.Equal(t, testCase.expectedExitCode, exitcode.Of(runErr))
+
+			assert.NoError(t, testCheckup.Teardown(context.Background()))
+			assert.Empty(t, testClient.createdVMIs)
+
+			expectedResults := testCase.results
+			expectedResults.NodeReadiness = testNodeReadiness()
+			expectedResults.ResourceFootprint = expectedResourceFootprint()
+			expectedResults.Environment = &status.Environment{KubeVirtVersion: testKubeVirtVersion}
+			expectedResults.Criteria = testCase.expectedCriteria
+			if testCase.executorFailure == nil {
+				expectedResults.OwnershipMode = config.OwnershipModeOwnerReference
+			}
+			actualResults := testCheckup.Results()
+			assert.Equal(t, []string{
+				checkup.ConfigMapsCreationPhase,
+				checkup.VMIsCreationPhase,
+				checkup.VMIsBootPhase,
+				checkup.TeardownPhase,
+			}, timelinePhases(actualResults.Timeline))
+			actualResults.Timeline = nil
+			assert.Equal(t, expectedResults, actualResults)
+		})
+	}
+}
+
+func TestRunShouldWeighTheCriteriaBySeverity(t *testing.T) {
+	const (
+		sentPackets    = 10
+		droppedPackets = 2
+	)
+
+	testConfig := newTestConfig()
+	testConfig.CriteriaSeverities = map[string]string{
+		config.CriterionDrops: config.SeverityMinor,
+		config.CriterionLoss:  config.SeverityMajor,
+	}
+	testCheckup := checkup.New(newClientStub(), testNamespace, testConfig, executorStub{results: status.Results{
+		TrafficGenSentPackets:       sentPackets,
+		VMUnderTestReceivedPackets:  sentPackets - droppedPackets,
+		VMUnderTestRxDroppedPackets: droppedPackets,
+	}})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+	actualCriteria := testCheckup.Results().Criteria
+	assert.NotNil(t, actualCriteria)
+	assert.Equal(t, []status.CriterionOutcome{
+		{Name: config.CriterionErrors, Severity: config.SeverityBlocker, Passed: true},
+		{
+			Name:          config.CriterionDrops,
+			Severity:      config.SeverityMinor,
+			FailureReason: "detected packets dropped on the VM-Under-Test's side: RX: 2; TX: 0",
+		},
+		{
+			Name:          config.CriterionLoss,
+			Severity:      config.SeverityMajor,
+			FailureReason: "not all generated packets had reached VM-Under-Test: Sent from traffic generator: 10; Received on VM-Under-Test: 8",
+		},
+	}, actualCriteria.Outcomes)
+	const expectedScore = 71 // 10 out of 14
+	assert.Equal(t, expectedScore, actualCriteria.Score)
+}
+
+func TestRunShouldCheckTheMaxDropRate(t *testing.T) {
+	const (
+		sentPackets           = 10
+		maxAllowedDropRateBps = 1000
+	)
+
+	testConfig := newTestConfig()
+	testConfig.MaxAllowedDropRateBps = maxAllowedDropRateBps
+
+	t.Run("when the drop rate is within the limit", func(t *testing.T) {
+		testCheckup := checkup.New(newClientStub(), testNamespace, testConfig, executorStub{results: status.Results{
+			TrafficGenSentPackets:      sentPackets,
+			VMUnderTestReceivedPackets: sentPackets,
+			MaxDropRateBps:             maxAllowedDropRateBps,
+		}})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Run(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+		expectedOutcomes := append(blockerOutcomes(nil),
+			status.CriterionOutcome{Name: config.CriterionDropRate, Severity: config.SeverityBlocker, Passed: true})
+		assert.Equal(t, expectedOutcomes, testCheckup.Results().Criteria.Outcomes)
+	})
+
+	t.Run("when the drop rate exceeds the limit", func(t *testing.T) {
+		const expectedFailureReason = "the traffic generator's side drop rate peaked at 1500bps, above the max allowed 1000bps"
+		testCheckup := checkup.New(newClientStub(), testNamespace, testConfig, executorStub{results: status.Results{
+			TrafficGenSentPackets:      sentPackets,
+			VMUnderTestReceivedPackets: sentPackets,
+			MaxDropRateBps:             1500,
+		}})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.ErrorContains(t, testCheckup.Run(context.Background()), expectedFailureReason)
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+		expectedOutcomes := append(blockerOutcomes(nil),
+			status.CriterionOutcome{Name: config.CriterionDropRate, Severity: config.SeverityBlocker, FailureReason: expectedFailureReason})
+		assert.Equal(t, expectedOutcomes, testCheckup.Results().Criteria.Outcomes)
+	})
+}
+
+func TestRunShouldBeInconclusiveWhenTheTxDurationDeviates(t *testing.T) {
+	const sentPackets = 10
+
+	t.Run("when the TX duration is close to the requested one", func(t *testing.T) {
+		testCheckup := checkup.New(newClientStub(), testNamespace, newTestConfig(), executorStub{results: status.Results{
+			TrafficGenSentPackets:      sentPackets,
+			VMUnderTestReceivedPackets: sentPackets,
+			TxDuration:                 &status.TxDuration{RequestedSeconds: 120, ActualSeconds: 115, DeltaPercent: -4.2},
+		}})
 
 		assert.NoError(t, testCheckup.Setup(context.Background()))
 		assert.NoError(t, testCheckup.Run(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+	})
 
-		testCtx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
-		defer cancel()
+	t.Run("when the TX duration is much shorter than the requested one", func(t *testing.T) {
+		testCheckup := checkup.New(newClientStub(), testNamespace, newTestConfig(), executorStub{results: status.Results{
+			TrafficGenSentPackets:      sentPackets,
+			VMUnderTestReceivedPackets: sentPackets,
+			TxDuration:                 &status.TxDuration{RequestedSeconds: 120, ActualSeconds: 60, DeltaPercent: -50},
+		}})
 
-		testClient.skipDeletion = true
-		assert.ErrorContains(t, testCheckup.Teardown(testCtx), "timed out waiting for the condition")
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		err := testCheckup.Run(context.Background())
+		assert.ErrorContains(t, err, "the traffic generator transmitted for 60.0s out of the requested 120s (-50.0%)")
+		assert.Equal(t, exitcode.Inconclusive, exitcode.Of(err))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
 	})
 }
 
-func TestVMConfigMapTeardownFailure(t *testing.T) {
-	testClient := newClientStub()
-	testConfig := newTestConfig()
+func TestRunShouldBeCancelledWhenAnAbortWasRequested(t *testing.T) {
+	const sentPackets = 10
 
-	testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: successfulRunResults()})
+	testCheckup := checkup.New(newClientStub(), testNamespace, newTestConfig(), executorStub{results: status.Results{
+		TrafficGenSentPackets:      sentPackets,
+		VMUnderTestReceivedPackets: sentPackets,
+		AbortRequested:             true,
+	}})
 
 	assert.NoError(t, testCheckup.Setup(context.Background()))
-	assert.NotEmpty(t, testClient.createdConfigMaps)
+	err := testCheckup.Run(context.Background())
+	assert.ErrorContains(t, err, `the run was aborted by the "spec.abort" ConfigMap key`)
+	assert.Equal(t, exitcode.Cancelled, exitcode.Of(err))
+	assert.Equal(t, int64(sentPackets), testCheckup.Results().TrafficGenSentPackets)
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+}
 
-	assert.NoError(t, testCheckup.Run(context.Background()))
+func TestRunShouldFailWhenTheSoakSnapshotsAbortedTheTraffic(t *testing.T) {
+	const sentPackets = 10
 
-	expectedCMDeletionFailure := errors.New("failed to delete ConfigMap")
-	testClient.configMapDeletionFailure = expectedCMDeletionFailure
+	testCheckup := checkup.New(newClientStub(), testNamespace, newTestConfig(), executorStub{results: status.Results{
+		TrafficGenSentPackets:      sentPackets,
+		VMUnderTestReceivedPackets: sentPackets,
+		Soak: &status.Soak{
+			Snapshots:   []status.SoakSnapshot{{Phase: "traffic iteration 1/1", ElapsedSeconds: 600, TrafficGenSentPackets: sentPackets}},
+			AbortReason: "the traffic generator received packets stalled at 0, 600s into the traffic iteration 1/1",
+		},
+	}})
 
-	assert.ErrorContains(t, testCheckup.Teardown(context.Background()), expectedCMDeletionFailure.Error())
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	err := testCheckup.Run(context.Background())
+	assert.ErrorContains(t, err, "the traffic was aborted early: the traffic generator received packets stalled at 0")
+	assert.Equal(t, exitcode.PerformanceFailure, exitcode.Of(err))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
 }
 
-func TestRunFailure(t *testing.T) {
-	const (
-		executeFailureMsg               = "failed to execute dpdk checkup"
-		trafficGenNoPacketsSentErrMsg   = "no packets were sent from the traffic generator"
-		trafficGenIOPacketsErrMsg       = "detected Error Packets on the traffic generator's side: Oerrors %d Ierrors %d"
-		vmUnderTestDroppedPacketsErrMsg = "detected packets dropped on the VM-Under-Test's side: RX: %d; TX: %d"
-		packetsDontMatchErrMsg          = "not all generated packets had reached VM-Under-Test: Sent from traffic generator:" +
-			" %d; Received on VM-Under-Test: %d"
+func TestRunShouldReportTheSameNodeScenario(t *testing.T) {
+	const sentPackets = 10
 
-		trafficGenSentPackets       = 10
-		trafficGenOutputErrPackets  = 1
-		trafficGenInputErrPackets   = 2
-		vmUnderTestTxDroppedPackets = 3
-		vmUnderTestRxDroppedPackets = 4
-		vmUnderTestReceivedPackets  = trafficGenSentPackets - 1
-	)
+	testConfig := newTestConfig()
+	testConfig.SameNodePlacement = true
+	testCheckup := checkup.New(newClientStub(), testNamespace, testConfig, executorStub{results: status.Results{
+		TrafficGenSentPackets:      sentPackets,
+		VMUnderTestReceivedPackets: sentPackets,
+	}})
 
-	type FailTestCase struct {
-		description     string
-		executorFailure error
-		results         status.Results
-		expectedRunErr  error
-	}
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
 
-	testCases := []FailTestCase{
-		{
-			description:     "Run Execute fails",
-			executorFailure: errors.New(executeFailureMsg),
-			results:         status.Results{},
-			expectedRunErr:  errors.New(executeFailureMsg),
-		},
-		{
-			description: "fail because no packets sent from traffic generator",
-			results: status.Results{
-				TrafficGenSentPackets: 0,
-			},
-			expectedRunErr: errors.New(trafficGenNoPacketsSentErrMsg),
-		},
-		{
-			description: "fail because found err packets on traffic generator side",
-			results: status.Results{
-				TrafficGenSentPackets:        trafficGenSentPackets,
-				TrafficGenOutputErrorPackets: trafficGenOutputErrPackets,
-				TrafficGenInputErrorPackets:  trafficGenInputErrPackets,
-			},
-			expectedRunErr: fmt.Errorf(trafficGenIOPacketsErrMsg, trafficGenOutputErrPackets, trafficGenInputErrPackets),
-		},
+	expectedScenarios := []status.ScenarioResults{{
+		ID:        status.SameNodeScenarioID,
+		Succeeded: true,
+		Counters:  status.PacketCounters{TrafficGenSentPackets: sentPackets, VMUnderTestReceivedPackets: sentPackets},
+	}}
+	assert.Equal(t, expectedScenarios, testCheckup.Results().Scenarios)
+}
+
+func TestRunShouldReportTheEnvironment(t *testing.T) {
+	const (
+		devicePluginImage = "ghcr.io/k8snetworkplumbingwg/sriov-network-device-plugin:v3.6.2"
+		cniImage          = "quay.io/openshift/origin-sriov-cni@sha256:0123456789"
+	)
+
+	testClient := newClientStub()
+	testClient.pods = []k8scorev1.Pod{
 		{
-			description: "fail because found err packets on VM-under-test side",
-			results: status.Results{
-				TrafficGenSentPackets:       trafficGenSentPackets,
-				VMUnderTestTxDroppedPackets: vmUnderTestTxDroppedPackets,
-				VMUnderTestRxDroppedPackets: vmUnderTestRxDroppedPackets,
-			},
-			expectedRunErr: fmt.Errorf(vmUnderTestDroppedPacketsErrMsg, vmUnderTestRxDroppedPackets, vmUnderTestTxDroppedPackets),
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "sriov-device-plugin-abcde", Namespace: "sriov-network-operator",
+				Labels: map[string]string{"app": "sriov-device-plugin"}},
+			Spec: k8scorev1.PodSpec{Containers: []k8scorev1.Container{{Name: "sriov-device-plugin", Image: devicePluginImage}}},
 		},
 		{
-			description: "fail because packets sent from traffic generator don't equal VM-under-test packets received",
-			results: status.Results{
-				TrafficGenSentPackets:      trafficGenSentPackets,
-				VMUnderTestReceivedPackets: vmUnderTestReceivedPackets,
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "sriov-network-config-daemon-abcde", Namespace: "sriov-network-operator",
+				Labels: map[string]string{"app": "sriov-network-config-daemon"}},
+			Spec: k8scorev1.PodSpec{
+				InitContainers: []k8scorev1.Container{
+					{Name: "sriov-infiniband-cni", Image: "quay.io/openshift/origin-ib-sriov-cni:4.14"},
+					{Name: "sriov-cni", Image: cniImage},
+				},
+				Containers: []k8scorev1.Container{{Name: "sriov-network-config-daemon", Image: "quay.io/openshift/config-daemon:4.14"}},
 			},
-			expectedRunErr: fmt.Errorf(packetsDontMatchErrMsg, trafficGenSentPackets, vmUnderTestReceivedPackets),
 		},
 	}
+	guestsEnvironment := &status.Environment{VMUnderTestKernel: "5.14.0-362.el9.x86_64", DPDKVersion: "22.11"}
+	testCheckup := checkup.New(testClient, testNamespace, newTestConfig(), executorStub{results: status.Results{
+		TrafficGenSentPackets:      10,
+		VMUnderTestReceivedPackets: 10,
+		Environment:                guestsEnvironment,
+	}})
 
-	for _, testCase := range testCases {
-		t.Run(testCase.description, func(t *testing.T) {
-			testClient := newClientStub()
-			testConfig := newTestConfig()
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	assert.NoError(t, testCheckup.Run(context.Background()))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
 
-			testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{
-				results:    testCase.results,
-				executeErr: testCase.executorFailure,
-			})
+	expectedEnvironment := &status.Environment{
+		KubeVirtVersion:        testKubeVirtVersion,
+		SRIOVDevicePluginImage: devicePluginImage,
+		SRIOVCNIImage:          cniImage,
+		VMUnderTestKernel:      guestsEnvironment.VMUnderTestKernel,
+		DPDKVersion:            guestsEnvironment.DPDKVersion,
+	}
+	assert.Equal(t, expectedEnvironment, testCheckup.Results().Environment)
+}
 
-			assert.NoError(t, testCheckup.Setup(context.Background()))
+func TestRunShouldCompareTheResultsToTheBaseline(t *testing.T) {
+	const baselineConfigMapName = "dpdk-checkup-baseline"
 
-			assert.ErrorContains(t, testCheckup.Run(context.Background()), testCase.expectedRunErr.Error())
+	testConfig := newTestConfig()
+	testConfig.BaselineConfigMapName = baselineConfigMapName
 
-			assert.NoError(t, testCheckup.Teardown(context.Background()))
-			assert.Empty(t, testClient.createdVMIs)
+	t.Run("when the baseline results exist", func(t *testing.T) {
+		testClient := newClientStub()
+		testClient.createdConfigMaps[checkup.ObjectFullName(testNamespace, baselineConfigMapName)] = &k8scorev1.ConfigMap{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: baselineConfigMapName, Namespace: testNamespace},
+			Data: map[string]string{
+				"status.result.trafficGenAvgRxPps":          "8000000",
+				"status.result.vmUnderTestRxDroppedPackets": "0",
+				"status.result.vmUnderTestTxDroppedPackets": "0",
+			},
+		}
+		testCheckup := checkup.New(testClient, testNamespace, testConfig, executorStub{results: status.Results{
+			TrafficGenSentPackets:      10,
+			VMUnderTestReceivedPackets: 10,
+			TrafficGenAvgRxPps:         6_000_000,
+		}})
 
-			actualResults := testCheckup.Results()
-			assert.Equal(t, testCase.results, actualResults)
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Run(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+		assert.Equal(t, &status.BaselineComparison{
+			ConfigMapName:        baselineConfigMapName,
+			AvgRxPpsDeltaPercent: -25,
+			Verdict:              "regressed: average RX rate -25.0%",
+		}, testCheckup.Results().Baseline)
+	})
+
+	t.Run("when the baseline ConfigMap is missing", func(t *testing.T) {
+		testCheckup := checkup.New(newClientStub(), testNamespace, testConfig, executorStub{results: status.Results{
+			TrafficGenSentPackets:      10,
+			VMUnderTestReceivedPackets: 10,
+		}})
+
+		assert.NoError(t, testCheckup.Setup(context.Background()))
+		assert.NoError(t, testCheckup.Run(context.Background()))
+		assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+		actualBaseline := testCheckup.Results().Baseline
+		assert.NotNil(t, actualBaseline)
+		assert.Equal(t, `undetermined, configmaps "dpdk-checkup-baseline" not found`, actualBaseline.Verdict)
+	})
+}
+
+func TestRunShouldFailOnABlockerCriterionOnly(t *testing.T) {
+	testConfig := newTestConfig()
+	testConfig.CriteriaSeverities = map[string]string{config.CriterionDrops: config.SeverityMinor}
+	testCheckup := checkup.New(newClientStub(), testNamespace, testConfig, executorStub{results: status.Results{
+		TrafficGenSentPackets:        10,
+		TrafficGenOutputErrorPackets: 1,
+		VMUnderTestReceivedPackets:   10,
+		VMUnderTestTxDroppedPackets:  1,
+	}})
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	runErr := testCheckup.Run(context.Background())
+	assert.ErrorContains(t, runErr, "detected Error Packets on the traffic generator's side")
+	assert.NotContains(t, runErr.Error(), "dropped")
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+}
+
+// blockerOutcomes returns the outcomes of the built-in criteria, all of blocker severity, given the failure reason of
+// each of the failed ones.
+func blockerOutcomes(failureReasons map[string]string) []status.CriterionOutcome {
+	var outcomes []status.CriterionOutcome
+	for _, criterion := range []string{config.CriterionErrors, config.CriterionDrops, config.CriterionLoss} {
+		outcomes = append(outcomes, status.CriterionOutcome{
+			Name:          criterion,
+			Severity:      config.SeverityBlocker,
+			Passed:        failureReasons[criterion] == "",
+			FailureReason: failureReasons[criterion],
 		})
 	}
+	return outcomes
 }
 
 func assertPodAntiAffinityExists(t *testing.T, testClient *clientStub, vmiName, ownerUID string) {
@@ -374,21 +1939,131 @@ func assertNodeAffinityDoesNotExist(t *testing.T, testClient *clientStub, vmiNam
 }
 
 type clientStub struct {
+	// mutex guards the VMs and VMIs, as they are created concurrently.
+	mutex                    sync.Mutex
+	createdVMs               map[string]*kvcorev1.VirtualMachine
 	createdVMIs              map[string]*kvcorev1.VirtualMachineInstance
+	pendingVMIs              map[string]*kvcorev1.VirtualMachineInstance
 	vmiCreationFailure       error
+	vmiCreations             int
+	failingVMICreations      map[int]bool
+	concurrentVMICreations   *sync.WaitGroup
 	vmiReadFailure           error
+	vmiNeverReady            bool
+	vmiNodeName              string
+	vmiReadyOnWatch          bool
+	vmiFoundLate             bool
+	vmiWatchFailure          error
+	vmiWatches               int
 	vmiDeletionFailure       error
 	createdConfigMaps        map[string]*k8scorev1.ConfigMap
 	configMapCreationFailure error
 	configMapDeletionFailure error
 	skipDeletion             bool
+	nodes                    map[string]*k8scorev1.Node
+	launcherPodSpec          *k8scorev1.PodSpec
+	pods                     []k8scorev1.Pod
+	networkAttachmentDef     *netattdefv1.NetworkAttachmentDefinition
+	networkAttachmentDefs    map[string]*netattdefv1.NetworkAttachmentDefinition
+	kubeVirtVersion          string
+	kubeVirtVersionFailure   error
+	recordedEvents           []k8scorev1.Event
 }
 
 func newClientStub() *clientStub {
 	return &clientStub{
+		createdVMs:        map[string]*kvcorev1.VirtualMachine{},
 		createdVMIs:       map[string]*kvcorev1.VirtualMachineInstance{},
+		pendingVMIs:       map[string]*kvcorev1.VirtualMachineInstance{},
 		createdConfigMaps: map[string]*k8scorev1.ConfigMap{},
+		kubeVirtVersion:   testKubeVirtVersion,
+		nodes:             map[string]*k8scorev1.Node{testNodeName: newReadyNode(testNodeName, sriovResourceName)},
+		networkAttachmentDef: &netattdefv1.NetworkAttachmentDefinition{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:        testNetworkAttachmentDefinitionName,
+				Namespace:   testNamespace,
+				Annotations: map[string]string{"k8s.v1.cni.cncf.io/resourceName": sriovResourceName},
+			},
+			Spec: netattdefv1.NetworkAttachmentDefinitionSpec{
+				Config: `{"cniVersion": "0.4.0", "name": "dpdk-network", "type": "sriov", "vlan": 0}`,
+			},
+		},
+	}
+}
+
+// CreateVirtualMachine also creates the VM's VMI, as the VM controller would do for the Always run strategy.
+// Like the VM controller, which creates the VMI after the VM, the VMI is only found from the read after the first one.
+func (cs *clientStub) CreateVirtualMachine(ctx context.Context,
+	namespace string,
+	vm *kvcorev1.VirtualMachine) (*kvcorev1.VirtualMachine, error) {
+	if cs.vmiCreationFailure != nil {
+		return nil, cs.vmiCreationFailure
+	}
+
+	vm.Namespace = namespace
+	generateName(&vm.ObjectMeta)
+	cs.mutex.Lock()
+	cs.createdVMs[checkup.ObjectFullName(vm.Namespace, vm.Name)] = vm
+	cs.mutex.Unlock()
+
+	vmi := &kvcorev1.VirtualMachineInstance{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:        vm.Name,
+			Labels:      vm.Spec.Template.ObjectMeta.Labels,
+			Annotations: vm.Spec.Template.ObjectMeta.Annotations,
+		},
+		Spec: vm.Spec.Template.Spec,
+	}
+	if _, err := cs.CreateVirtualMachineInstance(ctx, namespace, vmi); err != nil {
+		return nil, err
+	}
+
+	cs.mutex.Lock()
+	vmiFullName := checkup.ObjectFullName(vmi.Namespace, vmi.Name)
+	cs.pendingVMIs[vmiFullName] = vmi
+	delete(cs.createdVMIs, vmiFullName)
+	cs.mutex.Unlock()
+
+	return vm, nil
+}
+
+func (cs *clientStub) ListVirtualMachines(_ context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	vmList := &kvcorev1.VirtualMachineList{}
+	for _, vm := range cs.createdVMs {
+		if vm.Namespace == namespace && selector.Matches(labels.Set(vm.Labels)) {
+			vmList.Items = append(vmList.Items, *vm)
+		}
+	}
+
+	return vmList, nil
+}
+
+func (cs *clientStub) DeleteVirtualMachine(ctx context.Context, namespace, name string) error {
+	vmFullName := checkup.ObjectFullName(namespace, name)
+	cs.mutex.Lock()
+	_, exist := cs.createdVMs[vmFullName]
+	cs.mutex.Unlock()
+	if !exist {
+		return k8serrors.NewNotFound(schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachines"}, name)
+	}
+
+	if err := cs.DeleteVirtualMachineInstance(ctx, namespace, name); err != nil {
+		return err
 	}
+
+	cs.mutex.Lock()
+	delete(cs.createdVMs, vmFullName)
+	cs.mutex.Unlock()
+
+	return nil
 }
 
 func (cs *clientStub) CreateVirtualMachineInstance(_ context.Context,
@@ -398,10 +2073,47 @@ func (cs *clientStub) CreateVirtualMachineInstance(_ context.Context,
 		return nil, cs.vmiCreationFailure
 	}
 
+	if cs.concurrentVMICreations != nil {
+		// Both VMIs creations are expected to be in progress at the same time.
+		cs.concurrentVMICreations.Done()
+		if !waitWithTimeout(cs.concurrentVMICreations, time.Minute) {
+			return nil, errors.New("the VMIs were not created concurrently")
+		}
+	}
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	cs.vmiCreations++
+	if cs.failingVMICreations[cs.vmiCreations] {
+		return nil, errors.New("transient VMI creation failure")
+	}
+
 	vmi.Namespace = namespace
+	generateName(&vmi.ObjectMeta)
+	vmi.UID = types.UID(vmi.Name + "-uid")
+	vmi.ResourceVersion = "1"
+	vmi.Status.NodeName = cs.vmiNodeName
 
 	vmiFullName := checkup.ObjectFullName(vmi.Namespace, vmi.Name)
 	cs.createdVMIs[vmiFullName] = vmi
+	if cs.vmiFoundLate {
+		// The created VMI is only found from the read after the first one, as when read from a lagging cache.
+		cs.pendingVMIs[vmiFullName] = vmi
+		delete(cs.createdVMIs, vmiFullName)
+	}
+
+	if cs.launcherPodSpec != nil {
+		cs.pods = append(cs.pods, k8scorev1.Pod{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:      "virt-launcher-" + vmi.Name,
+				Namespace: namespace,
+				Labels:    map[string]string{kvcorev1.CreatedByLabel: string(vmi.UID)},
+			},
+			Spec:   *cs.launcherPodSpec,
+			Status: k8scorev1.PodStatus{Phase: k8scorev1.PodRunning},
+		})
+	}
 
 	return vmi, nil
 }
@@ -411,12 +2123,23 @@ func (cs *clientStub) GetVirtualMachineInstance(_ context.Context, namespace, na
 		return nil, cs.vmiReadFailure
 	}
 
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
 	vmiFullName := checkup.ObjectFullName(namespace, name)
 	vmi, exist := cs.createdVMIs[vmiFullName]
 	if !exist {
+		if pendingVMI, pending := cs.pendingVMIs[vmiFullName]; pending {
+			cs.createdVMIs[vmiFullName] = pendingVMI
+			delete(cs.pendingVMIs, vmiFullName)
+		}
 		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachineinstances"}, name)
 	}
 
+	if cs.vmiNeverReady {
+		return vmi, nil
+	}
+
 	vmi.Status.Conditions = append(vmi.Status.Conditions,
 		kvcorev1.VirtualMachineInstanceCondition{
 			Type:   kvcorev1.VirtualMachineInstanceReady,
@@ -426,12 +2149,85 @@ func (cs *clientStub) GetVirtualMachineInstance(_ context.Context, namespace, na
 	return vmi, nil
 }
 
+func (cs *clientStub) ListVirtualMachineInstances(_ context.Context,
+	namespace, labelSelector string) (*kvcorev1.VirtualMachineInstanceList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	vmiList := &kvcorev1.VirtualMachineInstanceList{}
+	for _, vmi := range cs.createdVMIs {
+		if vmi.Namespace == namespace && selector.Matches(labels.Set(vmi.Labels)) {
+			vmiList.Items = append(vmiList.Items, *vmi)
+		}
+	}
+
+	return vmiList, nil
+}
+
+// WatchVirtualMachineInstance returns a watch that does not end by itself.
+// When the VMIs become ready on watch, it reports the VMI as ready.
+// Like the API server, a watch from no version first reports the existing VMI as added.
+func (cs *clientStub) WatchVirtualMachineInstance(_ context.Context, namespace, name, resourceVersion string) (watch.Interface, error) {
+	cs.mutex.Lock()
+	cs.vmiWatches++
+	cs.mutex.Unlock()
+	if cs.vmiWatchFailure != nil {
+		return nil, cs.vmiWatchFailure
+	}
+
+	watcher := watch.NewRaceFreeFake()
+
+	cs.mutex.Lock()
+	vmi, exist := cs.createdVMIs[checkup.ObjectFullName(namespace, name)]
+	if exist {
+		vmi = vmi.DeepCopy()
+	}
+	cs.mutex.Unlock()
+
+	if exist && resourceVersion == "" {
+		if !cs.vmiNeverReady {
+			vmi.Status.Conditions = append(vmi.Status.Conditions,
+				kvcorev1.VirtualMachineInstanceCondition{
+					Type:   kvcorev1.VirtualMachineInstanceReady,
+					Status: k8scorev1.ConditionTrue,
+				})
+		}
+		watcher.Add(vmi)
+		return watcher, nil
+	}
+
+	if !cs.vmiReadyOnWatch || !exist {
+		return watcher, nil
+	}
+
+	vmi.Status.Conditions = append(vmi.Status.Conditions,
+		kvcorev1.VirtualMachineInstanceCondition{
+			Type:   kvcorev1.VirtualMachineInstanceReady,
+			Status: k8scorev1.ConditionTrue,
+		})
+	watcher.Modify(vmi)
+
+	return watcher, nil
+}
+
 func (cs *clientStub) DeleteVirtualMachineInstance(_ context.Context, namespace, name string) error {
 	if cs.vmiDeletionFailure != nil {
 		return cs.vmiDeletionFailure
 	}
 
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
 	vmiFullName := checkup.ObjectFullName(namespace, name)
+	if _, pending := cs.pendingVMIs[vmiFullName]; pending {
+		delete(cs.pendingVMIs, vmiFullName)
+		return nil
+	}
 	_, exist := cs.createdVMIs[vmiFullName]
 	if !exist {
 		return k8serrors.NewNotFound(schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachineinstances"}, name)
@@ -450,6 +2246,7 @@ func (cs *clientStub) CreateConfigMap(_ context.Context, namespace string, confi
 	}
 
 	configMap.Namespace = namespace
+	generateName(&configMap.ObjectMeta)
 
 	configMapFullName := checkup.ObjectFullName(configMap.Namespace, configMap.Name)
 	cs.createdConfigMaps[configMapFullName] = configMap
@@ -457,6 +2254,31 @@ func (cs *clientStub) CreateConfigMap(_ context.Context, namespace string, confi
 	return configMap, nil
 }
 
+func (cs *clientStub) GetConfigMap(_ context.Context, namespace, name string) (*k8scorev1.ConfigMap, error) {
+	configMap, exist := cs.createdConfigMaps[checkup.ObjectFullName(namespace, name)]
+	if !exist {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "", Resource: "configmaps"}, name)
+	}
+
+	return configMap, nil
+}
+
+func (cs *clientStub) ListConfigMaps(_ context.Context, namespace, labelSelector string) (*k8scorev1.ConfigMapList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	configMapList := &k8scorev1.ConfigMapList{}
+	for _, configMap := range cs.createdConfigMaps {
+		if configMap.Namespace == namespace && selector.Matches(labels.Set(configMap.Labels)) {
+			configMapList.Items = append(configMapList.Items, *configMap)
+		}
+	}
+
+	return configMapList, nil
+}
+
 func (cs *clientStub) DeleteConfigMap(_ context.Context, namespace, name string) error {
 	if cs.configMapDeletionFailure != nil {
 		return cs.configMapDeletionFailure
@@ -473,6 +2295,108 @@ func (cs *clientStub) DeleteConfigMap(_ context.Context, namespace, name string)
 	return nil
 }
 
+func (cs *clientStub) CreateEvent(_ context.Context, _ string, event *k8scorev1.Event) (*k8scorev1.Event, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	cs.recordedEvents = append(cs.recordedEvents, *event)
+
+	return event, nil
+}
+
+func (cs *clientStub) GetPod(_ context.Context, namespace, name string) (*k8scorev1.Pod, error) {
+	for i := range cs.pods {
+		if cs.pods[i].Namespace == namespace && cs.pods[i].Name == name {
+			return &cs.pods[i], nil
+		}
+	}
+
+	return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "", Resource: "pods"}, name)
+}
+
+func (cs *clientStub) ListPods(_ context.Context, namespace, labelSelector string) (*k8scorev1.PodList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	podList := &k8scorev1.PodList{}
+	for _, pod := range cs.pods {
+		if (namespace == k8smetav1.NamespaceAll || pod.Namespace == namespace) && selector.Matches(labels.Set(pod.Labels)) {
+			podList.Items = append(podList.Items, pod)
+		}
+	}
+
+	return podList, nil
+}
+
+func (cs *clientStub) GetNode(_ context.Context, name string) (*k8scorev1.Node, error) {
+	node, exist := cs.nodes[name]
+	if !exist {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "", Resource: "nodes"}, name)
+	}
+
+	return node, nil
+}
+
+func (cs *clientStub) ListNodes(_ context.Context, _ string) (*k8scorev1.NodeList, error) {
+	nodeList := &k8scorev1.NodeList{}
+	for _, node := range cs.nodes {
+		nodeList.Items = append(nodeList.Items, *node)
+	}
+
+	sort.Slice(nodeList.Items, func(i, j int) bool {
+		return nodeList.Items[i].Name < nodeList.Items[j].Name
+	})
+
+	return nodeList, nil
+}
+
+func (cs *clientStub) GetNetworkAttachmentDefinition(_ context.Context,
+	_, name string) (*netattdefv1.NetworkAttachmentDefinition, error) {
+	if nad, exist := cs.networkAttachmentDefs[name]; exist {
+		return nad, nil
+	}
+
+	if cs.networkAttachmentDef == nil {
+		return nil, k8serrors.NewNotFound(
+			schema.GroupResource{Group: "k8s.cni.cncf.io", Resource: "network-attachment-definitions"}, name)
+	}
+
+	return cs.networkAttachmentDef, nil
+}
+
+func (cs *clientStub) GetKubeVirtVersion(_ context.Context) (string, error) {
+	if cs.kubeVirtVersionFailure != nil {
+		return "", cs.kubeVirtVersionFailure
+	}
+
+	return cs.kubeVirtVersion, nil
+}
+
+func waitWithTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// generateName names the object from its name prefix, as the API server does on creation.
+func generateName(objectMeta *k8smetav1.ObjectMeta) {
+	const randomStringLen = 5
+	if objectMeta.Name == "" && objectMeta.GenerateName != "" {
+		objectMeta.Name = objectMeta.GenerateName + rand.String(randomStringLen)
+	}
+}
+
 func (cs *clientStub) VMIName(namePrefix string) string {
 	for _, vmi := range cs.createdVMIs {
 		if strings.Contains(vmi.Name, namePrefix) {
@@ -509,17 +2433,24 @@ func newTestConfig() config.Config {
 	vmiUnderTestEastHWAddress, _ := net.ParseMAC(vmiUnderTestEastMacAddress)
 	vmiUnderTestWestHWAddress, _ := net.ParseMAC(vmiUnderTestWestMacAddress)
 	return config.Config{
-		PodName:                         testPodName,
-		PodUID:                          testPodUID,
-		NetworkAttachmentDefinitionName: testNetworkAttachmentDefinitionName,
-		TrafficGenTargetNodeName:        "",
-		VMUnderTestTargetNodeName:       "",
-		TrafficGenPacketsPerSecond:      config.TrafficGenDefaultPacketsPerSecond,
-		PortBandwidthGbps:               config.PortBandwidthGbpsDefault,
-		TrafficGenEastMacAddress:        trafficGeneratorEastHWAddress,
-		TrafficGenWestMacAddress:        trafficGeneratorWestHWAddress,
-		VMUnderTestEastMacAddress:       vmiUnderTestEastHWAddress,
-		VMUnderTestWestMacAddress:       vmiUnderTestWestHWAddress,
-		TestDuration:                    config.TestDurationDefault,
+		PodName:                             testPodName,
+		PodUID:                              testPodUID,
+		CheckupUID:                          testPodUID,
+		NetworkAttachmentDefinitionName:     testNetworkAttachmentDefinitionName,
+		NetworkAttachmentDefinitionNameEast: testNetworkAttachmentDefinitionName,
+		NetworkAttachmentDefinitionNameWest: testNetworkAttachmentDefinitionName,
+		TrafficGenTargetNodeName:            "",
+		VMUnderTestTargetNodeName:           "",
+		TrafficGenPacketsPerSecond:          config.TrafficGenDefaultPacketsPerSecond,
+		PortBandwidthGbps:                   config.PortBandwidthGbpsDefault,
+		TrafficGenEastMacAddress:            trafficGeneratorEastHWAddress,
+		TrafficGenWestMacAddress:            trafficGeneratorWestHWAddress,
+		VMUnderTestEastMacAddress:           vmiUnderTestEastHWAddress,
+		VMUnderTestWestMacAddress:           vmiUnderTestWestHWAddress,
+		TestDuration:                        config.TestDurationDefault,
+		TrexBinaryPath:                      config.TrexBinaryPathDefault,
+		TestpmdBinaryPath:                   config.TestpmdBinaryPathDefault,
+		SetupTimeout:                        config.SetupTimeoutDefault,
+		TeardownTimeout:                     config.TeardownTimeoutDefault,
 	}
 }
@@ -0,0 +1,88 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"log"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/version"
+)
+
+// kubeVirtShim omits an optional VMI spec field set by the checkup, on clusters running a KubeVirt version older
+// than the one that introduced it, as such clusters may reject the VMI.
+type kubeVirtShim struct {
+	field        string
+	introducedIn string
+	omit         func(vmi *kvcorev1.VirtualMachineInstance)
+}
+
+var kubeVirtShims = []kubeVirtShim{
+	{
+		field:        "spec.domain.devices.networkInterfaceMultiqueue",
+		introducedIn: "0.26",
+		omit: func(vmi *kvcorev1.VirtualMachineInstance) {
+			vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue = nil
+		},
+	},
+}
+
+// adjustToKubeVirtVersion omits the VMI spec fields the cluster's KubeVirt version does not support.
+// When the version cannot be detected, the VMIs are left as they are.
+func (c *Checkup) adjustToKubeVirtVersion(ctx context.Context) {
+	rawKubeVirtVersion, err := c.client.GetKubeVirtVersion(ctx)
+	if err != nil {
+		log.Printf("Skipping the KubeVirt API compatibility adjustments, failed to detect the KubeVirt version: %v", err)
+		return
+	}
+
+	kubeVirtVersion, err := version.Parse(rawKubeVirtVersion)
+	if err != nil {
+		log.Printf("Skipping the KubeVirt API compatibility adjustments: %v", err)
+		return
+	}
+
+	log.Printf("Detected KubeVirt version %s", rawKubeVirtVersion)
+	for _, vmi := range []*kvcorev1.VirtualMachineInstance{c.vmiUnderTest, c.trafficGen} {
+		for _, field := range applyKubeVirtShims(vmi, kubeVirtVersion) {
+			log.Printf("Warning: omitting %q from VMI %q, it is not supported by KubeVirt %s",
+				field, ObjectFullName(c.namespace, vmi.Name), rawKubeVirtVersion)
+		}
+	}
+}
+
+// applyKubeVirtShims omits the fields not supported by the given KubeVirt version from the VMI,
+// and returns the omitted fields.
+func applyKubeVirtShims(vmi *kvcorev1.VirtualMachineInstance, kubeVirtVersion version.Version) []string {
+	var omittedFields []string
+	for _, shim := range kubeVirtShims {
+		introducedIn, err := version.Parse(shim.introducedIn)
+		if err != nil || !kubeVirtVersion.Before(introducedIn) {
+			continue
+		}
+
+		shim.omit(vmi)
+		omittedFields = append(omittedFields, shim.field)
+	}
+
+	return omittedFields
+}
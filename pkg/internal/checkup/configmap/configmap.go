@@ -26,19 +26,29 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-func New(name, ownerName, ownerUID string, data map[string]string) *k8scorev1.ConfigMap {
-	return &k8scorev1.ConfigMap{
+// New returns a ConfigMap whose name is generated by the API server on its creation, from the given prefix.
+// When an owner Pod is given, the ConfigMap is garbage collected along with it.
+func New(generateName, ownerName, ownerUID string, labels, data map[string]string) *k8scorev1.ConfigMap {
+	configMap := &k8scorev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: "v1",
-					Kind:       "Pod",
-					Name:       ownerName,
-					UID:        types.UID(ownerUID),
-				},
-			},
+			GenerateName: generateName,
+			Labels:       labels,
 		},
 		Data: data,
 	}
+
+	if ownerName != "" && ownerUID != "" {
+		blockOwnerDeletion := true
+		configMap.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion:         "v1",
+				Kind:               "Pod",
+				Name:               ownerName,
+				UID:                types.UID(ownerUID),
+				BlockOwnerDeletion: &blockOwnerDeletion,
+			},
+		}
+	}
+
+	return configMap
 }
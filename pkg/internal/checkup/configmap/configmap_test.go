@@ -33,22 +33,26 @@ import (
 )
 
 func TestNew(t *testing.T) {
-	name := "my-cm"
+	generateName := "my-cm-"
 	ownerName := "my-pod"
 	ownerUID := "1234567890"
+	labels := map[string]string{"some-label": "some-value"}
 	data := map[string]string{"some-key": "some-value"}
 
-	actualConfigMap := configmap.New(name, ownerName, ownerUID, data)
+	actualConfigMap := configmap.New(generateName, ownerName, ownerUID, labels, data)
 
+	blockOwnerDeletion := true
 	expectedConfigMap := &k8scorev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			GenerateName: generateName,
+			Labels:       map[string]string{"some-label": "some-value"},
 			OwnerReferences: []metav1.OwnerReference{
 				{
-					APIVersion: "v1",
-					Kind:       "Pod",
-					Name:       ownerName,
-					UID:        types.UID(ownerUID),
+					APIVersion:         "v1",
+					Kind:               "Pod",
+					Name:               ownerName,
+					UID:                types.UID(ownerUID),
+					BlockOwnerDeletion: &blockOwnerDeletion,
 				},
 			},
 		},
@@ -57,3 +61,19 @@ func TestNew(t *testing.T) {
 
 	assert.Equal(t, expectedConfigMap, actualConfigMap)
 }
+
+func TestNewWithoutOwner(t *testing.T) {
+	generateName := "my-cm-"
+	data := map[string]string{"some-key": "some-value"}
+
+	actualConfigMap := configmap.New(generateName, "", "", nil, data)
+
+	expectedConfigMap := &k8scorev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+		},
+		Data: map[string]string{"some-key": "some-value"},
+	}
+
+	assert.Equal(t, expectedConfigMap, actualConfigMap)
+}
@@ -0,0 +1,75 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"errors"
+	"fmt"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+var ErrIllegalCPUAssignment = errors.New("illegal CPU assignment")
+
+// validateCPUAssignments verifies the explicit CPU assignments of the guests DPDK applications fit the VMIs vCPU topology.
+func (c *Checkup) validateCPUAssignments() error {
+	if cpus := c.params.TestpmdCPUs; len(cpus) > 0 {
+		if err := validateCPUsInTopology(c.vmiUnderTest, cpus); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrIllegalCPUAssignment, config.TestpmdCPUsParamName, err)
+		}
+	}
+
+	if c.params.TrexCPUs == nil {
+		return nil
+	}
+
+	trexCPUs := c.params.TrexCPUAssignment()
+	if err := validateCPUsInTopology(c.trafficGen, trexCPUs.All()); err != nil {
+		return fmt.Errorf("%w: TRex: %v", ErrIllegalCPUAssignment, err)
+	}
+
+	portsPairsCount := len(c.params.TestInterfaces()) / 2
+	if len(trexCPUs.Traffic) < portsPairsCount || len(trexCPUs.Traffic)%portsPairsCount != 0 {
+		return fmt.Errorf("%w: %s: expected a multiple of the %d ports pairs, got %d CPUs",
+			ErrIllegalCPUAssignment, config.TrexTrafficCPUsParamName, portsPairsCount, len(trexCPUs.Traffic))
+	}
+
+	return nil
+}
+
+func validateCPUsInTopology(vmi *kvcorev1.VirtualMachineInstance, cpus []int) error {
+	vCPUs := vCPUsCount(vmi)
+	for _, cpu := range cpus {
+		if cpu >= vCPUs {
+			return fmt.Errorf("CPU %d is out of the %d vCPUs of VMI %q", cpu, vCPUs, vmi.Name)
+		}
+	}
+	return nil
+}
+
+func vCPUsCount(vmi *kvcorev1.VirtualMachineInstance) int {
+	cpu := vmi.Spec.Domain.CPU
+	if cpu == nil {
+		return 1
+	}
+	return int(cpu.Sockets * cpu.Cores * cpu.Threads)
+}
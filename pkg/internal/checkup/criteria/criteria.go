@@ -0,0 +1,85 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package criteria allows registering site-specific acceptance rules, evaluated over the checkup results
+// in addition to the built-in verification.
+//
+// Criteria are registered at build time, from an init function of a file guarded by a build tag, e.g.:
+//
+//	//go:build mysite
+//
+//	package criteria
+//
+//	func init() {
+//		Register(minSentPackets{min: 1_000_000})
+//	}
+package criteria
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// Criterion is an acceptance rule evaluated over the checkup results.
+// Evaluate returns an error describing why the results are not acceptable.
+type Criterion interface {
+	Name() string
+	Evaluate(results status.Results) error
+}
+
+var (
+	registeredMutex sync.Mutex
+	registered      []Criterion
+)
+
+// Register adds a criterion to be evaluated on every checkup run.
+func Register(criterion Criterion) {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+
+	registered = append(registered, criterion)
+}
+
+// Registered returns the criteria registered so far.
+func Registered() []Criterion {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+
+	return append([]Criterion(nil), registered...)
+}
+
+// Evaluate evaluates all the given criteria, and fails when any of them is not met.
+func Evaluate(criteria []Criterion, results status.Results) error {
+	var failedCriteria []string
+	for _, criterion := range criteria {
+		if err := criterion.Evaluate(results); err != nil {
+			failedCriteria = append(failedCriteria, fmt.Sprintf("%s: %v", criterion.Name(), err))
+		}
+	}
+
+	if len(failedCriteria) > 0 {
+		return fmt.Errorf("%d out of %d custom criteria were not met: %s",
+			len(failedCriteria), len(criteria), strings.Join(failedCriteria, "; "))
+	}
+
+	return nil
+}
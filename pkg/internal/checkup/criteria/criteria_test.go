@@ -0,0 +1,75 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package criteria_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/criteria"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+func TestEvaluateShouldSucceedWhenAllCriteriaAreMet(t *testing.T) {
+	testCriteria := []criteria.Criterion{
+		minSentPackets{min: 10},
+		minSentPackets{min: 100},
+	}
+
+	assert.NoError(t, criteria.Evaluate(testCriteria, status.Results{TrafficGenSentPackets: 100}))
+}
+
+func TestEvaluateShouldSucceedWithoutCriteria(t *testing.T) {
+	assert.NoError(t, criteria.Evaluate(nil, status.Results{}))
+}
+
+func TestEvaluateShouldFailWhenAnyCriterionIsNotMet(t *testing.T) {
+	testCriteria := []criteria.Criterion{
+		minSentPackets{min: 10},
+		minSentPackets{min: 1000},
+	}
+
+	err := criteria.Evaluate(testCriteria, status.Results{TrafficGenSentPackets: 100})
+	assert.EqualError(t, err, "1 out of 2 custom criteria were not met: minSentPackets: too few packets were sent")
+}
+
+func TestRegisterShouldAddCriterion(t *testing.T) {
+	criterion := minSentPackets{min: 42}
+	criteria.Register(criterion)
+
+	assert.Contains(t, criteria.Registered(), criterion)
+}
+
+type minSentPackets struct {
+	min int64
+}
+
+func (m minSentPackets) Name() string {
+	return "minSentPackets"
+}
+
+func (m minSentPackets) Evaluate(results status.Results) error {
+	if results.TrafficGenSentPackets < m.min {
+		return errors.New("too few packets were sent")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"log"
+	"path"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// sriovPodsLabelSelector selects the Pods of the SR-IOV device plugin and CNI, as deployed either by the SR-IOV network
+// operator or by the upstream manifests.
+const sriovPodsLabelSelector = "app in (sriov-device-plugin,sriovdp,sriov-network-config-daemon,sriov-cni)"
+
+// detectEnvironment records the cluster side of the environment the results depend on: the KubeVirt version and the
+// images of the SR-IOV device plugin and CNI.
+// Detection is best effort, as it requires cluster scoped permissions, the versions that cannot be detected are left empty.
+func (c *Checkup) detectEnvironment(ctx context.Context) {
+	environment := &status.Environment{}
+
+	kubeVirtVersion, err := c.client.GetKubeVirtVersion(ctx)
+	if err != nil {
+		log.Printf("failed to detect the KubeVirt version for the results: %v", err)
+	}
+	environment.KubeVirtVersion = kubeVirtVersion
+
+	pods, err := c.client.ListPods(ctx, metav1.NamespaceAll, sriovPodsLabelSelector)
+	if err != nil {
+		log.Printf("failed to detect the SR-IOV device plugin and CNI images for the results: %v", err)
+	} else {
+		environment.SRIOVDevicePluginImage, environment.SRIOVCNIImage = sriovImages(pods.Items)
+	}
+
+	c.results.Environment = environment
+}
+
+// sriovImages returns the images of the SR-IOV device plugin and CNI containers found in the given Pods.
+// The SR-IOV network operator installs the CNI binaries from an init container of its config daemon.
+func sriovImages(pods []k8scorev1.Pod) (devicePluginImage, cniImage string) {
+	for i := range pods {
+		containers := append(append([]k8scorev1.Container{}, pods[i].Spec.InitContainers...), pods[i].Spec.Containers...)
+		for _, container := range containers {
+			switch imageName := imageName(container.Image); {
+			case devicePluginImage == "" && strings.HasSuffix(imageName, "sriov-network-device-plugin"):
+				devicePluginImage = container.Image
+			case cniImage == "" && strings.HasSuffix(imageName, "sriov-cni") && !strings.HasSuffix(imageName, "ib-sriov-cni"):
+				cniImage = container.Image
+			}
+		}
+	}
+
+	return devicePluginImage, cniImage
+}
+
+// imageName returns the name of the given image reference, without its registry, repository path, tag or digest,
+// e.g. "sriov-cni" for "ghcr.io/k8snetworkplumbingwg/sriov-cni:v2.7.0".
+func imageName(image string) string {
+	name := path.Base(image)
+	if i := strings.IndexAny(name, ":@"); i != -1 {
+		name = name[:i]
+	}
+	return name
+}
+
+// withClusterEnvironment returns the environment the executor detected in the guests, along with the cluster side of it.
+func withClusterEnvironment(guestsEnvironment, clusterEnvironment *status.Environment) *status.Environment {
+	if guestsEnvironment == nil || clusterEnvironment == nil {
+		if guestsEnvironment != nil {
+			return guestsEnvironment
+		}
+		return clusterEnvironment
+	}
+
+	environment := *guestsEnvironment
+	environment.KubeVirtVersion = clusterEnvironment.KubeVirtVersion
+	environment.SRIOVDevicePluginImage = clusterEnvironment.SRIOVDevicePluginImage
+	environment.SRIOVCNIImage = clusterEnvironment.SRIOVCNIImage
+	return &environment
+}
@@ -0,0 +1,77 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+// abortWatcher follows the abort key on the checkup ConfigMap, allowing to end a long run gracefully, keeping the stats
+// collected so far, rather than deleting the checkup Job and losing them.
+type abortWatcher struct {
+	client    configMapReader
+	namespace string
+	name      string
+	requested bool
+}
+
+func newAbortWatcher(client configMapReader, namespace, name string) *abortWatcher {
+	return &abortWatcher{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// refresh re-reads the abort key and returns whether an abort was requested.
+// Once an abort was requested it is not re-read, as the run cannot be resumed.
+func (w *abortWatcher) refresh(ctx context.Context) bool {
+	if w.name == "" || w.requested {
+		return w.requested
+	}
+
+	configMap, err := w.client.GetConfigMap(ctx, w.namespace, w.name)
+	if err != nil {
+		log.Printf("failed to re-read the %q key: %v", config.AbortKey, err)
+		return false
+	}
+
+	rawVal := configMap.Data[config.AbortKey]
+	if rawVal == "" {
+		return false
+	}
+
+	requested, err := strconv.ParseBool(rawVal)
+	if err != nil {
+		log.Printf("ignoring invalid %q key value %q", config.AbortKey, rawVal)
+		return false
+	}
+
+	if requested {
+		log.Printf("%q key was set, aborting the run...", config.AbortKey)
+		w.requested = true
+	}
+
+	return w.requested
+}
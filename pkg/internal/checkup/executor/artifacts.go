@@ -0,0 +1,105 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/console"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/repro"
+)
+
+// The names of the files exported to the artifacts directory, along with the traffic samples.
+const (
+	transcriptFileName  = "console-transcript.txt"
+	diagnosticsFileName = "diagnostics.txt"
+)
+
+const artifactFileMode = 0o644
+
+// exportTranscript writes the commands executed on the VMI consoles to the artifacts directory.
+func exportTranscript(artifactsDir string, transcript *console.Transcript) {
+	var lines []string
+	for _, entry := range transcript.Entries() {
+		lines = append(lines, entry.String())
+	}
+	exportArtifact(artifactsDir, transcriptFileName, strings.Join(lines, "\n")+"\n")
+}
+
+// exportRecording writes the raw console output of the executed commands to the artifacts directory,
+// to be replayed offline by the console parsers tests.
+func exportRecording(artifactsDir string, recording *console.Recording) {
+	content, err := recording.Marshal()
+	if err != nil {
+		log.Printf("failed to export the console recording: %v", err)
+		return
+	}
+	exportArtifact(artifactsDir, console.RecordingFileName, string(content))
+}
+
+// exportReproScript writes a script replaying the failed run to the artifacts directory.
+// It is exported before the diagnostics are collected, so it holds only the commands of the run itself.
+func (e Executor) exportReproScript(transcript *console.Transcript, failure error, vmiNames ...string) {
+	// The run context may already be done, while the VMIs are still there until the teardown.
+	const getVMITimeout = 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), getVMITimeout)
+	defer cancel()
+
+	script := repro.Script{Namespace: e.namespace, Failure: failure}
+	entries := transcript.Entries()
+	for _, vmiName := range vmiNames {
+		vmi, err := e.vmiGetter.GetVirtualMachineInstance(ctx, e.namespace, vmiName)
+		if err != nil {
+			log.Printf("failed to export the repro script: %v", err)
+			return
+		}
+
+		replayedVMI := repro.VMI{Spec: vmi}
+		for _, entry := range entries {
+			if entry.VMI == path.Join(e.namespace, vmiName) {
+				replayedVMI.Commands = append(replayedVMI.Commands, entry.Command)
+			}
+		}
+		script.VMIs = append(script.VMIs, replayedVMI)
+	}
+
+	content, err := script.Render()
+	if err != nil {
+		log.Printf("failed to export the repro script: %v", err)
+		return
+	}
+	exportArtifact(e.artifactsDir, repro.FileName, content)
+}
+
+// exportArtifact writes the content to the named file in the artifacts directory.
+// The export is best-effort, as it does not affect the checkup verdict.
+func exportArtifact(artifactsDir, fileName, content string) {
+	fileFullPath := path.Join(artifactsDir, fileName)
+	if err := os.WriteFile(fileFullPath, []byte(content), artifactFileMode); err != nil {
+		log.Printf("failed to export %q: %v", fileFullPath, err)
+		return
+	}
+	log.Printf("exported %q", fileFullPath)
+}
@@ -0,0 +1,84 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/vfdriver"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/vfio"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// checkVFs detects the driver of the test NICs of the given VMI along with the hints for its known pitfalls, and
+// verifies the NICs DPDK does not drive through their kernel driver, per the NIC driver mode, are bound to the vfio-pci
+// driver, as a bad binding would otherwise only surface as a failure of the DPDK application to start.
+func (e Executor) checkVFs(runner guestCommandRunner, pciAddresses []string, guestName, vmiName string) ([]status.VFDriver,
+	[]string, error) {
+	var vfDrivers []status.VFDriver
+	var hints []string
+	var vfioPCIAddresses []string
+	for _, pciAddress := range pciAddresses {
+		nic, err := vfdriver.Detect(runner, pciAddress)
+		if err != nil {
+			return nil, nil, exitcode.Classify(exitcode.SetupFailure, fmt.Errorf("VMI \"%s/%s\": %w", e.namespace, vmiName, err))
+		}
+		log.Printf("VMI \"%s/%s\" NIC %s VF driver: %s", e.namespace, vmiName, pciAddress, nic.Driver)
+		vfDriver := status.VFDriver{Guest: guestName, PCIAddress: pciAddress, Driver: nic.Driver}
+		if nic.Driver != vfdriver.Other {
+			if vfDriver.DriverVersion, err = vfdriver.Version(runner, nic.Driver); err != nil {
+				log.Printf("failed to read the %s driver version on VMI \"%s/%s\": %v", nic.Driver, e.namespace, vmiName, err)
+			}
+		}
+		vfDrivers = append(vfDrivers, vfDriver)
+
+		for _, hint := range nic.Hints() {
+			log.Printf("Warning: %s: %s", guestName, hint)
+			hints = append(hints, guestName+": "+hint)
+		}
+
+		if e.expectsVFIOBinding(nic) {
+			vfioPCIAddresses = append(vfioPCIAddresses, pciAddress)
+		}
+	}
+
+	log.Printf("Verifying the NICs of VMI \"%s/%s\" are bound to %s...", e.namespace, vmiName, vfio.DriverName)
+	if err := vfio.VerifyBinding(runner, vfioPCIAddresses); err != nil {
+		return nil, nil, exitcode.Classify(exitcode.SetupFailure, fmt.Errorf("VMI \"%s/%s\": %w", e.namespace, vmiName, err))
+	}
+
+	return vfDrivers, hints, nil
+}
+
+// expectsVFIOBinding reports whether the given NIC is expected to be bound to vfio-pci, as the boot script binds it
+// per the NIC driver mode.
+func (e Executor) expectsVFIOBinding(nic vfdriver.NIC) bool {
+	switch e.nicDriverMode {
+	case config.NICDriverModeVFIO:
+		return true
+	case config.NICDriverModeMLX5:
+		return false
+	default:
+		return !nic.Bifurcated()
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// burstScenario sends line-rate bursts of doubling sizes, in the east to west direction, until a burst size has drops.
+// Bursts expose descriptor rings too small to absorb them, which constant rate traffic does not.
+type burstScenario struct {
+	Executor
+}
+
+func (s burstScenario) Name() string {
+	return config.ScenarioBurst
+}
+
+func (s burstScenario) Run(ctx context.Context, env scenarioEnv, results *status.Results) error {
+	burstSender, ok := env.trafficGenerator.(trafficgen.BurstSender)
+	if !ok {
+		return fmt.Errorf("the %s traffic generator does not support sending bursts", env.trafficGenerator.Name())
+	}
+
+	// The VM under test is migrated during the unicast traffic only
+	s.migration = nil
+
+	burst := &status.Burst{}
+	var sizesCounters []status.PacketCounters
+	for _, size := range burstSizes(s.burstMaxSize) {
+		if s.abort.refresh(ctx) {
+			break
+		}
+		log.Printf("Sending bursts of %d packets...", size)
+		s.progress.setPhase(fmt.Sprintf("burst size %d traffic", size))
+		counters, err := s.runBursts(ctx, burstSender, env, size)
+		if err != nil {
+			return err
+		}
+		sizesCounters = append(sizesCounters, counters)
+
+		if hasDrops(counters) {
+			log.Printf("bursts of %d packets were not forwarded without drops: sent %d, received %d, dropped RX %d TX %d",
+				size, counters.TrafficGenSentPackets, counters.VMUnderTestReceivedPackets,
+				counters.VMUnderTestRxDroppedPackets, counters.VMUnderTestTxDroppedPackets)
+			burst.FirstDroppingSize = size
+			break
+		}
+		burst.MaxSustainedSize = size
+	}
+
+	results.Burst = burst
+	results.Scenarios = append(results.Scenarios, status.ScenarioResults{ID: status.BurstScenarioID, Counters: sumCounters(sizesCounters)})
+	return nil
+}
+
+// burstSizes returns the burst sizes, doubling from the burst min size, and ending with the given burst max size.
+func burstSizes(maxSize int) []int {
+	var sizes []int
+	for size := config.BurstMinSize; size < maxSize; size *= 2 {
+		sizes = append(sizes, size)
+	}
+	return append(sizes, maxSize)
+}
+
+// runBursts clears the stats on both sides, sends the bursts of the given size and collects the counters once all
+// the bursts were sent.
+func (s burstScenario) runBursts(ctx context.Context, burstSender trafficgen.BurstSender, env scenarioEnv,
+	size int) (status.PacketCounters, error) {
+	if err := clearWorkloadStats(env.vmUnderTestWorkload); err != nil {
+		return status.PacketCounters{}, err
+	}
+	if err := clearTrafficGenStats(env.trafficGenerator); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", s.namespace, env.trafficGenVMIName, err)
+	}
+
+	trafficStart := time.Now()
+	sourcePorts := eastToWest.sourcePorts(len(s.testpmdPorts))
+	if err := burstSender.StartBursts(size, sourcePorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("failed to send bursts from traffic generator VMI \"%s/%s\" side: %w",
+			s.namespace, env.trafficGenVMIName, err)
+	}
+
+	allPorts := slices.Concat(sourcePorts, eastToWest.destPorts(len(s.testpmdPorts)))
+	if err := env.trafficGenerator.WaitForCompletion(ctx, allPorts...); err != nil {
+		if ctx.Err() != nil {
+			s.stopTraffic(env.trafficGenerator, sourcePorts)
+		}
+		return status.PacketCounters{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", s.namespace, env.trafficGenVMIName, err)
+	}
+	env.timeline.Record(s.progress.phase, trafficStart)
+
+	iteration, err := calculateStats(env.trafficGenerator, env.vmUnderTestWorkload, eastToWest, len(s.testpmdPorts))
+	return iteration.counters, err
+}
+
+// hasDrops tells whether any of the packets were dropped, lost or errored, on either side.
+func hasDrops(counters status.PacketCounters) bool {
+	return counters.TrafficGenOutputErrorPackets != 0 || counters.TrafficGenInputErrorPackets != 0 ||
+		counters.VMUnderTestRxDroppedPackets != 0 || counters.VMUnderTestTxDroppedPackets != 0 ||
+		counters.TrafficGenSentPackets != counters.VMUnderTestReceivedPackets
+}
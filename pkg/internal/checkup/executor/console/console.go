@@ -41,6 +41,12 @@ type Expecter struct {
 	vmiNamespace        string
 	vmiName             string
 	opts                []expect.Option
+	session             *session
+	transcript          *Transcript
+	recording           *Recording
+	commandTimeout      time.Duration
+	ssh                 *sshTarget
+	hideResponses       bool
 }
 
 const (
@@ -58,10 +64,43 @@ func NewExpecter(serialConsoleClient vmiSerialConsoleClient,
 		vmiNamespace:        vmiNamespace,
 		vmiName:             vmiName,
 		opts:                opts,
+		session:             &session{},
 	}
 }
 
-func (e Expecter) spawnConsole(timeout time.Duration) (*expect.GExpect, error) {
+// WithCommandTimeout returns a copy of the expecter, giving each command it executes at least the given timeout.
+// Commands that are expected to take longer keep their own timeouts.
+func (e Expecter) WithCommandTimeout(timeout time.Duration) Expecter {
+	e.commandTimeout = timeout
+	return e
+}
+
+// WithResponseLogs returns a copy of the expecter, logging the console output of the batches that fail only when enabled.
+func (e Expecter) WithResponseLogs(enabled bool) Expecter {
+	e.hideResponses = !enabled
+	return e
+}
+
+// logResponse logs the console output of a failed batch, unless the expecter is set to hide it.
+func (e Expecter) logResponse(message string, resp []expect.BatchRes) {
+	if e.hideResponses {
+		return
+	}
+
+	var outputs []string
+	for _, res := range resp {
+		outputs = append(outputs, res.Output)
+	}
+	log.Printf("%s, VMI %q console output:\n%s", message, e.vmiFullName(), strings.Join(outputs, "\n"))
+}
+
+// spawnConsole connects to the VMI console, or to the guest SSH shell when the expecter is set to use SSH.
+// When an output capture is given, the raw bytes received from the console are copied to it.
+func (e Expecter) spawnConsole(timeout time.Duration, output *capture) (*expect.GExpect, error) {
+	if e.ssh != nil {
+		return e.spawnSSHConsole(timeout, output)
+	}
+
 	vmiReader, vmiWriter := io.Pipe()
 	expecterReader, expecterWriter := io.Pipe()
 	resCh := make(chan error)
@@ -73,10 +112,16 @@ func (e Expecter) spawnConsole(timeout time.Duration) (*expect.GExpect, error) {
 	}
 	timeout -= time.Since(startTime)
 
+	var consoleOut io.Writer = expecterWriter
+	if output != nil {
+		// The output is captured before it is passed to the expecter, so the capture holds all that the expecter has matched.
+		consoleOut = io.MultiWriter(output, expecterWriter)
+	}
+
 	go func() {
 		resCh <- con.Stream(kubecli.StreamOptions{
 			In:  vmiReader,
-			Out: expecterWriter,
+			Out: consoleOut,
 		})
 	}()
 
@@ -112,22 +157,116 @@ func (e Expecter) GetGuestKernelArgs() (string, error) {
 	return resp[0].Output, err
 }
 
+// GetCommandOutput runs the given shell command in the guest and returns its output.
+func (e Expecter) GetCommandOutput(command string) (string, error) {
+	batch := []expect.Batcher{
+		&expect.BSnd{S: command + "\n"},
+		&expect.BExp{R: PromptExpression},
+	}
+	const getCommandOutputTimeout = 30 * time.Second
+	resp, err := e.SafeExpectBatchWithResponse(batch, getCommandOutputTimeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to run %q on VMI %q: %w", command, e.vmiFullName(), err)
+	}
+
+	// The first submatch is added by expectBatchWithValidatedSend, holding the output that follows the sent command.
+	const outputSubmatch = 1
+	if len(resp) == 0 || len(resp[0].Match) <= outputSubmatch {
+		return "", fmt.Errorf("failed to run %q on VMI %q: unexpected output", command, e.vmiFullName())
+	}
+
+	// The output ends with the prompt line, up to the matched prompt suffix, e.g. "[root@localhost ~]".
+	output := resp[0].Match[outputSubmatch]
+	if promptLineStart := strings.LastIndex(output, "\n"); promptLineStart != -1 {
+		output = output[:promptLineStart]
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// GetImageVersion returns the content of the version manifest file baked into the guest image.
+// An empty version is returned when the guest has no such file.
+func (e Expecter) GetImageVersion(manifestPath string) (string, error) {
+	const versionOutputPrefix = "image-version="
+	versionCmd := fmt.Sprintf("echo \"%s$(cat %s 2>/dev/null)\"\n", versionOutputPrefix, manifestPath)
+	batch := []expect.Batcher{
+		&expect.BSnd{S: versionCmd},
+		&expect.BExp{R: "\n" + versionOutputPrefix + `([^\r\n]*)` + CRLF + ".*" + PromptExpression},
+	}
+	const getImageVersionTimeout = 30 * time.Second
+	resp, err := e.SafeExpectBatchWithResponse(batch, getImageVersionTimeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %q on VMI %q: %w", manifestPath, e.vmiFullName(), err)
+	}
+
+	// The first submatch is added by expectBatchWithValidatedSend, to skip the echo of the sent command.
+	const versionSubmatch = 2
+	if len(resp) == 0 || len(resp[0].Match) <= versionSubmatch {
+		return "", fmt.Errorf("failed to read %q on VMI %q: unexpected output", manifestPath, e.vmiFullName())
+	}
+
+	return strings.TrimSpace(resp[0].Match[versionSubmatch]), nil
+}
+
+// ValidateExecutable verifies that the given binary exists in the guest and is executable.
+// The binary may be given either as a path or as a name looked up in PATH.
+func (e Expecter) ValidateExecutable(binaryPath string) error {
+	testCmd := fmt.Sprintf("test -x \"$(command -v %s)\"\n", binaryPath)
+	batch := []expect.Batcher{
+		&expect.BSnd{S: testCmd},
+		&expect.BExp{R: PromptExpression},
+		&expect.BSnd{S: "echo $?\n"},
+		&expect.BExp{R: RetValue("0")},
+	}
+	const validateExecutableTimeout = 30 * time.Second
+	if _, err := e.SafeExpectBatchWithResponse(batch, validateExecutableTimeout); err != nil {
+		return fmt.Errorf("%q is not an executable on VMI %q: %w", binaryPath, e.vmiFullName(), err)
+	}
+
+	return nil
+}
+
 // SafeExpectBatchWithResponse runs the batch from `expected`, connecting to a VMI's console and
 // waiting for the batch to return with a response until timeout.
 // It validates that the commands arrive to the console.
 // NOTE: This functions inherits limitations from `expectBatchWithValidatedSend`, refer to it for more information.
 func (e Expecter) SafeExpectBatchWithResponse(expected []expect.Batcher,
 	timeout time.Duration) ([]expect.BatchRes, error) {
-	genExpect, err := e.spawnConsole(timeout)
+	resp, err := e.expectBatchWithResponse(expected, timeout)
+	if err == nil {
+		return resp, nil
+	}
+
+	if relogged, reloginErr := e.reloginIfLoggedOut(); reloginErr != nil {
+		log.Printf("failed to login again to VMI %q console: %v", e.vmiFullName(), reloginErr)
+	} else if relogged {
+		return e.expectBatchWithResponse(expected, timeout)
+	}
+
+	return resp, err
+}
+
+func (e Expecter) expectBatchWithResponse(expected []expect.Batcher, timeout time.Duration) ([]expect.BatchRes, error) {
+	timeout = max(timeout, e.commandTimeout)
+
+	// The command is taken before sending the batch, as the expectations are rewritten while it is sent.
+	command := batchCommand(expected)
+	startTime := time.Now()
+
+	output := e.newCapture()
+	genExpect, err := e.spawnConsoleWithReconnect(timeout, output)
 	if err != nil {
+		e.record(command, startTime, "", err)
 		return nil, err
 	}
 	defer genExpect.Close()
 
 	resp, err := expectBatchWithValidatedSend(genExpect, expected, timeout)
 	if err != nil {
-		log.Printf("%v", resp)
+		e.logResponse(fmt.Sprintf("%q failed", command), resp)
 	}
+	e.record(command, startTime, batchExitStatus(expected, resp), err)
+	e.recordExchange(command, output)
 	return resp, err
 }
 
@@ -21,7 +21,6 @@ package console
 
 import (
 	"fmt"
-	"log"
 	"regexp"
 	"time"
 
@@ -35,7 +34,23 @@ func (e Expecter) LoginToCentOSAsRoot(password string) error {
 		promptTimeout     = 5 * time.Second
 	)
 
-	genExpect, err := e.spawnConsole(connectionTimeout)
+	e.session.password = password
+
+	// The password is not recorded.
+	const loginCommand = "<login as root>"
+	startTime := time.Now()
+	var err error
+	defer func() {
+		e.record(loginCommand, startTime, "", err)
+	}()
+
+	// The SSH shell is logged in already.
+	if e.ssh != nil {
+		err = e.waitForSSHShell()
+		return err
+	}
+
+	genExpect, err := e.spawnConsole(connectionTimeout, nil)
 	if err != nil {
 		return err
 	}
@@ -64,7 +79,7 @@ func (e Expecter) LoginToCentOSAsRoot(password string) error {
 			&expect.Case{
 				// Using only "login: " would match things like "Last failed login: Tue Jun  9 22:25:30 UTC 2020 on ttyS0"
 				// and in case the VM's did not get hostname form DHCP server try the default hostname
-				R:  regexp.MustCompile(fmt.Sprintf(`(localhost|centos|%s) login: `, e.vmiName)),
+				R:  regexp.MustCompile(e.loginPromptExpression()),
 				S:  "root\n",
 				T:  expect.Next(),
 				Rt: 10,
@@ -89,22 +104,26 @@ func (e Expecter) LoginToCentOSAsRoot(password string) error {
 	const loginTimeout = 2 * time.Minute
 	res, err := genExpect.ExpectBatch(b, loginTimeout)
 	if err != nil {
-		log.Printf("Login attempt failed: %+v", res)
+		e.logResponse("Login attempt failed", res)
 		// Try once more since sometimes the login prompt is ripped apart by asynchronous daemon updates
 		if retryRes, retryErr := genExpect.ExpectBatch(b, 1*time.Minute); retryErr != nil {
-			log.Printf("Retried login attempt after two minutes failed: %+v", retryRes)
+			e.logResponse("Retried login attempt after two minutes failed", retryRes)
 			return retryErr
 		}
 	}
 
-	err = configureConsole(genExpect)
+	err = e.configureConsole(genExpect)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func configureConsole(expecter expect.Expecter) error {
+func (e Expecter) loginPromptExpression() string {
+	return fmt.Sprintf(`(localhost|centos|%s) login: `, e.vmiName)
+}
+
+func (e Expecter) configureConsole(expecter expect.Expecter) error {
 	batch := []expect.Batcher{
 		&expect.BSnd{S: "stty cols 160 rows 50\n"},
 		&expect.BExp{R: PromptExpression},
@@ -118,7 +137,7 @@ func configureConsole(expecter expect.Expecter) error {
 	const configureConsoleTimeout = 30 * time.Second
 	resp, err := expecter.ExpectBatch(batch, configureConsoleTimeout)
 	if err != nil {
-		log.Printf("%v", resp)
+		e.logResponse("Configuring the console failed", resp)
 	}
 	return err
 }
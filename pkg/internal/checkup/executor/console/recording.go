@@ -0,0 +1,134 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// RecordingFileName is the name of the file the recording is exported to, in the artifacts directory.
+const RecordingFileName = "console-recording.json"
+
+// Exchange holds the raw bytes received from a VMI console while a batch of commands was executed on it.
+type Exchange struct {
+	VMI     string `json:"vmi"`
+	Command string `json:"command"`
+	Output  string `json:"output"`
+}
+
+// Recording captures the raw console bytes of the commands executed on the VMI consoles, in the order they were executed.
+// A recording may be replayed offline by a ReplayClient, to test the console parsers against real sessions.
+// It may be shared by the expecters of several VMIs.
+type Recording struct {
+	mutex     sync.Mutex
+	exchanges []Exchange
+}
+
+func NewRecording() *Recording {
+	return &Recording{}
+}
+
+// ParseRecording reads a recording previously marshaled with Marshal.
+func ParseRecording(data []byte) (*Recording, error) {
+	var exchanges []Exchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		return nil, err
+	}
+
+	return &Recording{exchanges: exchanges}, nil
+}
+
+func (r *Recording) add(exchange Exchange) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.exchanges = append(r.exchanges, exchange)
+}
+
+// Exchanges returns the exchanges recorded so far.
+func (r *Recording) Exchanges() []Exchange {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// Marshal returns the recorded exchanges as indented JSON.
+// The output is not HTML escaped, keeping the recorded commands readable.
+func (r *Recording) Marshal() ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.Exchanges()); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// WithRecording returns a copy of the expecter, recording the raw console bytes of the commands it executes.
+func (e Expecter) WithRecording(recording *Recording) Expecter {
+	e.recording = recording
+	return e
+}
+
+// newCapture returns the writer the console output is copied to, or nil when the expecter is not recording.
+func (e Expecter) newCapture() *capture {
+	if e.recording == nil {
+		return nil
+	}
+
+	return &capture{}
+}
+
+func (e Expecter) recordExchange(command string, output *capture) {
+	if e.recording == nil {
+		return
+	}
+
+	e.recording.add(Exchange{
+		VMI:     e.vmiFullName(),
+		Command: command,
+		Output:  output.String(),
+	})
+}
+
+// capture buffers the console output, while it is written by the console stream.
+type capture struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (c *capture) Write(p []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.buffer.Write(p)
+}
+
+func (c *capture) String() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.buffer.String()
+}
@@ -0,0 +1,99 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sync"
+	"time"
+
+	"kubevirt.io/client-go/kubecli"
+)
+
+// ReplayClient serves a recording in place of the VMIs serial consoles,
+// allowing to run the expecters, and the parsers of their output, offline.
+// Each connection to a VMI console replays the next exchange recorded for that VMI,
+// so the commands are expected to be executed in the order they were recorded.
+type ReplayClient struct {
+	mutex     sync.Mutex
+	exchanges map[string][]Exchange
+}
+
+func NewReplayClient(recording *Recording) *ReplayClient {
+	exchanges := map[string][]Exchange{}
+	for _, exchange := range recording.Exchanges() {
+		exchanges[exchange.VMI] = append(exchanges[exchange.VMI], exchange)
+	}
+
+	return &ReplayClient{exchanges: exchanges}
+}
+
+func (c *ReplayClient) VMISerialConsole(namespace, name string, _ time.Duration) (kubecli.StreamInterface, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	vmiFullName := path.Join(namespace, name)
+	pending := c.exchanges[vmiFullName]
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("no recorded exchange left for VMI %q console", vmiFullName)
+	}
+	c.exchanges[vmiFullName] = pending[1:]
+
+	return replayStream{output: pending[0].Output}, nil
+}
+
+// Pending returns how many recorded exchanges were not replayed yet.
+func (c *ReplayClient) Pending() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pending := 0
+	for _, exchanges := range c.exchanges {
+		pending += len(exchanges)
+	}
+
+	return pending
+}
+
+type replayStream struct {
+	output string
+}
+
+// Stream writes the recorded output and discards the sent commands, until the expecter closes the console.
+func (s replayStream) Stream(options kubecli.StreamOptions) error {
+	inputDone := make(chan struct{})
+	go func() {
+		defer close(inputDone)
+		_, _ = io.Copy(io.Discard, options.In)
+	}()
+
+	_, err := io.WriteString(options.Out, s.output)
+	<-inputDone
+
+	return err
+}
+
+// AsConn is not supported, as the expecter uses only Stream.
+func (s replayStream) AsConn() net.Conn {
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"kubevirt.io/client-go/kubecli"
+)
+
+// Script plays the guest side of the VMIs serial consoles.
+type Script interface {
+	// Execute runs a line sent to the given VMI console, and returns what the guest prints in response,
+	// including the prompt that follows.
+	Execute(vmiName, line string) string
+}
+
+// ScriptedClient serves scripted guests in place of the VMIs serial consoles,
+// allowing to run the expecters, and the flows built on them, without a cluster.
+// Unlike a ReplayClient, the commands are answered as they arrive, so they may be executed in any order.
+type ScriptedClient struct {
+	script Script
+}
+
+func NewScriptedClient(script Script) *ScriptedClient {
+	return &ScriptedClient{script: script}
+}
+
+func (c *ScriptedClient) VMISerialConsole(_, name string, _ time.Duration) (kubecli.StreamInterface, error) {
+	return scriptedStream{script: c.script, vmiName: name}, nil
+}
+
+type scriptedStream struct {
+	script  Script
+	vmiName string
+}
+
+// Stream echoes each line sent to the console followed by the guest response, as a terminal does,
+// until the expecter closes the console.
+func (s scriptedStream) Stream(options kubecli.StreamOptions) error {
+	reader := bufio.NewReader(options.In)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+				return nil
+			}
+			return err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		response := s.script.Execute(s.vmiName, line)
+		if _, err := io.WriteString(options.Out, line+CRLF+toCRLF(response)); err != nil {
+			// The expecter may close the console before reading the whole response.
+			return nil
+		}
+	}
+}
+
+// AsConn is not supported, as the expecter uses only Stream.
+func (s scriptedStream) AsConn() net.Conn {
+	return nil
+}
+
+func toCRLF(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, CRLF, "\n"), "\n", CRLF)
+}
@@ -0,0 +1,101 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console
+
+import (
+	"log"
+	"time"
+
+	expect "github.com/google/goexpect"
+)
+
+// session holds the state shared by all the copies of an Expecter to a single VMI console.
+type session struct {
+	password   string
+	reconnects int
+	shell      *sshShell
+}
+
+// Reconnects returns how many times the console session had to be re-established,
+// either because connecting to it failed or because the guest had logged it out.
+func (e Expecter) Reconnects() int {
+	return e.session.reconnects
+}
+
+func (e Expecter) spawnConsoleWithReconnect(timeout time.Duration, output *capture) (*expect.GExpect, error) {
+	genExpect, err := e.spawnConsole(timeout, output)
+	if err == nil {
+		return genExpect, nil
+	}
+
+	log.Printf("failed to connect to VMI %q console, reconnecting: %v", e.vmiFullName(), err)
+	e.session.reconnects++
+
+	return e.spawnConsole(timeout, output)
+}
+
+// reloginIfLoggedOut logs in again when the guest has logged the console session out, e.g. after a long idle period.
+// It reports whether the session was logged in again.
+func (e Expecter) reloginIfLoggedOut() (bool, error) {
+	// The guest does not log the SSH shell out.
+	if e.session.password == "" || e.ssh != nil {
+		return false, nil
+	}
+
+	loggedOut, err := e.isLoggedOut()
+	if err != nil || !loggedOut {
+		return false, err
+	}
+
+	log.Printf("VMI %q console session was logged out, logging in again...", e.vmiFullName())
+	e.session.reconnects++
+
+	if err := e.LoginToCentOSAsRoot(e.session.password); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (e Expecter) isLoggedOut() (bool, error) {
+	const (
+		connectionTimeout = 10 * time.Second
+		promptTimeout     = 5 * time.Second
+	)
+
+	genExpect, err := e.spawnConsole(connectionTimeout, nil)
+	if err != nil {
+		return false, err
+	}
+	defer genExpect.Close()
+
+	_, err = genExpect.ExpectBatch([]expect.Batcher{
+		&expect.BSnd{S: "\n"},
+		&expect.BExp{R: e.loginPromptExpression()},
+	},
+		promptTimeout,
+	)
+
+	return err == nil, nil
+}
+
+func (e Expecter) vmiFullName() string {
+	return e.vmiNamespace + "/" + e.vmiName
+}
@@ -0,0 +1,325 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	expect "github.com/google/goexpect"
+	"golang.org/x/crypto/ssh"
+)
+
+const sshUser = "root"
+
+type sshTarget struct {
+	address      string
+	clientConfig *ssh.ClientConfig
+}
+
+// sshShell is a login shell on the guest, opened over SSH.
+// It outlives the batches sent to it, so the programs started by one batch, e.g. testpmd, keep running for the next ones,
+// as they do on the serial console.
+type sshShell struct {
+	client *ssh.Client
+	stdin  io.Writer
+	done   chan struct{}
+
+	mutex    sync.Mutex
+	attached *io.PipeWriter
+	output   *capture
+}
+
+// WithSSH returns a copy of the expecter, reaching the guest shell over SSH at the given address rather than over the
+// VMI serial console. The root user is authenticated by the given signer.
+func (e Expecter) WithSSH(address string, signer ssh.Signer) Expecter {
+	e.ssh = &sshTarget{
+		address: address,
+		clientConfig: &ssh.ClientConfig{
+			User: sshUser,
+			Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			// The guests generate their host keys on their first boot, so they cannot be known in advance.
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint: gosec
+		},
+	}
+	return e
+}
+
+// Close closes the SSH shell of the expecter, when opened.
+func (e Expecter) Close() error {
+	if e.session.shell == nil {
+		return nil
+	}
+	return e.session.shell.close()
+}
+
+// GetSideCommandOutput runs the given shell command in an SSH session of its own, aside the guest shell, and returns its
+// output, along with its standard error. It lets the guest be queried while a program occupies the shell, e.g. testpmd.
+func (e Expecter) GetSideCommandOutput(command string) (string, error) {
+	const sideCommandTimeout = 30 * time.Second
+
+	shell := e.session.shell
+	if shell == nil || !shell.isOpen() {
+		return "", fmt.Errorf("failed to run %q on VMI %q: the SSH shell is not open", command, e.vmiFullName())
+	}
+
+	session, err := shell.client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %q on VMI %q: %w", command, e.vmiFullName(), err)
+	}
+	defer session.Close()
+
+	timer := time.AfterFunc(sideCommandTimeout, func() { session.Close() })
+	defer timer.Stop()
+
+	output, err := session.CombinedOutput(command)
+	if err != nil {
+		return "", fmt.Errorf("failed to run %q on VMI %q: %w", command, e.vmiFullName(), err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// spawnSSHConsole attaches an expecter to the guest SSH shell, opening the shell when it is not open yet.
+// When an output capture is given, the bytes received from the shell are copied to it.
+func (e Expecter) spawnSSHConsole(timeout time.Duration, output *capture) (*expect.GExpect, error) {
+	shell, err := e.sshShell(timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	expecterReader, expecterWriter := io.Pipe()
+	shell.attach(expecterWriter, output)
+
+	detached := make(chan struct{})
+	var detachOnce sync.Once
+
+	e.opts = append(e.opts, expect.SendTimeout(timeout), expect.Verbose(false))
+	genExpect, _, err := expect.SpawnGeneric(&expect.GenOptions{
+		In:  nopWriteCloser{Writer: shell.stdin},
+		Out: expecterReader,
+		Wait: func() error {
+			select {
+			case <-detached:
+				return nil
+			case <-shell.done:
+				return errors.New("the SSH shell was closed")
+			}
+		},
+		Close: func() error {
+			detachOnce.Do(func() {
+				shell.detach()
+				close(detached)
+			})
+			return nil
+		},
+		Check: shell.isOpen,
+	}, timeout, e.opts...)
+	return genExpect, err
+}
+
+// sshShell returns the open guest SSH shell, opening it again when the previous one was closed.
+func (e Expecter) sshShell(timeout time.Duration) (*sshShell, error) {
+	if shell := e.session.shell; shell != nil {
+		if shell.isOpen() {
+			return shell, nil
+		}
+
+		log.Printf("VMI %q SSH shell was closed, reconnecting...", e.vmiFullName())
+		e.session.reconnects++
+	}
+
+	shell, err := openSSHShell(e.ssh, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open an SSH shell on VMI %q: %w", e.vmiFullName(), err)
+	}
+	e.session.shell = shell
+
+	return shell, nil
+}
+
+// waitForSSHShell waits for the guest SSH server to accept connections and for the shell prompt.
+func (e Expecter) waitForSSHShell() error {
+	const (
+		connectionTimeout       = 10 * time.Second
+		connectionRetryInterval = 5 * time.Second
+		sshServerTimeout        = 2 * time.Minute
+		promptTimeout           = 30 * time.Second
+	)
+
+	deadline := time.Now().Add(sshServerTimeout)
+	for {
+		genExpect, err := e.spawnConsole(connectionTimeout, nil)
+		if err == nil {
+			defer genExpect.Close()
+
+			_, err = genExpect.ExpectBatch([]expect.Batcher{
+				&expect.BSnd{S: "\n"},
+				&expect.BExp{R: PromptExpression},
+			},
+				promptTimeout,
+			)
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(connectionRetryInterval)
+	}
+}
+
+func openSSHShell(target *sshTarget, timeout time.Duration) (*sshShell, error) {
+	clientConfig := *target.clientConfig
+	clientConfig.Timeout = timeout
+
+	client, err := ssh.Dial("tcp", target.address, &clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	shell, err := startShell(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return shell, nil
+}
+
+func startShell(client *ssh.Client) (*sshShell, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	// The terminal matches the serial console one, so the programs print the same output on both.
+	const (
+		terminal = "vt220"
+		rows     = 50
+		cols     = 160
+	)
+	if err := session.RequestPty(terminal, rows, cols, ssh.TerminalModes{ssh.ECHO: 1}); err != nil {
+		return nil, err
+	}
+
+	if err := session.Shell(); err != nil {
+		return nil, err
+	}
+
+	shell := &sshShell{
+		client: client,
+		stdin:  stdin,
+		done:   make(chan struct{}),
+	}
+	go shell.forwardOutput(stdout)
+
+	return shell, nil
+}
+
+// forwardOutput copies the shell output to the attached expecter, until the shell is closed.
+// The output received while no expecter is attached is dropped, as the serial console output is.
+func (s *sshShell) forwardOutput(stdout io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := stdout.Read(buf)
+		if n > 0 {
+			s.write(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	close(s.done)
+	if s.attached != nil {
+		s.attached.Close()
+	}
+}
+
+func (s *sshShell) write(data []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.attached == nil {
+		return
+	}
+	if s.output != nil {
+		_, _ = s.output.Write(data)
+	}
+	_, _ = s.attached.Write(data)
+}
+
+func (s *sshShell) attach(writer *io.PipeWriter, output *capture) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.attached = writer
+	s.output = output
+}
+
+func (s *sshShell) detach() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.attached != nil {
+		s.attached.Close()
+	}
+	s.attached = nil
+	s.output = nil
+}
+
+func (s *sshShell) isOpen() bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *sshShell) close() error {
+	return s.client.Close()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
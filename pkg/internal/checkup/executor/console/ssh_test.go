@@ -0,0 +1,196 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console_test
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/console"
+)
+
+const (
+	testNamespace = "default"
+	testVMIName   = "vmi-under-test"
+	shellPrompt   = "[root@localhost ~]# "
+)
+
+func TestSSHShellShouldKeepItsStateAcrossCommands(t *testing.T) {
+	signer := newTestSigner(t)
+	address := startGuestSSHServer(t, signer.PublicKey())
+
+	expecter := console.NewExpecter(nil, testNamespace, testVMIName).WithSSH(address, signer)
+	defer expecter.Close()
+
+	assert.NoError(t, expecter.LoginToCentOSAsRoot(""))
+
+	_, err := expecter.GetCommandOutput("value=42")
+	assert.NoError(t, err)
+
+	output, err := expecter.GetCommandOutput("echo $value")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", output)
+	assert.Zero(t, expecter.Reconnects())
+}
+
+func TestSideCommandShouldRunAsideTheSSHShell(t *testing.T) {
+	signer := newTestSigner(t)
+	address := startGuestSSHServer(t, signer.PublicKey())
+
+	expecter := console.NewExpecter(nil, testNamespace, testVMIName).WithSSH(address, signer)
+	defer expecter.Close()
+
+	_, err := expecter.GetSideCommandOutput("hostname")
+	assert.ErrorContains(t, err, "the SSH shell is not open")
+
+	assert.NoError(t, expecter.LoginToCentOSAsRoot(""))
+	_, err = expecter.GetCommandOutput("value=42")
+	assert.NoError(t, err)
+
+	output, err := expecter.GetSideCommandOutput("hostname")
+	assert.NoError(t, err)
+	assert.Equal(t, "ran hostname", output)
+
+	output, err = expecter.GetCommandOutput("echo $value")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", output)
+	assert.Zero(t, expecter.Reconnects())
+}
+
+func TestSSHShellShouldRejectAnUnauthorizedKey(t *testing.T) {
+	address := startGuestSSHServer(t, newTestSigner(t).PublicKey())
+
+	expecter := console.NewExpecter(nil, testNamespace, testVMIName).WithSSH(address, newTestSigner(t))
+	defer expecter.Close()
+
+	_, err := expecter.GetCommandOutput("true")
+	assert.ErrorContains(t, err, "unable to authenticate")
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	assert.NoError(t, err)
+
+	return signer
+}
+
+// startGuestSSHServer starts an SSH server authorizing the given key, whose shell echoes its input,
+// sets the variables assigned to it and prints them back. The commands executed aside the shell report they ran.
+func startGuestSSHServer(t *testing.T, authorizedKey ssh.PublicKey) string {
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(authorizedKey.Marshal()) {
+				return nil, fmt.Errorf("unauthorized key")
+			}
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(newTestSigner(t))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSHConnection(conn, serverConfig)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveSSHConnection(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			for request := range channelRequests {
+				_ = request.Reply(request.Type == "pty-req" || request.Type == "shell" || request.Type == "exec", nil)
+				switch request.Type {
+				case "shell":
+					go runShell(channel)
+				case "exec":
+					go runCommand(channel, request.Payload)
+				}
+			}
+		}()
+	}
+}
+
+func runShell(channel ssh.Channel) {
+	defer channel.Close()
+
+	variables := map[string]string{}
+	_, _ = channel.Write([]byte(shellPrompt))
+
+	lines := bufio.NewScanner(channel)
+	for lines.Scan() {
+		command := lines.Text()
+		output := ""
+		if name, value, found := strings.Cut(command, "="); found {
+			variables[name] = value
+		} else if name, found := strings.CutPrefix(command, "echo $"); found {
+			output = variables[name] + "\r\n"
+		}
+		_, _ = channel.Write([]byte(command + "\r\n" + output + shellPrompt))
+	}
+}
+
+func runCommand(channel ssh.Channel, payload []byte) {
+	defer channel.Close()
+
+	var execRequest struct{ Command string }
+	if err := ssh.Unmarshal(payload, &execRequest); err != nil {
+		return
+	}
+
+	_, _ = channel.Write([]byte("ran " + execRequest.Command + "\n"))
+	_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{}))
+}
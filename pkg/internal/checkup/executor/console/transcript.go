@@ -0,0 +1,141 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package console
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	expect "github.com/google/goexpect"
+)
+
+// TranscriptEntry records a single batch of commands executed on a VMI console.
+type TranscriptEntry struct {
+	VMI        string
+	Command    string
+	Timestamp  time.Time
+	Duration   time.Duration
+	ExitStatus string
+	Err        error
+}
+
+func (t TranscriptEntry) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s %s (%s)", t.Timestamp.UTC().Format(time.RFC3339), t.VMI, t.Duration.Round(time.Millisecond)))
+	if t.ExitStatus != "" {
+		sb.WriteString(" exit " + t.ExitStatus)
+	}
+	if t.Err != nil {
+		sb.WriteString(fmt.Sprintf(" failed: %v", t.Err))
+	}
+	sb.WriteString(": " + t.Command)
+
+	return sb.String()
+}
+
+// Transcript records the commands executed on the VMI consoles, in the order they were executed.
+// It may be shared by the expecters of several VMIs.
+type Transcript struct {
+	mutex   sync.Mutex
+	entries []TranscriptEntry
+}
+
+func NewTranscript() *Transcript {
+	return &Transcript{}
+}
+
+func (t *Transcript) add(entry TranscriptEntry) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.entries = append(t.entries, entry)
+}
+
+// Entries returns the commands recorded so far.
+func (t *Transcript) Entries() []TranscriptEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return append([]TranscriptEntry(nil), t.entries...)
+}
+
+// WithTranscript returns a copy of the expecter, recording the commands it executes to the given transcript.
+func (e Expecter) WithTranscript(transcript *Transcript) Expecter {
+	e.transcript = transcript
+	return e
+}
+
+func (e Expecter) record(command string, startTime time.Time, exitStatus string, err error) {
+	if e.transcript == nil {
+		return
+	}
+
+	e.transcript.add(TranscriptEntry{
+		VMI:        e.vmiFullName(),
+		Command:    command,
+		Timestamp:  startTime,
+		Duration:   time.Since(startTime),
+		ExitStatus: exitStatus,
+		Err:        err,
+	})
+}
+
+// batchCommand joins the commands sent by the batch, as they were typed on the console.
+func batchCommand(batch []expect.Batcher) string {
+	var commands []string
+	for _, batcher := range batch {
+		if batcher.Cmd() != expect.BatchSend {
+			continue
+		}
+		command := strings.TrimSuffix(batcher.Arg(), "\n")
+		if command == "" {
+			command = "<enter>"
+		}
+		commands = append(commands, command)
+	}
+
+	return strings.Join(commands, "; ")
+}
+
+// batchExitStatus returns the exit status echoed by the batch, if the batch queries it.
+func batchExitStatus(batch []expect.Batcher, resp []expect.BatchRes) string {
+	const exitStatusCommand = "echo $?\n"
+	exitStatusOutput := regexp.MustCompile(`\n(\d+)\r?\n`)
+
+	exitStatus := ""
+	for idx, batcher := range batch {
+		if batcher.Cmd() != expect.BatchSend || batcher.Arg() != exitStatusCommand {
+			continue
+		}
+		for _, res := range resp {
+			if res.Idx != idx+1 {
+				continue
+			}
+			if match := exitStatusOutput.FindStringSubmatch(res.Output); match != nil {
+				exitStatus = match[1]
+			}
+		}
+	}
+
+	return exitStatus
+}
@@ -0,0 +1,99 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+)
+
+// guestLogLines is the number of lines collected from the tail of each guest log.
+const guestLogLines = 100
+
+type portStatsGetter interface {
+	GetPortStats() (string, error)
+}
+
+// diagnosticsCollector gathers the guests logs once the run has failed.
+// The guests consoles are registered as they are logged into, and the workload once it runs on the VM under test console.
+// As the guests are set up concurrently, they are registered under a lock.
+type diagnosticsCollector struct {
+	mutex       sync.Mutex
+	vmUnderTest guestCommandRunner
+	trafficGen  guestCommandRunner
+	workload    workload
+}
+
+func (d *diagnosticsCollector) setVMUnderTest(runner guestCommandRunner) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.vmUnderTest = runner
+}
+
+func (d *diagnosticsCollector) setTrafficGen(runner guestCommandRunner) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.trafficGen = runner
+}
+
+func (d *diagnosticsCollector) setWorkload(w workload) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.workload = w
+}
+
+func (d *diagnosticsCollector) collect() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var sections []string
+	addSection := func(title, output string, err error) {
+		if err != nil {
+			output = fmt.Sprintf("failed to collect: %v", err)
+		}
+		sections = append(sections, fmt.Sprintf("==== %s ====\n%s", title, output))
+	}
+
+	if d.trafficGen != nil {
+		output, err := d.trafficGen.GetCommandOutput(fmt.Sprintf("dmesg | tail -n %d", guestLogLines))
+		addSection("traffic generator dmesg", output, err)
+
+		output, err = d.trafficGen.GetCommandOutput(
+			fmt.Sprintf("journalctl -u %s --no-pager | tail -n %d", trex.SystemdUnitFileName, guestLogLines))
+		addSection(fmt.Sprintf("traffic generator %s journal", trex.SystemdUnitFileName), output, err)
+	}
+
+	switch {
+	case d.workload != nil:
+		// The running workload holds the VM under test console, its shell is not reachable.
+		if getter, ok := d.workload.(portStatsGetter); ok {
+			output, err := getter.GetPortStats()
+			addSection(fmt.Sprintf("VM under test %s port stats", d.workload.Name()), output, err)
+		}
+	case d.vmUnderTest != nil:
+		output, err := d.vmUnderTest.GetCommandOutput(fmt.Sprintf("dmesg | tail -n %d", guestLogLines))
+		addSection("VM under test dmesg", output, err)
+	}
+
+	return strings.Join(sections, "\n")
+}
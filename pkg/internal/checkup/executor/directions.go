@@ -0,0 +1,62 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// trafficDirection is the direction the traffic is sent in, over each pair of ports.
+// By default, the traffic is sent from the first (east) port of each pair to the second (west) one.
+type trafficDirection struct {
+	scenarioID string
+	reversed   bool
+}
+
+var (
+	eastToWest = trafficDirection{scenarioID: status.EastToWestScenarioID}
+	westToEast = trafficDirection{scenarioID: status.WestToEastScenarioID, reversed: true}
+)
+
+// sourcePorts returns the traffic generator ports the traffic is sent from.
+func (d trafficDirection) sourcePorts(portsCount int) []trafficgen.PortIdx {
+	if d.reversed {
+		return trafficgen.DestPorts(portsCount)
+	}
+	return trafficgen.SourcePorts(portsCount)
+}
+
+// destPorts returns the traffic generator ports the traffic is received on.
+func (d trafficDirection) destPorts(portsCount int) []trafficgen.PortIdx {
+	if d.reversed {
+		return trafficgen.SourcePorts(portsCount)
+	}
+	return trafficgen.DestPorts(portsCount)
+}
+
+// portsPair returns the VM under test ports the traffic of the pair starting at the given port is received on,
+// and forwarded from.
+func (d trafficDirection) portsPair(firstPortIdx int) (rxPortIdx, txPortIdx int) {
+	if d.reversed {
+		return firstPortIdx + 1, firstPortIdx
+	}
+	return firstPortIdx, firstPortIdx + 1
+}
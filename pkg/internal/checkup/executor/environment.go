@@ -0,0 +1,76 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"log"
+	"regexp"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// trexVersionManifestPath is the file baked into the traffic generator image, holding the TRex version it was built with.
+const trexVersionManifestPath = "/etc/kubevirt-dpdk-checkup/trex-version"
+
+const (
+	kernelReleaseCommand = "uname -r"
+	dpdkVersionCommand   = "rpm -q --queryformat '%{VERSION}' dpdk"
+)
+
+// versionRegex matches the output of the version commands, as opposed to their error messages,
+// e.g. "package dpdk is not installed".
+var versionRegex = regexp.MustCompile(`^v?[0-9][0-9A-Za-z._+-]*$`)
+
+// detectGuestsEnvironment reads the guest side of the environment the results depend on: the guests kernel release,
+// the DPDK version of the VM under test and the TRex version of the traffic generator.
+// Detection is best effort, the versions that cannot be read are left empty.
+func detectGuestsEnvironment(vmUnderTestRunner, trafficGenRunner guestCommandRunner) *status.Environment {
+	environment := &status.Environment{
+		VMUnderTestKernel: guestVersion(vmUnderTestRunner, kernelReleaseCommand),
+		TrafficGenKernel:  guestVersion(trafficGenRunner, kernelReleaseCommand),
+		DPDKVersion:       guestVersion(vmUnderTestRunner, dpdkVersionCommand),
+	}
+
+	trexVersion, err := trafficGenRunner.GetImageVersion(trexVersionManifestPath)
+	if err != nil {
+		log.Printf("failed to read the TRex version: %v", err)
+	}
+	environment.TrexVersion = trexVersion
+
+	log.Printf("Guests environment: VM under test kernel %q, traffic generator kernel %q, DPDK %q, TRex %q",
+		environment.VMUnderTestKernel, environment.TrafficGenKernel, environment.DPDKVersion, environment.TrexVersion)
+
+	return environment
+}
+
+func guestVersion(runner guestCommandRunner, command string) string {
+	output, err := runner.GetCommandOutput(command)
+	if err != nil {
+		log.Printf("failed to run %q: %v", command, err)
+		return ""
+	}
+
+	if !versionRegex.MatchString(output) {
+		log.Printf("unexpected %q output: %q", command, output)
+		return ""
+	}
+
+	return output
+}
@@ -21,194 +21,1350 @@ package executor
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/ssh"
+
+	k8scorev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kvcorev1 "kubevirt.io/api/core/v1"
 	"kubevirt.io/client-go/kubecli"
 
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/console"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/guestagent"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/l3fwd"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/telemetry"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/testpmd"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/timeseries"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/events"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/version"
+)
+
+// The executor phases, as reported in the progress and recorded in the results timeline.
+const (
+	loginPhase           = "login"
+	trafficGenSetupPhase = "traffic generator setup"
+	warmupPhase          = "warm-up"
 )
 
+// The guests names, as reported in the results of the checks performed in both guests.
+const (
+	vmUnderTestGuestName = "VM under test"
+	trafficGenGuestName  = "traffic generator"
+)
+
+// trexConsoleBinaryName is the TRex console, expected to reside next to the TRex server binary.
+const trexConsoleBinaryName = "trex-console"
+
 type vmiSerialConsoleClient interface {
 	VMISerialConsole(namespace, name string, timeout time.Duration) (kubecli.StreamInterface, error)
 }
 
+type vmiPortForwarder interface {
+	VMIPortForward(namespace, name string, port int) (kubecli.StreamInterface, error)
+}
+
+type vmiGetter interface {
+	GetVirtualMachineInstance(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstance, error)
+}
+
+type executorClient interface {
+	vmiSerialConsoleClient
+	configMapReader
+	vmiGetter
+	vmiMigrator
+	vmiPortForwarder
+	guestagent.Client
+	CreateEvent(ctx context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error)
+}
+
+// guestCommandRunner runs the guest commands whose output is parsed by the checks, over the guest command transport.
+// The interactive sessions, i.e. the VM under test workload and the TRex console, run on the console expecter regardless.
+type guestCommandRunner interface {
+	GetCommandOutput(command string) (string, error)
+	GetGuestKernelArgs() (string, error)
+	GetImageVersion(manifestPath string) (string, error)
+	ValidateExecutable(binaryPath string) error
+}
+
 type Executor struct {
 	vmiSerialClient                  vmiSerialConsoleClient
+	guestAgentClient                 guestagent.Client
+	vmiGetter                        vmiGetter
+	vmiPortForwarder                 vmiPortForwarder
 	namespace                        string
 	vmiPassword                      string
-	vmiUnderTestEastNICPCIAddress    string
-	trafficGenEastMACAddress         string
-	vmiUnderTestWestNICPCIAddress    string
-	trafficGenWestMACAddress         string
+	vmUnderTestMACAddresses          []net.HardwareAddr
+	trafficGenMACAddresses           []net.HardwareAddr
+	vmUnderTestPCIAddresses          []string
+	trafficGenPCIAddresses           []string
+	testpmdPorts                     []testpmd.Port
+	testpmdForwardMode               testpmd.ForwardMode
+	testpmdTuning                    testpmd.Tuning
+	nicDriverMode                    string
 	testDuration                     time.Duration
+	testIterations                   int
+	perDirectionRuns                 bool
+	scenarios                        []string
+	burstMaxSize                     int
+	rateSweepSteps                   int
+	rateSweepStepDuration            time.Duration
+	snapshotInterval                 time.Duration
+	warmupDuration                   time.Duration
 	verbosePrintsEnabled             bool
+	logConsoleTranscripts            bool
+	verbosity                        *verbosityWatcher
+	abort                            *abortWatcher
+	events                           events.Recorder
+	consoleTranscript                string
 	trafficGeneratorPacketsPerSecond string
+	trafficGenServiceMode            bool
+	trafficGenFlowStats              bool
+	trafficVlanID                    int
+	trafficGenPortAddresses          []status.PortAddress
+	trexBinaryPath                   string
+	testpmdBinaryPath                string
+	vmUnderTestWorkload              string
+	l3fwdPorts                       []l3fwd.Port
+	artifactsDir                     string
+	reproScript                      bool
+	cpuIsolationCheck                string
+	measurementIsolation             string
+	migration                        *liveMigration
+	trafficGenerators                trafficgen.Registry
+	trafficGeneratorName             string
+	progress                         *progressTracker
+	trafficGenImage                  string
+	vmUnderTestImage                 string
+	consoleCommandTimeout            time.Duration
+	guestCommandTransport            string
+	guestSSHKey                      ed25519.PrivateKey
 }
 
-func New(client vmiSerialConsoleClient, namespace string, cfg config.Config) Executor {
+// New returns an executor. When progress is set, the progress of the run is reported to it every progress interval.
+func New(client executorClient, namespace string, cfg config.Config, progress progressReporter) Executor {
+	var migration *liveMigration
+	if cfg.MigrateVMUnderTest {
+		migration = newLiveMigration(client, client, namespace)
+	}
+
 	return Executor{
 		vmiSerialClient:                  client,
+		guestAgentClient:                 client,
+		vmiGetter:                        client,
+		vmiPortForwarder:                 client,
 		namespace:                        namespace,
-		vmiPassword:                      config.VMIPassword,
-		vmiUnderTestEastNICPCIAddress:    config.VMIEastNICPCIAddress,
-		trafficGenEastMACAddress:         cfg.TrafficGenEastMacAddress.String(),
-		vmiUnderTestWestNICPCIAddress:    config.VMIWestNICPCIAddress,
-		trafficGenWestMACAddress:         cfg.TrafficGenWestMacAddress.String(),
+		vmiPassword:                      cfg.VMIPassword,
+		vmUnderTestMACAddresses:          vmUnderTestMACAddresses(cfg.TestInterfaces()),
+		trafficGenMACAddresses:           trafficGenMACAddresses(cfg.TestInterfaces()),
+		testpmdPorts:                     testpmdPorts(cfg.TestInterfaces()),
+		testpmdForwardMode:               testpmdForwardMode(cfg),
+		testpmdTuning:                    testpmdTuning(cfg),
+		nicDriverMode:                    cfg.NICDriverMode,
 		testDuration:                     cfg.TestDuration,
+		testIterations:                   cfg.TestIterations,
+		perDirectionRuns:                 cfg.PerDirectionRuns,
+		scenarios:                        cfg.Scenarios,
+		burstMaxSize:                     cfg.BurstMaxSize,
+		rateSweepSteps:                   cfg.RateSweepSteps,
+		rateSweepStepDuration:            cfg.RateSweepStepDuration,
+		snapshotInterval:                 cfg.SnapshotInterval,
+		warmupDuration:                   cfg.WarmupDuration,
 		verbosePrintsEnabled:             cfg.Verbose,
+		logConsoleTranscripts:            cfg.LogConsoleTranscripts,
+		verbosity:                        newVerbosityWatcher(client, cfg.ConfigMapNamespace, cfg.ConfigMapName, cfg.Verbose),
+		abort:                            newAbortWatcher(client, cfg.ConfigMapNamespace, cfg.ConfigMapName),
+		events:                           events.New(client, namespace, cfg.PodName, cfg.PodUID),
+		consoleTranscript:                cfg.ConsoleTranscript,
 		trafficGeneratorPacketsPerSecond: cfg.TrafficGenPacketsPerSecond,
+		trafficGenServiceMode:            cfg.TrafficGenServiceMode,
+		trafficGenFlowStats:              cfg.TrafficGenFlowStats,
+		trafficVlanID:                    cfg.TrafficVlanID,
+		trafficGenPortAddresses:          trafficGenPortAddresses(cfg),
+		trexBinaryPath:                   cfg.TrexBinaryPath,
+		testpmdBinaryPath:                cfg.TestpmdBinaryPath,
+		vmUnderTestWorkload:              cfg.VMUnderTestWorkload,
+		l3fwdPorts:                       l3fwdPorts(cfg.TestInterfaces()),
+		artifactsDir:                     cfg.ArtifactsDir,
+		reproScript:                      cfg.ReproScript,
+		cpuIsolationCheck:                cfg.CPUIsolationCheck,
+		measurementIsolation:             cfg.MeasurementIsolation,
+		migration:                        migration,
+		trafficGenerators:                newTrafficGenerators(),
+		trafficGeneratorName:             trafficGeneratorName(cfg.TrexTransport),
+		progress:                         newProgressTracker(progress, cfg.ProgressInterval),
+		trafficGenImage:                  cfg.TrafficGenContainerDiskImage,
+		vmUnderTestImage:                 cfg.VMUnderTestContainerDiskImage,
+		consoleCommandTimeout:            cfg.ConsoleCommandTimeout,
+		guestCommandTransport:            cfg.GuestCommandTransport,
+		guestSSHKey:                      cfg.GuestSSHPrivateKey,
 	}
 }
 
-func (e Executor) Execute(ctx context.Context, vmiUnderTestName, trafficGenVMIName string) (status.Results, error) {
-	log.Printf("Login to VMI under test...")
-	vmiUnderTestConsoleExpecter := console.NewExpecter(e.vmiSerialClient, e.namespace, vmiUnderTestName)
-	if err := vmiUnderTestConsoleExpecter.LoginToCentOSAsRoot(e.vmiPassword); err != nil {
-		return status.Results{}, fmt.Errorf("failed to login to VMI \"%s/%s\": %w", e.namespace, vmiUnderTestName, err)
+func newTrafficGenerators() trafficgen.Registry {
+	return trafficgen.Registry{
+		trex.TrafficGeneratorName:    trex.NewTrafficGenerator,
+		trex.RPCTrafficGeneratorName: trex.NewRPCTrafficGenerator,
 	}
+}
 
-	log.Printf("Login to traffic generator...")
-	trafficGenConsoleExpecter := console.NewExpecter(e.vmiSerialClient, e.namespace, trafficGenVMIName)
-	if err := trafficGenConsoleExpecter.LoginToCentOSAsRoot(e.vmiPassword); err != nil {
-		return status.Results{}, fmt.Errorf("failed to login to VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+// trafficGeneratorName returns the name of the TRex traffic generator reading the stats over the given transport.
+func trafficGeneratorName(trexTransport string) string {
+	if trexTransport == config.TrexTransportRPC {
+		return trex.RPCTrafficGeneratorName
+	}
+	return trex.TrafficGeneratorName
+}
+
+func vmUnderTestMACAddresses(interfaces []config.Interface) []net.HardwareAddr {
+	var macAddresses []net.HardwareAddr
+	for _, iface := range interfaces {
+		macAddresses = append(macAddresses, iface.VMUnderTestMacAddress)
 	}
+	return macAddresses
+}
 
-	if e.verbosePrintsEnabled {
-		vmiUnderTestKernelArgs, _ := vmiUnderTestConsoleExpecter.GetGuestKernelArgs()
-		log.Printf("VMI under test guest kernel Args: %s", vmiUnderTestKernelArgs)
+func trafficGenMACAddresses(interfaces []config.Interface) []net.HardwareAddr {
+	var macAddresses []net.HardwareAddr
+	for _, iface := range interfaces {
+		macAddresses = append(macAddresses, iface.TrafficGenMacAddress)
+	}
+	return macAddresses
+}
 
-		trafficGenKernelArgs, _ := trafficGenConsoleExpecter.GetGuestKernelArgs()
-		log.Printf("traffic generator guest kernel Args: %s", trafficGenKernelArgs)
+// trafficGenPortAddresses returns the IPv4 addresses the traffic generator ports are set with,
+// or nil when the ports are in L2 mode.
+func trafficGenPortAddresses(cfg config.Config) []status.PortAddress {
+	if cfg.IPFamily == config.IPFamilyIPv6 {
+		return nil
 	}
 
-	trexClient := trex.NewClient(
-		trafficGenConsoleExpecter,
-		e.trafficGeneratorPacketsPerSecond,
-		e.testDuration,
-		e.verbosePrintsEnabled,
-	)
+	var addresses []status.PortAddress
+	for portIdx := range cfg.TestInterfaces() {
+		ip, gateway := cfg.TrafficGenPortIPAddresses(portIdx)
+		addresses = append(addresses, status.PortAddress{Port: portIdx, IP: ip.String(), Gateway: gateway.String()})
+	}
+	return addresses
+}
+
+// testpmdPorts maps the test interfaces to testpmd ports, each forwarding the traffic to its traffic generator peer.
+// The ports PCI addresses are set once the guest discovers them.
+func testpmdPorts(interfaces []config.Interface) []testpmd.Port {
+	var ports []testpmd.Port
+	for _, iface := range interfaces {
+		ports = append(ports, testpmd.Port{EthPeerMACAddress: iface.TrafficGenMacAddress.String()})
+	}
+	return ports
+}
+
+// testpmdForwardMode returns how testpmd forwards the traffic back to the traffic generator:
+// routed traffic is sent back through the gateway it was received from, otherwise it is sent directly.
+func testpmdForwardMode(cfg config.Config) testpmd.ForwardMode {
+	if cfg.Routed() {
+		return testpmd.ForwardMode5TupleSwap
+	}
+	return testpmd.ForwardModeMAC
+}
 
-	log.Printf("Starting traffic generator Server Service...")
-	if err := trexClient.StartServer(); err != nil {
-		return status.Results{}, fmt.Errorf("failed to Start to Trex Service on VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+func testpmdTuning(cfg config.Config) testpmd.Tuning {
+	return testpmd.Tuning{
+		MbufSize:       cfg.TestpmdMbufSize,
+		TotalNumMbufs:  cfg.TestpmdTotalNumMbufs,
+		MemoryChannels: cfg.TestpmdMemoryChannels,
+		Cores:          cfg.TestpmdCPUs,
+		InMemory:       cfg.NICDriverMode == config.NICDriverModeMLX5,
 	}
+}
 
-	log.Printf("Waiting until traffic generator Server Service is ready...")
-	if err := trexClient.WaitForServerToBeReady(ctx); err != nil {
-		return status.Results{}, fmt.Errorf("failed to Start to Trex Service on VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+// workloadExecutables returns the executables the workload under test requires in the VM under test.
+func (e Executor) workloadExecutables() []string {
+	if e.vmUnderTestWorkload == config.VMUnderTestWorkloadL3fwd {
+		return []string{config.L3fwdBinaryPath, telemetry.ScriptName}
+	}
+	return []string{e.testpmdBinaryPath}
+}
+
+func (e Executor) newWorkload(vmiUnderTestConsoleExpecter console.Expecter, vmiUnderTestRunner guestCommandRunner) workload {
+	if e.vmUnderTestWorkload == config.VMUnderTestWorkloadL3fwd {
+		return l3fwdWorkload{l3fwd.NewConsole(
+			vmiUnderTestConsoleExpecter,
+			config.L3fwdBinaryPath,
+			e.l3fwdPorts,
+			e.verbosePrintsEnabled,
+		)}
 	}
 
 	testpmdConsole := testpmd.NewTestpmdConsole(
 		vmiUnderTestConsoleExpecter,
-		e.vmiUnderTestEastNICPCIAddress,
-		e.trafficGenEastMACAddress,
-		e.vmiUnderTestWestNICPCIAddress,
-		e.trafficGenWestMACAddress,
+		e.testpmdBinaryPath,
+		e.testpmdPorts,
+		e.testpmdForwardMode,
+		e.testpmdTuning,
+		e.trafficVlanID,
 		e.verbosePrintsEnabled,
 	)
+	if runGuestCommand := e.sideGuestCommandRunner(vmiUnderTestConsoleExpecter, vmiUnderTestRunner); runGuestCommand != nil {
+		testpmdConsole = testpmdConsole.WithTelemetry(runGuestCommand)
+	}
+
+	return testpmdWorkload{testpmdConsole}
+}
 
-	log.Printf("Starting testpmd in VMI...")
-	if err := testpmdConsole.Run(); err != nil {
+// sideGuestCommandRunner returns the runner of the guest commands which does not go through the console, so the commands
+// may run while the console is occupied, e.g. by testpmd. The serial console transport has no such runner.
+func (e Executor) sideGuestCommandRunner(expecter console.Expecter, runner guestCommandRunner) func(command string) (string, error) {
+	switch e.guestCommandTransport {
+	case config.GuestCommandTransportGuestAgent:
+		return runner.GetCommandOutput
+	case config.GuestCommandTransportSSH:
+		return expecter.GetSideCommandOutput
+	}
+	return nil
+}
+
+func (e Executor) Execute(ctx context.Context, vmiUnderTestName, trafficGenVMIName string) (status.Results, error) {
+	transcript := console.NewTranscript()
+	// The raw console output is recorded only on verbose runs, as it may be large.
+	var recording *console.Recording
+	if e.verbosePrintsEnabled && e.artifactsDir != "" {
+		recording = console.NewRecording()
+	}
+
+	vmiUnderTestConsoleExpecter, err := e.newConsoleExpecter(ctx, transcript, recording, vmiUnderTestName)
+	if err != nil {
 		return status.Results{}, err
 	}
+	defer vmiUnderTestConsoleExpecter.Close()
 
-	log.Printf("Clearing testpmd stats in VMI...")
-	if err := testpmdConsole.ClearStats(); err != nil {
+	trafficGenConsoleExpecter, err := e.newConsoleExpecter(ctx, transcript, recording, trafficGenVMIName)
+	if err != nil {
 		return status.Results{}, err
 	}
+	defer trafficGenConsoleExpecter.Close()
 
-	log.Printf("Clearing Trex console stats before test...")
-	if _, err := trexClient.ClearStats(); err != nil {
-		return status.Results{}, fmt.Errorf("failed to clear trex stats on traffic generator VMI \"%s/%s\" side: %w",
-			e.namespace, trafficGenVMIName, err)
+	var timeline status.Timeline
+	var diagnostics diagnosticsCollector
+	results, err := e.execute(ctx, vmiUnderTestConsoleExpecter, trafficGenConsoleExpecter, &timeline, &diagnostics,
+		vmiUnderTestName, trafficGenVMIName)
+	results.Timeline = timeline
+	if err != nil && e.reproScript && e.artifactsDir != "" {
+		e.exportReproScript(transcript, err, vmiUnderTestName, trafficGenVMIName)
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Collecting the guests diagnostics...")
+		results.Diagnostics = diagnostics.collect()
+		if e.artifactsDir != "" {
+			exportArtifact(e.artifactsDir, diagnosticsFileName, results.Diagnostics+"\n")
+		}
+	}
+	if e.artifactsDir != "" {
+		exportTranscript(e.artifactsDir, transcript)
+	}
+	if recording != nil {
+		exportRecording(e.artifactsDir, recording)
+	}
+	if err != nil && exitcode.CancelledPhase(err) == "" {
+		// Failures caused by the run context being done are reported as a cancellation of the current phase.
+		if cancelErr := exitcode.CheckCancelled(ctx, e.progress.phase); cancelErr != nil {
+			log.Printf("%s: %v", cancelErr, err)
+			err = cancelErr
+		}
+	}
+	if err != nil || e.consoleTranscript == config.ConsoleTranscriptAlways {
+		printTranscript(transcript)
 	}
 
-	log.Printf("Running traffic for %s...", e.testDuration.String())
-	if _, err := trexClient.StartTraffic(trex.SourcePort); err != nil {
-		return status.Results{}, fmt.Errorf("failed to run traffic from traffic generator VMI \"%s/%s\" side: %w",
-			e.namespace, trafficGenVMIName, err)
+	return results, err
+}
+
+// newGuestCommandRunner returns the runner of the guest commands over the configured transport.
+func (e Executor) newGuestCommandRunner(expecter console.Expecter, vmiName string) guestCommandRunner {
+	if e.guestCommandTransport == config.GuestCommandTransportGuestAgent {
+		return guestagent.NewRunner(e.guestAgentClient, e.namespace, vmiName, e.consoleCommandTimeout)
+	}
+	return expecter
+}
+
+func (e Executor) loginToVMUnderTest(expecter console.Expecter, runner guestCommandRunner, vmiName string,
+	diagnostics *diagnosticsCollector) error {
+	log.Printf("Login to VMI under test...")
+	if err := expecter.LoginToCentOSAsRoot(e.vmiPassword); err != nil {
+		return fmt.Errorf("failed to login to VMI \"%s/%s\": %w", e.namespace, vmiName, err)
+	}
+	diagnostics.setVMUnderTest(runner)
+
+	if err := checkImageVersion(runner, e.vmUnderTestImage); err != nil {
+		return err
+	}
+
+	for _, executable := range e.workloadExecutables() {
+		if err := runner.ValidateExecutable(executable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e Executor) loginToTrafficGen(expecter console.Expecter, runner guestCommandRunner, vmiName string,
+	diagnostics *diagnosticsCollector) error {
+	log.Printf("Login to traffic generator...")
+	if err := expecter.LoginToCentOSAsRoot(e.vmiPassword); err != nil {
+		return fmt.Errorf("failed to login to VMI \"%s/%s\": %w", e.namespace, vmiName, err)
+	}
+	diagnostics.setTrafficGen(runner)
+
+	if err := checkImageVersion(runner, e.trafficGenImage); err != nil {
+		return err
+	}
+
+	trexBinDirectory := path.Dir(e.trexBinaryPath)
+	for _, trexBinary := range []string{e.trexBinaryPath, path.Join(trexBinDirectory, trexConsoleBinaryName)} {
+		if err := runner.ValidateExecutable(trexBinary); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setupTrafficGenerator sets up the traffic generator, and in service mode resolves its ports default gateways.
+func (e Executor) setupTrafficGenerator(ctx context.Context, trafficGenerator trafficgen.TrafficGenerator,
+	vmiName string) ([]status.GatewayResolution, error) {
+	log.Printf("Setting up the %s traffic generator...", trafficGenerator.Name())
+	if err := trafficGenerator.Setup(ctx); err != nil {
+		e.events.Warning(events.ReasonTrexNotReady, "the %s traffic generator on VMI %q was not ready: %v",
+			trafficGenerator.Name(), e.namespace+"/"+vmiName, err)
+		return nil, fmt.Errorf("failed to set up the %s traffic generator on VMI \"%s/%s\": %w",
+			trafficGenerator.Name(), e.namespace, vmiName, err)
 	}
 
-	var err error
-	trafficGeneratorMaxDropRate, err := e.monitorDropRates(ctx, trexClient)
+	if !e.trafficGenServiceMode {
+		return nil, nil
+	}
+
+	resolver, ok := trafficGenerator.(trafficgen.GatewayResolver)
+	if !ok {
+		return nil, fmt.Errorf("the %s traffic generator does not support service mode", trafficGenerator.Name())
+	}
+
+	log.Printf("Resolving traffic generator default gateways in service mode...")
+	resolutions, err := resolver.ResolveGateways()
 	if err != nil {
-		return status.Results{}, err
+		return nil, fmt.Errorf("failed to resolve default gateways on traffic generator VMI \"%s/%s\": %w",
+			e.namespace, vmiName, err)
+	}
+
+	return toStatusGatewayResolutions(resolutions), nil
+}
+
+// checkImageVersion verifies the guest image was built for a checkup version compatible with the running one.
+// Images built before the version manifest was introduced are not checked.
+func checkImageVersion(runner guestCommandRunner, image string) error {
+	imageVersion, err := runner.GetImageVersion(version.ImageManifestPath)
+	if err != nil {
+		return err
+	}
+
+	if imageVersion == "" {
+		log.Printf("image %s has no version manifest, skipping its compatibility check", image)
+		return nil
+	}
+
+	log.Printf("image %s was built for checkup v%s", image, imageVersion)
+	if err := version.CheckImage(image, imageVersion); err != nil {
+		return exitcode.Classify(exitcode.ConfigError, err)
+	}
+
+	return nil
+}
+
+// newConsoleExpecter returns the expecter of the VMI console.
+// With the SSH transport, the expecter reaches the guest shell over SSH rather than over the serial console.
+func (e Executor) newConsoleExpecter(ctx context.Context, transcript *console.Transcript, recording *console.Recording,
+	vmiName string) (console.Expecter, error) {
+	expecter := console.NewExpecter(e.vmiSerialClient, e.namespace, vmiName).
+		WithTranscript(transcript).
+		WithRecording(recording).
+		WithCommandTimeout(e.consoleCommandTimeout).
+		WithResponseLogs(e.logConsoleTranscripts)
+	if e.guestCommandTransport != config.GuestCommandTransportSSH {
+		return expecter, nil
+	}
+
+	address, err := e.guestSSHAddress(ctx, vmiName)
+	if err != nil {
+		return console.Expecter{}, err
 	}
-	log.Printf("traffic Generator Max Drop Rate: %fBps", trafficGeneratorMaxDropRate)
 
-	return calculateStats(trexClient, testpmdConsole)
+	signer, err := ssh.NewSignerFromKey(e.guestSSHKey)
+	if err != nil {
+		return console.Expecter{}, fmt.Errorf("failed to load the guests SSH key: %w", err)
+	}
+
+	return expecter.WithSSH(address, signer), nil
 }
 
-func calculateStats(trexClient trex.Client, testpmdConsole *testpmd.TestpmdConsole) (status.Results, error) {
-	var err error
-	results := status.Results{}
-	var trafficGeneratorSrcPortStats trex.PortStats
-	trafficGeneratorSrcPortStats, err = trexClient.GetPortStats(trex.SourcePort)
+// guestSSHAddress returns the address of the VMI SSH server, on the VMI pod network IP.
+func (e Executor) guestSSHAddress(ctx context.Context, vmiName string) (string, error) {
+	const sshPort = "22"
+
+	vmi, err := e.vmiGetter.GetVirtualMachineInstance(ctx, e.namespace, vmiName)
 	if err != nil {
+		return "", err
+	}
+
+	for _, iface := range vmi.Status.Interfaces {
+		if iface.Name == config.GuestPodNetworkName && iface.IP != "" {
+			return net.JoinHostPort(iface.IP, sshPort), nil
+		}
+	}
+
+	return "", fmt.Errorf("VMI %q has no IP address on the %q network", vmiName, config.GuestPodNetworkName)
+}
+
+// printTranscript prints the commands executed on the VMI consoles, to be collected with the checkup logs.
+func printTranscript(transcript *console.Transcript) {
+	entries := transcript.Entries()
+	log.Printf("Console transcript (%d commands):", len(entries))
+	for _, entry := range entries {
+		log.Printf("  %s", entry)
+	}
+}
+
+func (e Executor) execute(ctx context.Context, vmiUnderTestConsoleExpecter, trafficGenConsoleExpecter console.Expecter,
+	timeline *status.Timeline, diagnostics *diagnosticsCollector, vmiUnderTestName, trafficGenVMIName string) (status.Results, error) {
+	if e.migration != nil {
+		e.migration.vmiName = vmiUnderTestName
+	}
+
+	e.progress.setPhase(loginPhase)
+	loginStart := time.Now()
+	vmiUnderTestRunner := e.newGuestCommandRunner(vmiUnderTestConsoleExpecter, vmiUnderTestName)
+	trafficGenRunner := e.newGuestCommandRunner(trafficGenConsoleExpecter, trafficGenVMIName)
+	trexBinDirectory := path.Dir(e.trexBinaryPath)
+	if err := runGuestTasks(ctx, guestLoginTimeout,
+		guestTask{guestName: vmUnderTestGuestName, run: func(context.Context) error {
+			return e.loginToVMUnderTest(vmiUnderTestConsoleExpecter, vmiUnderTestRunner, vmiUnderTestName, diagnostics)
+		}},
+		guestTask{guestName: trafficGenGuestName, run: func(context.Context) error {
+			return e.loginToTrafficGen(trafficGenConsoleExpecter, trafficGenRunner, trafficGenVMIName, diagnostics)
+		}},
+	); err != nil {
 		return status.Results{}, err
 	}
+	timeline.Record(loginPhase, loginStart)
+
+	if e.verbosePrintsEnabled {
+		vmiUnderTestKernelArgs, _ := vmiUnderTestRunner.GetGuestKernelArgs()
+		log.Printf("VMI under test guest kernel Args: %s", vmiUnderTestKernelArgs)
 
-	var trafficGeneratorDstPortStats trex.PortStats
-	trafficGeneratorDstPortStats, err = trexClient.GetPortStats(trex.DestPort)
+		trafficGenKernelArgs, _ := trafficGenRunner.GetGuestKernelArgs()
+		log.Printf("traffic generator guest kernel Args: %s", trafficGenKernelArgs)
+	}
+
+	e, err := e.withGuestPCIAddresses(vmiUnderTestRunner, trafficGenRunner, vmiUnderTestName, trafficGenVMIName)
 	if err != nil {
 		return status.Results{}, err
 	}
 
-	results.TrafficGenOutputErrorPackets = trafficGeneratorSrcPortStats.Result.Oerrors
-	log.Printf("traffic Generator port %d Packet output errors: %d", trex.SourcePort, results.TrafficGenOutputErrorPackets)
-	results.TrafficGenInputErrorPackets = trafficGeneratorDstPortStats.Result.Ierrors
-	log.Printf("traffic Generator port %d Packet output errors: %d", trex.DestPort, results.TrafficGenInputErrorPackets)
-	results.TrafficGenSentPackets = trafficGeneratorSrcPortStats.Result.Opackets
-	log.Printf("traffic Generator packet sent via port %d: %d", trex.SourcePort, results.TrafficGenSentPackets)
+	environment := detectGuestsEnvironment(vmiUnderTestRunner, trafficGenRunner)
 
-	log.Printf("get testpmd stats in VM-Under-Test...")
-	var testPmdStats [testpmd.StatsArraySize]testpmd.PortStats
-	if testPmdStats, err = testpmdConsole.GetStats(); err != nil {
+	var cpuIsolation string
+	if e.cpuIsolationCheck != config.CPUIsolationCheckSkip {
+		var err error
+		if cpuIsolation, err = e.checkCPUIsolation(vmiUnderTestRunner, trafficGenRunner); err != nil {
+			return status.Results{Environment: environment, CPUIsolation: cpuIsolation}, err
+		}
+	}
+
+	vfDrivers, vfDriverHints, err := e.checkVFs(vmiUnderTestRunner, e.vmUnderTestPCIAddresses, vmUnderTestGuestName,
+		vmiUnderTestName)
+	if err != nil {
+		return status.Results{Environment: environment, CPUIsolation: cpuIsolation}, err
+	}
+
+	trafficGenVFDrivers, trafficGenVFDriverHints, err := e.checkVFs(trafficGenRunner, e.trafficGenPCIAddresses,
+		trafficGenGuestName, trafficGenVMIName)
+	if err != nil {
+		return status.Results{Environment: environment, CPUIsolation: cpuIsolation, VFDrivers: vfDrivers,
+			VFDriverHints: vfDriverHints}, err
+	}
+	vfDrivers = append(vfDrivers, trafficGenVFDrivers...)
+	vfDriverHints = append(vfDriverHints, trafficGenVFDriverHints...)
+
+	numaAlignment := e.checkNUMAAlignment(ctx, vmiUnderTestRunner, trafficGenRunner,
+		vmiUnderTestName, trafficGenVMIName)
+
+	trafficGenerator, err := e.trafficGenerators.New(e.trafficGeneratorName, trafficgen.Params{
+		ConsoleExpecter:      trafficGenConsoleExpecter,
+		BinDirectory:         trexBinDirectory,
+		PacketsPerSecond:     e.trafficGeneratorPacketsPerSecond,
+		VerbosePrintsEnabled: e.verbosePrintsEnabled,
+		DialServer: func(port int) (net.Conn, error) {
+			stream, err := e.vmiPortForwarder.VMIPortForward(e.namespace, trafficGenVMIName, port)
+			if err != nil {
+				return nil, err
+			}
+			return stream.AsConn(), nil
+		},
+	})
+	if err != nil {
 		return status.Results{}, err
 	}
-	results.VMUnderTestRxDroppedPackets = testPmdStats[testpmd.StatsSummary].RXDropped
-	results.VMUnderTestTxDroppedPackets = testPmdStats[testpmd.StatsSummary].TXDropped
+	if closer, ok := trafficGenerator.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	vmUnderTestWorkload := e.newWorkload(vmiUnderTestConsoleExpecter, vmiUnderTestRunner)
+
+	// The traffic generator is set up while the workload starts, the traffic is sent only once both are ready.
+	e.progress.setPhase(trafficGenSetupPhase + " and " + vmUnderTestWorkload.Name() + " start")
+	trafficGenSetupStart := time.Now()
+	var trafficGenSetupEnd time.Time
+	var gatewayResolutions []status.GatewayResolution
+	if err := runGuestTasks(ctx, guestServiceStartTimeout,
+		guestTask{guestName: vmUnderTestGuestName, run: func(context.Context) error {
+			log.Printf("Starting %s in VMI...", vmUnderTestWorkload.Name())
+			if err := vmUnderTestWorkload.Run(); err != nil {
+				return err
+			}
+			diagnostics.setWorkload(vmUnderTestWorkload)
+			return nil
+		}},
+		guestTask{guestName: trafficGenGuestName, run: func(taskCtx context.Context) error {
+			var err error
+			gatewayResolutions, err = e.setupTrafficGenerator(taskCtx, trafficGenerator, trafficGenVMIName)
+			trafficGenSetupEnd = time.Now()
+			return err
+		}},
+	); err != nil {
+		return status.Results{}, err
+	}
+	*timeline = append(*timeline, status.PhaseTiming{Phase: trafficGenSetupPhase, Start: trafficGenSetupStart, End: trafficGenSetupEnd})
+
+	if e.warmupDuration > 0 {
+		warmupStart := time.Now()
+		if err := e.runWarmupTraffic(ctx, trafficGenerator, trafficGenVMIName); err != nil {
+			return status.Results{}, err
+		}
+		timeline.Record(warmupPhase, warmupStart)
+	}
+
+	results, err := e.runScenarios(ctx, scenarioEnv{
+		trafficGenerator:    trafficGenerator,
+		vmUnderTestWorkload: vmUnderTestWorkload,
+		trafficGenVMIName:   trafficGenVMIName,
+		timeline:            timeline,
+	})
+	if err != nil {
+		return status.Results{}, err
+	}
+	e.events.Normal(events.ReasonTrafficCompleted, "the traffic between VMI %q and VMI %q was completed",
+		e.namespace+"/"+trafficGenVMIName, e.namespace+"/"+vmiUnderTestName)
+
+	results.AbortRequested = e.abort.requested
+	results.VMUnderTestConsoleReconnects = vmiUnderTestConsoleExpecter.Reconnects()
+	results.TrafficGenConsoleReconnects = trafficGenConsoleExpecter.Reconnects()
+	results.TrafficGenGatewayResolution = gatewayResolutions
+	results.TrafficGenPortAddresses = e.trafficGenPortAddresses
+	results.Environment = environment
+	results.CPUIsolation = cpuIsolation
+	results.NumaAlignment = numaAlignment
+	results.VFDrivers = vfDrivers
+	results.VFDriverHints = vfDriverHints
+	if e.migration != nil {
+		results.Migration = e.migration.result
+	}
+	if vmUnderTestWorkload.Name() == config.VMUnderTestWorkloadTestpmd {
+		results.VMUnderTestTestpmdCommand = vmUnderTestWorkload.Command()
+	}
+
+	return results, nil
+}
+
+func toStatusGatewayResolutions(resolutions []trafficgen.GatewayResolution) []status.GatewayResolution {
+	var statusResolutions []status.GatewayResolution
+	for _, resolution := range resolutions {
+		log.Printf("traffic Generator port %d gateway %s is at %s", resolution.Port, resolution.IP, resolution.MAC)
+		statusResolutions = append(statusResolutions, status.GatewayResolution{
+			Port: int(resolution.Port),
+			IP:   resolution.IP,
+			MAC:  resolution.MAC,
+		})
+	}
+	return statusResolutions
+}
+
+// runWarmupTraffic runs the traffic for the warm-up duration, so flow setup and cache warm-up effects
+// do not pollute the measured results. Its stats are discarded when the measured traffic starts.
+func (e Executor) runWarmupTraffic(ctx context.Context, trafficGenerator trafficgen.TrafficGenerator, trafficGenVMIName string) error {
+	log.Printf("Running warm-up traffic for %s...", e.warmupDuration.String())
+	e.progress.setPhase(warmupPhase)
+	if err := trafficGenerator.Start(e.warmupDuration, trafficgen.SourcePorts(len(e.testpmdPorts))...); err != nil {
+		return fmt.Errorf("failed to run warm-up traffic from traffic generator VMI \"%s/%s\" side: %w",
+			e.namespace, trafficGenVMIName, err)
+	}
+
+	// Allow the traffic generator to stop the warm-up traffic before the measured traffic is started.
+	const warmupGracePeriod = 5 * time.Second
+	timer := time.NewTimer(e.warmupDuration + warmupGracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		log.Printf("warm-up traffic has finished")
+		return nil
+	case <-ctx.Done():
+		e.stopTraffic(trafficGenerator, trafficgen.SourcePorts(len(e.testpmdPorts)))
+		return exitcode.CheckCancelled(ctx, e.progress.phase)
+	}
+}
+
+// stopTraffic stops the traffic of a cancelled run, so it does not keep flooding the network until its duration ends.
+// Stopping is best-effort, as the VMIs are about to be torn down.
+func (e Executor) stopTraffic(trafficGenerator trafficgen.TrafficGenerator, ports []trafficgen.PortIdx) {
+	log.Printf("Stopping the %s traffic...", trafficGenerator.Name())
+	if err := trafficGenerator.Stop(ports...); err != nil {
+		log.Printf("failed to stop the %s traffic: %v", trafficGenerator.Name(), err)
+	}
+}
+
+// runPerDirectionTraffic runs the traffic in each direction separately, reporting each direction as a scenario,
+// so a fault affecting a single direction can be told apart. The counters of both directions are summed.
+func (e Executor) runPerDirectionTraffic(ctx context.Context,
+	trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	trafficGenVMIName string,
+	rates *throughputSampler,
+	timeline *status.Timeline) (status.Results, []iterationStats, error) {
+	var directionsCounters []status.PacketCounters
+	var scenarios []status.ScenarioResults
+	var iterations []iterationStats
+	for _, direction := range []trafficDirection{eastToWest, westToEast} {
+		log.Printf("Running the %s traffic...", direction.scenarioID)
+		directionIterations, err := e.runTrafficIterations(ctx, direction, trafficGenerator, vmUnderTestWorkload,
+			trafficGenVMIName, rates, timeline)
+		if err != nil {
+			return status.Results{}, nil, err
+		}
+
+		counters := sumCounters(iterationsCounters(directionIterations))
+		directionsCounters = append(directionsCounters, counters)
+		scenarios = append(scenarios, status.ScenarioResults{ID: direction.scenarioID, Counters: counters})
+		iterations = append(iterations, directionIterations...)
+		if rates.aborted() || e.abort.requested {
+			break
+		}
+	}
+
+	results := countersResults(sumCounters(directionsCounters))
+	results.Scenarios = scenarios
+	return results, iterations, nil
+}
+
+// runTrafficIterations runs the traffic in the given direction for the configured number of iterations.
+func (e Executor) runTrafficIterations(ctx context.Context,
+	direction trafficDirection,
+	trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	trafficGenVMIName string,
+	rates *throughputSampler,
+	timeline *status.Timeline) ([]iterationStats, error) {
+	phasePrefix := ""
+	if e.perDirectionRuns {
+		phasePrefix = direction.scenarioID + " "
+	}
+
+	var iterations []iterationStats
+	for iteration := 1; iteration <= e.testIterations; iteration++ {
+		if e.testIterations > 1 {
+			log.Printf("Starting %straffic iteration %d/%d...", phasePrefix, iteration, e.testIterations)
+		}
+		e.progress.setPhase(fmt.Sprintf("%straffic iteration %d/%d", phasePrefix, iteration, e.testIterations))
+
+		iteration, err := e.runTrafficIteration(ctx, direction, trafficGenerator, vmUnderTestWorkload,
+			trafficGenVMIName, rates, timeline)
+		if err != nil {
+			return nil, err
+		}
+		iterations = append(iterations, iteration)
+		if rates.aborted() || e.abort.requested {
+			break
+		}
+	}
+
+	return iterations, nil
+}
+
+// runTrafficIteration clears the stats on both sides, runs the traffic for the test duration and collects the counters.
+func (e Executor) runTrafficIteration(ctx context.Context,
+	direction trafficDirection,
+	trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	trafficGenVMIName string,
+	rates *throughputSampler,
+	timeline *status.Timeline) (iterationStats, error) {
+	log.Printf("Clearing %s stats in VMI...", vmUnderTestWorkload.Name())
+	if err := clearWorkloadStats(vmUnderTestWorkload); err != nil {
+		return iterationStats{}, err
+	}
+
+	log.Printf("Clearing %s stats before test...", trafficGenerator.Name())
+	if err := clearTrafficGenStats(trafficGenerator); err != nil {
+		return iterationStats{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+	}
+
+	log.Printf("Running traffic for %s...", e.testDuration.String())
+	trafficStart := time.Now()
+	sourcePorts := direction.sourcePorts(len(e.testpmdPorts))
+	if err := trafficGenerator.Start(e.testDuration, sourcePorts...); err != nil {
+		return iterationStats{}, fmt.Errorf("failed to run traffic from traffic generator VMI \"%s/%s\" side: %w", e.namespace, trafficGenVMIName, err)
+	}
+
+	if err := e.awaitTraffic(ctx, direction, trafficGenerator, vmUnderTestWorkload, rates); err != nil {
+		if ctx.Err() != nil {
+			e.stopTraffic(trafficGenerator, sourcePorts)
+		}
+		return iterationStats{}, err
+	}
+	timeline.Record(e.progress.phase, trafficStart)
+
+	// The traffic is stopped explicitly rather than relying on its duration alone, and the stats are collected only once
+	// the trailing packets have been received, so they do not skew the counters of both sides.
+	log.Printf("Stopping the traffic and waiting for it to complete...")
+	if err := trafficGenerator.Stop(sourcePorts...); err != nil {
+		return iterationStats{}, fmt.Errorf("failed to stop traffic from traffic generator VMI \"%s/%s\" side: %w", e.namespace, trafficGenVMIName, err)
+	}
+	allPorts := slices.Concat(sourcePorts, direction.destPorts(len(e.testpmdPorts)))
+	if err := trafficGenerator.WaitForCompletion(ctx, allPorts...); err != nil {
+		return iterationStats{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+	}
+
+	iteration, err := calculateStats(trafficGenerator, vmUnderTestWorkload, direction, len(e.testpmdPorts))
+	if err != nil || !e.trafficGenFlowStats {
+		return iteration, err
+	}
+	iteration.streams = streamStats(trafficGenerator)
+
+	return iteration, nil
+}
+
+// streamStats reads the counters of each stream the traffic generator sent.
+// They only break the port counters down, so failing to read them does not fail the iteration.
+func streamStats(trafficGenerator trafficgen.TrafficGenerator) []status.StreamStats {
+	reader, ok := trafficGenerator.(trafficgen.StreamStatsReader)
+	if !ok {
+		log.Printf("the %s traffic generator does not support stream stats", trafficGenerator.Name())
+		return nil
+	}
+
+	streams, err := reader.StreamStats()
+	if err != nil {
+		log.Printf("failed to read the %s stream stats: %v", trafficGenerator.Name(), err)
+		return nil
+	}
+
+	var stats []status.StreamStats
+	for _, stream := range streams {
+		stats = append(stats, status.StreamStats{
+			ID:        stream.ID,
+			TXPort:    int(stream.TXPort),
+			TXPackets: stream.TXPackets,
+			RXPackets: stream.RXPackets,
+		})
+	}
+	return stats
+}
+
+// aggregateIterations sums the counters of all the iterations.
+// When there is more than a single iteration, the per-iteration counters and their spread are kept as well.
+func aggregateIterations(iterations []status.PacketCounters) status.Results {
+	results := countersResults(sumCounters(iterations))
+	if len(iterations) < 2 {
+		return results
+	}
+
+	results.Iterations = iterations
+	results.IterationsSummary = &status.IterationsSummary{
+		TrafficGenSentPackets: summarizeCounter(iterations, func(i status.PacketCounters) int64 {
+			return i.TrafficGenSentPackets
+		}),
+		VMUnderTestReceivedPackets: summarizeCounter(iterations, func(i status.PacketCounters) int64 {
+			return i.VMUnderTestReceivedPackets
+		}),
+		VMUnderTestDroppedPackets: summarizeCounter(iterations, func(i status.PacketCounters) int64 {
+			return i.VMUnderTestRxDroppedPackets + i.VMUnderTestTxDroppedPackets
+		}),
+	}
+
+	log.Printf("Sent packets per iteration: min %d max %d avg %.0f",
+		results.IterationsSummary.TrafficGenSentPackets.Min,
+		results.IterationsSummary.TrafficGenSentPackets.Max,
+		results.IterationsSummary.TrafficGenSentPackets.Avg)
+
+	return results
+}
+
+func sumCounters(counters []status.PacketCounters) status.PacketCounters {
+	var sum status.PacketCounters
+	for _, c := range counters {
+		sum.TrafficGenSentPackets += c.TrafficGenSentPackets
+		sum.TrafficGenOutputErrorPackets += c.TrafficGenOutputErrorPackets
+		sum.TrafficGenInputErrorPackets += c.TrafficGenInputErrorPackets
+		sum.VMUnderTestReceivedPackets += c.VMUnderTestReceivedPackets
+		sum.VMUnderTestRxDroppedPackets += c.VMUnderTestRxDroppedPackets
+		sum.VMUnderTestTxDroppedPackets += c.VMUnderTestTxDroppedPackets
+	}
+	return sum
+}
+
+func countersResults(counters status.PacketCounters) status.Results {
+	return status.Results{
+		TrafficGenSentPackets:        counters.TrafficGenSentPackets,
+		TrafficGenOutputErrorPackets: counters.TrafficGenOutputErrorPackets,
+		TrafficGenInputErrorPackets:  counters.TrafficGenInputErrorPackets,
+		VMUnderTestReceivedPackets:   counters.VMUnderTestReceivedPackets,
+		VMUnderTestRxDroppedPackets:  counters.VMUnderTestRxDroppedPackets,
+		VMUnderTestTxDroppedPackets:  counters.VMUnderTestTxDroppedPackets,
+	}
+}
+
+func summarizeCounter(iterations []status.PacketCounters, counter func(status.PacketCounters) int64) status.CounterSummary {
+	summary := status.CounterSummary{Min: math.MaxInt64, Max: math.MinInt64}
+	var sum int64
+	for _, iteration := range iterations {
+		value := counter(iteration)
+		sum += value
+		if value < summary.Min {
+			summary.Min = value
+		}
+		if value > summary.Max {
+			summary.Max = value
+		}
+	}
+	summary.Avg = float64(sum) / float64(len(iterations))
+
+	return summary
+}
+
+// statsReadSkewThreshold is the time between the reads of both sides stats, above which comparing them is not trustworthy.
+const statsReadSkewThreshold = 5 * time.Second
+
+type workloadStatsRead struct {
+	stats     testpmd.Stats
+	timestamp time.Time
+	err       error
+}
+
+// iterationStats holds the stats collected at the end of a single traffic iteration.
+type iterationStats struct {
+	counters status.PacketCounters
+	queues   []status.QueueStats
+	streams  []status.StreamStats
+	read     status.StatsRead
+}
+
+func iterationsCounters(iterations []iterationStats) []status.PacketCounters {
+	var counters []status.PacketCounters
+	for _, iteration := range iterations {
+		counters = append(counters, iteration.counters)
+	}
+	return counters
+}
+
+// sumQueueStats sums the per-queue counters of all the iterations, by forwarding stream.
+func sumQueueStats(iterations []iterationStats) []status.QueueStats {
+	var queues []status.QueueStats
+	queueIdx := map[status.QueueStats]int{}
+	for _, iteration := range iterations {
+		for _, queue := range iteration.queues {
+			stream := status.QueueStats{RXPort: queue.RXPort, RXQueue: queue.RXQueue, TXPort: queue.TXPort, TXQueue: queue.TXQueue}
+			idx, exists := queueIdx[stream]
+			if !exists {
+				idx = len(queues)
+				queueIdx[stream] = idx
+				queues = append(queues, stream)
+			}
+			queues[idx].RXPackets += queue.RXPackets
+			queues[idx].TXPackets += queue.TXPackets
+			queues[idx].TXDropped += queue.TXDropped
+		}
+	}
+	return queues
+}
+
+// sumStreamStats sums the counters of each traffic generator stream over all the iterations.
+func sumStreamStats(iterations []iterationStats) []status.StreamStats {
+	var streams []status.StreamStats
+	streamIdx := map[int]int{}
+	for _, iteration := range iterations {
+		for _, stream := range iteration.streams {
+			idx, exists := streamIdx[stream.ID]
+			if !exists {
+				idx = len(streams)
+				streamIdx[stream.ID] = idx
+				streams = append(streams, status.StreamStats{ID: stream.ID, TXPort: stream.TXPort})
+			}
+			streams[idx].TXPackets += stream.TXPackets
+			streams[idx].RXPackets += stream.RXPackets
+		}
+	}
+	return streams
+}
+
+// calculateStats sums the counters over all the pairs of ports.
+// Both sides are read in parallel, so their counters are snapshotted as close together as possible.
+func calculateStats(trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	direction trafficDirection,
+	portsCount int) (iterationStats, error) {
+	log.Printf("get %s stats in VM-Under-Test...", vmUnderTestWorkload.Name())
+	workloadStatsCh := make(chan workloadStatsRead, 1)
+	go func() {
+		stats, err := vmUnderTestWorkload.GetStats()
+		workloadStatsCh <- workloadStatsRead{stats: stats, timestamp: time.Now(), err: err}
+	}()
+
+	results, err := trafficGenCounters(trafficGenerator, direction, portsCount)
+	statsRead := status.StatsRead{TrafficGen: time.Now()}
+	workloadRead := <-workloadStatsCh
+	if err != nil {
+		return iterationStats{}, err
+	}
+	if workloadRead.err != nil {
+		return iterationStats{}, workloadRead.err
+	}
+	statsRead.VMUnderTest = workloadRead.timestamp
+	log.Printf("traffic generator and VM-Under-Test stats were read %s apart", statsRead.Skew().Round(time.Millisecond))
+
+	testPmdStats := workloadRead.stats
+	results.VMUnderTestRxDroppedPackets = testPmdStats.Summary.RXDropped
+	results.VMUnderTestTxDroppedPackets = testPmdStats.Summary.TXDropped
 	log.Printf("VMI-Under-Test's side packets Dropped: Rx: %d; TX: %d",
 		results.VMUnderTestRxDroppedPackets, results.VMUnderTestTxDroppedPackets)
-	results.VMUnderTestReceivedPackets =
-		testPmdStats[testpmd.StatsSummary].RXTotal - testPmdStats[testpmd.StatsPort0].TXPackets - testPmdStats[testpmd.StatsPort1].RXPackets
+	results.VMUnderTestReceivedPackets = testPmdStats.Summary.RXTotal
+	for portIdx := 0; portIdx+1 < len(testPmdStats.Ports); portIdx += 2 {
+		rxPortIdx, txPortIdx := direction.portsPair(portIdx)
+		results.VMUnderTestReceivedPackets -= testPmdStats.Ports[rxPortIdx].TXPackets + testPmdStats.Ports[txPortIdx].RXPackets
+	}
 	log.Printf("VMI-Under-Test's side test packets received (including dropped, excluding non-related packets): %d",
 		results.VMUnderTestReceivedPackets)
 
+	var queues []status.QueueStats
+	for _, queue := range testPmdStats.Queues {
+		queues = append(queues, status.QueueStats(queue))
+	}
+
+	return iterationStats{counters: results, queues: queues, read: statsRead}, nil
+}
+
+// trafficGenCounters sums the traffic generator counters over all the pairs of ports.
+func trafficGenCounters(trafficGenerator trafficgen.TrafficGenerator,
+	direction trafficDirection,
+	portsCount int) (status.PacketCounters, error) {
+	results := status.PacketCounters{}
+	for _, srcPort := range direction.sourcePorts(portsCount) {
+		trafficGeneratorSrcPortStats, err := trafficGenerator.PortStats(srcPort)
+		if err != nil {
+			return status.PacketCounters{}, err
+		}
+
+		results.TrafficGenOutputErrorPackets += trafficGeneratorSrcPortStats.OutputErrors
+		log.Printf("traffic Generator port %d Packet output errors: %d", srcPort, trafficGeneratorSrcPortStats.OutputErrors)
+		results.TrafficGenSentPackets += trafficGeneratorSrcPortStats.OutputPackets
+		log.Printf("traffic Generator packet sent via port %d: %d", srcPort, trafficGeneratorSrcPortStats.OutputPackets)
+	}
+
+	for _, dstPort := range direction.destPorts(portsCount) {
+		trafficGeneratorDstPortStats, err := trafficGenerator.PortStats(dstPort)
+		if err != nil {
+			return status.PacketCounters{}, err
+		}
+
+		results.TrafficGenInputErrorPackets += trafficGeneratorDstPortStats.InputErrors
+		log.Printf("traffic Generator port %d Packet input errors: %d", dstPort, trafficGeneratorDstPortStats.InputErrors)
+	}
+
 	return results, nil
 }
 
-func (e Executor) monitorDropRates(ctx context.Context, trexClient trex.Client) (float64, error) {
-	const interval = 10 * time.Second
+// statsSkewWarning warns about the iterations whose stats were read too far apart to be trustworthy.
+func statsSkewWarning(statsReads []status.StatsRead) string {
+	var skewedReads []string
+	for i, statsRead := range statsReads {
+		if skew := statsRead.Skew(); skew > statsReadSkewThreshold {
+			skewedReads = append(skewedReads, fmt.Sprintf("iteration %d: %s", i+1, skew.Round(time.Millisecond)))
+		}
+	}
+	if len(skewedReads) == 0 {
+		return ""
+	}
+
+	warning := fmt.Sprintf("the traffic generator and VM under test stats were read more than %s apart (%s), "+
+		"the sent and received packets comparison may be skewed", statsReadSkewThreshold, strings.Join(skewedReads, ", "))
+	log.Printf("Warning: %s", warning)
+	return warning
+}
+
+func (e Executor) monitorDropRates(ctx context.Context,
+	direction trafficDirection,
+	trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	rates *throughputSampler) error {
+	const (
+		interval          = 10 * time.Second
+		keepAliveInterval = time.Minute
+	)
 
 	log.Printf("Monitoring traffic generator side drop rates every %s during the test duration...", interval)
-	maxDropRateBps := float64(0)
+	monitorStart := time.Now()
+	lastKeepAlive := monitorStart
+	readCounters := readMigrationCounters(trafficGenerator, direction, len(e.testpmdPorts))
+	var migrationErr error
+	var snapshots *soakMonitor
+	if e.snapshotInterval > 0 {
+		snapshots = newSoakMonitor(e.snapshotInterval, e.progress.phase, readCounters, vmUnderTestWorkload, &rates.soak)
+	}
 
 	ctxWithNewDeadline, cancel := context.WithTimeout(ctx, e.testDuration)
 	defer cancel()
 
+	// The immediate poll samples the rates as the traffic starts, while they are still ramping up
+	rampUp := true
 	conditionFn := func(ctx context.Context) (bool, error) {
-		statsGlobal, err := trexClient.GetGlobalStats()
-		if statsGlobal.Result.MRxDropBps > maxDropRateBps {
-			maxDropRateBps = statsGlobal.Result.MRxDropBps
+		statsGlobal, err := trafficGenerator.GlobalStats()
+		if err != nil {
+			return false, err
+		}
+		rates.add(statsGlobal, rampUp)
+		rampUp = false
+		e.progress.update(statsGlobal)
+
+		if e.migration != nil {
+			if migrationErr = e.migration.step(ctx, time.Since(monitorStart), readCounters); migrationErr != nil {
+				return false, migrationErr
+			}
 		}
-		return false, err
+
+		// The VMI under test console is idle while the traffic runs, poke it so it is not dropped.
+		if time.Since(lastKeepAlive) >= keepAliveInterval {
+			if err := vmUnderTestWorkload.KeepAlive(); err != nil {
+				log.Printf("%s console keep-alive failed: %v", vmUnderTestWorkload.Name(), err)
+			}
+			lastKeepAlive = time.Now()
+		}
+		if e.verbosity.refresh(ctx) {
+			log.Printf("traffic Generator global stats: TX %.0fpps/%.0fbps; RX %.0fpps/%.0fbps; RX drop %.0fbps",
+				statsGlobal.TxPps, statsGlobal.TxBps,
+				statsGlobal.RxPps, statsGlobal.RxBps,
+				statsGlobal.RxDropBps)
+		}
+
+		// Once aborted, or once the snapshots show the traffic cannot pass, it is ended early, keeping the counters so far
+		if e.abort.refresh(ctx) {
+			return true, nil
+		}
+		if snapshots != nil && snapshots.due() && snapshots.take() {
+			return true, nil
+		}
+		return false, nil
 	}
 
 	if err := wait.PollImmediateUntilWithContext(ctxWithNewDeadline, interval, conditionFn); err != nil {
+		// The polling also stops with a timeout when the run context is done, which should not pass as the test end.
+		if cancelErr := exitcode.CheckCancelled(ctx, e.progress.phase); cancelErr != nil {
+			return cancelErr
+		}
+		if migrationErr != nil {
+			return migrationErr
+		}
 		if !errors.Is(err, wait.ErrWaitTimeout) {
-			return 0, fmt.Errorf("failed to poll global stats in trex-console: %w", err)
+			return fmt.Errorf("failed to poll global stats in trex-console: %w", err)
 		}
 		log.Printf("finished polling for drop rates")
 	}
 
-	return maxDropRateBps, nil
+	return nil
+}
+
+// awaitTraffic waits for the test duration, monitoring the drop rates unless the measurement is strictly isolated.
+// When the VM under test is migrated, the migration is expected to complete within the test duration.
+func (e Executor) awaitTraffic(ctx context.Context,
+	direction trafficDirection,
+	trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	rates *throughputSampler) error {
+	if e.measurementIsolation == config.MeasurementIsolationStrict {
+		return e.awaitTrafficDuration(ctx)
+	}
+
+	if err := e.monitorDropRates(ctx, direction, trafficGenerator, vmUnderTestWorkload, rates); err != nil {
+		return err
+	}
+
+	if e.migration != nil {
+		return e.migration.finish()
+	}
+	return nil
+}
+
+// awaitTrafficDuration waits for the test duration without reading any stats nor touching the consoles, so the
+// measurement is not perturbed by the checkup itself. No drop rates nor throughput samples are collected.
+// Only the checkup ConfigMap is re-read, so the run can still be aborted.
+func (e Executor) awaitTrafficDuration(ctx context.Context) error {
+	const abortPollInterval = 10 * time.Second
+
+	log.Printf("Measurement isolation is %s, not polling the stats during the test duration...", config.MeasurementIsolationStrict)
+	timer := time.NewTimer(e.testDuration)
+	defer timer.Stop()
+	ticker := time.NewTicker(abortPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			if e.abort.refresh(ctx) {
+				return nil
+			}
+		case <-ctx.Done():
+			return exitcode.CheckCancelled(ctx, e.progress.phase)
+		}
+	}
+}
+
+// throughputSampler accumulates the traffic generator's global TX/RX rates,
+// as sampled periodically during the test, into average and peak values.
+type throughputSampler struct {
+	samplesCount int
+	txPpsSum     float64
+	rxPpsSum     float64
+	txBpsSum     float64
+	rxBpsSum     float64
+	maxTxPps     float64
+	maxRxPps     float64
+	maxTxBps     float64
+	maxRxBps     float64
+	maxDropBps   float64
+	samples      timeseries.Series
+	soak         status.Soak
+}
+
+// add samples the rates. A ramp-up sample is left out of the averages, as it would bias them low.
+func (s *throughputSampler) add(stats trafficgen.GlobalStats, rampUp bool) {
+	if !rampUp {
+		s.samplesCount++
+		s.txPpsSum += stats.TxPps
+		s.rxPpsSum += stats.RxPps
+		s.txBpsSum += stats.TxBps
+		s.rxBpsSum += stats.RxBps
+	}
+	s.maxTxPps = math.Max(s.maxTxPps, stats.TxPps)
+	s.maxRxPps = math.Max(s.maxRxPps, stats.RxPps)
+	s.maxTxBps = math.Max(s.maxTxBps, stats.TxBps)
+	s.maxRxBps = math.Max(s.maxRxBps, stats.RxBps)
+	s.maxDropBps = math.Max(s.maxDropBps, stats.RxDropBps)
+	s.samples = append(s.samples, timeseries.Sample{
+		Timestamp: time.Now(),
+		TxPps:     stats.TxPps,
+		RxPps:     stats.RxPps,
+		DropBps:   stats.RxDropBps,
+		CPUUtil:   stats.CPUUtil,
+	})
+}
+
+// aborted tells whether the traffic was ended early by the soak snapshots.
+func (s *throughputSampler) aborted() bool {
+	return s.soak.AbortReason != ""
+}
+
+func (s *throughputSampler) apply(results *status.Results) {
+	if len(s.samples) == 0 {
+		return
+	}
+
+	if len(s.soak.Snapshots) > 0 {
+		soak := s.soak
+		results.Soak = &soak
+	}
+
+	// The averages are left unset when the traffic ended before its rates were sampled past the ramp-up
+	if s.samplesCount > 0 {
+		count := float64(s.samplesCount)
+		results.TrafficGenAvgTxPps = s.txPpsSum / count
+		results.TrafficGenAvgRxPps = s.rxPpsSum / count
+		results.TrafficGenAvgTxBps = s.txBpsSum / count
+		results.TrafficGenAvgRxBps = s.rxBpsSum / count
+	}
+	results.TrafficGenMaxTxPps = s.maxTxPps
+	results.TrafficGenMaxRxPps = s.maxRxPps
+	results.TrafficGenMaxTxBps = s.maxTxBps
+	results.TrafficGenMaxRxBps = s.maxRxBps
+	results.MaxDropRateBps = s.maxDropBps
+
+	log.Printf("traffic Generator throughput: TX avg %.0fpps/%.0fbps max %.0fpps/%.0fbps; RX avg %.0fpps/%.0fbps max %.0fpps/%.0fbps",
+		results.TrafficGenAvgTxPps, results.TrafficGenAvgTxBps, results.TrafficGenMaxTxPps, results.TrafficGenMaxTxBps,
+		results.TrafficGenAvgRxPps, results.TrafficGenAvgRxBps, results.TrafficGenMaxRxPps, results.TrafficGenMaxRxBps)
+	log.Printf("traffic Generator Max Drop Rate: %.0fbps", results.MaxDropRateBps)
+}
+
+// txDuration compares the requested traffic duration with the time the sent packets imply at the average TX rate.
+// It returns nil when the TX rate was not sampled.
+func txDuration(requested time.Duration, sentPackets int64, avgTxPps float64) *status.TxDuration {
+	if avgTxPps == 0 || requested == 0 {
+		return nil
+	}
+
+	actualSeconds := float64(sentPackets) / avgTxPps
+	result := &status.TxDuration{
+		RequestedSeconds: requested.Seconds(),
+		ActualSeconds:    actualSeconds,
+		DeltaPercent:     (actualSeconds - requested.Seconds()) / requested.Seconds() * 100,
+	}
+	log.Printf("traffic Generator TX duration: requested %.0fs, actual %.1fs (%+.1f%%)",
+		result.RequestedSeconds, result.ActualSeconds, result.DeltaPercent)
+	return result
+}
+
+// exportSamples writes the sampled metrics as CSV to the artifacts directory.
+// The export is best-effort, as it does not affect the checkup verdict.
+func exportSamples(artifactsDir string, samples timeseries.Series) {
+	fileFullPath := path.Join(artifactsDir, timeseries.CSVFileName)
+	if err := writeSamplesCSV(fileFullPath, samples); err != nil {
+		log.Printf("failed to export the traffic samples to %q: %v", fileFullPath, err)
+		return
+	}
+	log.Printf("exported %d traffic samples to %q", len(samples), fileFullPath)
+}
+
+func writeSamplesCSV(fileFullPath string, samples timeseries.Series) error {
+	f, err := os.Create(fileFullPath)
+	if err != nil {
+		return err
+	}
+
+	if err = samples.WriteCSV(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
 }
@@ -0,0 +1,237 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package guestagent runs commands in the guests through the QEMU guest agent, as an alternative to the serial console.
+// The agent commands are sent by virsh, executed in the compute container of the VMI virt-launcher Pod.
+package guestagent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+)
+
+const (
+	computeContainerName = "compute"
+	libvirtURI           = "qemu+unix:///session?socket=/var/run/libvirt/virtqemud-sock"
+	execStatusInterval   = 200 * time.Millisecond
+)
+
+type Client interface {
+	GetVirtualMachineInstance(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstance, error)
+	ListPods(ctx context.Context, namespace, labelSelector string) (*k8scorev1.PodList, error)
+	ExecInPod(ctx context.Context, namespace, podName, container string, command []string) (string, error)
+}
+
+// Runner runs shell commands in a single guest, each bounded by the given timeout.
+type Runner struct {
+	client    Client
+	namespace string
+	vmiName   string
+	timeout   time.Duration
+}
+
+func NewRunner(client Client, namespace, vmiName string, timeout time.Duration) Runner {
+	return Runner{
+		client:    client,
+		namespace: namespace,
+		vmiName:   vmiName,
+		timeout:   timeout,
+	}
+}
+
+// ExecStatus is the outcome of a command executed by the guest agent.
+type ExecStatus struct {
+	Exited   bool   `json:"exited"`
+	ExitCode int    `json:"exitcode"`
+	OutData  string `json:"out-data,omitempty"`
+	ErrData  string `json:"err-data,omitempty"`
+}
+
+type agentCommand struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+type guestExecArguments struct {
+	Path          string   `json:"path"`
+	Arg           []string `json:"arg,omitempty"`
+	CaptureOutput bool     `json:"capture-output"`
+}
+
+type guestExecStatusArguments struct {
+	PID int `json:"pid"`
+}
+
+// GetCommandOutput runs the given shell command in the guest and returns its output, along with its standard error,
+// as it would have been printed on the console.
+func (r Runner) GetCommandOutput(command string) (string, error) {
+	status, err := r.Exec(command)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(status.OutData + status.ErrData), nil
+}
+
+// GetGuestKernelArgs returns the guest kernel command line.
+func (r Runner) GetGuestKernelArgs() (string, error) {
+	return r.GetCommandOutput("cat /proc/cmdline")
+}
+
+// GetImageVersion returns the content of the version manifest file baked into the guest image.
+// An empty version is returned when the guest has no such file.
+func (r Runner) GetImageVersion(manifestPath string) (string, error) {
+	status, err := r.Exec(fmt.Sprintf("cat %s 2>/dev/null", manifestPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %q on VMI %q: %w", manifestPath, r.vmiFullName(), err)
+	}
+
+	return strings.TrimSpace(status.OutData), nil
+}
+
+// ValidateExecutable verifies that the given binary exists in the guest and is executable.
+// The binary may be given either as a path or as a name looked up in PATH.
+func (r Runner) ValidateExecutable(binaryPath string) error {
+	status, err := r.Exec(fmt.Sprintf("test -x \"$(command -v %s)\"", binaryPath))
+	if err != nil {
+		return fmt.Errorf("failed to validate %q on VMI %q: %w", binaryPath, r.vmiFullName(), err)
+	}
+
+	if status.ExitCode != 0 {
+		return fmt.Errorf("%q is not an executable on VMI %q", binaryPath, r.vmiFullName())
+	}
+
+	return nil
+}
+
+// Exec runs the shell command in the guest and waits for it to exit, decoding its output.
+func (r Runner) Exec(command string) (ExecStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	// The Pod is looked up per command, as the VMI moves to another Pod once migrated.
+	podName, err := r.launcherPodName(ctx)
+	if err != nil {
+		return ExecStatus{}, err
+	}
+
+	var started struct {
+		Return guestExecStatusArguments `json:"return"`
+	}
+	err = r.agentCommand(ctx, podName, agentCommand{
+		Execute:   "guest-exec",
+		Arguments: guestExecArguments{Path: "/bin/sh", Arg: []string{"-c", command}, CaptureOutput: true},
+	}, &started)
+	if err != nil {
+		return ExecStatus{}, fmt.Errorf("failed to run %q on VMI %q: %w", command, r.vmiFullName(), err)
+	}
+
+	for {
+		var status struct {
+			Return ExecStatus `json:"return"`
+		}
+		err = r.agentCommand(ctx, podName, agentCommand{Execute: "guest-exec-status", Arguments: started.Return}, &status)
+		if err != nil {
+			return ExecStatus{}, fmt.Errorf("failed to read the status of %q on VMI %q: %w", command, r.vmiFullName(), err)
+		}
+
+		if status.Return.Exited {
+			return decode(status.Return)
+		}
+
+		select {
+		case <-time.After(execStatusInterval):
+		case <-ctx.Done():
+			return ExecStatus{}, fmt.Errorf("%q did not exit on VMI %q: %w", command, r.vmiFullName(), ctx.Err())
+		}
+	}
+}
+
+func (r Runner) agentCommand(ctx context.Context, podName string, command agentCommand, response interface{}) error {
+	rawCommand, err := json.Marshal(command)
+	if err != nil {
+		return err
+	}
+
+	output, err := r.client.ExecInPod(ctx, r.namespace, podName, computeContainerName,
+		[]string{"virsh", "-c", libvirtURI, "qemu-agent-command", DomainName(r.namespace, r.vmiName), string(rawCommand)})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(output), response); err != nil {
+		return fmt.Errorf("failed to parse the guest agent response %q: %w", output, err)
+	}
+
+	return nil
+}
+
+// launcherPodName returns the name of the running virt-launcher Pod, on the node the VMI currently runs on.
+func (r Runner) launcherPodName(ctx context.Context) (string, error) {
+	vmi, err := r.client.GetVirtualMachineInstance(ctx, r.namespace, r.vmiName)
+	if err != nil {
+		return "", err
+	}
+
+	pods, err := r.client.ListPods(ctx, r.namespace, fmt.Sprintf("%s=%s", kvcorev1.CreatedByLabel, vmi.UID))
+	if err != nil {
+		return "", err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == k8scorev1.PodRunning && pod.Spec.NodeName == vmi.Status.NodeName {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("running virt-launcher Pod of VMI %q not found", r.vmiFullName())
+}
+
+func (r Runner) vmiFullName() string {
+	return r.namespace + "/" + r.vmiName
+}
+
+// DomainName returns the name of the libvirt domain of the VMI.
+func DomainName(namespace, vmiName string) string {
+	return namespace + "_" + vmiName
+}
+
+func decode(status ExecStatus) (ExecStatus, error) {
+	outData, err := base64.StdEncoding.DecodeString(status.OutData)
+	if err != nil {
+		return ExecStatus{}, fmt.Errorf("failed to decode the command output: %w", err)
+	}
+
+	errData, err := base64.StdEncoding.DecodeString(status.ErrData)
+	if err != nil {
+		return ExecStatus{}, fmt.Errorf("failed to decode the command error output: %w", err)
+	}
+
+	status.OutData, status.ErrData = string(outData), string(errData)
+	return status, nil
+}
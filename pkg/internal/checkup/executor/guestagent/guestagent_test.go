@@ -0,0 +1,215 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package guestagent_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/guestagent"
+)
+
+const (
+	testNamespace      = "default"
+	testVMIName        = "vmi-under-test"
+	testVMIUID         = "0123456789"
+	testNodeName       = "worker1"
+	testPodName        = "virt-launcher-vmi-under-test-abcde"
+	testCommandTimeout = time.Second
+)
+
+func TestGetCommandOutputShouldReturnTheCommandOutput(t *testing.T) {
+	client := newClientStub()
+	client.commands["cat /sys/devices/system/cpu/isolated"] = guestagent.ExecStatus{OutData: "2-7\n"}
+	runner := guestagent.NewRunner(client, testNamespace, testVMIName, testCommandTimeout)
+
+	output, err := runner.GetCommandOutput("cat /sys/devices/system/cpu/isolated")
+	assert.NoError(t, err)
+	assert.Equal(t, "2-7", output)
+	assert.Equal(t, []string{testPodName}, client.execPods)
+}
+
+func TestExecShouldWaitForTheCommandToExit(t *testing.T) {
+	client := newClientStub()
+	client.commands["sleep 1; echo done"] = guestagent.ExecStatus{OutData: "done\n"}
+	client.pendingStatuses = 2
+	runner := guestagent.NewRunner(client, testNamespace, testVMIName, testCommandTimeout)
+
+	status, err := runner.Exec("sleep 1; echo done")
+	assert.NoError(t, err)
+	assert.Equal(t, guestagent.ExecStatus{Exited: true, OutData: "done\n"}, status)
+}
+
+func TestExecShouldUseTheLauncherPodOfTheVMINode(t *testing.T) {
+	client := newClientStub()
+	client.commands["true"] = guestagent.ExecStatus{}
+	const migrationTargetPodName = "virt-launcher-vmi-under-test-fghij"
+	client.pods = append(client.pods, newLauncherPod(migrationTargetPodName, "worker2"))
+	client.vmi.Status.NodeName = "worker2"
+	runner := guestagent.NewRunner(client, testNamespace, testVMIName, testCommandTimeout)
+
+	_, err := runner.Exec("true")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{migrationTargetPodName}, client.execPods)
+}
+
+func TestValidateExecutable(t *testing.T) {
+	client := newClientStub()
+	client.commands[`test -x "$(command -v dpdk-testpmd)"`] = guestagent.ExecStatus{}
+	client.commands[`test -x "$(command -v t-rex-64)"`] = guestagent.ExecStatus{ExitCode: 1}
+	runner := guestagent.NewRunner(client, testNamespace, testVMIName, testCommandTimeout)
+
+	assert.NoError(t, runner.ValidateExecutable("dpdk-testpmd"))
+	assert.ErrorContains(t, runner.ValidateExecutable("t-rex-64"), "is not an executable")
+}
+
+func TestExecShouldFailWhen(t *testing.T) {
+	t.Run("the virt-launcher Pod is not running", func(t *testing.T) {
+		client := newClientStub()
+		client.pods[0].Status.Phase = k8scorev1.PodPending
+		runner := guestagent.NewRunner(client, testNamespace, testVMIName, testCommandTimeout)
+
+		_, err := runner.Exec("true")
+		assert.ErrorContains(t, err, "virt-launcher Pod")
+	})
+
+	t.Run("the guest agent command fails", func(t *testing.T) {
+		expectedErr := errors.New("guest agent is not connected")
+		client := newClientStub()
+		client.execErr = expectedErr
+		runner := guestagent.NewRunner(client, testNamespace, testVMIName, testCommandTimeout)
+
+		_, err := runner.Exec("true")
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("the command does not exit in time", func(t *testing.T) {
+		client := newClientStub()
+		client.commands["sleep infinity"] = guestagent.ExecStatus{}
+		client.pendingStatuses = -1
+		runner := guestagent.NewRunner(client, testNamespace, testVMIName, 10*time.Millisecond)
+
+		_, err := runner.Exec("sleep infinity")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// clientStub emulates the guest agent of a single VMI, driven by virsh in its virt-launcher Pod.
+type clientStub struct {
+	vmi      *kvcorev1.VirtualMachineInstance
+	pods     []k8scorev1.Pod
+	commands map[string]guestagent.ExecStatus
+	// pendingStatuses is the number of status reads before the command is reported to exit, or -1 for never.
+	pendingStatuses int
+	execErr         error
+	execPods        []string
+	lastCommand     string
+}
+
+func newClientStub() *clientStub {
+	return &clientStub{
+		vmi: &kvcorev1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: testVMIName, Namespace: testNamespace, UID: testVMIUID},
+			Status:     kvcorev1.VirtualMachineInstanceStatus{NodeName: testNodeName},
+		},
+		pods:     []k8scorev1.Pod{newLauncherPod(testPodName, testNodeName)},
+		commands: map[string]guestagent.ExecStatus{},
+	}
+}
+
+func newLauncherPod(name, nodeName string) k8scorev1.Pod {
+	return k8scorev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{kvcorev1.CreatedByLabel: testVMIUID},
+		},
+		Spec:   k8scorev1.PodSpec{NodeName: nodeName},
+		Status: k8scorev1.PodStatus{Phase: k8scorev1.PodRunning},
+	}
+}
+
+func (cs *clientStub) GetVirtualMachineInstance(_ context.Context, _, _ string) (*kvcorev1.VirtualMachineInstance, error) {
+	return cs.vmi, nil
+}
+
+func (cs *clientStub) ListPods(_ context.Context, _, labelSelector string) (*k8scorev1.PodList, error) {
+	if labelSelector != fmt.Sprintf("%s=%s", kvcorev1.CreatedByLabel, types.UID(testVMIUID)) {
+		return &k8scorev1.PodList{}, nil
+	}
+	return &k8scorev1.PodList{Items: cs.pods}, nil
+}
+
+func (cs *clientStub) ExecInPod(_ context.Context, namespace, podName, container string, command []string) (string, error) {
+	if cs.execErr != nil {
+		return "", cs.execErr
+	}
+	if container != "compute" || len(command) != 6 || command[3] != "qemu-agent-command" ||
+		command[4] != guestagent.DomainName(namespace, testVMIName) {
+		return "", fmt.Errorf("unexpected command %v in container %q", command, container)
+	}
+
+	var agentCommand struct {
+		Execute   string `json:"execute"`
+		Arguments struct {
+			Arg []string `json:"arg"`
+			PID int      `json:"pid"`
+		} `json:"arguments"`
+	}
+	if err := json.Unmarshal([]byte(command[5]), &agentCommand); err != nil {
+		return "", err
+	}
+
+	switch agentCommand.Execute {
+	case "guest-exec":
+		cs.execPods = append(cs.execPods, podName)
+		cs.lastCommand = agentCommand.Arguments.Arg[1]
+		return `{"return":{"pid":1234}}`, nil
+	case "guest-exec-status":
+		if cs.pendingStatuses != 0 {
+			cs.pendingStatuses--
+			return `{"return":{"exited":false}}`, nil
+		}
+		status, exists := cs.commands[cs.lastCommand]
+		if !exists {
+			status = guestagent.ExecStatus{ExitCode: 127, ErrData: "command not found"}
+		}
+		status.Exited = true
+		status.OutData = base64.StdEncoding.EncodeToString([]byte(status.OutData))
+		status.ErrData = base64.StdEncoding.EncodeToString([]byte(status.ErrData))
+		response, err := json.Marshal(map[string]interface{}{"return": status})
+		return string(response), err
+	}
+
+	return "", fmt.Errorf("unexpected agent command %q", agentCommand.Execute)
+}
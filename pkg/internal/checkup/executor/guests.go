@@ -0,0 +1,77 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The timeouts of the tasks performed on each guest, before the traffic starts.
+const (
+	guestLoginTimeout        = 3 * time.Minute
+	guestServiceStartTimeout = 5 * time.Minute
+)
+
+// guestTask is a series of steps performed on a single guest console.
+type guestTask struct {
+	guestName string
+	run       func(ctx context.Context) error
+}
+
+// runGuestTasks runs the tasks concurrently, each bounded by the given timeout, and returns the errors of all the failed ones.
+// The console interactions can not be interrupted, so a task that has timed out is abandoned and left to end on its own.
+func runGuestTasks(ctx context.Context, timeout time.Duration, tasks ...guestTask) error {
+	errs := make([]error, len(tasks))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, task guestTask) {
+			defer wg.Done()
+			errs[i] = runGuestTask(ctx, timeout, task)
+		}(i, task)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func runGuestTask(ctx context.Context, timeout time.Duration, task guestTask) error {
+	taskCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- task.run(taskCtx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-taskCtx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("the %s did not complete its tasks within %s", task.guestName, timeout)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/isolation"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+)
+
+// cpuIsolationVerified is reported when both guests have the DPDK applications CPUs isolated.
+const cpuIsolationVerified = "verified"
+
+// checkCPUIsolation verifies both guests have the DPDK applications CPUs isolated by the tuned cpu-partitioning profile,
+// and returns what is missing in each guest. Missing isolation fails the run only when the check is set to fail.
+func (e Executor) checkCPUIsolation(vmUnderTestRunner, trafficGenRunner guestCommandRunner) (string, error) {
+	log.Printf("Checking the guests CPUs isolation...")
+	guests := []struct {
+		name   string
+		runner guestCommandRunner
+	}{
+		{name: vmUnderTestGuestName, runner: vmUnderTestRunner},
+		{name: trafficGenGuestName, runner: trafficGenRunner},
+	}
+
+	var findings []string
+	for _, guest := range guests {
+		guestFindings, err := isolation.Check(guest.runner, config.GuestIsolatedCPUs)
+		if err != nil {
+			return "", fmt.Errorf("failed to check the %s CPUs isolation: %w", guest.name, err)
+		}
+		for _, finding := range guestFindings {
+			findings = append(findings, guest.name+": "+finding)
+		}
+	}
+
+	if len(findings) == 0 {
+		log.Printf("The guests CPUs %s are isolated", config.GuestIsolatedCPUs)
+		return cpuIsolationVerified, nil
+	}
+
+	summary := strings.Join(findings, "; ")
+	if e.cpuIsolationCheck == config.CPUIsolationCheckFail {
+		return summary, exitcode.Classify(exitcode.SetupFailure, fmt.Errorf("the guests CPUs are not isolated: %s", summary))
+	}
+	log.Printf("Warning: the guests CPUs are not isolated, the results may be affected: %s", summary)
+
+	return summary, nil
+}
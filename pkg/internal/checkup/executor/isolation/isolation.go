@@ -0,0 +1,93 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package isolation verifies the guests have the DPDK applications CPUs isolated, as the tuned cpu-partitioning
+// profile is expected to set up on their first boot.
+package isolation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type commandRunner interface {
+	GetCommandOutput(command string) (string, error)
+}
+
+const (
+	kernelArgsCommand   = "cat /proc/cmdline"
+	tunedActiveCommand  = "tuned-adm active"
+	isolatedCPUsCommand = "cat /sys/devices/system/cpu/isolated"
+	tunedProfile        = "cpu-partitioning"
+	isolcpusKernelArg   = "isolcpus"
+	nohzFullKernelArg   = "nohz_full"
+	listSeparator       = ","
+)
+
+// isolcpusFlags are the flags the isolcpus kernel arg may prefix its CPU list with.
+var isolcpusFlags = []string{"nohz", "domain", "managed_irq"}
+
+// Check reads the guest kernel args, tuned active profile and kernel isolated CPUs, and returns what is missing for the
+// given CPUs to be isolated. No findings are returned when the CPUs are isolated.
+func Check(runner commandRunner, cpus string) ([]string, error) {
+	outputs := map[string]string{}
+	for _, command := range []string{kernelArgsCommand, tunedActiveCommand, isolatedCPUsCommand} {
+		output, err := runner.GetCommandOutput(command)
+		if err != nil {
+			return nil, err
+		}
+		outputs[command] = output
+	}
+
+	var findings []string
+	if !strings.Contains(outputs[tunedActiveCommand], tunedProfile) {
+		findings = append(findings, fmt.Sprintf("the tuned %s profile is not active (%q)", tunedProfile, outputs[tunedActiveCommand]))
+	}
+
+	if !kernelArgsIsolate(outputs[kernelArgsCommand], cpus) {
+		findings = append(findings, fmt.Sprintf("no %s or %s kernel arg isolates CPUs %s", isolcpusKernelArg, nohzFullKernelArg, cpus))
+	}
+
+	if outputs[isolatedCPUsCommand] != cpus {
+		findings = append(findings, fmt.Sprintf("CPUs %s are not isolated by the kernel (isolated: %q)", cpus, outputs[isolatedCPUsCommand]))
+	}
+
+	return findings, nil
+}
+
+func kernelArgsIsolate(kernelArgs, cpus string) bool {
+	for _, arg := range strings.Fields(kernelArgs) {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case nohzFullKernelArg:
+			if value == cpus {
+				return true
+			}
+		case isolcpusKernelArg:
+			cpuList := slices.DeleteFunc(strings.Split(value, listSeparator), func(item string) bool {
+				return slices.Contains(isolcpusFlags, item)
+			})
+			if strings.Join(cpuList, listSeparator) == cpus {
+				return true
+			}
+		}
+	}
+	return false
+}
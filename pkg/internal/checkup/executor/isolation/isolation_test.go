@@ -0,0 +1,91 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package isolation_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/isolation"
+)
+
+const (
+	isolatedCPUs       = "2-7"
+	tunedActiveProfile = "Current active profile: cpu-partitioning"
+)
+
+func TestCheckShouldSucceedWhenTheCPUsAreIsolated(t *testing.T) {
+	tests := map[string]string{
+		"isolcpus with flags": "BOOT_IMAGE=/vmlinuz root=/dev/vda1 isolcpus=managed_irq,domain,2-7 intel_pstate=disable",
+		"isolcpus":            "BOOT_IMAGE=/vmlinuz root=/dev/vda1 isolcpus=2-7",
+		"nohz_full":           "BOOT_IMAGE=/vmlinuz root=/dev/vda1 nohz=on nohz_full=2-7 rcu_nocbs=2-7",
+	}
+
+	for name, kernelArgs := range tests {
+		t.Run(name, func(t *testing.T) {
+			runner := runnerStub{outputs: map[string]string{
+				"cat /proc/cmdline":                    kernelArgs,
+				"tuned-adm active":                     tunedActiveProfile,
+				"cat /sys/devices/system/cpu/isolated": isolatedCPUs,
+			}}
+
+			findings, err := isolation.Check(runner, isolatedCPUs)
+			assert.NoError(t, err)
+			assert.Empty(t, findings)
+		})
+	}
+}
+
+func TestCheckShouldReportTheMissingIsolation(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{
+		"cat /proc/cmdline":                    "BOOT_IMAGE=/vmlinuz root=/dev/vda1 isolcpus=managed_irq,domain,2-5",
+		"tuned-adm active":                     "No current active profile.",
+		"cat /sys/devices/system/cpu/isolated": "",
+	}}
+
+	findings, err := isolation.Check(runner, isolatedCPUs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`the tuned cpu-partitioning profile is not active ("No current active profile.")`,
+		"no isolcpus or nohz_full kernel arg isolates CPUs 2-7",
+		`CPUs 2-7 are not isolated by the kernel (isolated: "")`,
+	}, findings)
+}
+
+func TestCheckShouldFailWhenACommandFails(t *testing.T) {
+	expectedErr := errors.New("console is not responding")
+
+	_, err := isolation.Check(runnerStub{err: expectedErr}, isolatedCPUs)
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+type runnerStub struct {
+	outputs map[string]string
+	err     error
+}
+
+func (r runnerStub) GetCommandOutput(command string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.outputs[command], nil
+}
@@ -0,0 +1,260 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package l3fwd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	expect "github.com/google/goexpect"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/telemetry"
+)
+
+type consoleExpecter interface {
+	SafeExpectBatchWithResponse(expected []expect.Batcher, timeout time.Duration) ([]expect.BatchRes, error)
+}
+
+const (
+	logFileFullPath       = "/tmp/dpdk-l3fwd.log"
+	ipv4RulesFileFullPath = "/tmp/dpdk-l3fwd-rules-v4.cfg"
+	ipv6RulesFileFullPath = "/tmp/dpdk-l3fwd-rules-v6.cfg"
+
+	shellPrompt      = "# "
+	exitStatusZero   = "\n0\r\n.*" + shellPrompt
+	mainLoopLogEntry = "entering main loop"
+)
+
+// Port is an l3fwd port, sending the traffic routed to it to its Ethernet destination.
+type Port struct {
+	PCIAddress         string
+	EthDestMACAddress  string
+	DestIPv4Prefix     string
+	DestIPv6Prefix     string
+	DestinationPortIdx int
+}
+
+type PortStats = telemetry.PortStats
+
+// Console drives dpdk-l3fwd, which runs in the background of the VM under test shell.
+// The traffic received on each port is routed to its destination port, according to the destination IP prefixes of the port.
+// As the port statistics are read using the DPDK telemetry, which cannot reset them,
+// clearing the statistics is done by taking a baseline they are reported relative to.
+type Console struct {
+	consoleExpecter      consoleExpecter
+	binaryPath           string
+	ports                []Port
+	verbosePrintsEnabled bool
+	baseline             []PortStats
+}
+
+func NewConsole(vmiUnderTestConsoleExpecter consoleExpecter,
+	binaryPath string,
+	ports []Port,
+	verbosePrintsEnabled bool) *Console {
+	return &Console{
+		consoleExpecter:      vmiUnderTestConsoleExpecter,
+		binaryPath:           binaryPath,
+		ports:                ports,
+		verbosePrintsEnabled: verbosePrintsEnabled,
+	}
+}
+
+// Run sets up the routes and starts l3fwd, waiting for it to enter its main loop.
+func (c *Console) Run() error {
+	const (
+		batchTimeout       = 30 * time.Second
+		mainLoopTimeoutSec = 60
+	)
+
+	waitCmd := fmt.Sprintf("timeout %d sh -c 'until grep -q %q %s; do sleep 1; done'",
+		mainLoopTimeoutSec, mainLoopLogEntry, logFileFullPath)
+
+	var batch []expect.Batcher
+	for _, command := range []string{
+		writeRulesFileCmd(ipv4RulesFileFullPath, c.ipv4Rules()),
+		writeRulesFileCmd(ipv6RulesFileFullPath, c.ipv6Rules()),
+		c.Command() + " > " + logFileFullPath + " 2>&1 &",
+		waitCmd,
+	} {
+		batch = append(batch,
+			&expect.BSnd{S: command + "\n"},
+			&expect.BExp{R: shellPrompt},
+			&expect.BSnd{S: "echo $?\n"},
+			&expect.BExp{R: exitStatusZero},
+		)
+	}
+
+	if _, err := c.consoleExpecter.SafeExpectBatchWithResponse(batch, batchTimeout+mainLoopTimeoutSec*time.Second); err != nil {
+		c.printLog()
+		return fmt.Errorf("failed to start l3fwd: %w", err)
+	}
+
+	if c.verbosePrintsEnabled {
+		c.printLog()
+	}
+
+	return nil
+}
+
+// Command returns the command line l3fwd is started with.
+func (c *Console) Command() string {
+	const (
+		lcores                  = "2-7"
+		firstForwardingLcore    = 4
+		forwardingLcoresCount   = 4
+		hugepageSizeInMegaBytes = 1024
+		hugepagesMountedDir     = "/mnt/huge"
+	)
+
+	sb := strings.Builder{}
+	sb.WriteString(c.binaryPath + " ")
+	sb.WriteString(fmt.Sprintf("-l %s ", lcores))
+	for _, port := range c.ports {
+		sb.WriteString(fmt.Sprintf("-a %s ", port.PCIAddress))
+	}
+	sb.WriteString(fmt.Sprintf("--socket-mem %d ", hugepageSizeInMegaBytes))
+	sb.WriteString(fmt.Sprintf("--huge-dir %s ", hugepagesMountedDir))
+	sb.WriteString("-- ")
+	sb.WriteString(fmt.Sprintf("-p 0x%x ", 1<<len(c.ports)-1))
+	sb.WriteString("-P ")
+	sb.WriteString("--parse-ptype ")
+
+	var queuesConfig []string
+	for portIdx := range c.ports {
+		queuesConfig = append(queuesConfig,
+			fmt.Sprintf("(%d,0,%d)", portIdx, firstForwardingLcore+portIdx%forwardingLcoresCount))
+	}
+	sb.WriteString(fmt.Sprintf("--config=%q ", strings.Join(queuesConfig, ",")))
+
+	for portIdx, port := range c.ports {
+		sb.WriteString(fmt.Sprintf("--eth-dest=%d,%s ", portIdx, port.EthDestMACAddress))
+	}
+	sb.WriteString(fmt.Sprintf("--rule_ipv4=%s ", ipv4RulesFileFullPath))
+	sb.WriteString(fmt.Sprintf("--rule_ipv6=%s", ipv6RulesFileFullPath))
+
+	return sb.String()
+}
+
+func (c *Console) ipv4Rules() []string {
+	var rules []string
+	for _, port := range c.ports {
+		if port.DestIPv4Prefix != "" {
+			rules = append(rules, fmt.Sprintf("R%s %d", port.DestIPv4Prefix, port.DestinationPortIdx))
+		}
+	}
+	return rules
+}
+
+func (c *Console) ipv6Rules() []string {
+	var rules []string
+	for _, port := range c.ports {
+		if port.DestIPv6Prefix != "" {
+			rules = append(rules, fmt.Sprintf("R%s %d", port.DestIPv6Prefix, port.DestinationPortIdx))
+		}
+	}
+	return rules
+}
+
+func writeRulesFileCmd(fileFullPath string, rules []string) string {
+	var content string
+	for _, rule := range rules {
+		content += rule + `\n`
+	}
+	return fmt.Sprintf("printf '%s' > %s", content, fileFullPath)
+}
+
+func (c *Console) printLog() {
+	const batchTimeout = 30 * time.Second
+
+	resp, err := c.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+		&expect.BSnd{S: "cat " + logFileFullPath + "\n"},
+		&expect.BExp{R: shellPrompt},
+	},
+		batchTimeout,
+	)
+	if err != nil {
+		log.Printf("failed to read the l3fwd log: %v", err)
+		return
+	}
+
+	log.Printf("l3fwd log:\n%s", resp[0].Output)
+}
+
+// ClearStats takes the current statistics as the baseline the following statistics are reported relative to.
+func (c *Console) ClearStats() error {
+	stats, err := c.readStats()
+	if err != nil {
+		return err
+	}
+
+	c.baseline = stats
+	return nil
+}
+
+// KeepAlive pokes the shell the l3fwd runs in the background of, to keep it from being dropped while it is idle.
+func (c *Console) KeepAlive() error {
+	const batchTimeout = 10 * time.Second
+
+	_, err := c.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+		&expect.BSnd{S: "\n"},
+		&expect.BExp{R: shellPrompt},
+	},
+		batchTimeout,
+	)
+
+	return err
+}
+
+// GetStats returns the statistics of each port, since they were last cleared.
+func (c *Console) GetStats() ([]PortStats, error) {
+	stats, err := c.readStats()
+	if err != nil {
+		return nil, err
+	}
+
+	for portIdx := range c.baseline {
+		stats[portIdx] = telemetry.Subtract(stats[portIdx], c.baseline[portIdx])
+	}
+
+	return stats, nil
+}
+
+func (c *Console) readStats() ([]PortStats, error) {
+	const batchTimeout = 30 * time.Second
+
+	resp, err := c.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+		&expect.BSnd{S: telemetry.PortStatsCommand(len(c.ports)) + "\n"},
+		&expect.BExp{R: shellPrompt},
+	},
+		batchTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.verbosePrintsEnabled {
+		log.Printf("l3fwd stats:\n%s", resp[0].Output)
+	}
+
+	return telemetry.ParsePortStats(resp[0].Output, len(c.ports))
+}
@@ -0,0 +1,183 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package l3fwd_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	expect "github.com/google/goexpect"
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/l3fwd"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/telemetry"
+)
+
+const (
+	l3fwdBinaryPath      = "dpdk-l3fwd"
+	verbosePrintsEnabled = false
+)
+
+func TestRunShouldSetupTheRoutesAndStartL3fwd(t *testing.T) {
+	expecter := &expecterStub{}
+	c := l3fwd.NewConsole(expecter, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+	assert.NoError(t, c.Run())
+	assert.Contains(t, expecter.sent, `printf 'R10.0.0.0/24 1\n' > /tmp/dpdk-l3fwd-rules-v4.cfg`)
+	assert.Contains(t, expecter.sent, `printf 'R2001:db8:10:0::/64 1\n' > /tmp/dpdk-l3fwd-rules-v6.cfg`)
+	assert.Contains(t, expecter.sent, c.Command()+" > /tmp/dpdk-l3fwd.log 2>&1 &\n")
+	assert.Contains(t, expecter.sent, `grep -q "entering main loop" /tmp/dpdk-l3fwd.log`)
+}
+
+func TestRunFailure(t *testing.T) {
+	expectedErr := errors.New("l3fwd failed to start")
+	c := l3fwd.NewConsole(&expecterStub{err: expectedErr}, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+	assert.ErrorContains(t, c.Run(), expectedErr.Error())
+}
+
+func TestCommand(t *testing.T) {
+	c := l3fwd.NewConsole(&expecterStub{}, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+	expected := "dpdk-l3fwd -l 2-7 -a 0000:06:00.0 -a 0000:07:00.0 --socket-mem 1024 --huge-dir /mnt/huge -- " +
+		`-p 0x3 -P --parse-ptype --config="(0,0,4),(1,0,5)" ` +
+		"--eth-dest=0,60:94:19:c9:ac:01 --eth-dest=1,60:94:19:c9:ac:02 " +
+		"--rule_ipv4=/tmp/dpdk-l3fwd-rules-v4.cfg --rule_ipv6=/tmp/dpdk-l3fwd-rules-v6.cfg"
+	assert.Equal(t, expected, c.Command())
+}
+
+func TestGetStatsSuccess(t *testing.T) {
+	expecter := &expecterStub{statsOutputs: []string{telemetryStatsOutput(portStatsReply(100, 0, 1, 0), portStatsReply(0, 90, 0, 2))}}
+	c := l3fwd.NewConsole(expecter, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+	stats, err := c.GetStats()
+	assert.NoError(t, err)
+	expected := []l3fwd.PortStats{
+		{RXPackets: 100, RXDropped: 1, RXTotal: 101, TXPackets: 0, TXDropped: 0, TXTotal: 0},
+		{RXPackets: 0, RXDropped: 0, RXTotal: 0, TXPackets: 90, TXDropped: 2, TXTotal: 92},
+	}
+	assert.Equal(t, expected, stats)
+}
+
+func TestGetStatsShouldBeRelativeToTheClearedStats(t *testing.T) {
+	expecter := &expecterStub{statsOutputs: []string{
+		telemetryStatsOutput(portStatsReply(100, 0, 1, 0), portStatsReply(0, 90, 0, 2)),
+		telemetryStatsOutput(portStatsReply(300, 0, 4, 0), portStatsReply(0, 280, 0, 2)),
+	}}
+	c := l3fwd.NewConsole(expecter, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+	assert.NoError(t, c.ClearStats())
+
+	stats, err := c.GetStats()
+	assert.NoError(t, err)
+	expected := []l3fwd.PortStats{
+		{RXPackets: 200, RXDropped: 3, RXTotal: 203},
+		{TXPackets: 190, TXDropped: 0, TXTotal: 190},
+	}
+	assert.Equal(t, expected, stats)
+}
+
+func TestGetStatsFailure(t *testing.T) {
+	t.Run("when batch execution fails", func(t *testing.T) {
+		expectedErr := errors.New("failed to run batch")
+		c := l3fwd.NewConsole(&expecterStub{err: expectedErr}, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+		stats, err := c.GetStats()
+		assert.ErrorContains(t, err, expectedErr.Error())
+		assert.Empty(t, stats)
+	})
+
+	t.Run("when the stats of a port are missing", func(t *testing.T) {
+		expecter := &expecterStub{statsOutputs: []string{
+			telemetryStatsOutput(portStatsReply(1, 1, 0, 0)),
+		}}
+		c := l3fwd.NewConsole(expecter, l3fwdBinaryPath, testPorts(), verbosePrintsEnabled)
+
+		stats, err := c.GetStats()
+		assert.ErrorContains(t, err, "expected the statistics of 2 ports, found 1")
+		assert.Empty(t, stats)
+	})
+}
+
+func testPorts() []l3fwd.Port {
+	return []l3fwd.Port{
+		{
+			PCIAddress:         "0000:06:00.0",
+			EthDestMACAddress:  "60:94:19:c9:ac:01",
+			DestIPv4Prefix:     "10.0.0.0/24",
+			DestIPv6Prefix:     "2001:db8:10:0::/64",
+			DestinationPortIdx: 1,
+		},
+		{
+			PCIAddress:        "0000:07:00.0",
+			EthDestMACAddress: "60:94:19:c9:ac:02",
+		},
+	}
+}
+
+// telemetryStatsOutput returns the telemetry script output, replying to the stats query of each port in order.
+func telemetryStatsOutput(portsReplies ...string) string {
+	sb := strings.Builder{}
+	for portIdx, reply := range portsReplies {
+		sb.WriteString(fmt.Sprintf("--> /ethdev/stats,%d\n%s\n", portIdx, reply))
+	}
+	sb.WriteString("--> \n# ")
+	return sb.String()
+}
+
+func portStatsReply(ipackets, opackets, imissed, oerrors int64) string {
+	return fmt.Sprintf(`{"/ethdev/stats": {"ipackets": %d, "opackets": %d, "ibytes": 0, "obytes": 0, `+
+		`"imissed": %d, "ierrors": 0, "oerrors": %d, "rx_nombuf": 0, "q_ipackets": [0]}}`, ipackets, opackets, imissed, oerrors)
+}
+
+type expecterStub struct {
+	err          error
+	statsOutputs []string
+	sent         string
+}
+
+func (es *expecterStub) SafeExpectBatchWithResponse(expected []expect.Batcher, _ time.Duration) ([]expect.BatchRes, error) {
+	if es.err != nil {
+		return nil, es.err
+	}
+
+	var batchRes []expect.BatchRes
+	for idx, batcher := range expected {
+		if batcher.Cmd() != expect.BatchSend {
+			batchRes = append(batchRes, expect.BatchRes{Idx: idx, Output: "# "})
+			continue
+		}
+		es.sent += batcher.Arg()
+
+		if strings.Contains(batcher.Arg(), telemetry.ScriptName) {
+			if len(es.statsOutputs) == 0 {
+				return nil, fmt.Errorf("unexpected stats query: %s", batcher.Arg())
+			}
+			batchRes = append(batchRes, expect.BatchRes{Idx: idx + 1, Output: es.statsOutputs[0]})
+			es.statsOutputs = es.statsOutputs[1:]
+			return batchRes, nil
+		}
+	}
+
+	return batchRes, nil
+}
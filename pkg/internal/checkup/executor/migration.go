@@ -0,0 +1,196 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// migrationDelay is how long the traffic runs before the VM under test is migrated, so the loss of the steady traffic
+// is measured.
+const migrationDelay = 10 * time.Second
+
+type vmiMigrator interface {
+	MigrateVirtualMachineInstance(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error)
+	GetVirtualMachineInstanceMigration(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error)
+}
+
+// liveMigration migrates the VM under test once, while the traffic of the first iteration runs, and counts the packets
+// lost from its start to its completion. It is driven by the stats polling, so the traffic generator console is never
+// used concurrently.
+type liveMigration struct {
+	migrator      vmiMigrator
+	vmiGetter     vmiGetter
+	namespace     string
+	vmiName       string
+	name          string
+	sourceNode    string
+	start         time.Time
+	startCounters migrationCounters
+	result        *status.Migration
+}
+
+// migrationCounters are the packets sent and received back by the traffic generator.
+type migrationCounters struct {
+	sent     int64
+	received int64
+}
+
+func newLiveMigration(migrator vmiMigrator, getter vmiGetter, namespace string) *liveMigration {
+	return &liveMigration{migrator: migrator, vmiGetter: getter, namespace: namespace}
+}
+
+// step advances the migration on every stats poll: it is triggered once the traffic has run for the migration delay,
+// and its completion is checked on the following polls.
+func (m *liveMigration) step(ctx context.Context, elapsed time.Duration, readCounters func() (migrationCounters, error)) error {
+	switch {
+	case m.result != nil:
+		return nil
+	case m.name == "":
+		if elapsed < migrationDelay {
+			return nil
+		}
+		return m.trigger(ctx, readCounters)
+	default:
+		return m.checkCompletion(ctx, readCounters)
+	}
+}
+
+// finish verifies the migration completed while the traffic ran.
+func (m *liveMigration) finish() error {
+	switch {
+	case m.result != nil:
+		return nil
+	case m.name == "":
+		return fmt.Errorf("the traffic did not run long enough to migrate VMI \"%s/%s\", a test duration longer than %s is required",
+			m.namespace, m.vmiName, migrationDelay)
+	default:
+		return fmt.Errorf("the live migration %q of VMI \"%s/%s\" did not complete within the test duration",
+			m.name, m.namespace, m.vmiName)
+	}
+}
+
+func (m *liveMigration) trigger(ctx context.Context, readCounters func() (migrationCounters, error)) error {
+	vmi, err := m.vmiGetter.GetVirtualMachineInstance(ctx, m.namespace, m.vmiName)
+	if err != nil {
+		return err
+	}
+	if err := liveMigratable(vmi); err != nil {
+		return fmt.Errorf("VMI \"%s/%s\" cannot be migrated: %w", m.namespace, m.vmiName, err)
+	}
+
+	if m.startCounters, err = readCounters(); err != nil {
+		return err
+	}
+
+	log.Printf("Live migrating VMI \"%s/%s\" from node %q...", m.namespace, m.vmiName, vmi.Status.NodeName)
+	migration, err := m.migrator.MigrateVirtualMachineInstance(ctx, m.namespace, m.vmiName)
+	if err != nil {
+		return fmt.Errorf("failed to migrate VMI \"%s/%s\": %w", m.namespace, m.vmiName, err)
+	}
+	m.name = migration.Name
+	m.sourceNode = vmi.Status.NodeName
+	m.start = time.Now()
+
+	return nil
+}
+
+func (m *liveMigration) checkCompletion(ctx context.Context, readCounters func() (migrationCounters, error)) error {
+	migration, err := m.migrator.GetVirtualMachineInstanceMigration(ctx, m.namespace, m.name)
+	if err != nil {
+		return err
+	}
+
+	switch migration.Status.Phase {
+	case kvcorev1.MigrationFailed:
+		return fmt.Errorf("the live migration %q of VMI \"%s/%s\" failed", m.name, m.namespace, m.vmiName)
+	case kvcorev1.MigrationSucceeded:
+	default:
+		return nil
+	}
+
+	endCounters, err := readCounters()
+	if err != nil {
+		return err
+	}
+
+	duration := time.Since(m.start)
+	result := &status.Migration{SourceNode: m.sourceNode}
+	if state := migration.Status.MigrationState; state != nil {
+		result.TargetNode = state.TargetNode
+		if state.StartTimestamp != nil && state.EndTimestamp != nil {
+			duration = state.EndTimestamp.Sub(state.StartTimestamp.Time)
+		}
+	}
+	result.DurationSeconds = duration.Seconds()
+	result.LostPackets = (endCounters.sent - m.startCounters.sent) - (endCounters.received - m.startCounters.received)
+	m.result = result
+
+	log.Printf("VMI \"%s/%s\" was migrated to node %q in %s, losing %d packets",
+		m.namespace, m.vmiName, result.TargetNode, duration.Round(time.Millisecond), result.LostPackets)
+	return nil
+}
+
+func liveMigratable(vmi *kvcorev1.VirtualMachineInstance) error {
+	for _, condition := range vmi.Status.Conditions {
+		if condition.Type == kvcorev1.VirtualMachineInstanceIsMigratable {
+			if condition.Status == k8scorev1.ConditionTrue {
+				return nil
+			}
+			return fmt.Errorf("%s: %s", condition.Reason, condition.Message)
+		}
+	}
+	return errors.New("the VMI reports no live migratable condition")
+}
+
+// readMigrationCounters returns a reader of the packets sent on the source ports and received back on the
+// destination ports.
+func readMigrationCounters(trafficGenerator trafficgen.TrafficGenerator,
+	direction trafficDirection,
+	portsCount int) func() (migrationCounters, error) {
+	return func() (migrationCounters, error) {
+		var counters migrationCounters
+		for _, srcPort := range direction.sourcePorts(portsCount) {
+			stats, err := trafficGenerator.PortStats(srcPort)
+			if err != nil {
+				return migrationCounters{}, err
+			}
+			counters.sent += stats.OutputPackets
+		}
+		for _, dstPort := range direction.destPorts(portsCount) {
+			stats, err := trafficGenerator.PortStats(dstPort)
+			if err != nil {
+				return migrationCounters{}, err
+			}
+			counters.received += stats.InputPackets
+		}
+		return counters, nil
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/numa"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+// checkNUMAAlignment reports, per VMI, whether its vCPUs, hugepages and NICs reside on a single NUMA node.
+// The guest NUMA topology is meaningful only when the VMI passes it through, otherwise the alignment is undetermined.
+// Misalignment is reported and logged, but does not fail the run.
+func (e Executor) checkNUMAAlignment(ctx context.Context, vmUnderTestRunner, trafficGenRunner guestCommandRunner,
+	vmiUnderTestName, trafficGenVMIName string) string {
+	log.Printf("Checking the VMIs NUMA alignment...")
+	guests := []struct {
+		name         string
+		vmiName      string
+		runner       guestCommandRunner
+		pciAddresses []string
+	}{
+		{name: vmUnderTestGuestName, vmiName: vmiUnderTestName, runner: vmUnderTestRunner, pciAddresses: e.vmUnderTestPCIAddresses},
+		{name: trafficGenGuestName, vmiName: trafficGenVMIName, runner: trafficGenRunner, pciAddresses: e.trafficGenPCIAddresses},
+	}
+
+	var summaries []string
+	for _, guest := range guests {
+		alignment := e.guestNUMAAlignment(ctx, guest.vmiName, guest.runner, guest.pciAddresses)
+		summaries = append(summaries, guest.name+": "+alignment)
+	}
+
+	summary := strings.Join(summaries, "; ")
+	log.Printf("NUMA alignment: %s", summary)
+	return summary
+}
+
+func (e Executor) guestNUMAAlignment(ctx context.Context, vmiName string, runner guestCommandRunner, pciAddresses []string) string {
+	vmi, err := e.vmiGetter.GetVirtualMachineInstance(ctx, e.namespace, vmiName)
+	if err != nil {
+		log.Printf("Warning: failed to get VMI \"%s/%s\": %v", e.namespace, vmiName, err)
+		return fmt.Sprintf("undetermined, failed to get the VMI: %v", err)
+	}
+
+	if cpu := vmi.Spec.Domain.CPU; cpu == nil || cpu.NUMA == nil || cpu.NUMA.GuestMappingPassthrough == nil {
+		return fmt.Sprintf("undetermined, the VMI NUMA topology is not passed through (see %s)", config.NUMAPassthroughParamName)
+	}
+
+	alignment, err := numa.Read(runner, pciAddresses)
+	if err != nil {
+		log.Printf("Warning: failed to read VMI \"%s/%s\" NUMA nodes: %v", e.namespace, vmiName, err)
+		return fmt.Sprintf("undetermined, failed to read the guest NUMA nodes: %v", err)
+	}
+
+	if !alignment.Aligned() {
+		log.Printf("Warning: VMI \"%s/%s\" resources are not on a single NUMA node, the results may be affected: %s",
+			e.namespace, vmiName, alignment)
+	}
+	return alignment.String()
+}
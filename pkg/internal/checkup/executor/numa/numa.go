@@ -0,0 +1,162 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package numa reads which guest NUMA nodes the DPDK applications resources reside on. The guest NUMA topology
+// reflects the host one only when the VMI NUMA guest mapping is passed through.
+package numa
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type commandRunner interface {
+	GetCommandOutput(command string) (string, error)
+}
+
+const (
+	nodesDirectory     = "/sys/devices/system/node"
+	pciDevicesDir      = "/sys/bus/pci/devices"
+	hugepagesDirectory = "hugepages/hugepages-1048576kB"
+	noNode             = -1
+)
+
+// Alignment lists the guest NUMA nodes the vCPUs, the 1Gi hugepages and the NICs reside on.
+type Alignment struct {
+	CPUNodes       []int
+	HugepagesNodes []int
+	NICNodes       []int
+}
+
+// Aligned reports whether the vCPUs, hugepages and NICs all reside on a single NUMA node.
+func (a Alignment) Aligned() bool {
+	nodes := a.nodes()
+	return len(nodes) == 1 && len(a.CPUNodes) == 1 && len(a.HugepagesNodes) == 1
+}
+
+func (a Alignment) String() string {
+	if a.Aligned() {
+		return fmt.Sprintf("aligned on NUMA node %d", a.CPUNodes[0])
+	}
+	return fmt.Sprintf("misaligned, vCPUs on NUMA nodes %s, hugepages on NUMA nodes %s, NICs on NUMA nodes %s",
+		formatNodes(a.CPUNodes), formatNodes(a.HugepagesNodes), formatNodes(a.NICNodes))
+}
+
+func (a Alignment) nodes() []int {
+	var nodes []int
+	for _, node := range slices.Concat(a.CPUNodes, a.HugepagesNodes, a.NICNodes) {
+		if !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Read reads the guest NUMA nodes the vCPUs, hugepages and NICs at the given PCI addresses reside on.
+// NICs the guest reports no NUMA node for are not listed.
+func Read(runner commandRunner, pciAddresses []string) (Alignment, error) {
+	onlineNodes, err := runner.GetCommandOutput("cat " + nodesDirectory + "/online")
+	if err != nil {
+		return Alignment{}, err
+	}
+	nodes, err := parseList(onlineNodes)
+	if err != nil {
+		return Alignment{}, fmt.Errorf("failed to parse the online NUMA nodes %q: %w", onlineNodes, err)
+	}
+
+	var alignment Alignment
+	for _, node := range nodes {
+		nodeDirectory := fmt.Sprintf("%s/node%d", nodesDirectory, node)
+		cpus, err := runner.GetCommandOutput("cat " + nodeDirectory + "/cpulist")
+		if err != nil {
+			return Alignment{}, err
+		}
+		if cpus != "" {
+			alignment.CPUNodes = append(alignment.CPUNodes, node)
+		}
+
+		hugepages, err := readInt(runner, nodeDirectory+"/"+hugepagesDirectory+"/nr_hugepages")
+		if err != nil {
+			return Alignment{}, err
+		}
+		if hugepages > 0 {
+			alignment.HugepagesNodes = append(alignment.HugepagesNodes, node)
+		}
+	}
+
+	for _, pciAddress := range pciAddresses {
+		node, err := readInt(runner, pciDevicesDir+"/"+pciAddress+"/numa_node")
+		if err != nil {
+			return Alignment{}, fmt.Errorf("failed to read NIC %s NUMA node: %w", pciAddress, err)
+		}
+		if node != noNode && !slices.Contains(alignment.NICNodes, node) {
+			alignment.NICNodes = append(alignment.NICNodes, node)
+		}
+	}
+
+	return alignment, nil
+}
+
+func readInt(runner commandRunner, filePath string) (int, error) {
+	output, err := runner.GetCommandOutput("cat " + filePath)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s %q: %w", filePath, output, err)
+	}
+	return value, nil
+}
+
+// parseList parses a kernel list format, such as "0-1,3".
+func parseList(list string) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(strings.TrimSpace(list), ",") {
+		first, last, isRange := strings.Cut(item, "-")
+		if !isRange {
+			last = first
+		}
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, err
+		}
+		end, err := strconv.Atoi(last)
+		if err != nil {
+			return nil, err
+		}
+		for value := start; value <= end; value++ {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func formatNodes(nodes []int) string {
+	if len(nodes) == 0 {
+		return "none"
+	}
+	formatted := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		formatted = append(formatted, strconv.Itoa(node))
+	}
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,92 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package numa_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/numa"
+)
+
+var pciAddresses = []string{"0000:06:00.0", "0000:07:00.0"}
+
+func TestReadShouldReportAlignedResources(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{
+		"cat /sys/devices/system/node/online":                                           "0",
+		"cat /sys/devices/system/node/node0/cpulist":                                    "0-7",
+		"cat /sys/devices/system/node/node0/hugepages/hugepages-1048576kB/nr_hugepages": "8",
+		"cat /sys/bus/pci/devices/0000:06:00.0/numa_node":                               "0",
+		"cat /sys/bus/pci/devices/0000:07:00.0/numa_node":                               "-1",
+	}}
+
+	alignment, err := numa.Read(runner, pciAddresses)
+	assert.NoError(t, err)
+	assert.Equal(t, numa.Alignment{CPUNodes: []int{0}, HugepagesNodes: []int{0}, NICNodes: []int{0}}, alignment)
+	assert.True(t, alignment.Aligned())
+	assert.Equal(t, "aligned on NUMA node 0", alignment.String())
+}
+
+func TestReadShouldReportMisalignedResources(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{
+		"cat /sys/devices/system/node/online":                                           "0-1",
+		"cat /sys/devices/system/node/node0/cpulist":                                    "0-3",
+		"cat /sys/devices/system/node/node0/hugepages/hugepages-1048576kB/nr_hugepages": "8",
+		"cat /sys/devices/system/node/node1/cpulist":                                    "4-7",
+		"cat /sys/devices/system/node/node1/hugepages/hugepages-1048576kB/nr_hugepages": "0",
+		"cat /sys/bus/pci/devices/0000:06:00.0/numa_node":                               "1",
+		"cat /sys/bus/pci/devices/0000:07:00.0/numa_node":                               "1",
+	}}
+
+	alignment, err := numa.Read(runner, pciAddresses)
+	assert.NoError(t, err)
+	assert.False(t, alignment.Aligned())
+	assert.Equal(t, "misaligned, vCPUs on NUMA nodes 0,1, hugepages on NUMA nodes 0, NICs on NUMA nodes 1", alignment.String())
+}
+
+func TestReadShouldFailWhenTheOutputIsUnexpected(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{
+		"cat /sys/devices/system/node/online": "No such file or directory",
+	}}
+
+	_, err := numa.Read(runner, pciAddresses)
+	assert.ErrorContains(t, err, "failed to parse the online NUMA nodes")
+}
+
+func TestReadShouldFailWhenACommandFails(t *testing.T) {
+	expectedErr := errors.New("console is not responding")
+
+	_, err := numa.Read(runnerStub{err: expectedErr}, pciAddresses)
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+type runnerStub struct {
+	outputs map[string]string
+	err     error
+}
+
+func (r runnerStub) GetCommandOutput(command string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.outputs[command], nil
+}
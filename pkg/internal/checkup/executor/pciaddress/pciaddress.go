@@ -0,0 +1,61 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package pciaddress reads the PCI addresses the guest boot script discovered the test NICs at, by their MAC addresses.
+package pciaddress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+type commandRunner interface {
+	GetCommandOutput(command string) (string, error)
+}
+
+var ErrNotFound = errors.New("NIC was not discovered in the guest")
+
+// Read reads the PCI addresses of the NICs with the given MAC addresses, in the same order, from the addresses file the
+// guest boot script keeps, a "<MAC address> <PCI address>" line per NIC.
+func Read(runner commandRunner, addressesFile string, macAddresses []net.HardwareAddr) ([]string, error) {
+	output, err := runner.GetCommandOutput("cat " + addressesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			discovered[strings.ToLower(fields[0])] = fields[1]
+		}
+	}
+
+	var pciAddresses []string
+	for _, macAddress := range macAddresses {
+		pciAddress, exists := discovered[macAddress.String()]
+		if !exists {
+			return nil, fmt.Errorf("%w: no NIC has MAC address %s", ErrNotFound, macAddress)
+		}
+		pciAddresses = append(pciAddresses, pciAddress)
+	}
+
+	return pciAddresses, nil
+}
@@ -0,0 +1,71 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package pciaddress_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/pciaddress"
+)
+
+const addressesFile = "/var/dpdk-checkup-pci-addresses"
+
+var macAddresses = []net.HardwareAddr{{0x60, 0, 0, 0, 0, 0x01}, {0x60, 0, 0, 0, 0, 0x02}}
+
+func TestReadShouldReturnTheAddressesInTheMACAddressesOrder(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{
+		"cat " + addressesFile: "60:00:00:00:00:02 0000:0a:00.0\r\n60:00:00:00:00:01 0000:09:00.0\r\n",
+	}}
+
+	pciAddresses, err := pciaddress.Read(runner, addressesFile, macAddresses)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0000:09:00.0", "0000:0a:00.0"}, pciAddresses)
+}
+
+func TestReadShouldFailWhenANICWasNotDiscovered(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{"cat " + addressesFile: "60:00:00:00:00:01 0000:09:00.0\n"}}
+
+	_, err := pciaddress.Read(runner, addressesFile, macAddresses)
+	assert.ErrorIs(t, err, pciaddress.ErrNotFound)
+	assert.ErrorContains(t, err, "no NIC has MAC address 60:00:00:00:00:02")
+}
+
+func TestReadShouldFailWhenTheCommandFails(t *testing.T) {
+	expectedErr := errors.New("console is not responding")
+
+	_, err := pciaddress.Read(runnerStub{err: expectedErr}, addressesFile, macAddresses)
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+type runnerStub struct {
+	outputs map[string]string
+	err     error
+}
+
+func (r runnerStub) GetCommandOutput(command string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.outputs[command], nil
+}
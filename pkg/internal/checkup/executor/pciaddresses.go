@@ -0,0 +1,67 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"slices"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/pciaddress"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+)
+
+// withGuestPCIAddresses returns the executor set with the PCI addresses each guest boot script discovered the test
+// NICs at, by their MAC addresses, as these are allocated by KubeVirt unless pinned.
+func (e Executor) withGuestPCIAddresses(vmUnderTestRunner, trafficGenRunner guestCommandRunner,
+	vmiUnderTestName, trafficGenVMIName string) (Executor, error) {
+	var err error
+	if e.vmUnderTestPCIAddresses, err = e.readGuestPCIAddresses(vmUnderTestRunner, e.vmUnderTestMACAddresses,
+		vmiUnderTestName); err != nil {
+		return Executor{}, err
+	}
+	if e.trafficGenPCIAddresses, err = e.readGuestPCIAddresses(trafficGenRunner, e.trafficGenMACAddresses,
+		trafficGenVMIName); err != nil {
+		return Executor{}, err
+	}
+
+	// The ports are copied, as their slices are shared with the executor the copy is made of.
+	e.testpmdPorts = slices.Clone(e.testpmdPorts)
+	for portIdx := range e.testpmdPorts {
+		e.testpmdPorts[portIdx].PCIAddress = e.vmUnderTestPCIAddresses[portIdx]
+	}
+	e.l3fwdPorts = slices.Clone(e.l3fwdPorts)
+	for portIdx := range e.l3fwdPorts {
+		e.l3fwdPorts[portIdx].PCIAddress = e.vmUnderTestPCIAddresses[portIdx]
+	}
+	return e, nil
+}
+
+func (e Executor) readGuestPCIAddresses(runner guestCommandRunner, macAddresses []net.HardwareAddr,
+	vmiName string) ([]string, error) {
+	pciAddresses, err := pciaddress.Read(runner, config.BootScriptPCIAddressesFileFullPath, macAddresses)
+	if err != nil {
+		return nil, exitcode.Classify(exitcode.SetupFailure, fmt.Errorf("VMI \"%s/%s\": %w", e.namespace, vmiName, err))
+	}
+	log.Printf("VMI \"%s/%s\" test NICs PCI addresses: %v", e.namespace, vmiName, pciAddresses)
+	return pciAddresses, nil
+}
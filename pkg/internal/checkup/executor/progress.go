@@ -0,0 +1,79 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"log"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+type progressReporter interface {
+	ReportProgress(status.Progress) error
+}
+
+// progressTracker reports the current phase of the run, and while the traffic runs its current rates,
+// at most once per interval. Reporting failures are logged and do not fail the checkup.
+type progressTracker struct {
+	reporter   progressReporter
+	interval   time.Duration
+	phase      string
+	lastReport time.Time
+}
+
+func newProgressTracker(reporter progressReporter, interval time.Duration) *progressTracker {
+	return &progressTracker{
+		reporter: reporter,
+		interval: interval,
+	}
+}
+
+// setPhase reports the new phase immediately.
+func (p *progressTracker) setPhase(phase string) {
+	p.phase = phase
+	p.report(status.Progress{Phase: phase})
+}
+
+// update reports the current rates, once the interval has passed since the last report.
+func (p *progressTracker) update(stats trafficgen.GlobalStats) {
+	if time.Since(p.lastReport) < p.interval {
+		return
+	}
+
+	p.report(status.Progress{
+		Phase:   p.phase,
+		TxPps:   stats.TxPps,
+		RxPps:   stats.RxPps,
+		DropBps: stats.RxDropBps,
+	})
+}
+
+func (p *progressTracker) report(progress status.Progress) {
+	if p.reporter == nil || p.interval == 0 {
+		return
+	}
+
+	if err := p.reporter.ReportProgress(progress); err != nil {
+		log.Printf("failed to report the progress: %v", err)
+	}
+	p.lastReport = time.Now()
+}
@@ -0,0 +1,138 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// promiscuousModeSetter is implemented by workloads that are able to toggle their ports promiscuous and all-multicast modes.
+type promiscuousModeSetter interface {
+	SetPromiscuousMode(promiscuous, allMulticast bool) error
+}
+
+// promiscuousMode is a run of the promiscuous scenario, sending multicast and broadcast frames with the VM under test
+// ports promiscuous mode set as given. The all-multicast mode is enabled in both runs, so all the frames are expected
+// to be forwarded, unless the VFs are not trusted to receive them, e.g. when the VF trust is off.
+type promiscuousMode struct {
+	id          string
+	promiscuous bool
+}
+
+var promiscuousModes = []promiscuousMode{
+	{id: status.PromiscuousOnScenarioID, promiscuous: true},
+	{id: status.PromiscuousOffScenarioID, promiscuous: false},
+}
+
+// promiscuousScenario runs the multicast and broadcast traffic once per promiscuous mode, in the east to west
+// direction, reporting the counters of each run as a scenario of its own.
+type promiscuousScenario struct {
+	Executor
+}
+
+func (s promiscuousScenario) Name() string {
+	return config.ScenarioPromiscuous
+}
+
+func (s promiscuousScenario) Run(ctx context.Context, env scenarioEnv, results *status.Results) error {
+	multicastSender, ok := env.trafficGenerator.(trafficgen.MulticastSender)
+	if !ok {
+		return fmt.Errorf("the %s traffic generator does not support sending multicast traffic", env.trafficGenerator.Name())
+	}
+	modeSetter, ok := env.vmUnderTestWorkload.(promiscuousModeSetter)
+	if !ok {
+		return fmt.Errorf("the %s workload does not support toggling the promiscuous mode", env.vmUnderTestWorkload.Name())
+	}
+
+	// The VM under test is migrated, and the soak snapshots are taken, during the unicast traffic only
+	s.migration = nil
+	s.snapshotInterval = 0
+
+	for _, mode := range promiscuousModes {
+		if s.abort.requested {
+			break
+		}
+		log.Printf("Running the %s traffic...", mode.id)
+		s.progress.setPhase(mode.id + " traffic")
+		if err := modeSetter.SetPromiscuousMode(mode.promiscuous, true); err != nil {
+			return fmt.Errorf("failed to set the %s promiscuous mode in VMI: %w", env.vmUnderTestWorkload.Name(), err)
+		}
+
+		counters, err := s.runMulticastTraffic(ctx, multicastSender, env.trafficGenerator, env.vmUnderTestWorkload,
+			env.trafficGenVMIName, env.timeline)
+		if err != nil {
+			return err
+		}
+		results.Scenarios = append(results.Scenarios, status.ScenarioResults{ID: mode.id, Counters: counters})
+	}
+
+	return nil
+}
+
+// runMulticastTraffic clears the stats on both sides, runs the multicast and broadcast traffic for the test duration
+// and collects the counters. Its rates are not sampled, so they do not skew the unicast traffic rates.
+func (e Executor) runMulticastTraffic(ctx context.Context,
+	multicastSender trafficgen.MulticastSender,
+	trafficGenerator trafficgen.TrafficGenerator,
+	vmUnderTestWorkload workload,
+	trafficGenVMIName string,
+	timeline *status.Timeline) (status.PacketCounters, error) {
+	if err := clearWorkloadStats(vmUnderTestWorkload); err != nil {
+		return status.PacketCounters{}, err
+	}
+	if err := clearTrafficGenStats(trafficGenerator); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+	}
+
+	trafficStart := time.Now()
+	sourcePorts := eastToWest.sourcePorts(len(e.testpmdPorts))
+	if err := multicastSender.StartMulticast(e.testDuration, sourcePorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("failed to run multicast traffic from traffic generator VMI \"%s/%s\" side: %w",
+			e.namespace, trafficGenVMIName, err)
+	}
+
+	if err := e.awaitTraffic(ctx, eastToWest, trafficGenerator, vmUnderTestWorkload, &throughputSampler{}); err != nil {
+		if ctx.Err() != nil {
+			e.stopTraffic(trafficGenerator, sourcePorts)
+		}
+		return status.PacketCounters{}, err
+	}
+	timeline.Record(e.progress.phase, trafficStart)
+
+	if err := trafficGenerator.Stop(sourcePorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("failed to stop multicast traffic from traffic generator VMI \"%s/%s\" side: %w",
+			e.namespace, trafficGenVMIName, err)
+	}
+	allPorts := slices.Concat(sourcePorts, eastToWest.destPorts(len(e.testpmdPorts)))
+	if err := trafficGenerator.WaitForCompletion(ctx, allPorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", e.namespace, trafficGenVMIName, err)
+	}
+
+	iteration, err := calculateStats(trafficGenerator, vmUnderTestWorkload, eastToWest, len(e.testpmdPorts))
+	return iteration.counters, err
+}
@@ -0,0 +1,153 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// rateSweepScenario sends the traffic in the east to west direction at step-wise increasing percentages of the line
+// rate, recording the loss at each rate. All the rates are run, so the loss versus rate curve is complete.
+// The scenario is informational, drops at the higher rates are expected and do not fail the checkup.
+type rateSweepScenario struct {
+	Executor
+}
+
+func (s rateSweepScenario) Name() string {
+	return config.ScenarioRateSweep
+}
+
+func (s rateSweepScenario) Run(ctx context.Context, env scenarioEnv, results *status.Results) error {
+	lineRateSender, ok := env.trafficGenerator.(trafficgen.LineRateSender)
+	if !ok {
+		return fmt.Errorf("the %s traffic generator does not support sending traffic at a line rate percentage",
+			env.trafficGenerator.Name())
+	}
+
+	// The VM under test is migrated, and the soak snapshots are taken, during the unicast traffic only
+	s.migration = nil
+	s.snapshotInterval = 0
+	s.testDuration = s.rateSweepStepDuration
+
+	rateSweep := &status.RateSweep{}
+	for _, percent := range rateSweepPercents(s.rateSweepSteps) {
+		if s.abort.requested {
+			break
+		}
+		log.Printf("Sending traffic at %d%% of the line rate...", percent)
+		s.progress.setPhase(fmt.Sprintf("rate sweep %d%% traffic", percent))
+		counters, err := s.runRate(ctx, lineRateSender, env, percent)
+		if err != nil {
+			return err
+		}
+		// The rates of a step cut short by an abort cannot be told
+		if s.abort.requested {
+			break
+		}
+
+		step := newRateSweepStep(percent, counters, s.rateSweepStepDuration)
+		log.Printf("%d%% of the line rate: tx %.0f pps, rx %.0f pps, lost %d packets (%.2f%%)",
+			percent, step.TxPps, step.RxPps, step.LostPackets, step.LossPercent)
+		rateSweep.Steps = append(rateSweep.Steps, step)
+
+		if rateSweep.FirstDroppingPercent == 0 && hasDrops(counters) {
+			rateSweep.FirstDroppingPercent = percent
+		}
+	}
+
+	results.RateSweep = rateSweep
+	return nil
+}
+
+// rateSweepPercents returns the line rate percentages of the given number of steps, evenly spread up to the full line rate.
+func rateSweepPercents(steps int) []int {
+	const fullLineRatePercent = 100
+	var percents []int
+	for step := 1; step <= steps; step++ {
+		percents = append(percents, fullLineRatePercent*step/steps)
+	}
+	return percents
+}
+
+// newRateSweepStep derives the average rates and the loss of a rate sweep step from its counters.
+func newRateSweepStep(percent int, counters status.PacketCounters, duration time.Duration) status.RateSweepStep {
+	lost := counters.TrafficGenSentPackets - counters.VMUnderTestReceivedPackets
+	lost = max(lost, 0) + counters.VMUnderTestRxDroppedPackets + counters.VMUnderTestTxDroppedPackets
+
+	step := status.RateSweepStep{
+		LineRatePercent: percent,
+		TxPps:           float64(counters.TrafficGenSentPackets) / duration.Seconds(),
+		RxPps:           float64(counters.VMUnderTestReceivedPackets) / duration.Seconds(),
+		SentPackets:     counters.TrafficGenSentPackets,
+		LostPackets:     lost,
+	}
+	if counters.TrafficGenSentPackets > 0 {
+		const percentMultiplier = 100
+		step.LossPercent = float64(lost) * percentMultiplier / float64(counters.TrafficGenSentPackets)
+	}
+	return step
+}
+
+// runRate clears the stats on both sides, runs the traffic at the given line rate percentage for the step duration
+// and collects the counters. Its rates are not sampled, so they do not skew the unicast traffic rates.
+func (s rateSweepScenario) runRate(ctx context.Context, lineRateSender trafficgen.LineRateSender, env scenarioEnv,
+	percent int) (status.PacketCounters, error) {
+	if err := clearWorkloadStats(env.vmUnderTestWorkload); err != nil {
+		return status.PacketCounters{}, err
+	}
+	if err := clearTrafficGenStats(env.trafficGenerator); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", s.namespace, env.trafficGenVMIName, err)
+	}
+
+	trafficStart := time.Now()
+	sourcePorts := eastToWest.sourcePorts(len(s.testpmdPorts))
+	if err := lineRateSender.StartAtLineRatePercent(percent, s.testDuration, sourcePorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("failed to run traffic at %d%% of the line rate "+
+			"from traffic generator VMI \"%s/%s\" side: %w", percent, s.namespace, env.trafficGenVMIName, err)
+	}
+
+	if err := s.awaitTraffic(ctx, eastToWest, env.trafficGenerator, env.vmUnderTestWorkload, &throughputSampler{}); err != nil {
+		if ctx.Err() != nil {
+			s.stopTraffic(env.trafficGenerator, sourcePorts)
+		}
+		return status.PacketCounters{}, err
+	}
+	env.timeline.Record(s.progress.phase, trafficStart)
+
+	if err := env.trafficGenerator.Stop(sourcePorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("failed to stop traffic from traffic generator VMI \"%s/%s\" side: %w",
+			s.namespace, env.trafficGenVMIName, err)
+	}
+	allPorts := slices.Concat(sourcePorts, eastToWest.destPorts(len(s.testpmdPorts)))
+	if err := env.trafficGenerator.WaitForCompletion(ctx, allPorts...); err != nil {
+		return status.PacketCounters{}, fmt.Errorf("traffic generator VMI \"%s/%s\": %w", s.namespace, env.trafficGenVMIName, err)
+	}
+
+	iteration, err := calculateStats(env.trafficGenerator, env.vmUnderTestWorkload, eastToWest, len(s.testpmdPorts))
+	return iteration.counters, err
+}
@@ -0,0 +1,98 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package repro
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+)
+
+// FileName is the name of the script, in the artifacts directory.
+const FileName = "repro.sh"
+
+// VMI is a VMI the checkup has used, along with the commands it has run on its console, in order.
+type VMI struct {
+	Spec     *kvcorev1.VirtualMachineInstance
+	Commands []string
+}
+
+// Script replays a failed checkup run: it recreates the VMIs unless they were preserved,
+// and prints the commands to run on their consoles.
+type Script struct {
+	Namespace string
+	Failure   error
+	VMIs      []VMI
+}
+
+// Render returns the script content.
+func (s Script) Render() (string, error) {
+	sb := strings.Builder{}
+	sb.WriteString("#!/usr/bin/env bash\n")
+	sb.WriteString("# Replays a failed kubevirt-dpdk-checkup run.\n")
+	if s.Failure != nil {
+		sb.WriteString(fmt.Sprintf("# Failure: %s\n", strings.ReplaceAll(s.Failure.Error(), "\n", " ")))
+	}
+	sb.WriteString("#\n")
+	sb.WriteString("# The VMIs are created unless they were preserved, then the commands the checkup has run on their consoles\n")
+	sb.WriteString("# are printed, in order, to be run from `virtctl console`.\n")
+	sb.WriteString("set -euo pipefail\n\n")
+	sb.WriteString(fmt.Sprintf("NAMESPACE=\"${NAMESPACE:-%s}\"\n", s.Namespace))
+
+	for _, vmi := range s.VMIs {
+		manifest, err := yaml.Marshal(replayableVMI(vmi.Spec))
+		if err != nil {
+			return "", fmt.Errorf("failed to render VMI %q: %w", vmi.Spec.Name, err)
+		}
+
+		sb.WriteString(fmt.Sprintf("\nif ! kubectl get vmi -n \"${NAMESPACE}\" %s >/dev/null 2>&1; then\n", vmi.Spec.Name))
+		sb.WriteString("  kubectl create -n \"${NAMESPACE}\" -f - <<'EOF_VMI'\n")
+		sb.Write(manifest)
+		sb.WriteString("EOF_VMI\nfi\n")
+	}
+
+	for _, vmi := range s.VMIs {
+		sb.WriteString(fmt.Sprintf("\necho \"==== virtctl console -n ${NAMESPACE} %s ====\"\n", vmi.Spec.Name))
+		sb.WriteString("cat <<'EOF_COMMANDS'\n")
+		for _, command := range vmi.Commands {
+			sb.WriteString(command + "\n")
+		}
+		sb.WriteString("EOF_COMMANDS\n")
+	}
+
+	return sb.String(), nil
+}
+
+// replayableVMI strips the VMI from its status and from the metadata set by the cluster.
+// The owner references are dropped as well, as the owner is likely gone by the time the script is run.
+func replayableVMI(vmi *kvcorev1.VirtualMachineInstance) *kvcorev1.VirtualMachineInstance {
+	replayable := &kvcorev1.VirtualMachineInstance{
+		Spec: vmi.Spec,
+	}
+	replayable.APIVersion = kvcorev1.GroupVersion.String()
+	replayable.Kind = kvcorev1.VirtualMachineInstanceGroupVersionKind.Kind
+	replayable.Name = vmi.Name
+	replayable.Labels = vmi.Labels
+	replayable.Annotations = vmi.Annotations
+	return replayable
+}
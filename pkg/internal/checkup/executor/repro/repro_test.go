@@ -0,0 +1,79 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package repro_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/repro"
+)
+
+const (
+	testNamespace       = "target-ns"
+	testVMIUnderTest    = "dpdk-vmi-under-test"
+	testpmdCommand      = "dpdk-testpmd -a 0000:06:00.0 -a 0000:07:00.0 -n 4 -- -i; start"
+	testOwnerPodName    = "dpdk-checkup-pod"
+	testVMIUnderTestCPU = 8
+)
+
+func TestRenderShouldReplayTheVMIsAndTheirCommands(t *testing.T) {
+	vmi := &kvcorev1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            testVMIUnderTest,
+			Namespace:       testNamespace,
+			Labels:          map[string]string{"kiagnose/checkup-uid": "0123456789"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Pod", Name: testOwnerPodName}},
+			ResourceVersion: "1234",
+		},
+		Spec: kvcorev1.VirtualMachineInstanceSpec{
+			Domain: kvcorev1.DomainSpec{CPU: &kvcorev1.CPU{Cores: testVMIUnderTestCPU}},
+		},
+		Status: kvcorev1.VirtualMachineInstanceStatus{NodeName: "worker1"},
+	}
+
+	script := repro.Script{
+		Namespace: testNamespace,
+		Failure:   errors.New("testpmd stopped\nresponding"),
+		VMIs:      []repro.VMI{{Spec: vmi, Commands: []string{"<login as root>", testpmdCommand}}},
+	}
+
+	content, err := script.Render()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(content, "#!/usr/bin/env bash\n"))
+	assert.Contains(t, content, "# Failure: testpmd stopped responding\n")
+	assert.Contains(t, content, `NAMESPACE="${NAMESPACE:-target-ns}"`)
+	assert.Contains(t, content, `kubectl get vmi -n "${NAMESPACE}" dpdk-vmi-under-test`)
+	assert.Contains(t, content, "kind: VirtualMachineInstance\n")
+	assert.Contains(t, content, "cores: 8\n")
+	assert.Contains(t, content, "kiagnose/checkup-uid: \"0123456789\"\n")
+	assert.NotContains(t, content, testOwnerPodName)
+	assert.NotContains(t, content, "resourceVersion")
+	assert.NotContains(t, content, "worker1")
+	assert.Contains(t, content, "cat <<'EOF_COMMANDS'\n<login as root>\n"+testpmdCommand+"\nEOF_COMMANDS\n")
+}
@@ -0,0 +1,171 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// scenarioEnv is what the scenarios run against, once the traffic generator and the VM under test workload are ready.
+type scenarioEnv struct {
+	trafficGenerator    trafficgen.TrafficGenerator
+	vmUnderTestWorkload workload
+	trafficGenVMIName   string
+	timeline            *status.Timeline
+}
+
+// scenario is a named phase of the checkup traffic, contributing its own section of the results.
+type scenario interface {
+	Name() string
+	Run(ctx context.Context, env scenarioEnv, results *status.Results) error
+}
+
+// scenarioFactories maps the names of the scenarios, as selected by the scenarios param, to their factories.
+// A new test type is added by registering its scenario.
+var scenarioFactories = map[string]func(e Executor) scenario{
+	config.ScenarioThroughput:  func(e Executor) scenario { return throughputScenario{Executor: e} },
+	config.ScenarioPromiscuous: func(e Executor) scenario { return promiscuousScenario{Executor: e} },
+	config.ScenarioBurst:       func(e Executor) scenario { return burstScenario{Executor: e} },
+	config.ScenarioRateSweep:   func(e Executor) scenario { return rateSweepScenario{Executor: e} },
+}
+
+// runScenarios runs the selected scenarios in order, each contributing its own section of the results.
+func (e Executor) runScenarios(ctx context.Context, env scenarioEnv) (status.Results, error) {
+	var results status.Results
+	for _, name := range e.scenarios {
+		if e.abort.requested {
+			log.Printf("The run was aborted, skipping the %s scenario", name)
+			continue
+		}
+		s := scenarioFactories[name](e)
+		log.Printf("Running the %s scenario...", s.Name())
+		if err := s.Run(ctx, env, &results); err != nil {
+			return status.Results{}, err
+		}
+	}
+
+	// Once other scenarios are reported, the unicast traffic is reported as a scenario too, so it is verified alongside them.
+	// Per-direction runs report the unicast traffic of each direction as a scenario already.
+	if len(results.Scenarios) > 0 && results.TrafficGenSentPackets > 0 && !e.perDirectionRuns {
+		results.Scenarios = append([]status.ScenarioResults{unicastScenario(results)}, results.Scenarios...)
+	}
+
+	return results, nil
+}
+
+// unicastScenario reports the counters of the unicast traffic as a scenario.
+func unicastScenario(results status.Results) status.ScenarioResults {
+	return status.ScenarioResults{
+		ID: status.UnicastScenarioID,
+		Counters: status.PacketCounters{
+			TrafficGenSentPackets:        results.TrafficGenSentPackets,
+			TrafficGenOutputErrorPackets: results.TrafficGenOutputErrorPackets,
+			TrafficGenInputErrorPackets:  results.TrafficGenInputErrorPackets,
+			VMUnderTestReceivedPackets:   results.VMUnderTestReceivedPackets,
+			VMUnderTestRxDroppedPackets:  results.VMUnderTestRxDroppedPackets,
+			VMUnderTestTxDroppedPackets:  results.VMUnderTestTxDroppedPackets,
+		},
+	}
+}
+
+// throughputScenario runs the unicast traffic for the configured iterations, optionally in each direction separately.
+// Its counters, rates and stats are the top-level results.
+type throughputScenario struct {
+	Executor
+}
+
+func (s throughputScenario) Name() string {
+	return config.ScenarioThroughput
+}
+
+func (s throughputScenario) Run(ctx context.Context, env scenarioEnv, results *status.Results) error {
+	var rates throughputSampler
+	var throughput status.Results
+	var iterations []iterationStats
+	var err error
+	if s.perDirectionRuns {
+		throughput, iterations, err = s.runPerDirectionTraffic(ctx, env.trafficGenerator, env.vmUnderTestWorkload,
+			env.trafficGenVMIName, &rates, env.timeline)
+	} else {
+		iterations, err = s.runTrafficIterations(ctx, eastToWest, env.trafficGenerator, env.vmUnderTestWorkload,
+			env.trafficGenVMIName, &rates, env.timeline)
+		throughput = aggregateIterations(iterationsCounters(iterations))
+	}
+	if err != nil {
+		return err
+	}
+
+	rates.apply(&throughput)
+	// An aborted run has transmitted for less than the test duration on purpose
+	if !rates.aborted() && !s.abort.requested {
+		throughput.TxDuration = txDuration(s.testDuration*time.Duration(len(iterations)), throughput.TrafficGenSentPackets,
+			throughput.TrafficGenAvgTxPps)
+	}
+	for _, iteration := range iterations {
+		throughput.StatsReads = append(throughput.StatsReads, iteration.read)
+	}
+	throughput.StatsSkewWarning = statsSkewWarning(throughput.StatsReads)
+	throughput.PerQueueStats = sumQueueStats(iterations)
+	throughput.PerStreamStats = sumStreamStats(iterations)
+	if s.artifactsDir != "" && len(rates.samples) > 0 {
+		exportSamples(s.artifactsDir, rates.samples)
+	}
+
+	mergeThroughputResults(results, &throughput)
+	return nil
+}
+
+// mergeThroughputResults copies the sections of the throughput scenario into the results, keeping the sections of the
+// scenarios that ran before it, as the scenarios may be listed in any order.
+func mergeThroughputResults(results, throughput *status.Results) {
+	results.TrafficGenSentPackets = throughput.TrafficGenSentPackets
+	results.TrafficGenOutputErrorPackets = throughput.TrafficGenOutputErrorPackets
+	results.TrafficGenInputErrorPackets = throughput.TrafficGenInputErrorPackets
+	results.VMUnderTestReceivedPackets = throughput.VMUnderTestReceivedPackets
+	results.VMUnderTestRxDroppedPackets = throughput.VMUnderTestRxDroppedPackets
+	results.VMUnderTestTxDroppedPackets = throughput.VMUnderTestTxDroppedPackets
+
+	results.TrafficGenAvgTxPps = throughput.TrafficGenAvgTxPps
+	results.TrafficGenMaxTxPps = throughput.TrafficGenMaxTxPps
+	results.TrafficGenAvgRxPps = throughput.TrafficGenAvgRxPps
+	results.TrafficGenMaxRxPps = throughput.TrafficGenMaxRxPps
+	results.TrafficGenAvgTxBps = throughput.TrafficGenAvgTxBps
+	results.TrafficGenMaxTxBps = throughput.TrafficGenMaxTxBps
+	results.TrafficGenAvgRxBps = throughput.TrafficGenAvgRxBps
+	results.TrafficGenMaxRxBps = throughput.TrafficGenMaxRxBps
+	results.MaxDropRateBps = throughput.MaxDropRateBps
+	results.Soak = throughput.Soak
+	results.TxDuration = throughput.TxDuration
+
+	results.Iterations = throughput.Iterations
+	results.IterationsSummary = throughput.IterationsSummary
+	results.StatsReads = throughput.StatsReads
+	results.StatsSkewWarning = throughput.StatsSkewWarning
+	results.PerQueueStats = throughput.PerQueueStats
+	results.PerStreamStats = throughput.PerStreamStats
+
+	results.Scenarios = append(results.Scenarios, throughput.Scenarios...)
+}
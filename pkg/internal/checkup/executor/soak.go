@@ -0,0 +1,119 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// soakDropGrowthSnapshots is the number of consecutive snapshots the VM under test dropped packets should grow in,
+// for the drops to be considered steady rather than a transient hiccup.
+const soakDropGrowthSnapshots = 3
+
+// soakMonitor snapshots the counters of both sides every snapshot interval during long traffic runs, and tells when
+// the traffic should be aborted, as it already cannot pass, rather than waiting hours for the end of the test duration.
+type soakMonitor struct {
+	interval     time.Duration
+	phase        string
+	start        time.Time
+	readCounters func() (migrationCounters, error)
+	workload     workload
+	soak         *status.Soak
+	previous     status.SoakSnapshot
+	dropGrowths  int
+}
+
+func newSoakMonitor(interval time.Duration,
+	phase string,
+	readCounters func() (migrationCounters, error),
+	vmUnderTestWorkload workload,
+	soak *status.Soak) *soakMonitor {
+	now := time.Now()
+	return &soakMonitor{
+		interval:     interval,
+		phase:        phase,
+		start:        now,
+		readCounters: readCounters,
+		workload:     vmUnderTestWorkload,
+		soak:         soak,
+		previous:     status.SoakSnapshot{Phase: phase},
+	}
+}
+
+// due tells whether the next snapshot should be taken.
+func (m *soakMonitor) due() bool {
+	elapsed := time.Since(m.start).Seconds()
+	return elapsed-m.previous.ElapsedSeconds >= m.interval.Seconds()
+}
+
+// take snapshots the counters and returns whether the traffic should be aborted. The counters are compared to the
+// previous snapshot, the stats being cleared before the traffic starts. Failing to read the counters skips the snapshot.
+func (m *soakMonitor) take() bool {
+	elapsed := time.Since(m.start).Seconds()
+	counters, err := m.readCounters()
+	if err != nil {
+		log.Printf("failed to snapshot the traffic generator counters: %v", err)
+		return false
+	}
+	stats, err := m.workload.GetStats()
+	if err != nil {
+		log.Printf("failed to snapshot the %s counters: %v", m.workload.Name(), err)
+		return false
+	}
+
+	snapshot := status.SoakSnapshot{
+		Phase:                     m.phase,
+		ElapsedSeconds:            elapsed,
+		TrafficGenSentPackets:     counters.sent,
+		TrafficGenReceivedPackets: counters.received,
+		VMUnderTestDroppedPackets: stats.Summary.RXDropped + stats.Summary.TXDropped,
+	}
+	m.soak.Snapshots = append(m.soak.Snapshots, snapshot)
+	log.Printf("snapshot at %.0fs: sent %d, received %d, VM under test dropped %d", snapshot.ElapsedSeconds,
+		snapshot.TrafficGenSentPackets, snapshot.TrafficGenReceivedPackets, snapshot.VMUnderTestDroppedPackets)
+
+	if snapshot.VMUnderTestDroppedPackets > m.previous.VMUnderTestDroppedPackets {
+		m.dropGrowths++
+	} else {
+		m.dropGrowths = 0
+	}
+	previous := m.previous
+	m.previous = snapshot
+
+	switch {
+	case snapshot.TrafficGenSentPackets == previous.TrafficGenSentPackets:
+		m.soak.AbortReason = fmt.Sprintf("the traffic generator sent packets stalled at %d", snapshot.TrafficGenSentPackets)
+	case snapshot.TrafficGenReceivedPackets == previous.TrafficGenReceivedPackets:
+		m.soak.AbortReason = fmt.Sprintf("the traffic generator received packets stalled at %d", snapshot.TrafficGenReceivedPackets)
+	case m.dropGrowths >= soakDropGrowthSnapshots:
+		m.soak.AbortReason = fmt.Sprintf("the VM under test dropped packets grew in %d consecutive snapshots, to %d",
+			m.dropGrowths, snapshot.VMUnderTestDroppedPackets)
+	default:
+		return false
+	}
+
+	m.soak.AbortReason = fmt.Sprintf("%s, %.0fs into the %s", m.soak.AbortReason, elapsed, m.phase)
+	log.Printf("aborting the traffic: %s", m.soak.AbortReason)
+	return true
+}
@@ -0,0 +1,86 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+)
+
+// clearedStatsMaxPackets is the number of packets the counters may hold right after they are cleared,
+// e.g. due to control plane traffic arriving in between the clear and the read back.
+const clearedStatsMaxPackets = 100
+
+// clearStatsConfirmed runs the clear function and reads the counters back, retrying once when they were not cleared.
+// Otherwise, the final counters may include pre-run noise and produce false failures.
+func clearStatsConfirmed(side string, clear func() error, readCounters func() (int64, error)) error {
+	const maxAttempts = 2
+
+	var counters int64
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := clear(); err != nil {
+			return fmt.Errorf("failed to clear %s stats: %w", side, err)
+		}
+
+		var err error
+		if counters, err = readCounters(); err != nil {
+			return fmt.Errorf("failed to read back %s stats after clearing them: %w", side, err)
+		}
+
+		if counters <= clearedStatsMaxPackets {
+			return nil
+		}
+
+		log.Printf("%s stats were not cleared (attempt %d/%d): %d packets are still counted",
+			side, attempt, maxAttempts, counters)
+	}
+
+	return fmt.Errorf("%s stats were not cleared: %d packets are still counted", side, counters)
+}
+
+func clearWorkloadStats(vmUnderTestWorkload workload) error {
+	return clearStatsConfirmed(vmUnderTestWorkload.Name(), vmUnderTestWorkload.ClearStats, func() (int64, error) {
+		stats, err := vmUnderTestWorkload.GetStats()
+		if err != nil {
+			return 0, err
+		}
+
+		summary := stats.Summary
+		return summary.RXTotal + summary.TXTotal, nil
+	})
+}
+
+func clearTrafficGenStats(trafficGenerator trafficgen.TrafficGenerator) error {
+	return clearStatsConfirmed(trafficGenerator.Name(), trafficGenerator.ClearStats, func() (int64, error) {
+		srcPortStats, err := trafficGenerator.PortStats(trafficgen.SourcePort)
+		if err != nil {
+			return 0, err
+		}
+
+		dstPortStats, err := trafficGenerator.PortStats(trafficgen.DestPort)
+		if err != nil {
+			return 0, err
+		}
+
+		return srcPortStats.OutputPackets + dstPortStats.InputPackets, nil
+	})
+}
@@ -0,0 +1,109 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package telemetry
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ScriptName is the DPDK telemetry client, querying the telemetry socket of the DPDK application running in the guest.
+const ScriptName = "dpdk-telemetry.py"
+
+type PortStats struct {
+	RXPackets int64
+	RXDropped int64
+	RXTotal   int64
+	TXPackets int64
+	TXDropped int64
+	TXTotal   int64
+}
+
+var portStatsReply = regexp.MustCompile(`\{"/ethdev/stats": \{([^{}]*)\}\}`)
+
+// PortStatsCommand returns the shell command querying the statistics of the given number of ports, ordered by the port index.
+func PortStatsCommand(portsCount int) string {
+	var queries []string
+	for portIdx := 0; portIdx < portsCount; portIdx++ {
+		queries = append(queries, fmt.Sprintf(`/ethdev/stats,%d\n`, portIdx))
+	}
+	return fmt.Sprintf("printf '%s' | %s", strings.Join(queries, ""), ScriptName)
+}
+
+// ParsePortStats parses the replies to the port statistics queries, ordered by the port index, e.g.
+// {"/ethdev/stats": {"ipackets": 100, "opackets": 90, "imissed": 1, "ierrors": 0, "oerrors": 2, "rx_nombuf": 0, ...}}
+func ParsePortStats(output string, portsCount int) ([]PortStats, error) {
+	replies := portStatsReply.FindAllStringSubmatch(output, -1)
+	if len(replies) != portsCount {
+		return nil, fmt.Errorf("expected the statistics of %d ports, found %d", portsCount, len(replies))
+	}
+
+	stats := make([]PortStats, portsCount)
+	for portIdx, reply := range replies {
+		counters, err := parseCounters(reply[1], "ipackets", "opackets", "imissed", "ierrors", "oerrors", "rx_nombuf")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the statistics of port %d: %w", portIdx, err)
+		}
+
+		stats[portIdx] = PortStats{
+			RXPackets: counters["ipackets"],
+			RXDropped: counters["imissed"] + counters["rx_nombuf"],
+			TXPackets: counters["opackets"],
+			TXDropped: counters["oerrors"],
+		}
+		stats[portIdx].RXTotal = stats[portIdx].RXPackets + stats[portIdx].RXDropped + counters["ierrors"]
+		stats[portIdx].TXTotal = stats[portIdx].TXPackets + stats[portIdx].TXDropped
+	}
+
+	return stats, nil
+}
+
+func parseCounters(reply string, names ...string) (map[string]int64, error) {
+	counters := map[string]int64{}
+	for _, name := range names {
+		counter := regexp.MustCompile(fmt.Sprintf(`"%s": (\d+)`, name)).FindStringSubmatch(reply)
+		if counter == nil {
+			return nil, fmt.Errorf("counter %q is missing", name)
+		}
+
+		value, err := strconv.ParseInt(counter[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		counters[name] = value
+	}
+
+	return counters, nil
+}
+
+// Subtract returns the statistics relative to the given baseline.
+// The telemetry cannot reset the port statistics, so clearing them is done by taking a baseline.
+func Subtract(stats, baseline PortStats) PortStats {
+	return PortStats{
+		RXPackets: stats.RXPackets - baseline.RXPackets,
+		RXDropped: stats.RXDropped - baseline.RXDropped,
+		RXTotal:   stats.RXTotal - baseline.RXTotal,
+		TXPackets: stats.TXPackets - baseline.TXPackets,
+		TXDropped: stats.TXDropped - baseline.TXDropped,
+		TXTotal:   stats.TXTotal - baseline.TXTotal,
+	}
+}
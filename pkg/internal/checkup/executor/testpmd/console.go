@@ -22,11 +22,14 @@ package testpmd
 import (
 	"fmt"
 	"log"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	expect "github.com/google/goexpect"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/telemetry"
 )
 
 type consoleExpecter interface {
@@ -34,12 +37,43 @@ type consoleExpecter interface {
 }
 
 type TestpmdConsole struct {
-	consoleExpecter          consoleExpecter
-	vmiEastNICPCIAddress     string
-	vmiEastEthPeerMACAddress string
-	vmiWestNICPCIAddress     string
-	vmiWestEthPeerMACAddress string
-	verbosePrintsEnabled     bool
+	consoleExpecter      consoleExpecter
+	binaryPath           string
+	ports                []Port
+	forwardMode          ForwardMode
+	tuning               Tuning
+	vlanID               int
+	verbosePrintsEnabled bool
+	runGuestCommand      func(command string) (string, error)
+	telemetryBaseline    []telemetry.PortStats
+}
+
+// ForwardMode is the way testpmd forwards the packets it receives on a port, through its paired port.
+type ForwardMode string
+
+const (
+	// ForwardModeMAC sends the packets to the Ethernet peer of the port.
+	ForwardModeMAC ForwardMode = "mac"
+	// ForwardMode5TupleSwap swaps the MAC and IP addresses and the UDP ports of the packets,
+	// sending them back to the hop they were received from, e.g. a gateway routing them.
+	ForwardMode5TupleSwap ForwardMode = "5tswap"
+)
+
+// Tuning holds the testpmd memory and CPU settings. Zero values leave the defaults in place.
+// When set, the first of the Cores runs the testpmd main lcore and the rest forward the traffic.
+// InMemory keeps the testpmd hugepages and runtime files in memory rather than on the filesystem (`--in-memory`).
+type Tuning struct {
+	MbufSize       int
+	TotalNumMbufs  int
+	MemoryChannels int
+	Cores          []int
+	InMemory       bool
+}
+
+// Port is a testpmd port, forwarding the traffic it receives from its paired port to its Ethernet peer.
+type Port struct {
+	PCIAddress        string
+	EthPeerMACAddress string
 }
 
 type PortStats struct {
@@ -51,60 +85,113 @@ type PortStats struct {
 	TXTotal   int64
 }
 
-type StatsIdx int
+// QueueStats holds the forward statistics of a single forwarding stream, from an RX queue to a TX queue.
+type QueueStats struct {
+	RXPort    int
+	RXQueue   int
+	TXPort    int
+	TXQueue   int
+	RXPackets int64
+	TXPackets int64
+	TXDropped int64
+}
 
-const (
-	StatsPort0 StatsIdx = iota
-	StatsPort1
-	StatsSummary
-	StatsArraySize
-)
+// Stats holds the forward statistics of each port, and their accumulation over all the ports.
+// When the ports have several queues, the statistics of each forwarding stream are held as well.
+type Stats struct {
+	Ports   []PortStats
+	Summary PortStats
+	Queues  []QueueStats
+}
+
+// e.g. "------- Forward Stats for RX Port= 0/Queue= 1 -> TX Port= 1/Queue= 1 -------"
+// followed by "RX-packets: 80000000       TX-packets: 80000000       TX-dropped: 0"
+var queueStatsRegex = regexp.MustCompile(
+	`Forward Stats for RX Port=\s*(\d+)/Queue=\s*(\d+) -> TX Port=\s*(\d+)/Queue=\s*(\d+)\s*-+\s*` +
+		`RX-packets:\s*(\d+)\s+TX-packets:\s*(\d+)\s+TX-dropped:\s*(\d+)`)
 
 const testpmdPrompt = "testpmd> "
 
 func NewTestpmdConsole(vmiUnderTestConsoleExpecter consoleExpecter,
-	vmiUnderTestEastNICPCIAddress,
-	trafficGenEastMACAddress,
-	vmiUnderTestWestNICPCIAddress,
-	trafficGenWestMACAddress string,
+	binaryPath string,
+	ports []Port,
+	forwardMode ForwardMode,
+	tuning Tuning,
+	vlanID int,
 	verbosePrintsEnabled bool) *TestpmdConsole {
 	return &TestpmdConsole{
-		consoleExpecter:          vmiUnderTestConsoleExpecter,
-		vmiEastEthPeerMACAddress: trafficGenEastMACAddress,
-		vmiWestEthPeerMACAddress: trafficGenWestMACAddress,
-		vmiEastNICPCIAddress:     vmiUnderTestEastNICPCIAddress,
-		vmiWestNICPCIAddress:     vmiUnderTestWestNICPCIAddress,
-		verbosePrintsEnabled:     verbosePrintsEnabled,
+		consoleExpecter:      vmiUnderTestConsoleExpecter,
+		binaryPath:           binaryPath,
+		ports:                ports,
+		forwardMode:          forwardMode,
+		tuning:               tuning,
+		vlanID:               vlanID,
+		verbosePrintsEnabled: verbosePrintsEnabled,
 	}
 }
 
+// WithTelemetry returns the console reading the port statistics using the DPDK telemetry rather than scraping the
+// testpmd forward statistics. As testpmd occupies the console, the telemetry is queried by the given guest command
+// runner, aside the console.
+// The forward statistics are still read when the telemetry is unavailable, e.g. when the guest lacks the telemetry script.
+func (t *TestpmdConsole) WithTelemetry(runGuestCommand func(command string) (string, error)) *TestpmdConsole {
+	t.runGuestCommand = runGuestCommand
+	return t
+}
+
 func (t TestpmdConsole) Run() error {
 	const batchTimeout = 30 * time.Second
 
-	testpmdCmd := buildTestpmdCmd(t.vmiEastNICPCIAddress, t.vmiWestNICPCIAddress, t.vmiEastEthPeerMACAddress, t.vmiWestEthPeerMACAddress)
+	testpmdCmd := t.Command()
 
-	resp, err := t.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+	batch := []expect.Batcher{
 		&expect.BSnd{S: testpmdCmd + "\n"},
 		&expect.BExp{R: testpmdPrompt},
+	}
+	if t.vlanID != 0 {
+		// Tagged traffic should be forwarded as is, regardless of the VLANs configured on the ports
+		batch = append(batch, vlanPassthroughBatch(len(t.ports))...)
+	}
+	batch = append(batch,
 		&expect.BSnd{S: "start" + "\n"},
 		&expect.BExp{R: testpmdPrompt},
-	},
-		batchTimeout,
 	)
 
+	resp, err := t.consoleExpecter.SafeExpectBatchWithResponse(batch, batchTimeout)
+
 	if err != nil {
 		return err
 	}
 
 	if t.verbosePrintsEnabled {
 		log.Printf("testpmd run:\n%s", resp[0].Output)
-		log.Printf("testpmd start:\n%s", resp[1].Output)
+		log.Printf("testpmd start:\n%s", resp[len(resp)-1].Output)
 	}
 
 	return nil
 }
 
-func (t TestpmdConsole) ClearStats() error {
+func vlanPassthroughBatch(portsCount int) []expect.Batcher {
+	var batch []expect.Batcher
+	for portIdx := 0; portIdx < portsCount; portIdx++ {
+		batch = append(batch,
+			&expect.BSnd{S: fmt.Sprintf("vlan set filter off %d\n", portIdx)},
+			&expect.BExp{R: testpmdPrompt},
+			&expect.BSnd{S: fmt.Sprintf("vlan set strip off %d\n", portIdx)},
+			&expect.BExp{R: testpmdPrompt},
+		)
+	}
+	return batch
+}
+
+// Command returns the command line testpmd is started with.
+func (t TestpmdConsole) Command() string {
+	return buildTestpmdCmd(t.binaryPath, t.ports, t.forwardMode, t.tuning)
+}
+
+// ClearStats clears the forward statistics.
+// As the telemetry cannot reset the port statistics, it also takes a telemetry baseline they are reported relative to.
+func (t *TestpmdConsole) ClearStats() error {
 	const batchTimeout = 30 * time.Second
 
 	const testpmdCmd = "clear fwd stats all"
@@ -120,10 +207,107 @@ func (t TestpmdConsole) ClearStats() error {
 		return err
 	}
 
+	t.telemetryBaseline = nil
+	if t.runGuestCommand != nil {
+		baseline, err := t.readTelemetryStats()
+		if err != nil {
+			log.Printf("testpmd telemetry is unavailable, falling back to the forward stats: %v", err)
+			return nil
+		}
+		t.telemetryBaseline = baseline
+	}
+
 	return nil
 }
 
-func (t TestpmdConsole) GetStats() ([StatsArraySize]PortStats, error) {
+// KeepAlive pokes the testpmd console, to keep it from being dropped while it is idle during the traffic run.
+func (t TestpmdConsole) KeepAlive() error {
+	const batchTimeout = 10 * time.Second
+
+	_, err := t.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+		&expect.BSnd{S: "\n"},
+		&expect.BExp{R: testpmdPrompt},
+	},
+		batchTimeout,
+	)
+
+	return err
+}
+
+// SetPromiscuousMode enables or disables the promiscuous and all-multicast modes of all the ports.
+// testpmd enables the promiscuous mode of all the ports on start.
+func (t TestpmdConsole) SetPromiscuousMode(promiscuous, allMulticast bool) error {
+	const batchTimeout = 30 * time.Second
+
+	_, err := t.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+		&expect.BSnd{S: fmt.Sprintf("set promisc all %s\n", onOff(promiscuous))},
+		&expect.BExp{R: testpmdPrompt},
+		&expect.BSnd{S: fmt.Sprintf("set allmulti all %s\n", onOff(allMulticast))},
+		&expect.BExp{R: testpmdPrompt},
+	},
+		batchTimeout,
+	)
+
+	return err
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// GetStats returns the statistics of each port since they were last cleared, read using the telemetry when it is available.
+// Otherwise, they are parsed from the testpmd forward statistics, which hold the statistics of each forwarding stream as well.
+func (t *TestpmdConsole) GetStats() (Stats, error) {
+	if t.telemetryBaseline != nil {
+		stats, err := t.getTelemetryStats()
+		if err == nil {
+			return stats, nil
+		}
+		log.Printf("failed to read the testpmd telemetry stats, falling back to the forward stats: %v", err)
+	}
+
+	return t.getFwdStats()
+}
+
+func (t *TestpmdConsole) getTelemetryStats() (Stats, error) {
+	portsStats, err := t.readTelemetryStats()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	var stats Stats
+	for portIdx, portStats := range portsStats {
+		ps := PortStats(telemetry.Subtract(portStats, t.telemetryBaseline[portIdx]))
+		stats.Ports = append(stats.Ports, ps)
+
+		stats.Summary.RXPackets += ps.RXPackets
+		stats.Summary.RXDropped += ps.RXDropped
+		stats.Summary.RXTotal += ps.RXTotal
+		stats.Summary.TXPackets += ps.TXPackets
+		stats.Summary.TXDropped += ps.TXDropped
+		stats.Summary.TXTotal += ps.TXTotal
+	}
+
+	return stats, nil
+}
+
+func (t *TestpmdConsole) readTelemetryStats() ([]telemetry.PortStats, error) {
+	output, err := t.runGuestCommand(telemetry.PortStatsCommand(len(t.ports)))
+	if err != nil {
+		return nil, err
+	}
+
+	if t.verbosePrintsEnabled {
+		log.Printf("testpmd telemetry stats:\n%s", output)
+	}
+
+	return telemetry.ParsePortStats(output, len(t.ports))
+}
+
+func (t *TestpmdConsole) getFwdStats() (Stats, error) {
 	const batchTimeout = 30 * time.Second
 
 	const testpmdPromt = "testpmd> "
@@ -138,14 +322,34 @@ func (t TestpmdConsole) GetStats() ([StatsArraySize]PortStats, error) {
 	)
 
 	if err != nil {
-		return [StatsArraySize]PortStats{}, err
+		return Stats{}, err
 	}
 
 	if t.verbosePrintsEnabled {
 		log.Printf("testpmd stats:\n%s", resp[0].Output)
 	}
 
-	return parseTestpmdStats(resp[0].Output)
+	return parseTestpmdStats(resp[0].Output, len(t.ports))
+}
+
+// GetPortStats returns the raw testpmd port statistics, to be attached to the checkup diagnostics.
+func (t TestpmdConsole) GetPortStats() (string, error) {
+	const batchTimeout = 30 * time.Second
+
+	const testpmdCmd = "show port stats all"
+
+	resp, err := t.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
+		&expect.BSnd{S: testpmdCmd + "\n"},
+		&expect.BExp{R: testpmdPrompt},
+	},
+		batchTimeout,
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	return resp[0].Output, nil
 }
 
 func extractSectionStatistics(input, sectionStart, sectionEnd string) (string, error) {
@@ -171,33 +375,54 @@ func findStringLineIndex(lines []string, substring string) int {
 	return len(lines)
 }
 
-func parseTestpmdStats(input string) ([StatsArraySize]PortStats, error) {
-	var statistics [StatsArraySize]PortStats
+func parseTestpmdStats(input string, portsCount int) (Stats, error) {
 	const (
-		port0SectionStart   = "Forward statistics for port 0"
-		port0SectionEnd     = "----------------------------------------------------------------------------"
-		port1SectionStart   = "Forward statistics for port 1"
-		port1SectionEnd     = port0SectionEnd
-		SummarySectionStart = "Accumulated forward statistics for all ports"
-		SummarySectionEnd   = "++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++"
+		portSectionStartFormat = "Forward statistics for port %d "
+		portSectionEnd         = "----------------------------------------------------------------------------"
+		SummarySectionStart    = "Accumulated forward statistics for all ports"
+		SummarySectionEnd      = "++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++"
 	)
 
-	startSections := [StatsArraySize]string{port0SectionStart, port1SectionStart, SummarySectionStart}
-	endSections := [StatsArraySize]string{port0SectionEnd, port1SectionEnd, SummarySectionEnd}
-	for statsIdx := range startSections {
-		sectionString, err := extractSectionStatistics(input, startSections[statsIdx], endSections[statsIdx])
+	statistics := Stats{Ports: make([]PortStats, portsCount)}
+	for portIdx := range statistics.Ports {
+		sectionString, err := extractSectionStatistics(input, fmt.Sprintf(portSectionStartFormat, portIdx), portSectionEnd)
 		if err != nil {
-			return [StatsArraySize]PortStats{}, fmt.Errorf("failed parsing section on port %d: %w", statsIdx, err)
+			return Stats{}, fmt.Errorf("failed parsing section on port %d: %w", portIdx, err)
 		}
-		err = parseTestpmdStatsSection(&statistics[statsIdx], sectionString)
-		if err != nil {
-			return [StatsArraySize]PortStats{}, err
+		if err = parseTestpmdStatsSection(&statistics.Ports[portIdx], sectionString); err != nil {
+			return Stats{}, err
 		}
 	}
 
+	sectionString, err := extractSectionStatistics(input, SummarySectionStart, SummarySectionEnd)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed parsing summary section: %w", err)
+	}
+	if err = parseTestpmdStatsSection(&statistics.Summary, sectionString); err != nil {
+		return Stats{}, err
+	}
+
+	statistics.Queues = parseTestpmdQueueStats(input)
+
 	return statistics, nil
 }
 
+func parseTestpmdQueueStats(input string) []QueueStats {
+	var queues []QueueStats
+	for _, match := range queueStatsRegex.FindAllStringSubmatch(input, -1) {
+		queue := QueueStats{}
+		queue.RXPort, _ = strconv.Atoi(match[1])
+		queue.RXQueue, _ = strconv.Atoi(match[2])
+		queue.TXPort, _ = strconv.Atoi(match[3])
+		queue.TXQueue, _ = strconv.Atoi(match[4])
+		queue.RXPackets, _ = strconv.ParseInt(match[5], 10, 64)
+		queue.TXPackets, _ = strconv.ParseInt(match[6], 10, 64)
+		queue.TXDropped, _ = strconv.ParseInt(match[7], 10, 64)
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
 func parseTestpmdStatsSection(stats *PortStats, section string) error {
 	const (
 		RXPacketsIndex = 1
@@ -228,32 +453,59 @@ func parseTestpmdStatsSection(stats *PortStats, section string) error {
 	return nil
 }
 
-func buildTestpmdCmd(vmiEastNICPCIAddress, vmiWestNICPCIAddress, eastEthPeerMACAddress, westEthPeerMACAddress string) string {
+func buildTestpmdCmd(binaryPath string, ports []Port, forwardMode ForwardMode, tuning Tuning) string {
 	const (
 		cpuAssignmentMap        = "0@2-3,1@4,2@5,3@6,4@7"
-		numberOfCores           = 4
-		queuesPerPort           = numberOfCores
+		defaultNumberOfCores    = 4
 		hugepageSizeInMegaBytes = 1024
 		hugepagesMountedDir     = "/mnt/huge"
 	)
 
+	numberOfCores := defaultNumberOfCores
 	sb := strings.Builder{}
-	sb.WriteString("dpdk-testpmd ")
-	sb.WriteString(fmt.Sprintf("--lcores %s ", cpuAssignmentMap))
-	sb.WriteString(fmt.Sprintf("-a %s ", vmiEastNICPCIAddress))
-	sb.WriteString(fmt.Sprintf("-a %s ", vmiWestNICPCIAddress))
+	sb.WriteString(binaryPath + " ")
+	if len(tuning.Cores) > 0 {
+		var cores []string
+		for _, core := range tuning.Cores {
+			cores = append(cores, strconv.Itoa(core))
+		}
+		sb.WriteString(fmt.Sprintf("-l %s ", strings.Join(cores, ",")))
+		numberOfCores = len(tuning.Cores) - 1
+	} else {
+		sb.WriteString(fmt.Sprintf("--lcores %s ", cpuAssignmentMap))
+	}
+	for _, port := range ports {
+		sb.WriteString(fmt.Sprintf("-a %s ", port.PCIAddress))
+	}
 	sb.WriteString(fmt.Sprintf("--socket-mem %d ", hugepageSizeInMegaBytes))
 	sb.WriteString(fmt.Sprintf("--huge-dir %s ", hugepagesMountedDir))
+	if tuning.MemoryChannels > 0 {
+		sb.WriteString(fmt.Sprintf("-n %d ", tuning.MemoryChannels))
+	}
+	if tuning.InMemory {
+		sb.WriteString("--in-memory ")
+	}
 	sb.WriteString("-- ")
 	sb.WriteString("-i ")
 	sb.WriteString(fmt.Sprintf("--nb-cores=%d ", numberOfCores))
 	sb.WriteString("--rxd=2048 ")
 	sb.WriteString("--txd=2048 ")
-	sb.WriteString(fmt.Sprintf("--rxq=%d ", queuesPerPort))
-	sb.WriteString(fmt.Sprintf("--txq=%d ", queuesPerPort))
-	sb.WriteString("--forward-mode=mac ")
-	sb.WriteString(fmt.Sprintf("--eth-peer=0,%s ", eastEthPeerMACAddress))
-	sb.WriteString(fmt.Sprintf("--eth-peer=1,%s", westEthPeerMACAddress))
+	sb.WriteString(fmt.Sprintf("--rxq=%d ", numberOfCores))
+	sb.WriteString(fmt.Sprintf("--txq=%d ", numberOfCores))
+	if tuning.MbufSize > 0 {
+		sb.WriteString(fmt.Sprintf("--mbuf-size=%d ", tuning.MbufSize))
+	}
+	if tuning.TotalNumMbufs > 0 {
+		sb.WriteString(fmt.Sprintf("--total-num-mbufs=%d ", tuning.TotalNumMbufs))
+	}
+	sb.WriteString(fmt.Sprintf("--forward-mode=%s", forwardMode))
+	if forwardMode == ForwardModeMAC {
+		var ethPeers []string
+		for portIdx, port := range ports {
+			ethPeers = append(ethPeers, fmt.Sprintf("--eth-peer=%d,%s", portIdx, port.EthPeerMACAddress))
+		}
+		sb.WriteString(" " + strings.Join(ethPeers, " "))
+	}
 
 	return sb.String()
 }
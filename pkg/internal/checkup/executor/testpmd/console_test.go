@@ -22,54 +22,64 @@ package testpmd_test
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"strings"
 	"testing"
 	"time"
 
 	expect "github.com/google/goexpect"
 	assert "github.com/stretchr/testify/require"
 
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/console"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/telemetry"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/testpmd"
 )
 
 const (
+	testpmdBinaryPath             = "/usr/local/bin/dpdk-testpmd"
 	vmiUnderTestEastNICPCIAddress = "0000:06:00.0"
 	trafficGenEastMACAddress      = "60:94:19:c9:ac:01"
 	vmiUnderTestWestNICPCIAddress = "0000:07:00.0"
 	trafficGenWestMACAddress      = "60:94:19:c9:ac:02"
 	verbosePrintsEnabled          = false
+	noVlanID                      = 0
 )
 
 func TestGetPortStatsSuccess(t *testing.T) {
 	expecter := expecterStub{}
 	c := testpmd.NewTestpmdConsole(
 		expecter,
-		vmiUnderTestEastNICPCIAddress,
-		trafficGenEastMACAddress,
-		vmiUnderTestWestNICPCIAddress,
-		trafficGenWestMACAddress,
+		testpmdBinaryPath,
+		testPorts(),
+		testpmd.ForwardModeMAC,
+		testpmd.Tuning{},
+		noVlanID,
 		verbosePrintsEnabled,
 	)
 
 	stats, err := c.GetStats()
 	assert.NoError(t, err, "GetStats returned an error")
-	expected := [testpmd.StatsArraySize]testpmd.PortStats{
-		{
-			RXPackets: 480000001,
-			RXDropped: 2,
-			RXTotal:   480000003,
-			TXPackets: 4,
-			TXDropped: 5,
-			TXTotal:   6,
-		},
-		{
-			RXPackets: 7,
-			RXDropped: 8,
-			RXTotal:   9,
-			TXPackets: 480000010,
-			TXDropped: 11,
-			TXTotal:   480000012,
+	expected := testpmd.Stats{
+		Ports: []testpmd.PortStats{
+			{
+				RXPackets: 480000001,
+				RXDropped: 2,
+				RXTotal:   480000003,
+				TXPackets: 4,
+				TXDropped: 5,
+				TXTotal:   6,
+			},
+			{
+				RXPackets: 7,
+				RXDropped: 8,
+				RXTotal:   9,
+				TXPackets: 480000010,
+				TXDropped: 11,
+				TXTotal:   480000012,
+			},
 		},
-		{
+		Summary: testpmd.PortStats{
 			RXPackets: 480000013,
 			RXDropped: 14,
 			RXTotal:   480000015,
@@ -77,6 +87,12 @@ func TestGetPortStatsSuccess(t *testing.T) {
 			TXDropped: 17,
 			TXTotal:   480000018,
 		},
+		Queues: []testpmd.QueueStats{
+			{RXPort: 0, RXQueue: 0, TXPort: 1, TXQueue: 0, RXPackets: 160000000, TXPackets: 160000000},
+			{RXPort: 0, RXQueue: 1, TXPort: 1, TXQueue: 1, RXPackets: 80000000, TXPackets: 80000000},
+			{RXPort: 0, RXQueue: 2, TXPort: 1, TXQueue: 2, RXPackets: 80000000, TXPackets: 80000000},
+			{RXPort: 0, RXQueue: 3, TXPort: 1, TXQueue: 3, RXPackets: 160000000, TXPackets: 160000000},
+		},
 	}
 	assert.Equal(t, expected, stats, "GetStats returned unexpected result")
 }
@@ -90,10 +106,11 @@ func TestGetPortStatsFailure(t *testing.T) {
 
 		c := testpmd.NewTestpmdConsole(
 			expecter,
-			vmiUnderTestEastNICPCIAddress,
-			trafficGenEastMACAddress,
-			vmiUnderTestWestNICPCIAddress,
-			trafficGenWestMACAddress,
+			testpmdBinaryPath,
+			testPorts(),
+			testpmd.ForwardModeMAC,
+			testpmd.Tuning{},
+			noVlanID,
 			verbosePrintsEnabled,
 		)
 
@@ -108,10 +125,11 @@ func TestGetPortStatsFailure(t *testing.T) {
 		}
 		c := testpmd.NewTestpmdConsole(
 			expecter,
-			vmiUnderTestEastNICPCIAddress,
-			trafficGenEastMACAddress,
-			vmiUnderTestWestNICPCIAddress,
-			trafficGenWestMACAddress,
+			testpmdBinaryPath,
+			testPorts(),
+			testpmd.ForwardModeMAC,
+			testpmd.Tuning{},
+			noVlanID,
 			verbosePrintsEnabled,
 		)
 		stats, err := c.GetStats()
@@ -121,14 +139,292 @@ func TestGetPortStatsFailure(t *testing.T) {
 	})
 }
 
+func TestGetStatsShouldReadTheTelemetryRelativeToTheClearedStats(t *testing.T) {
+	runner := &telemetryRunnerStub{outputs: []string{
+		telemetryStatsOutput(portStatsReply(100, 0, 1, 0), portStatsReply(0, 90, 0, 2)),
+		telemetryStatsOutput(portStatsReply(300, 0, 4, 0), portStatsReply(0, 280, 0, 2)),
+	}}
+	c := testpmd.NewTestpmdConsole(
+		expecterStub{},
+		testpmdBinaryPath,
+		testPorts(),
+		testpmd.ForwardModeMAC,
+		testpmd.Tuning{},
+		noVlanID,
+		verbosePrintsEnabled,
+	).WithTelemetry(runner.run)
+
+	assert.NoError(t, c.ClearStats())
+
+	stats, err := c.GetStats()
+	assert.NoError(t, err)
+	expected := testpmd.Stats{
+		Ports: []testpmd.PortStats{
+			{RXPackets: 200, RXDropped: 3, RXTotal: 203},
+			{TXPackets: 190, TXDropped: 0, TXTotal: 190},
+		},
+		Summary: testpmd.PortStats{RXPackets: 200, RXDropped: 3, RXTotal: 203, TXPackets: 190, TXTotal: 190},
+	}
+	assert.Equal(t, expected, stats)
+	assert.Contains(t, runner.commands[0], "printf '/ethdev/stats,0\\n/ethdev/stats,1\\n' | "+telemetry.ScriptName)
+}
+
+func TestGetStatsShouldFallBackToTheFwdStatsWhenTheTelemetryIsUnavailable(t *testing.T) {
+	t.Run("when the baseline cannot be taken", func(t *testing.T) {
+		runner := &telemetryRunnerStub{outputs: []string{"bash: dpdk-telemetry.py: command not found"}}
+		c := testpmd.NewTestpmdConsole(
+			expecterStub{},
+			testpmdBinaryPath,
+			testPorts(),
+			testpmd.ForwardModeMAC,
+			testpmd.Tuning{},
+			noVlanID,
+			verbosePrintsEnabled,
+		).WithTelemetry(runner.run)
+
+		assert.NoError(t, c.ClearStats())
+
+		stats, err := c.GetStats()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(480000015), stats.Summary.RXTotal)
+		assert.Len(t, runner.commands, 1)
+	})
+
+	t.Run("when the stats cannot be read", func(t *testing.T) {
+		runner := &telemetryRunnerStub{outputs: []string{
+			telemetryStatsOutput(portStatsReply(100, 0, 1, 0), portStatsReply(0, 90, 0, 2)),
+		}}
+		c := testpmd.NewTestpmdConsole(
+			expecterStub{},
+			testpmdBinaryPath,
+			testPorts(),
+			testpmd.ForwardModeMAC,
+			testpmd.Tuning{},
+			noVlanID,
+			verbosePrintsEnabled,
+		).WithTelemetry(runner.run)
+
+		assert.NoError(t, c.ClearStats())
+
+		stats, err := c.GetStats()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(480000015), stats.Summary.RXTotal)
+		assert.Len(t, runner.commands, 2)
+	})
+}
+
+func TestKeepAlive(t *testing.T) {
+	t.Run("succeeds when the testpmd prompt is back", func(t *testing.T) {
+		c := testpmd.NewTestpmdConsole(
+			expecterStub{},
+			testpmdBinaryPath,
+			testPorts(),
+			testpmd.ForwardModeMAC,
+			testpmd.Tuning{},
+			noVlanID,
+			verbosePrintsEnabled,
+		)
+
+		assert.NoError(t, c.KeepAlive())
+	})
+
+	t.Run("fails when the console is not responsive", func(t *testing.T) {
+		expectedTimeoutErr := errors.New("failed on timeout")
+		c := testpmd.NewTestpmdConsole(
+			expecterStub{timeoutErr: expectedTimeoutErr},
+			testpmdBinaryPath,
+			testPorts(),
+			testpmd.ForwardModeMAC,
+			testpmd.Tuning{},
+			noVlanID,
+			verbosePrintsEnabled,
+		)
+
+		assert.ErrorContains(t, c.KeepAlive(), expectedTimeoutErr.Error())
+	})
+}
+
+func TestSetPromiscuousMode(t *testing.T) {
+	var cmds string
+	c := testpmd.NewTestpmdConsole(expecterStub{runCmd: &cmds}, testpmdBinaryPath, testPorts(), testpmd.ForwardModeMAC,
+		testpmd.Tuning{}, noVlanID, verbosePrintsEnabled)
+
+	assert.NoError(t, c.SetPromiscuousMode(false, true))
+	assert.Equal(t, "set promisc all off\nset allmulti all on\n", cmds)
+}
+
+func TestRunShouldUseTheBinaryPath(t *testing.T) {
+	c := testpmd.NewTestpmdConsole(
+		expecterStub{},
+		testpmdBinaryPath,
+		testPorts(),
+		testpmd.ForwardModeMAC,
+		testpmd.Tuning{},
+		noVlanID,
+		verbosePrintsEnabled,
+	)
+
+	assert.NoError(t, c.Run())
+}
+
+func TestRunShouldAllowAndPeerAllThePorts(t *testing.T) {
+	var runCmd string
+	ports := append(testPorts(),
+		testpmd.Port{PCIAddress: "0000:08:00.0", EthPeerMACAddress: "60:94:19:c9:ac:03"},
+		testpmd.Port{PCIAddress: "0000:09:00.0", EthPeerMACAddress: "60:94:19:c9:ac:04"},
+	)
+	c := testpmd.NewTestpmdConsole(expecterStub{runCmd: &runCmd}, testpmdBinaryPath, ports, testpmd.ForwardModeMAC, testpmd.Tuning{}, noVlanID, verbosePrintsEnabled)
+
+	assert.NoError(t, c.Run())
+	for portIdx, port := range ports {
+		assert.Contains(t, runCmd, fmt.Sprintf("-a %s ", port.PCIAddress))
+		assert.Contains(t, runCmd, fmt.Sprintf("--eth-peer=%d,%s", portIdx, port.EthPeerMACAddress))
+	}
+	assert.NotContains(t, runCmd, "vlan set")
+}
+
+func TestRunShouldDisableVlanFilteringWhenVlanIDIsSet(t *testing.T) {
+	const vlanID = 100
+	var runCmd string
+	c := testpmd.NewTestpmdConsole(expecterStub{runCmd: &runCmd}, testpmdBinaryPath, testPorts(), testpmd.ForwardModeMAC, testpmd.Tuning{}, vlanID,
+		verbosePrintsEnabled)
+
+	assert.NoError(t, c.Run())
+	for portIdx := range testPorts() {
+		assert.Contains(t, runCmd, fmt.Sprintf("vlan set filter off %d\n", portIdx))
+		assert.Contains(t, runCmd, fmt.Sprintf("vlan set strip off %d\n", portIdx))
+	}
+	assert.True(t, strings.HasSuffix(runCmd, "start\n"))
+}
+
+func TestRunShouldSwapTheAddressesWhenForwardModeIs5TupleSwap(t *testing.T) {
+	var runCmd string
+	c := testpmd.NewTestpmdConsole(expecterStub{runCmd: &runCmd}, testpmdBinaryPath, testPorts(), testpmd.ForwardMode5TupleSwap,
+		testpmd.Tuning{}, noVlanID, verbosePrintsEnabled)
+
+	assert.NoError(t, c.Run())
+	assert.Contains(t, runCmd, "--forward-mode=5tswap")
+	assert.NotContains(t, runCmd, "--eth-peer")
+}
+
+func TestRunShouldApplyTheTuning(t *testing.T) {
+	tuning := testpmd.Tuning{MbufSize: 9216, TotalNumMbufs: 262144, MemoryChannels: 4, InMemory: true}
+	var runCmd string
+	c := testpmd.NewTestpmdConsole(expecterStub{runCmd: &runCmd}, testpmdBinaryPath, testPorts(), testpmd.ForwardModeMAC, tuning,
+		noVlanID, verbosePrintsEnabled)
+
+	assert.NoError(t, c.Run())
+	assert.Contains(t, runCmd, "--huge-dir /mnt/huge -n 4 --in-memory -- ")
+	assert.Contains(t, runCmd, " --mbuf-size=9216 --total-num-mbufs=262144 ")
+	assert.Equal(t, runCmd, c.Command()+"\nstart\n")
+}
+
+func TestRunShouldApplyTheExplicitCores(t *testing.T) {
+	tuning := testpmd.Tuning{Cores: []int{2, 3, 4, 5}}
+	var runCmd string
+	c := testpmd.NewTestpmdConsole(expecterStub{runCmd: &runCmd}, testpmdBinaryPath, testPorts(), testpmd.ForwardModeMAC, tuning,
+		noVlanID, verbosePrintsEnabled)
+
+	assert.NoError(t, c.Run())
+	assert.Contains(t, runCmd, testpmdBinaryPath+" -l 2,3,4,5 -a ")
+	assert.NotContains(t, runCmd, "--lcores")
+	assert.Contains(t, runCmd, " --nb-cores=3 --rxd=2048 --txd=2048 --rxq=3 --txq=3 ")
+}
+
+func TestGetStatsFromRecordedSession(t *testing.T) {
+	testCases := []struct {
+		recordingFileName string
+		expectedPorts     []testpmd.PortStats
+	}{
+		{
+			recordingFileName: "dpdk-22.11-fwd-stats.json",
+			expectedPorts: []testpmd.PortStats{
+				{RXPackets: 29999123, RXDropped: 877, RXTotal: 30000000, TXPackets: 29998990, TXTotal: 29998990},
+				{RXPackets: 29998990, RXDropped: 1010, RXTotal: 30000000, TXPackets: 29999123, TXTotal: 29999123},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.recordingFileName, func(t *testing.T) {
+			replayClient, expecter := newReplayExpecter(t, testCase.recordingFileName)
+			c := testpmd.NewTestpmdConsole(expecter, testpmdBinaryPath, testPorts(), testpmd.ForwardModeMAC, testpmd.Tuning{}, noVlanID,
+				verbosePrintsEnabled)
+
+			stats, err := c.GetStats()
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedPorts, stats.Ports)
+			assert.Zero(t, replayClient.Pending())
+		})
+	}
+}
+
+func testPorts() []testpmd.Port {
+	return []testpmd.Port{
+		{PCIAddress: vmiUnderTestEastNICPCIAddress, EthPeerMACAddress: trafficGenEastMACAddress},
+		{PCIAddress: vmiUnderTestWestNICPCIAddress, EthPeerMACAddress: trafficGenWestMACAddress},
+	}
+}
+
+// newReplayExpecter returns an expecter replaying the recorded session of the VMI under test.
+func newReplayExpecter(t *testing.T, recordingFileName string) (*console.ReplayClient, console.Expecter) {
+	const (
+		recordedVMINamespace = "dpdk-checkup-ns"
+		recordedVMIName      = "vmi-under-test-x2k9d"
+	)
+
+	data, err := os.ReadFile(path.Join("testdata", "recordings", recordingFileName))
+	assert.NoError(t, err)
+	recording, err := console.ParseRecording(data)
+	assert.NoError(t, err)
+
+	replayClient := console.NewReplayClient(recording)
+	return replayClient, console.NewExpecter(replayClient, recordedVMINamespace, recordedVMIName)
+}
+
+// telemetryStatsOutput returns the telemetry script output, replying to the stats query of each port in order.
+func telemetryStatsOutput(portsReplies ...string) string {
+	sb := strings.Builder{}
+	for portIdx, reply := range portsReplies {
+		sb.WriteString(fmt.Sprintf("--> /ethdev/stats,%d\n%s\n", portIdx, reply))
+	}
+	sb.WriteString("--> ")
+	return sb.String()
+}
+
+func portStatsReply(ipackets, opackets, imissed, oerrors int64) string {
+	return fmt.Sprintf(`{"/ethdev/stats": {"ipackets": %d, "opackets": %d, "ibytes": 0, "obytes": 0, `+
+		`"imissed": %d, "ierrors": 0, "oerrors": %d, "rx_nombuf": 0, "q_ipackets": [0]}}`, ipackets, opackets, imissed, oerrors)
+}
+
+type telemetryRunnerStub struct {
+	outputs  []string
+	commands []string
+}
+
+func (rs *telemetryRunnerStub) run(command string) (string, error) {
+	rs.commands = append(rs.commands, command)
+	if len(rs.outputs) == 0 {
+		return "", errors.New("the telemetry socket is gone")
+	}
+
+	output := rs.outputs[0]
+	rs.outputs = rs.outputs[1:]
+	return output, nil
+}
+
 type expecterStub struct {
 	expectBatchErr error
 	timeoutErr     error
+	runCmd         *string
 }
 
 const (
-	getStatsCmd    = "show fwd stats all\n"
-	getStatsOutput = "" +
+	keepAliveCmd        = "\n"
+	clearStatsCmd       = "clear fwd stats all\n"
+	setPromiscCmdPrefix = "set promisc all "
+	getStatsCmd         = "show fwd stats all\n"
+	getStatsOutput      = "" +
 		"  ------- Forward Stats for RX Port= 0/Queue= 0 -> TX Port= 1/Queue= 0 -------\n" +
 		"  RX-packets: 160000000      TX-packets: 160000000      TX-dropped: 0             \n" +
 		"\n" +
@@ -157,6 +453,17 @@ const (
 		"testpmd> "
 )
 
+// sentCommands concatenates the commands sent by the batch.
+func sentCommands(batch []expect.Batcher) string {
+	sb := strings.Builder{}
+	for _, batcher := range batch {
+		if batcher.Cmd() == expect.BatchSend {
+			sb.WriteString(batcher.Arg())
+		}
+	}
+	return sb.String()
+}
+
 func (es expecterStub) SafeExpectBatchWithResponse(expected []expect.Batcher, _ time.Duration) ([]expect.BatchRes, error) {
 	if es.expectBatchErr != nil {
 		return nil, es.expectBatchErr
@@ -165,8 +472,28 @@ func (es expecterStub) SafeExpectBatchWithResponse(expected []expect.Batcher, _
 		return nil, es.timeoutErr
 	}
 
+	if strings.HasPrefix(expected[0].Arg(), testpmdBinaryPath+" ") {
+		if es.runCmd != nil {
+			*es.runCmd = sentCommands(expected)
+		}
+		return []expect.BatchRes{{Idx: 1, Output: "testpmd> "}, {Idx: 3, Output: "testpmd> "}}, nil
+	}
+
+	if strings.HasPrefix(expected[0].Arg(), setPromiscCmdPrefix) {
+		if es.runCmd != nil {
+			*es.runCmd = sentCommands(expected)
+		}
+		return []expect.BatchRes{{Idx: 1, Output: "testpmd> "}, {Idx: 3, Output: "testpmd> "}}, nil
+	}
+
 	var batchRes []expect.BatchRes
 	switch expected[0].Arg() {
+	case keepAliveCmd, clearStatsCmd:
+		batchRes = append(batchRes,
+			expect.BatchRes{
+				Idx:    1,
+				Output: "testpmd> ",
+			})
 	case getStatsCmd:
 		batchRes = append(batchRes,
 			expect.BatchRes{
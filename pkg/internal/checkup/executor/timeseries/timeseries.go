@@ -0,0 +1,70 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package timeseries holds the metrics sampled periodically while the traffic runs.
+package timeseries
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVFileName is the name of the file the samples are exported to, in the artifacts directory.
+const CSVFileName = "traffic-samples.csv"
+
+type Sample struct {
+	Timestamp time.Time
+	TxPps     float64
+	RxPps     float64
+	DropBps   float64
+	CPUUtil   float64
+}
+
+type Series []Sample
+
+// WriteCSV writes the samples as CSV, with a header row naming the columns.
+func (s Series) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+
+	if err := csvWriter.Write([]string{"timestamp", "tx_pps", "rx_pps", "drop_bps", "cpu_util"}); err != nil {
+		return err
+	}
+
+	for _, sample := range s {
+		record := []string{
+			sample.Timestamp.UTC().Format(time.RFC3339Nano),
+			formatFloat(sample.TxPps),
+			formatFloat(sample.RxPps),
+			formatFloat(sample.DropBps),
+			formatFloat(sample.CPUUtil),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,52 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package timeseries_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/timeseries"
+)
+
+func TestWriteCSV(t *testing.T) {
+	startTime := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+	series := timeseries.Series{
+		{Timestamp: startTime, TxPps: 8000000, RxPps: 7999999.5, DropBps: 256, CPUUtil: 12.25},
+		{Timestamp: startTime.Add(10 * time.Second), TxPps: 8000000, RxPps: 8000000},
+	}
+
+	sb := strings.Builder{}
+	assert.NoError(t, series.WriteCSV(&sb))
+
+	expected := "timestamp,tx_pps,rx_pps,drop_bps,cpu_util\n" +
+		"2023-05-01T10:00:00Z,8000000,7999999.5,256,12.25\n" +
+		"2023-05-01T10:00:10Z,8000000,8000000,0,0\n"
+	assert.Equal(t, expected, sb.String())
+}
+
+func TestWriteCSVWithNoSamples(t *testing.T) {
+	sb := strings.Builder{}
+	assert.NoError(t, timeseries.Series{}.WriteCSV(&sb))
+	assert.Equal(t, "timestamp,tx_pps,rx_pps,drop_bps,cpu_util\n", sb.String())
+}
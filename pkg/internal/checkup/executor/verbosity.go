@@ -0,0 +1,86 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	"github.com/kiagnose/kiagnose/kiagnose/types"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+type configMapReader interface {
+	GetConfigMap(ctx context.Context, namespace, name string) (*k8scorev1.ConfigMap, error)
+}
+
+// verbosityWatcher follows the verbose param on the checkup ConfigMap,
+// allowing to toggle the verbose prints of a long run without restarting it.
+type verbosityWatcher struct {
+	client    configMapReader
+	namespace string
+	name      string
+	enabled   bool
+}
+
+func newVerbosityWatcher(client configMapReader, namespace, name string, enabled bool) *verbosityWatcher {
+	return &verbosityWatcher{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		enabled:   enabled,
+	}
+}
+
+// refresh re-reads the verbose param and returns its current value.
+// The last known value is kept when the param cannot be read.
+func (w *verbosityWatcher) refresh(ctx context.Context) bool {
+	if w.name == "" {
+		return w.enabled
+	}
+
+	configMap, err := w.client.GetConfigMap(ctx, w.namespace, w.name)
+	if err != nil {
+		log.Printf("failed to re-read the %q param: %v", config.VerboseParamName, err)
+		return w.enabled
+	}
+
+	rawVal := configMap.Data[types.ParamNameKeyPrefix+config.VerboseParamName]
+	if rawVal == "" {
+		return w.enabled
+	}
+
+	enabled, err := strconv.ParseBool(rawVal)
+	if err != nil {
+		log.Printf("ignoring invalid %q param value %q", config.VerboseParamName, rawVal)
+		return w.enabled
+	}
+
+	if enabled != w.enabled {
+		log.Printf("%q param was changed to %t", config.VerboseParamName, enabled)
+		w.enabled = enabled
+	}
+
+	return w.enabled
+}
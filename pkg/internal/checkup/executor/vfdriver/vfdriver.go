@@ -0,0 +1,133 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package vfdriver detects the kernel driver of the guests VFs, and the known pitfalls of using them with DPDK.
+package vfdriver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type commandRunner interface {
+	GetCommandOutput(command string) (string, error)
+}
+
+// The VF drivers the checkup is aware of.
+const (
+	IAVF  = "iavf"
+	MLX5  = "mlx5_core"
+	ICE   = "ice"
+	Other = "unknown"
+)
+
+const (
+	intelVendorID    = "8086"
+	mellanoxVendorID = "15b3"
+
+	// adaptiveVFDeviceID is the Intel Adaptive VF, which the E810 (ice) PFs expose.
+	adaptiveVFDeviceID = "1889"
+)
+
+var (
+	pciIDsRegex        = regexp.MustCompile(`^\S+ [0-9a-f]{4}: ([0-9a-f]{4}):([0-9a-f]{4})`)
+	kernelModulesRegex = regexp.MustCompile(`Kernel modules:\s*(.+)`)
+	versionRegex       = regexp.MustCompile(`^[0-9][0-9A-Za-z._+-]*$`)
+)
+
+// NIC describes the VF at a guest PCI address.
+type NIC struct {
+	PCIAddress string
+	VendorID   string
+	DeviceID   string
+	Driver     string
+}
+
+// Detect reads the vendor and device IDs of the NIC at the given PCI address, and the kernel driver able to drive it.
+// The NIC is expected to be bound to vfio-pci already, so the driver is deduced from the kernel modules matching it.
+func Detect(runner commandRunner, pciAddress string) (NIC, error) {
+	output, err := runner.GetCommandOutput("lspci -nk -s " + pciAddress)
+	if err != nil {
+		return NIC{}, err
+	}
+
+	ids := pciIDsRegex.FindStringSubmatch(output)
+	if ids == nil {
+		return NIC{}, fmt.Errorf("NIC %s was not found", pciAddress)
+	}
+
+	nic := NIC{PCIAddress: pciAddress, VendorID: ids[1], DeviceID: ids[2], Driver: Other}
+	var modules []string
+	if matches := kernelModulesRegex.FindStringSubmatch(output); matches != nil {
+		modules = strings.Split(matches[1], ",")
+	}
+	for _, module := range modules {
+		if module = strings.TrimSpace(module); module == IAVF || module == MLX5 || module == ICE {
+			nic.Driver = module
+			break
+		}
+	}
+	if nic.Driver == Other && nic.VendorID == mellanoxVendorID {
+		nic.Driver = MLX5
+	}
+
+	return nic, nil
+}
+
+// Version returns the version of the given driver module, or the guest kernel release when the module has no version of
+// its own, as the in-tree modules are versioned with the kernel.
+// An empty version is returned when neither is available.
+func Version(runner commandRunner, driver string) (string, error) {
+	for _, command := range []string{"modinfo -F version " + driver, "uname -r"} {
+		output, err := runner.GetCommandOutput(command)
+		if err != nil {
+			return "", err
+		}
+		if versionRegex.MatchString(output) {
+			return output, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Bifurcated reports whether DPDK drives the NIC alongside its kernel driver, rather than through vfio-pci.
+func (n NIC) Bifurcated() bool {
+	return n.Driver == MLX5
+}
+
+// Hints returns actionable hints for the known pitfalls of using the NIC with DPDK.
+func (n NIC) Hints() []string {
+	switch {
+	case n.Driver == MLX5:
+		return []string{fmt.Sprintf("NIC %s is a Mellanox VF, which DPDK drives through the bifurcated %s driver: "+
+			"it is kept bound to %s rather than to vfio-pci", n.PCIAddress, MLX5, MLX5)}
+	case n.Driver == IAVF && n.VendorID == intelVendorID && n.DeviceID == adaptiveVFDeviceID:
+		return []string{fmt.Sprintf("NIC %s is an Intel Adaptive VF: when its PF is an E810 NIC, the VF RSS depends on "+
+			"the %s DDP package loaded on the node, make sure the same package is loaded on all the nodes", n.PCIAddress, ICE)}
+	case n.Driver == ICE:
+		return []string{fmt.Sprintf("NIC %s is an E810 PF passed through to the guest: the DPDK %s PMD requires the DDP "+
+			"package in the guest /lib/firmware/intel/ice/ddp directory", n.PCIAddress, ICE)}
+	case n.Driver == Other:
+		return []string{fmt.Sprintf("NIC %s (%s:%s) has no known VF driver, the DPDK applications may not support it",
+			n.PCIAddress, n.VendorID, n.DeviceID)}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package vfdriver_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/vfdriver"
+)
+
+const pciAddress = "0000:06:00.0"
+
+func TestDetectShouldReportTheVFDriver(t *testing.T) {
+	tests := map[string]struct {
+		lspciOutput   string
+		expectedNIC   vfdriver.NIC
+		expectedHints int
+	}{
+		"iavf": {
+			lspciOutput: "06:00.0 0200: 8086:154c (rev 02)\n" +
+				"\tSubsystem: 8086:0000\n" +
+				"\tKernel driver in use: vfio-pci\n" +
+				"\tKernel modules: iavf",
+			expectedNIC: vfdriver.NIC{PCIAddress: pciAddress, VendorID: "8086", DeviceID: "154c", Driver: vfdriver.IAVF},
+		},
+		"iavf of an E810 PF": {
+			lspciOutput: "06:00.0 0200: 8086:1889 (rev 02)\n" +
+				"\tKernel driver in use: vfio-pci\n" +
+				"\tKernel modules: iavf",
+			expectedNIC:   vfdriver.NIC{PCIAddress: pciAddress, VendorID: "8086", DeviceID: "1889", Driver: vfdriver.IAVF},
+			expectedHints: 1,
+		},
+		"mlx5": {
+			lspciOutput: "06:00.0 0200: 15b3:101e\n" +
+				"\tKernel driver in use: mlx5_core\n" +
+				"\tKernel modules: mlx5_core",
+			expectedNIC:   vfdriver.NIC{PCIAddress: pciAddress, VendorID: "15b3", DeviceID: "101e", Driver: vfdriver.MLX5},
+			expectedHints: 1,
+		},
+		"mlx5 without kernel modules": {
+			lspciOutput:   "06:00.0 0200: 15b3:101e",
+			expectedNIC:   vfdriver.NIC{PCIAddress: pciAddress, VendorID: "15b3", DeviceID: "101e", Driver: vfdriver.MLX5},
+			expectedHints: 1,
+		},
+		"unknown": {
+			lspciOutput: "06:00.0 0200: 1af4:1041 (rev 01)\n" +
+				"\tKernel driver in use: vfio-pci\n" +
+				"\tKernel modules: virtio_pci",
+			expectedNIC:   vfdriver.NIC{PCIAddress: pciAddress, VendorID: "1af4", DeviceID: "1041", Driver: vfdriver.Other},
+			expectedHints: 1,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			runner := runnerStub{outputs: map[string]string{"lspci -nk -s " + pciAddress: testCase.lspciOutput}}
+
+			nic, err := vfdriver.Detect(runner, pciAddress)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedNIC, nic)
+			assert.Len(t, nic.Hints(), testCase.expectedHints)
+			assert.Equal(t, testCase.expectedNIC.Driver == vfdriver.MLX5, nic.Bifurcated())
+		})
+	}
+}
+
+func TestDetectShouldFailWhen(t *testing.T) {
+	expectedErr := errors.New("console is not responding")
+
+	t.Run("the NIC is not found", func(t *testing.T) {
+		_, err := vfdriver.Detect(runnerStub{outputs: map[string]string{}}, pciAddress)
+		assert.ErrorContains(t, err, "NIC 0000:06:00.0 was not found")
+	})
+
+	t.Run("the command fails", func(t *testing.T) {
+		_, err := vfdriver.Detect(runnerStub{err: expectedErr}, pciAddress)
+		assert.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestVersion(t *testing.T) {
+	const kernelRelease = "5.14.0-362.el9.x86_64"
+
+	tests := map[string]struct {
+		modinfoOutput   string
+		expectedVersion string
+	}{
+		"out-of-tree module":                 {modinfoOutput: "4.8.2", expectedVersion: "4.8.2"},
+		"in-tree module":                     {modinfoOutput: "", expectedVersion: kernelRelease},
+		"module not found":                   {modinfoOutput: "modinfo: ERROR: Module iavf not found.", expectedVersion: kernelRelease},
+		"module with a distribution version": {modinfoOutput: "23.10-0.5.5", expectedVersion: "23.10-0.5.5"},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			runner := runnerStub{outputs: map[string]string{
+				"modinfo -F version " + vfdriver.IAVF: testCase.modinfoOutput,
+				"uname -r":                            kernelRelease,
+			}}
+
+			version, err := vfdriver.Version(runner, vfdriver.IAVF)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedVersion, version)
+		})
+	}
+
+	t.Run("no version is available", func(t *testing.T) {
+		version, err := vfdriver.Version(runnerStub{outputs: map[string]string{}}, vfdriver.IAVF)
+		assert.NoError(t, err)
+		assert.Empty(t, version)
+	})
+}
+
+type runnerStub struct {
+	outputs map[string]string
+	err     error
+}
+
+func (r runnerStub) GetCommandOutput(command string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.outputs[command], nil
+}
@@ -0,0 +1,80 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package vfio verifies the guests NICs are bound to the vfio-pci driver, which the DPDK applications require.
+package vfio
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+type commandRunner interface {
+	GetCommandOutput(command string) (string, error)
+}
+
+const (
+	DriverName = "vfio-pci"
+
+	listOverridesCommand = "driverctl list-overrides"
+	noDriver             = "none"
+)
+
+var ErrNotBound = errors.New("NIC is not bound to the " + DriverName + " driver")
+
+var driverInUseRegex = regexp.MustCompile(`Kernel driver in use:\s*(\S+)`)
+
+// VerifyBinding verifies each of the NICs at the given PCI addresses is bound to the vfio-pci driver.
+// A NIC bound to another driver fails the verification, listing the driver overrides set in the guest.
+func VerifyBinding(runner commandRunner, pciAddresses []string) error {
+	for _, pciAddress := range pciAddresses {
+		driver, err := driverInUse(runner, pciAddress)
+		if err != nil {
+			return err
+		}
+
+		if driver != DriverName {
+			overrides, err := runner.GetCommandOutput(listOverridesCommand)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: NIC %s is bound to %q (driver overrides: %q)", ErrNotBound, pciAddress, driver, overrides)
+		}
+	}
+
+	return nil
+}
+
+func driverInUse(runner commandRunner, pciAddress string) (string, error) {
+	output, err := runner.GetCommandOutput("lspci -k -s " + pciAddress)
+	if err != nil {
+		return "", err
+	}
+
+	if output == "" {
+		return "", fmt.Errorf("NIC %s was not found", pciAddress)
+	}
+
+	matches := driverInUseRegex.FindStringSubmatch(output)
+	if matches == nil {
+		return noDriver, nil
+	}
+	return matches[1], nil
+}
@@ -0,0 +1,103 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package vfio_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/vfio"
+)
+
+const (
+	eastPCIAddress = "0000:06:00.0"
+	westPCIAddress = "0000:07:00.0"
+
+	boundNICOutput = "06:00.0 Ethernet controller: Intel Corporation Ethernet Virtual Function 700 Series (rev 02)\n" +
+		"\tSubsystem: Intel Corporation Device 0000\n" +
+		"\tKernel driver in use: vfio-pci\n" +
+		"\tKernel modules: iavf"
+)
+
+func TestVerifyBindingShouldSucceedWhenAllNICsAreBound(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{
+		"lspci -k -s " + eastPCIAddress: boundNICOutput,
+		"lspci -k -s " + westPCIAddress: boundNICOutput,
+	}}
+
+	assert.NoError(t, vfio.VerifyBinding(runner, []string{eastPCIAddress, westPCIAddress}))
+}
+
+func TestVerifyBindingShouldFailWhen(t *testing.T) {
+	tests := map[string]struct {
+		westNICOutput string
+		expectedErr   string
+	}{
+		"a NIC is bound to another driver": {
+			westNICOutput: "07:00.0 Ethernet controller: Intel Corporation Ethernet Virtual Function 700 Series (rev 02)\n" +
+				"\tKernel driver in use: iavf",
+			expectedErr: `NIC 0000:07:00.0 is bound to "iavf" (driver overrides: "0000:06:00.0 vfio-pci")`,
+		},
+		"a NIC is not bound to any driver": {
+			westNICOutput: "07:00.0 Ethernet controller: Intel Corporation Ethernet Virtual Function 700 Series (rev 02)",
+			expectedErr:   `NIC 0000:07:00.0 is bound to "none" (driver overrides: "0000:06:00.0 vfio-pci")`,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			runner := runnerStub{outputs: map[string]string{
+				"lspci -k -s " + eastPCIAddress: boundNICOutput,
+				"lspci -k -s " + westPCIAddress: testCase.westNICOutput,
+				"driverctl list-overrides":      "0000:06:00.0 vfio-pci",
+			}}
+
+			err := vfio.VerifyBinding(runner, []string{eastPCIAddress, westPCIAddress})
+			assert.ErrorIs(t, err, vfio.ErrNotBound)
+			assert.ErrorContains(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+func TestVerifyBindingShouldFailWhenANICIsMissing(t *testing.T) {
+	runner := runnerStub{outputs: map[string]string{"lspci -k -s " + eastPCIAddress: boundNICOutput}}
+
+	assert.ErrorContains(t, vfio.VerifyBinding(runner, []string{eastPCIAddress, westPCIAddress}), "NIC 0000:07:00.0 was not found")
+}
+
+func TestVerifyBindingShouldFailWhenACommandFails(t *testing.T) {
+	expectedErr := errors.New("console is not responding")
+
+	assert.ErrorIs(t, vfio.VerifyBinding(runnerStub{err: expectedErr}, []string{eastPCIAddress}), expectedErr)
+}
+
+type runnerStub struct {
+	outputs map[string]string
+	err     error
+}
+
+func (r runnerStub) GetCommandOutput(command string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.outputs[command], nil
+}
@@ -0,0 +1,94 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package executor
+
+import (
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/l3fwd"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/testpmd"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+// workload is the DPDK application under test, forwarding the traffic generator traffic in the VM under test.
+type workload interface {
+	Name() string
+	Run() error
+	Command() string
+	ClearStats() error
+	GetStats() (testpmd.Stats, error)
+	KeepAlive() error
+}
+
+type testpmdWorkload struct {
+	*testpmd.TestpmdConsole
+}
+
+func (w testpmdWorkload) Name() string {
+	return config.VMUnderTestWorkloadTestpmd
+}
+
+type l3fwdWorkload struct {
+	*l3fwd.Console
+}
+
+func (w l3fwdWorkload) Name() string {
+	return config.VMUnderTestWorkloadL3fwd
+}
+
+// GetStats reports the l3fwd port stats the same way testpmd reports its forward stats.
+func (w l3fwdWorkload) GetStats() (testpmd.Stats, error) {
+	portsStats, err := w.Console.GetStats()
+	if err != nil {
+		return testpmd.Stats{}, err
+	}
+
+	var stats testpmd.Stats
+	for _, portStats := range portsStats {
+		ps := testpmd.PortStats(portStats)
+		stats.Ports = append(stats.Ports, ps)
+
+		stats.Summary.RXPackets += ps.RXPackets
+		stats.Summary.RXDropped += ps.RXDropped
+		stats.Summary.RXTotal += ps.RXTotal
+		stats.Summary.TXPackets += ps.TXPackets
+		stats.Summary.TXDropped += ps.TXDropped
+		stats.Summary.TXTotal += ps.TXTotal
+	}
+
+	return stats, nil
+}
+
+// l3fwdPorts maps the test interfaces to l3fwd ports.
+// The traffic sent by each traffic generator source port is routed to the paired port, toward its traffic generator peer.
+// The ports PCI addresses are set once the guest discovers them.
+func l3fwdPorts(interfaces []config.Interface) []l3fwd.Port {
+	var ports []l3fwd.Port
+	for portIdx, iface := range interfaces {
+		port := l3fwd.Port{EthDestMACAddress: iface.TrafficGenMacAddress.String()}
+		if portIdx%2 == 0 && portIdx+1 < len(interfaces) {
+			port.DestIPv4Prefix = trex.DestIPv4Prefix(trafficgen.PortIdx(portIdx))
+			port.DestIPv6Prefix = trex.DestIPv6Prefix(trafficgen.PortIdx(portIdx))
+			port.DestinationPortIdx = portIdx + 1
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
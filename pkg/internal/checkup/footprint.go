@@ -0,0 +1,54 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// resourceFootprint sums the node resources dedicated to the given VMIs: the dedicated CPUs (including the isolated
+// emulator thread), the hugepages backing the guest memory and the VFs attached to the SR-IOV interfaces.
+func resourceFootprint(vmis ...*kvcorev1.VirtualMachineInstance) *status.ResourceFootprint {
+	const bytesInGiB = 1 << 30
+
+	footprint := &status.ResourceFootprint{}
+	for _, vmi := range vmis {
+		if cpu := vmi.Spec.Domain.CPU; cpu != nil && cpu.DedicatedCPUPlacement {
+			footprint.DedicatedCPUs += int(cpu.Sockets * cpu.Cores * cpu.Threads)
+			if cpu.IsolateEmulatorThread {
+				footprint.DedicatedCPUs++
+			}
+		}
+
+		if memory := vmi.Spec.Domain.Memory; memory != nil && memory.Hugepages != nil && memory.Guest != nil {
+			footprint.HugepagesGiB += memory.Guest.Value() / bytesInGiB
+		}
+
+		for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+			if iface.SRIOV != nil {
+				footprint.VFs++
+			}
+		}
+	}
+
+	return footprint
+}
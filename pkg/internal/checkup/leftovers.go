@@ -0,0 +1,167 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+// ErrAnotherCheckupRunning is returned by Setup when resources of other checkup runs are found in the namespace.
+var ErrAnotherCheckupRunning = errors.New("another dpdk-checkup appears to be running")
+
+// checkOtherRuns verifies no other checkup run is using the namespace, as concurrent runs compete for the same VFs.
+// The resources left by crashed runs are deleted first.
+func (c *Checkup) checkOtherRuns(ctx context.Context) error {
+	otherRunsObjects := c.reconcileOtherRuns(ctx)
+	if len(otherRunsObjects) == 0 {
+		return nil
+	}
+
+	if c.params.AllowConcurrentRuns {
+		log.Printf("Running alongside other checkup runs, whose resources were found: %s", strings.Join(otherRunsObjects, ", "))
+		return nil
+	}
+
+	return fmt.Errorf("%w in namespace %q, its resources were found: %s (set %q to run anyway)",
+		ErrAnotherCheckupRunning, c.namespace, strings.Join(otherRunsObjects, ", "), config.AllowConcurrentRunsParamName)
+}
+
+// reconcileOtherRuns deletes the VMs, VMIs and ConfigMaps left in the namespace by previous checkup runs that crashed
+// before their teardown, i.e. whose owner Pod is gone or has terminated, and returns the objects of the other runs.
+// While their owner Pod is kept, e.g. by a failed Job, the garbage collector does not delete them, and the VMIs keep
+// holding their VFs and hugepages.
+// The objects of runs whose owner Pod is still running, and of runs with no owner Pod, are kept.
+// The reconciliation is best-effort, the objects that fail to be listed or deleted are skipped.
+func (c *Checkup) reconcileOtherRuns(ctx context.Context) []string {
+	labelSelector := DPDKCheckupUIDLabelKey + "," + DPDKCheckupUIDLabelKey + "!=" + c.params.CheckupUID
+	ownerPods := ownerPodsStatus{client: c.client, namespace: c.namespace, crashed: map[types.UID]bool{}}
+	var otherRunsObjects []string
+
+	// The VMIs of VMs are owned by their VM, they are deleted along with it.
+	vms, err := c.client.ListVirtualMachines(ctx, c.namespace, labelSelector)
+	if err != nil {
+		log.Printf("Skipping the check of other runs VMs: %v", err)
+	} else {
+		for i := range vms.Items {
+			if ownerPods.isCrashed(ctx, vms.Items[i].ObjectMeta) {
+				c.deleteLeftover(ctx, "VM", vms.Items[i].Name, c.client.DeleteVirtualMachine)
+			} else {
+				otherRunsObjects = append(otherRunsObjects, "VM "+ObjectFullName(c.namespace, vms.Items[i].Name))
+			}
+		}
+	}
+
+	vmis, err := c.client.ListVirtualMachineInstances(ctx, c.namespace, labelSelector)
+	if err != nil {
+		log.Printf("Skipping the check of other runs VMIs: %v", err)
+	} else {
+		for i := range vmis.Items {
+			switch {
+			case ownerPods.isCrashed(ctx, vmis.Items[i].ObjectMeta):
+				c.deleteLeftover(ctx, "VMI", vmis.Items[i].Name, c.client.DeleteVirtualMachineInstance)
+			case !isOwnedByVM(vmis.Items[i].ObjectMeta):
+				otherRunsObjects = append(otherRunsObjects, "VMI "+ObjectFullName(c.namespace, vmis.Items[i].Name))
+			}
+		}
+	}
+
+	configMaps, err := c.client.ListConfigMaps(ctx, c.namespace, labelSelector)
+	if err != nil {
+		log.Printf("Skipping the check of other runs ConfigMaps: %v", err)
+	} else {
+		for i := range configMaps.Items {
+			if ownerPods.isCrashed(ctx, configMaps.Items[i].ObjectMeta) {
+				c.deleteLeftover(ctx, "ConfigMap", configMaps.Items[i].Name, c.client.DeleteConfigMap)
+			} else {
+				otherRunsObjects = append(otherRunsObjects, "ConfigMap "+ObjectFullName(c.namespace, configMaps.Items[i].Name))
+			}
+		}
+	}
+
+	return otherRunsObjects
+}
+
+// isOwnedByVM reports whether the VMI is run by a VM, which is reported in its place.
+func isOwnedByVM(objectMeta metav1.ObjectMeta) bool {
+	for _, ownerReference := range objectMeta.OwnerReferences {
+		if ownerReference.Kind == kvcorev1.VirtualMachineGroupVersionKind.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checkup) deleteLeftover(ctx context.Context, kind, name string,
+	deleteFn func(ctx context.Context, namespace, name string) error) {
+	log.Printf("Deleting %s %q, left by a crashed checkup run...", kind, ObjectFullName(c.namespace, name))
+	if err := deleteFn(ctx, c.namespace, name); err != nil && !k8serrors.IsNotFound(err) {
+		log.Printf("failed to delete %s %q: %v", kind, ObjectFullName(c.namespace, name), err)
+	}
+}
+
+// ownerPodsStatus tells whether the owner Pods of the checkup objects have crashed, looking each Pod up once.
+type ownerPodsStatus struct {
+	client    kubeVirtVMIClient
+	namespace string
+	crashed   map[types.UID]bool
+}
+
+// isCrashed reports whether the object owner Pod is gone, has been replaced by another Pod of the same name, or has
+// terminated. Objects with no owner Pod are never reported, as there is no telling whether their run is still going.
+func (s ownerPodsStatus) isCrashed(ctx context.Context, objectMeta metav1.ObjectMeta) bool {
+	for _, ownerReference := range objectMeta.OwnerReferences {
+		if ownerReference.Kind != "Pod" {
+			continue
+		}
+
+		if crashed, known := s.crashed[ownerReference.UID]; known {
+			return crashed
+		}
+
+		pod, err := s.client.GetPod(ctx, s.namespace, ownerReference.Name)
+		switch {
+		case k8serrors.IsNotFound(err):
+			s.crashed[ownerReference.UID] = true
+		case err != nil:
+			log.Printf("failed to get Pod %q: %v", ObjectFullName(s.namespace, ownerReference.Name), err)
+			return false
+		default:
+			s.crashed[ownerReference.UID] = pod.UID != ownerReference.UID ||
+				pod.Status.Phase == k8scorev1.PodSucceeded || pod.Status.Phase == k8scorev1.PodFailed
+		}
+
+		return s.crashed[ownerReference.UID]
+	}
+
+	return false
+}
@@ -0,0 +1,162 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+const computeContainerName = "compute"
+
+// collectLauncherSecurityPosture reads the security context effectively applied to the virt-launcher Pod of the given VMI.
+// The container level settings of the compute container take precedence over the Pod level ones.
+func (c *Checkup) collectLauncherSecurityPosture(ctx context.Context,
+	vmi *kvcorev1.VirtualMachineInstance) (*status.LauncherSecurityPosture, error) {
+	pod, err := c.launcherPod(ctx, vmi)
+	if err != nil {
+		return nil, err
+	}
+
+	return launcherSecurityPosture(pod), nil
+}
+
+// launcherPod returns the virt-launcher Pod of the given VMI, the running one when there are several.
+func (c *Checkup) launcherPod(ctx context.Context, vmi *kvcorev1.VirtualMachineInstance) (*k8scorev1.Pod, error) {
+	labelSelector := fmt.Sprintf("%s=%s", kvcorev1.CreatedByLabel, vmi.UID)
+	pods, err := c.client.ListPods(ctx, c.namespace, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := activeLauncherPod(pods.Items)
+	if pod == nil {
+		return nil, fmt.Errorf("virt-launcher Pod of VMI %q not found", ObjectFullName(c.namespace, vmi.Name))
+	}
+
+	return pod, nil
+}
+
+func activeLauncherPod(pods []k8scorev1.Pod) *k8scorev1.Pod {
+	for i := range pods {
+		if pods[i].Status.Phase == k8scorev1.PodRunning {
+			return &pods[i]
+		}
+	}
+
+	if len(pods) > 0 {
+		return &pods[0]
+	}
+
+	return nil
+}
+
+func launcherSecurityPosture(pod *k8scorev1.Pod) *status.LauncherSecurityPosture {
+	posture := &status.LauncherSecurityPosture{PodName: pod.Name}
+
+	if podSecurityContext := pod.Spec.SecurityContext; podSecurityContext != nil {
+		setSELinuxOptions(posture, podSecurityContext.SELinuxOptions)
+		setSeccompProfile(posture, podSecurityContext.SeccompProfile)
+		posture.RunAsUser = podSecurityContext.RunAsUser
+	}
+
+	containerSecurityContext := computeContainerSecurityContext(pod)
+	if containerSecurityContext == nil {
+		return posture
+	}
+
+	setSELinuxOptions(posture, containerSecurityContext.SELinuxOptions)
+	setSeccompProfile(posture, containerSecurityContext.SeccompProfile)
+	if containerSecurityContext.RunAsUser != nil {
+		posture.RunAsUser = containerSecurityContext.RunAsUser
+	}
+	if containerSecurityContext.Privileged != nil {
+		posture.Privileged = *containerSecurityContext.Privileged
+	}
+	if capabilities := containerSecurityContext.Capabilities; capabilities != nil {
+		posture.AddedCapabilities = capabilityNames(capabilities.Add)
+		posture.DroppedCapabilities = capabilityNames(capabilities.Drop)
+	}
+
+	return posture
+}
+
+func computeContainerSecurityContext(pod *k8scorev1.Pod) *k8scorev1.SecurityContext {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == computeContainerName {
+			return pod.Spec.Containers[i].SecurityContext
+		}
+	}
+
+	return nil
+}
+
+func setSELinuxOptions(posture *status.LauncherSecurityPosture, options *k8scorev1.SELinuxOptions) {
+	if options == nil {
+		return
+	}
+
+	if options.Type != "" {
+		posture.SELinuxType = options.Type
+	}
+	if options.Level != "" {
+		posture.SELinuxLevel = options.Level
+	}
+}
+
+func setSeccompProfile(posture *status.LauncherSecurityPosture, profile *k8scorev1.SeccompProfile) {
+	if profile == nil {
+		return
+	}
+
+	posture.SeccompProfile = string(profile.Type)
+	if profile.Type == k8scorev1.SeccompProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		posture.SeccompProfile += "/" + *profile.LocalhostProfile
+	}
+}
+
+func capabilityNames(capabilities []k8scorev1.Capability) []string {
+	var names []string
+	for _, capability := range capabilities {
+		names = append(names, string(capability))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (c *Checkup) collectLaunchersSecurityPosture(ctx context.Context) {
+	var err error
+	if c.results.TrafficGenLauncherSecurity, err = c.collectLauncherSecurityPosture(ctx, c.trafficGen); err != nil {
+		log.Printf("Failed to collect the traffic generator's virt-launcher security posture: %v", err)
+	}
+
+	if c.results.VMUnderTestLauncherSecurity, err = c.collectLauncherSecurityPosture(ctx, c.vmiUnderTest); err != nil {
+		log.Printf("Failed to collect the VM under test's virt-launcher security posture: %v", err)
+	}
+}
@@ -0,0 +1,428 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+const (
+	NodeReadyCheckName      = "nodeReady"
+	HugepagesCheckName      = "hugepages"
+	CPUManagerCheckName     = "cpuManager"
+	SRIOVVFsCheckName       = "sriovVFs"
+	RealtimeKernelCheckName = "realtimeKernel"
+)
+
+const (
+	KernelFlavorRealtime   = "realtime"
+	KernelFlavorLowLatency = "lowlatency"
+	KernelFlavorStandard   = "standard"
+)
+
+// Node Feature Discovery labels, exposing the preemption model the node kernel is configured with.
+const (
+	kernelConfigPreemptRTLabel = "feature.node.kubernetes.io/kernel-config.PREEMPT_RT"
+	kernelConfigPreemptLabel   = "feature.node.kubernetes.io/kernel-config.PREEMPT"
+)
+
+var (
+	// e.g. "5.14.0-284.11.1.rt14.296.el9_2.x86_64" or "5.15.0-1032-realtime"
+	realtimeKernelVersionRegex = regexp.MustCompile(`(\.rt\d+|-realtime)`)
+	// e.g. "5.15.0-76-lowlatency"
+	lowLatencyKernelVersionRegex = regexp.MustCompile(`-lowlatency`)
+)
+
+const networkResourceNameAnnotation = "k8s.v1.cni.cncf.io/resourceName"
+
+var (
+	ErrInvalidNetworkAttachmentDefinition = errors.New("invalid Network-Attachment-Definition")
+	ErrNoCapableNode                      = errors.New("no node is capable of running the checkup VMIs")
+)
+
+// vfioCapableCNITypes are the CNI plugins able to attach a VF to a VMI.
+var vfioCapableCNITypes = map[string]struct{}{
+	"sriov":       {},
+	"host-device": {},
+}
+
+// checkNodesReadiness evaluates the pre-flight checks on the nodes the checkup VMIs may be scheduled to.
+func (c *Checkup) checkNodesReadiness(ctx context.Context) ([]status.NodeReadiness, error) {
+	nodes, err := c.candidateNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requiredVFs, sriovErr := c.requiredVFs(ctx)
+
+	var readiness []status.NodeReadiness
+	for i := range nodes {
+		readiness = append(readiness, nodeReadiness(&nodes[i], requiredVFs, sriovErr, c.params.RequireRealtimeKernel))
+	}
+
+	return readiness, nil
+}
+
+func (c *Checkup) candidateNodes(ctx context.Context) ([]k8scorev1.Node, error) {
+	if c.params.TrafficGenTargetNodeName != "" {
+		nodeNames := []string{c.params.TrafficGenTargetNodeName}
+		if c.params.VMUnderTestTargetNodeName != c.params.TrafficGenTargetNodeName {
+			nodeNames = append(nodeNames, c.params.VMUnderTestTargetNodeName)
+		}
+
+		var nodes []k8scorev1.Node
+		for _, nodeName := range nodeNames {
+			node, err := c.client.GetNode(ctx, nodeName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node %q: %w", nodeName, err)
+			}
+			nodes = append(nodes, *node)
+		}
+		return nodes, nil
+	}
+
+	schedulableSelector := kvcorev1.NodeSchedulable + "=true"
+	if c.params.TrafficGenNodeLabelSelector == "" && c.params.VMUnderTestNodeLabelSelector == "" {
+		nodeList, err := c.client.ListNodes(ctx, schedulableSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		return nodeList.Items, nil
+	}
+
+	// The candidates are the nodes either VMI may be scheduled on
+	var nodes []k8scorev1.Node
+	listedNodes := map[string]bool{}
+	for _, nodeLabelSelector := range []string{c.params.TrafficGenNodeLabelSelector, c.params.VMUnderTestNodeLabelSelector} {
+		labelSelector := schedulableSelector
+		if nodeLabelSelector != "" {
+			labelSelector += "," + nodeLabelSelector
+		}
+		nodeList, err := c.client.ListNodes(ctx, labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes matching %q: %w", labelSelector, err)
+		}
+		for i := range nodeList.Items {
+			if !listedNodes[nodeList.Items[i].Name] {
+				listedNodes[nodeList.Items[i].Name] = true
+				nodes = append(nodes, nodeList.Items[i])
+			}
+		}
+	}
+
+	return nodes, nil
+}
+
+// requiredVFs returns the number of VFs a single VMI allocates from each device plugin resource,
+// as each VMI is connected to a VF of the Network-Attachment-Definition of each of its test interfaces.
+// Network-Attachment-Definitions that do not specify a resource are skipped.
+func (c *Checkup) requiredVFs(ctx context.Context) (map[string]int64, error) {
+	requiredVFs := map[string]int64{}
+	for _, iface := range c.params.TestInterfaces() {
+		nadName := iface.NetworkAttachmentDefinitionName
+		nad, err := c.client.GetNetworkAttachmentDefinition(ctx, c.namespace, nadName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Network-Attachment-Definition %q: %w", ObjectFullName(c.namespace, nadName), err)
+		}
+
+		if resourceName := nad.Annotations[networkResourceNameAnnotation]; resourceName != "" {
+			requiredVFs[resourceName]++
+		}
+	}
+
+	return requiredVFs, nil
+}
+
+// validateNetworkAttachmentDefinitions verifies the Network-Attachment-Definitions of all the test interfaces.
+func (c *Checkup) validateNetworkAttachmentDefinitions(ctx context.Context) error {
+	for _, nadName := range c.params.NetworkAttachmentDefinitionNames() {
+		if err := c.validateNetworkAttachmentDefinition(ctx, nadName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateNetworkAttachmentDefinition verifies the Network-Attachment-Definition exists, uses a CNI plugin that is able to
+// attach VFs, and points to the device plugin resource the VFs are allocated from.
+// Otherwise, the VMIs would silently fail to be scheduled until the setup times out.
+func (c *Checkup) validateNetworkAttachmentDefinition(ctx context.Context, nadName string) error {
+	nadFullName := ObjectFullName(c.namespace, nadName)
+
+	nad, err := c.client.GetNetworkAttachmentDefinition(ctx, c.namespace, nadName)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidNetworkAttachmentDefinition, nadFullName, err)
+	}
+
+	cniTypes, err := networkCNITypes(nad.Spec.Config)
+	if err != nil {
+		return fmt.Errorf("%w %q: failed to parse its config: %v", ErrInvalidNetworkAttachmentDefinition, nadFullName, err)
+	}
+
+	if !hasVFIOCapableCNIType(cniTypes) {
+		return fmt.Errorf("%w %q: CNI type %q is not SR-IOV capable",
+			ErrInvalidNetworkAttachmentDefinition, nadFullName, strings.Join(cniTypes, ","))
+	}
+
+	if nad.Annotations[networkResourceNameAnnotation] == "" {
+		return fmt.Errorf("%w %q: missing %q annotation",
+			ErrInvalidNetworkAttachmentDefinition, nadFullName, networkResourceNameAnnotation)
+	}
+
+	return nil
+}
+
+// networkCNITypes returns the CNI plugin types of a network config, either a single plugin config or a plugins list.
+func networkCNITypes(rawConfig string) ([]string, error) {
+	type pluginConfig struct {
+		Type string `json:"type"`
+	}
+	var networkConfig struct {
+		pluginConfig
+		Plugins []pluginConfig `json:"plugins"`
+	}
+
+	if err := json.Unmarshal([]byte(rawConfig), &networkConfig); err != nil {
+		return nil, err
+	}
+
+	var cniTypes []string
+	if networkConfig.Type != "" {
+		cniTypes = append(cniTypes, networkConfig.Type)
+	}
+	for _, plugin := range networkConfig.Plugins {
+		cniTypes = append(cniTypes, plugin.Type)
+	}
+
+	return cniTypes, nil
+}
+
+func hasVFIOCapableCNIType(cniTypes []string) bool {
+	for _, cniType := range cniTypes {
+		if _, exists := vfioCapableCNITypes[cniType]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeReadiness(node *k8scorev1.Node, requiredVFs map[string]int64, sriovErr error,
+	requireRealtimeKernel bool) status.NodeReadiness {
+	flavor := kernelFlavor(node)
+
+	checks := []status.PreflightOutcome{
+		checkNodeReady(node),
+		checkHugepages(node),
+		checkCPUManager(node),
+	}
+
+	if sriovErr != nil {
+		checks = append(checks, status.PreflightOutcome{Name: SRIOVVFsCheckName, Reason: sriovErr.Error()})
+	} else if len(requiredVFs) > 0 {
+		checks = append(checks, checkSRIOVVFs(node, requiredVFs))
+	}
+
+	if requireRealtimeKernel {
+		checks = append(checks, checkRealtimeKernel(node, flavor))
+	}
+
+	passed := 0
+	for _, check := range checks {
+		if check.Passed {
+			passed++
+		}
+	}
+
+	const fullScore = 100
+	return status.NodeReadiness{
+		NodeName:     node.Name,
+		Score:        passed * fullScore / len(checks),
+		KernelFlavor: flavor,
+		Checks:       checks,
+	}
+}
+
+func checkNodeReady(node *k8scorev1.Node) status.PreflightOutcome {
+	outcome := status.PreflightOutcome{Name: NodeReadyCheckName}
+
+	if node.Spec.Unschedulable {
+		outcome.Reason = "node is cordoned"
+		return outcome
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == k8scorev1.NodeReady && condition.Status == k8scorev1.ConditionTrue {
+			outcome.Passed = true
+			return outcome
+		}
+	}
+
+	outcome.Reason = "node is not ready"
+	return outcome
+}
+
+func checkHugepages(node *k8scorev1.Node) status.PreflightOutcome {
+	outcome := status.PreflightOutcome{Name: HugepagesCheckName}
+
+	resourceName := k8scorev1.ResourceName(k8scorev1.ResourceHugePagesPrefix + hugePageSize)
+	required := resource.MustParse(guestMemory)
+	allocatable := node.Status.Allocatable[resourceName]
+
+	if allocatable.Cmp(required) < 0 {
+		outcome.Reason = fmt.Sprintf("%s allocatable %s is less than the required %s", resourceName, allocatable.String(), guestMemory)
+		return outcome
+	}
+
+	outcome.Passed = true
+	return outcome
+}
+
+func checkCPUManager(node *k8scorev1.Node) status.PreflightOutcome {
+	outcome := status.PreflightOutcome{Name: CPUManagerCheckName}
+
+	if node.Labels[kvcorev1.CPUManager] != "true" {
+		outcome.Reason = fmt.Sprintf("node is not labeled with %s=true", kvcorev1.CPUManager)
+		return outcome
+	}
+
+	outcome.Passed = true
+	return outcome
+}
+
+func checkSRIOVVFs(node *k8scorev1.Node, requiredVFs map[string]int64) status.PreflightOutcome {
+	outcome := status.PreflightOutcome{Name: SRIOVVFsCheckName}
+
+	resourceNames := make([]string, 0, len(requiredVFs))
+	for resourceName := range requiredVFs {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	sort.Strings(resourceNames)
+
+	var reasons []string
+	for _, resourceName := range resourceNames {
+		allocatable := node.Status.Allocatable[k8scorev1.ResourceName(resourceName)]
+		if allocatable.Value() < requiredVFs[resourceName] {
+			reasons = append(reasons, fmt.Sprintf("%s allocatable %d is less than the required %d",
+				resourceName, allocatable.Value(), requiredVFs[resourceName]))
+		}
+	}
+
+	if len(reasons) > 0 {
+		outcome.Reason = strings.Join(reasons, ", ")
+		return outcome
+	}
+
+	outcome.Passed = true
+	return outcome
+}
+
+// kernelFlavor returns the flavor of the node kernel, according to its version string or Node Feature Discovery labels.
+// An empty flavor is returned when the node reports neither.
+func kernelFlavor(node *k8scorev1.Node) string {
+	kernelVersion := node.Status.NodeInfo.KernelVersion
+	switch {
+	case realtimeKernelVersionRegex.MatchString(kernelVersion), node.Labels[kernelConfigPreemptRTLabel] == "true":
+		return KernelFlavorRealtime
+	case lowLatencyKernelVersionRegex.MatchString(kernelVersion), node.Labels[kernelConfigPreemptLabel] == "true":
+		return KernelFlavorLowLatency
+	case kernelVersion != "":
+		return KernelFlavorStandard
+	default:
+		return ""
+	}
+}
+
+func checkRealtimeKernel(node *k8scorev1.Node, flavor string) status.PreflightOutcome {
+	outcome := status.PreflightOutcome{Name: RealtimeKernelCheckName}
+
+	switch flavor {
+	case KernelFlavorRealtime, KernelFlavorLowLatency:
+		outcome.Passed = true
+	case KernelFlavorStandard:
+		outcome.Reason = fmt.Sprintf("kernel %q is neither realtime nor low-latency", node.Status.NodeInfo.KernelVersion)
+	default:
+		outcome.Reason = "kernel flavor is unknown"
+	}
+
+	return outcome
+}
+
+// verifyNodesCapability fails when the VMIs cannot be scheduled to any of the candidate nodes:
+// When target nodes are specified, all of them should pass the checks. Otherwise, at least one node should pass them.
+// The failed checks of the relevant nodes are detailed, instead of waiting for the VMIs to be scheduled until the setup times out.
+func (c *Checkup) verifyNodesCapability(readiness []status.NodeReadiness) error {
+	if len(readiness) == 0 {
+		return fmt.Errorf("%w: no node is labeled with %s=true", ErrNoCapableNode, kvcorev1.NodeSchedulable)
+	}
+
+	var incapableNodes []string
+	for _, nodeReadiness := range readiness {
+		if failedChecks := failedChecksReasons(nodeReadiness); len(failedChecks) > 0 {
+			incapableNodes = append(incapableNodes,
+				fmt.Sprintf("%s: %s", nodeReadiness.NodeName, strings.Join(failedChecks, ", ")))
+		}
+	}
+
+	targetNodesSpecified := c.params.TrafficGenTargetNodeName != ""
+	if len(incapableNodes) == 0 || !targetNodesSpecified && len(incapableNodes) < len(readiness) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrNoCapableNode, strings.Join(incapableNodes, "; "))
+}
+
+func failedChecksReasons(nodeReadiness status.NodeReadiness) []string {
+	var failedChecks []string
+	for _, check := range nodeReadiness.Checks {
+		if !check.Passed {
+			failedChecks = append(failedChecks, fmt.Sprintf("%s (%s)", check.Name, check.Reason))
+		}
+	}
+
+	return failedChecks
+}
+
+func logNodesReadiness(readiness []status.NodeReadiness) {
+	for _, nodeReadiness := range readiness {
+		failedChecks := failedChecksReasons(nodeReadiness)
+		if len(failedChecks) == 0 {
+			log.Printf("Node %q readiness score: %d%%", nodeReadiness.NodeName, nodeReadiness.Score)
+			continue
+		}
+
+		log.Printf("Node %q readiness score: %d%%, failed checks: %s",
+			nodeReadiness.NodeName, nodeReadiness.Score, strings.Join(failedChecks, ", "))
+	}
+}
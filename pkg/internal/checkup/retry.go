@@ -0,0 +1,192 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/vmi"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+)
+
+const (
+	maxSetupRetryBackoff   = time.Minute
+	setupRetryDeletionTime = 30 * time.Second
+)
+
+// setupTimeout returns the timeout of the whole setup, extended to cover the retries of the VMIs setup.
+func (c *Checkup) setupTimeout() time.Duration {
+	return c.params.SetupTimeout + time.Duration(c.params.SetupRetries)*(c.params.SetupAttemptTimeout+maxSetupRetryBackoff)
+}
+
+// setupVMIs creates the VMIs and waits for them to be ready.
+// When retries are set, each attempt is limited to the setup attempt timeout. Once an attempt fails, its VMIs are
+// deleted and re-created after a growing backoff, avoiding the nodes the VMIs that did not become ready were placed on.
+// When both VMIs were scheduled on the same node while different nodes are required, the VM under test avoids that node.
+func (c *Checkup) setupVMIs(ctx context.Context) error {
+	vmiUnderTestTemplate, trafficGenTemplate := c.vmiUnderTest.DeepCopy(), c.trafficGen.DeepCopy()
+	var avoidedNodes, vmiUnderTestAvoidedNodes []string
+	backoff := c.params.SetupRetryBackoff
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := c.setupAttemptContext(ctx)
+		err := c.createVMIsAndWaitForReadiness(attemptCtx)
+		cancel()
+		if err == nil {
+			c.results.SetupRetries = attempt
+			return nil
+		}
+
+		if attempt == c.params.SetupRetries || ctx.Err() != nil {
+			c.cleanupCreatedVMIs()
+			if attempt > 0 && ctx.Err() == nil && exitcode.Of(err) == exitcode.Cancelled {
+				// The attempt timed out, while the checkup itself was not cancelled
+				err = fmt.Errorf("setup: VMIs were not ready within the %s setup attempt timeout", c.params.SetupAttemptTimeout)
+			}
+			if attempt > 0 {
+				return fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return err
+		}
+
+		log.Printf("Setup attempt %d out of %d failed, retrying in %s: %v", attempt+1, c.params.SetupRetries+1, backoff, err)
+		failedNodes, sharedNode := c.deleteFailedAttemptVMIs(ctx, errors.Is(err, ErrVMIsOnTheSameNode))
+		avoidedNodes = append(avoidedNodes, failedNodes...)
+		if sharedNode != "" {
+			log.Printf("Avoiding node %q for the VM under test on the next setup attempts, as both VMIs were placed on it", sharedNode)
+			vmiUnderTestAvoidedNodes = append(vmiUnderTestAvoidedNodes, sharedNode)
+		}
+
+		c.vmiUnderTest, c.trafficGen = vmiUnderTestTemplate.DeepCopy(), trafficGenTemplate.DeepCopy()
+		if len(avoidedNodes) > 0 {
+			log.Printf("Avoiding nodes %v on the next setup attempt", avoidedNodes)
+			avoidNodes(c.trafficGen, avoidedNodes)
+		}
+		if vmiUnderTestNodes := slices.Concat(avoidedNodes, vmiUnderTestAvoidedNodes); len(vmiUnderTestNodes) > 0 {
+			avoidNodes(c.vmiUnderTest, vmiUnderTestNodes)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return exitcode.CheckCancelled(ctx, "setup retry backoff")
+		}
+		backoff = min(2*backoff, maxSetupRetryBackoff)
+	}
+}
+
+// setupAttemptContext limits a setup attempt to the setup attempt timeout, only when it may be retried.
+func (c *Checkup) setupAttemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.params.SetupRetries == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.params.SetupAttemptTimeout)
+}
+
+// cleanupCreatedVMIs cleans up the VMIs of a failed setup, unless they are kept for inspection.
+func (c *Checkup) cleanupCreatedVMIs() {
+	for _, createdVMI := range []*kvcorev1.VirtualMachineInstance{c.vmiUnderTest, c.trafficGen} {
+		if createdVMI.Name != "" {
+			c.cleanupVMI(createdVMI.Name)
+		}
+	}
+}
+
+// deleteFailedAttemptVMIs deletes the VMIs of a failed setup attempt, even when failed VMIs are kept for inspection,
+// as they hold the resources the next attempt requires. It returns the nodes the VMIs that were not ready were placed on,
+// or, when the VMIs were co-located, the node they shared, as that node is not to blame for the failure.
+func (c *Checkup) deleteFailedAttemptVMIs(ctx context.Context, coLocated bool) (failedNodes []string, sharedNode string) {
+	delCtx, cancel := context.WithTimeout(ctx, setupRetryDeletionTime)
+	defer cancel()
+
+	for _, createdVMI := range []*kvcorev1.VirtualMachineInstance{c.vmiUnderTest, c.trafficGen} {
+		if createdVMI.Name == "" {
+			continue
+		}
+
+		if currentVMI, err := c.client.GetVirtualMachineInstance(delCtx, c.namespace, createdVMI.Name); err == nil &&
+			currentVMI.Status.NodeName != "" {
+			switch {
+			case coLocated:
+				sharedNode = currentVMI.Status.NodeName
+			case !isVMIReady(currentVMI):
+				failedNodes = append(failedNodes, currentVMI.Status.NodeName)
+			}
+		}
+
+		vmiFullName := ObjectFullName(c.namespace, createdVMI.Name)
+		log.Printf("Deleting VMI %q of the failed setup attempt", vmiFullName)
+		_ = c.deleteVMI(delCtx, createdVMI.Name)
+		if err := c.waitForVMIDeletion(delCtx, createdVMI.Name); err != nil {
+			log.Printf("Failed to wait for VMI %q disposal: %v", vmiFullName, err)
+		}
+	}
+
+	return failedNodes, sharedNode
+}
+
+// avoidNodes prevents the VMI from being scheduled on the given nodes, unless it targets a specific node.
+// When the VMI is restricted to the nodes matching a label selector, the given nodes are excluded from them.
+func avoidNodes(vmiToUpdate *kvcorev1.VirtualMachineInstance, nodeNames []string) {
+	if vmiToUpdate.Spec.Affinity == nil {
+		vmiToUpdate.Spec.Affinity = &k8scorev1.Affinity{}
+	}
+	nodeAffinity := vmiToUpdate.Spec.Affinity.NodeAffinity
+	if nodeAffinity == nil || nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		vmiToUpdate.Spec.Affinity.NodeAffinity = vmi.NewRequiredNodeAntiAffinity(nodeNames)
+		return
+	}
+
+	terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		for _, req := range terms[i].MatchExpressions {
+			if req.Key == k8scorev1.LabelHostname && req.Operator == k8scorev1.NodeSelectorOpIn {
+				return
+			}
+		}
+	}
+	avoidedNodesReq := k8scorev1.NodeSelectorRequirement{
+		Key:      k8scorev1.LabelHostname,
+		Operator: k8scorev1.NodeSelectorOpNotIn,
+		Values:   nodeNames,
+	}
+	for i := range terms {
+		terms[i].MatchExpressions = append(terms[i].MatchExpressions, avoidedNodesReq)
+	}
+}
+
+func isVMIReady(vmiToCheck *kvcorev1.VirtualMachineInstance) bool {
+	for _, condition := range vmiToCheck.Status.Conditions {
+		if condition.Type == kvcorev1.VirtualMachineInstanceReady && condition.Status == k8scorev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+)
+
+// ErrUnexpectedRuntimeClass is returned by Setup when the virt-launcher Pods do not run with the expected RuntimeClass.
+var ErrUnexpectedRuntimeClass = errors.New("unexpected virt-launcher RuntimeClass")
+
+// verifyLaunchersRuntimeClass verifies the virt-launcher Pods of both VMIs run with the RuntimeClass set by the
+// runtimeClassName param, e.g. the one of a performance profile.
+// The RuntimeClass cannot be set per VMI: KubeVirt sets it on all the virt-launcher Pods, per the defaultRuntimeClass
+// of its configuration, thus the checkup verifies it rather than sets it.
+func (c *Checkup) verifyLaunchersRuntimeClass(ctx context.Context) error {
+	if c.params.RuntimeClassName == "" {
+		return nil
+	}
+
+	for _, vmi := range []*kvcorev1.VirtualMachineInstance{c.trafficGen, c.vmiUnderTest} {
+		pod, err := c.launcherPod(ctx, vmi)
+		if err != nil {
+			return fmt.Errorf("failed to verify the %q: %w", config.RuntimeClassNameParamName, err)
+		}
+
+		runtimeClassName := ""
+		if pod.Spec.RuntimeClassName != nil {
+			runtimeClassName = *pod.Spec.RuntimeClassName
+		}
+		if runtimeClassName != c.params.RuntimeClassName {
+			return fmt.Errorf("%w: virt-launcher Pod %q runs with RuntimeClass %q rather than %q, "+
+				"set it as the KubeVirt spec.configuration.defaultRuntimeClass",
+				ErrUnexpectedRuntimeClass, ObjectFullName(c.namespace, pod.Name), runtimeClassName, c.params.RuntimeClassName)
+		}
+		log.Printf("virt-launcher Pod %q runs with RuntimeClass %q", ObjectFullName(c.namespace, pod.Name), runtimeClassName)
+	}
+
+	return nil
+}
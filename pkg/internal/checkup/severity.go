@@ -0,0 +1,112 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// severityWeights weigh the criteria in the overall score, by their severity.
+var severityWeights = map[string]int{
+	config.SeverityBlocker: 10,
+	config.SeverityMajor:   3,
+	config.SeverityMinor:   1,
+}
+
+type criterionCheck struct {
+	name string
+	err  error
+}
+
+// checkPacketCounters checks each of the built-in criteria over the packet counters.
+func checkPacketCounters(counters status.PacketCounters) []criterionCheck {
+	checks := []criterionCheck{{name: config.CriterionErrors}, {name: config.CriterionDrops}, {name: config.CriterionLoss}}
+
+	if counters.TrafficGenOutputErrorPackets != 0 || counters.TrafficGenInputErrorPackets != 0 {
+		checks[0].err = fmt.Errorf("detected Error Packets on the traffic generator's side: Oerrors %d Ierrors %d",
+			counters.TrafficGenOutputErrorPackets, counters.TrafficGenInputErrorPackets)
+	}
+
+	if counters.VMUnderTestRxDroppedPackets != 0 || counters.VMUnderTestTxDroppedPackets != 0 {
+		checks[1].err = fmt.Errorf("detected packets dropped on the VM-Under-Test's side: RX: %d; TX: %d",
+			counters.VMUnderTestRxDroppedPackets, counters.VMUnderTestTxDroppedPackets)
+	}
+
+	if counters.TrafficGenSentPackets != counters.VMUnderTestReceivedPackets {
+		checks[2].err = fmt.Errorf("not all generated packets had reached VM-Under-Test: "+
+			"Sent from traffic generator: %d; Received on VM-Under-Test: %d", counters.TrafficGenSentPackets, counters.VMUnderTestReceivedPackets)
+	}
+
+	return checks
+}
+
+// checkDropRate checks the peak drop rate, sampled during the traffic, against the max allowed drop rate.
+func checkDropRate(maxDropRateBps, maxAllowedDropRateBps float64) criterionCheck {
+	check := criterionCheck{name: config.CriterionDropRate}
+	if maxDropRateBps > maxAllowedDropRateBps {
+		check.err = fmt.Errorf("the traffic generator's side drop rate peaked at %.0fbps, above the max allowed %.0fbps",
+			maxDropRateBps, maxAllowedDropRateBps)
+	}
+	return check
+}
+
+// score returns the percentage of the criteria weights that passed.
+func score(outcomes []status.CriterionOutcome) int {
+	var passedWeight, totalWeight int
+	for _, outcome := range outcomes {
+		totalWeight += severityWeights[outcome.Severity]
+		if outcome.Passed {
+			passedWeight += severityWeights[outcome.Severity]
+		}
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return int(math.Round(float64(100*passedWeight) / float64(totalWeight)))
+}
+
+// mergeOutcomes merges the criteria outcomes of a scenario into the outcomes of the previous scenarios.
+func mergeOutcomes(merged []status.CriterionOutcome, scenarioID string, outcomes []status.CriterionOutcome) []status.CriterionOutcome {
+	for _, outcome := range outcomes {
+		if outcome.FailureReason != "" {
+			outcome.FailureReason = scenarioID + ": " + outcome.FailureReason
+		}
+
+		idx := slices.IndexFunc(merged, func(m status.CriterionOutcome) bool { return m.Name == outcome.Name })
+		if idx == -1 {
+			merged = append(merged, outcome)
+			continue
+		}
+
+		merged[idx].Passed = merged[idx].Passed && outcome.Passed
+		switch {
+		case merged[idx].FailureReason == "":
+			merged[idx].FailureReason = outcome.FailureReason
+		case outcome.FailureReason != "":
+			merged[idx].FailureReason += "; " + outcome.FailureReason
+		}
+	}
+	return merged
+}
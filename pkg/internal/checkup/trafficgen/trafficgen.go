@@ -0,0 +1,166 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package trafficgen abstracts the traffic generator the checkup sends the traffic from,
+// allowing alternate traffic generators to be plugged in.
+package trafficgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	expect "github.com/google/goexpect"
+)
+
+// ConsoleExpecter is the console of the traffic generator VMI.
+type ConsoleExpecter interface {
+	SafeExpectBatchWithResponse(expected []expect.Batcher, timeout time.Duration) ([]expect.BatchRes, error)
+}
+
+type PortIdx int
+
+const (
+	SourcePort PortIdx = iota
+	DestPort
+)
+
+// SourcePorts returns the ports the traffic is sent from, being the first port of each pair of ports.
+func SourcePorts(portsCount int) []PortIdx {
+	var ports []PortIdx
+	for port := SourcePort; int(port) < portsCount; port += 2 {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// DestPorts returns the ports the traffic is received on, being the second port of each pair of ports.
+func DestPorts(portsCount int) []PortIdx {
+	var ports []PortIdx
+	for port := DestPort; int(port) < portsCount; port += 2 {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+type PortStats struct {
+	OutputPackets int64
+	OutputErrors  int64
+	InputPackets  int64
+	InputErrors   int64
+}
+
+// GlobalStats holds the current rates over all the ports.
+type GlobalStats struct {
+	TxPps     float64
+	RxPps     float64
+	TxBps     float64
+	RxBps     float64
+	RxDropBps float64
+	CPUUtil   float64
+}
+
+// StreamStats holds the counters of a single stream, sent from a single port.
+type StreamStats struct {
+	ID        int
+	TXPort    PortIdx
+	TXPackets int64
+	RXPackets int64
+}
+
+// GatewayResolution is the outcome of resolving the default gateway of a traffic generator port.
+type GatewayResolution struct {
+	Port PortIdx
+	IP   string
+	MAC  string
+}
+
+// TrafficGenerator sends the traffic from the source ports, and counts the traffic forwarded back to the destination ports.
+type TrafficGenerator interface {
+	Name() string
+	// Setup prepares the traffic generator for sending traffic, e.g. by starting its server.
+	Setup(ctx context.Context) error
+	Start(duration time.Duration, ports ...PortIdx) error
+	Stop(ports ...PortIdx) error
+	// WaitForCompletion waits until the traffic on the given ports has completed, including its trailing packets.
+	WaitForCompletion(ctx context.Context, ports ...PortIdx) error
+	ClearStats() error
+	PortStats(port PortIdx) (PortStats, error)
+	GlobalStats() (GlobalStats, error)
+}
+
+// GatewayResolver is implemented by traffic generators that are able to resolve their ports' default gateways.
+type GatewayResolver interface {
+	ResolveGateways() ([]GatewayResolution, error)
+}
+
+// StreamStatsReader is implemented by traffic generators that are able to count the packets of each stream they send,
+// on both the port it is sent from and the port it is received on.
+type StreamStatsReader interface {
+	StreamStats() ([]StreamStats, error)
+}
+
+// MulticastSender is implemented by traffic generators that are able to send multicast and broadcast frames,
+// which the VM under test receives depending on its ports promiscuous and all-multicast modes.
+type MulticastSender interface {
+	StartMulticast(duration time.Duration, ports ...PortIdx) error
+}
+
+// BurstSender is implemented by traffic generators that are able to send line-rate bursts separated by idle gaps.
+// The bursts end on their own, once all of them were sent.
+type BurstSender interface {
+	StartBursts(burstSize int, ports ...PortIdx) error
+}
+
+// LineRateSender is implemented by traffic generators that are able to send the traffic at a percentage of the
+// ports line rate, rather than at the configured packets per second.
+type LineRateSender interface {
+	StartAtLineRatePercent(percent int, duration time.Duration, ports ...PortIdx) error
+}
+
+// Params are the parameters traffic generators are created with.
+type Params struct {
+	ConsoleExpecter      ConsoleExpecter
+	BinDirectory         string
+	PacketsPerSecond     string
+	VerbosePrintsEnabled bool
+	// DialServer connects to the given port of the traffic generator VMI, e.g. to reach the traffic generator server API.
+	DialServer func(port int) (net.Conn, error)
+}
+
+type Factory func(params Params) TrafficGenerator
+
+var ErrUnknownTrafficGenerator = errors.New("unknown traffic generator")
+
+// Registry maps the traffic generators names to their factories.
+type Registry map[string]Factory
+
+func (r Registry) Register(name string, factory Factory) {
+	r[name] = factory
+}
+
+func (r Registry) New(name string, params Params) (TrafficGenerator, error) {
+	factory, exists := r[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTrafficGenerator, name)
+	}
+	return factory(params), nil
+}
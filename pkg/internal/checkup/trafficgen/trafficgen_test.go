@@ -0,0 +1,79 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trafficgen_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+)
+
+func TestPairedPorts(t *testing.T) {
+	assert.Equal(t, []trafficgen.PortIdx{trafficgen.SourcePort}, trafficgen.SourcePorts(2))
+	assert.Equal(t, []trafficgen.PortIdx{trafficgen.DestPort}, trafficgen.DestPorts(2))
+	assert.Equal(t, []trafficgen.PortIdx{0, 2, 4, 6}, trafficgen.SourcePorts(8))
+	assert.Equal(t, []trafficgen.PortIdx{1, 3, 5, 7}, trafficgen.DestPorts(8))
+}
+
+func TestRegistry(t *testing.T) {
+	const name = "stub"
+	registry := trafficgen.Registry{}
+	registry.Register(name, func(params trafficgen.Params) trafficgen.TrafficGenerator {
+		return trafficGeneratorStub{params: params}
+	})
+
+	t.Run("creates the registered traffic generator", func(t *testing.T) {
+		params := trafficgen.Params{BinDirectory: "/opt/stub", PacketsPerSecond: "1m"}
+		trafficGenerator, err := registry.New(name, params)
+		assert.NoError(t, err)
+		assert.Equal(t, trafficGeneratorStub{params: params}, trafficGenerator)
+	})
+
+	t.Run("fails on an unknown traffic generator", func(t *testing.T) {
+		_, err := registry.New("moongen", trafficgen.Params{})
+		assert.ErrorIs(t, err, trafficgen.ErrUnknownTrafficGenerator)
+		assert.ErrorContains(t, err, "moongen")
+	})
+}
+
+type trafficGeneratorStub struct {
+	params trafficgen.Params
+}
+
+func (trafficGeneratorStub) Name() string                                         { return "stub" }
+func (trafficGeneratorStub) Setup(_ context.Context) error                        { return nil }
+func (trafficGeneratorStub) Start(_ time.Duration, _ ...trafficgen.PortIdx) error { return nil }
+func (trafficGeneratorStub) Stop(_ ...trafficgen.PortIdx) error                   { return nil }
+func (trafficGeneratorStub) WaitForCompletion(_ context.Context, _ ...trafficgen.PortIdx) error {
+	return nil
+}
+func (trafficGeneratorStub) ClearStats() error { return nil }
+
+func (trafficGeneratorStub) PortStats(_ trafficgen.PortIdx) (trafficgen.PortStats, error) {
+	return trafficgen.PortStats{}, nil
+}
+
+func (trafficGeneratorStub) GlobalStats() (trafficgen.GlobalStats, error) {
+	return trafficgen.GlobalStats{}, nil
+}
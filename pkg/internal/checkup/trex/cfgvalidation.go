@@ -0,0 +1,206 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+type cfgFile struct {
+	PortLimit       int           `json:"port_limit"`
+	Version         int           `json:"version"`
+	Interfaces      []string      `json:"interfaces"`
+	RxDesc          int           `json:"rx_desc"`
+	TxDesc          int           `json:"tx_desc"`
+	PortBandwidthGb int           `json:"port_bandwidth_gb"`
+	PortInfo        []cfgPortInfo `json:"port_info"`
+	Platform        *cfgPlatform  `json:"platform"`
+}
+
+type cfgPortInfo struct {
+	IP        string `json:"ip"`
+	DefaultGW string `json:"default_gw"`
+	SrcMAC    string `json:"src_mac"`
+	DestMAC   string `json:"dest_mac"`
+}
+
+type cfgPlatform struct {
+	MasterThreadID  *int        `json:"master_thread_id"`
+	LatencyThreadID *int        `json:"latency_thread_id"`
+	DualIf          []cfgDualIf `json:"dual_if"`
+}
+
+type cfgDualIf struct {
+	Socket  int   `json:"socket"`
+	Threads []int `json:"threads"`
+}
+
+const supportedCfgVersion = 2
+
+// ValidateCfgFile checks that the rendered trex_cfg.yaml is parsable and consistent,
+// so that configuration errors are caught before the TRex server fails to start inside the guest.
+func ValidateCfgFile(rawCfgFile string) error {
+	var cfgs []cfgFile
+	if err := yaml.UnmarshalStrict([]byte(rawCfgFile), &cfgs); err != nil {
+		return fmt.Errorf("invalid %s: %w", CfgFileName, err)
+	}
+
+	if len(cfgs) != 1 {
+		return fmt.Errorf("invalid %s: expected a single configuration entry, found %d", CfgFileName, len(cfgs))
+	}
+
+	if err := validateCfg(&cfgs[0]); err != nil {
+		return fmt.Errorf("invalid %s: %w", CfgFileName, err)
+	}
+
+	return nil
+}
+
+func validateCfg(cfg *cfgFile) error {
+	if cfg.Version != supportedCfgVersion {
+		return fmt.Errorf("unsupported version %d", cfg.Version)
+	}
+
+	if err := validateInterfaces(cfg.PortLimit, cfg.Interfaces); err != nil {
+		return err
+	}
+
+	if cfg.RxDesc <= 0 || cfg.TxDesc <= 0 {
+		return fmt.Errorf("rx_desc and tx_desc must be positive, got %d and %d", cfg.RxDesc, cfg.TxDesc)
+	}
+
+	if cfg.PortBandwidthGb <= 0 {
+		return fmt.Errorf("port_bandwidth_gb must be positive, got %d", cfg.PortBandwidthGb)
+	}
+
+	if err := validatePortInfo(cfg.PortLimit, cfg.PortInfo); err != nil {
+		return err
+	}
+
+	return validatePlatform(cfg.PortLimit, cfg.Platform)
+}
+
+func validateInterfaces(portLimit int, interfaces []string) error {
+	if portLimit <= 0 || portLimit%2 != 0 {
+		return fmt.Errorf("port_limit must be a positive even number, got %d", portLimit)
+	}
+
+	if len(interfaces) != portLimit {
+		return fmt.Errorf("expected %d interfaces, found %d", portLimit, len(interfaces))
+	}
+
+	pciAddressFormat := regexp.MustCompile(`^([0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+	placeholderFormat := regexp.MustCompile(`^` + pciAddressPlaceholderPrefix + `([0-9a-f]{2}:){5}[0-9a-f]{2}$`)
+	seen := map[string]bool{}
+	for _, iface := range interfaces {
+		if !pciAddressFormat.MatchString(iface) && !placeholderFormat.MatchString(iface) {
+			return fmt.Errorf("interface %q is not a valid PCI address", iface)
+		}
+		if seen[iface] {
+			return fmt.Errorf("interface %q is listed more than once", iface)
+		}
+		seen[iface] = true
+	}
+
+	return nil
+}
+
+func validatePortInfo(portLimit int, portInfo []cfgPortInfo) error {
+	if len(portInfo) != portLimit {
+		return fmt.Errorf("expected %d port_info entries, found %d", portLimit, len(portInfo))
+	}
+
+	for i, info := range portInfo {
+		if info.DestMAC != "" {
+			if err := validateL2PortInfo(info); err != nil {
+				return fmt.Errorf("port_info[%d]: %w", i, err)
+			}
+			continue
+		}
+		if net.ParseIP(info.IP) == nil {
+			return fmt.Errorf("port_info[%d]: invalid ip %q", i, info.IP)
+		}
+		if net.ParseIP(info.DefaultGW) == nil {
+			return fmt.Errorf("port_info[%d]: invalid default_gw %q", i, info.DefaultGW)
+		}
+	}
+
+	return nil
+}
+
+// validateL2PortInfo checks a port set to L2 mode, in which it has MAC addresses instead of IP addresses.
+func validateL2PortInfo(info cfgPortInfo) error {
+	if info.IP != "" || info.DefaultGW != "" {
+		return errors.New("dest_mac cannot be set along with ip or default_gw")
+	}
+	if _, err := net.ParseMAC(info.DestMAC); err != nil {
+		return fmt.Errorf("invalid dest_mac %q", info.DestMAC)
+	}
+	if info.SrcMAC != "" {
+		if _, err := net.ParseMAC(info.SrcMAC); err != nil {
+			return fmt.Errorf("invalid src_mac %q", info.SrcMAC)
+		}
+	}
+	return nil
+}
+
+func validatePlatform(portLimit int, platform *cfgPlatform) error {
+	if platform == nil {
+		return errors.New("platform section is missing")
+	}
+
+	if platform.MasterThreadID == nil || platform.LatencyThreadID == nil {
+		return errors.New("master_thread_id and latency_thread_id are mandatory")
+	}
+
+	if *platform.MasterThreadID == *platform.LatencyThreadID {
+		return fmt.Errorf("master_thread_id and latency_thread_id must differ, both are %d", *platform.MasterThreadID)
+	}
+
+	if expectedDualIfCount := portLimit / 2; len(platform.DualIf) != expectedDualIfCount {
+		return fmt.Errorf("expected %d dual_if entries, found %d", expectedDualIfCount, len(platform.DualIf))
+	}
+
+	usedThreads := map[int]bool{
+		*platform.MasterThreadID:  true,
+		*platform.LatencyThreadID: true,
+	}
+	for i, dualIf := range platform.DualIf {
+		if len(dualIf.Threads) == 0 {
+			return fmt.Errorf("dual_if[%d]: no traffic threads", i)
+		}
+		for _, thread := range dualIf.Threads {
+			if thread < 0 {
+				return fmt.Errorf("dual_if[%d]: invalid thread %d", i, thread)
+			}
+			if usedThreads[thread] {
+				return fmt.Errorf("dual_if[%d]: thread %d is already in use", i, thread)
+			}
+			usedThreads[thread] = true
+		}
+	}
+
+	return nil
+}
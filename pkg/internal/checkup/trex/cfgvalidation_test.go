@@ -0,0 +1,135 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex_test
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+)
+
+func TestValidateCfgFileShouldSucceedOnGeneratedFile(t *testing.T) {
+	cfgs := createSampleConfigs()
+	assert.NoError(t, trex.ValidateCfgFile(cfgs.GenerateCfgFile()))
+}
+
+func TestValidateCfgFileShouldFailWhen(t *testing.T) {
+	type failureTestCase struct {
+		description   string
+		original      string
+		replacement   string
+		expectedError string
+	}
+
+	testCases := []failureTestCase{
+		{
+			description:   "YAML is malformed",
+			original:      "  interfaces:",
+			replacement:   "  interfaces: [",
+			expectedError: "invalid trex_cfg.yaml",
+		},
+		{
+			description:   "unknown field is present",
+			original:      "  rx_desc:",
+			replacement:   "  rx_descs:",
+			expectedError: "unknown field",
+		},
+		{
+			description:   "version is unsupported",
+			original:      "  version: 2",
+			replacement:   "  version: 3",
+			expectedError: "unsupported version 3",
+		},
+		{
+			description:   "port limit does not match the interfaces count",
+			original:      "- port_limit: 2",
+			replacement:   "- port_limit: 4",
+			expectedError: "expected 4 interfaces, found 2",
+		},
+		{
+			description:   "interface is not a PCI address",
+			original:      `"pci-address-of-00:00:00:00:00:01"`,
+			replacement:   `"eth1"`,
+			expectedError: `interface "eth1" is not a valid PCI address`,
+		},
+		{
+			description:   "interface is duplicated",
+			original:      `"pci-address-of-00:00:00:00:00:01"`,
+			replacement:   `"pci-address-of-00:00:00:00:00:00"`,
+			expectedError: "is listed more than once",
+		},
+		{
+			description:   "port bandwidth is zero",
+			original:      "port_bandwidth_gb: 40",
+			replacement:   "port_bandwidth_gb: 0",
+			expectedError: "port_bandwidth_gb must be positive",
+		},
+		{
+			description:   "port info IP is invalid",
+			original:      "ip: 10.10.20.2",
+			replacement:   "ip: 10.10.20",
+			expectedError: `port_info[1]: invalid ip "10.10.20"`,
+		},
+		{
+			description:   "port info dest MAC is invalid",
+			original:      "- ip: 10.10.20.2\n      default_gw: 10.10.20.1",
+			replacement:   "- dest_mac: 00:00:00",
+			expectedError: `port_info[1]: invalid dest_mac "00:00:00"`,
+		},
+		{
+			description:   "port info dest MAC is set along with an IP",
+			original:      "default_gw: 10.10.20.1",
+			replacement:   "default_gw: 10.10.20.1\n      dest_mac: 00:00:00:00:00:03",
+			expectedError: "port_info[1]: dest_mac cannot be set along with ip or default_gw",
+		},
+		{
+			description:   "master and latency threads are the same",
+			original:      "latency_thread_id: 3",
+			replacement:   "latency_thread_id: 2",
+			expectedError: "master_thread_id and latency_thread_id must differ",
+		},
+		{
+			description:   "traffic thread overlaps the latency thread",
+			original:      "threads: [4,5,6,7]",
+			replacement:   "threads: [3,4,5,6]",
+			expectedError: "thread 3 is already in use",
+		},
+		{
+			description:   "no traffic threads are set",
+			original:      "threads: [4,5,6,7]",
+			replacement:   "threads: []",
+			expectedError: "no traffic threads",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			cfgs := createSampleConfigs()
+			cfgFile := cfgs.GenerateCfgFile()
+			assert.Contains(t, cfgFile, testCase.original)
+
+			faultyCfgFile := strings.Replace(cfgFile, testCase.original, testCase.replacement, 1)
+			assert.ErrorContains(t, trex.ValidateCfgFile(faultyCfgFile), testCase.expectedError)
+		})
+	}
+}
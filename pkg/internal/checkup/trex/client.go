@@ -27,12 +27,16 @@ import (
 	"log"
 	"path"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	expect "github.com/google/goexpect"
 
 	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
 )
 
 type consoleExpecter interface {
@@ -41,31 +45,24 @@ type consoleExpecter interface {
 
 type Client struct {
 	consoleExpecter                  consoleExpecter
+	binDirectory                     string
 	trafficGeneratorPacketsPerSecond string
-	testDuration                     time.Duration
 	verbosePrintsEnabled             bool
 }
 
-type PortIdx int
-
-const (
-	SourcePort PortIdx = iota
-	DestPort
-)
-
 const (
 	shellPrompt  = "# "
 	batchTimeout = 30 * time.Second
 )
 
 func NewClient(trafficGenConsoleExpecter consoleExpecter,
+	binDirectory,
 	trafficGeneratorPacketsPerSecond string,
-	testDuration time.Duration,
 	verbosePrintsEnabled bool) Client {
 	return Client{
 		consoleExpecter:                  trafficGenConsoleExpecter,
+		binDirectory:                     binDirectory,
 		trafficGeneratorPacketsPerSecond: trafficGeneratorPacketsPerSecond,
-		testDuration:                     testDuration,
 		verbosePrintsEnabled:             verbosePrintsEnabled,
 	}
 }
@@ -100,6 +97,9 @@ func (c Client) WaitForServerToBeReady(ctx context.Context) error {
 		return false, nil
 	}
 	if err = wait.PollImmediateUntilWithContext(ctxWithNewDeadline, interval, conditionFn); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("stopped waiting for trex-server to be ready: %w", ctx.Err())
+		}
 		if !errors.Is(err, wait.ErrWaitTimeout) {
 			return err
 		}
@@ -117,11 +117,107 @@ func (c Client) ClearStats() (string, error) {
 	return c.runTrexConsoleCmd("clear")
 }
 
-func (c Client) StartTraffic(port PortIdx) (string, error) {
-	startTrafficCmd := c.getStartTrafficCmd(port)
+func (c Client) StartTraffic(duration time.Duration, ports ...trafficgen.PortIdx) (string, error) {
+	startTrafficCmd := c.getStartTrafficCmd(StreamPyFileName, c.trafficGeneratorPacketsPerSecond+"pps", ports, duration)
+	return c.runTrexConsoleCmd(startTrafficCmd)
+}
+
+// StartTrafficAtLineRatePercent starts the unicast streams at the given percentage of the ports line rate,
+// rather than at the configured packets per second.
+func (c Client) StartTrafficAtLineRatePercent(percent int, duration time.Duration, ports ...trafficgen.PortIdx) (string, error) {
+	startTrafficCmd := c.getStartTrafficCmd(StreamPyFileName, fmt.Sprintf("%d%%", percent), ports, duration)
 	return c.runTrexConsoleCmd(startTrafficCmd)
 }
 
+// StartMulticastTraffic starts the multicast and broadcast streams of the promiscuous scenario profile.
+func (c Client) StartMulticastTraffic(duration time.Duration, ports ...trafficgen.PortIdx) (string, error) {
+	startTrafficCmd := c.getStartTrafficCmd(MulticastStreamPyFileName, c.trafficGeneratorPacketsPerSecond+"pps", ports, duration)
+	return c.runTrexConsoleCmd(startTrafficCmd)
+}
+
+// StartBurstTraffic starts the bursts of the given size of the burst scenario profile, at the ports line rate.
+// The bursts end on their own, once all of them were sent.
+func (c Client) StartBurstTraffic(burstSize int, ports ...trafficgen.PortIdx) (string, error) {
+	sb := strings.Builder{}
+	sb.WriteString("start ")
+	sb.WriteString(fmt.Sprintf("-f %s ", path.Join(StreamsPyPath, BurstStreamPyFileName)))
+	sb.WriteString("-m 100% ")
+	sb.WriteString("-p ")
+	for _, port := range ports {
+		sb.WriteString(fmt.Sprintf("%d ", port))
+	}
+	sb.WriteString(fmt.Sprintf("-t burst_size=%d", burstSize))
+	return c.runTrexConsoleCmd(sb.String())
+}
+
+func (c Client) StopTraffic(ports ...trafficgen.PortIdx) (string, error) {
+	sb := strings.Builder{}
+	sb.WriteString("stop -p")
+	for _, port := range ports {
+		sb.WriteString(fmt.Sprintf(" %d", port))
+	}
+	return c.runTrexConsoleCmd(sb.String())
+}
+
+// WaitForTrafficCompletion waits until the traffic on the given ports has completed, i.e. their packet counters have not
+// changed between two consecutive reads, so the counters read afterwards include the trailing packets.
+func (c Client) WaitForTrafficCompletion(ctx context.Context, ports ...trafficgen.PortIdx) error {
+	return waitForTrafficCompletion(ctx, c.GetPortStats, ports...)
+}
+
+func waitForTrafficCompletion(ctx context.Context, getPortStats func(trafficgen.PortIdx) (PortStats, error),
+	ports ...trafficgen.PortIdx) error {
+	const (
+		interval = time.Second
+		timeout  = 30 * time.Second
+	)
+	ctxWithNewDeadline, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastCounters []int64
+	conditionFn := func(ctx context.Context) (bool, error) {
+		var counters []int64
+		for _, port := range ports {
+			stats, err := getPortStats(port)
+			if err != nil {
+				return false, err
+			}
+			counters = append(counters, stats.Result.Opackets, stats.Result.Ipackets)
+		}
+		completed := lastCounters != nil && slices.Equal(counters, lastCounters)
+		lastCounters = counters
+		return completed, nil
+	}
+	if err := wait.PollImmediateUntilWithContext(ctxWithNewDeadline, interval, conditionFn); err != nil {
+		return fmt.Errorf("failed waiting for the traffic on ports %v to complete: %w", ports, err)
+	}
+	return nil
+}
+
+// ResolveGateways moves the ports to service mode, in which the traffic generator answers ARP and ICMP requests,
+// resolves the ports' default gateways using ARP and moves the ports back to normal mode, so the traffic runs
+// at full rate.
+func (c Client) ResolveGateways() ([]trafficgen.GatewayResolution, error) {
+	const resolveCommand = "service -a;resolve -a;service -a --off"
+	stdout, err := c.runTrexConsoleCmd(resolveCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.verbosePrintsEnabled {
+		log.Printf("ResolveGateways Response:\n%s", stdout)
+	}
+
+	resolutions := extractGatewayResolutions(stdout)
+	for _, port := range []trafficgen.PortIdx{trafficgen.SourcePort, trafficgen.DestPort} {
+		if !isPortResolved(resolutions, port) {
+			return resolutions, fmt.Errorf("failed to resolve the default gateway of port %d", port)
+		}
+	}
+
+	return resolutions, nil
+}
+
 func (c Client) GetGlobalStats() (GlobalStats, error) {
 	const (
 		globalStatsCommand    = "stats -g"
@@ -144,7 +240,7 @@ func (c Client) GetGlobalStats() (GlobalStats, error) {
 	return gs, nil
 }
 
-func (c Client) GetPortStats(port PortIdx) (PortStats, error) {
+func (c Client) GetPortStats(port trafficgen.PortIdx) (PortStats, error) {
 	const (
 		portStatsRequestKey = "get_port_stats"
 	)
@@ -165,6 +261,29 @@ func (c Client) GetPortStats(port PortIdx) (PortStats, error) {
 	return ps, nil
 }
 
+// GetStreamStats returns the flow stats of the streams, by their packet group IDs.
+func (c Client) GetStreamStats() (PgIDStats, error) {
+	const (
+		streamStatsCommand    = "stats -s"
+		streamStatsRequestKey = "get_pgid_stats"
+	)
+	streamStatsJSONString, err := c.runTrexConsoleCmdWithJSONResponse(streamStatsCommand, streamStatsRequestKey)
+	if err != nil {
+		return PgIDStats{}, fmt.Errorf("failed to get stream stats json: %w", err)
+	}
+
+	if c.verbosePrintsEnabled {
+		log.Printf("GetStreamStats JSON Response:\n%s", streamStatsJSONString)
+	}
+
+	var ss PgIDStats
+	err = json.Unmarshal([]byte(streamStatsJSONString), &ss)
+	if err != nil {
+		return PgIDStats{}, fmt.Errorf("failed to unmarshal stream stats json: %w", err)
+	}
+	return ss, nil
+}
+
 func (c Client) isServerRunning() bool {
 	const helpSubstring = "Console Commands"
 	resp, err := c.runTrexConsoleCmd("help")
@@ -215,18 +334,21 @@ func (c Client) getTrexServiceJournalctl() (string, error) {
 	return resp[0].Output, err
 }
 
-func (c Client) getStartTrafficCmd(port PortIdx) string {
+func (c Client) getStartTrafficCmd(profileFileName, multiplier string, ports []trafficgen.PortIdx, duration time.Duration) string {
 	sb := strings.Builder{}
 	sb.WriteString("start ")
-	sb.WriteString(fmt.Sprintf("-f %s ", path.Join(StreamsPyPath, StreamPyFileName)))
-	sb.WriteString(fmt.Sprintf("-m %spps ", c.trafficGeneratorPacketsPerSecond))
-	sb.WriteString(fmt.Sprintf("-p %d ", port))
-	sb.WriteString(fmt.Sprintf("-d %.0f", c.testDuration.Seconds()))
+	sb.WriteString(fmt.Sprintf("-f %s ", path.Join(StreamsPyPath, profileFileName)))
+	sb.WriteString(fmt.Sprintf("-m %s ", multiplier))
+	sb.WriteString("-p ")
+	for _, port := range ports {
+		sb.WriteString(fmt.Sprintf("%d ", port))
+	}
+	sb.WriteString(fmt.Sprintf("-d %.0f", duration.Seconds()))
 	return sb.String()
 }
 
 func (c Client) runTrexConsoleCmd(command string) (string, error) {
-	shellCommand := fmt.Sprintf("cd %s && echo %q | ./trex-console", BinDirectory, command)
+	shellCommand := fmt.Sprintf("cd %s && echo %q | ./trex-console", c.binDirectory, command)
 	resp, err := c.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
 		&expect.BSnd{S: shellCommand + "\n"},
 		&expect.BExp{R: shellPrompt},
@@ -249,7 +371,7 @@ func (c Client) runTrexConsoleCmd(command string) (string, error) {
 func (c Client) runTrexConsoleCmdWithJSONResponse(command, requestKey string) (string, error) {
 	const verboseOn = "verbose on;"
 	trexConsoleCommand := verboseOn + command
-	shellCommand := fmt.Sprintf("cd %s && echo %q | ./trex-console -q", BinDirectory, trexConsoleCommand)
+	shellCommand := fmt.Sprintf("cd %s && echo %q | ./trex-console -q", c.binDirectory, trexConsoleCommand)
 
 	resp, err := c.consoleExpecter.SafeExpectBatchWithResponse([]expect.Batcher{
 		&expect.BSnd{S: shellCommand + "\n"},
@@ -289,6 +411,29 @@ func removeUnprintableCharacters(input string) string {
 	return cleanedInput
 }
 
+func extractGatewayResolutions(stdout string) []trafficgen.GatewayResolution {
+	arpReply := regexp.MustCompile(`Port (\d+) - Rec(?:ie|ei)ved ARP reply from: ([0-9.]+), hw: ([0-9a-fA-F:]+)`)
+
+	var resolutions []trafficgen.GatewayResolution
+	for _, match := range arpReply.FindAllStringSubmatch(stdout, -1) {
+		port, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		resolutions = append(resolutions, trafficgen.GatewayResolution{Port: trafficgen.PortIdx(port), IP: match[2], MAC: match[3]})
+	}
+	return resolutions
+}
+
+func isPortResolved(resolutions []trafficgen.GatewayResolution, port trafficgen.PortIdx) bool {
+	for _, resolution := range resolutions {
+		if resolution.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
 func extractJSONString(input, requestKey string) (string, error) {
 	const (
 		responseStart = "[verbose] Server Response:\n\n"
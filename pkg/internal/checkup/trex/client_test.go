@@ -20,8 +20,11 @@
 package trex_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -29,20 +32,23 @@ import (
 
 	assert "github.com/stretchr/testify/require"
 
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/console"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
 )
 
 const (
+	binDirectory                     = "/opt/trex"
 	trafficGeneratorPacketsPerSecond = "1m"
 	testDuration                     = time.Second
 	verbosePrintsEnabled             = false
 
-	portIdx = trex.SourcePort
+	portIdx = trafficgen.SourcePort
 )
 
 func TestClearStatsSuccess(t *testing.T) {
 	expecter := expecterStub{expectTrexConsoleFailure: false}
-	c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
 	_, err := c.ClearStats()
 	assert.NoError(t, err, "ClearStats returned an error")
@@ -50,7 +56,7 @@ func TestClearStatsSuccess(t *testing.T) {
 
 func TestClearStatsFailure(t *testing.T) {
 	expecter := expecterStub{expectTrexConsoleFailure: true}
-	c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
 	_, err := c.ClearStats()
 	assert.ErrorContains(t, err, "trex command \"clear\" failed. check logs for more information")
@@ -58,23 +64,77 @@ func TestClearStatsFailure(t *testing.T) {
 
 func TestStartTrafficSuccess(t *testing.T) {
 	expecter := expecterStub{expectTrexConsoleFailure: false}
-	c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
-	_, err := c.StartTraffic(trex.SourcePort)
+	_, err := c.StartTraffic(testDuration, trafficgen.SourcePort)
 	assert.NoError(t, err, "StartTraffic returned an error")
 }
 
 func TestStartTrafficFailure(t *testing.T) {
 	expecter := expecterStub{expectTrexConsoleFailure: true}
-	c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
-	_, err := c.StartTraffic(trex.SourcePort)
+	_, err := c.StartTraffic(testDuration, trafficgen.SourcePort)
 	assert.ErrorContains(t, err, "trex command \"start -f /opt/tests/testpmd.py -m 1mpps -p 0 -d 1\" failed. check logs for more information")
 }
 
+func TestStopTrafficSuccess(t *testing.T) {
+	expecter := expecterStub{}
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+	_, err := c.StopTraffic(trafficgen.SourcePort)
+	assert.NoError(t, err, "StopTraffic returned an error")
+}
+
+func TestWaitForTrafficCompletionSuccess(t *testing.T) {
+	expecter := expecterStub{}
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+	assert.NoError(t, c.WaitForTrafficCompletion(context.Background(), portIdx))
+}
+
+func TestWaitForTrafficCompletionFailure(t *testing.T) {
+	expecter := expecterStub{expectBatchErr: errors.New("console is closed")}
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+	err := c.WaitForTrafficCompletion(context.Background(), portIdx)
+	assert.ErrorContains(t, err, "failed waiting for the traffic on ports [0] to complete")
+	assert.ErrorContains(t, err, "console is closed")
+}
+
+func TestResolveGatewaysSuccess(t *testing.T) {
+	expecter := expecterStub{}
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+	resolutions, err := c.ResolveGateways()
+	assert.NoError(t, err)
+	expected := []trafficgen.GatewayResolution{
+		{Port: trafficgen.SourcePort, IP: "10.10.10.1", MAC: "52:54:00:d0:7a:01"},
+		{Port: trafficgen.DestPort, IP: "10.10.20.1", MAC: "52:54:00:d0:7a:02"},
+	}
+	assert.Equal(t, expected, resolutions)
+}
+
+func TestResolveGatewaysFailure(t *testing.T) {
+	t.Run("when a gateway is not resolved", func(t *testing.T) {
+		expecter := expecterStub{expectUnresolvedGateway: true}
+		c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+		_, err := c.ResolveGateways()
+		assert.EqualError(t, err, "failed to resolve the default gateway of port 1")
+	})
+	t.Run("when the command fails", func(t *testing.T) {
+		expecter := expecterStub{expectTrexConsoleFailure: true}
+		c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+		_, err := c.ResolveGateways()
+		assert.ErrorContains(t, err, "failed. check logs for more information")
+	})
+}
+
 func TestGetPortStatsSuccess(t *testing.T) {
 	expecter := expecterStub{}
-	c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
 	stats, err := c.GetPortStats(portIdx)
 	assert.NoError(t, err, "GetPortStats returned an error")
@@ -105,7 +165,7 @@ func TestGetPortStatsFailure(t *testing.T) {
 			expectBatchErr: expectedBatchErr,
 		}
 
-		c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+		c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
 		stats, err := c.GetPortStats(portIdx)
 		assert.ErrorContains(t, err, expectedBatchErr.Error())
@@ -116,7 +176,7 @@ func TestGetPortStatsFailure(t *testing.T) {
 		expecter := &expecterStub{
 			timeoutErr: expectedTimeoutErr,
 		}
-		c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+		c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
 		stats, err := c.GetPortStats(portIdx)
 		assert.ErrorContains(t, err, expectedTimeoutErr.Error())
@@ -124,9 +184,49 @@ func TestGetPortStatsFailure(t *testing.T) {
 	})
 }
 
+func TestGetStatsFromRecordedSession(t *testing.T) {
+	testCases := []struct {
+		recordingFileName       string
+		expectedPortOpackets    int64
+		expectedPortMTotalTxPps float64
+		expectedGlobalMTxPps    float64
+		expectedGlobalRxPkts    int64
+		expectedGlobalTxPkts    int64
+	}{
+		{
+			recordingFileName:       "trex-console-v3.0-stats.json",
+			expectedPortOpackets:    480000000,
+			expectedPortMTotalTxPps: 3346474.25,
+			expectedGlobalMTxPps:    7988831,
+			expectedGlobalRxPkts:    431910752,
+			expectedGlobalTxPkts:    431830210,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.recordingFileName, func(t *testing.T) {
+			replayClient, expecter := newReplayExpecter(t, testCase.recordingFileName)
+			c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
+
+			portStats, err := c.GetPortStats(portIdx)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedPortOpackets, portStats.Result.Opackets)
+			assert.Equal(t, testCase.expectedPortMTotalTxPps, portStats.Result.MTotalTxPps)
+
+			globalStats, err := c.GetGlobalStats()
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedGlobalMTxPps, globalStats.Result.MTxPps)
+			assert.Equal(t, testCase.expectedGlobalRxPkts, globalStats.Result.MTotalRxPkts)
+			assert.Equal(t, testCase.expectedGlobalTxPkts, globalStats.Result.MTotalTxPkts)
+
+			assert.Zero(t, replayClient.Pending())
+		})
+	}
+}
+
 func TestGetGlobalStatsSuccess(t *testing.T) {
 	expecter := expecterStub{}
-	c := trex.NewClient(expecter, trafficGeneratorPacketsPerSecond, testDuration, verbosePrintsEnabled)
+	c := trex.NewClient(expecter, binDirectory, trafficGeneratorPacketsPerSecond, verbosePrintsEnabled)
 
 	stats, err := c.GetGlobalStats()
 	assert.NoError(t, err, "GetGlobalStats returned an error")
@@ -323,10 +423,81 @@ const (
 		"[root@dpdk-traffic-gen-jscpt trex]# "
 )
 
+const (
+	streamStatsCmd    = "cd /opt/trex && echo \"verbose on;stats -s\" | ./trex-console -q\n"
+	streamStatsOutput = "Using 'python3' as Python interpeter\r\n\r\n\r\n-=TRex Console v3.0=-\r\n\r\n" +
+		"trex>\r\nverbose set to on\r\n\r\n\r\n\r\n[verbose] Sending Request To Server:\r\n\r\n" +
+		"{\r\n    \"id\": \"ojq3rx2c\",\r\n    \"jsonrpc\": \"2.0\",\r\n    \"method\": \"get_active_pgids\",\r\n" +
+		"    \"params\": {}\r\n}\r\n\r\n\r\n\r\n[verbose] Server Response:\r\n\r\n" +
+		"{\r\n    \"id\": \"ojq3rx2c\",\r\n    \"jsonrpc\": \"2.0\",\r\n    \"result\": {\r\n" +
+		"        \"ids\": {\r\n            \"flow_stats\": [0, 100],\r\n            \"latency\": []\r\n        }\r\n    }\r\n}\r\n\r\n" +
+		"\r\n\r\n[verbose] Sending Request To Server:\r\n\r\n" +
+		"{\r\n    \"id\": \"8ktwq1bn\",\r\n    \"jsonrpc\": \"2.0\",\r\n    \"method\": \"get_pgid_stats\",\r\n" +
+		"    \"params\": {\r\n        \"pgids\": [0, 100]\r\n    }\r\n}\r\n\r\n\r\n\r\n[verbose] Server Response:\r\n\r\n" +
+		"{\r\n    \"id\": \"8ktwq1bn\",\r\n    \"jsonrpc\": \"2.0\",\r\n    \"result\": {\r\n" +
+		"        \"flow_stats\": {\r\n" +
+		"            \"0\": {\"rx_pkts\": {\"0\": 0, \"1\": 998}, \"tx_pkts\": {\"0\": 1000, \"1\": 0}},\r\n" +
+		"            \"100\": {\"rx_pkts\": {\"0\": 500, \"1\": 0}, \"tx_pkts\": {\"0\": 0, \"1\": 500}}\r\n" +
+		"        },\r\n        \"latency\": {}\r\n    }\r\n}\r\n\r\n" +
+		"trex>Shutting down RPC client\r\n\r\n[root@dpdk-traffic-gen-jscpt trex]# "
+)
+
+const (
+	stopTrafficCmd          = "cd /opt/trex && echo \"stop -p 0\" | ./trex-console\n"
+	stopCmdSuccessfulOutput = "Using 'python3' as Python interpeter\n\n\n" +
+		"Connecting to RPC server on localhost:4501                   [SUCCESS]\n\n\n" +
+		"Connecting to publisher server on localhost:4500             [SUCCESS]\n\n\n" +
+		"Acquiring ports [0, 1]:                                      [SUCCESS]\n\n" +
+		"trex>Stopping traffic on port(s) [0._]:                      [SUCCESS]\n\n" +
+		"11.12 [ms]\n\ntrex>Shutting down RPC client"
+)
+
+const (
+	resolveCmd          = "cd /opt/trex && echo \"service -a;resolve -a;service -a --off\" | ./trex-console\n"
+	resolveOutputPrefix = "Using 'python3' as Python interpeter\n\n\n" +
+		"Connecting to RPC server on localhost:4501                   [SUCCESS]\n\n\n" +
+		"Connecting to publisher server on localhost:4500             [SUCCESS]\n\n\n" +
+		"Acquiring ports [0, 1]:                                      [SUCCESS]\n\n" +
+		"trex>Enabling service mode on port(s): [0, 1]                 [SUCCESS]\n\n" +
+		"trex>Resolving destination on port(s) [0, 1]:                "
+	resolveSuccessfulOutput = resolveOutputPrefix + "[SUCCESS]\n\n" +
+		"Port 0 - Recieved ARP reply from: 10.10.10.1, hw: 52:54:00:d0:7a:01\n" +
+		"Port 1 - Recieved ARP reply from: 10.10.20.1, hw: 52:54:00:d0:7a:02\n\n" +
+		"trex>Disabling service mode on port(s): [0, 1]                [SUCCESS]\n\n" +
+		"trex>Shutting down RPC client\n\n" +
+		"[root@dpdk-traffic-gen-jscpt trex]# "
+	resolveUnresolvedGatewayOutput = resolveOutputPrefix + "\n\n" +
+		"Port 0 - Recieved ARP reply from: 10.10.10.1, hw: 52:54:00:d0:7a:01\n" +
+		"Port 1 - *timeout*\n\n" +
+		"trex>Disabling service mode on port(s): [0, 1]                [SUCCESS]\n\n" +
+		"trex>Shutting down RPC client\n\n" +
+		"[root@dpdk-traffic-gen-jscpt trex]# "
+	resolveFailedOutput = resolveOutputPrefix + "[FAILED]\n\n" +
+		"trex>Shutting down RPC client\n\n" +
+		"[root@dpdk-traffic-gen-jscpt trex]# "
+)
+
+// newReplayExpecter returns an expecter replaying the recorded session of the traffic generator.
+func newReplayExpecter(t *testing.T, recordingFileName string) (*console.ReplayClient, console.Expecter) {
+	const (
+		recordedVMINamespace = "dpdk-checkup-ns"
+		recordedVMIName      = "dpdk-traffic-gen-jscpt"
+	)
+
+	data, err := os.ReadFile(path.Join("testdata", "recordings", recordingFileName))
+	assert.NoError(t, err)
+	recording, err := console.ParseRecording(data)
+	assert.NoError(t, err)
+
+	replayClient := console.NewReplayClient(recording)
+	return replayClient, console.NewExpecter(replayClient, recordedVMINamespace, recordedVMIName)
+}
+
 type expecterStub struct {
 	expectBatchErr           error
 	timeoutErr               error
 	expectTrexConsoleFailure bool
+	expectUnresolvedGateway  bool
 }
 
 func (es expecterStub) SafeExpectBatchWithResponse(expected []expect.Batcher, _ time.Duration) ([]expect.BatchRes, error) {
@@ -375,6 +546,30 @@ func (es expecterStub) SafeExpectBatchWithResponse(expected []expect.Batcher, _
 				Idx:    1,
 				Output: consoleResponse,
 			})
+	case stopTrafficCmd:
+		batchRes = append(batchRes,
+			expect.BatchRes{
+				Idx:    1,
+				Output: stopCmdSuccessfulOutput,
+			})
+	case resolveCmd:
+		consoleResponse := resolveSuccessfulOutput
+		if es.expectTrexConsoleFailure {
+			consoleResponse = resolveFailedOutput
+		} else if es.expectUnresolvedGateway {
+			consoleResponse = resolveUnresolvedGatewayOutput
+		}
+		batchRes = append(batchRes,
+			expect.BatchRes{
+				Idx:    1,
+				Output: consoleResponse,
+			})
+	case streamStatsCmd:
+		batchRes = append(batchRes,
+			expect.BatchRes{
+				Idx:    1,
+				Output: streamStatsOutput,
+			})
 	default:
 		return nil, fmt.Errorf("command not recognized: %s", expected[0].Arg())
 	}
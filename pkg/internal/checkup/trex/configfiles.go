@@ -21,9 +21,12 @@ package trex
 
 import (
 	"fmt"
+	"net"
 	"path"
+	"strconv"
 	"strings"
 
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
 )
 
@@ -31,81 +34,171 @@ const (
 	CfgFileName                = "trex_cfg.yaml"
 	StreamPyFileName           = "testpmd.py"
 	StreamPeerParamsPyFileName = "testpmd_addr.py"
+	MulticastStreamPyFileName  = "testpmd_multicast.py"
+	BurstStreamPyFileName      = "testpmd_burst.py"
 	StreamsPyPath              = "/opt/tests"
 	ExecutionScriptName        = "run_trex_daemon"
-	BinDirectory               = "/opt/trex"
 	SystemdUnitFileName        = "trex.service"
 )
 
+// PCIAddressPlaceholder returns the placeholder of the PCI address of the traffic generator NIC with the given MAC
+// address in trex_cfg.yaml. The guest boot script replaces it with the PCI address it discovers the NIC at.
+func PCIAddressPlaceholder(macAddress net.HardwareAddr) string {
+	return pciAddressPlaceholderPrefix + macAddress.String()
+}
+
+const pciAddressPlaceholderPrefix = "pci-address-of-"
+
+// PGIDPortStride is the gap between the packet group IDs of the streams of consecutive ports, when the streams are
+// counted by the flow stats. The packet group ID of a stream is its port index times the stride, plus the stream index.
+const PGIDPortStride = 100
+
+// The bursts each port of the burst profile sends, separated by the inter-burst gap, in microseconds.
+const (
+	BurstCount        = 1000
+	BurstGapMicrosecs = 1000
+)
+
 type Config struct {
-	masterCPU                      string
-	latencyCPU                     string
-	trafficCPUs                    string
-	numOfTrafficCPUs               string
-	portBandwidthGB                string
-	trafficGeneratorEastMacAddress string
-	trafficGeneratorWestMacAddress string
-	DPDKEastMacAddress             string
-	DPDKWestMacAddress             string
-	rxDesc                         string
-	txDesc                         string
+	binDirectory    string
+	binaryName      string
+	masterCPU       string
+	latencyCPU      string
+	trafficCPUs     []string
+	portBandwidthGB string
+	interfaces      []config.Interface
+	rxDesc          string
+	txDesc          string
+	serviceMode     bool
+	vlanID          int
+	ipFamily        string
+	gatewayMACs     []net.HardwareAddr
+	portIPs         []net.IP
+	portGateways    []net.IP
+	flowCount       int
+	flowStats       bool
+	serverCores     int
+	ioMode          int
+	scapyServer     bool
+	softwareMode    bool
 }
 
 func NewConfig(cfg config.Config) Config {
 	const (
-		masterCPU        = "2"
-		latencyCPU       = "3"
-		trafficCPUs      = "4,5,6,7"
-		numOfTrafficCPUs = "4"
-		rxDesc           = "4096"
-		txDesc           = "4096"
+		rxDesc = "4096"
+		txDesc = "4096"
 	)
+	cpus := cfg.TrexCPUAssignment()
+	var trafficCPUs []string
+	for _, cpu := range cpus.Traffic {
+		trafficCPUs = append(trafficCPUs, strconv.Itoa(cpu))
+	}
+	var portIPs, portGateways []net.IP
+	for portIdx := range cfg.TestInterfaces() {
+		ip, gateway := cfg.TrafficGenPortIPAddresses(portIdx)
+		portIPs = append(portIPs, ip)
+		portGateways = append(portGateways, gateway)
+	}
 	return Config{
-		masterCPU:                      masterCPU,
-		latencyCPU:                     latencyCPU,
-		trafficCPUs:                    trafficCPUs,
-		numOfTrafficCPUs:               numOfTrafficCPUs,
-		portBandwidthGB:                fmt.Sprintf("%d", cfg.PortBandwidthGbps),
-		trafficGeneratorEastMacAddress: cfg.TrafficGenEastMacAddress.String(),
-		trafficGeneratorWestMacAddress: cfg.TrafficGenWestMacAddress.String(),
-		DPDKEastMacAddress:             cfg.VMUnderTestEastMacAddress.String(),
-		DPDKWestMacAddress:             cfg.VMUnderTestWestMacAddress.String(),
-		rxDesc:                         rxDesc,
-		txDesc:                         txDesc,
+		binDirectory:    path.Dir(cfg.TrexBinaryPath),
+		binaryName:      path.Base(cfg.TrexBinaryPath),
+		masterCPU:       strconv.Itoa(cpus.Master),
+		latencyCPU:      strconv.Itoa(cpus.Latency),
+		trafficCPUs:     trafficCPUs,
+		portBandwidthGB: fmt.Sprintf("%d", cfg.PortBandwidthGbps),
+		interfaces:      cfg.TestInterfaces(),
+		rxDesc:          rxDesc,
+		txDesc:          txDesc,
+		serviceMode:     cfg.TrafficGenServiceMode,
+		vlanID:          cfg.TrafficVlanID,
+		ipFamily:        cfg.IPFamily,
+		gatewayMACs:     gatewayMacAddresses(cfg),
+		portIPs:         portIPs,
+		portGateways:    portGateways,
+		flowCount:       cfg.TrafficGenFlowCount,
+		flowStats:       cfg.TrafficGenFlowStats,
+		serverCores:     cfg.TrafficGenServerCores,
+		ioMode:          cfg.TrafficGenServerIOMode,
+		scapyServer:     cfg.TrafficGenScapyServer,
+		softwareMode:    cfg.TrafficGenSoftwareMode,
 	}
 }
 
+func gatewayMacAddresses(cfg config.Config) []net.HardwareAddr {
+	if !cfg.Routed() {
+		return nil
+	}
+
+	var addresses []net.HardwareAddr
+	for portIdx := range cfg.TestInterfaces() {
+		addresses = append(addresses, cfg.GatewayMacAddress(portIdx))
+	}
+	return addresses
+}
+
+// destMacAddress returns the destination MAC address of the traffic sent through the given port:
+// its gateway when the traffic is routed, otherwise the VM under test.
+func (c Config) destMacAddress(portIdx int) string {
+	if len(c.gatewayMACs) > 0 {
+		return c.gatewayMACs[portIdx].String()
+	}
+	return c.interfaces[portIdx].VMUnderTestMacAddress.String()
+}
+
+// trafficCPUsPerPortsPair returns how many of the traffic CPUs are dedicated to each pair of ports,
+// as the traffic CPUs are evenly split between the pairs.
+func (c Config) trafficCPUsPerPortsPair() int {
+	return len(c.trafficCPUs) / c.portsPairsCount()
+}
+
+// coresPerPortsPair returns how many cores the TRex server runs the traffic of each pair of ports on:
+// the explicitly set count, otherwise all the traffic CPUs of the pair.
+func (c Config) coresPerPortsPair() int {
+	if c.serverCores > 0 {
+		return c.serverCores
+	}
+	return c.trafficCPUsPerPortsPair()
+}
+
+func (c Config) portsPairsCount() int {
+	return len(c.interfaces) / 2
+}
+
 func (c Config) GenerateCfgFile() string {
-	const cfgTemplate = `- port_limit: 2
-  version: 2
-  interfaces:
-    - %q
-    - %q
-  rx_desc: %s
-  tx_desc: %s
-  port_bandwidth_gb: %s
-  port_info:
-    - ip: 10.10.10.2
-      default_gw: 10.10.10.1
-    - ip: 10.10.20.2
-      default_gw: 10.10.20.1
-  platform:
-    master_thread_id: %s
-    latency_thread_id: %s
-    dual_if:
-      - socket: 0
-        threads: [%s]
-`
-	return fmt.Sprintf(cfgTemplate,
-		config.VMIEastNICPCIAddress,
-		config.VMIWestNICPCIAddress,
-		c.rxDesc,
-		c.txDesc,
-		c.portBandwidthGB,
-		c.masterCPU,
-		c.latencyCPU,
-		c.trafficCPUs,
-	)
+	sb := strings.Builder{}
+
+	sb.WriteString(fmt.Sprintf("- port_limit: %d\n", len(c.interfaces)))
+	sb.WriteString("  version: 2\n")
+	sb.WriteString("  interfaces:\n")
+	for _, iface := range c.interfaces {
+		sb.WriteString(fmt.Sprintf("    - %q\n", PCIAddressPlaceholder(iface.TrafficGenMacAddress)))
+	}
+	sb.WriteString(fmt.Sprintf("  rx_desc: %s\n", c.rxDesc))
+	sb.WriteString(fmt.Sprintf("  tx_desc: %s\n", c.txDesc))
+	sb.WriteString(fmt.Sprintf("  port_bandwidth_gb: %s\n", c.portBandwidthGB))
+	sb.WriteString("  port_info:\n")
+	for portIdx, iface := range c.interfaces {
+		if c.ipFamily == config.IPFamilyIPv6 {
+			// TRex ports are IPv4 only, thus they are set to L2 mode, sending the traffic to its destination MAC
+			sb.WriteString(fmt.Sprintf("    - src_mac: %s\n", iface.TrafficGenMacAddress.String()))
+			sb.WriteString(fmt.Sprintf("      dest_mac: %s\n", c.destMacAddress(portIdx)))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("    - ip: %s\n", c.portIPs[portIdx]))
+		sb.WriteString(fmt.Sprintf("      default_gw: %s\n", c.portGateways[portIdx]))
+	}
+	sb.WriteString("  platform:\n")
+	sb.WriteString(fmt.Sprintf("    master_thread_id: %s\n", c.masterCPU))
+	sb.WriteString(fmt.Sprintf("    latency_thread_id: %s\n", c.latencyCPU))
+	sb.WriteString("    dual_if:\n")
+	cpusPerPair := c.trafficCPUsPerPortsPair()
+	for pair := 0; pair < c.portsPairsCount(); pair++ {
+		sb.WriteString("      - socket: 0\n")
+		sb.WriteString(fmt.Sprintf("        threads: [%s]\n",
+			strings.Join(c.trafficCPUs[pair*cpusPerPair:(pair+1)*cpusPerPair], ",")))
+	}
+
+	return sb.String()
 }
 
 func (c Config) GenerateStreamPyFile() string {
@@ -114,8 +207,7 @@ func (c Config) GenerateStreamPyFile() string {
 from testpmd_addr import *
 
 # Wild local MACs
-mac_localport0=%q
-mac_localport1=%q
+mac_localport=[%s]
 
 class STLS1(object):
 
@@ -123,29 +215,27 @@ class STLS1(object):
         self.fsize  =64; # the size of the packet
         self.number = 0
 
-    def create_stream (self, direction = 0):
+    def create_stream (self, port_id = 0):
         size = self.fsize - 4; # HW will add 4 bytes ethernet FCS
         dport = 1026 + self.number
         self.number = self.number + 1
-        if direction == 0:
-            base_pkt =  Ether(dst=mac_telco0,src=mac_localport0)/IP(src="16.0.0.1",dst=ip_telco0)/UDP(dport=dport,sport=1026)
-        else:
-            base_pkt =  Ether(dst=mac_telco1,src=mac_localport1)/IP(src="16.1.0.1",dst=ip_telco1)/UDP(dport=dport,sport=1026)
+        base_pkt =  Ether(dst=mac_telco[port_id],src=mac_localport[port_id])%s/%s/UDP(dport=dport,sport=1026)
         pad = (60 - len(base_pkt)) * 'x'
 
         return STLStream(
             packet =
             STLPktBuilder(
-                pkt = base_pkt / pad
+                pkt = base_pkt / pad%s
             ),
-            mode = STLTXCont())
-
+            mode = STLTXCont()%s)
+%s
 
     def get_streams (self, direction = 0, **kwargs):
+        port_id = kwargs.get('port_id', direction)
         # create multiple streams, one stream per core generating traffic...
         s = []
-        for i in range(%s):
-            s.append(self.create_stream(direction = direction))
+        for i in range(%d):
+            s.append(self.create_stream(port_id = port_id))
         return s
 
 # dynamic load - used for trex console or simulator
@@ -153,45 +243,279 @@ def register():
     return STLS1()
 `
 
+	var trafficGenMacAddresses []string
+	for _, iface := range c.interfaces {
+		trafficGenMacAddresses = append(trafficGenMacAddresses, fmt.Sprintf("%q", iface.TrafficGenMacAddress.String()))
+	}
+
+	vlanLayer := ""
+	if c.vlanID != 0 {
+		vlanLayer = fmt.Sprintf("/Dot1Q(vlan=%d)", c.vlanID)
+	}
+
 	return fmt.Sprintf(streamPyTemplate,
-		c.trafficGeneratorEastMacAddress,
-		c.trafficGeneratorWestMacAddress,
-		c.numOfTrafficCPUs,
+		strings.Join(trafficGenMacAddresses, ", "),
+		vlanLayer,
+		c.streamIPLayer(),
+		c.streamFieldEngine(),
+		c.streamFlowStats(),
+		c.streamFieldEngineMethod(),
+		c.streamsPerPort(),
+	)
+}
+
+// streamIPLayer returns the IP layer of the streams' packets.
+// In dual IP family, the streams alternate between IPv4 and IPv6 packets.
+func (c Config) streamIPLayer() string {
+	const (
+		ipv4Layer = `IP(src="16.%d.0.1" % port_id,dst=ip_telco[port_id])`
+		ipv6Layer = `IPv6(src="2001:db8:16:%d::1" % port_id,dst=ip6_telco[port_id])`
+	)
+
+	switch c.ipFamily {
+	case config.IPFamilyIPv6:
+		return ipv6Layer
+	case config.IPFamilyDual:
+		return fmt.Sprintf("(%s if dport %% 2 == 0 else %s)", ipv4Layer, ipv6Layer)
+	default:
+		return ipv4Layer
+	}
+}
+
+// streamFieldEngine returns the field engine argument of the streams' packet builder, which varies their flows.
+func (c Config) streamFieldEngine() string {
+	if c.flowCount == 0 {
+		return ""
+	}
+	return ",\n                vm = self.create_vm(base_pkt)"
+}
+
+// streamFlowStats returns the flow stats argument of the streams, counting the packets of each stream
+// by its packet group ID, on both the port it is sent from and the port it is received on.
+func (c Config) streamFlowStats() string {
+	if !c.flowStats {
+		return ""
+	}
+	return fmt.Sprintf(",\n            flow_stats = STLFlowStats(pg_id = port_id * %d + self.number - 1)", PGIDPortStride)
+}
+
+// streamFieldEngineMethod returns the method creating the streams' field engine,
+// varying the source IP address and UDP port of the packets over the flows,
+// so the packets are spread over the VM under test receive queues by RSS.
+func (c Config) streamFieldEngineMethod() string {
+	const createVMMethodTemplate = `
+    def create_vm (self, base_pkt):
+        # vary the last two bytes of the source IP, and the source UDP port
+        ip_layer = "IPv6" if base_pkt.haslayer(IPv6) else "IP"
+        ip_offset_fixup = 14 if ip_layer == "IPv6" else 2
+        vm = [
+            STLVmFlowVar(name = "flow", min_value = 0, max_value = %d, size = 2, op = "inc"),
+            STLVmWrFlowVar(fv_name = "flow", pkt_offset = ip_layer + ".src", offset_fixup = ip_offset_fixup),
+            STLVmWrFlowVar(fv_name = "flow", pkt_offset = "UDP.sport", add_val = 1026),
+        ]
+        if ip_layer == "IP":
+            vm.append(STLVmFixIpv4(offset = "IP"))
+        return STLScVmRaw(vm)
+`
+	if c.flowCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf(createVMMethodTemplate, c.flowCount-1)
+}
+
+// streamsPerPort returns the number of streams each port transmits, one per traffic core.
+// In dual IP family, at least one stream of each IP family is transmitted.
+func (c Config) streamsPerPort() int {
+	const minDualStreams = 2
+	if c.ipFamily == config.IPFamilyDual && c.coresPerPortsPair() < minDualStreams {
+		return minDualStreams
+	}
+	return c.coresPerPortsPair()
+}
+
+// GenerateMulticastStreamPyFile returns the profile of the promiscuous scenario, sending a multicast stream and
+// a broadcast stream from each port. The VM under test ports receive the multicast frames only when their
+// promiscuous or all-multicast mode is enabled, and the broadcast frames regardless.
+func (c Config) GenerateMulticastStreamPyFile() string {
+	const multicastStreamPyTemplate = `from trex_stl_lib.api import *
+
+# Wild local MACs
+mac_localport=[%s]
+
+# the multicast and broadcast destination MAC and IP addresses
+destinations=[("01:00:5e:01:01:01", "239.1.1.1"), ("ff:ff:ff:ff:ff:ff", "255.255.255.255")]
+
+class STLS1(object):
+
+    def __init__ (self):
+        self.fsize  =64; # the size of the packet
+
+    def create_stream (self, port_id, dst_mac, dst_ip):
+        size = self.fsize - 4; # HW will add 4 bytes ethernet FCS
+        base_pkt =  Ether(dst=dst_mac,src=mac_localport[port_id])%s/IP(src="16.%%d.0.1" %% port_id,dst=dst_ip)/UDP(dport=1026,sport=1026)
+        pad = (60 - len(base_pkt)) * 'x'
+
+        return STLStream(
+            packet =
+            STLPktBuilder(
+                pkt = base_pkt / pad
+            ),
+            mode = STLTXCont())
+
+    def get_streams (self, direction = 0, **kwargs):
+        port_id = kwargs.get('port_id', direction)
+        return [self.create_stream(port_id, dst_mac, dst_ip) for dst_mac, dst_ip in destinations]
+
+# dynamic load - used for trex console or simulator
+def register():
+    return STLS1()
+`
+
+	var trafficGenMacAddresses []string
+	for _, iface := range c.interfaces {
+		trafficGenMacAddresses = append(trafficGenMacAddresses, fmt.Sprintf("%q", iface.TrafficGenMacAddress.String()))
+	}
+
+	vlanLayer := ""
+	if c.vlanID != 0 {
+		vlanLayer = fmt.Sprintf("/Dot1Q(vlan=%d)", c.vlanID)
+	}
+
+	return fmt.Sprintf(multicastStreamPyTemplate, strings.Join(trafficGenMacAddresses, ", "), vlanLayer)
+}
+
+// GenerateBurstStreamPyFile returns the profile of the burst scenario, sending a stream of bursts from each port.
+// The size of the bursts is set by the burst_size tunable, and their rate by the start multiplier.
+func (c Config) GenerateBurstStreamPyFile() string {
+	const burstStreamPyTemplate = `from trex_stl_lib.api import *
+
+from testpmd_addr import *
+
+# Wild local MACs
+mac_localport=[%s]
+
+class STLS1(object):
+
+    def create_stream (self, port_id, burst_size):
+        dport = 1026
+        base_pkt =  Ether(dst=mac_telco[port_id],src=mac_localport[port_id])%s/%s/UDP(dport=dport,sport=1026)
+        pad = (60 - len(base_pkt)) * 'x'
+
+        return STLStream(
+            packet =
+            STLPktBuilder(
+                pkt = base_pkt / pad
+            ),
+            mode = STLTXMultiBurst(pkts_per_burst = burst_size, ibg = %d, count = %d))
+
+    def get_streams (self, direction = 0, **kwargs):
+        port_id = kwargs.get('port_id', direction)
+        burst_size = int(kwargs.get('burst_size', %d))
+        return [self.create_stream(port_id, burst_size)]
+
+# dynamic load - used for trex console or simulator
+def register():
+    return STLS1()
+`
+
+	var trafficGenMacAddresses []string
+	for _, iface := range c.interfaces {
+		trafficGenMacAddresses = append(trafficGenMacAddresses, fmt.Sprintf("%q", iface.TrafficGenMacAddress.String()))
+	}
+
+	vlanLayer := ""
+	if c.vlanID != 0 {
+		vlanLayer = fmt.Sprintf("/Dot1Q(vlan=%d)", c.vlanID)
+	}
+
+	return fmt.Sprintf(burstStreamPyTemplate,
+		strings.Join(trafficGenMacAddresses, ", "),
+		vlanLayer,
+		c.streamIPLayer(),
+		BurstGapMicrosecs,
+		BurstCount,
+		config.BurstMinSize,
 	)
 }
 
 func (c Config) GenerateStreamAddrPyFile() string {
-	const streamAddrPyTemplate = `# wild first XL710 mac
-mac_telco0 = %q
-# wild second XL710 mac
-mac_telco1 = %q
+	const streamAddrPyTemplate = `# the VM under test MACs, one per port
+mac_telco = [%s]
 # we don’t care of the IP in this phase
-ip_telco0  = '10.0.0.1'
-ip_telco1 = '10.1.1.1'
+ip_telco = [%s]
 `
-	return fmt.Sprintf(streamAddrPyTemplate,
-		c.DPDKEastMacAddress,
-		c.DPDKWestMacAddress,
+	var destMacAddresses, ipAddresses, ipv6Addresses []string
+	for portIdx := range c.interfaces {
+		destMacAddresses = append(destMacAddresses, fmt.Sprintf("%q", c.destMacAddress(portIdx)))
+		ipAddresses = append(ipAddresses, fmt.Sprintf("'%s'", destIPv4Address(portIdx)))
+		ipv6Addresses = append(ipv6Addresses, fmt.Sprintf("'%s'", destIPv6Address(portIdx)))
+	}
+
+	addrPyFile := fmt.Sprintf(streamAddrPyTemplate,
+		strings.Join(destMacAddresses, ", "),
+		strings.Join(ipAddresses, ", "),
 	)
+	if c.ipFamily == config.IPFamilyIPv6 || c.ipFamily == config.IPFamilyDual {
+		addrPyFile += fmt.Sprintf("ip6_telco = [%s]\n", strings.Join(ipv6Addresses, ", "))
+	}
+
+	return addrPyFile
+}
+
+func destIPv4Address(portIdx int) string {
+	return fmt.Sprintf("10.%d.%d.1", portIdx, portIdx)
+}
+
+func destIPv6Address(portIdx int) string {
+	return fmt.Sprintf("2001:db8:10:%d::1", portIdx)
+}
+
+// DestIPv4Prefix returns the IPv4 prefix the traffic sent through the given port is destined to.
+func DestIPv4Prefix(port trafficgen.PortIdx) string {
+	return fmt.Sprintf("10.%d.%d.0/24", port, port)
+}
+
+// DestIPv6Prefix returns the IPv6 prefix the traffic sent through the given port is destined to.
+func DestIPv6Prefix(port trafficgen.PortIdx) string {
+	return fmt.Sprintf("2001:db8:10:%d::/64", port)
 }
 
 func (c Config) GenerateExecutionScript() string {
 	sb := strings.Builder{}
 
 	sb.WriteString("#!/usr/bin/env bash\n")
-	sb.WriteString(fmt.Sprintf("./t-rex-64 --no-ofed-check --no-scapy-server --no-hw-flow-stat -i -c %s --iom 0\n", c.numOfTrafficCPUs))
+	sb.WriteString(fmt.Sprintf("./%s --no-ofed-check", c.binaryName))
+	if !c.scapyServer {
+		sb.WriteString(" --no-scapy-server")
+	}
+	sb.WriteString(fmt.Sprintf(" --no-hw-flow-stat -i -c %d --iom %d", c.coresPerPortsPair(), c.ioMode))
+	if c.softwareMode {
+		// Let TRex handle the traffic in software, for NICs lacking the hardware offloads it otherwise relies on.
+		sb.WriteString(" --software")
+	}
+	if c.serviceMode {
+		// Announce the ports' addresses with gratuitous ARPs, so L3-aware fabrics learn them before the traffic starts.
+		const gratuitousARPPeriodSeconds = 10
+		sb.WriteString(fmt.Sprintf(" --arp-refresh-period %d", gratuitousARPPeriodSeconds))
+	}
+	sb.WriteString("\n")
 
 	return sb.String()
 }
 
-func GenerateSystemdUnitFile() string {
+// BinDirectory returns the directory holding the TRex binaries, which is also the TRex server's working directory.
+func (c Config) BinDirectory() string {
+	return c.binDirectory
+}
+
+func (c Config) GenerateSystemdUnitFile() string {
 	sb := strings.Builder{}
 
 	sb.WriteString("[Unit]\n")
 	sb.WriteString("Description=TRex Server\n")
 	sb.WriteString("[Service]\n")
-	sb.WriteString(fmt.Sprintf("WorkingDirectory=%s\n", BinDirectory))
-	sb.WriteString(fmt.Sprintf("ExecStart=%s\n", path.Join(BinDirectory, ExecutionScriptName)))
+	sb.WriteString(fmt.Sprintf("WorkingDirectory=%s\n", c.binDirectory))
+	sb.WriteString(fmt.Sprintf("ExecStart=%s\n", path.Join(c.binDirectory, ExecutionScriptName)))
 	sb.WriteString("Restart=no\n")
 	sb.WriteString("User=root\n")
 	sb.WriteString("Group=root\n")
@@ -38,8 +38,8 @@ func TestGetTrexCfgFile(t *testing.T) {
 	const expectedCfgFile = `- port_limit: 2
   version: 2
   interfaces:
-    - "0000:06:00.0"
-    - "0000:07:00.0"
+    - "pci-address-of-00:00:00:00:00:00"
+    - "pci-address-of-00:00:00:00:00:01"
   rx_desc: 4096
   tx_desc: 4096
   port_bandwidth_gb: 40
@@ -67,8 +67,7 @@ func TestGetTestpmdStreamPyFile(t *testing.T) {
 from testpmd_addr import *
 
 # Wild local MACs
-mac_localport0="00:00:00:00:00:00"
-mac_localport1="00:00:00:00:00:01"
+mac_localport=["00:00:00:00:00:00", "00:00:00:00:00:01"]
 
 class STLS1(object):
 
@@ -76,14 +75,11 @@ class STLS1(object):
         self.fsize  =64; # the size of the packet
         self.number = 0
 
-    def create_stream (self, direction = 0):
+    def create_stream (self, port_id = 0):
         size = self.fsize - 4; # HW will add 4 bytes ethernet FCS
         dport = 1026 + self.number
         self.number = self.number + 1
-        if direction == 0:
-            base_pkt =  Ether(dst=mac_telco0,src=mac_localport0)/IP(src="16.0.0.1",dst=ip_telco0)/UDP(dport=dport,sport=1026)
-        else:
-            base_pkt =  Ether(dst=mac_telco1,src=mac_localport1)/IP(src="16.1.0.1",dst=ip_telco1)/UDP(dport=dport,sport=1026)
+        base_pkt =  Ether(dst=mac_telco[port_id],src=mac_localport[port_id])/IP(src="16.%d.0.1" % port_id,dst=ip_telco[port_id])/UDP(dport=dport,sport=1026)
         pad = (60 - len(base_pkt)) * 'x'
 
         return STLStream(
@@ -95,10 +91,11 @@ class STLS1(object):
 
 
     def get_streams (self, direction = 0, **kwargs):
+        port_id = kwargs.get('port_id', direction)
         # create multiple streams, one stream per core generating traffic...
         s = []
         for i in range(4):
-            s.append(self.create_stream(direction = direction))
+            s.append(self.create_stream(port_id = port_id))
         return s
 
 # dynamic load - used for trex console or simulator
@@ -112,13 +109,10 @@ func TestGetTestpmdStreamAddrPyFile(t *testing.T) {
 	cfgs := createSampleConfigs()
 	addrPyFile := cfgs.GenerateStreamAddrPyFile()
 
-	const expectedAddrPyFile = `# wild first XL710 mac
-mac_telco0 = "00:00:00:00:00:02"
-# wild second XL710 mac
-mac_telco1 = "00:00:00:00:00:03"
+	const expectedAddrPyFile = `# the VM under test MACs, one per port
+mac_telco = ["00:00:00:00:00:02", "00:00:00:00:00:03"]
 # we don’t care of the IP in this phase
-ip_telco0  = '10.0.0.1'
-ip_telco1 = '10.1.1.1'
+ip_telco = ['10.0.0.1', '10.1.1.1']
 `
 	assert.Equal(t, expectedAddrPyFile, addrPyFile)
 }
@@ -136,7 +130,9 @@ func TestExecutionScript(t *testing.T) {
 }
 
 func TestSystemdUnitFile(t *testing.T) {
-	actualSystemdUnitFile := trex.GenerateSystemdUnitFile()
+	trexConfig := createSampleConfigs()
+
+	actualSystemdUnitFile := trexConfig.GenerateSystemdUnitFile()
 
 	expectedSystemdUnitFile := fmt.Sprintf(`[Unit]
 Description=TRex Server
@@ -146,21 +142,225 @@ ExecStart=%s
 Restart=no
 User=root
 Group=root
-`, trex.BinDirectory, path.Join(trex.BinDirectory, trex.ExecutionScriptName))
+`, "/opt/trex", path.Join("/opt/trex", trex.ExecutionScriptName))
 	assert.Equal(t, expectedSystemdUnitFile, actualSystemdUnitFile)
 }
 
+func TestExecutionScriptWithCustomBinaryPath(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: "/usr/local/trex/v3.02/t-rex-64-debug"}
+	trexConfig := trex.NewConfig(cfg)
+
+	assert.Equal(t, "/usr/local/trex/v3.02", trexConfig.BinDirectory())
+	assert.Contains(t, trexConfig.GenerateExecutionScript(), "\n./t-rex-64-debug --no-ofed-check")
+	assert.Contains(t, trexConfig.GenerateSystemdUnitFile(), "WorkingDirectory=/usr/local/trex/v3.02\n")
+}
+
+func TestExecutionScriptWithServiceMode(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: config.TrexBinaryPathDefault, TrafficGenServiceMode: true}
+	trexConfig := trex.NewConfig(cfg)
+
+	assert.Contains(t, trexConfig.GenerateExecutionScript(), " --iom 0 --arp-refresh-period 10\n")
+}
+
+func TestExecutionScriptWithServerParams(t *testing.T) {
+	cfg := config.Config{
+		TrexBinaryPath:         config.TrexBinaryPathDefault,
+		TrafficGenServerCores:  2,
+		TrafficGenServerIOMode: 1,
+		TrafficGenScapyServer:  true,
+		TrafficGenSoftwareMode: true,
+	}
+	trexConfig := trex.NewConfig(cfg)
+
+	expextedExecutionScript := `#!/usr/bin/env bash
+./t-rex-64 --no-ofed-check --no-hw-flow-stat -i -c 2 --iom 1 --software
+`
+	assert.Equal(t, expextedExecutionScript, trexConfig.GenerateExecutionScript())
+	assert.Contains(t, trexConfig.GenerateStreamPyFile(), "for i in range(2):")
+}
+
+func TestGetTestpmdStreamPyFileWithVlan(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: config.TrexBinaryPathDefault, TrafficVlanID: 100}
+	trexConfig := trex.NewConfig(cfg)
+
+	assert.Contains(t, trexConfig.GenerateStreamPyFile(),
+		"base_pkt =  Ether(dst=mac_telco[port_id],src=mac_localport[port_id])/Dot1Q(vlan=100)/IP(")
+}
+
+func TestGetMulticastStreamPyFileWithVlan(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: config.TrexBinaryPathDefault, TrafficVlanID: 100}
+	trexConfig := trex.NewConfig(cfg)
+
+	pyFile := trexConfig.GenerateMulticastStreamPyFile()
+	assert.Contains(t, pyFile,
+		`destinations=[("01:00:5e:01:01:01", "239.1.1.1"), ("ff:ff:ff:ff:ff:ff", "255.255.255.255")]`)
+	assert.Contains(t, pyFile,
+		`base_pkt =  Ether(dst=dst_mac,src=mac_localport[port_id])/Dot1Q(vlan=100)/IP(src="16.%d.0.1" % port_id,dst=dst_ip)/`)
+}
+
+func TestGetBurstStreamPyFile(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: config.TrexBinaryPathDefault}
+	trexConfig := trex.NewConfig(cfg)
+
+	pyFile := trexConfig.GenerateBurstStreamPyFile()
+	assert.Contains(t, pyFile, "mode = STLTXMultiBurst(pkts_per_burst = burst_size, ibg = 1000, count = 1000))")
+	assert.Contains(t, pyFile, "burst_size = int(kwargs.get('burst_size', 32))")
+}
+
+func TestGetTrexFilesWithIPv6(t *testing.T) {
+	trexConfig := createSampleConfigsWithIPFamily(config.IPFamilyIPv6)
+
+	cfgFile := trexConfig.GenerateCfgFile()
+	assert.Contains(t, cfgFile, `  port_info:
+    - src_mac: 00:00:00:00:00:00
+      dest_mac: 00:00:00:00:00:02
+    - src_mac: 00:00:00:00:00:01
+      dest_mac: 00:00:00:00:00:03
+  platform:
+`)
+	assert.NoError(t, trex.ValidateCfgFile(cfgFile))
+
+	assert.Contains(t, trexConfig.GenerateStreamPyFile(),
+		`/IPv6(src="2001:db8:16:%d::1" % port_id,dst=ip6_telco[port_id])/UDP(dport=dport,sport=1026)`)
+	assert.Contains(t, trexConfig.GenerateStreamAddrPyFile(), "ip6_telco = ['2001:db8:10:0::1', '2001:db8:10:1::1']\n")
+}
+
+func TestGetTestpmdStreamAddrPyFileWithGateway(t *testing.T) {
+	gatewayMacAddress, _ := net.ParseMAC("02:00:00:00:00:01")
+	cfg := config.Config{
+		TrexBinaryPath:                config.TrexBinaryPathDefault,
+		TrafficGenGatewayMacAddresses: []net.HardwareAddr{gatewayMacAddress},
+	}
+	trexConfig := trex.NewConfig(cfg)
+
+	assert.Contains(t, trexConfig.GenerateStreamAddrPyFile(), `mac_telco = ["02:00:00:00:00:01", "02:00:00:00:00:01"]`)
+}
+
+func TestGetTestpmdStreamPyFileWithFlowCount(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: config.TrexBinaryPathDefault, TrafficGenFlowCount: 256}
+	trexConfig := trex.NewConfig(cfg)
+
+	pyFile := trexConfig.GenerateStreamPyFile()
+	assert.Contains(t, pyFile, `                pkt = base_pkt / pad,
+                vm = self.create_vm(base_pkt)
+            ),
+            mode = STLTXCont())
+
+    def create_vm (self, base_pkt):
+`)
+	assert.Contains(t, pyFile, `STLVmFlowVar(name = "flow", min_value = 0, max_value = 255, size = 2, op = "inc")`)
+	assert.Contains(t, pyFile, `        return STLScVmRaw(vm)
+
+
+    def get_streams (self, direction = 0, **kwargs):`)
+}
+
+func TestGetTestpmdStreamPyFileWithFlowStats(t *testing.T) {
+	cfg := config.Config{TrexBinaryPath: config.TrexBinaryPathDefault, TrafficGenFlowStats: true}
+	trexConfig := trex.NewConfig(cfg)
+
+	assert.Contains(t, trexConfig.GenerateStreamPyFile(), `            mode = STLTXCont(),
+            flow_stats = STLFlowStats(pg_id = port_id * 100 + self.number - 1))
+`)
+}
+
+func TestGetTrexFilesWithDualIPFamily(t *testing.T) {
+	trexConfig := createSampleConfigsWithIPFamily(config.IPFamilyDual)
+
+	assert.Contains(t, trexConfig.GenerateCfgFile(), "    - ip: 10.10.10.2\n")
+	assert.Contains(t, trexConfig.GenerateStreamPyFile(),
+		`/(IP(src="16.%d.0.1" % port_id,dst=ip_telco[port_id]) if dport % 2 == 0 `+
+			`else IPv6(src="2001:db8:16:%d::1" % port_id,dst=ip6_telco[port_id]))/UDP(`)
+	assert.Contains(t, trexConfig.GenerateStreamAddrPyFile(), "ip_telco = ['10.0.0.1', '10.1.1.1']\n")
+	assert.Contains(t, trexConfig.GenerateStreamAddrPyFile(), "ip6_telco = ['2001:db8:10:0::1', '2001:db8:10:1::1']\n")
+}
+
+func TestGetTrexCfgFileWithFourInterfaces(t *testing.T) {
+	cfg := config.Config{
+		TrexBinaryPath:    config.TrexBinaryPathDefault,
+		PortBandwidthGbps: 25,
+		Interfaces: []config.Interface{
+			{TrafficGenMacAddress: net.HardwareAddr{0x50, 0, 0, 0, 0, 0x01}},
+			{TrafficGenMacAddress: net.HardwareAddr{0x50, 0, 0, 0, 0, 0x02}},
+			{TrafficGenMacAddress: net.HardwareAddr{0x50, 0, 0, 0, 0, 0x03}},
+			{TrafficGenMacAddress: net.HardwareAddr{0x50, 0, 0, 0, 0, 0x04}},
+		},
+	}
+	trexConfig := trex.NewConfig(cfg)
+
+	const expectedCfgFile = `- port_limit: 4
+  version: 2
+  interfaces:
+    - "pci-address-of-50:00:00:00:00:01"
+    - "pci-address-of-50:00:00:00:00:02"
+    - "pci-address-of-50:00:00:00:00:03"
+    - "pci-address-of-50:00:00:00:00:04"
+  rx_desc: 4096
+  tx_desc: 4096
+  port_bandwidth_gb: 25
+  port_info:
+    - ip: 10.10.10.2
+      default_gw: 10.10.10.1
+    - ip: 10.10.20.2
+      default_gw: 10.10.20.1
+    - ip: 10.10.30.2
+      default_gw: 10.10.30.1
+    - ip: 10.10.40.2
+      default_gw: 10.10.40.1
+  platform:
+    master_thread_id: 2
+    latency_thread_id: 3
+    dual_if:
+      - socket: 0
+        threads: [4,5]
+      - socket: 0
+        threads: [6,7]
+`
+	cfgFile := trexConfig.GenerateCfgFile()
+	assert.Equal(t, expectedCfgFile, cfgFile)
+	assert.NoError(t, trex.ValidateCfgFile(cfgFile))
+	assert.Contains(t, trexConfig.GenerateExecutionScript(), " -c 2 ")
+	assert.Contains(t, trexConfig.GenerateStreamPyFile(), "for i in range(2):")
+}
+
+func TestGetTrexCfgFileWithPortIPs(t *testing.T) {
+	cfg := config.Config{
+		TrexBinaryPath:           config.TrexBinaryPathDefault,
+		PortBandwidthGbps:        40,
+		IPFamily:                 config.IPFamilyIPv4,
+		TrafficGenEastMacAddress: net.HardwareAddr{0x50, 0, 0, 0, 0, 0x01},
+		TrafficGenWestMacAddress: net.HardwareAddr{0x50, 0, 0, 0, 0, 0x02},
+		TrafficGenPortIPs:        []net.IP{net.ParseIP("192.168.100.2"), net.ParseIP("192.168.200.2")},
+		TrafficGenPortGateways:   []net.IP{net.ParseIP("192.168.100.1"), net.ParseIP("192.168.200.1")},
+	}
+
+	cfgFile := trex.NewConfig(cfg).GenerateCfgFile()
+	assert.Contains(t, cfgFile, `  port_info:
+    - ip: 192.168.100.2
+      default_gw: 192.168.100.1
+    - ip: 192.168.200.2
+      default_gw: 192.168.200.1
+`)
+	assert.NoError(t, trex.ValidateCfgFile(cfgFile))
+}
+
 func createSampleConfigs() trex.Config {
+	return createSampleConfigsWithIPFamily(config.IPFamilyIPv4)
+}
+
+func createSampleConfigsWithIPFamily(ipFamily string) trex.Config {
 	trafficGeneratorEastMacAddress, _ := net.ParseMAC("00:00:00:00:00:00")
 	trafficGeneratorWestMacAddress, _ := net.ParseMAC("00:00:00:00:00:01")
 	DPDKEastMacAddress, _ := net.ParseMAC("00:00:00:00:00:02")
 	DPDKWestMacAddress, _ := net.ParseMAC("00:00:00:00:00:03")
 	cfg := config.Config{
+		TrexBinaryPath:            config.TrexBinaryPathDefault,
 		PortBandwidthGbps:         40,
 		TrafficGenEastMacAddress:  trafficGeneratorEastMacAddress,
 		TrafficGenWestMacAddress:  trafficGeneratorWestMacAddress,
 		VMUnderTestEastMacAddress: DPDKEastMacAddress,
 		VMUnderTestWestMacAddress: DPDKWestMacAddress,
+		IPFamily:                  ipFamily,
 	}
 	return trex.NewConfig(cfg)
 }
@@ -0,0 +1,249 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+)
+
+// RPCPort is the port of the TRex server JSON-RPC API.
+const RPCPort = 4501
+
+const (
+	rpcCallTimeout = 30 * time.Second
+
+	// The TRex server compresses large replies, prefixing them with the magic and their uncompressed size.
+	rpcCompressedMagic      = 0xABE85CEA
+	rpcCompressedHeaderSize = 8
+)
+
+// rpcAPIVersion is the TRex stateless API version the client works with.
+var rpcAPIVersion = map[string]interface{}{"name": "STL", "major": 5, "minor": 1}
+
+// RPCClient calls the TRex server JSON-RPC API methods, over a connection to the RPC port of the traffic generator.
+// The connection is established on the first call, and again after a failed call.
+type RPCClient struct {
+	dial func() (net.Conn, error)
+
+	mutex      sync.Mutex
+	socket     *zmqReqSocket
+	apiHandler string
+	lastID     int
+}
+
+type rpcRequest struct {
+	ID      string                 `json:"id"`
+	JSONRPC string                 `json:"jsonrpc"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	ID     string          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code        int    `json:"code"`
+	Message     string `json:"message"`
+	SpecificErr string `json:"specific_err"`
+}
+
+func NewRPCClient(dial func() (net.Conn, error)) *RPCClient {
+	return &RPCClient{dial: dial}
+}
+
+func (c *RPCClient) GetGlobalStats() (GlobalStats, error) {
+	var stats GlobalStats
+	if err := c.call("get_global_stats", nil, &stats); err != nil {
+		return GlobalStats{}, fmt.Errorf("failed to get global stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (c *RPCClient) GetPortStats(port trafficgen.PortIdx) (PortStats, error) {
+	var stats PortStats
+	if err := c.call("get_port_stats", map[string]interface{}{"port_id": port}, &stats); err != nil {
+		return PortStats{}, fmt.Errorf("failed to get port %d stats: %w", port, err)
+	}
+	return stats, nil
+}
+
+// Close closes the connection to the TRex server, when established.
+// GetStreamStats returns the flow stats of the active packet group IDs.
+func (c *RPCClient) GetStreamStats() (PgIDStats, error) {
+	var activePgIDs ActivePgIDs
+	if err := c.call("get_active_pgids", map[string]interface{}{}, &activePgIDs); err != nil {
+		return PgIDStats{}, fmt.Errorf("failed to get the active packet group IDs: %w", err)
+	}
+
+	var stats PgIDStats
+	params := map[string]interface{}{"pgids": activePgIDs.Result.IDs.FlowStats}
+	if err := c.call("get_pgid_stats", params, &stats); err != nil {
+		return PgIDStats{}, fmt.Errorf("failed to get stream stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (c *RPCClient) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.disconnect()
+}
+
+// call calls the method, unmarshalling the whole response into the given response value.
+func (c *RPCClient) call(method string, params map[string]interface{}, response interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.socket == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	withAPIHandler := map[string]interface{}{"api_h": c.apiHandler}
+	for key, value := range params {
+		withAPIHandler[key] = value
+	}
+
+	rawResponse, err := c.request(method, withAPIHandler)
+	if err != nil {
+		// The REQ socket cannot recover from a failed exchange, so it is connected again on the next call.
+		_ = c.disconnect()
+		return err
+	}
+
+	return json.Unmarshal(rawResponse, response)
+}
+
+func (c *RPCClient) connect() error {
+	conn, err := c.dial()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the TRex server: %w", err)
+	}
+
+	if err = conn.SetDeadline(time.Now().Add(rpcCallTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	socket, err := newZMQReqSocket(conn)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to the TRex server: %w", err)
+	}
+	c.socket = socket
+
+	rawResponse, err := c.request("api_sync_v2", rpcAPIVersion)
+	if err != nil {
+		_ = c.disconnect()
+		return fmt.Errorf("failed to sync with the TRex server API: %w", err)
+	}
+
+	var response struct {
+		Result struct {
+			APIHandler string `json:"api_h"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rawResponse, &response); err != nil {
+		_ = c.disconnect()
+		return fmt.Errorf("failed to sync with the TRex server API: %w", err)
+	}
+	c.apiHandler = response.Result.APIHandler
+
+	return nil
+}
+
+func (c *RPCClient) disconnect() error {
+	if c.socket == nil {
+		return nil
+	}
+
+	err := c.socket.Close()
+	c.socket = nil
+	return err
+}
+
+// request sends the JSON-RPC request and returns the raw response, once checked for an error.
+func (c *RPCClient) request(method string, params map[string]interface{}) ([]byte, error) {
+	c.lastID++
+	request, err := json.Marshal(rpcRequest{
+		ID:      strconv.Itoa(c.lastID),
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.socket.conn.SetDeadline(time.Now().Add(rpcCallTimeout)); err != nil {
+		return nil, err
+	}
+	reply, err := c.socket.Request(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", method, err)
+	}
+
+	if reply, err = decompressReply(reply); err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", method, err)
+	}
+
+	var response rpcResponse
+	if err := json.Unmarshal(reply, &response); err != nil {
+		return nil, fmt.Errorf("%s call failed: invalid response: %w", method, err)
+	}
+	if response.ID != strconv.Itoa(c.lastID) {
+		return nil, fmt.Errorf("%s call failed: response ID %q does not match the request ID %d", method, response.ID, c.lastID)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("%s call failed: %s (code %d) %s",
+			method, response.Error.Message, response.Error.Code, response.Error.SpecificErr)
+	}
+
+	return reply, nil
+}
+
+func decompressReply(reply []byte) ([]byte, error) {
+	if len(reply) < rpcCompressedHeaderSize || binary.BigEndian.Uint32(reply) != rpcCompressedMagic {
+		return reply, nil
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(reply[rpcCompressedHeaderSize:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the response: %w", err)
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,228 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+)
+
+const rpcTestAPIHandler = "test-api-handler"
+
+func TestRPCClientGetPortStats(t *testing.T) {
+	server := newRPCServerStub(t)
+	server.results["get_port_stats"] = map[string]interface{}{"opackets": 1000, "ipackets": 990}
+
+	stats, err := trex.NewRPCClient(server.dial).GetPortStats(portIdx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), stats.Result.Opackets)
+	assert.Equal(t, int64(990), stats.Result.Ipackets)
+	assert.Equal(t, []string{"api_sync_v2", "get_port_stats"}, server.methods)
+	assert.Equal(t, rpcTestAPIHandler, server.lastParams["api_h"])
+	assert.Equal(t, float64(portIdx), server.lastParams["port_id"])
+}
+
+func TestRPCClientGetStreamStats(t *testing.T) {
+	server := newRPCServerStub(t)
+	server.results["get_active_pgids"] = map[string]interface{}{"ids": map[string]interface{}{"flow_stats": []int{0, 100}}}
+	server.results["get_pgid_stats"] = map[string]interface{}{"flow_stats": map[string]interface{}{
+		"0":   map[string]interface{}{"tx_pkts": map[string]int64{"0": 1000, "total": 1000}, "rx_pkts": map[string]int64{"1": 998}},
+		"100": map[string]interface{}{"tx_pkts": map[string]int64{"1": 500}, "rx_pkts": map[string]int64{"0": 500}},
+	}}
+
+	stats, err := trex.NewRPCClient(server.dial).GetStreamStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"0": 1000, "total": 1000}, stats.Result.FlowStats["0"].TxPkts)
+	assert.Equal(t, map[string]int64{"0": 500}, stats.Result.FlowStats["100"].RxPkts)
+	assert.Equal(t, []string{"api_sync_v2", "get_active_pgids", "get_pgid_stats"}, server.methods)
+	assert.Equal(t, []interface{}{float64(0), float64(100)}, server.lastParams["pgids"])
+}
+
+func TestRPCClientShouldDecompressCompressedReplies(t *testing.T) {
+	server := newRPCServerStub(t)
+	server.compressReplies = true
+	server.results["get_global_stats"] = map[string]interface{}{"m_tx_pps": 7988831.0}
+
+	stats, err := trex.NewRPCClient(server.dial).GetGlobalStats()
+	assert.NoError(t, err)
+	assert.Equal(t, 7988831.0, stats.Result.MTxPps)
+}
+
+func TestRPCClientShouldFailOnErrorResponse(t *testing.T) {
+	server := newRPCServerStub(t)
+
+	_, err := trex.NewRPCClient(server.dial).GetGlobalStats()
+	assert.ErrorContains(t, err, "get_global_stats call failed: Method not found")
+}
+
+func TestRPCClientShouldReconnectAfterFailedCall(t *testing.T) {
+	server := newRPCServerStub(t)
+	server.results["get_port_stats"] = map[string]interface{}{"opackets": 1000}
+	client := trex.NewRPCClient(server.dial)
+
+	server.closeAfterRequest = true
+	_, err := client.GetPortStats(portIdx)
+	assert.Error(t, err)
+
+	server.closeAfterRequest = false
+	_, err = client.GetPortStats(portIdx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, server.connections)
+}
+
+// rpcServerStub serves the TRex JSON-RPC API over a ZMQ REP socket, speaking ZMTP 3.0 over an in-memory connection.
+type rpcServerStub struct {
+	t                 *testing.T
+	results           map[string]interface{}
+	compressReplies   bool
+	closeAfterRequest bool
+	connections       int
+	methods           []string
+	lastParams        map[string]interface{}
+}
+
+func newRPCServerStub(t *testing.T) *rpcServerStub {
+	return &rpcServerStub{
+		t:       t,
+		results: map[string]interface{}{"api_sync_v2": map[string]interface{}{"api_h": rpcTestAPIHandler}},
+	}
+}
+
+func (s *rpcServerStub) dial() (net.Conn, error) {
+	s.connections++
+	clientConn, serverConn := net.Pipe()
+	s.t.Cleanup(func() { clientConn.Close() })
+
+	closeAfterRequest := s.closeAfterRequest
+	go s.serve(serverConn, closeAfterRequest)
+
+	return clientConn, nil
+}
+
+// serve reads the peer messages before writing, as the pipe writes block until they are read.
+func (s *rpcServerStub) serve(conn net.Conn, closeAfterRequest bool) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	greeting := make([]byte, 64)
+	if _, err := io.ReadFull(reader, greeting); err != nil {
+		return
+	}
+	greeting[32] = 1 // as-server
+	if _, err := conn.Write(greeting); err != nil {
+		return
+	}
+
+	if _, err := readFrame(reader); err != nil {
+		return
+	}
+	ready := []byte("\x05READY\x0bSocket-Type\x00\x00\x00\x03REP")
+	if _, err := conn.Write(append([]byte{0x04, byte(len(ready))}, ready...)); err != nil {
+		return
+	}
+
+	for {
+		if _, err := readFrame(reader); err != nil {
+			return
+		}
+		request, err := readFrame(reader)
+		if err != nil {
+			return
+		}
+
+		response := s.respond(request)
+		if closeAfterRequest && s.methods[len(s.methods)-1] != "api_sync_v2" {
+			return
+		}
+
+		frame := binary.BigEndian.AppendUint64([]byte{0x01, 0x00, 0x02}, uint64(len(response)))
+		if _, err := conn.Write(append(frame, response...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *rpcServerStub) respond(rawRequest []byte) []byte {
+	var request struct {
+		ID     string                 `json:"id"`
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	assert.NoError(s.t, json.Unmarshal(rawRequest, &request))
+	s.methods = append(s.methods, request.Method)
+	s.lastParams = request.Params
+
+	response := map[string]interface{}{"id": request.ID, "jsonrpc": "2.0"}
+	if result, exists := s.results[request.Method]; exists {
+		response["result"] = result
+	} else {
+		response["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+	}
+	rawResponse, err := json.Marshal(response)
+	assert.NoError(s.t, err)
+
+	if !s.compressReplies || request.Method == "api_sync_v2" {
+		return rawResponse
+	}
+
+	var compressed bytes.Buffer
+	compressed.Write(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, 0xABE85CEA), uint32(len(rawResponse))))
+	writer := zlib.NewWriter(&compressed)
+	_, err = writer.Write(rawResponse)
+	assert.NoError(s.t, err)
+	assert.NoError(s.t, writer.Close())
+	return compressed.Bytes()
+}
+
+func readFrame(reader *bufio.Reader) ([]byte, error) {
+	flags, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var size uint64
+	if flags&0x02 != 0 {
+		sizeBytes := make([]byte, 8)
+		if _, err := io.ReadFull(reader, sizeBytes); err != nil {
+			return nil, err
+		}
+		size = binary.BigEndian.Uint64(sizeBytes)
+	} else {
+		sizeByte, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size = uint64(sizeByte)
+	}
+
+	body := make([]byte, size)
+	_, err = io.ReadFull(reader, body)
+	return body, err
+}
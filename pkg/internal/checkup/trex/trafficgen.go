@@ -0,0 +1,241 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+)
+
+const (
+	TrafficGeneratorName    = "trex"
+	RPCTrafficGeneratorName = "trex-rpc"
+)
+
+// TrafficGenerator is the TRex traffic generator, driven using the TRex console.
+type TrafficGenerator struct {
+	client Client
+}
+
+func NewTrafficGenerator(params trafficgen.Params) trafficgen.TrafficGenerator {
+	return TrafficGenerator{
+		client: NewClient(params.ConsoleExpecter, params.BinDirectory, params.PacketsPerSecond, params.VerbosePrintsEnabled),
+	}
+}
+
+func (t TrafficGenerator) Name() string {
+	return TrafficGeneratorName
+}
+
+// Setup starts the TRex server service, and waits for it to be ready.
+func (t TrafficGenerator) Setup(ctx context.Context) error {
+	if err := t.client.StartServer(); err != nil {
+		return err
+	}
+
+	return t.client.WaitForServerToBeReady(ctx)
+}
+
+func (t TrafficGenerator) Start(duration time.Duration, ports ...trafficgen.PortIdx) error {
+	_, err := t.client.StartTraffic(duration, ports...)
+	return err
+}
+
+// StartMulticast starts sending multicast and broadcast frames rather than the unicast streams.
+func (t TrafficGenerator) StartMulticast(duration time.Duration, ports ...trafficgen.PortIdx) error {
+	_, err := t.client.StartMulticastTraffic(duration, ports...)
+	return err
+}
+
+// StartAtLineRatePercent starts sending the unicast streams at the given percentage of the ports line rate.
+func (t TrafficGenerator) StartAtLineRatePercent(percent int, duration time.Duration, ports ...trafficgen.PortIdx) error {
+	_, err := t.client.StartTrafficAtLineRatePercent(percent, duration, ports...)
+	return err
+}
+
+// StartBursts starts sending line-rate bursts of the given size, separated by idle gaps.
+func (t TrafficGenerator) StartBursts(burstSize int, ports ...trafficgen.PortIdx) error {
+	_, err := t.client.StartBurstTraffic(burstSize, ports...)
+	return err
+}
+
+func (t TrafficGenerator) Stop(ports ...trafficgen.PortIdx) error {
+	_, err := t.client.StopTraffic(ports...)
+	return err
+}
+
+func (t TrafficGenerator) WaitForCompletion(ctx context.Context, ports ...trafficgen.PortIdx) error {
+	return t.client.WaitForTrafficCompletion(ctx, ports...)
+}
+
+func (t TrafficGenerator) ClearStats() error {
+	_, err := t.client.ClearStats()
+	return err
+}
+
+func (t TrafficGenerator) PortStats(port trafficgen.PortIdx) (trafficgen.PortStats, error) {
+	stats, err := t.client.GetPortStats(port)
+	if err != nil {
+		return trafficgen.PortStats{}, err
+	}
+
+	return toTrafficGenPortStats(stats), nil
+}
+
+func (t TrafficGenerator) GlobalStats() (trafficgen.GlobalStats, error) {
+	stats, err := t.client.GetGlobalStats()
+	if err != nil {
+		return trafficgen.GlobalStats{}, err
+	}
+
+	return toTrafficGenGlobalStats(stats), nil
+}
+
+// StreamStats returns the counters of each stream, by its packet group ID.
+func (t TrafficGenerator) StreamStats() ([]trafficgen.StreamStats, error) {
+	stats, err := t.client.GetStreamStats()
+	if err != nil {
+		return nil, err
+	}
+
+	return toTrafficGenStreamStats(stats)
+}
+
+// ResolveGateways resolves the ports' default gateways in service mode.
+func (t TrafficGenerator) ResolveGateways() ([]trafficgen.GatewayResolution, error) {
+	return t.client.ResolveGateways()
+}
+
+// RPCTrafficGenerator is the TRex traffic generator, driven using the TRex console, whose stats are read from the TRex
+// server JSON-RPC API rather than from the TRex console output.
+type RPCTrafficGenerator struct {
+	TrafficGenerator
+	rpcClient *RPCClient
+}
+
+func NewRPCTrafficGenerator(params trafficgen.Params) trafficgen.TrafficGenerator {
+	return RPCTrafficGenerator{
+		TrafficGenerator: TrafficGenerator{
+			client: NewClient(params.ConsoleExpecter, params.BinDirectory, params.PacketsPerSecond, params.VerbosePrintsEnabled),
+		},
+		rpcClient: NewRPCClient(func() (net.Conn, error) {
+			return params.DialServer(RPCPort)
+		}),
+	}
+}
+
+func (t RPCTrafficGenerator) Name() string {
+	return RPCTrafficGeneratorName
+}
+
+func (t RPCTrafficGenerator) WaitForCompletion(ctx context.Context, ports ...trafficgen.PortIdx) error {
+	return waitForTrafficCompletion(ctx, t.rpcClient.GetPortStats, ports...)
+}
+
+func (t RPCTrafficGenerator) PortStats(port trafficgen.PortIdx) (trafficgen.PortStats, error) {
+	stats, err := t.rpcClient.GetPortStats(port)
+	if err != nil {
+		return trafficgen.PortStats{}, err
+	}
+
+	return toTrafficGenPortStats(stats), nil
+}
+
+func (t RPCTrafficGenerator) GlobalStats() (trafficgen.GlobalStats, error) {
+	stats, err := t.rpcClient.GetGlobalStats()
+	if err != nil {
+		return trafficgen.GlobalStats{}, err
+	}
+
+	return toTrafficGenGlobalStats(stats), nil
+}
+
+func (t RPCTrafficGenerator) StreamStats() ([]trafficgen.StreamStats, error) {
+	stats, err := t.rpcClient.GetStreamStats()
+	if err != nil {
+		return nil, err
+	}
+
+	return toTrafficGenStreamStats(stats)
+}
+
+// Close closes the connection to the TRex server API.
+func (t RPCTrafficGenerator) Close() error {
+	return t.rpcClient.Close()
+}
+
+func toTrafficGenPortStats(stats PortStats) trafficgen.PortStats {
+	return trafficgen.PortStats{
+		OutputPackets: stats.Result.Opackets,
+		OutputErrors:  stats.Result.Oerrors,
+		InputPackets:  stats.Result.Ipackets,
+		InputErrors:   stats.Result.Ierrors,
+	}
+}
+
+func toTrafficGenGlobalStats(stats GlobalStats) trafficgen.GlobalStats {
+	return trafficgen.GlobalStats{
+		TxPps:     stats.Result.MTxPps,
+		RxPps:     stats.Result.MRxPps,
+		TxBps:     stats.Result.MTxBps,
+		RxBps:     stats.Result.MRxBps,
+		RxDropBps: stats.Result.MRxDropBps,
+		CPUUtil:   stats.Result.MCPUUtil,
+	}
+}
+
+// toTrafficGenStreamStats sums the counters of each packet group ID over all the ports, ordered by the packet group ID.
+// The port each stream is sent from is derived from its packet group ID.
+func toTrafficGenStreamStats(stats PgIDStats) ([]trafficgen.StreamStats, error) {
+	var streams []trafficgen.StreamStats
+	for rawPgID, flowStats := range stats.Result.FlowStats {
+		pgID, err := strconv.Atoi(rawPgID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid packet group ID %q: %w", rawPgID, err)
+		}
+
+		streams = append(streams, trafficgen.StreamStats{
+			ID:        pgID,
+			TXPort:    trafficgen.PortIdx(pgID / PGIDPortStride),
+			TXPackets: sumPortCounters(flowStats.TxPkts),
+			RXPackets: sumPortCounters(flowStats.RxPkts),
+		})
+	}
+
+	sort.Slice(streams, func(i, j int) bool { return streams[i].ID < streams[j].ID })
+	return streams, nil
+}
+
+// sumPortCounters sums the counters of all the ports, skipping their total when it is reported as well.
+func sumPortCounters(counters map[string]int64) int64 {
+	var sum int64
+	for port, counter := range counters {
+		if port != "total" {
+			sum += counter
+		}
+	}
+	return sum
+}
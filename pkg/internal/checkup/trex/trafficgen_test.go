@@ -0,0 +1,101 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex_test
+
+import (
+	"context"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trafficgen"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+)
+
+func TestTrafficGeneratorPortStats(t *testing.T) {
+	trafficGenerator := newTestTrafficGenerator()
+
+	stats, err := trafficGenerator.PortStats(portIdx)
+	assert.NoError(t, err)
+	expected := trafficgen.PortStats{
+		OutputPackets: 480000000,
+		OutputErrors:  15,
+		InputPackets:  893,
+		InputErrors:   10,
+	}
+	assert.Equal(t, expected, stats)
+}
+
+func TestTrafficGeneratorGlobalStats(t *testing.T) {
+	trafficGenerator := newTestTrafficGenerator()
+
+	stats, err := trafficGenerator.GlobalStats()
+	assert.NoError(t, err)
+	expected := trafficgen.GlobalStats{
+		TxPps:     7988831.0,
+		RxPps:     7988813.0,
+		TxBps:     4345917952.0,
+		RxBps:     4090272768.0,
+		RxDropBps: 6.0,
+		CPUUtil:   21.275409698486328,
+	}
+	assert.Equal(t, expected, stats)
+}
+
+func TestTrafficGeneratorStreamStats(t *testing.T) {
+	reader, ok := newTestTrafficGenerator().(trafficgen.StreamStatsReader)
+	assert.True(t, ok)
+
+	stats, err := reader.StreamStats()
+	assert.NoError(t, err)
+	expected := []trafficgen.StreamStats{
+		{ID: 0, TXPort: 0, TXPackets: 1000, RXPackets: 998},
+		{ID: 100, TXPort: 1, TXPackets: 500, RXPackets: 500},
+	}
+	assert.Equal(t, expected, stats)
+}
+
+func TestTrafficGeneratorStartAndStop(t *testing.T) {
+	trafficGenerator := newTestTrafficGenerator()
+
+	assert.NoError(t, trafficGenerator.Start(testDuration, trafficgen.SourcePort))
+	assert.NoError(t, trafficGenerator.Stop(trafficgen.SourcePort))
+	assert.NoError(t, trafficGenerator.WaitForCompletion(context.Background(), trafficgen.SourcePort))
+}
+
+func TestTrafficGeneratorShouldResolveGateways(t *testing.T) {
+	trafficGenerator := newTestTrafficGenerator()
+
+	resolver, ok := trafficGenerator.(trafficgen.GatewayResolver)
+	assert.True(t, ok)
+
+	resolutions, err := resolver.ResolveGateways()
+	assert.NoError(t, err)
+	assert.Len(t, resolutions, 2)
+}
+
+func newTestTrafficGenerator() trafficgen.TrafficGenerator {
+	return trex.NewTrafficGenerator(trafficgen.Params{
+		ConsoleExpecter:      expecterStub{},
+		BinDirectory:         binDirectory,
+		PacketsPerSecond:     trafficGeneratorPacketsPerSecond,
+		VerbosePrintsEnabled: verbosePrintsEnabled,
+	})
+}
@@ -82,3 +82,31 @@ type PortStatsResult struct {
 	Oerrors     int64   `json:"oerrors"`
 	Opackets    int64   `json:"opackets"`
 }
+
+type PgIDStats struct {
+	ID      string          `json:"id"`
+	Jsonrpc string          `json:"jsonrpc"`
+	Result  PgIDStatsResult `json:"result"`
+}
+
+type PgIDStatsResult struct {
+	FlowStats map[string]FlowStats `json:"flow_stats"`
+}
+
+// FlowStats holds the counters of a single packet group ID, by port index.
+type FlowStats struct {
+	RxPkts map[string]int64 `json:"rx_pkts"`
+	TxPkts map[string]int64 `json:"tx_pkts"`
+}
+
+type ActivePgIDs struct {
+	ID      string            `json:"id"`
+	Jsonrpc string            `json:"jsonrpc"`
+	Result  ActivePgIDsResult `json:"result"`
+}
+
+type ActivePgIDsResult struct {
+	IDs struct {
+		FlowStats []int `json:"flow_stats"`
+	} `json:"ids"`
+}
@@ -0,0 +1,261 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package trex
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// zmqReqSocket is a ZMQ REQ socket over a single connection, speaking ZMTP 3.0 with the NULL security mechanism,
+// as the TRex server RPC REP socket expects.
+type zmqReqSocket struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+const (
+	zmqGreetingSize      = 64
+	zmqSignatureStart    = 0xff
+	zmqSignatureEnd      = 0x7f
+	zmqSignatureEndIdx   = 9
+	zmqVersionMajorIdx   = 10
+	zmqMechanismIdx      = 12
+	zmqMechanismSize     = 20
+	zmqVersionMajor      = 3
+	zmqNullMechanism     = "NULL"
+	zmqReadyCommand      = "READY"
+	zmqSocketTypeKey     = "Socket-Type"
+	zmqReqSocketType     = "REQ"
+	zmqRepSocketType     = "REP"
+	zmqFlagMore          = 0x01
+	zmqFlagLong          = 0x02
+	zmqFlagCommand       = 0x04
+	zmqMaxShortFrameSize = 255
+	zmqMaxFrameSize      = 64 << 20
+)
+
+// newZMQReqSocket performs the ZMTP handshake over the connection, with the REP socket on its other end.
+func newZMQReqSocket(conn net.Conn) (*zmqReqSocket, error) {
+	s := &zmqReqSocket{conn: conn, reader: bufio.NewReader(conn)}
+
+	if err := s.exchangeGreetings(); err != nil {
+		return nil, err
+	}
+
+	if err := s.exchangeReadyCommands(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Request sends the request message and returns the reply message.
+func (s *zmqReqSocket) Request(request []byte) ([]byte, error) {
+	// A REQ socket prefixes its messages with an empty delimiter frame, which the REP socket echoes in its reply.
+	if err := s.writeFrame(zmqFlagMore, nil); err != nil {
+		return nil, err
+	}
+	if err := s.writeFrame(0, request); err != nil {
+		return nil, err
+	}
+
+	return s.readReply()
+}
+
+func (s *zmqReqSocket) Close() error {
+	return s.conn.Close()
+}
+
+func (s *zmqReqSocket) exchangeGreetings() error {
+	greeting := make([]byte, zmqGreetingSize)
+	greeting[0] = zmqSignatureStart
+	greeting[zmqSignatureEndIdx] = zmqSignatureEnd
+	greeting[zmqVersionMajorIdx] = zmqVersionMajor
+	copy(greeting[zmqMechanismIdx:], zmqNullMechanism)
+	if _, err := s.conn.Write(greeting); err != nil {
+		return err
+	}
+
+	peerGreeting := make([]byte, zmqGreetingSize)
+	if _, err := io.ReadFull(s.reader, peerGreeting); err != nil {
+		return fmt.Errorf("failed to read the ZMTP greeting: %w", err)
+	}
+
+	if peerGreeting[0] != zmqSignatureStart || peerGreeting[zmqSignatureEndIdx]&1 != 1 {
+		return errors.New("invalid ZMTP greeting signature")
+	}
+	if peerGreeting[zmqVersionMajorIdx] < zmqVersionMajor {
+		return fmt.Errorf("unsupported ZMTP version %d", peerGreeting[zmqVersionMajorIdx])
+	}
+	mechanism := bytes.TrimRight(peerGreeting[zmqMechanismIdx:zmqMechanismIdx+zmqMechanismSize], "\x00")
+	if string(mechanism) != zmqNullMechanism {
+		return fmt.Errorf("unsupported ZMTP security mechanism %q", mechanism)
+	}
+
+	return nil
+}
+
+func (s *zmqReqSocket) exchangeReadyCommands() error {
+	if err := s.writeFrame(zmqFlagCommand, readyCommand(zmqReqSocketType)); err != nil {
+		return err
+	}
+
+	flags, body, err := s.readFrame()
+	if err != nil {
+		return fmt.Errorf("failed to read the ZMTP READY command: %w", err)
+	}
+	if flags&zmqFlagCommand == 0 {
+		return errors.New("unexpected ZMTP handshake message, a READY command is expected")
+	}
+	properties, err := parseReadyCommand(body)
+	if err != nil {
+		return err
+	}
+	if socketType := properties[zmqSocketTypeKey]; socketType != zmqRepSocketType {
+		return fmt.Errorf("unexpected ZMTP peer socket type %q", socketType)
+	}
+
+	return nil
+}
+
+func (s *zmqReqSocket) readReply() ([]byte, error) {
+	var frames [][]byte
+	for {
+		flags, body, err := s.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		// Commands, e.g. heartbeats, may arrive in between the messages.
+		if flags&zmqFlagCommand != 0 {
+			continue
+		}
+
+		frames = append(frames, body)
+		if flags&zmqFlagMore == 0 {
+			break
+		}
+	}
+
+	if len(frames[0]) != 0 {
+		return nil, errors.New("the reply is missing its empty delimiter frame")
+	}
+
+	return bytes.Join(frames[1:], nil), nil
+}
+
+func (s *zmqReqSocket) writeFrame(flags byte, body []byte) error {
+	var header []byte
+	if len(body) > zmqMaxShortFrameSize {
+		header = binary.BigEndian.AppendUint64([]byte{flags | zmqFlagLong}, uint64(len(body)))
+	} else {
+		header = []byte{flags, byte(len(body))}
+	}
+
+	_, err := s.conn.Write(append(header, body...))
+	return err
+}
+
+func (s *zmqReqSocket) readFrame() (flags byte, body []byte, err error) {
+	if flags, err = s.reader.ReadByte(); err != nil {
+		return 0, nil, err
+	}
+
+	var size uint64
+	if flags&zmqFlagLong != 0 {
+		sizeBytes := make([]byte, 8)
+		if _, err = io.ReadFull(s.reader, sizeBytes); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(sizeBytes)
+	} else {
+		sizeByte, err := s.reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(sizeByte)
+	}
+	if size > zmqMaxFrameSize {
+		return 0, nil, fmt.Errorf("ZMTP frame of %d bytes exceeds the %d bytes limit", size, zmqMaxFrameSize)
+	}
+
+	body = make([]byte, size)
+	if _, err = io.ReadFull(s.reader, body); err != nil {
+		return 0, nil, err
+	}
+
+	return flags, body, nil
+}
+
+// readyCommand returns the body of the READY command of the given socket type.
+func readyCommand(socketType string) []byte {
+	command := []byte{byte(len(zmqReadyCommand))}
+	command = append(command, zmqReadyCommand...)
+	command = append(command, byte(len(zmqSocketTypeKey)))
+	command = append(command, zmqSocketTypeKey...)
+	command = binary.BigEndian.AppendUint32(command, uint32(len(socketType)))
+	return append(command, socketType...)
+}
+
+// parseReadyCommand returns the properties of the READY command body.
+func parseReadyCommand(body []byte) (map[string]string, error) {
+	errInvalid := fmt.Errorf("invalid ZMTP READY command %q", body)
+
+	name, rest, ok := cutShortString(body)
+	if !ok || name != zmqReadyCommand {
+		return nil, errInvalid
+	}
+
+	properties := map[string]string{}
+	for len(rest) > 0 {
+		var key string
+		if key, rest, ok = cutShortString(rest); !ok {
+			return nil, errInvalid
+		}
+
+		const valueSizeLength = 4
+		if len(rest) < valueSizeLength {
+			return nil, errInvalid
+		}
+		valueSize := binary.BigEndian.Uint32(rest)
+		rest = rest[valueSizeLength:]
+		if uint64(len(rest)) < uint64(valueSize) {
+			return nil, errInvalid
+		}
+		properties[key] = string(rest[:valueSize])
+		rest = rest[valueSize:]
+	}
+
+	return properties, nil
+}
+
+// cutShortString cuts the string prefixed by its one byte length from the data.
+func cutShortString(data []byte) (value string, rest []byte, ok bool) {
+	if len(data) == 0 || len(data) < 1+int(data[0]) {
+		return "", nil, false
+	}
+	return string(data[1 : 1+data[0]]), data[1+data[0]:], true
+}
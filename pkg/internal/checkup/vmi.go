@@ -21,10 +21,12 @@ package checkup
 
 import (
 	"fmt"
+	"net"
 	"path"
 	"strings"
 
 	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	kvcorev1 "kubevirt.io/api/core/v1"
 
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
@@ -47,13 +49,16 @@ const (
 	guestMemory       = "4Gi"
 	rootDiskName      = "rootdisk"
 	cloudInitDiskName = "cloudinitdisk"
-	eastNetworkName   = "nic-east"
-	westNetworkName   = "nic-west"
+	mellanoxVendorID  = "0x15b3"
+
+	// trafficGenCfgDirectory is where the traffic generator boot commands install trex_cfg.yaml.
+	trafficGenCfgDirectory = "/etc"
 
 	terminationGracePeriodSeconds = 0
 )
 
-func newVMIUnderTest(name string, checkupConfig config.Config, configMapName string) *kvcorev1.VirtualMachineInstance {
+// newVMIUnderTest returns the VM under test VMI, whose ConfigMap volume is set once its ConfigMap name is generated.
+func newVMIUnderTest(checkupConfig config.Config) *kvcorev1.VirtualMachineInstance {
 	const (
 		configDiskSerial = "DEADBEEF"
 		configVolumeName = "vmi-under-test-config"
@@ -62,45 +67,71 @@ func newVMIUnderTest(name string, checkupConfig config.Config, configMapName str
 	optionsToApply := baseOptions(checkupConfig)
 
 	optionsToApply = append(optionsToApply,
-		vmi.WithAffinity(Affinity(checkupConfig.VMUnderTestTargetNodeName, checkupConfig.PodUID)),
-		vmi.WithSRIOVInterface(eastNetworkName, checkupConfig.VMUnderTestEastMacAddress.String(), config.VMIEastNICPCIAddress),
-		vmi.WithSRIOVInterface(westNetworkName, checkupConfig.VMUnderTestWestMacAddress.String(), config.VMIWestNICPCIAddress),
+		vmi.WithAffinity(Affinity(checkupConfig.VMUnderTestTargetNodeName, checkupConfig.VMUnderTestNodeLabelSelector,
+			checkupConfig.CheckupUID, checkupConfig.SameNodePlacement, checkupConfig.RequireDifferentNodes)),
+	)
+
+	for _, iface := range checkupConfig.TestInterfaces() {
+		optionsToApply = append(optionsToApply,
+			vmi.WithSRIOVInterface(iface.Name, iface.VMUnderTestMacAddress.String(), iface.PCIAddress))
+	}
+
+	optionsToApply = append(optionsToApply,
 		vmi.WithContainerDisk(rootDiskName, checkupConfig.VMUnderTestContainerDiskImage),
-		vmi.WithCloudInitNoCloudVolume(cloudInitDiskName, CloudInit(vmiUnderTestBootCommands(configDiskSerial))),
-		vmi.WithConfigMapVolume(configVolumeName, configMapName),
+		vmi.WithCloudInitNoCloudVolume(cloudInitDiskName, CloudInit(
+			checkupConfig.VMIPassword,
+			vmiUnderTestBootCommands(configDiskSerial),
+			sshAuthorizedKeys(checkupConfig),
+		)),
+		vmi.WithConfigMapVolume(configVolumeName, ""),
 		vmi.WithConfigMapDisk(configVolumeName, configDiskSerial),
 		vmi.WithReadinessFileProbe(config.BootScriptReadinessMarkerFileFullPath),
 	)
 
-	return vmi.New(name, optionsToApply...)
+	return vmi.New(VMIUnderTestNamePrefix+"-", optionsToApply...)
 }
 
-func newTrafficGen(name string, checkupConfig config.Config, configMapName string) *kvcorev1.VirtualMachineInstance {
+// newTrafficGen returns the traffic generator VMI, whose ConfigMap volume is set once its ConfigMap name is generated.
+func newTrafficGen(checkupConfig config.Config) *kvcorev1.VirtualMachineInstance {
 	const configDiskSerial = "DEADBEEF"
 	const configVolumeName = "trex-config"
 
 	optionsToApply := baseOptions(checkupConfig)
 
 	optionsToApply = append(optionsToApply,
-		vmi.WithAffinity(Affinity(checkupConfig.TrafficGenTargetNodeName, checkupConfig.PodUID)),
-		vmi.WithSRIOVInterface(eastNetworkName, checkupConfig.TrafficGenEastMacAddress.String(), config.VMIEastNICPCIAddress),
-		vmi.WithSRIOVInterface(westNetworkName, checkupConfig.TrafficGenWestMacAddress.String(), config.VMIWestNICPCIAddress),
+		vmi.WithAffinity(Affinity(checkupConfig.TrafficGenTargetNodeName, checkupConfig.TrafficGenNodeLabelSelector,
+			checkupConfig.CheckupUID, checkupConfig.SameNodePlacement, checkupConfig.RequireDifferentNodes)),
+	)
+
+	for _, iface := range checkupConfig.TestInterfaces() {
+		optionsToApply = append(optionsToApply,
+			vmi.WithSRIOVInterface(iface.Name, iface.TrafficGenMacAddress.String(), iface.PCIAddress))
+	}
+
+	optionsToApply = append(optionsToApply,
 		vmi.WithContainerDisk(rootDiskName, checkupConfig.TrafficGenContainerDiskImage),
-		vmi.WithCloudInitNoCloudVolume(cloudInitDiskName, CloudInit(trafficGenBootCommands(configDiskSerial))),
-		vmi.WithConfigMapVolume(configVolumeName, configMapName),
+		vmi.WithCloudInitNoCloudVolume(cloudInitDiskName, CloudInit(
+			checkupConfig.VMIPassword,
+			trafficGenBootCommands(configDiskSerial, trex.NewConfig(checkupConfig).BinDirectory()),
+			sshAuthorizedKeys(checkupConfig),
+		)),
+		vmi.WithConfigMapVolume(configVolumeName, ""),
 		vmi.WithConfigMapDisk(configVolumeName, configDiskSerial),
 		vmi.WithReadinessFileProbe(config.BootScriptReadinessMarkerFileFullPath),
 	)
 
-	return vmi.New(name, optionsToApply...)
+	return vmi.New(TrafficGenNamePrefix+"-", optionsToApply...)
 }
 
 func baseOptions(checkupConfig config.Config) []vmi.Option {
 	labels := map[string]string{
-		DPDKCheckupUIDLabelKey: checkupConfig.PodUID,
+		DPDKCheckupUIDLabelKey: checkupConfig.CheckupUID,
 	}
 
-	return []vmi.Option{
+	// The extra labels and annotations are added first, so they cannot override the ones the checkup relies on.
+	options := []vmi.Option{
+		vmi.WithLabels(checkupConfig.VMIExtraLabels),
+		vmi.WithAnnotations(checkupConfig.VMIExtraAnnotations),
 		vmi.WithOwnerReference(checkupConfig.PodName, checkupConfig.PodUID),
 		vmi.WithLabels(labels),
 		vmi.WithoutCRIOCPULoadBalancing(),
@@ -111,26 +142,85 @@ func baseOptions(checkupConfig config.Config) []vmi.Option {
 		vmi.WithNetworkInterfaceMultiQueue(),
 		vmi.WithRandomNumberGenerator(),
 		vmi.WithTerminationGracePeriodSeconds(terminationGracePeriodSeconds),
-		vmi.WithMultusNetwork(eastNetworkName, checkupConfig.NetworkAttachmentDefinitionName),
-		vmi.WithMultusNetwork(westNetworkName, checkupConfig.NetworkAttachmentDefinitionName),
+	}
+
+	if checkupConfig.NUMAPassthrough {
+		options = append(options, vmi.WithNUMAGuestMappingPassthrough())
+	}
+
+	if checkupConfig.PriorityClassName != "" {
+		options = append(options, vmi.WithPriorityClassName(checkupConfig.PriorityClassName))
+	}
+
+	if len(checkupConfig.VMITolerations) > 0 {
+		options = append(options, vmi.WithTolerations(tolerations(checkupConfig.VMITolerations)))
+	}
+
+	for _, iface := range checkupConfig.TestInterfaces() {
+		options = append(options, vmi.WithMultusNetwork(iface.Name, iface.NetworkAttachmentDefinitionName))
+	}
+
+	// The guests are reached over SSH and the TRex RPC port through the pod network.
+	if checkupConfig.GuestCommandTransport == config.GuestCommandTransportSSH || checkupConfig.TrexTransport == config.TrexTransportRPC {
+		options = append(options, vmi.WithMasqueradeInterface(config.GuestPodNetworkName), vmi.WithPodNetwork(config.GuestPodNetworkName))
+	}
+
+	return append(options,
 		vmi.WithVirtIODisk(rootDiskName),
 		vmi.WithVirtIODisk(cloudInitDiskName),
+	)
+}
+
+// tolerations returns the VMIs tolerations, tolerating any value of the key when no value is set.
+func tolerations(checkupTolerations []config.Toleration) []k8scorev1.Toleration {
+	var vmiTolerations []k8scorev1.Toleration
+	for _, toleration := range checkupTolerations {
+		vmiToleration := k8scorev1.Toleration{
+			Key:      toleration.Key,
+			Operator: k8scorev1.TolerationOpEqual,
+			Value:    toleration.Value,
+			Effect:   k8scorev1.TaintEffect(toleration.Effect),
+		}
+		if toleration.Value == "" {
+			vmiToleration.Operator = k8scorev1.TolerationOpExists
+		}
+		vmiTolerations = append(vmiTolerations, vmiToleration)
 	}
+	return vmiTolerations
 }
 
-func Affinity(nodeName, ownerUID string) *k8scorev1.Affinity {
+// Affinity returns the affinity of the checkup VMIs: the target node when set, otherwise either pod affinity to place
+// both VMIs on the same node, or pod anti-affinity to place them on different nodes, among the nodes matching the node
+// label selector when set. The different nodes are only preferred, unless they are required.
+func Affinity(nodeName, nodeLabelSelector, ownerUID string, sameNode, differentNodes bool) *k8scorev1.Affinity {
 	var affinity k8scorev1.Affinity
 	if nodeName != "" {
 		affinity.NodeAffinity = vmi.NewRequiredNodeAffinity(nodeName)
-	} else {
+		return &affinity
+	}
+
+	// The selector is validated by the config
+	if selector, err := labels.Parse(nodeLabelSelector); err == nil && !selector.Empty() {
+		requirements, _ := selector.Requirements()
+		affinity.NodeAffinity = vmi.NewRequiredNodeLabelsAffinity(requirements)
+	}
+
+	switch {
+	case sameNode:
+		affinity.PodAffinity = vmi.NewRequiredPodAffinity(DPDKCheckupUIDLabelKey, ownerUID)
+	case differentNodes:
+		affinity.PodAntiAffinity = vmi.NewRequiredPodAntiAffinity(DPDKCheckupUIDLabelKey, ownerUID)
+	default:
 		affinity.PodAntiAffinity = vmi.NewPreferredPodAntiAffinity(DPDKCheckupUIDLabelKey, ownerUID)
 	}
 
 	return &affinity
 }
 
-func generateBootScript() string {
-	const isolatedCores = "2-7"
+// generateBootScript returns the guest boot script, which sets the CPU partitioning and binds the test NICs for DPDK.
+// The NICs are discovered by their given MAC addresses, and their PCI addresses are kept for the executor, and
+// substituted for their placeholders in the TRex configuration file, when a path to one is given.
+func generateBootScript(macAddresses []net.HardwareAddr, nicDriverMode, trexCfgFileFullPath string) string {
 	sb := strings.Builder{}
 
 	sb.WriteString("#!/bin/bash\n")
@@ -139,25 +229,102 @@ func generateBootScript() string {
 	sb.WriteString("checkup_tuned_adm_set_marker_full_path=" + config.BootScriptTunedAdmSetMarkerFileFullPath + "\n")
 	sb.WriteString("\n")
 	sb.WriteString("if [ ! -f \"$checkup_tuned_adm_set_marker_full_path\" ]; then\n")
-	sb.WriteString("  echo \"isolated_cores=" + isolatedCores + "\" > /etc/tuned/cpu-partitioning-variables.conf\n")
+	sb.WriteString("  echo \"isolated_cores=" + config.GuestIsolatedCPUs + "\" > /etc/tuned/cpu-partitioning-variables.conf\n")
 	sb.WriteString("  tuned-adm profile cpu-partitioning\n\n")
 	sb.WriteString("  touch $checkup_tuned_adm_set_marker_full_path\n")
 	sb.WriteString("  reboot\n")
 	sb.WriteString("  exit 0\n")
 	sb.WriteString("fi\n")
 	sb.WriteString("\n")
-	sb.WriteString("driverctl set-override " + config.VMIEastNICPCIAddress + " vfio-pci\n")
-	sb.WriteString("driverctl set-override " + config.VMIWestNICPCIAddress + " vfio-pci\n")
+	sb.WriteString(pciAddressDiscoveryFunction)
+	for _, macAddress := range macAddresses {
+		sb.WriteString("\n")
+		sb.WriteString("nic_pci_address=$(pci_address_of " + macAddress.String() + ")\n")
+		sb.WriteString("if [ -n \"$nic_pci_address\" ]; then\n")
+		switch nicDriverMode {
+		case config.NICDriverModeVFIO:
+			sb.WriteString("  driverctl set-override \"$nic_pci_address\" vfio-pci\n")
+		case config.NICDriverModeMLX5:
+			// Overriding the driver would break the bifurcated NIC, which DPDK drives alongside mlx5_core.
+		default:
+			// DPDK drives the Mellanox VFs through their bifurcated mlx5_core driver, rather than through vfio-pci.
+			sb.WriteString("  if [ \"$(cat /sys/bus/pci/devices/$nic_pci_address/vendor)\" != \"" + mellanoxVendorID + "\" ]; then\n")
+			sb.WriteString("    driverctl set-override \"$nic_pci_address\" vfio-pci\n")
+			sb.WriteString("  fi\n")
+		}
+		if trexCfgFileFullPath != "" {
+			sb.WriteString(fmt.Sprintf("  sed -i \"s/%s/$nic_pci_address/\" %s\n",
+				trex.PCIAddressPlaceholder(macAddress), trexCfgFileFullPath))
+		}
+		sb.WriteString("fi\n")
+	}
+	sb.WriteString("\n")
 	sb.WriteString("touch " + config.BootScriptReadinessMarkerFileFullPath + "\n")
 	sb.WriteString("chcon -t virt_qemu_ga_exec_t " + config.BootScriptReadinessMarkerFileFullPath + "\n")
 
 	return sb.String()
 }
 
-func CloudInit(bootCommands []string) string {
+func vmUnderTestMacAddresses(interfaces []config.Interface) []net.HardwareAddr {
+	var macAddresses []net.HardwareAddr
+	for _, iface := range interfaces {
+		macAddresses = append(macAddresses, iface.VMUnderTestMacAddress)
+	}
+	return macAddresses
+}
+
+func trafficGenMacAddresses(interfaces []config.Interface) []net.HardwareAddr {
+	var macAddresses []net.HardwareAddr
+	for _, iface := range interfaces {
+		macAddresses = append(macAddresses, iface.TrafficGenMacAddress)
+	}
+	return macAddresses
+}
+
+// pciAddressDiscoveryFunction prints the PCI address of the NIC with the given MAC address, as discovered on an earlier
+// boot, or otherwise by the MAC address of its network interface, which is gone once the NIC is bound to vfio-pci.
+const pciAddressDiscoveryFunction = `pci_addresses_file=` + config.BootScriptPCIAddressesFileFullPath + `
+touch "$pci_addresses_file"
+
+pci_address_of() {
+  local pci_address
+  pci_address=$(awk -v mac="$1" '$1 == mac {print $2}' "$pci_addresses_file")
+  if [ -z "$pci_address" ]; then
+    for netdev in /sys/class/net/*; do
+      if [ -e "$netdev/device" ] && [ "$(cat "$netdev/address")" = "$1" ]; then
+        pci_address=$(basename "$(readlink -f "$netdev/device")")
+        echo "$1 $pci_address" >> "$pci_addresses_file"
+      fi
+    done
+  fi
+  echo "$pci_address"
+}
+`
+
+// CloudInit returns the cloud-init user data running the boot commands.
+// The root password, when given, is set by the first boot command, before the boot script marks the guest as ready.
+// The SSH authorized keys, when given, are authorized for the root user, and the SSH server, disabled in the guest
+// images, is enabled.
+func CloudInit(rootPassword string, bootCommands, sshAuthorizedKeys []string) string {
+	if rootPassword != "" {
+		bootCommands = append([]string{fmt.Sprintf("echo 'root:%s' | chpasswd", rootPassword)}, bootCommands...)
+	}
+
 	sb := strings.Builder{}
 	sb.WriteString("#cloud-config\n")
 
+	if len(sshAuthorizedKeys) != 0 {
+		sb.WriteString("disable_root: false\n")
+		sb.WriteString("ssh_authorized_keys:\n")
+
+		for _, key := range sshAuthorizedKeys {
+			sb.WriteString(fmt.Sprintf("  - %q\n", key))
+		}
+
+		sb.WriteString("runcmd:\n")
+		sb.WriteString("  - \"systemctl enable --now sshd\"\n")
+	}
+
 	if len(bootCommands) != 0 {
 		sb.WriteString("bootcmd:\n")
 
@@ -169,16 +336,23 @@ func CloudInit(bootCommands []string) string {
 	return sb.String()
 }
 
-func trafficGenBootCommands(configDiskSerial string) []string {
+func sshAuthorizedKeys(checkupConfig config.Config) []string {
+	if checkupConfig.GuestSSHAuthorizedKey == "" {
+		return nil
+	}
+	return []string{checkupConfig.GuestSSHAuthorizedKey}
+}
+
+func trafficGenBootCommands(configDiskSerial, trexBinDirectory string) []string {
 	const configMountDirectory = "/mnt/app-config"
 
 	return []string{
 		fmt.Sprintf("mkdir %s", configMountDirectory),
 		fmt.Sprintf("mount /dev/$(lsblk --nodeps -no name,serial | grep %s | cut -f1 -d' ') %s", configDiskSerial, configMountDirectory),
 		fmt.Sprintf("cp %s /etc/systemd/system", path.Join(configMountDirectory, trex.SystemdUnitFileName)),
-		fmt.Sprintf("cp %s %s", path.Join(configMountDirectory, trex.ExecutionScriptName), trex.BinDirectory),
-		fmt.Sprintf("chmod 744 %s", path.Join(trex.BinDirectory, trex.ExecutionScriptName)),
-		fmt.Sprintf("cp %s /etc", path.Join(configMountDirectory, trex.CfgFileName)),
+		fmt.Sprintf("cp %s %s", path.Join(configMountDirectory, trex.ExecutionScriptName), trexBinDirectory),
+		fmt.Sprintf("chmod 744 %s", path.Join(trexBinDirectory, trex.ExecutionScriptName)),
+		fmt.Sprintf("cp %s %s", path.Join(configMountDirectory, trex.CfgFileName), trafficGenCfgDirectory),
 		fmt.Sprintf("mkdir -p %s", trex.StreamsPyPath),
 		fmt.Sprintf("cp %s/*.py %s", configMountDirectory, trex.StreamsPyPath),
 		fmt.Sprintf("cp %s %s", path.Join(configMountDirectory, config.BootScriptName), config.BootScriptBinDirectory),
@@ -22,6 +22,8 @@ package vmi
 import (
 	k8scorev1 "k8s.io/api/core/v1"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 // NewRequiredNodeAffinity returns new node affinity with node selector of the given node name.
@@ -44,6 +46,49 @@ func NewRequiredNodeAffinity(nodeName string) *k8scorev1.NodeAffinity {
 	}
 }
 
+// NewRequiredNodeLabelsAffinity returns new node affinity with node selector of the given label requirements.
+// Adding it to a VMI will make sure it will schedule on a node whose labels match all the requirements.
+func NewRequiredNodeLabelsAffinity(requirements labels.Requirements) *k8scorev1.NodeAffinity {
+	var reqs []k8scorev1.NodeSelectorRequirement
+	for _, requirement := range requirements {
+		req := k8scorev1.NodeSelectorRequirement{
+			Key:      requirement.Key(),
+			Operator: nodeSelectorOperator(requirement.Operator()),
+		}
+		if requirement.Values().Len() > 0 {
+			req.Values = requirement.Values().List()
+		}
+		reqs = append(reqs, req)
+	}
+	term := []k8scorev1.NodeSelectorTerm{
+		{
+			MatchExpressions: reqs,
+		},
+	}
+	return &k8scorev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &k8scorev1.NodeSelector{
+			NodeSelectorTerms: term,
+		},
+	}
+}
+
+func nodeSelectorOperator(operator selection.Operator) k8scorev1.NodeSelectorOperator {
+	switch operator {
+	case selection.NotIn, selection.NotEquals:
+		return k8scorev1.NodeSelectorOpNotIn
+	case selection.Exists:
+		return k8scorev1.NodeSelectorOpExists
+	case selection.DoesNotExist:
+		return k8scorev1.NodeSelectorOpDoesNotExist
+	case selection.GreaterThan:
+		return k8scorev1.NodeSelectorOpGt
+	case selection.LessThan:
+		return k8scorev1.NodeSelectorOpLt
+	default:
+		return k8scorev1.NodeSelectorOpIn
+	}
+}
+
 // NewPreferredPodAntiAffinity returns new pod anti-affinity with label selector of the given label key and value.
 // Adding it to a VMI will make sure it won't schedule on the same node as other VMIs with the given label.
 func NewPreferredPodAntiAffinity(labelKey, labelVal string) *k8scorev1.PodAntiAffinity {
@@ -67,3 +112,64 @@ func NewPreferredPodAntiAffinity(labelKey, labelVal string) *k8scorev1.PodAntiAf
 		PreferredDuringSchedulingIgnoredDuringExecution: []k8scorev1.WeightedPodAffinityTerm{weightedTerm},
 	}
 }
+
+// NewRequiredPodAntiAffinity returns new pod anti-affinity with label selector of the given label key and value.
+// Adding it to a VMI will make sure it will not schedule on the same node as other VMIs with the given label.
+func NewRequiredPodAntiAffinity(labelKey, labelVal string) *k8scorev1.PodAntiAffinity {
+	req := k8smetav1.LabelSelectorRequirement{
+		Operator: k8smetav1.LabelSelectorOpIn,
+		Key:      labelKey,
+		Values:   []string{labelVal},
+	}
+	labelSelector := &k8smetav1.LabelSelector{
+		MatchExpressions: []k8smetav1.LabelSelectorRequirement{req},
+	}
+	term := k8scorev1.PodAffinityTerm{
+		TopologyKey:   k8scorev1.LabelHostname,
+		LabelSelector: labelSelector,
+	}
+	return &k8scorev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{term},
+	}
+}
+
+// NewRequiredPodAffinity returns new pod affinity with label selector of the given label key and value.
+// Adding it to a VMI will make sure it will schedule on the same node as other VMIs with the given label.
+// When no such VMI is scheduled yet, a VMI that has the given label is scheduled on any node.
+func NewRequiredPodAffinity(labelKey, labelVal string) *k8scorev1.PodAffinity {
+	req := k8smetav1.LabelSelectorRequirement{
+		Operator: k8smetav1.LabelSelectorOpIn,
+		Key:      labelKey,
+		Values:   []string{labelVal},
+	}
+	labelSelector := &k8smetav1.LabelSelector{
+		MatchExpressions: []k8smetav1.LabelSelectorRequirement{req},
+	}
+	term := k8scorev1.PodAffinityTerm{
+		TopologyKey:   k8scorev1.LabelHostname,
+		LabelSelector: labelSelector,
+	}
+	return &k8scorev1.PodAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{term},
+	}
+}
+
+// NewRequiredNodeAntiAffinity returns new node affinity with node selector excluding the given node names.
+// Adding it to a VMI will make sure it won't schedule on any of the given node names.
+func NewRequiredNodeAntiAffinity(nodeNames []string) *k8scorev1.NodeAffinity {
+	req := k8scorev1.NodeSelectorRequirement{
+		Key:      k8scorev1.LabelHostname,
+		Operator: k8scorev1.NodeSelectorOpNotIn,
+		Values:   nodeNames,
+	}
+	term := []k8scorev1.NodeSelectorTerm{
+		{
+			MatchExpressions: []k8scorev1.NodeSelectorRequirement{req},
+		},
+	}
+	return &k8scorev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &k8scorev1.NodeSelector{
+			NodeSelectorTerms: term,
+		},
+	}
+}
@@ -45,14 +45,15 @@ const Disable = "disable"
 
 type Option func(vmi *kvcorev1.VirtualMachineInstance)
 
-func New(name string, options ...Option) *kvcorev1.VirtualMachineInstance {
+// New returns a VMI whose name is generated by the API server on its creation, from the given prefix.
+func New(generateName string, options ...Option) *kvcorev1.VirtualMachineInstance {
 	newVMI := &kvcorev1.VirtualMachineInstance{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       kvcorev1.VirtualMachineInstanceGroupVersionKind.Kind,
 			APIVersion: kvcorev1.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			GenerateName: generateName,
 		},
 	}
 
@@ -63,14 +64,18 @@ func New(name string, options ...Option) *kvcorev1.VirtualMachineInstance {
 	return newVMI
 }
 
+// WithOwnerReference sets the given Pod as the owner of the VMI, which is garbage collected along with it.
+// The Pod deletion is blocked until the VMI is deleted, so the VMI resources, e.g. its VFs and hugepages, are released
+// before a new checkup Pod may be created for the same Job.
 func WithOwnerReference(ownerName, ownerUID string) Option {
 	return func(vmi *kvcorev1.VirtualMachineInstance) {
 		if ownerUID != "" && ownerName != "" {
 			vmi.ObjectMeta.OwnerReferences = append(vmi.ObjectMeta.OwnerReferences, metav1.OwnerReference{
-				APIVersion: "v1",
-				Kind:       "Pod",
-				Name:       ownerName,
-				UID:        types.UID(ownerUID),
+				APIVersion:         "v1",
+				Kind:               "Pod",
+				Name:               ownerName,
+				UID:                types.UID(ownerUID),
+				BlockOwnerDeletion: Pointer(true),
 			})
 		}
 	}
@@ -118,6 +123,17 @@ func WithDedicatedCPU(socketsCount, coresCount, threadsCount uint32) Option {
 	}
 }
 
+// WithNUMAGuestMappingPassthrough models the guest NUMA topology after the host NUMA nodes of its dedicated CPUs.
+// It requires the dedicated CPUs and hugepages to be set, and the KubeVirt NUMA feature gate to be enabled.
+func WithNUMAGuestMappingPassthrough() Option {
+	return func(vmi *kvcorev1.VirtualMachineInstance) {
+		if vmi.Spec.Domain.CPU == nil {
+			vmi.Spec.Domain.CPU = &kvcorev1.CPU{}
+		}
+		vmi.Spec.Domain.CPU.NUMA = &kvcorev1.NUMA{GuestMappingPassthrough: &kvcorev1.NUMAGuestMappingPassthrough{}}
+	}
+}
+
 func WithVirtIODisk(name string) Option {
 	return func(vmi *kvcorev1.VirtualMachineInstance) {
 		vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, kvcorev1.Disk{
@@ -154,6 +170,17 @@ func WithSRIOVInterface(name, macAddress, pciAddress string) Option {
 	}
 }
 
+func WithMasqueradeInterface(name string) Option {
+	return func(vmi *kvcorev1.VirtualMachineInstance) {
+		vmi.Spec.Domain.Devices.Interfaces = append(vmi.Spec.Domain.Devices.Interfaces, kvcorev1.Interface{
+			Name: name,
+			InterfaceBindingMethod: kvcorev1.InterfaceBindingMethod{
+				Masquerade: &kvcorev1.InterfaceMasquerade{},
+			},
+		})
+	}
+}
+
 func WithNetworkInterfaceMultiQueue() Option {
 	return func(vmi *kvcorev1.VirtualMachineInstance) {
 		vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue = Pointer(true)
@@ -182,6 +209,13 @@ func WithTerminationGracePeriodSeconds(terminationGracePeriodSeconds int64) Opti
 	}
 }
 
+// WithPriorityClassName sets the PriorityClass of the VMI, which KubeVirt applies to its virt-launcher Pod.
+func WithPriorityClassName(priorityClassName string) Option {
+	return func(vmi *kvcorev1.VirtualMachineInstance) {
+		vmi.Spec.PriorityClassName = priorityClassName
+	}
+}
+
 func WithMultusNetwork(name, networkAttachmentDefinitionName string) Option {
 	return func(vmi *kvcorev1.VirtualMachineInstance) {
 		vmi.Spec.Networks = append(vmi.Spec.Networks, kvcorev1.Network{
@@ -195,6 +229,17 @@ func WithMultusNetwork(name, networkAttachmentDefinitionName string) Option {
 	}
 }
 
+func WithPodNetwork(name string) Option {
+	return func(vmi *kvcorev1.VirtualMachineInstance) {
+		vmi.Spec.Networks = append(vmi.Spec.Networks, kvcorev1.Network{
+			Name: name,
+			NetworkSource: kvcorev1.NetworkSource{
+				Pod: &kvcorev1.PodNetwork{},
+			},
+		})
+	}
+}
+
 func WithContainerDisk(volumeName, imageName string) Option {
 	return func(vmi *kvcorev1.VirtualMachineInstance) {
 		newVolume := kvcorev1.Volume{
@@ -253,6 +298,26 @@ func WithLabels(labels map[string]string) Option {
 	}
 }
 
+// WithAnnotations adds the given annotations.
+func WithAnnotations(annotations map[string]string) Option {
+	return func(vmi *kvcorev1.VirtualMachineInstance) {
+		if vmi.ObjectMeta.Annotations == nil {
+			vmi.ObjectMeta.Annotations = map[string]string{}
+		}
+
+		for key, val := range annotations {
+			vmi.ObjectMeta.Annotations[key] = val
+		}
+	}
+}
+
+// WithTolerations adds the given tolerations.
+func WithTolerations(tolerations []corev1.Toleration) Option {
+	return func(vmi *kvcorev1.VirtualMachineInstance) {
+		vmi.Spec.Tolerations = append(vmi.Spec.Tolerations, tolerations...)
+	}
+}
+
 // WithAffinity adds the given affinity.
 func WithAffinity(affinity *corev1.Affinity) Option {
 	return func(vmi *kvcorev1.VirtualMachineInstance) {
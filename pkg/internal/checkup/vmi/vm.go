@@ -0,0 +1,54 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package vmi
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kvcorev1 "kubevirt.io/api/core/v1"
+)
+
+// NewVirtualMachine returns a VirtualMachine that always runs a VMI based on the given one, under the same name.
+// The VMI's owner references are moved to the VirtualMachine, which owns the VMI it runs.
+func NewVirtualMachine(vmiTemplate *kvcorev1.VirtualMachineInstance) *kvcorev1.VirtualMachine {
+	runStrategy := kvcorev1.RunStrategyAlways
+
+	return &kvcorev1.VirtualMachine{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       kvcorev1.VirtualMachineGroupVersionKind.Kind,
+			APIVersion: kvcorev1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            vmiTemplate.Name,
+			GenerateName:    vmiTemplate.GenerateName,
+			Labels:          vmiTemplate.Labels,
+			OwnerReferences: vmiTemplate.OwnerReferences,
+		},
+		Spec: kvcorev1.VirtualMachineSpec{
+			RunStrategy: &runStrategy,
+			Template: &kvcorev1.VirtualMachineInstanceTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      vmiTemplate.Labels,
+					Annotations: vmiTemplate.Annotations,
+				},
+				Spec: vmiTemplate.Spec,
+			},
+		},
+	}
+}
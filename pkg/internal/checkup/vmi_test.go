@@ -36,7 +36,7 @@ func TestAffinityCalculation(t *testing.T) {
 	t.Run("When node affinity is expected", func(t *testing.T) {
 		nodeName := "node01"
 
-		actualAffinity := checkup.Affinity(nodeName, ownerUID)
+		actualAffinity := checkup.Affinity(nodeName, "", ownerUID, false, false)
 
 		expectedAffinity := &k8scorev1.Affinity{
 			NodeAffinity: &k8scorev1.NodeAffinity{
@@ -61,7 +61,7 @@ func TestAffinityCalculation(t *testing.T) {
 	t.Run("When pod anti-affinity is expected", func(t *testing.T) {
 		var nodeName string
 
-		actualAffinity := checkup.Affinity(nodeName, ownerUID)
+		actualAffinity := checkup.Affinity(nodeName, "", ownerUID, false, false)
 
 		expectedAffinity := &k8scorev1.Affinity{
 			PodAntiAffinity: &k8scorev1.PodAntiAffinity{
@@ -87,6 +87,108 @@ func TestAffinityCalculation(t *testing.T) {
 
 		assert.Equal(t, expectedAffinity, actualAffinity)
 	})
+
+	t.Run("When node label selector affinity is expected", func(t *testing.T) {
+		actualAffinity := checkup.Affinity("", "node-role.kubernetes.io/worker-dpdk,zone in (b,a),!maintenance", ownerUID, true, false)
+
+		expectedAffinity := &k8scorev1.Affinity{
+			NodeAffinity: &k8scorev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &k8scorev1.NodeSelector{
+					NodeSelectorTerms: []k8scorev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []k8scorev1.NodeSelectorRequirement{
+								{
+									Key:      "maintenance",
+									Operator: k8scorev1.NodeSelectorOpDoesNotExist,
+								},
+								{
+									Key:      "node-role.kubernetes.io/worker-dpdk",
+									Operator: k8scorev1.NodeSelectorOpExists,
+								},
+								{
+									Key:      "zone",
+									Operator: k8scorev1.NodeSelectorOpIn,
+									Values:   []string{"a", "b"},
+								},
+							},
+						},
+					},
+				},
+			},
+			PodAffinity: &k8scorev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{
+					{
+						TopologyKey: k8scorev1.LabelHostname,
+						LabelSelector: &k8smetav1.LabelSelector{
+							MatchExpressions: []k8smetav1.LabelSelectorRequirement{
+								{
+									Operator: k8smetav1.LabelSelectorOpIn,
+									Key:      checkup.DPDKCheckupUIDLabelKey,
+									Values:   []string{ownerUID},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, expectedAffinity, actualAffinity)
+	})
+
+	t.Run("When required pod anti-affinity is expected", func(t *testing.T) {
+		var nodeName string
+
+		actualAffinity := checkup.Affinity(nodeName, "", ownerUID, false, true)
+
+		expectedAffinity := &k8scorev1.Affinity{
+			PodAntiAffinity: &k8scorev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{
+					{
+						TopologyKey: k8scorev1.LabelHostname,
+						LabelSelector: &k8smetav1.LabelSelector{
+							MatchExpressions: []k8smetav1.LabelSelectorRequirement{
+								{
+									Operator: k8smetav1.LabelSelectorOpIn,
+									Key:      checkup.DPDKCheckupUIDLabelKey,
+									Values:   []string{ownerUID},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, expectedAffinity, actualAffinity)
+	})
+
+	t.Run("When pod affinity is expected", func(t *testing.T) {
+		var nodeName string
+
+		actualAffinity := checkup.Affinity(nodeName, "", ownerUID, true, false)
+
+		expectedAffinity := &k8scorev1.Affinity{
+			PodAffinity: &k8scorev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{
+					{
+						TopologyKey: k8scorev1.LabelHostname,
+						LabelSelector: &k8smetav1.LabelSelector{
+							MatchExpressions: []k8smetav1.LabelSelectorRequirement{
+								{
+									Operator: k8smetav1.LabelSelectorOpIn,
+									Key:      checkup.DPDKCheckupUIDLabelKey,
+									Values:   []string{ownerUID},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, expectedAffinity, actualAffinity)
+	})
 }
 
 func TestCloudInitString(t *testing.T) {
@@ -96,13 +198,39 @@ func TestCloudInitString(t *testing.T) {
 			"sudo mount /dev/$(lsblk --nodeps -no name,serial | grep DEADBEEF | cut -f1 -d' ') /mnt/app-config",
 		}
 
-		actualString := checkup.CloudInit(bootCommands)
+		actualString := checkup.CloudInit("", bootCommands, nil)
 		expectedString := `#cloud-config
 bootcmd:
   - "sudo mkdir /mnt/app-config"
   - "sudo mount /dev/$(lsblk --nodeps -no name,serial | grep DEADBEEF | cut -f1 -d' ') /mnt/app-config"
 `
 
+		assert.Equal(t, expectedString, actualString)
+	})
+	t.Run("with SSH authorized keys", func(t *testing.T) {
+		sshAuthorizedKeys := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBXz0sMlTJX7fzRPCp9Dlmlu4w7y2Z3u9mlvNpC+3fZ5"}
+
+		actualString := checkup.CloudInit("", []string{"touch /tmp/ready"}, sshAuthorizedKeys)
+		expectedString := `#cloud-config
+disable_root: false
+ssh_authorized_keys:
+  - "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBXz0sMlTJX7fzRPCp9Dlmlu4w7y2Z3u9mlvNpC+3fZ5"
+runcmd:
+  - "systemctl enable --now sshd"
+bootcmd:
+  - "touch /tmp/ready"
+`
+
+		assert.Equal(t, expectedString, actualString)
+	})
+	t.Run("with root password", func(t *testing.T) {
+		actualString := checkup.CloudInit("0123456789abcdef", []string{"touch /tmp/ready"}, nil)
+		expectedString := `#cloud-config
+bootcmd:
+  - "echo 'root:0123456789abcdef' | chpasswd"
+  - "touch /tmp/ready"
+`
+
 		assert.Equal(t, expectedString, actualString)
 	})
 }
@@ -0,0 +1,136 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package checkup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+)
+
+const (
+	// vmiRewatchInterval is how long to wait before reading and watching a VMI again, once its watch has ended.
+	vmiRewatchInterval = time.Second
+	// vmiPollInterval is how long to wait before reading the VMI again, once it could not be watched.
+	vmiPollInterval = 5 * time.Second
+)
+
+// vmiCondition reports whether a VMI has reached the awaited state. A nil VMI stands for a VMI that does not exist.
+type vmiCondition func(vmi *kvcorev1.VirtualMachineInstance) (bool, error)
+
+// waitForVMI waits for the VMI to meet the condition. The VMI is read, and then watched from the read version on,
+// detecting its changes as they happen. Once the watch ends, e.g. when the API server closes it, the VMI is read and
+// watched again. Once the VMI cannot be watched, e.g. when watching is forbidden, it is polled instead, and once its
+// watch fails, it is watched again after the poll interval. Like the polling waits, wait.ErrWaitTimeout is returned
+// when the context is done first.
+func (c *Checkup) waitForVMI(ctx context.Context, name string, condition vmiCondition) error {
+	vmiFullName := ObjectFullName(c.namespace, name)
+	polling, watchFailureLogged := false, false
+	for {
+		vmi, err := c.client.GetVirtualMachineInstance(ctx, c.namespace, name)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			if ctx.Err() != nil {
+				return wait.ErrWaitTimeout
+			}
+			return err
+		}
+
+		var resourceVersion string
+		if err != nil {
+			vmi = nil
+		} else {
+			resourceVersion = vmi.ResourceVersion
+		}
+
+		if met, err := condition(vmi); met || err != nil {
+			return err
+		}
+
+		interval := vmiPollInterval
+		if !polling {
+			met, watchErr, err := c.watchVMI(ctx, name, resourceVersion, condition)
+			if met || err != nil {
+				return err
+			}
+			switch {
+			case watchErr == nil:
+				interval = vmiRewatchInterval
+			case !watchFailureLogged:
+				log.Printf("Failed to watch VMI %q, reading it every %s: %v", vmiFullName, vmiPollInterval, watchErr)
+				watchFailureLogged = true
+			}
+			polling = errors.Is(watchErr, errVMIUnwatchable)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return wait.ErrWaitTimeout
+		}
+	}
+}
+
+// errVMIUnwatchable wraps the failures to start watching a VMI.
+var errVMIUnwatchable = errors.New("cannot watch")
+
+// watchVMI watches the VMI until it meets the condition, the watch ends or the context is done.
+// The watch failure is returned apart from the condition error, as the VMI may still be read.
+func (c *Checkup) watchVMI(ctx context.Context, name, resourceVersion string, condition vmiCondition) (met bool, watchErr, err error) {
+	watcher, err := c.client.WatchVirtualMachineInstance(ctx, c.namespace, name, resourceVersion)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", errVMIUnwatchable, err), nil
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil, nil
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				if vmi, isVMI := event.Object.(*kvcorev1.VirtualMachineInstance); isVMI {
+					if met, err := condition(vmi); met || err != nil {
+						return met, nil, err
+					}
+				}
+			case watch.Deleted:
+				met, err := condition(nil)
+				return met, nil, err
+			case watch.Error:
+				return false, k8serrors.FromObject(event.Object), nil
+			}
+		}
+	}
+}
@@ -21,11 +21,18 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
 	k8scorev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	kvcorev1 "kubevirt.io/api/core/v1"
 	"kubevirt.io/client-go/kubecli"
 )
@@ -34,8 +41,18 @@ type Client struct {
 	kubecli.KubevirtClient
 }
 
+// New returns a client of the cluster the checkup runs in.
+// Out of the cluster, e.g. on a laptop or a CI runner, it falls back to the kubeconfig file of $KUBECONFIG, or of
+// ~/.kube/config.
 func New() (*Client, error) {
-	config, err := rest.InClusterConfig()
+	return NewFromKubeconfig("")
+}
+
+// NewFromKubeconfig returns a client of the cluster the given kubeconfig file points at.
+// When the path is empty, the in-cluster config is used, falling back to the kubeconfig file of $KUBECONFIG, or of
+// ~/.kube/config.
+func NewFromKubeconfig(kubeconfigPath string) (*Client, error) {
+	config, err := restConfig(kubeconfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +65,30 @@ func New() (*Client, error) {
 	return &Client{client}, nil
 }
 
+func restConfig(kubeconfigPath string) (*rest.Config, error) {
+	if kubeconfigPath == "" {
+		config, inClusterErr := rest.InClusterConfig()
+		if inClusterErr == nil {
+			return config, nil
+		}
+
+		config, err := kubeconfigLoader("").ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("%v, and no kubeconfig file is usable: %w", inClusterErr, err)
+		}
+		return config, nil
+	}
+
+	return kubeconfigLoader(kubeconfigPath).ClientConfig()
+}
+
+// kubeconfigLoader loads the kubeconfig file of the given path, or of $KUBECONFIG or ~/.kube/config when it is empty.
+func kubeconfigLoader(kubeconfigPath string) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+}
+
 func (c *Client) CreateVirtualMachineInstance(ctx context.Context,
 	namespace string,
 	vmi *kvcorev1.VirtualMachineInstance) (*kvcorev1.VirtualMachineInstance, error) {
@@ -62,6 +103,48 @@ func (c *Client) DeleteVirtualMachineInstance(ctx context.Context, namespace, na
 	return c.KubevirtClient.VirtualMachineInstance(namespace).Delete(ctx, name, &metav1.DeleteOptions{})
 }
 
+// WatchVirtualMachineInstance watches the changes of a single VMI, from the given resource version on.
+func (c *Client) WatchVirtualMachineInstance(ctx context.Context, namespace, name, resourceVersion string) (watch.Interface, error) {
+	return c.KubevirtClient.VirtualMachineInstance(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: resourceVersion,
+	})
+}
+
+func (c *Client) ListVirtualMachineInstances(ctx context.Context,
+	namespace, labelSelector string) (*kvcorev1.VirtualMachineInstanceList, error) {
+	return c.KubevirtClient.VirtualMachineInstance(namespace).List(ctx, &metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (c *Client) CreateVirtualMachine(ctx context.Context,
+	namespace string,
+	vm *kvcorev1.VirtualMachine) (*kvcorev1.VirtualMachine, error) {
+	return c.KubevirtClient.VirtualMachine(namespace).Create(ctx, vm)
+}
+
+func (c *Client) DeleteVirtualMachine(ctx context.Context, namespace, name string) error {
+	return c.KubevirtClient.VirtualMachine(namespace).Delete(ctx, name, &metav1.DeleteOptions{})
+}
+
+func (c *Client) ListVirtualMachines(ctx context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineList, error) {
+	return c.KubevirtClient.VirtualMachine(namespace).List(ctx, &metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+// MigrateVirtualMachineInstance requests the live migration of the given VMI to another node.
+func (c *Client) MigrateVirtualMachineInstance(_ context.Context,
+	namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error) {
+	migration := &kvcorev1.VirtualMachineInstanceMigration{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: name + "-migration-"},
+		Spec:       kvcorev1.VirtualMachineInstanceMigrationSpec{VMIName: name},
+	}
+	return c.KubevirtClient.VirtualMachineInstanceMigration(namespace).Create(migration, &metav1.CreateOptions{})
+}
+
+func (c *Client) GetVirtualMachineInstanceMigration(_ context.Context,
+	namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error) {
+	return c.KubevirtClient.VirtualMachineInstanceMigration(namespace).Get(name, &metav1.GetOptions{})
+}
+
 func (c *Client) VMISerialConsole(namespace, name string, timeout time.Duration) (kubecli.StreamInterface, error) {
 	return c.KubevirtClient.VirtualMachineInstance(namespace).SerialConsole(
 		name,
@@ -69,10 +152,64 @@ func (c *Client) VMISerialConsole(namespace, name string, timeout time.Duration)
 	)
 }
 
+func (c *Client) VMIPortForward(namespace, name string, port int) (kubecli.StreamInterface, error) {
+	const protocol = "tcp"
+	return c.KubevirtClient.VirtualMachineInstance(namespace).PortForward(name, port, protocol)
+}
+
 func (c *Client) CreateConfigMap(ctx context.Context, namespace string, configMap *k8scorev1.ConfigMap) (*k8scorev1.ConfigMap, error) {
 	return c.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
 }
 
+func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*k8scorev1.ConfigMap, error) {
+	return c.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
 func (c *Client) DeleteConfigMap(ctx context.Context, namespace, name string) error {
 	return c.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
+
+func (c *Client) ListConfigMaps(ctx context.Context, namespace, labelSelector string) (*k8scorev1.ConfigMapList, error) {
+	return c.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (c *Client) CreateEvent(ctx context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error) {
+	return c.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{})
+}
+
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (*k8scorev1.Pod, error) {
+	return c.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Client) ListPods(ctx context.Context, namespace, labelSelector string) (*k8scorev1.PodList, error) {
+	return c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (c *Client) GetNode(ctx context.Context, name string) (*k8scorev1.Node, error) {
+	return c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Client) ListNodes(ctx context.Context, labelSelector string) (*k8scorev1.NodeList, error) {
+	return c.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (c *Client) GetNetworkAttachmentDefinition(ctx context.Context,
+	namespace, name string) (*netattdefv1.NetworkAttachmentDefinition, error) {
+	return c.NetworkClient().K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetKubeVirtVersion returns the KubeVirt version deployed on the cluster, as observed by its KubeVirt CR.
+func (c *Client) GetKubeVirtVersion(_ context.Context) (string, error) {
+	kubeVirts, err := c.KubevirtClient.KubeVirt(metav1.NamespaceAll).List(&metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for i := range kubeVirts.Items {
+		if observedVersion := kubeVirts.Items[i].Status.ObservedKubeVirtVersion; observedVersion != "" {
+			return observedVersion, nil
+		}
+	}
+
+	return "", errors.New("no deployed KubeVirt CR was found")
+}
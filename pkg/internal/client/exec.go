@@ -0,0 +1,181 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// The channels of the Kubernetes streaming protocol, each message is prefixed by the channel it belongs to.
+const (
+	execStreamProtocol = "v4.channel.k8s.io"
+	execStdoutChannel  = 1
+	execStderrChannel  = 2
+	execErrorChannel   = 3
+)
+
+// ExecInPod runs the command in the given container of the Pod, with no standard input, and returns its standard output.
+func (c *Client) ExecInPod(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+	restConfig := c.KubevirtClient.Config()
+	execURL, err := podExecURL(restConfig.Host, namespace, podName, container, command)
+	if err != nil {
+		return "", err
+	}
+
+	tlsConfig, err := rest.TLSConfigFor(restConfig)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	roundTripper, err := rest.HTTPWrappersForConfig(restConfig, &execRoundTripper{
+		dialer: &websocket.Dialer{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+			Subprotocols:    []string{execStreamProtocol},
+		},
+		stdout: &stdout,
+		stderr: &stderr,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, execURL, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	if _, err := roundTripper.RoundTrip(request); err != nil {
+		return "", fmt.Errorf("failed to exec %q in Pod \"%s/%s\": %w (stderr: %q)",
+			strings.Join(command, " "), namespace, podName, err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+func podExecURL(host, namespace, podName, container string, command []string) (string, error) {
+	execURL, err := url.Parse(host)
+	if err != nil {
+		return "", err
+	}
+
+	switch execURL.Scheme {
+	case "https":
+		execURL.Scheme = "wss"
+	case "http":
+		execURL.Scheme = "ws"
+	default:
+		return "", fmt.Errorf("unsupported protocol %q", execURL.Scheme)
+	}
+
+	execURL.Path = path.Join(execURL.Path, "api", "v1", "namespaces", namespace, "pods", podName, "exec")
+	query := url.Values{
+		"container": {container},
+		"stdout":    {"true"},
+		"stderr":    {"true"},
+		"command":   command,
+	}
+	execURL.RawQuery = query.Encode()
+
+	return execURL.String(), nil
+}
+
+// execRoundTripper connects to the exec endpoint over a websocket, and reads the command streams until it ends.
+type execRoundTripper struct {
+	dialer *websocket.Dialer
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+}
+
+func (rt *execRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	conn, response, err := rt.dialer.DialContext(request.Context(), request.URL.String(), request.Header)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("%w: %s", err, response.Status)
+		}
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := request.Context().Deadline(); ok {
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, rt.readStreams(conn)
+}
+
+func (rt *execRoundTripper) readStreams(conn *websocket.Conn) error {
+	var execErr error
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			// The server closes the connection once the command has ended and its status was sent
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				return execErr
+			}
+			return err
+		}
+
+		// Each stream starts with an empty message, holding its channel only
+		if len(message) < 2 {
+			continue
+		}
+
+		data := message[1:]
+		switch message[0] {
+		case execStdoutChannel:
+			rt.stdout.Write(data)
+		case execStderrChannel:
+			rt.stderr.Write(data)
+		case execErrorChannel:
+			execErr = statusError(data)
+		}
+	}
+}
+
+// statusError returns the error the command status reports, e.g. of a non-zero exit code.
+func statusError(data []byte) error {
+	var status metav1.Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("failed to parse the exec status %q: %w", data, err)
+	}
+
+	if status.Status == metav1.StatusSuccess {
+		return nil
+	}
+
+	return errors.New(status.Message)
+}
@@ -20,33 +20,169 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math"
 	"net"
+	"path"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/ssh"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
 	kconfig "github.com/kiagnose/kiagnose/kiagnose/config"
 )
 
 const (
-	NetworkAttachmentDefinitionNameParamName = "networkAttachmentDefinitionName"
-	TrafficGenContainerDiskImageParamName    = "trafficGenContainerDiskImage"
-	TrafficGenTargetNodeNameParamName        = "trafficGenTargetNodeName"
-	TrafficGenPacketsPerSecondParamName      = "trafficGenPacketsPerSecond"
-	VMUnderTestContainerDiskImageParamName   = "vmUnderTestContainerDiskImage"
-	VMUnderTestTargetNodeNameParamName       = "vmUnderTestTargetNodeName"
-	TestDurationParamName                    = "testDuration"
-	PortBandwidthGbpsParamName               = "portBandwidthGbps"
-	VerboseParamName                         = "verbose"
+	NetworkAttachmentDefinitionNameParamName     = "networkAttachmentDefinitionName"
+	NetworkAttachmentDefinitionNameEastParamName = "networkAttachmentDefinitionNameEast"
+	NetworkAttachmentDefinitionNameWestParamName = "networkAttachmentDefinitionNameWest"
+	InterfacesParamName                          = "interfaces"
+	TrafficGenContainerDiskImageParamName        = "trafficGenContainerDiskImage"
+	TrafficGenTargetNodeNameParamName            = "trafficGenTargetNodeName"
+	TrafficGenNodeLabelSelectorParamName         = "trafficGenNodeLabelSelector"
+	TrafficGenPacketsPerSecondParamName          = "trafficGenPacketsPerSecond"
+	TrafficGenServiceModeParamName               = "trafficGenServiceMode"
+	TrafficVlanIDParamName                       = "trafficVlanId"
+	IPFamilyParamName                            = "ipFamily"
+	TrafficGenGatewayMacAddressesParamName       = "trafficGenGatewayMacAddresses"
+	TrafficGenPortIPsParamName                   = "trafficGenPortIPs"
+	TrafficGenPortGatewaysParamName              = "trafficGenPortGateways"
+	TrafficGenFlowCountParamName                 = "trafficGenFlowCount"
+	TrafficGenFlowStatsParamName                 = "trafficGenFlowStats"
+	TrafficGenServerCoresParamName               = "trafficGenServerCores"
+	TrafficGenServerIOModeParamName              = "trafficGenServerIOMode"
+	TrafficGenScapyServerParamName               = "trafficGenScapyServer"
+	TrafficGenSoftwareModeParamName              = "trafficGenSoftwareMode"
+	VMUnderTestContainerDiskImageParamName       = "vmUnderTestContainerDiskImage"
+	VMUnderTestTargetNodeNameParamName           = "vmUnderTestTargetNodeName"
+	VMUnderTestNodeLabelSelectorParamName        = "vmUnderTestNodeLabelSelector"
+	TestDurationParamName                        = "testDuration"
+	TestIterationsParamName                      = "testIterations"
+	PerDirectionRunsParamName                    = "perDirectionRuns"
+	ScenariosParamName                           = "scenarios"
+	BurstMaxSizeParamName                        = "burstMaxSize"
+	RateSweepStepsParamName                      = "rateSweepSteps"
+	RateSweepStepDurationParamName               = "rateSweepStepDuration"
+	WarmupDurationParamName                      = "warmupDuration"
+	PortBandwidthGbpsParamName                   = "portBandwidthGbps"
+	VerboseParamName                             = "verbose"
+	ConsoleTranscriptParamName                   = "consoleTranscript"
+	LogConsoleTranscriptsParamName               = "logConsoleTranscripts"
+	SkipTeardownParamName                        = "skipTeardown"
+	AllowConcurrentRunsParamName                 = "allowConcurrentRuns"
+	PriorityClassNameParamName                   = "priorityClassName"
+	RuntimeClassNameParamName                    = "runtimeClassName"
+	VMIExtraLabelsParamName                      = "vmiExtraLabels"
+	VMIExtraAnnotationsParamName                 = "vmiExtraAnnotations"
+	VMITolerationsParamName                      = "vmiTolerations"
+	TargetNodeTolerationsParamName               = "targetNodeTolerations"
+	CPUIsolationCheckParamName                   = "cpuIsolationCheck"
+	MeasurementIsolationParamName                = "measurementIsolation"
+	CriteriaSeveritiesParamName                  = "criteriaSeverities"
+	UseVirtualMachinesParamName                  = "useVirtualMachines"
+	RequireRealtimeKernelParamName               = "requireRealtimeKernel"
+	NUMAPassthroughParamName                     = "numaPassthrough"
+	MigrateVMUnderTestParamName                  = "migrateVMUnderTest"
+	MaxAllowedDropRateBpsParamName               = "maxAllowedDropRateBps"
+	SnapshotIntervalParamName                    = "snapshotInterval"
+	MatrixNodeSelectorParamName                  = "matrixNodeSelector"
+	MatrixTestDurationParamName                  = "matrixTestDuration"
+	SameNodePlacementParamName                   = "sameNodePlacement"
+	RequireDifferentNodesParamName               = "requireDifferentNodes"
+	SetupRetriesParamName                        = "setupRetries"
+	SetupAttemptTimeoutParamName                 = "setupAttemptTimeout"
+	SetupRetryBackoffParamName                   = "setupRetryBackoff"
+	SetupTimeoutParamName                        = "setupTimeout"
+	VMIBootTimeoutParamName                      = "vmiBootTimeout"
+	ConsoleCommandTimeoutParamName               = "consoleCommandTimeout"
+	TeardownTimeoutParamName                     = "teardownTimeout"
+	GuestCommandTransportParamName               = "guestCommandTransport"
+	TrexBinaryPathParamName                      = "trexBinaryPath"
+	TrexTransportParamName                       = "trexTransport"
+	TestpmdBinaryPathParamName                   = "testpmdBinaryPath"
+	TestpmdMbufSizeParamName                     = "testpmdMbufSize"
+	TestpmdTotalNumMbufsParamName                = "testpmdTotalNumMbufs"
+	TestpmdMemoryChannelsParamName               = "testpmdMemoryChannels"
+	TestpmdCPUsParamName                         = "testpmdCPUs"
+	TrexMasterCPUParamName                       = "trexMasterCPU"
+	TrexLatencyCPUParamName                      = "trexLatencyCPU"
+	TrexTrafficCPUsParamName                     = "trexTrafficCPUs"
+	VMUnderTestWorkloadParamName                 = "vmUnderTestWorkload"
+	NICDriverModeParamName                       = "nicDriverMode"
+	ResultSinksParamName                         = "resultSinks"
+	ResultsFormatParamName                       = "resultsFormat"
+	ResultsObjectNameParamName                   = "resultsObjectName"
+	ResultsObjectKindParamName                   = "resultsObjectKind"
+	ArtifactsDirParamName                        = "artifactsDir"
+	ArtifactsPVCNameParamName                    = "artifactsPVCName"
+	ReproScriptParamName                         = "reproScript"
+	ProgressIntervalParamName                    = "progressInterval"
+	BaselineConfigMapNameParamName               = "baselineConfigMapName"
 )
 
+// AbortKey is the checkup ConfigMap key that requests a running checkup to abort gracefully, once set to "true".
+// Unlike the params, it is re-read while the checkup runs.
+const AbortKey = "spec.abort"
+
 const (
 	TrafficGenDefaultPacketsPerSecond = "8m"
 	TestDurationDefault               = 5 * time.Minute
+	TestIterationsDefault             = 1
+	PerDirectionRunsDefault           = false
+	ScenariosDefault                  = ScenarioThroughput
+	BurstMaxSizeDefault               = 4096
+	RateSweepStepsDefault             = 10
+	RateSweepStepDurationDefault      = 30 * time.Second
+	WarmupDurationDefault             = 0
 	PortBandwidthGbpsDefault          = 10
 	VerboseDefault                    = false
+	ConsoleTranscriptDefault          = ConsoleTranscriptOnFailure
+	LogConsoleTranscriptsDefault      = true
+	SkipTeardownDefault               = SkipTeardownNever
+	AllowConcurrentRunsDefault        = false
+	CPUIsolationCheckDefault          = CPUIsolationCheckWarn
+	MeasurementIsolationDefault       = MeasurementIsolationNone
+	TrafficGenServiceModeDefault      = false
+	TrafficGenFlowStatsDefault        = false
+	TrafficGenServerIOModeDefault     = 0
+	TrafficGenScapyServerDefault      = false
+	TrafficGenSoftwareModeDefault     = false
+	IPFamilyDefault                   = IPFamilyIPv4
+	UseVirtualMachinesDefault         = false
+	ReproScriptDefault                = false
+	RequireRealtimeKernelDefault      = false
+	NUMAPassthroughDefault            = false
+	MigrateVMUnderTestDefault         = false
+	MatrixTestDurationDefault         = time.Minute
+	SameNodePlacementDefault          = false
+	RequireDifferentNodesDefault      = false
+	SetupRetriesDefault               = 0
+	SetupAttemptTimeoutDefault        = 5 * time.Minute
+	SetupRetryBackoffDefault          = 10 * time.Second
+	SetupTimeoutDefault               = 15 * time.Minute
+	ConsoleCommandTimeoutDefault      = 30 * time.Second
+	TeardownTimeoutDefault            = 5 * time.Minute
+	GuestCommandTransportDefault      = GuestCommandTransportConsole
+	TrexBinaryPathDefault             = "/opt/trex/t-rex-64"
+	TrexTransportDefault              = TrexTransportConsole
+	TestpmdBinaryPathDefault          = "dpdk-testpmd"
+	L3fwdBinaryPath                   = "dpdk-l3fwd"
+	VMUnderTestWorkloadDefault        = VMUnderTestWorkloadTestpmd
+	NICDriverModeDefault              = NICDriverModeAuto
+	ResultSinksDefault                = ResultSinksConfigMap
+	ResultsFormatDefault              = ResultsFormatConfigMapKeys
+	ResultsObjectKindDefault          = ResultsObjectKindConfigMap
+	ProgressIntervalDefault           = 30 * time.Second
 
 	TrafficGenMACAddressPrefixOctet  = 0x50
 	VMUnderTestMACAddressPrefixOctet = 0x60
@@ -55,44 +191,327 @@ const (
 )
 
 const (
-	VMIPassword = "redhat" // #nosec
+	OwnershipModeOwnerReference = "ownerReference"
+	OwnershipModeLabel          = "label"
+)
+
+const (
+	VMUnderTestWorkloadTestpmd = "testpmd"
+	VMUnderTestWorkloadL3fwd   = "l3fwd"
+)
+
+// The NIC driver modes, setting how the guests NICs are bound for DPDK.
+const (
+	// NICDriverModeAuto binds the NICs to vfio-pci, except for the Mellanox NICs, detected by their vendor ID.
+	NICDriverModeAuto = "auto"
+	// NICDriverModeVFIO binds all the NICs to vfio-pci.
+	NICDriverModeVFIO = "vfio"
+	// NICDriverModeMLX5 keeps all the NICs bound to their bifurcated mlx5_core driver.
+	NICDriverModeMLX5 = "mlx5"
+)
+
+// The scenarios the checkup traffic may run, in the order they are listed.
+const (
+	// ScenarioThroughput runs the unicast traffic, measuring the forwarded packets.
+	ScenarioThroughput = "throughput"
+	// ScenarioPromiscuous sends multicast and broadcast frames, with the VM under test promiscuous mode toggled.
+	ScenarioPromiscuous = "promiscuous"
+	// ScenarioBurst sends line-rate bursts of increasing sizes, separated by idle gaps.
+	ScenarioBurst = "burst"
+	// ScenarioRateSweep sends the traffic at increasing rates, up to the line rate, recording the loss at each rate.
+	ScenarioRateSweep = "rate-sweep"
+)
+
+// BurstMinSize is the size of the first bursts of the burst scenario, in packets.
+// Each following burst size doubles the previous one, up to the burst max size.
+const BurstMinSize = 32
 
-	VMIEastNICPCIAddress = "0000:06:00.0"
-	VMIWestNICPCIAddress = "0000:07:00.0"
+// SnapshotIntervalMin is the shortest snapshot interval, as the snapshots are taken on the stats polls, 10s apart.
+const SnapshotIntervalMin = 10 * time.Second
 
+const (
+	GuestCommandTransportConsole    = "console"
+	GuestCommandTransportGuestAgent = "guest-agent"
+	GuestCommandTransportSSH        = "ssh"
+)
+
+const (
+	TrexTransportConsole = "console"
+	TrexTransportRPC     = "rpc"
+)
+
+const (
+	IPFamilyIPv4 = "ipv4"
+	IPFamilyIPv6 = "ipv6"
+	IPFamilyDual = "dual"
+)
+
+const (
+	ConsoleTranscriptOnFailure = "onFailure"
+	ConsoleTranscriptAlways    = "always"
+)
+
+const (
+	SkipTeardownAlways    = "always"
+	SkipTeardownOnFailure = "onFailure"
+	SkipTeardownNever     = "never"
+)
+
+const (
+	CPUIsolationCheckWarn = "warn"
+	CPUIsolationCheckFail = "fail"
+	CPUIsolationCheckSkip = "skip"
+)
+
+const (
+	MeasurementIsolationNone   = "none"
+	MeasurementIsolationStrict = "strict"
+)
+
+// The built-in verification criteria, which may be assigned a severity.
+const (
+	CriterionErrors = "errors"
+	CriterionDrops  = "drops"
+	CriterionLoss   = "loss"
+	// CriterionDropRate is checked only when a max allowed drop rate is set.
+	CriterionDropRate = "dropRate"
+)
+
+const (
+	SeverityBlocker = "blocker"
+	SeverityMajor   = "major"
+	SeverityMinor   = "minor"
+)
+
+const (
+	ResultSinksConfigMap  = "configmap"
+	ResultSinksStdoutOnly = "stdout-only"
+)
+
+const (
+	ResultsFormatConfigMapKeys = "configmap-keys"
+	ResultsFormatJSON          = "json"
+)
+
+const (
+	ResultsObjectKindConfigMap = "ConfigMap"
+	ResultsObjectKindSecret    = "Secret"
+)
+
+const (
 	BootScriptName                          = "dpdk-checkup-boot.sh"
 	BootScriptBinDirectory                  = "/usr/bin/"
 	BootScriptTunedAdmSetMarkerFileFullPath = "/var/dpdk-checkup-tuned-adm-set-marker"
 	BootScriptReadinessMarkerFileFullPath   = "/tmp/dpdk-checkup-ready-marker"
+	// BootScriptPCIAddressesFileFullPath lists the PCI addresses the boot script discovered for the test NICs, a
+	// "<MAC address> <PCI address>" line per NIC. It is kept across reboots, as the NICs bound to vfio-pci can no longer
+	// be discovered by their MAC address.
+	BootScriptPCIAddressesFileFullPath = "/var/dpdk-checkup-pci-addresses"
+
+	// GuestIsolatedCPUs are the guests CPUs the tuned cpu-partitioning profile isolates for the DPDK applications.
+	GuestIsolatedCPUs = "2-7"
+
+	// GuestPodNetworkName is the pod network the guests are reached on, over SSH or through the TRex RPC port.
+	GuestPodNetworkName = "default"
 )
 
 var (
 	ErrInvalidNetworkAttachmentDefinitionName = errors.New("invalid Network-Attachment-Definition Name")
+	ErrInvalidInterfaces                      = errors.New("invalid Interfaces")
 	ErrInvalidTrafficGenContainerDiskImage    = errors.New("invalid Traffic Generator container disk image")
 	ErrIllegalTargetNodeNamesCombination      = errors.New("illegal Traffic Generator and VM under test target node names combination")
 	ErrInvalidTrafficGenPacketsPerSecond      = errors.New("invalid Traffic Generator Packets Per Second")
+	ErrInvalidTrafficGenServiceMode           = errors.New("invalid Traffic Generator Service Mode value [true|false]")
+	ErrInvalidTrafficVlanID                   = errors.New("invalid Traffic VLAN ID [1-4094]")
+	ErrInvalidIPFamily                        = errors.New("invalid IP Family value [ipv4|ipv6|dual]")
+	ErrInvalidTrafficGenGatewayMacAddresses   = errors.New("invalid Traffic Generator Gateway MAC Addresses")
+	ErrInvalidTrafficGenPortIPs               = errors.New("invalid Traffic Generator Port IPs")
+	ErrInvalidTrafficGenPortGateways          = errors.New("invalid Traffic Generator Port Gateways")
+	ErrIllegalTrafficGenPortIPsCombination    = errors.New("illegal Traffic Generator Port IPs combination")
+	ErrInvalidTrafficGenFlowCount             = errors.New("invalid Traffic Generator Flow Count [1-32768]")
+	ErrInvalidTrafficGenFlowStats             = errors.New("invalid Traffic Generator Flow Stats value [true|false]")
+	ErrInvalidTrafficGenServerCores           = errors.New("invalid Traffic Generator Server Cores [1-traffic CPUs per ports pair]")
+	ErrInvalidTrafficGenServerIOMode          = errors.New("invalid Traffic Generator Server IO Mode [0-2]")
+	ErrInvalidTrafficGenScapyServer           = errors.New("invalid Traffic Generator Scapy Server value [true|false]")
+	ErrInvalidTrafficGenSoftwareMode          = errors.New("invalid Traffic Generator Software Mode value [true|false]")
+	ErrIllegalServiceModeIPFamilyCombination  = errors.New("illegal Traffic Generator Service Mode with ipv6 IP Family")
 	ErrInvalidVMUnderTestContainerDiskImage   = errors.New("invalid VM Under test container disk image")
 	ErrInvalidTestDuration                    = errors.New("invalid Test Duration")
+	ErrInvalidTestIterations                  = errors.New("invalid Test Iterations")
+	ErrInvalidPerDirectionRuns                = errors.New("invalid Per Direction Runs value [true|false]")
+	ErrIllegalPerDirectionRunsCombination     = errors.New("illegal Per Direction Runs with l3fwd VM under test Workload")
+	ErrInvalidScenarios                       = errors.New("invalid Scenarios, a list of [throughput|promiscuous|burst|rate-sweep]")
+	ErrInvalidBurstMaxSize                    = errors.New("invalid Burst Max Size [32-32768]")
+	ErrInvalidRateSweepSteps                  = errors.New("invalid Rate Sweep Steps [1-100]")
+	ErrInvalidRateSweepStepDuration           = errors.New("invalid Rate Sweep Step Duration")
+	ErrIllegalPromiscuousScenarioCombination  = errors.New("illegal promiscuous Scenario with l3fwd VM under test Workload or routed traffic")
+	ErrInvalidWarmupDuration                  = errors.New("invalid Warmup Duration")
 	ErrInvalidPortBandwidthGbps               = errors.New("invalid Port Bandwidth [Gbps]")
 	ErrInvalidVerbose                         = errors.New("invalid Verbose value [true|false]")
+	ErrInvalidConsoleTranscript               = errors.New("invalid Console Transcript value [onFailure|always]")
+	ErrInvalidLogConsoleTranscripts           = errors.New("invalid Log Console Transcripts value [true|false]")
+	ErrInvalidSkipTeardown                    = errors.New("invalid Skip Teardown value [always|onFailure|never]")
+	ErrInvalidAllowConcurrentRuns             = errors.New("invalid Allow Concurrent Runs value [true|false]")
+	ErrInvalidPriorityClassName               = errors.New("invalid Priority Class Name")
+	ErrInvalidRuntimeClassName                = errors.New("invalid Runtime Class Name")
+	ErrInvalidVMIExtraLabels                  = errors.New("invalid VMI Extra Labels, expected <key>=<value> pairs")
+	ErrInvalidVMIExtraAnnotations             = errors.New("invalid VMI Extra Annotations, expected <key>=<value> pairs")
+	ErrInvalidVMITolerations                  = errors.New("invalid VMI Tolerations, expected <key>[=<value>][:<effect>] items")
+	ErrInvalidTargetNodeTolerations           = errors.New("invalid Target Node Tolerations, expected <key>[=<value>][:<effect>] items")
+	ErrInvalidCPUIsolationCheck               = errors.New("invalid CPU Isolation Check value [warn|fail|skip]")
+	ErrInvalidMeasurementIsolation            = errors.New("invalid Measurement Isolation value [none|strict]")
+	ErrInvalidCriteriaSeverities              = errors.New("invalid Criteria Severities, expected <criterion>:<severity> pairs")
+	ErrInvalidUseVirtualMachines              = errors.New("invalid Use Virtual Machines value [true|false]")
+	ErrInvalidRequireRealtimeKernel           = errors.New("invalid Require Realtime Kernel value [true|false]")
+	ErrInvalidNUMAPassthrough                 = errors.New("invalid NUMA Passthrough value [true|false]")
+	ErrInvalidMigrateVMUnderTest              = errors.New("invalid Migrate VM Under Test value [true|false]")
+	ErrIllegalMigrateVMUnderTestCombination   = errors.New("illegal Migrate VM Under Test with strict Measurement Isolation or target nodes")
+	ErrInvalidMaxAllowedDropRateBps           = errors.New("invalid Max Allowed Drop Rate Bps, a positive number is expected")
+	ErrIllegalMaxAllowedDropRateCombination   = errors.New("illegal Max Allowed Drop Rate Bps with strict Measurement Isolation")
+	ErrInvalidSnapshotInterval                = errors.New("invalid Snapshot Interval, at least 10s is expected")
+	ErrIllegalSnapshotIntervalCombination     = errors.New("illegal Snapshot Interval with strict Measurement Isolation")
+	ErrInvalidMatrixNodeSelector              = errors.New("invalid Matrix Node Selector, a label selector is expected")
+	ErrIllegalMatrixNodeSelectorCombination   = errors.New("illegal Matrix Node Selector with target nodes or Migrate VM Under Test")
+	ErrInvalidTrafficGenNodeLabelSelector     = errors.New("invalid Traffic Generator Node Label Selector, a label selector is expected")
+	ErrInvalidVMUnderTestNodeLabelSelector    = errors.New("invalid VM Under Test Node Label Selector, a label selector is expected")
+	ErrIllegalNodeLabelSelectorCombination    = errors.New("illegal Node Label Selector with target node names")
+	ErrInvalidMatrixTestDuration              = errors.New("invalid Matrix Test Duration")
+	ErrInvalidSameNodePlacement               = errors.New("invalid Same Node Placement value [true|false]")
+	ErrIllegalSameNodePlacementCombination    = errors.New("illegal Same Node Placement with different target nodes, migration or matrix")
+	ErrInvalidRequireDifferentNodes           = errors.New("invalid Require Different Nodes value [true|false]")
+	ErrIllegalDifferentNodesCombination       = errors.New("illegal Require Different Nodes with Same Node Placement or the same target node")
+	ErrInvalidSetupRetries                    = errors.New("invalid Setup Retries [0-10]")
+	ErrInvalidSetupAttemptTimeout             = errors.New("invalid Setup Attempt Timeout")
+	ErrInvalidSetupRetryBackoff               = errors.New("invalid Setup Retry Backoff")
+	ErrInvalidSetupTimeout                    = errors.New("invalid Setup Timeout")
+	ErrInvalidVMIBootTimeout                  = errors.New("invalid VMI Boot Timeout")
+	ErrInvalidConsoleCommandTimeout           = errors.New("invalid Console Command Timeout")
+	ErrInvalidTeardownTimeout                 = errors.New("invalid Teardown Timeout")
+	ErrInvalidGuestCommandTransport           = errors.New("invalid Guest Command Transport value [console|guest-agent|ssh]")
+	ErrIllegalSSHTransportCombination         = errors.New("illegal SSH Guest Command Transport with Migrate VM Under Test")
+	ErrInvalidTrexBinaryPath                  = errors.New("invalid TRex Binary Path, an absolute path is expected")
+	ErrInvalidTrexTransport                   = errors.New("invalid TRex Transport value [console|rpc]")
+	ErrInvalidTestpmdBinaryPath               = errors.New("invalid testpmd Binary Path")
+	ErrInvalidTestpmdMbufSize                 = errors.New("invalid testpmd Mbuf Size")
+	ErrInvalidTestpmdTotalNumMbufs            = errors.New("invalid testpmd Total Number of Mbufs")
+	ErrInvalidTestpmdMemoryChannels           = errors.New("invalid testpmd Memory Channels")
+	ErrInvalidTestpmdCPUs                     = errors.New("invalid testpmd CPUs, expected a list of at least 2 distinct CPUs")
+	ErrInvalidTrexMasterCPU                   = errors.New("invalid TRex Master CPU")
+	ErrInvalidTrexLatencyCPU                  = errors.New("invalid TRex Latency CPU")
+	ErrInvalidTrexTrafficCPUs                 = errors.New("invalid TRex Traffic CPUs, expected a list of distinct CPUs")
+	ErrIllegalTrexCPUsCombination             = errors.New("illegal TRex CPUs, the master, latency and traffic CPUs must differ")
+	ErrInvalidVMUnderTestWorkload             = errors.New("invalid VM under test Workload value [testpmd|l3fwd]")
+	ErrInvalidNICDriverMode                   = errors.New("invalid NIC Driver Mode value [auto|vfio|mlx5]")
+	ErrInvalidArtifactsDir                    = errors.New("invalid Artifacts Directory, expected an absolute path")
+	ErrInvalidArtifactsPVCName                = errors.New("invalid Artifacts PVC Name")
+	ErrIllegalArtifactsCombination            = errors.New("illegal Artifacts PVC Name with Artifacts Directory")
+	ErrInvalidReproScript                     = errors.New("invalid Repro Script value [true|false]")
+	ErrIllegalReproScriptCombination          = errors.New("illegal Repro Script without Artifacts Directory or Artifacts PVC Name")
+	ErrInvalidProgressInterval                = errors.New("invalid Progress Interval")
+	ErrInvalidBaselineConfigMapName           = errors.New("invalid Baseline ConfigMap Name")
+	ErrIllegalVMUnderTestWorkloadCombination  = errors.New(
+		"illegal l3fwd VM under test Workload with Traffic VLAN ID or Traffic Generator Gateway MAC Addresses")
+	ErrInvalidResultSinks              = errors.New("invalid Result Sinks value [configmap|stdout-only]")
+	ErrInvalidResultsFormat            = errors.New("invalid Results Format value [configmap-keys|json]")
+	ErrInvalidResultsObjectKind        = errors.New("invalid Results Object Kind value [ConfigMap|Secret]")
+	ErrIllegalResultsObjectCombination = errors.New("illegal Results Object Kind without Results Object Name")
 )
 
 type Config struct {
-	PodName                         string
-	PodUID                          string
-	NetworkAttachmentDefinitionName string
-	TrafficGenContainerDiskImage    string
-	TrafficGenTargetNodeName        string
-	TrafficGenPacketsPerSecond      string
-	TrafficGenEastMacAddress        net.HardwareAddr
-	TrafficGenWestMacAddress        net.HardwareAddr
-	VMUnderTestContainerDiskImage   string
-	VMUnderTestTargetNodeName       string
-	VMUnderTestEastMacAddress       net.HardwareAddr
-	VMUnderTestWestMacAddress       net.HardwareAddr
-	TestDuration                    time.Duration
-	PortBandwidthGbps               int
-	Verbose                         bool
+	PodName                             string
+	PodUID                              string
+	CheckupUID                          string
+	ConfigMapNamespace                  string
+	ConfigMapName                       string
+	NetworkAttachmentDefinitionName     string
+	NetworkAttachmentDefinitionNameEast string
+	NetworkAttachmentDefinitionNameWest string
+	Interfaces                          []Interface
+	TrafficGenContainerDiskImage        string
+	TrafficGenTargetNodeName            string
+	TrafficGenNodeLabelSelector         string
+	TrafficGenPacketsPerSecond          string
+	TrafficGenServiceMode               bool
+	TrafficVlanID                       int
+	IPFamily                            string
+	TrafficGenGatewayMacAddresses       []net.HardwareAddr
+	TrafficGenPortIPs                   []net.IP
+	TrafficGenPortGateways              []net.IP
+	TrafficGenFlowCount                 int
+	TrafficGenFlowStats                 bool
+	TrafficGenServerCores               int
+	TrafficGenServerIOMode              int
+	TrafficGenScapyServer               bool
+	TrafficGenSoftwareMode              bool
+	TrafficGenEastMacAddress            net.HardwareAddr
+	TrafficGenWestMacAddress            net.HardwareAddr
+	VMUnderTestContainerDiskImage       string
+	VMUnderTestTargetNodeName           string
+	VMUnderTestNodeLabelSelector        string
+	VMUnderTestEastMacAddress           net.HardwareAddr
+	VMUnderTestWestMacAddress           net.HardwareAddr
+	TestDuration                        time.Duration
+	TestIterations                      int
+	PerDirectionRuns                    bool
+	Scenarios                           []string
+	BurstMaxSize                        int
+	RateSweepSteps                      int
+	RateSweepStepDuration               time.Duration
+	WarmupDuration                      time.Duration
+	PortBandwidthGbps                   int
+	Verbose                             bool
+	ConsoleTranscript                   string
+	LogConsoleTranscripts               bool
+	SkipTeardown                        string
+	AllowConcurrentRuns                 bool
+	PriorityClassName                   string
+	RuntimeClassName                    string
+	VMIExtraLabels                      map[string]string
+	VMIExtraAnnotations                 map[string]string
+	VMITolerations                      []Toleration
+	CPUIsolationCheck                   string
+	MeasurementIsolation                string
+	CriteriaSeverities                  map[string]string
+	UseVirtualMachines                  bool
+	RequireRealtimeKernel               bool
+	NUMAPassthrough                     bool
+	MigrateVMUnderTest                  bool
+	MaxAllowedDropRateBps               float64
+	SnapshotInterval                    time.Duration
+	MatrixNodeSelector                  string
+	MatrixTestDuration                  time.Duration
+	SameNodePlacement                   bool
+	RequireDifferentNodes               bool
+	SetupRetries                        int
+	SetupAttemptTimeout                 time.Duration
+	SetupRetryBackoff                   time.Duration
+	SetupTimeout                        time.Duration
+	VMIBootTimeout                      time.Duration
+	ConsoleCommandTimeout               time.Duration
+	TeardownTimeout                     time.Duration
+	GuestCommandTransport               string
+	VMIPassword                         string
+	GuestSSHPrivateKey                  ed25519.PrivateKey
+	GuestSSHAuthorizedKey               string
+	TrexBinaryPath                      string
+	TrexTransport                       string
+	TestpmdBinaryPath                   string
+	TestpmdMbufSize                     int
+	TestpmdTotalNumMbufs                int
+	TestpmdMemoryChannels               int
+	TestpmdCPUs                         []int
+	TrexCPUs                            *TrexCPUs
+	VMUnderTestWorkload                 string
+	NICDriverMode                       string
+	ResultSinks                         string
+	ResultsFormat                       string
+	ResultsObjectName                   string
+	ResultsObjectKind                   string
+	ArtifactsDir                        string
+	ArtifactsPVCName                    string
+	ReproScript                         bool
+	ProgressInterval                    time.Duration
+	BaselineConfigMapName               string
 }
 
 func New(baseConfig kconfig.Config) (Config, error) {
@@ -119,10 +538,19 @@ func New(baseConfig kconfig.Config) (Config, error) {
 	newConfig := Config{
 		PodName:                         baseConfig.PodName,
 		PodUID:                          baseConfig.PodUID,
+		CheckupUID:                      checkupUID(baseConfig),
+		ConfigMapNamespace:              baseConfig.ConfigMapNamespace,
+		ConfigMapName:                   baseConfig.ConfigMapName,
 		NetworkAttachmentDefinitionName: baseConfig.Params[NetworkAttachmentDefinitionNameParamName],
 		TrafficGenContainerDiskImage:    baseConfig.Params[TrafficGenContainerDiskImageParamName],
 		TrafficGenTargetNodeName:        baseConfig.Params[TrafficGenTargetNodeNameParamName],
 		TrafficGenPacketsPerSecond:      TrafficGenDefaultPacketsPerSecond,
+		TrafficGenServiceMode:           TrafficGenServiceModeDefault,
+		TrafficGenFlowStats:             TrafficGenFlowStatsDefault,
+		TrafficGenServerIOMode:          TrafficGenServerIOModeDefault,
+		TrafficGenScapyServer:           TrafficGenScapyServerDefault,
+		TrafficGenSoftwareMode:          TrafficGenSoftwareModeDefault,
+		IPFamily:                        IPFamilyDefault,
 		TrafficGenEastMacAddress:        trafficGenEastMacAddress,
 		TrafficGenWestMacAddress:        trafficGenWestMacAddress,
 		VMUnderTestContainerDiskImage:   baseConfig.Params[VMUnderTestContainerDiskImageParamName],
@@ -130,14 +558,76 @@ func New(baseConfig kconfig.Config) (Config, error) {
 		VMUnderTestEastMacAddress:       vmUnderTestEastMACAddress,
 		VMUnderTestWestMacAddress:       vmUnderTestWestMacAddress,
 		TestDuration:                    TestDurationDefault,
+		TestIterations:                  TestIterationsDefault,
+		PerDirectionRuns:                PerDirectionRunsDefault,
+		Scenarios:                       []string{ScenariosDefault},
+		BurstMaxSize:                    BurstMaxSizeDefault,
+		RateSweepSteps:                  RateSweepStepsDefault,
+		RateSweepStepDuration:           RateSweepStepDurationDefault,
+		WarmupDuration:                  WarmupDurationDefault,
 		PortBandwidthGbps:               PortBandwidthGbpsDefault,
 		Verbose:                         VerboseDefault,
+		ConsoleTranscript:               ConsoleTranscriptDefault,
+		LogConsoleTranscripts:           LogConsoleTranscriptsDefault,
+		SkipTeardown:                    SkipTeardownDefault,
+		AllowConcurrentRuns:             AllowConcurrentRunsDefault,
+		CPUIsolationCheck:               CPUIsolationCheckDefault,
+		MeasurementIsolation:            MeasurementIsolationDefault,
+		UseVirtualMachines:              UseVirtualMachinesDefault,
+		ReproScript:                     ReproScriptDefault,
+		RequireRealtimeKernel:           RequireRealtimeKernelDefault,
+		NUMAPassthrough:                 NUMAPassthroughDefault,
+		MigrateVMUnderTest:              MigrateVMUnderTestDefault,
+		MatrixTestDuration:              MatrixTestDurationDefault,
+		SameNodePlacement:               SameNodePlacementDefault,
+		RequireDifferentNodes:           RequireDifferentNodesDefault,
+		SetupRetries:                    SetupRetriesDefault,
+		SetupAttemptTimeout:             SetupAttemptTimeoutDefault,
+		SetupRetryBackoff:               SetupRetryBackoffDefault,
+		SetupTimeout:                    SetupTimeoutDefault,
+		ConsoleCommandTimeout:           ConsoleCommandTimeoutDefault,
+		TeardownTimeout:                 TeardownTimeoutDefault,
+		GuestCommandTransport:           GuestCommandTransportDefault,
+		TrexBinaryPath:                  TrexBinaryPathDefault,
+		TrexTransport:                   TrexTransportDefault,
+		TestpmdBinaryPath:               TestpmdBinaryPathDefault,
+		VMUnderTestWorkload:             VMUnderTestWorkloadDefault,
+		NICDriverMode:                   NICDriverModeDefault,
+		ResultSinks:                     ResultSinksDefault,
+		ResultsFormat:                   ResultsFormatDefault,
+		ProgressInterval:                ProgressIntervalDefault,
 	}
 
-	if newConfig.NetworkAttachmentDefinitionName == "" {
+	newConfig.NetworkAttachmentDefinitionNameEast = interfaceNetworkAttachmentDefinitionName(baseConfig,
+		NetworkAttachmentDefinitionNameEastParamName)
+	newConfig.NetworkAttachmentDefinitionNameWest = interfaceNetworkAttachmentDefinitionName(baseConfig,
+		NetworkAttachmentDefinitionNameWestParamName)
+
+	if rawVal := baseConfig.Params[InterfacesParamName]; rawVal != "" {
+		interfaces, err := parseInterfaces(rawVal, newConfig)
+		if errors.Is(err, ErrInvalidNetworkAttachmentDefinitionName) {
+			return Config{}, ErrInvalidNetworkAttachmentDefinitionName
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %v", ErrInvalidInterfaces, err)
+		}
+		newConfig.Interfaces = interfaces
+		newConfig.TrafficGenEastMacAddress = interfaces[0].TrafficGenMacAddress
+		newConfig.TrafficGenWestMacAddress = interfaces[1].TrafficGenMacAddress
+		newConfig.VMUnderTestEastMacAddress = interfaces[0].VMUnderTestMacAddress
+		newConfig.VMUnderTestWestMacAddress = interfaces[1].VMUnderTestMacAddress
+	} else if newConfig.NetworkAttachmentDefinitionNameEast == "" || newConfig.NetworkAttachmentDefinitionNameWest == "" {
 		return Config{}, ErrInvalidNetworkAttachmentDefinitionName
 	}
 
+	if rawVal := baseConfig.Params[TrafficGenGatewayMacAddressesParamName]; rawVal != "" {
+		gatewayMacAddresses, err := parseGatewayMacAddresses(rawVal, len(newConfig.TestInterfaces()))
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %v", ErrInvalidTrafficGenGatewayMacAddresses, err)
+		}
+		newConfig.TrafficGenGatewayMacAddresses = gatewayMacAddresses
+	}
+
 	if newConfig.TrafficGenContainerDiskImage == "" {
 		return Config{}, ErrInvalidTrafficGenContainerDiskImage
 	}
@@ -154,6 +644,45 @@ func New(baseConfig kconfig.Config) (Config, error) {
 	return setOptionalParams(baseConfig, newConfig)
 }
 
+// OwnershipMode reports how the checkup's resources are tied to the checkup.
+// When running inside a Pod, they are owned by it and garbage collected along with it.
+// Otherwise, they are only labeled with the checkup UID and rely on an explicit teardown.
+func (c Config) OwnershipMode() string {
+	if c.PodName != "" && c.PodUID != "" {
+		return OwnershipModeOwnerReference
+	}
+	return OwnershipModeLabel
+}
+
+// NetworkAttachmentDefinitionNames returns the distinct Network-Attachment-Definitions the VMIs are connected to.
+func (c Config) NetworkAttachmentDefinitionNames() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, iface := range c.TestInterfaces() {
+		if !seen[iface.NetworkAttachmentDefinitionName] {
+			names = append(names, iface.NetworkAttachmentDefinitionName)
+			seen[iface.NetworkAttachmentDefinitionName] = true
+		}
+	}
+	return names
+}
+
+// interfaceNetworkAttachmentDefinitionName returns the Network-Attachment-Definition of a single interface,
+// which defaults to the one shared by both interfaces.
+func interfaceNetworkAttachmentDefinitionName(baseConfig kconfig.Config, paramName string) string {
+	if name := baseConfig.Params[paramName]; name != "" {
+		return name
+	}
+	return baseConfig.Params[NetworkAttachmentDefinitionNameParamName]
+}
+
+func checkupUID(baseConfig kconfig.Config) string {
+	if baseConfig.PodUID != "" {
+		return baseConfig.PodUID
+	}
+	return baseConfig.UID
+}
+
 func setOptionalParams(baseConfig kconfig.Config, newConfig Config) (Config, error) {
 	var err error
 
@@ -164,6 +693,51 @@ func setOptionalParams(baseConfig kconfig.Config, newConfig Config) (Config, err
 		}
 	}
 
+	if rawVal := baseConfig.Params[TrafficGenServiceModeParamName]; rawVal != "" {
+		newConfig.TrafficGenServiceMode, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTrafficGenServiceMode
+		}
+	}
+
+	if rawVal := baseConfig.Params[IPFamilyParamName]; rawVal != "" {
+		if rawVal != IPFamilyIPv4 && rawVal != IPFamilyIPv6 && rawVal != IPFamilyDual {
+			return Config{}, ErrInvalidIPFamily
+		}
+		newConfig.IPFamily = rawVal
+	}
+
+	// Service mode resolves the gateways using ARP, while the traffic generator ports have no IPv4 address in ipv6 IP family
+	if newConfig.TrafficGenServiceMode && newConfig.IPFamily == IPFamilyIPv6 {
+		return Config{}, ErrIllegalServiceModeIPFamilyCombination
+	}
+
+	if err := setTrafficGenPortIPs(&newConfig, baseConfig.Params); err != nil {
+		return Config{}, err
+	}
+
+	if rawVal := baseConfig.Params[TrafficGenFlowCountParamName]; rawVal != "" {
+		const maxFlowCount = 32768
+		newConfig.TrafficGenFlowCount, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil || newConfig.TrafficGenFlowCount > maxFlowCount {
+			return Config{}, ErrInvalidTrafficGenFlowCount
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrafficGenFlowStatsParamName]; rawVal != "" {
+		newConfig.TrafficGenFlowStats, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTrafficGenFlowStats
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrafficVlanIDParamName]; rawVal != "" {
+		newConfig.TrafficVlanID, err = parseVlanID(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTrafficVlanID
+		}
+	}
+
 	if rawVal := baseConfig.Params[TestDurationParamName]; rawVal != "" {
 		newConfig.TestDuration, err = time.ParseDuration(rawVal)
 		if err != nil {
@@ -171,6 +745,20 @@ func setOptionalParams(baseConfig kconfig.Config, newConfig Config) (Config, err
 		}
 	}
 
+	if rawVal := baseConfig.Params[TestIterationsParamName]; rawVal != "" {
+		newConfig.TestIterations, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTestIterations
+		}
+	}
+
+	if rawVal := baseConfig.Params[WarmupDurationParamName]; rawVal != "" {
+		newConfig.WarmupDuration, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.WarmupDuration < 0 {
+			return Config{}, ErrInvalidWarmupDuration
+		}
+	}
+
 	if rawVal := baseConfig.Params[PortBandwidthGbpsParamName]; rawVal != "" {
 		newConfig.PortBandwidthGbps, err = parseNonZeroPositiveInt(rawVal)
 		if err != nil {
@@ -185,6 +773,726 @@ func setOptionalParams(baseConfig kconfig.Config, newConfig Config) (Config, err
 		}
 	}
 
+	if rawVal := baseConfig.Params[ConsoleTranscriptParamName]; rawVal != "" {
+		if rawVal != ConsoleTranscriptOnFailure && rawVal != ConsoleTranscriptAlways {
+			return Config{}, ErrInvalidConsoleTranscript
+		}
+		newConfig.ConsoleTranscript = rawVal
+	}
+
+	if rawVal := baseConfig.Params[LogConsoleTranscriptsParamName]; rawVal != "" {
+		newConfig.LogConsoleTranscripts, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidLogConsoleTranscripts
+		}
+	}
+
+	if rawVal := baseConfig.Params[SkipTeardownParamName]; rawVal != "" {
+		if rawVal != SkipTeardownAlways && rawVal != SkipTeardownOnFailure && rawVal != SkipTeardownNever {
+			return Config{}, ErrInvalidSkipTeardown
+		}
+		newConfig.SkipTeardown = rawVal
+	}
+
+	if rawVal := baseConfig.Params[AllowConcurrentRunsParamName]; rawVal != "" {
+		newConfig.AllowConcurrentRuns, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidAllowConcurrentRuns
+		}
+	}
+
+	if rawVal := baseConfig.Params[PriorityClassNameParamName]; rawVal != "" {
+		if len(validation.IsDNS1123Subdomain(rawVal)) > 0 {
+			return Config{}, ErrInvalidPriorityClassName
+		}
+		newConfig.PriorityClassName = rawVal
+	}
+
+	if rawVal := baseConfig.Params[RuntimeClassNameParamName]; rawVal != "" {
+		if len(validation.IsDNS1123Subdomain(rawVal)) > 0 {
+			return Config{}, ErrInvalidRuntimeClassName
+		}
+		newConfig.RuntimeClassName = rawVal
+	}
+
+	if rawVal := baseConfig.Params[VMIExtraLabelsParamName]; rawVal != "" {
+		if newConfig.VMIExtraLabels, err = labels.ConvertSelectorToLabelsMap(rawVal); err != nil {
+			return Config{}, ErrInvalidVMIExtraLabels
+		}
+	}
+
+	if rawVal := baseConfig.Params[VMIExtraAnnotationsParamName]; rawVal != "" {
+		if newConfig.VMIExtraAnnotations, err = parseAnnotations(rawVal); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if rawVal := baseConfig.Params[VMITolerationsParamName]; rawVal != "" {
+		if newConfig.VMITolerations, err = parseTolerations(rawVal); err != nil {
+			return Config{}, err
+		}
+	}
+
+	// The target node tolerations are appended to the VMI tolerations.
+	if rawVal := baseConfig.Params[TargetNodeTolerationsParamName]; rawVal != "" {
+		targetNodeTolerations, parseErr := parseTolerations(rawVal)
+		if parseErr != nil {
+			return Config{}, ErrInvalidTargetNodeTolerations
+		}
+		newConfig.VMITolerations = append(newConfig.VMITolerations, targetNodeTolerations...)
+	}
+
+	if rawVal := baseConfig.Params[CPUIsolationCheckParamName]; rawVal != "" {
+		if rawVal != CPUIsolationCheckWarn && rawVal != CPUIsolationCheckFail && rawVal != CPUIsolationCheckSkip {
+			return Config{}, ErrInvalidCPUIsolationCheck
+		}
+		newConfig.CPUIsolationCheck = rawVal
+	}
+
+	if rawVal := baseConfig.Params[MeasurementIsolationParamName]; rawVal != "" {
+		if rawVal != MeasurementIsolationNone && rawVal != MeasurementIsolationStrict {
+			return Config{}, ErrInvalidMeasurementIsolation
+		}
+		newConfig.MeasurementIsolation = rawVal
+	}
+
+	if rawVal := baseConfig.Params[CriteriaSeveritiesParamName]; rawVal != "" {
+		if newConfig.CriteriaSeverities, err = parseCriteriaSeverities(rawVal); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if rawVal := baseConfig.Params[UseVirtualMachinesParamName]; rawVal != "" {
+		newConfig.UseVirtualMachines, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidUseVirtualMachines
+		}
+	}
+
+	if rawVal := baseConfig.Params[RequireRealtimeKernelParamName]; rawVal != "" {
+		newConfig.RequireRealtimeKernel, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidRequireRealtimeKernel
+		}
+	}
+
+	if rawVal := baseConfig.Params[NUMAPassthroughParamName]; rawVal != "" {
+		newConfig.NUMAPassthrough, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidNUMAPassthrough
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrafficGenNodeLabelSelectorParamName]; rawVal != "" {
+		if _, err = labels.Parse(rawVal); err != nil {
+			return Config{}, ErrInvalidTrafficGenNodeLabelSelector
+		}
+		newConfig.TrafficGenNodeLabelSelector = rawVal
+	}
+
+	if rawVal := baseConfig.Params[VMUnderTestNodeLabelSelectorParamName]; rawVal != "" {
+		if _, err = labels.Parse(rawVal); err != nil {
+			return Config{}, ErrInvalidVMUnderTestNodeLabelSelector
+		}
+		newConfig.VMUnderTestNodeLabelSelector = rawVal
+	}
+
+	// The target node names already pin the VMIs to their nodes
+	if (newConfig.TrafficGenNodeLabelSelector != "" || newConfig.VMUnderTestNodeLabelSelector != "") &&
+		newConfig.TrafficGenTargetNodeName != "" {
+		return Config{}, ErrIllegalNodeLabelSelectorCombination
+	}
+
+	if rawVal := baseConfig.Params[MigrateVMUnderTestParamName]; rawVal != "" {
+		newConfig.MigrateVMUnderTest, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidMigrateVMUnderTest
+		}
+		// The migration is driven by the stats polling, and requires the VM under test to be free to move to another node
+		if newConfig.MigrateVMUnderTest &&
+			(newConfig.MeasurementIsolation == MeasurementIsolationStrict || newConfig.VMUnderTestTargetNodeName != "") {
+			return Config{}, ErrIllegalMigrateVMUnderTestCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[MaxAllowedDropRateBpsParamName]; rawVal != "" {
+		newConfig.MaxAllowedDropRateBps, err = strconv.ParseFloat(rawVal, 64)
+		maxAllowed := newConfig.MaxAllowedDropRateBps
+		if err != nil || maxAllowed <= 0 || math.IsInf(maxAllowed, 0) || math.IsNaN(maxAllowed) {
+			return Config{}, ErrInvalidMaxAllowedDropRateBps
+		}
+		// The drop rate is sampled by the stats polling, which is skipped on strictly isolated measurements
+		if newConfig.MeasurementIsolation == MeasurementIsolationStrict {
+			return Config{}, ErrIllegalMaxAllowedDropRateCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[SnapshotIntervalParamName]; rawVal != "" {
+		newConfig.SnapshotInterval, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.SnapshotInterval < SnapshotIntervalMin {
+			return Config{}, ErrInvalidSnapshotInterval
+		}
+		// The snapshots are taken by the stats polling too
+		if newConfig.MeasurementIsolation == MeasurementIsolationStrict {
+			return Config{}, ErrIllegalSnapshotIntervalCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[MatrixNodeSelectorParamName]; rawVal != "" {
+		if _, err = labels.Parse(rawVal); err != nil {
+			return Config{}, ErrInvalidMatrixNodeSelector
+		}
+		// The matrix places the VMIs on each of the node pairs, and keeps them there for the test duration
+		if newConfig.TrafficGenTargetNodeName != "" || newConfig.VMUnderTestTargetNodeName != "" || newConfig.MigrateVMUnderTest ||
+			newConfig.TrafficGenNodeLabelSelector != "" || newConfig.VMUnderTestNodeLabelSelector != "" {
+			return Config{}, ErrIllegalMatrixNodeSelectorCombination
+		}
+		newConfig.MatrixNodeSelector = rawVal
+	}
+
+	if rawVal := baseConfig.Params[MatrixTestDurationParamName]; rawVal != "" {
+		newConfig.MatrixTestDuration, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.MatrixTestDuration <= 0 {
+			return Config{}, ErrInvalidMatrixTestDuration
+		}
+	}
+
+	if rawVal := baseConfig.Params[SameNodePlacementParamName]; rawVal != "" {
+		newConfig.SameNodePlacement, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidSameNodePlacement
+		}
+		// Both VMIs are kept on a single node for the whole run
+		if newConfig.SameNodePlacement &&
+			(newConfig.TrafficGenTargetNodeName != newConfig.VMUnderTestTargetNodeName ||
+				newConfig.TrafficGenNodeLabelSelector != newConfig.VMUnderTestNodeLabelSelector ||
+				newConfig.MigrateVMUnderTest || newConfig.MatrixNodeSelector != "") {
+			return Config{}, ErrIllegalSameNodePlacementCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[RequireDifferentNodesParamName]; rawVal != "" {
+		newConfig.RequireDifferentNodes, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidRequireDifferentNodes
+		}
+		if newConfig.RequireDifferentNodes &&
+			(newConfig.SameNodePlacement ||
+				newConfig.TrafficGenTargetNodeName != "" && newConfig.TrafficGenTargetNodeName == newConfig.VMUnderTestTargetNodeName) {
+			return Config{}, ErrIllegalDifferentNodesCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[SetupRetriesParamName]; rawVal != "" {
+		const maxSetupRetries = 10
+		newConfig.SetupRetries, err = strconv.Atoi(rawVal)
+		if err != nil || newConfig.SetupRetries < 0 || newConfig.SetupRetries > maxSetupRetries {
+			return Config{}, ErrInvalidSetupRetries
+		}
+	}
+
+	if rawVal := baseConfig.Params[SetupAttemptTimeoutParamName]; rawVal != "" {
+		newConfig.SetupAttemptTimeout, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.SetupAttemptTimeout <= 0 {
+			return Config{}, ErrInvalidSetupAttemptTimeout
+		}
+	}
+
+	if rawVal := baseConfig.Params[SetupRetryBackoffParamName]; rawVal != "" {
+		newConfig.SetupRetryBackoff, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.SetupRetryBackoff <= 0 {
+			return Config{}, ErrInvalidSetupRetryBackoff
+		}
+	}
+
+	if rawVal := baseConfig.Params[SetupTimeoutParamName]; rawVal != "" {
+		newConfig.SetupTimeout, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.SetupTimeout <= 0 {
+			return Config{}, ErrInvalidSetupTimeout
+		}
+	}
+
+	if rawVal := baseConfig.Params[VMIBootTimeoutParamName]; rawVal != "" {
+		newConfig.VMIBootTimeout, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.VMIBootTimeout <= 0 {
+			return Config{}, ErrInvalidVMIBootTimeout
+		}
+	}
+
+	if rawVal := baseConfig.Params[ConsoleCommandTimeoutParamName]; rawVal != "" {
+		newConfig.ConsoleCommandTimeout, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.ConsoleCommandTimeout <= 0 {
+			return Config{}, ErrInvalidConsoleCommandTimeout
+		}
+	}
+
+	if rawVal := baseConfig.Params[TeardownTimeoutParamName]; rawVal != "" {
+		newConfig.TeardownTimeout, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.TeardownTimeout <= 0 {
+			return Config{}, ErrInvalidTeardownTimeout
+		}
+	}
+
+	if rawVal := baseConfig.Params[GuestCommandTransportParamName]; rawVal != "" {
+		transports := []string{GuestCommandTransportConsole, GuestCommandTransportGuestAgent, GuestCommandTransportSSH}
+		if !slices.Contains(transports, rawVal) {
+			return Config{}, ErrInvalidGuestCommandTransport
+		}
+		newConfig.GuestCommandTransport = rawVal
+	}
+
+	// The password is set on the guests for this run only.
+	if newConfig.VMIPassword, err = generateVMIPassword(); err != nil {
+		return Config{}, err
+	}
+
+	if newConfig.GuestCommandTransport == GuestCommandTransportSSH {
+		// The migrated VMI is reached on another pod IP, closing the SSH shell and the programs running in it.
+		if newConfig.MigrateVMUnderTest {
+			return Config{}, ErrIllegalSSHTransportCombination
+		}
+
+		// The key is authorized on the guests for this run only.
+		newConfig.GuestSSHPrivateKey, newConfig.GuestSSHAuthorizedKey, err = generateGuestSSHKey()
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrexBinaryPathParamName]; rawVal != "" {
+		if !path.IsAbs(rawVal) || !isValidBinaryPath(rawVal) {
+			return Config{}, ErrInvalidTrexBinaryPath
+		}
+		newConfig.TrexBinaryPath = path.Clean(rawVal)
+	}
+
+	if rawVal := baseConfig.Params[TrexTransportParamName]; rawVal != "" {
+		if rawVal != TrexTransportConsole && rawVal != TrexTransportRPC {
+			return Config{}, ErrInvalidTrexTransport
+		}
+		newConfig.TrexTransport = rawVal
+	}
+
+	if rawVal := baseConfig.Params[TestpmdBinaryPathParamName]; rawVal != "" {
+		if !isValidBinaryPath(rawVal) {
+			return Config{}, ErrInvalidTestpmdBinaryPath
+		}
+		newConfig.TestpmdBinaryPath = rawVal
+	}
+
+	if rawVal := baseConfig.Params[TestpmdMbufSizeParamName]; rawVal != "" {
+		newConfig.TestpmdMbufSize, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTestpmdMbufSize
+		}
+	}
+
+	if rawVal := baseConfig.Params[TestpmdTotalNumMbufsParamName]; rawVal != "" {
+		newConfig.TestpmdTotalNumMbufs, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTestpmdTotalNumMbufs
+		}
+	}
+
+	if rawVal := baseConfig.Params[TestpmdMemoryChannelsParamName]; rawVal != "" {
+		newConfig.TestpmdMemoryChannels, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTestpmdMemoryChannels
+		}
+	}
+
+	if rawVal := baseConfig.Params[VMUnderTestWorkloadParamName]; rawVal != "" {
+		if rawVal != VMUnderTestWorkloadTestpmd && rawVal != VMUnderTestWorkloadL3fwd {
+			return Config{}, ErrInvalidVMUnderTestWorkload
+		}
+		newConfig.VMUnderTestWorkload = rawVal
+	}
+
+	if rawVal := baseConfig.Params[NICDriverModeParamName]; rawVal != "" {
+		if rawVal != NICDriverModeAuto && rawVal != NICDriverModeVFIO && rawVal != NICDriverModeMLX5 {
+			return Config{}, ErrInvalidNICDriverMode
+		}
+		newConfig.NICDriverMode = rawVal
+	}
+
+	// l3fwd neither forwards VLAN tagged packets, nor sends the packets back through the gateway
+	if newConfig.VMUnderTestWorkload == VMUnderTestWorkloadL3fwd && (newConfig.TrafficVlanID != 0 || newConfig.Routed()) {
+		return Config{}, ErrIllegalVMUnderTestWorkloadCombination
+	}
+
+	if rawVal := baseConfig.Params[PerDirectionRunsParamName]; rawVal != "" {
+		newConfig.PerDirectionRuns, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidPerDirectionRuns
+		}
+		// l3fwd routes the traffic received on the even ports only
+		if newConfig.PerDirectionRuns && newConfig.VMUnderTestWorkload == VMUnderTestWorkloadL3fwd {
+			return Config{}, ErrIllegalPerDirectionRunsCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[ScenariosParamName]; rawVal != "" {
+		newConfig.Scenarios, err = parseScenarios(rawVal)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	if rawVal := baseConfig.Params[BurstMaxSizeParamName]; rawVal != "" {
+		const maxBurstSize = 32768
+		newConfig.BurstMaxSize, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil || newConfig.BurstMaxSize < BurstMinSize || newConfig.BurstMaxSize > maxBurstSize {
+			return Config{}, ErrInvalidBurstMaxSize
+		}
+	}
+
+	if rawVal := baseConfig.Params[RateSweepStepsParamName]; rawVal != "" {
+		const maxRateSweepSteps = 100
+		newConfig.RateSweepSteps, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil || newConfig.RateSweepSteps > maxRateSweepSteps {
+			return Config{}, ErrInvalidRateSweepSteps
+		}
+	}
+
+	if rawVal := baseConfig.Params[RateSweepStepDurationParamName]; rawVal != "" {
+		newConfig.RateSweepStepDuration, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.RateSweepStepDuration <= 0 {
+			return Config{}, ErrInvalidRateSweepStepDuration
+		}
+	}
+
+	// Only testpmd MAC forwarding sends the multicast and broadcast frames back as is
+	if slices.Contains(newConfig.Scenarios, ScenarioPromiscuous) &&
+		(newConfig.VMUnderTestWorkload == VMUnderTestWorkloadL3fwd || newConfig.Routed()) {
+		return Config{}, ErrIllegalPromiscuousScenarioCombination
+	}
+
+	if rawVal := baseConfig.Params[ResultSinksParamName]; rawVal != "" {
+		if rawVal != ResultSinksConfigMap && rawVal != ResultSinksStdoutOnly {
+			return Config{}, ErrInvalidResultSinks
+		}
+		newConfig.ResultSinks = rawVal
+	}
+
+	if rawVal := baseConfig.Params[ResultsFormatParamName]; rawVal != "" {
+		if rawVal != ResultsFormatConfigMapKeys && rawVal != ResultsFormatJSON {
+			return Config{}, ErrInvalidResultsFormat
+		}
+		newConfig.ResultsFormat = rawVal
+	}
+
+	if rawVal := baseConfig.Params[ArtifactsDirParamName]; rawVal != "" {
+		if !path.IsAbs(rawVal) {
+			return Config{}, ErrInvalidArtifactsDir
+		}
+		newConfig.ArtifactsDir = path.Clean(rawVal)
+	}
+
+	if rawVal := baseConfig.Params[ArtifactsPVCNameParamName]; rawVal != "" {
+		if len(validation.IsDNS1123Subdomain(rawVal)) > 0 {
+			return Config{}, ErrInvalidArtifactsPVCName
+		}
+		if newConfig.ArtifactsDir != "" {
+			return Config{}, ErrIllegalArtifactsCombination
+		}
+		newConfig.ArtifactsPVCName = rawVal
+	}
+
+	if rawVal := baseConfig.Params[ReproScriptParamName]; rawVal != "" {
+		newConfig.ReproScript, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidReproScript
+		}
+		if newConfig.ReproScript && newConfig.ArtifactsDir == "" && newConfig.ArtifactsPVCName == "" {
+			return Config{}, ErrIllegalReproScriptCombination
+		}
+	}
+
+	if rawVal := baseConfig.Params[ProgressIntervalParamName]; rawVal != "" {
+		newConfig.ProgressInterval, err = time.ParseDuration(rawVal)
+		if err != nil || newConfig.ProgressInterval < 0 {
+			return Config{}, ErrInvalidProgressInterval
+		}
+	}
+
+	if rawVal := baseConfig.Params[BaselineConfigMapNameParamName]; rawVal != "" {
+		if len(validation.IsDNS1123Subdomain(rawVal)) > 0 {
+			return Config{}, ErrInvalidBaselineConfigMapName
+		}
+		newConfig.BaselineConfigMapName = rawVal
+	}
+
+	if newConfig, err = setCPUParams(baseConfig, newConfig); err != nil {
+		return Config{}, err
+	}
+
+	if newConfig, err = setTrafficGenServerParams(baseConfig, newConfig); err != nil {
+		return Config{}, err
+	}
+
+	return setResultsObjectParams(baseConfig, newConfig)
+}
+
+// CriterionSeverity returns the severity of the given built-in criterion, which is a blocker unless set otherwise.
+func (c Config) CriterionSeverity(criterion string) string {
+	if severity, exists := c.CriteriaSeverities[criterion]; exists {
+		return severity
+	}
+	return SeverityBlocker
+}
+
+// parseCriteriaSeverities parses "<criterion>:<severity>" pairs, separated by commas, e.g. "drops:minor,loss:major".
+func parseCriteriaSeverities(rawVal string) (map[string]string, error) {
+	severities := map[string]string{}
+	for _, pair := range strings.Split(rawVal, ",") {
+		criterion, severity, found := strings.Cut(pair, ":")
+		if !found || !slices.Contains([]string{CriterionErrors, CriterionDrops, CriterionLoss, CriterionDropRate}, criterion) ||
+			!slices.Contains([]string{SeverityBlocker, SeverityMajor, SeverityMinor}, severity) {
+			return nil, ErrInvalidCriteriaSeverities
+		}
+		if _, exists := severities[criterion]; exists {
+			return nil, ErrInvalidCriteriaSeverities
+		}
+		severities[criterion] = severity
+	}
+	return severities, nil
+}
+
+// parseAnnotations parses "<key>=<value>" pairs, separated by commas, e.g. "example.com/cost-center=network".
+func parseAnnotations(rawVal string) (map[string]string, error) {
+	annotations := map[string]string{}
+	for _, pair := range strings.Split(rawVal, ",") {
+		key, val, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || len(validation.IsQualifiedName(key)) > 0 {
+			return nil, ErrInvalidVMIExtraAnnotations
+		}
+		if _, exists := annotations[key]; exists {
+			return nil, ErrInvalidVMIExtraAnnotations
+		}
+		annotations[key] = val
+	}
+	return annotations, nil
+}
+
+// Toleration is a toleration of the checkup VMIs, of the taints with the given key, value and effect.
+// A toleration without a value tolerates any value of the key, and one without an effect tolerates all effects.
+type Toleration struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+const (
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+)
+
+// parseTolerations parses "<key>[=<value>][:<effect>]" items, separated by commas, following the kubectl taint syntax,
+// e.g. "dedicated=dpdk:NoSchedule,example.com/maintenance".
+func parseTolerations(rawVal string) ([]Toleration, error) {
+	var tolerations []Toleration
+	for _, item := range strings.Split(rawVal, ",") {
+		var toleration Toleration
+		keyVal, effect, _ := strings.Cut(strings.TrimSpace(item), ":")
+		toleration.Key, toleration.Value, _ = strings.Cut(keyVal, "=")
+		toleration.Effect = effect
+		if len(validation.IsQualifiedName(toleration.Key)) > 0 ||
+			(toleration.Value != "" && len(validation.IsValidLabelValue(toleration.Value)) > 0) ||
+			!slices.Contains([]string{"", TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute}, effect) {
+			return nil, ErrInvalidVMITolerations
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
+
+// parseScenarios parses the comma separated names of the scenarios, each listed once at most, keeping their order.
+func parseScenarios(rawVal string) ([]string, error) {
+	var scenarios []string
+	for _, scenario := range strings.Split(rawVal, ",") {
+		scenario = strings.TrimSpace(scenario)
+		if !slices.Contains([]string{ScenarioThroughput, ScenarioPromiscuous, ScenarioBurst, ScenarioRateSweep}, scenario) ||
+			slices.Contains(scenarios, scenario) {
+			return nil, ErrInvalidScenarios
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+// TrexCPUs is the assignment of the traffic generator vCPUs to the TRex threads.
+type TrexCPUs struct {
+	Master  int
+	Latency int
+	Traffic []int
+}
+
+// DefaultTrexCPUs returns the TRex CPU assignment used unless set explicitly.
+func DefaultTrexCPUs() TrexCPUs {
+	return TrexCPUs{Master: 2, Latency: 3, Traffic: []int{4, 5, 6, 7}}
+}
+
+// All returns all the CPUs TRex runs on.
+func (t TrexCPUs) All() []int {
+	return append([]int{t.Master, t.Latency}, t.Traffic...)
+}
+
+// TrexCPUAssignment returns the explicit TRex CPU assignment, or the default one.
+func (c Config) TrexCPUAssignment() TrexCPUs {
+	if c.TrexCPUs != nil {
+		return *c.TrexCPUs
+	}
+	return DefaultTrexCPUs()
+}
+
+// setCPUParams applies the explicit CPU assignments of the guests DPDK applications.
+// They are validated against the VMIs vCPU topology by the checkup setup.
+func setCPUParams(baseConfig kconfig.Config, newConfig Config) (Config, error) {
+	var err error
+	if rawVal := baseConfig.Params[TestpmdCPUsParamName]; rawVal != "" {
+		const minTestpmdCPUs = 2
+		newConfig.TestpmdCPUs, err = parseCPUList(rawVal)
+		if err != nil || len(newConfig.TestpmdCPUs) < minTestpmdCPUs {
+			return Config{}, ErrInvalidTestpmdCPUs
+		}
+	}
+
+	trexCPUs := DefaultTrexCPUs()
+	explicitTrexCPUs := false
+	if rawVal := baseConfig.Params[TrexMasterCPUParamName]; rawVal != "" {
+		explicitTrexCPUs = true
+		if trexCPUs.Master, err = parseCPU(rawVal); err != nil {
+			return Config{}, ErrInvalidTrexMasterCPU
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrexLatencyCPUParamName]; rawVal != "" {
+		explicitTrexCPUs = true
+		if trexCPUs.Latency, err = parseCPU(rawVal); err != nil {
+			return Config{}, ErrInvalidTrexLatencyCPU
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrexTrafficCPUsParamName]; rawVal != "" {
+		explicitTrexCPUs = true
+		if trexCPUs.Traffic, err = parseCPUList(rawVal); err != nil {
+			return Config{}, ErrInvalidTrexTrafficCPUs
+		}
+	}
+
+	if explicitTrexCPUs {
+		if hasDuplicateCPUs(trexCPUs.All()) {
+			return Config{}, ErrIllegalTrexCPUsCombination
+		}
+		newConfig.TrexCPUs = &trexCPUs
+	}
+
+	return newConfig, nil
+}
+
+// setTrafficGenServerParams applies the TRex server startup parameters.
+// The server cores are validated against the traffic CPUs, so it should follow setCPUParams.
+func setTrafficGenServerParams(baseConfig kconfig.Config, newConfig Config) (Config, error) {
+	var err error
+	if rawVal := baseConfig.Params[TrafficGenServerCoresParamName]; rawVal != "" {
+		portsPairsCount := len(newConfig.TestInterfaces()) / 2
+		maxCores := len(newConfig.TrexCPUAssignment().Traffic) / portsPairsCount
+		newConfig.TrafficGenServerCores, err = parseNonZeroPositiveInt(rawVal)
+		if err != nil || newConfig.TrafficGenServerCores > maxCores {
+			return Config{}, ErrInvalidTrafficGenServerCores
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrafficGenServerIOModeParamName]; rawVal != "" {
+		const maxIOMode = 2
+		newConfig.TrafficGenServerIOMode, err = strconv.Atoi(rawVal)
+		if err != nil || newConfig.TrafficGenServerIOMode < 0 || newConfig.TrafficGenServerIOMode > maxIOMode {
+			return Config{}, ErrInvalidTrafficGenServerIOMode
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrafficGenScapyServerParamName]; rawVal != "" {
+		newConfig.TrafficGenScapyServer, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTrafficGenScapyServer
+		}
+	}
+
+	if rawVal := baseConfig.Params[TrafficGenSoftwareModeParamName]; rawVal != "" {
+		newConfig.TrafficGenSoftwareMode, err = strconv.ParseBool(rawVal)
+		if err != nil {
+			return Config{}, ErrInvalidTrafficGenSoftwareMode
+		}
+	}
+
+	return newConfig, nil
+}
+
+func parseCPU(rawVal string) (int, error) {
+	cpu, err := strconv.Atoi(rawVal)
+	if err != nil || cpu < 0 {
+		return 0, errors.New("CPU is not a non-negative integer")
+	}
+	return cpu, nil
+}
+
+// parseCPUList parses a list of distinct CPUs, in the cpuset list format, e.g. "2,4-7".
+func parseCPUList(rawVal string) ([]int, error) {
+	var cpus []int
+	for _, cpuRange := range strings.Split(rawVal, ",") {
+		first, last, isRange := strings.Cut(cpuRange, "-")
+		firstCPU, err := parseCPU(first)
+		if err != nil {
+			return nil, err
+		}
+		lastCPU := firstCPU
+		if isRange {
+			if lastCPU, err = parseCPU(last); err != nil || lastCPU < firstCPU {
+				return nil, fmt.Errorf("invalid CPU range %q", cpuRange)
+			}
+		}
+		for cpu := firstCPU; cpu <= lastCPU; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	if hasDuplicateCPUs(cpus) {
+		return nil, errors.New("CPUs are listed more than once")
+	}
+	return cpus, nil
+}
+
+func hasDuplicateCPUs(cpus []int) bool {
+	seen := map[int]bool{}
+	for _, cpu := range cpus {
+		if seen[cpu] {
+			return true
+		}
+		seen[cpu] = true
+	}
+	return false
+}
+
+func setResultsObjectParams(baseConfig kconfig.Config, newConfig Config) (Config, error) {
+	newConfig.ResultsObjectName = baseConfig.Params[ResultsObjectNameParamName]
+	rawKind := baseConfig.Params[ResultsObjectKindParamName]
+
+	if newConfig.ResultsObjectName == "" {
+		if rawKind != "" {
+			return Config{}, ErrIllegalResultsObjectCombination
+		}
+		return newConfig, nil
+	}
+
+	newConfig.ResultsObjectKind = ResultsObjectKindDefault
+	if rawKind != "" {
+		if rawKind != ResultsObjectKindConfigMap && rawKind != ResultsObjectKindSecret {
+			return Config{}, ErrInvalidResultsObjectKind
+		}
+		newConfig.ResultsObjectKind = rawKind
+	}
+
 	return newConfig, nil
 }
 
@@ -196,6 +1504,12 @@ func parseTrafficGenPacketsPerSecond(rawVal string) (string, error) {
 	return rawVal, nil
 }
 
+// isValidBinaryPath reports whether the path can be safely embedded in the guest's shell commands and scripts.
+func isValidBinaryPath(rawVal string) bool {
+	validFormat := regexp.MustCompile(`^[-_./a-zA-Z0-9]+$`)
+	return validFormat.MatchString(rawVal) && !strings.HasSuffix(rawVal, "/")
+}
+
 func parseNonZeroPositiveInt(rawVal string) (int, error) {
 	val, err := strconv.Atoi(rawVal)
 	if err != nil || val <= 0 {
@@ -204,6 +1518,45 @@ func parseNonZeroPositiveInt(rawVal string) (int, error) {
 	return val, nil
 }
 
+func parseVlanID(rawVal string) (int, error) {
+	const maxVlanID = 4094
+	val, err := parseNonZeroPositiveInt(rawVal)
+	if err != nil {
+		return 0, err
+	}
+	if val > maxVlanID {
+		return 0, fmt.Errorf("VLAN ID is greater than %d", maxVlanID)
+	}
+	return val, nil
+}
+
+// generateVMIPassword returns a new random password, made of hex digits so it is typed as is on the guests consoles.
+func generateVMIPassword() (string, error) {
+	const passwordBytes = 16
+
+	password := make([]byte, passwordBytes)
+	if _, err := rand.Read(password); err != nil {
+		return "", fmt.Errorf("failed to generate the guests password: %w", err)
+	}
+
+	return hex.EncodeToString(password), nil
+}
+
+// generateGuestSSHKey returns a new private key, with its public key in the authorized_keys format.
+func generateGuestSSHKey() (ed25519.PrivateKey, string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate the guests SSH key: %w", err)
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate the guests SSH key: %w", err)
+	}
+
+	return privateKey, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey))), nil
+}
+
 func generateMacAddressWithPresetPrefixAndSuffix(prefixOctet, suffixOctet byte) net.HardwareAddr {
 	const (
 		MACOctetsCount = 6
@@ -20,8 +20,11 @@
 package config_test
 
 import (
+	"crypto/ed25519"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -42,7 +45,49 @@ const (
 	testVMUnderTestContainerDiskImage = "quay.io/ramlavi/kubevirt-dpdk-checkup-vm:main"
 	testVMUnderTestTargetNodeName     = "worker-dpdk2"
 	testDuration                      = "30m"
+	testTestIterations                = 3
+	testBurstMaxSize                  = 1024
+	testRateSweepSteps                = 5
+	testRateSweepStepDuration         = "1m"
+	testWarmupDuration                = "30s"
+	testMatrixTestDuration            = "2m"
+	testSetupRetries                  = 2
+	testSetupAttemptTimeout           = "3m"
+	testSetupRetryBackoff             = "20s"
+	testSetupTimeout                  = "30m"
+	testVMIBootTimeout                = "10m"
+	testConsoleCommandTimeout         = "1m"
+	testTeardownTimeout               = "10m"
 	testPortBandwidthGbps             = 100
+	testTrafficVlanID                 = 100
+	testTrafficGenFlowCount           = 256
+	testTrafficGenServerCores         = 2
+	testTestpmdMbufSize               = 9216
+	testTestpmdTotalNumMbufs          = 262144
+	testTestpmdMemoryChannels         = 4
+	testTestpmdCPUs                   = "2-6"
+	testTrexMasterCPU                 = 0
+	testTrexLatencyCPU                = 1
+	testTrexTrafficCPUs               = "2,3,6,7"
+	testArtifactsDir                  = "/artifacts"
+	testProgressInterval              = "1m"
+	testBaselineConfigMapName         = "dpdk-checkup-baseline"
+	testResultsObjectName             = "dpdk-checkup-results"
+	testTrexBinaryPath                = "/usr/local/trex/t-rex-64"
+	testTestpmdBinaryPath             = "/usr/local/bin/dpdk-testpmd"
+	testPriorityClassName             = "dpdk-checkup-critical"
+	testRuntimeClassName              = "performance-dpdk"
+)
+
+var testCriteriaSeverities = map[string]string{config.CriterionDrops: config.SeverityMinor, config.CriterionLoss: config.SeverityMajor}
+
+var (
+	testVMIExtraLabels      = map[string]string{"example.com/cost-center": "network", "team": "nfv"}
+	testVMIExtraAnnotations = map[string]string{"example.com/owner": "NFV team"}
+	testVMITolerations      = []config.Toleration{
+		{Key: "dedicated", Value: "dpdk", Effect: config.TaintEffectNoSchedule},
+		{Key: "example.com/maintenance"},
+	}
 )
 
 func TestNewShouldApplyDefaultsWhenOptionalFieldsAreMissing(t *testing.T) {
@@ -65,23 +110,389 @@ func TestNewShouldApplyDefaultsWhenOptionalFieldsAreMissing(t *testing.T) {
 	assert.NotNil(t, actualConfig.VMUnderTestWestMacAddress)
 
 	expectedConfig := config.Config{
-		PodName:                         testPodName,
-		PodUID:                          testPodUID,
-		NetworkAttachmentDefinitionName: networkAttachmentDefinitionName,
-		TrafficGenContainerDiskImage:    testTrafficGenContainerDiskImage,
-		TrafficGenPacketsPerSecond:      config.TrafficGenDefaultPacketsPerSecond,
-		TrafficGenEastMacAddress:        actualConfig.TrafficGenEastMacAddress,
-		TrafficGenWestMacAddress:        actualConfig.TrafficGenWestMacAddress,
-		VMUnderTestContainerDiskImage:   testVMUnderTestContainerDiskImage,
-		VMUnderTestEastMacAddress:       actualConfig.VMUnderTestEastMacAddress,
-		VMUnderTestWestMacAddress:       actualConfig.VMUnderTestWestMacAddress,
-		TestDuration:                    config.TestDurationDefault,
-		PortBandwidthGbps:               config.PortBandwidthGbpsDefault,
-		Verbose:                         config.VerboseDefault,
+		PodName:                             testPodName,
+		PodUID:                              testPodUID,
+		CheckupUID:                          testPodUID,
+		NetworkAttachmentDefinitionName:     networkAttachmentDefinitionName,
+		NetworkAttachmentDefinitionNameEast: networkAttachmentDefinitionName,
+		NetworkAttachmentDefinitionNameWest: networkAttachmentDefinitionName,
+		TrafficGenContainerDiskImage:        testTrafficGenContainerDiskImage,
+		TrafficGenPacketsPerSecond:          config.TrafficGenDefaultPacketsPerSecond,
+		TrafficGenServiceMode:               config.TrafficGenServiceModeDefault,
+		TrafficGenFlowStats:                 config.TrafficGenFlowStatsDefault,
+		TrafficGenServerIOMode:              config.TrafficGenServerIOModeDefault,
+		TrafficGenScapyServer:               config.TrafficGenScapyServerDefault,
+		TrafficGenSoftwareMode:              config.TrafficGenSoftwareModeDefault,
+		IPFamily:                            config.IPFamilyDefault,
+		TrafficGenEastMacAddress:            actualConfig.TrafficGenEastMacAddress,
+		TrafficGenWestMacAddress:            actualConfig.TrafficGenWestMacAddress,
+		VMUnderTestContainerDiskImage:       testVMUnderTestContainerDiskImage,
+		VMUnderTestEastMacAddress:           actualConfig.VMUnderTestEastMacAddress,
+		VMUnderTestWestMacAddress:           actualConfig.VMUnderTestWestMacAddress,
+		VMIPassword:                         actualConfig.VMIPassword,
+		TestDuration:                        config.TestDurationDefault,
+		TestIterations:                      config.TestIterationsDefault,
+		PerDirectionRuns:                    config.PerDirectionRunsDefault,
+		Scenarios:                           []string{config.ScenariosDefault},
+		BurstMaxSize:                        config.BurstMaxSizeDefault,
+		RateSweepSteps:                      config.RateSweepStepsDefault,
+		RateSweepStepDuration:               config.RateSweepStepDurationDefault,
+		WarmupDuration:                      config.WarmupDurationDefault,
+		PortBandwidthGbps:                   config.PortBandwidthGbpsDefault,
+		Verbose:                             config.VerboseDefault,
+		ConsoleTranscript:                   config.ConsoleTranscriptDefault,
+		LogConsoleTranscripts:               config.LogConsoleTranscriptsDefault,
+		AllowConcurrentRuns:                 config.AllowConcurrentRunsDefault,
+		SkipTeardown:                        config.SkipTeardownDefault,
+		CPUIsolationCheck:                   config.CPUIsolationCheckDefault,
+		MeasurementIsolation:                config.MeasurementIsolationDefault,
+		UseVirtualMachines:                  config.UseVirtualMachinesDefault,
+		ReproScript:                         config.ReproScriptDefault,
+		RequireRealtimeKernel:               config.RequireRealtimeKernelDefault,
+		NUMAPassthrough:                     config.NUMAPassthroughDefault,
+		MigrateVMUnderTest:                  config.MigrateVMUnderTestDefault,
+		SameNodePlacement:                   config.SameNodePlacementDefault,
+		SetupRetries:                        config.SetupRetriesDefault,
+		SetupAttemptTimeout:                 config.SetupAttemptTimeoutDefault,
+		SetupRetryBackoff:                   config.SetupRetryBackoffDefault,
+		SetupTimeout:                        config.SetupTimeoutDefault,
+		ConsoleCommandTimeout:               config.ConsoleCommandTimeoutDefault,
+		TeardownTimeout:                     config.TeardownTimeoutDefault,
+		GuestCommandTransport:               config.GuestCommandTransportDefault,
+		MatrixTestDuration:                  config.MatrixTestDurationDefault,
+		TrexBinaryPath:                      config.TrexBinaryPathDefault,
+		TrexTransport:                       config.TrexTransportDefault,
+		TestpmdBinaryPath:                   config.TestpmdBinaryPathDefault,
+		VMUnderTestWorkload:                 config.VMUnderTestWorkloadDefault,
+		NICDriverMode:                       config.NICDriverModeDefault,
+		ResultSinks:                         config.ResultSinksDefault,
+		ResultsFormat:                       config.ResultsFormatDefault,
+		ProgressInterval:                    config.ProgressIntervalDefault,
 	}
 	assert.Equal(t, expectedConfig, actualConfig)
 }
 
+func TestNewShouldUseConfigMapUIDWhenPodUIDIsMissing(t *testing.T) {
+	const testConfigMapUID = "9876543210-9876543210"
+
+	baseConfig := kconfig.Config{
+		PodName: testPodName,
+		UID:     testConfigMapUID,
+		Params:  getValidUserParameters(),
+	}
+
+	actualConfig, err := config.New(baseConfig)
+	assert.NoError(t, err)
+
+	assert.Empty(t, actualConfig.PodUID)
+	assert.Equal(t, testConfigMapUID, actualConfig.CheckupUID)
+	assert.Equal(t, config.OwnershipModeLabel, actualConfig.OwnershipMode())
+}
+
+func TestNewShouldDefaultResultsObjectKindWhenOnlyNameIsSet(t *testing.T) {
+	params := getValidUserParameters()
+	delete(params, config.ResultsObjectKindParamName)
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+
+	assert.Equal(t, testResultsObjectName, actualConfig.ResultsObjectName)
+	assert.Equal(t, config.ResultsObjectKindDefault, actualConfig.ResultsObjectKind)
+}
+
+func TestNewShouldApplyArtifactsPVCName(t *testing.T) {
+	const testArtifactsPVCName = "dpdk-checkup-artifacts"
+
+	params := getValidUserParameters()
+	delete(params, config.ArtifactsDirParamName)
+	params[config.ArtifactsPVCNameParamName] = testArtifactsPVCName
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+
+	assert.Equal(t, testArtifactsPVCName, actualConfig.ArtifactsPVCName)
+	assert.Empty(t, actualConfig.ArtifactsDir)
+}
+
+func TestNewShouldFailWhenPerDirectionRunsIsSetWithL3fwd(t *testing.T) {
+	params := getValidUserParameters()
+	delete(params, config.TrafficVlanIDParamName)
+	params[config.VMUnderTestWorkloadParamName] = config.VMUnderTestWorkloadL3fwd
+
+	_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.ErrorIs(t, err, config.ErrIllegalPerDirectionRunsCombination)
+}
+
+func TestNewShouldParseTheScenariosInOrder(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.ScenariosParamName] = "promiscuous, throughput"
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{config.ScenarioPromiscuous, config.ScenarioThroughput}, actualConfig.Scenarios)
+}
+
+func TestNewShouldFailWhenPromiscuousScenarioIsSetWith(t *testing.T) {
+	t.Run("l3fwd", func(t *testing.T) {
+		params := getValidUserParameters()
+		delete(params, config.TrafficVlanIDParamName)
+		delete(params, config.PerDirectionRunsParamName)
+		params[config.VMUnderTestWorkloadParamName] = config.VMUnderTestWorkloadL3fwd
+		params[config.ScenariosParamName] = "throughput,promiscuous"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalPromiscuousScenarioCombination)
+	})
+
+	t.Run("routed traffic", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.TrafficGenGatewayMacAddressesParamName] = "02:00:00:00:00:01"
+		params[config.ScenariosParamName] = "throughput,promiscuous"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalPromiscuousScenarioCombination)
+	})
+}
+
+func TestNewShouldFailWhenMigrateVMUnderTestIsSetWith(t *testing.T) {
+	t.Run("strict measurement isolation", func(t *testing.T) {
+		params := getValidUserParameters()
+		delete(params, config.TrafficGenTargetNodeNameParamName)
+		delete(params, config.VMUnderTestTargetNodeNameParamName)
+		params[config.MigrateVMUnderTestParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalMigrateVMUnderTestCombination)
+	})
+
+	t.Run("target nodes", func(t *testing.T) {
+		params := getValidUserParameters()
+		delete(params, config.MeasurementIsolationParamName)
+		params[config.MigrateVMUnderTestParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalMigrateVMUnderTestCombination)
+	})
+}
+
+func TestNewShouldApplyMatrixNodeSelector(t *testing.T) {
+	const matrixNodeSelector = "node-role.kubernetes.io/worker-dpdk="
+
+	params := getValidUserParameters()
+	delete(params, config.TrafficGenTargetNodeNameParamName)
+	delete(params, config.VMUnderTestTargetNodeNameParamName)
+	params[config.MatrixNodeSelectorParamName] = matrixNodeSelector
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	assert.Equal(t, matrixNodeSelector, actualConfig.MatrixNodeSelector)
+}
+
+func TestNewShouldFailWhenMatrixNodeSelectorIsSetWithTargetNodes(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.MatrixNodeSelectorParamName] = "node-role.kubernetes.io/worker-dpdk="
+
+	_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.ErrorIs(t, err, config.ErrIllegalMatrixNodeSelectorCombination)
+}
+
+func TestNewShouldApplyNodeLabelSelectors(t *testing.T) {
+	const (
+		trafficGenNodeLabelSelector  = "node-role.kubernetes.io/worker-dpdk"
+		vmUnderTestNodeLabelSelector = "node-role.kubernetes.io/worker-dpdk,zone in (a,b)"
+	)
+
+	params := getValidUserParametersWithOutNodeSelectors()
+	params[config.TrafficGenNodeLabelSelectorParamName] = trafficGenNodeLabelSelector
+	params[config.VMUnderTestNodeLabelSelectorParamName] = vmUnderTestNodeLabelSelector
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	assert.Equal(t, trafficGenNodeLabelSelector, actualConfig.TrafficGenNodeLabelSelector)
+	assert.Equal(t, vmUnderTestNodeLabelSelector, actualConfig.VMUnderTestNodeLabelSelector)
+}
+
+func TestNewShouldFailWhenNodeLabelSelectorIsSetWith(t *testing.T) {
+	t.Run("target nodes", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.VMUnderTestNodeLabelSelectorParamName] = "node-role.kubernetes.io/worker-dpdk"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalNodeLabelSelectorCombination)
+	})
+
+	t.Run("matrix node selector", func(t *testing.T) {
+		params := getValidUserParametersWithOutNodeSelectors()
+		params[config.TrafficGenNodeLabelSelectorParamName] = "node-role.kubernetes.io/worker-dpdk"
+		params[config.MatrixNodeSelectorParamName] = "node-role.kubernetes.io/worker-dpdk="
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalMatrixNodeSelectorCombination)
+	})
+
+	t.Run("same node placement and different selectors", func(t *testing.T) {
+		params := getValidUserParametersWithOutNodeSelectors()
+		params[config.TrafficGenNodeLabelSelectorParamName] = "node-role.kubernetes.io/worker-dpdk"
+		params[config.SameNodePlacementParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalSameNodePlacementCombination)
+	})
+}
+
+func TestNewShouldApplySameNodePlacement(t *testing.T) {
+	t.Run("without target nodes", func(t *testing.T) {
+		params := getValidUserParametersWithOutNodeSelectors()
+		params[config.SameNodePlacementParamName] = "true"
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+		assert.True(t, actualConfig.SameNodePlacement)
+	})
+
+	t.Run("with the same target node", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.VMUnderTestTargetNodeNameParamName] = params[config.TrafficGenTargetNodeNameParamName]
+		params[config.SameNodePlacementParamName] = "true"
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+		assert.True(t, actualConfig.SameNodePlacement)
+	})
+}
+
+func TestNewShouldFailWhenSameNodePlacementIsSetWith(t *testing.T) {
+	t.Run("different target nodes", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.SameNodePlacementParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalSameNodePlacementCombination)
+	})
+
+	t.Run("matrix node selector", func(t *testing.T) {
+		params := getValidUserParametersWithOutNodeSelectors()
+		params[config.MatrixNodeSelectorParamName] = "node-role.kubernetes.io/worker-dpdk="
+		params[config.SameNodePlacementParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalSameNodePlacementCombination)
+	})
+}
+
+func TestNewShouldApplyRequireDifferentNodes(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.RequireDifferentNodesParamName] = "true"
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	assert.True(t, actualConfig.RequireDifferentNodes)
+}
+
+func TestNewShouldFailWhenRequireDifferentNodesIsSetWith(t *testing.T) {
+	t.Run("same node placement", func(t *testing.T) {
+		params := getValidUserParametersWithOutNodeSelectors()
+		params[config.SameNodePlacementParamName] = "true"
+		params[config.RequireDifferentNodesParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalDifferentNodesCombination)
+	})
+
+	t.Run("the same target node", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.VMUnderTestTargetNodeNameParamName] = params[config.TrafficGenTargetNodeNameParamName]
+		params[config.RequireDifferentNodesParamName] = "true"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalDifferentNodesCombination)
+	})
+}
+
+func TestNewShouldApplyMaxAllowedDropRateBps(t *testing.T) {
+	params := getValidUserParameters()
+	delete(params, config.MeasurementIsolationParamName)
+	params[config.MaxAllowedDropRateBpsParamName] = "1e6"
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	assert.Equal(t, 1e6, actualConfig.MaxAllowedDropRateBps)
+}
+
+func TestNewShouldApplySnapshotInterval(t *testing.T) {
+	params := getValidUserParameters()
+	delete(params, config.MeasurementIsolationParamName)
+	params[config.SnapshotIntervalParamName] = "5m"
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, actualConfig.SnapshotInterval)
+}
+
+func TestNewShouldGenerateUniqueVMIPasswords(t *testing.T) {
+	params := getValidUserParameters()
+
+	firstConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	secondConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+
+	assert.Len(t, firstConfig.VMIPassword, 32)
+	assert.NotEqual(t, firstConfig.VMIPassword, secondConfig.VMIPassword)
+}
+
+func TestNewShouldGenerateGuestSSHKeyWithSSHTransport(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.GuestCommandTransportParamName] = config.GuestCommandTransportSSH
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+	assert.Equal(t, config.GuestCommandTransportSSH, actualConfig.GuestCommandTransport)
+	assert.Len(t, actualConfig.GuestSSHPrivateKey, ed25519.PrivateKeySize)
+	assert.True(t, strings.HasPrefix(actualConfig.GuestSSHAuthorizedKey, "ssh-ed25519 "))
+}
+
+func TestNewShouldFailWhenSSHTransportIsSetWithMigrateVMUnderTest(t *testing.T) {
+	params := getValidUserParameters()
+	delete(params, config.MeasurementIsolationParamName)
+	delete(params, config.TrafficGenTargetNodeNameParamName)
+	delete(params, config.VMUnderTestTargetNodeNameParamName)
+	params[config.MigrateVMUnderTestParamName] = "true"
+	params[config.GuestCommandTransportParamName] = config.GuestCommandTransportSSH
+
+	_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.ErrorIs(t, err, config.ErrIllegalSSHTransportCombination)
+}
+
+func TestNewShouldMergeTheTargetNodeTolerationsIntoTheVMITolerations(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.TargetNodeTolerationsParamName] = "node-role.kubernetes.io/worker-dpdk:NoExecute"
+
+	testConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+
+	expectedTolerations := slices.Concat(testVMITolerations,
+		[]config.Toleration{{Key: "node-role.kubernetes.io/worker-dpdk", Effect: config.TaintEffectNoExecute}})
+	assert.Equal(t, expectedTolerations, testConfig.VMITolerations)
+}
+
+func TestNewShouldFailWhenMaxAllowedDropRateBpsIsSetWithStrictMeasurementIsolation(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.MaxAllowedDropRateBpsParamName] = "1000"
+
+	_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.ErrorIs(t, err, config.ErrIllegalMaxAllowedDropRateCombination)
+}
+
+func TestNewShouldFailWhenSnapshotIntervalIsSetWithStrictMeasurementIsolation(t *testing.T) {
+	params := getValidUserParameters()
+	params[config.SnapshotIntervalParamName] = "1m"
+
+	_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.ErrorIs(t, err, config.ErrIllegalSnapshotIntervalCombination)
+}
+
 type SuccessTestCase struct {
 	description    string
 	params         map[string]string
@@ -94,32 +505,162 @@ func TestNewShouldApplyUserConfigWhen(t *testing.T) {
 			"config is valid and both Node Selectors are set",
 			getValidUserParametersWithNodeSelectors(),
 			config.Config{
-				PodName:                         testPodName,
-				PodUID:                          testPodUID,
-				NetworkAttachmentDefinitionName: networkAttachmentDefinitionName,
-				TrafficGenContainerDiskImage:    testTrafficGenContainerDiskImage,
-				TrafficGenTargetNodeName:        testTrafficGenTargetNodeName,
-				TrafficGenPacketsPerSecond:      testTrafficGenPacketsPerSecond,
-				VMUnderTestContainerDiskImage:   testVMUnderTestContainerDiskImage,
-				VMUnderTestTargetNodeName:       testVMUnderTestTargetNodeName,
-				TestDuration:                    30 * time.Minute,
-				PortBandwidthGbps:               testPortBandwidthGbps,
-				Verbose:                         true,
+				PodName:                             testPodName,
+				PodUID:                              testPodUID,
+				CheckupUID:                          testPodUID,
+				NetworkAttachmentDefinitionName:     networkAttachmentDefinitionName,
+				NetworkAttachmentDefinitionNameEast: networkAttachmentDefinitionName,
+				NetworkAttachmentDefinitionNameWest: networkAttachmentDefinitionName,
+				TrafficGenContainerDiskImage:        testTrafficGenContainerDiskImage,
+				TrafficGenTargetNodeName:            testTrafficGenTargetNodeName,
+				TrafficGenPacketsPerSecond:          testTrafficGenPacketsPerSecond,
+				TrafficGenServiceMode:               true,
+				TrafficVlanID:                       testTrafficVlanID,
+				IPFamily:                            config.IPFamilyDual,
+				TrafficGenFlowCount:                 testTrafficGenFlowCount,
+				TrafficGenFlowStats:                 true,
+				TrafficGenServerCores:               testTrafficGenServerCores,
+				TrafficGenServerIOMode:              1,
+				TrafficGenScapyServer:               true,
+				TrafficGenSoftwareMode:              true,
+				VMUnderTestContainerDiskImage:       testVMUnderTestContainerDiskImage,
+				VMUnderTestTargetNodeName:           testVMUnderTestTargetNodeName,
+				TestDuration:                        30 * time.Minute,
+				TestIterations:                      testTestIterations,
+				PerDirectionRuns:                    true,
+				Scenarios:                           []string{config.ScenarioThroughput},
+				BurstMaxSize:                        testBurstMaxSize,
+				RateSweepSteps:                      testRateSweepSteps,
+				RateSweepStepDuration:               time.Minute,
+				WarmupDuration:                      30 * time.Second,
+				PortBandwidthGbps:                   testPortBandwidthGbps,
+				Verbose:                             true,
+				ConsoleTranscript:                   config.ConsoleTranscriptAlways,
+				LogConsoleTranscripts:               false,
+				AllowConcurrentRuns:                 true,
+				PriorityClassName:                   testPriorityClassName,
+				RuntimeClassName:                    testRuntimeClassName,
+				VMIExtraLabels:                      testVMIExtraLabels,
+				VMIExtraAnnotations:                 testVMIExtraAnnotations,
+				VMITolerations:                      testVMITolerations,
+				SkipTeardown:                        config.SkipTeardownOnFailure,
+				CPUIsolationCheck:                   config.CPUIsolationCheckFail,
+				MeasurementIsolation:                config.MeasurementIsolationStrict,
+				CriteriaSeverities:                  testCriteriaSeverities,
+				UseVirtualMachines:                  true,
+				RequireRealtimeKernel:               true,
+				NUMAPassthrough:                     true,
+				SetupRetries:                        testSetupRetries,
+				SetupAttemptTimeout:                 3 * time.Minute,
+				SetupRetryBackoff:                   20 * time.Second,
+				SetupTimeout:                        30 * time.Minute,
+				VMIBootTimeout:                      10 * time.Minute,
+				ConsoleCommandTimeout:               time.Minute,
+				TeardownTimeout:                     10 * time.Minute,
+				GuestCommandTransport:               config.GuestCommandTransportGuestAgent,
+				MatrixTestDuration:                  2 * time.Minute,
+				BaselineConfigMapName:               testBaselineConfigMapName,
+				TrexBinaryPath:                      testTrexBinaryPath,
+				TrexTransport:                       config.TrexTransportRPC,
+				TestpmdBinaryPath:                   testTestpmdBinaryPath,
+				TestpmdMbufSize:                     testTestpmdMbufSize,
+				TestpmdTotalNumMbufs:                testTestpmdTotalNumMbufs,
+				TestpmdMemoryChannels:               testTestpmdMemoryChannels,
+				TestpmdCPUs:                         []int{2, 3, 4, 5, 6},
+				TrexCPUs: &config.TrexCPUs{
+					Master:  testTrexMasterCPU,
+					Latency: testTrexLatencyCPU,
+					Traffic: []int{2, 3, 6, 7},
+				},
+				VMUnderTestWorkload: config.VMUnderTestWorkloadTestpmd,
+				NICDriverMode:       config.NICDriverModeMLX5,
+				ResultSinks:         config.ResultSinksStdoutOnly,
+				ResultsFormat:       config.ResultsFormatJSON,
+				ResultsObjectName:   testResultsObjectName,
+				ResultsObjectKind:   config.ResultsObjectKindSecret,
+				ArtifactsDir:        testArtifactsDir,
+				ReproScript:         true,
+				ProgressInterval:    time.Minute,
 			},
 		},
 		{
 			"config is valid and both Node Selectors are not set",
 			getValidUserParametersWithOutNodeSelectors(),
 			config.Config{
-				PodName:                         testPodName,
-				PodUID:                          testPodUID,
-				NetworkAttachmentDefinitionName: networkAttachmentDefinitionName,
-				TrafficGenContainerDiskImage:    testTrafficGenContainerDiskImage,
-				TrafficGenPacketsPerSecond:      testTrafficGenPacketsPerSecond,
-				VMUnderTestContainerDiskImage:   testVMUnderTestContainerDiskImage,
-				TestDuration:                    30 * time.Minute,
-				PortBandwidthGbps:               testPortBandwidthGbps,
-				Verbose:                         true,
+				PodName:                             testPodName,
+				PodUID:                              testPodUID,
+				CheckupUID:                          testPodUID,
+				NetworkAttachmentDefinitionName:     networkAttachmentDefinitionName,
+				NetworkAttachmentDefinitionNameEast: networkAttachmentDefinitionName,
+				NetworkAttachmentDefinitionNameWest: networkAttachmentDefinitionName,
+				TrafficGenContainerDiskImage:        testTrafficGenContainerDiskImage,
+				TrafficGenPacketsPerSecond:          testTrafficGenPacketsPerSecond,
+				TrafficGenServiceMode:               true,
+				TrafficVlanID:                       testTrafficVlanID,
+				IPFamily:                            config.IPFamilyDual,
+				TrafficGenFlowCount:                 testTrafficGenFlowCount,
+				TrafficGenFlowStats:                 true,
+				TrafficGenServerCores:               testTrafficGenServerCores,
+				TrafficGenServerIOMode:              1,
+				TrafficGenScapyServer:               true,
+				TrafficGenSoftwareMode:              true,
+				VMUnderTestContainerDiskImage:       testVMUnderTestContainerDiskImage,
+				TestDuration:                        30 * time.Minute,
+				TestIterations:                      testTestIterations,
+				PerDirectionRuns:                    true,
+				Scenarios:                           []string{config.ScenarioThroughput},
+				BurstMaxSize:                        testBurstMaxSize,
+				RateSweepSteps:                      testRateSweepSteps,
+				RateSweepStepDuration:               time.Minute,
+				WarmupDuration:                      30 * time.Second,
+				PortBandwidthGbps:                   testPortBandwidthGbps,
+				Verbose:                             true,
+				ConsoleTranscript:                   config.ConsoleTranscriptAlways,
+				LogConsoleTranscripts:               false,
+				AllowConcurrentRuns:                 true,
+				PriorityClassName:                   testPriorityClassName,
+				RuntimeClassName:                    testRuntimeClassName,
+				VMIExtraLabels:                      testVMIExtraLabels,
+				VMIExtraAnnotations:                 testVMIExtraAnnotations,
+				VMITolerations:                      testVMITolerations,
+				SkipTeardown:                        config.SkipTeardownOnFailure,
+				CPUIsolationCheck:                   config.CPUIsolationCheckFail,
+				MeasurementIsolation:                config.MeasurementIsolationStrict,
+				CriteriaSeverities:                  testCriteriaSeverities,
+				UseVirtualMachines:                  true,
+				RequireRealtimeKernel:               true,
+				NUMAPassthrough:                     true,
+				SetupRetries:                        testSetupRetries,
+				SetupAttemptTimeout:                 3 * time.Minute,
+				SetupRetryBackoff:                   20 * time.Second,
+				SetupTimeout:                        30 * time.Minute,
+				VMIBootTimeout:                      10 * time.Minute,
+				ConsoleCommandTimeout:               time.Minute,
+				TeardownTimeout:                     10 * time.Minute,
+				GuestCommandTransport:               config.GuestCommandTransportGuestAgent,
+				MatrixTestDuration:                  2 * time.Minute,
+				BaselineConfigMapName:               testBaselineConfigMapName,
+				TrexBinaryPath:                      testTrexBinaryPath,
+				TrexTransport:                       config.TrexTransportRPC,
+				TestpmdBinaryPath:                   testTestpmdBinaryPath,
+				TestpmdMbufSize:                     testTestpmdMbufSize,
+				TestpmdTotalNumMbufs:                testTestpmdTotalNumMbufs,
+				TestpmdMemoryChannels:               testTestpmdMemoryChannels,
+				TestpmdCPUs:                         []int{2, 3, 4, 5, 6},
+				TrexCPUs: &config.TrexCPUs{
+					Master:  testTrexMasterCPU,
+					Latency: testTrexLatencyCPU,
+					Traffic: []int{2, 3, 6, 7},
+				},
+				VMUnderTestWorkload: config.VMUnderTestWorkloadTestpmd,
+				NICDriverMode:       config.NICDriverModeMLX5,
+				ResultSinks:         config.ResultSinksStdoutOnly,
+				ResultsFormat:       config.ResultsFormatJSON,
+				ResultsObjectName:   testResultsObjectName,
+				ResultsObjectKind:   config.ResultsObjectKindSecret,
+				ArtifactsDir:        testArtifactsDir,
+				ReproScript:         true,
+				ProgressInterval:    time.Minute,
 			},
 		},
 	}
@@ -143,12 +684,237 @@ func TestNewShouldApplyUserConfigWhen(t *testing.T) {
 			testCase.expectedConfig.TrafficGenWestMacAddress = actualConfig.TrafficGenWestMacAddress
 			testCase.expectedConfig.VMUnderTestEastMacAddress = actualConfig.VMUnderTestEastMacAddress
 			testCase.expectedConfig.VMUnderTestWestMacAddress = actualConfig.VMUnderTestWestMacAddress
+			testCase.expectedConfig.VMIPassword = actualConfig.VMIPassword
 
 			assert.Equal(t, testCase.expectedConfig, actualConfig)
 		})
 	}
 }
 
+func TestNewShouldApplySeparateNetworkAttachmentDefinitions(t *testing.T) {
+	const (
+		eastNetworkAttachmentDefinitionName = "intel-dpdk-network-pf0"
+		westNetworkAttachmentDefinitionName = "intel-dpdk-network-pf1"
+	)
+
+	t.Run("when both are set", func(t *testing.T) {
+		params := getValidUserParameters()
+		delete(params, config.NetworkAttachmentDefinitionNameParamName)
+		params[config.NetworkAttachmentDefinitionNameEastParamName] = eastNetworkAttachmentDefinitionName
+		params[config.NetworkAttachmentDefinitionNameWestParamName] = westNetworkAttachmentDefinitionName
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+
+		assert.Equal(t, eastNetworkAttachmentDefinitionName, actualConfig.NetworkAttachmentDefinitionNameEast)
+		assert.Equal(t, westNetworkAttachmentDefinitionName, actualConfig.NetworkAttachmentDefinitionNameWest)
+		assert.Equal(t,
+			[]string{eastNetworkAttachmentDefinitionName, westNetworkAttachmentDefinitionName},
+			actualConfig.NetworkAttachmentDefinitionNames(),
+		)
+	})
+
+	t.Run("when only one is set, the other defaults to the shared one", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.NetworkAttachmentDefinitionNameWestParamName] = westNetworkAttachmentDefinitionName
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+
+		assert.Equal(t, networkAttachmentDefinitionName, actualConfig.NetworkAttachmentDefinitionNameEast)
+		assert.Equal(t, westNetworkAttachmentDefinitionName, actualConfig.NetworkAttachmentDefinitionNameWest)
+	})
+
+	t.Run("when only one is set without a shared one", func(t *testing.T) {
+		params := getValidUserParameters()
+		delete(params, config.NetworkAttachmentDefinitionNameParamName)
+		params[config.NetworkAttachmentDefinitionNameEastParamName] = eastNetworkAttachmentDefinitionName
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrInvalidNetworkAttachmentDefinitionName)
+	})
+}
+
+func TestNewShouldApplyInterfaces(t *testing.T) {
+	const (
+		eastNetworkAttachmentDefinitionName  = "intel-dpdk-network-pf0"
+		westNetworkAttachmentDefinitionName  = "intel-dpdk-network-pf1"
+		thirdNetworkAttachmentDefinitionName = "intel-dpdk-network-pf2"
+		customTrafficGenMacAddress           = "50:00:00:00:00:aa"
+		customPCIAddress                     = "0000:0a:00.0"
+	)
+
+	params := getValidUserParameters()
+	delete(params, config.NetworkAttachmentDefinitionNameParamName)
+	params[config.NetworkAttachmentDefinitionNameEastParamName] = eastNetworkAttachmentDefinitionName
+	params[config.NetworkAttachmentDefinitionNameWestParamName] = westNetworkAttachmentDefinitionName
+	params[config.InterfacesParamName] = `[
+		{},
+		{"trafficGenMacAddress": "` + customTrafficGenMacAddress + `"},
+		{"networkAttachmentDefinitionName": "` + thirdNetworkAttachmentDefinitionName + `"},
+		{"pciAddress": "` + customPCIAddress + `"}
+	]`
+
+	actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+	assert.NoError(t, err)
+
+	interfaces := actualConfig.TestInterfaces()
+	assert.Len(t, interfaces, 4)
+
+	var names, nadNames, pciAddresses []string
+	for _, iface := range interfaces {
+		names = append(names, iface.Name)
+		nadNames = append(nadNames, iface.NetworkAttachmentDefinitionName)
+		pciAddresses = append(pciAddresses, iface.PCIAddress)
+	}
+	assert.Equal(t, []string{"nic-east", "nic-west", "nic-east-1", "nic-west-1"}, names)
+	assert.Equal(t, []string{
+		eastNetworkAttachmentDefinitionName,
+		westNetworkAttachmentDefinitionName,
+		thirdNetworkAttachmentDefinitionName,
+		westNetworkAttachmentDefinitionName,
+	}, nadNames)
+	assert.Equal(t, []string{"", "", "", customPCIAddress}, pciAddresses)
+
+	assert.Equal(t, customTrafficGenMacAddress, interfaces[1].TrafficGenMacAddress.String())
+	assert.Equal(t, interfaces[1].TrafficGenMacAddress, actualConfig.TrafficGenWestMacAddress)
+	assert.Equal(t, byte(config.VMUnderTestMACAddressPrefixOctet), interfaces[3].VMUnderTestMacAddress[0])
+	assert.Equal(t, byte(4), interfaces[3].VMUnderTestMacAddress[5])
+
+	assert.Equal(t, []string{
+		eastNetworkAttachmentDefinitionName,
+		westNetworkAttachmentDefinitionName,
+		thirdNetworkAttachmentDefinitionName,
+	}, actualConfig.NetworkAttachmentDefinitionNames())
+}
+
+func TestNewShouldApplyTrafficGenGatewayMacAddresses(t *testing.T) {
+	const (
+		eastGatewayMacAddress = "02:00:00:00:00:01"
+		westGatewayMacAddress = "02:00:00:00:00:02"
+	)
+
+	t.Run("a single gateway is shared by all the interfaces", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.TrafficGenGatewayMacAddressesParamName] = eastGatewayMacAddress
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+
+		assert.True(t, actualConfig.Routed())
+		assert.Equal(t, eastGatewayMacAddress, actualConfig.GatewayMacAddress(0).String())
+		assert.Equal(t, eastGatewayMacAddress, actualConfig.GatewayMacAddress(1).String())
+	})
+
+	t.Run("a gateway per interface", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.TrafficGenGatewayMacAddressesParamName] = eastGatewayMacAddress + ", " + westGatewayMacAddress
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+
+		assert.Equal(t, eastGatewayMacAddress, actualConfig.GatewayMacAddress(0).String())
+		assert.Equal(t, westGatewayMacAddress, actualConfig.GatewayMacAddress(1).String())
+	})
+
+	t.Run("the traffic is not routed by default", func(t *testing.T) {
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: getValidUserParameters()})
+		assert.NoError(t, err)
+
+		assert.False(t, actualConfig.Routed())
+		assert.Nil(t, actualConfig.GatewayMacAddress(0))
+	})
+}
+
+func TestNewShouldApplyTrafficGenPortIPs(t *testing.T) {
+	t.Run("the ports IPs and gateways are set by the user", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.TrafficGenPortIPsParamName] = "192.168.100.2, 192.168.200.2"
+		params[config.TrafficGenPortGatewaysParamName] = "192.168.100.1, 192.168.200.1"
+
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.NoError(t, err)
+
+		ip, gateway := actualConfig.TrafficGenPortIPAddresses(0)
+		assert.Equal(t, "192.168.100.2", ip.String())
+		assert.Equal(t, "192.168.100.1", gateway.String())
+		ip, gateway = actualConfig.TrafficGenPortIPAddresses(1)
+		assert.Equal(t, "192.168.200.2", ip.String())
+		assert.Equal(t, "192.168.200.1", gateway.String())
+	})
+
+	t.Run("each port resides on its own subnet by default", func(t *testing.T) {
+		actualConfig, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: getValidUserParameters()})
+		assert.NoError(t, err)
+
+		ip, gateway := actualConfig.TrafficGenPortIPAddresses(1)
+		assert.Equal(t, "10.10.20.2", ip.String())
+		assert.Equal(t, "10.10.20.1", gateway.String())
+	})
+
+	t.Run("the ports IPs cannot be set with ipv6 IP family", func(t *testing.T) {
+		params := getValidUserParameters()
+		params[config.TrafficGenServiceModeParamName] = strconv.FormatBool(false)
+		params[config.IPFamilyParamName] = config.IPFamilyIPv6
+		params[config.TrafficGenPortIPsParamName] = "192.168.100.2,192.168.200.2"
+		params[config.TrafficGenPortGatewaysParamName] = "192.168.100.1,192.168.200.1"
+
+		_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+		assert.ErrorIs(t, err, config.ErrIllegalTrafficGenPortIPsCombination)
+	})
+}
+
+func TestNewShouldFailWhenTrafficGenPortIPsAreInvalid(t *testing.T) {
+	testCases := []struct {
+		description   string
+		ips           string
+		gateways      string
+		expectedError error
+	}{
+		{
+			description:   "an IP is not an IPv4 address",
+			ips:           "192.168.100.2,2001:db8::2",
+			gateways:      "192.168.100.1,192.168.200.1",
+			expectedError: config.ErrInvalidTrafficGenPortIPs,
+		},
+		{
+			description:   "the IPs count does not match the interfaces count",
+			ips:           "192.168.100.2",
+			gateways:      "192.168.100.1,192.168.200.1",
+			expectedError: config.ErrInvalidTrafficGenPortIPs,
+		},
+		{
+			description:   "an IP is used more than once",
+			ips:           "192.168.100.2,192.168.100.2",
+			gateways:      "192.168.100.1,192.168.200.1",
+			expectedError: config.ErrInvalidTrafficGenPortIPs,
+		},
+		{
+			description:   "a gateway is invalid",
+			ips:           "192.168.100.2,192.168.200.2",
+			gateways:      "192.168.100.1,192.168.200",
+			expectedError: config.ErrInvalidTrafficGenPortGateways,
+		},
+		{
+			description:   "a port IP is also its gateway",
+			ips:           "192.168.100.2,192.168.200.2",
+			gateways:      "192.168.100.1,192.168.200.2",
+			expectedError: config.ErrIllegalTrafficGenPortIPsCombination,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			params := getValidUserParameters()
+			params[config.TrafficGenPortIPsParamName] = testCase.ips
+			params[config.TrafficGenPortGatewaysParamName] = testCase.gateways
+
+			_, err := config.New(kconfig.Config{PodName: testPodName, PodUID: testPodUID, Params: params})
+			assert.ErrorIs(t, err, testCase.expectedError)
+		})
+	}
+}
+
 type failureTestCase struct {
 	description    string
 	key            string
@@ -200,24 +966,684 @@ func TestNewShouldFailWhen(t *testing.T) {
 			faultyKeyValue: "15f",
 			expectedError:  config.ErrInvalidTrafficGenPacketsPerSecond,
 		},
+		{
+			description:    "Interfaces is not a JSON list",
+			key:            config.InterfacesParamName,
+			faultyKeyValue: "nic-east,nic-west",
+			expectedError:  config.ErrInvalidInterfaces,
+		},
+		{
+			description:    "Interfaces count cannot be paired",
+			key:            config.InterfacesParamName,
+			faultyKeyValue: "[{},{},{}]",
+			expectedError:  config.ErrInvalidInterfaces,
+		},
+		{
+			description:    "Interfaces count exceeds the supported one",
+			key:            config.InterfacesParamName,
+			faultyKeyValue: "[{},{},{},{},{},{},{},{},{},{}]",
+			expectedError:  config.ErrInvalidInterfaces,
+		},
+		{
+			description:    "Interfaces PCI address is invalid",
+			key:            config.InterfacesParamName,
+			faultyKeyValue: `[{"pciAddress":"06:00.0"},{}]`,
+			expectedError:  config.ErrInvalidInterfaces,
+		},
+		{
+			description:    "Interfaces PCI address is used twice",
+			key:            config.InterfacesParamName,
+			faultyKeyValue: `[{"pciAddress":"0000:06:00.0"},{"pciAddress":"0000:06:00.0"}]`,
+			expectedError:  config.ErrInvalidInterfaces,
+		},
+		{
+			description:    "Interfaces MAC address is invalid",
+			key:            config.InterfacesParamName,
+			faultyKeyValue: `[{"trafficGenMacAddress":"50:00"},{}]`,
+			expectedError:  config.ErrInvalidInterfaces,
+		},
+		{
+			description:    "TrafficGenServiceMode is invalid",
+			key:            config.TrafficGenServiceModeParamName,
+			faultyKeyValue: "sometimes",
+			expectedError:  config.ErrInvalidTrafficGenServiceMode,
+		},
 		{
 			description:    "TestDuration is invalid",
 			key:            config.TestDurationParamName,
 			faultyKeyValue: "invalid value",
 			expectedError:  config.ErrInvalidTestDuration,
 		},
+		{
+			description:    "TestIterations is zero",
+			key:            config.TestIterationsParamName,
+			faultyKeyValue: "0",
+			expectedError:  config.ErrInvalidTestIterations,
+		},
+		{
+			description:    "TestIterations is invalid",
+			key:            config.TestIterationsParamName,
+			faultyKeyValue: "twice",
+			expectedError:  config.ErrInvalidTestIterations,
+		},
+		{
+			description:    "PerDirectionRuns is invalid",
+			key:            config.PerDirectionRunsParamName,
+			faultyKeyValue: "both",
+			expectedError:  config.ErrInvalidPerDirectionRuns,
+		},
+		{
+			description:    "Scenarios has an unknown scenario",
+			key:            config.ScenariosParamName,
+			faultyKeyValue: "throughput,latency",
+			expectedError:  config.ErrInvalidScenarios,
+		},
+		{
+			description:    "Scenarios has a duplicate scenario",
+			key:            config.ScenariosParamName,
+			faultyKeyValue: "throughput,throughput",
+			expectedError:  config.ErrInvalidScenarios,
+		},
+		{
+			description:    "BurstMaxSize is below the burst min size",
+			key:            config.BurstMaxSizeParamName,
+			faultyKeyValue: "16",
+			expectedError:  config.ErrInvalidBurstMaxSize,
+		},
+		{
+			description:    "BurstMaxSize is above the max burst size",
+			key:            config.BurstMaxSizeParamName,
+			faultyKeyValue: "65536",
+			expectedError:  config.ErrInvalidBurstMaxSize,
+		},
+		{
+			description:    "RateSweepSteps is above the max steps",
+			key:            config.RateSweepStepsParamName,
+			faultyKeyValue: "101",
+			expectedError:  config.ErrInvalidRateSweepSteps,
+		},
+		{
+			description:    "RateSweepStepDuration is invalid",
+			key:            config.RateSweepStepDurationParamName,
+			faultyKeyValue: "0s",
+			expectedError:  config.ErrInvalidRateSweepStepDuration,
+		},
+		{
+			description:    "WarmupDuration is invalid",
+			key:            config.WarmupDurationParamName,
+			faultyKeyValue: "invalid value",
+			expectedError:  config.ErrInvalidWarmupDuration,
+		},
+		{
+			description:    "WarmupDuration is negative",
+			key:            config.WarmupDurationParamName,
+			faultyKeyValue: "-1m",
+			expectedError:  config.ErrInvalidWarmupDuration,
+		},
 		{
 			description:    "PortBandwidthGbps is invalid",
 			key:            config.PortBandwidthGbpsParamName,
 			faultyKeyValue: "0",
 			expectedError:  config.ErrInvalidPortBandwidthGbps,
 		},
+		{
+			description:    "IPFamily is invalid",
+			key:            config.IPFamilyParamName,
+			faultyKeyValue: "ipv5",
+			expectedError:  config.ErrInvalidIPFamily,
+		},
+		{
+			description:    "IPFamily is ipv6 along with TrafficGenServiceMode",
+			key:            config.IPFamilyParamName,
+			faultyKeyValue: config.IPFamilyIPv6,
+			expectedError:  config.ErrIllegalServiceModeIPFamilyCombination,
+		},
+		{
+			description:    "TrafficGenGatewayMacAddresses is not a MAC address",
+			key:            config.TrafficGenGatewayMacAddressesParamName,
+			faultyKeyValue: "02:00:00",
+			expectedError:  config.ErrInvalidTrafficGenGatewayMacAddresses,
+		},
+		{
+			description:    "TrafficGenGatewayMacAddresses count does not match the interfaces count",
+			key:            config.TrafficGenGatewayMacAddressesParamName,
+			faultyKeyValue: "02:00:00:00:00:01,02:00:00:00:00:02,02:00:00:00:00:03",
+			expectedError:  config.ErrInvalidTrafficGenGatewayMacAddresses,
+		},
+		{
+			description:    "TrafficGenPortIPs is set without TrafficGenPortGateways",
+			key:            config.TrafficGenPortIPsParamName,
+			faultyKeyValue: "192.168.100.2,192.168.200.2",
+			expectedError:  config.ErrIllegalTrafficGenPortIPsCombination,
+		},
+		{
+			description:    "TrafficGenPortGateways is set without TrafficGenPortIPs",
+			key:            config.TrafficGenPortGatewaysParamName,
+			faultyKeyValue: "192.168.100.1,192.168.200.1",
+			expectedError:  config.ErrIllegalTrafficGenPortIPsCombination,
+		},
+		{
+			description:    "TrafficGenFlowCount is zero",
+			key:            config.TrafficGenFlowCountParamName,
+			faultyKeyValue: "0",
+			expectedError:  config.ErrInvalidTrafficGenFlowCount,
+		},
+		{
+			description:    "TrafficGenFlowCount is too large",
+			key:            config.TrafficGenFlowCountParamName,
+			faultyKeyValue: "32769",
+			expectedError:  config.ErrInvalidTrafficGenFlowCount,
+		},
+		{
+			description:    "TrafficGenFlowStats is invalid",
+			key:            config.TrafficGenFlowStatsParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidTrafficGenFlowStats,
+		}, {
+			description:    "TrafficGenServerCores is zero",
+			key:            config.TrafficGenServerCoresParamName,
+			faultyKeyValue: "0",
+			expectedError:  config.ErrInvalidTrafficGenServerCores,
+		},
+		{
+			description:    "TrafficGenServerCores exceeds the traffic CPUs",
+			key:            config.TrafficGenServerCoresParamName,
+			faultyKeyValue: "5",
+			expectedError:  config.ErrInvalidTrafficGenServerCores,
+		},
+		{
+			description:    "TrafficGenServerIOMode is out of range",
+			key:            config.TrafficGenServerIOModeParamName,
+			faultyKeyValue: "3",
+			expectedError:  config.ErrInvalidTrafficGenServerIOMode,
+		},
+		{
+			description:    "TrafficGenScapyServer is invalid",
+			key:            config.TrafficGenScapyServerParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidTrafficGenScapyServer,
+		},
+		{
+			description:    "TrafficGenSoftwareMode is invalid",
+			key:            config.TrafficGenSoftwareModeParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidTrafficGenSoftwareMode,
+		},
+
+		{
+			description:    "TrafficVlanID is zero",
+			key:            config.TrafficVlanIDParamName,
+			faultyKeyValue: "0",
+			expectedError:  config.ErrInvalidTrafficVlanID,
+		},
+		{
+			description:    "TrafficVlanID is out of range",
+			key:            config.TrafficVlanIDParamName,
+			faultyKeyValue: "4095",
+			expectedError:  config.ErrInvalidTrafficVlanID,
+		},
 		{
 			description:    "Verbose is invalid",
 			key:            config.VerboseParamName,
 			faultyKeyValue: "maybe",
 			expectedError:  config.ErrInvalidVerbose,
 		},
+		{
+			description:    "SkipTeardown is invalid",
+			key:            config.SkipTeardownParamName,
+			faultyKeyValue: "onSuccess",
+			expectedError:  config.ErrInvalidSkipTeardown,
+		},
+		{
+			description:    "CPUIsolationCheck is invalid",
+			key:            config.CPUIsolationCheckParamName,
+			faultyKeyValue: "always",
+			expectedError:  config.ErrInvalidCPUIsolationCheck,
+		},
+		{
+			description:    "MeasurementIsolation is invalid",
+			key:            config.MeasurementIsolationParamName,
+			faultyKeyValue: "quiet",
+			expectedError:  config.ErrInvalidMeasurementIsolation,
+		},
+		{
+			description:    "CriteriaSeverities has an unknown criterion",
+			key:            config.CriteriaSeveritiesParamName,
+			faultyKeyValue: "latency:minor",
+			expectedError:  config.ErrInvalidCriteriaSeverities,
+		},
+		{
+			description:    "CriteriaSeverities has an unknown severity",
+			key:            config.CriteriaSeveritiesParamName,
+			faultyKeyValue: "drops:critical",
+			expectedError:  config.ErrInvalidCriteriaSeverities,
+		},
+		{
+			description:    "CriteriaSeverities has a pair without a severity",
+			key:            config.CriteriaSeveritiesParamName,
+			faultyKeyValue: "drops",
+			expectedError:  config.ErrInvalidCriteriaSeverities,
+		},
+		{
+			description:    "CriteriaSeverities has a duplicate criterion",
+			key:            config.CriteriaSeveritiesParamName,
+			faultyKeyValue: "drops:minor,drops:major",
+			expectedError:  config.ErrInvalidCriteriaSeverities,
+		},
+		{
+			description:    "ConsoleTranscript is invalid",
+			key:            config.ConsoleTranscriptParamName,
+			faultyKeyValue: "sometimes",
+			expectedError:  config.ErrInvalidConsoleTranscript,
+		},
+		{
+			description:    "LogConsoleTranscripts is invalid",
+			key:            config.LogConsoleTranscriptsParamName,
+			faultyKeyValue: "sometimes",
+			expectedError:  config.ErrInvalidLogConsoleTranscripts,
+		},
+		{
+			description:    "AllowConcurrentRuns is invalid",
+			key:            config.AllowConcurrentRunsParamName,
+			faultyKeyValue: "sometimes",
+			expectedError:  config.ErrInvalidAllowConcurrentRuns,
+		},
+		{
+			description:    "PriorityClassName is invalid",
+			key:            config.PriorityClassNameParamName,
+			faultyKeyValue: "Not_A_Name",
+			expectedError:  config.ErrInvalidPriorityClassName,
+		},
+		{
+			description:    "RuntimeClassName is invalid",
+			key:            config.RuntimeClassNameParamName,
+			faultyKeyValue: "Not_A_Name",
+			expectedError:  config.ErrInvalidRuntimeClassName,
+		},
+		{
+			description:    "VMIExtraLabels has a pair without a value",
+			key:            config.VMIExtraLabelsParamName,
+			faultyKeyValue: "team",
+			expectedError:  config.ErrInvalidVMIExtraLabels,
+		},
+		{
+			description:    "VMIExtraLabels has an invalid value",
+			key:            config.VMIExtraLabelsParamName,
+			faultyKeyValue: "team=NFV team",
+			expectedError:  config.ErrInvalidVMIExtraLabels,
+		},
+		{
+			description:    "VMIExtraAnnotations has a pair without a value",
+			key:            config.VMIExtraAnnotationsParamName,
+			faultyKeyValue: "example.com/owner",
+			expectedError:  config.ErrInvalidVMIExtraAnnotations,
+		},
+		{
+			description:    "VMIExtraAnnotations has an invalid key",
+			key:            config.VMIExtraAnnotationsParamName,
+			faultyKeyValue: "not a key=value",
+			expectedError:  config.ErrInvalidVMIExtraAnnotations,
+		},
+		{
+			description:    "VMIExtraAnnotations has a duplicate key",
+			key:            config.VMIExtraAnnotationsParamName,
+			faultyKeyValue: "owner=a,owner=b",
+			expectedError:  config.ErrInvalidVMIExtraAnnotations,
+		},
+		{
+			description:    "VMITolerations has an unknown effect",
+			key:            config.VMITolerationsParamName,
+			faultyKeyValue: "dedicated=dpdk:NoPlacement",
+			expectedError:  config.ErrInvalidVMITolerations,
+		},
+		{
+			description:    "VMITolerations has an empty key",
+			key:            config.VMITolerationsParamName,
+			faultyKeyValue: "=dpdk:NoSchedule",
+			expectedError:  config.ErrInvalidVMITolerations,
+		},
+		{
+			description:    "VMITolerations has an invalid value",
+			key:            config.VMITolerationsParamName,
+			faultyKeyValue: "dedicated=not a value",
+			expectedError:  config.ErrInvalidVMITolerations,
+		},
+		{
+			description:    "TargetNodeTolerations has an unknown effect",
+			key:            config.TargetNodeTolerationsParamName,
+			faultyKeyValue: "dedicated=dpdk:NoPlacement",
+			expectedError:  config.ErrInvalidTargetNodeTolerations,
+		},
+		{
+			description:    "UseVirtualMachines is invalid",
+			key:            config.UseVirtualMachinesParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidUseVirtualMachines,
+		},
+		{
+			description:    "RequireRealtimeKernel is invalid",
+			key:            config.RequireRealtimeKernelParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidRequireRealtimeKernel,
+		},
+		{
+			description:    "NUMAPassthrough is invalid",
+			key:            config.NUMAPassthroughParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidNUMAPassthrough,
+		},
+		{
+			description:    "MigrateVMUnderTest is invalid",
+			key:            config.MigrateVMUnderTestParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidMigrateVMUnderTest,
+		},
+		{
+			description:    "SameNodePlacement is invalid",
+			key:            config.SameNodePlacementParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidSameNodePlacement,
+		},
+		{
+			description:    "SetupRetries is negative",
+			key:            config.SetupRetriesParamName,
+			faultyKeyValue: "-1",
+			expectedError:  config.ErrInvalidSetupRetries,
+		},
+		{
+			description:    "SetupRetries is too large",
+			key:            config.SetupRetriesParamName,
+			faultyKeyValue: "11",
+			expectedError:  config.ErrInvalidSetupRetries,
+		},
+		{
+			description:    "SetupAttemptTimeout is invalid",
+			key:            config.SetupAttemptTimeoutParamName,
+			faultyKeyValue: "0s",
+			expectedError:  config.ErrInvalidSetupAttemptTimeout,
+		},
+		{
+			description:    "SetupRetryBackoff is invalid",
+			key:            config.SetupRetryBackoffParamName,
+			faultyKeyValue: "soon",
+			expectedError:  config.ErrInvalidSetupRetryBackoff,
+		},
+		{
+			description:    "SetupTimeout is invalid",
+			key:            config.SetupTimeoutParamName,
+			faultyKeyValue: "0s",
+			expectedError:  config.ErrInvalidSetupTimeout,
+		},
+		{
+			description:    "VMIBootTimeout is negative",
+			key:            config.VMIBootTimeoutParamName,
+			faultyKeyValue: "-1m",
+			expectedError:  config.ErrInvalidVMIBootTimeout,
+		},
+		{
+			description:    "ConsoleCommandTimeout is invalid",
+			key:            config.ConsoleCommandTimeoutParamName,
+			faultyKeyValue: "30",
+			expectedError:  config.ErrInvalidConsoleCommandTimeout,
+		},
+		{
+			description:    "TeardownTimeout is invalid",
+			key:            config.TeardownTimeoutParamName,
+			faultyKeyValue: "0s",
+			expectedError:  config.ErrInvalidTeardownTimeout,
+		},
+		{
+			description:    "GuestCommandTransport is invalid",
+			key:            config.GuestCommandTransportParamName,
+			faultyKeyValue: "telnet",
+			expectedError:  config.ErrInvalidGuestCommandTransport,
+		},
+		{
+			description:    "TrafficGenNodeLabelSelector is not a label selector",
+			key:            config.TrafficGenNodeLabelSelectorParamName,
+			faultyKeyValue: "dpdk in (",
+			expectedError:  config.ErrInvalidTrafficGenNodeLabelSelector,
+		},
+		{
+			description:    "VMUnderTestNodeLabelSelector is not a label selector",
+			key:            config.VMUnderTestNodeLabelSelectorParamName,
+			faultyKeyValue: "dpdk in (",
+			expectedError:  config.ErrInvalidVMUnderTestNodeLabelSelector,
+		},
+		{
+			description:    "RequireDifferentNodes is invalid",
+			key:            config.RequireDifferentNodesParamName,
+			faultyKeyValue: "maybe",
+			expectedError:  config.ErrInvalidRequireDifferentNodes,
+		},
+		{
+			description:    "MatrixNodeSelector is not a label selector",
+			key:            config.MatrixNodeSelectorParamName,
+			faultyKeyValue: "dpdk in (",
+			expectedError:  config.ErrInvalidMatrixNodeSelector,
+		},
+		{
+			description:    "MatrixTestDuration is invalid",
+			key:            config.MatrixTestDurationParamName,
+			faultyKeyValue: "1 minute",
+			expectedError:  config.ErrInvalidMatrixTestDuration,
+		},
+		{
+			description:    "MatrixTestDuration is zero",
+			key:            config.MatrixTestDurationParamName,
+			faultyKeyValue: "0s",
+			expectedError:  config.ErrInvalidMatrixTestDuration,
+		},
+		{
+			description:    "MaxAllowedDropRateBps is not a number",
+			key:            config.MaxAllowedDropRateBpsParamName,
+			faultyKeyValue: "fast",
+			expectedError:  config.ErrInvalidMaxAllowedDropRateBps,
+		},
+		{
+			description:    "MaxAllowedDropRateBps is zero",
+			key:            config.MaxAllowedDropRateBpsParamName,
+			faultyKeyValue: "0",
+			expectedError:  config.ErrInvalidMaxAllowedDropRateBps,
+		},
+		{
+			description:    "MaxAllowedDropRateBps is infinite",
+			key:            config.MaxAllowedDropRateBpsParamName,
+			faultyKeyValue: "+Inf",
+			expectedError:  config.ErrInvalidMaxAllowedDropRateBps,
+		},
+		{
+			description:    "SnapshotInterval is invalid",
+			key:            config.SnapshotIntervalParamName,
+			faultyKeyValue: "hourly",
+			expectedError:  config.ErrInvalidSnapshotInterval,
+		},
+		{
+			description:    "SnapshotInterval is shorter than the stats polling interval",
+			key:            config.SnapshotIntervalParamName,
+			faultyKeyValue: "5s",
+			expectedError:  config.ErrInvalidSnapshotInterval,
+		},
+		{
+			description:    "TrexBinaryPath is relative",
+			key:            config.TrexBinaryPathParamName,
+			faultyKeyValue: "trex/t-rex-64",
+			expectedError:  config.ErrInvalidTrexBinaryPath,
+		},
+		{
+			description:    "TrexBinaryPath is a directory",
+			key:            config.TrexBinaryPathParamName,
+			faultyKeyValue: "/opt/trex/",
+			expectedError:  config.ErrInvalidTrexBinaryPath,
+		},
+		{
+			description:    "TrexTransport is invalid",
+			key:            config.TrexTransportParamName,
+			faultyKeyValue: "zmq",
+			expectedError:  config.ErrInvalidTrexTransport,
+		},
+		{
+			description:    "TestpmdBinaryPath contains shell characters",
+			key:            config.TestpmdBinaryPathParamName,
+			faultyKeyValue: "dpdk-testpmd; reboot",
+			expectedError:  config.ErrInvalidTestpmdBinaryPath,
+		},
+		{
+			description:    "TestpmdMbufSize is invalid",
+			key:            config.TestpmdMbufSizeParamName,
+			faultyKeyValue: "0",
+			expectedError:  config.ErrInvalidTestpmdMbufSize,
+		},
+		{
+			description:    "TestpmdTotalNumMbufs is invalid",
+			key:            config.TestpmdTotalNumMbufsParamName,
+			faultyKeyValue: "-1",
+			expectedError:  config.ErrInvalidTestpmdTotalNumMbufs,
+		},
+		{
+			description:    "TestpmdMemoryChannels is invalid",
+			key:            config.TestpmdMemoryChannelsParamName,
+			faultyKeyValue: "many",
+			expectedError:  config.ErrInvalidTestpmdMemoryChannels,
+		},
+		{
+			description:    "TestpmdCPUs is not a CPU list",
+			key:            config.TestpmdCPUsParamName,
+			faultyKeyValue: "2-a",
+			expectedError:  config.ErrInvalidTestpmdCPUs,
+		},
+		{
+			description:    "TestpmdCPUs has a reversed range",
+			key:            config.TestpmdCPUsParamName,
+			faultyKeyValue: "6-2",
+			expectedError:  config.ErrInvalidTestpmdCPUs,
+		},
+		{
+			description:    "TestpmdCPUs has duplicate CPUs",
+			key:            config.TestpmdCPUsParamName,
+			faultyKeyValue: "2-4,4",
+			expectedError:  config.ErrInvalidTestpmdCPUs,
+		},
+		{
+			description:    "TestpmdCPUs has a single CPU",
+			key:            config.TestpmdCPUsParamName,
+			faultyKeyValue: "2",
+			expectedError:  config.ErrInvalidTestpmdCPUs,
+		},
+		{
+			description:    "TrexMasterCPU is negative",
+			key:            config.TrexMasterCPUParamName,
+			faultyKeyValue: "-1",
+			expectedError:  config.ErrInvalidTrexMasterCPU,
+		},
+		{
+			description:    "TrexLatencyCPU is invalid",
+			key:            config.TrexLatencyCPUParamName,
+			faultyKeyValue: "one",
+			expectedError:  config.ErrInvalidTrexLatencyCPU,
+		},
+		{
+			description:    "TrexTrafficCPUs is not a CPU list",
+			key:            config.TrexTrafficCPUsParamName,
+			faultyKeyValue: "4,,5",
+			expectedError:  config.ErrInvalidTrexTrafficCPUs,
+		},
+		{
+			description:    "TrexTrafficCPUs overlaps the master CPU",
+			key:            config.TrexTrafficCPUsParamName,
+			faultyKeyValue: "0,4",
+			expectedError:  config.ErrIllegalTrexCPUsCombination,
+		},
+		{
+			description:    "VMUnderTestWorkload is invalid",
+			key:            config.VMUnderTestWorkloadParamName,
+			faultyKeyValue: "vpp",
+			expectedError:  config.ErrInvalidVMUnderTestWorkload,
+		},
+		{
+			description:    "VMUnderTestWorkload is l3fwd along with TrafficVlanID",
+			key:            config.VMUnderTestWorkloadParamName,
+			faultyKeyValue: config.VMUnderTestWorkloadL3fwd,
+			expectedError:  config.ErrIllegalVMUnderTestWorkloadCombination,
+		},
+		{
+			description:    "NICDriverMode is invalid",
+			key:            config.NICDriverModeParamName,
+			faultyKeyValue: "igb_uio",
+			expectedError:  config.ErrInvalidNICDriverMode,
+		},
+		{
+			description:    "ResultSinks is invalid",
+			key:            config.ResultSinksParamName,
+			faultyKeyValue: "stdout",
+			expectedError:  config.ErrInvalidResultSinks,
+		},
+		{
+			description:    "ResultsFormat is invalid",
+			key:            config.ResultsFormatParamName,
+			faultyKeyValue: "yaml",
+			expectedError:  config.ErrInvalidResultsFormat,
+		},
+		{
+			description:    "ResultsObjectKind is invalid",
+			key:            config.ResultsObjectKindParamName,
+			faultyKeyValue: "Pod",
+			expectedError:  config.ErrInvalidResultsObjectKind,
+		},
+		{
+			description:    "ArtifactsDir is not an absolute path",
+			key:            config.ArtifactsDirParamName,
+			faultyKeyValue: "artifacts",
+			expectedError:  config.ErrInvalidArtifactsDir,
+		},
+		{
+			description:    "ArtifactsPVCName is invalid",
+			key:            config.ArtifactsPVCNameParamName,
+			faultyKeyValue: "Artifacts_PVC",
+			expectedError:  config.ErrInvalidArtifactsPVCName,
+		},
+		{
+			description:    "ArtifactsPVCName is set along with ArtifactsDir",
+			key:            config.ArtifactsPVCNameParamName,
+			faultyKeyValue: "artifacts",
+			expectedError:  config.ErrIllegalArtifactsCombination,
+		},
+		{
+			description:    "ReproScript is invalid",
+			key:            config.ReproScriptParamName,
+			faultyKeyValue: "yes",
+			expectedError:  config.ErrInvalidReproScript,
+		},
+		{
+			description:    "ReproScript is set without ArtifactsDir or ArtifactsPVCName",
+			key:            config.ArtifactsDirParamName,
+			faultyKeyValue: "",
+			expectedError:  config.ErrIllegalReproScriptCombination,
+		},
+		{
+			description:    "ProgressInterval is invalid",
+			key:            config.ProgressIntervalParamName,
+			faultyKeyValue: "often",
+			expectedError:  config.ErrInvalidProgressInterval,
+		},
+		{
+			description:    "BaselineConfigMapName is invalid",
+			key:            config.BaselineConfigMapNameParamName,
+			faultyKeyValue: "Baseline_Results",
+			expectedError:  config.ErrInvalidBaselineConfigMapName,
+		},
+		{
+			description:    "ProgressInterval is negative",
+			key:            config.ProgressIntervalParamName,
+			faultyKeyValue: "-1m",
+			expectedError:  config.ErrInvalidProgressInterval,
+		},
+		{
+			description:    "ResultsObjectName is missing and ResultsObjectKind is set",
+			key:            config.ResultsObjectNameParamName,
+			faultyKeyValue: "",
+			expectedError:  config.ErrIllegalResultsObjectCombination,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -261,7 +1687,69 @@ func getValidUserParameters() map[string]string {
 		config.VMUnderTestContainerDiskImageParamName:   testVMUnderTestContainerDiskImage,
 		config.VMUnderTestTargetNodeNameParamName:       testVMUnderTestTargetNodeName,
 		config.TestDurationParamName:                    testDuration,
+		config.TestIterationsParamName:                  fmt.Sprintf("%d", testTestIterations),
+		config.PerDirectionRunsParamName:                "true",
+		config.ScenariosParamName:                       config.ScenarioThroughput,
+		config.BurstMaxSizeParamName:                    fmt.Sprintf("%d", testBurstMaxSize),
+		config.RateSweepStepsParamName:                  fmt.Sprintf("%d", testRateSweepSteps),
+		config.RateSweepStepDurationParamName:           testRateSweepStepDuration,
+		config.WarmupDurationParamName:                  testWarmupDuration,
 		config.PortBandwidthGbpsParamName:               fmt.Sprintf("%d", testPortBandwidthGbps),
 		config.VerboseParamName:                         strconv.FormatBool(true),
+		config.ConsoleTranscriptParamName:               config.ConsoleTranscriptAlways,
+		config.LogConsoleTranscriptsParamName:           "false",
+		config.AllowConcurrentRunsParamName:             "true",
+		config.PriorityClassNameParamName:               testPriorityClassName,
+		config.RuntimeClassNameParamName:                testRuntimeClassName,
+		config.VMIExtraLabelsParamName:                  "example.com/cost-center=network,team=nfv",
+		config.VMIExtraAnnotationsParamName:             "example.com/owner=NFV team",
+		config.VMITolerationsParamName:                  "dedicated=dpdk:NoSchedule,example.com/maintenance",
+		config.SkipTeardownParamName:                    config.SkipTeardownOnFailure,
+		config.CPUIsolationCheckParamName:               config.CPUIsolationCheckFail,
+		config.MeasurementIsolationParamName:            config.MeasurementIsolationStrict,
+		config.CriteriaSeveritiesParamName:              "drops:minor,loss:major",
+		config.TrafficGenServiceModeParamName:           strconv.FormatBool(true),
+		config.TrafficVlanIDParamName:                   fmt.Sprintf("%d", testTrafficVlanID),
+		config.IPFamilyParamName:                        config.IPFamilyDual,
+		config.TrafficGenFlowCountParamName:             fmt.Sprintf("%d", testTrafficGenFlowCount),
+		config.TrafficGenFlowStatsParamName:             strconv.FormatBool(true),
+		config.TrafficGenServerCoresParamName:           fmt.Sprintf("%d", testTrafficGenServerCores),
+		config.TrafficGenServerIOModeParamName:          "1",
+		config.TrafficGenScapyServerParamName:           strconv.FormatBool(true),
+		config.TrafficGenSoftwareModeParamName:          strconv.FormatBool(true),
+		config.UseVirtualMachinesParamName:              strconv.FormatBool(true),
+		config.RequireRealtimeKernelParamName:           strconv.FormatBool(true),
+		config.NUMAPassthroughParamName:                 strconv.FormatBool(true),
+		config.MigrateVMUnderTestParamName:              strconv.FormatBool(false),
+		config.MatrixTestDurationParamName:              testMatrixTestDuration,
+		config.SameNodePlacementParamName:               strconv.FormatBool(false),
+		config.SetupRetriesParamName:                    fmt.Sprintf("%d", testSetupRetries),
+		config.SetupAttemptTimeoutParamName:             testSetupAttemptTimeout,
+		config.SetupRetryBackoffParamName:               testSetupRetryBackoff,
+		config.SetupTimeoutParamName:                    testSetupTimeout,
+		config.VMIBootTimeoutParamName:                  testVMIBootTimeout,
+		config.ConsoleCommandTimeoutParamName:           testConsoleCommandTimeout,
+		config.TeardownTimeoutParamName:                 testTeardownTimeout,
+		config.GuestCommandTransportParamName:           config.GuestCommandTransportGuestAgent,
+		config.TrexBinaryPathParamName:                  testTrexBinaryPath,
+		config.TrexTransportParamName:                   config.TrexTransportRPC,
+		config.TestpmdBinaryPathParamName:               testTestpmdBinaryPath,
+		config.TestpmdMbufSizeParamName:                 fmt.Sprintf("%d", testTestpmdMbufSize),
+		config.TestpmdTotalNumMbufsParamName:            fmt.Sprintf("%d", testTestpmdTotalNumMbufs),
+		config.TestpmdMemoryChannelsParamName:           fmt.Sprintf("%d", testTestpmdMemoryChannels),
+		config.TestpmdCPUsParamName:                     testTestpmdCPUs,
+		config.TrexMasterCPUParamName:                   fmt.Sprintf("%d", testTrexMasterCPU),
+		config.TrexLatencyCPUParamName:                  fmt.Sprintf("%d", testTrexLatencyCPU),
+		config.TrexTrafficCPUsParamName:                 testTrexTrafficCPUs,
+		config.VMUnderTestWorkloadParamName:             config.VMUnderTestWorkloadTestpmd,
+		config.NICDriverModeParamName:                   config.NICDriverModeMLX5,
+		config.ResultSinksParamName:                     config.ResultSinksStdoutOnly,
+		config.ResultsFormatParamName:                   config.ResultsFormatJSON,
+		config.ResultsObjectNameParamName:               testResultsObjectName,
+		config.ResultsObjectKindParamName:               config.ResultsObjectKindSecret,
+		config.ArtifactsDirParamName:                    testArtifactsDir,
+		config.ReproScriptParamName:                     strconv.FormatBool(true),
+		config.ProgressIntervalParamName:                testProgressInterval,
+		config.BaselineConfigMapNameParamName:           testBaselineConfigMapName,
 	}
 }
@@ -0,0 +1,301 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+const (
+	eastInterfaceName = "nic-east"
+	westInterfaceName = "nic-west"
+)
+
+// Interface is a test interface, attached to both VMs.
+// Interfaces are paired in order: the traffic generator sends the traffic through the first interface of each pair,
+// and the VM under test forwards it back through the second one.
+// The PCI address, when set, pins the interface to its slot in both VMs. Either way, the guests discover the PCI address
+// of the interface by its MAC address.
+type Interface struct {
+	Name                            string
+	NetworkAttachmentDefinitionName string
+	TrafficGenMacAddress            net.HardwareAddr
+	VMUnderTestMacAddress           net.HardwareAddr
+	PCIAddress                      string
+}
+
+// interfaceParams is a single entry of the interfaces parameter, in which all the fields are optional.
+type interfaceParams struct {
+	NetworkAttachmentDefinitionName string `json:"networkAttachmentDefinitionName"`
+	TrafficGenMacAddress            string `json:"trafficGenMacAddress"`
+	VMUnderTestMacAddress           string `json:"vmUnderTestMacAddress"`
+	PCIAddress                      string `json:"pciAddress"`
+}
+
+// TestInterfaces returns the test interfaces attached to both VMs.
+// Unless set explicitly, these are the east and west interfaces.
+func (c Config) TestInterfaces() []Interface {
+	if len(c.Interfaces) > 0 {
+		return c.Interfaces
+	}
+
+	return []Interface{
+		{
+			Name:                            eastInterfaceName,
+			NetworkAttachmentDefinitionName: c.NetworkAttachmentDefinitionNameEast,
+			TrafficGenMacAddress:            c.TrafficGenEastMacAddress,
+			VMUnderTestMacAddress:           c.VMUnderTestEastMacAddress,
+		},
+		{
+			Name:                            westInterfaceName,
+			NetworkAttachmentDefinitionName: c.NetworkAttachmentDefinitionNameWest,
+			TrafficGenMacAddress:            c.TrafficGenWestMacAddress,
+			VMUnderTestMacAddress:           c.VMUnderTestWestMacAddress,
+		},
+	}
+}
+
+// parseInterfaces parses the interfaces parameter, a JSON list of interfaces.
+// Missing fields are defaulted: interfaces at even positions are connected to the east Network-Attachment-Definition
+// and the ones at odd positions to the west one, and MAC addresses are allocated by position.
+// The PCI addresses are left for KubeVirt to allocate, unless set.
+func parseInterfaces(rawVal string, c Config) ([]Interface, error) {
+	var params []interfaceParams
+	if err := json.Unmarshal([]byte(rawVal), &params); err != nil {
+		return nil, err
+	}
+
+	if !isSupportedInterfacesCount(len(params)) {
+		return nil, fmt.Errorf("expected 2, 4 or 8 interfaces, found %d", len(params))
+	}
+
+	interfaces := make([]Interface, 0, len(params))
+	for idx, param := range params {
+		iface, err := newInterface(idx, param, c)
+		if err != nil {
+			return nil, fmt.Errorf("interface %d: %w", idx, err)
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	if err := validateInterfacesUniqueness(interfaces); err != nil {
+		return nil, err
+	}
+
+	return interfaces, nil
+}
+
+// isSupportedInterfacesCount checks that the interfaces can be paired,
+// and that the traffic generator cores can be evenly split between the pairs.
+func isSupportedInterfacesCount(count int) bool {
+	return count == 2 || count == 4 || count == 8
+}
+
+func newInterface(idx int, param interfaceParams, c Config) (Interface, error) {
+	iface := Interface{
+		Name:                            interfaceName(idx),
+		NetworkAttachmentDefinitionName: param.NetworkAttachmentDefinitionName,
+		PCIAddress:                      param.PCIAddress,
+	}
+
+	if iface.NetworkAttachmentDefinitionName == "" {
+		iface.NetworkAttachmentDefinitionName = c.NetworkAttachmentDefinitionNameEast
+		if idx%2 != 0 {
+			iface.NetworkAttachmentDefinitionName = c.NetworkAttachmentDefinitionNameWest
+		}
+	}
+	if iface.NetworkAttachmentDefinitionName == "" {
+		return Interface{}, ErrInvalidNetworkAttachmentDefinitionName
+	}
+
+	var err error
+	suffixOctet := byte(idx + 1)
+	if iface.TrafficGenMacAddress, err = interfaceMacAddress(param.TrafficGenMacAddress,
+		TrafficGenMACAddressPrefixOctet, suffixOctet); err != nil {
+		return Interface{}, err
+	}
+	if iface.VMUnderTestMacAddress, err = interfaceMacAddress(param.VMUnderTestMacAddress,
+		VMUnderTestMACAddressPrefixOctet, suffixOctet); err != nil {
+		return Interface{}, err
+	}
+
+	pciAddressFormat := regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{2}:[0-9a-f]{2}\.[0-7]$`)
+	if iface.PCIAddress != "" && !pciAddressFormat.MatchString(iface.PCIAddress) {
+		return Interface{}, fmt.Errorf("invalid PCI address %q", iface.PCIAddress)
+	}
+
+	return iface, nil
+}
+
+// interfaceName names the interfaces of the first pair east and west, and the following pairs after their position.
+func interfaceName(idx int) string {
+	name := eastInterfaceName
+	if idx%2 != 0 {
+		name = westInterfaceName
+	}
+
+	if pair := idx / 2; pair > 0 {
+		return fmt.Sprintf("%s-%d", name, pair)
+	}
+	return name
+}
+
+func interfaceMacAddress(rawVal string, prefixOctet, suffixOctet byte) (net.HardwareAddr, error) {
+	if rawVal == "" {
+		return generateMacAddressWithPresetPrefixAndSuffix(prefixOctet, suffixOctet), nil
+	}
+
+	macAddress, err := net.ParseMAC(rawVal)
+	if err != nil {
+		return nil, err
+	}
+	return macAddress, nil
+}
+
+func validateInterfacesUniqueness(interfaces []Interface) error {
+	seenPCIAddresses := map[string]bool{}
+	seenMacAddresses := map[string]bool{}
+	for _, iface := range interfaces {
+		if iface.PCIAddress != "" {
+			if seenPCIAddresses[iface.PCIAddress] {
+				return fmt.Errorf("PCI address %q is used more than once", iface.PCIAddress)
+			}
+			seenPCIAddresses[iface.PCIAddress] = true
+		}
+
+		for _, macAddress := range []string{iface.TrafficGenMacAddress.String(), iface.VMUnderTestMacAddress.String()} {
+			if seenMacAddresses[macAddress] {
+				return fmt.Errorf("MAC address %q is used more than once", macAddress)
+			}
+			seenMacAddresses[macAddress] = true
+		}
+	}
+
+	return nil
+}
+
+// Routed reports whether the traffic is routed through a gateway, instead of being sent directly to the VM under test.
+func (c Config) Routed() bool {
+	return len(c.TrafficGenGatewayMacAddresses) > 0
+}
+
+// GatewayMacAddress returns the MAC address of the gateway the traffic of the given port is sent to,
+// or nil when the traffic is not routed.
+func (c Config) GatewayMacAddress(portIdx int) net.HardwareAddr {
+	switch len(c.TrafficGenGatewayMacAddresses) {
+	case 0:
+		return nil
+	case 1:
+		return c.TrafficGenGatewayMacAddresses[0]
+	default:
+		return c.TrafficGenGatewayMacAddresses[portIdx]
+	}
+}
+
+// parseGatewayMacAddresses parses a comma separated list of MAC addresses,
+// either a single one shared by all the interfaces, or one per interface.
+func parseGatewayMacAddresses(rawVal string, interfacesCount int) ([]net.HardwareAddr, error) {
+	rawAddresses := strings.Split(rawVal, ",")
+	if len(rawAddresses) != 1 && len(rawAddresses) != interfacesCount {
+		return nil, fmt.Errorf("expected either a single MAC address or %d, found %d", interfacesCount, len(rawAddresses))
+	}
+
+	var addresses []net.HardwareAddr
+	for _, rawAddress := range rawAddresses {
+		address, err := net.ParseMAC(strings.TrimSpace(rawAddress))
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+// TrafficGenPortIPAddresses returns the IPv4 address of the given traffic generator port and its default gateway.
+// Unless set by the user, each port resides on its own 10.10.<port+1>0.0/24 subnet.
+func (c Config) TrafficGenPortIPAddresses(portIdx int) (ip, gateway net.IP) {
+	if len(c.TrafficGenPortIPs) > 0 {
+		return c.TrafficGenPortIPs[portIdx], c.TrafficGenPortGateways[portIdx]
+	}
+	subnet := byte((portIdx + 1) * 10)
+	return net.IPv4(10, 10, subnet, 2), net.IPv4(10, 10, subnet, 1)
+}
+
+// setTrafficGenPortIPs sets the traffic generator ports IPs and their gateways, which are set together.
+func setTrafficGenPortIPs(cfg *Config, params map[string]string) error {
+	rawIPs, rawGateways := params[TrafficGenPortIPsParamName], params[TrafficGenPortGatewaysParamName]
+	if rawIPs == "" && rawGateways == "" {
+		return nil
+	}
+	// The traffic generator ports have no IPv4 address in ipv6 IP family
+	if rawIPs == "" || rawGateways == "" || cfg.IPFamily == IPFamilyIPv6 {
+		return ErrIllegalTrafficGenPortIPsCombination
+	}
+
+	interfacesCount := len(cfg.TestInterfaces())
+	ips, err := parseIPv4Addresses(rawIPs, interfacesCount)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTrafficGenPortIPs, err)
+	}
+	gateways, err := parseIPv4Addresses(rawGateways, interfacesCount)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTrafficGenPortGateways, err)
+	}
+
+	seenIPs := map[string]bool{}
+	for portIdx, ip := range ips {
+		if seenIPs[ip.String()] {
+			return fmt.Errorf("%w: IP %q is used more than once", ErrInvalidTrafficGenPortIPs, ip)
+		}
+		seenIPs[ip.String()] = true
+
+		if ip.Equal(gateways[portIdx]) {
+			return fmt.Errorf("%w: port %d IP %q is also its gateway", ErrIllegalTrafficGenPortIPsCombination, portIdx, ip)
+		}
+	}
+
+	cfg.TrafficGenPortIPs = ips
+	cfg.TrafficGenPortGateways = gateways
+	return nil
+}
+
+// parseIPv4Addresses parses a comma separated list of IPv4 addresses, one per test interface.
+func parseIPv4Addresses(rawVal string, interfacesCount int) ([]net.IP, error) {
+	rawAddresses := strings.Split(rawVal, ",")
+	if len(rawAddresses) != interfacesCount {
+		return nil, fmt.Errorf("expected %d IPv4 addresses, found %d", interfacesCount, len(rawAddresses))
+	}
+
+	var addresses []net.IP
+	for _, rawAddress := range rawAddresses {
+		address := net.ParseIP(strings.TrimSpace(rawAddress))
+		if address == nil || address.To4() == nil {
+			return nil, fmt.Errorf("%q is not an IPv4 address", strings.TrimSpace(rawAddress))
+		}
+		addresses = append(addresses, address.To4())
+	}
+
+	return addresses, nil
+}
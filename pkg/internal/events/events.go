@@ -0,0 +1,120 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package events records Kubernetes events on the checkup Pod, so the run milestones and failures
+// are visible with "kubectl describe" and "kubectl get events", without reading the checkup logs.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Component is the source component of the recorded events.
+const Component = "kubevirt-dpdk-checkup"
+
+// The reasons of the recorded events.
+const (
+	ReasonVMICreated       = "VMICreated"
+	ReasonVMIBootTimeout   = "VMIBootTimeout"
+	ReasonTrexNotReady     = "TrexNotReady"
+	ReasonTrafficCompleted = "TrafficCompleted"
+	ReasonCheckupSucceeded = "CheckupSucceeded"
+	ReasonCheckupFailed    = "CheckupFailed"
+)
+
+const recordTimeout = 10 * time.Second
+
+type eventCreator interface {
+	CreateEvent(ctx context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error)
+}
+
+// Recorder records events on the checkup Pod.
+// Recording is best effort: a failure is only logged, as it should not fail the checkup.
+type Recorder struct {
+	client    eventCreator
+	namespace string
+	podName   string
+	podUID    string
+}
+
+// New returns a recorder of events on the given Pod.
+// When the checkup does not run inside a Pod, i.e. the Pod name is empty, no event is recorded.
+func New(client eventCreator, namespace, podName, podUID string) Recorder {
+	return Recorder{
+		client:    client,
+		namespace: namespace,
+		podName:   podName,
+		podUID:    podUID,
+	}
+}
+
+// Normal records an event of a run milestone.
+func (r Recorder) Normal(reason, messageFormat string, args ...any) {
+	r.record(k8scorev1.EventTypeNormal, reason, fmt.Sprintf(messageFormat, args...))
+}
+
+// Warning records an event of a run failure.
+func (r Recorder) Warning(reason, messageFormat string, args ...any) {
+	r.record(k8scorev1.EventTypeWarning, reason, fmt.Sprintf(messageFormat, args...))
+}
+
+func (r Recorder) record(eventType, reason, message string) {
+	if r.client == nil || r.podName == "" {
+		return
+	}
+
+	// The event is recorded with a context of its own, as the failures are recorded after the run context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+
+	if _, err := r.client.CreateEvent(ctx, r.namespace, r.newEvent(eventType, reason, message)); err != nil {
+		log.Printf("failed to record the %q event on Pod %q: %v", reason, r.namespace+"/"+r.podName, err)
+	}
+}
+
+func (r Recorder) newEvent(eventType, reason, message string) *k8scorev1.Event {
+	now := metav1.Now()
+	return &k8scorev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: r.podName + ".",
+			Namespace:    r.namespace,
+		},
+		InvolvedObject: k8scorev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  r.namespace,
+			Name:       r.podName,
+			UID:        types.UID(r.podUID),
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         k8scorev1.EventSource{Component: Component},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/events"
+)
+
+const (
+	testNamespace = "target-ns"
+	testPodName   = "dpdk-checkup-pod"
+	testPodUID    = "0123456789"
+)
+
+func TestRecorderShouldRecordEventsOnThePod(t *testing.T) {
+	testClient := &clientStub{}
+	recorder := events.New(testClient, testNamespace, testPodName, testPodUID)
+
+	recorder.Normal(events.ReasonVMICreated, "VMI %q was created", "target-ns/vmi")
+	recorder.Warning(events.ReasonCheckupFailed, "the checkup failed: %v", errors.New("some error"))
+
+	assert.Len(t, testClient.createdEvents, 2)
+
+	normalEvent := testClient.createdEvents[0]
+	assert.Equal(t, k8scorev1.EventTypeNormal, normalEvent.Type)
+	assert.Equal(t, events.ReasonVMICreated, normalEvent.Reason)
+	assert.Equal(t, `VMI "target-ns/vmi" was created`, normalEvent.Message)
+	assert.Equal(t, testNamespace, normalEvent.Namespace)
+	assert.Equal(t, testPodName+".", normalEvent.GenerateName)
+	assert.Equal(t, k8scorev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  testNamespace,
+		Name:       testPodName,
+		UID:        types.UID(testPodUID),
+	}, normalEvent.InvolvedObject)
+	assert.Equal(t, events.Component, normalEvent.Source.Component)
+
+	warningEvent := testClient.createdEvents[1]
+	assert.Equal(t, k8scorev1.EventTypeWarning, warningEvent.Type)
+	assert.Equal(t, events.ReasonCheckupFailed, warningEvent.Reason)
+	assert.Equal(t, "the checkup failed: some error", warningEvent.Message)
+}
+
+func TestRecorderShouldNotRecordEventsWhenNotRunningInAPod(t *testing.T) {
+	testClient := &clientStub{}
+	recorder := events.New(testClient, testNamespace, "", "")
+
+	recorder.Normal(events.ReasonCheckupSucceeded, "the checkup succeeded")
+
+	assert.Empty(t, testClient.createdEvents)
+}
+
+func TestRecorderShouldIgnoreEventCreationFailures(t *testing.T) {
+	testClient := &clientStub{createFailure: errors.New("forbidden")}
+	recorder := events.New(testClient, testNamespace, testPodName, testPodUID)
+
+	recorder.Warning(events.ReasonVMIBootTimeout, "VMI %q was not ready", "target-ns/vmi")
+
+	assert.Empty(t, testClient.createdEvents)
+}
+
+type clientStub struct {
+	createdEvents []k8scorev1.Event
+	createFailure error
+}
+
+func (cs *clientStub) CreateEvent(_ context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error) {
+	if cs.createFailure != nil {
+		return nil, cs.createFailure
+	}
+
+	event.Namespace = namespace
+	cs.createdEvents = append(cs.createdEvents, *event)
+
+	return event, nil
+}
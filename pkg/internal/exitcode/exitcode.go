@@ -0,0 +1,137 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package exitcode maps the checkup outcome classes to the exit codes of the checkup binary,
+// allowing Job wrappers and scripts to branch on the outcome without reading the results.
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type Code int
+
+const (
+	Success Code = 0
+	// InternalError is reported on any failure that is not classified otherwise.
+	InternalError Code = 1
+	// ConfigError is reported when the checkup configuration is missing or invalid.
+	ConfigError Code = 2
+	// SetupFailure is reported when the cluster infrastructure failed to set up or tear down the checkup.
+	SetupFailure Code = 3
+	// PerformanceFailure is reported when the traffic ran, but its results did not meet the expectations.
+	PerformanceFailure Code = 4
+	// Inconclusive is reported when the traffic could not be run to completion, thus no verdict was reached.
+	Inconclusive Code = 5
+	// Cancelled is reported when the run context was cancelled, or its deadline has passed, before the checkup completed.
+	Cancelled Code = 6
+)
+
+var outcomes = map[Code]string{
+	Success:            "succeeded",
+	InternalError:      "internalError",
+	ConfigError:        "configError",
+	SetupFailure:       "setupFailure",
+	PerformanceFailure: "performanceFailure",
+	Inconclusive:       "inconclusive",
+	Cancelled:          "cancelled",
+}
+
+// Outcome returns the name of the outcome class, as reported in the results.
+func (c Code) Outcome() string {
+	return outcomes[c]
+}
+
+// Error is an error classified by the exit code it should be reported with.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Classify classifies the given error by the given exit code.
+// Errors that are already classified keep their original classification.
+func Classify(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the exit code the given error should be reported with.
+func Of(err error) Code {
+	if err == nil {
+		return Success
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Code
+	}
+
+	return InternalError
+}
+
+// Cancellation is the error of a checkup whose run context was done, along with the phase it was done in.
+type Cancellation struct {
+	Phase string
+	Err   error
+}
+
+func (c *Cancellation) Error() string {
+	return fmt.Sprintf("cancelled during %s: %v", c.Phase, c.Err)
+}
+
+func (c *Cancellation) Unwrap() error {
+	return c.Err
+}
+
+// CheckCancelled returns an error classified as Cancelled when the given context is done, otherwise nil.
+func CheckCancelled(ctx context.Context, phase string) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	return &Error{Code: Cancelled, Err: &Cancellation{Phase: phase, Err: ctx.Err()}}
+}
+
+// CancelledPhase returns the phase the checkup was cancelled in, or an empty string when it was not cancelled.
+func CancelledPhase(err error) string {
+	var cancellation *Cancellation
+	if errors.As(err, &cancellation) {
+		return cancellation.Phase
+	}
+
+	return ""
+}
@@ -0,0 +1,117 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package exitcode_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+)
+
+func TestOf(t *testing.T) {
+	testErr := errors.New("test error")
+
+	t.Run("no error", func(t *testing.T) {
+		assert.Equal(t, exitcode.Success, exitcode.Of(nil))
+	})
+
+	t.Run("unclassified error", func(t *testing.T) {
+		assert.Equal(t, exitcode.InternalError, exitcode.Of(testErr))
+	})
+
+	t.Run("classified error", func(t *testing.T) {
+		assert.Equal(t, exitcode.ConfigError, exitcode.Of(exitcode.Classify(exitcode.ConfigError, testErr)))
+	})
+
+	t.Run("wrapped classified error", func(t *testing.T) {
+		err := fmt.Errorf("run: %w", exitcode.Classify(exitcode.PerformanceFailure, testErr))
+		assert.Equal(t, exitcode.PerformanceFailure, exitcode.Of(err))
+	})
+}
+
+func TestClassify(t *testing.T) {
+	testErr := errors.New("test error")
+
+	t.Run("keeps the error message", func(t *testing.T) {
+		err := exitcode.Classify(exitcode.SetupFailure, testErr)
+		assert.EqualError(t, err, testErr.Error())
+		assert.ErrorIs(t, err, testErr)
+	})
+
+	t.Run("keeps the original classification", func(t *testing.T) {
+		err := exitcode.Classify(exitcode.SetupFailure, exitcode.Classify(exitcode.Inconclusive, testErr))
+		assert.Equal(t, exitcode.Inconclusive, exitcode.Of(err))
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		assert.NoError(t, exitcode.Classify(exitcode.SetupFailure, nil))
+	})
+}
+
+func TestCheckCancelled(t *testing.T) {
+	t.Run("context is not done", func(t *testing.T) {
+		assert.NoError(t, exitcode.CheckCancelled(context.Background(), "warm-up"))
+	})
+
+	t.Run("context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := exitcode.CheckCancelled(ctx, "warm-up")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, exitcode.Cancelled, exitcode.Of(err))
+		assert.Equal(t, "warm-up", exitcode.CancelledPhase(err))
+		assert.EqualError(t, err, "cancelled during warm-up: context canceled")
+	})
+
+	t.Run("context deadline has passed", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+		defer cancel()
+
+		err := exitcode.CheckCancelled(ctx, "traffic iteration 1/1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, exitcode.Cancelled, exitcode.Of(err))
+	})
+
+	t.Run("cancellation keeps its classification when classified again", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := exitcode.Classify(exitcode.Inconclusive, fmt.Errorf("run: %w", exitcode.CheckCancelled(ctx, "warm-up")))
+		assert.Equal(t, exitcode.Cancelled, exitcode.Of(err))
+		assert.Equal(t, "warm-up", exitcode.CancelledPhase(err))
+	})
+}
+
+func TestCancelledPhaseShouldBeEmptyWhenNotCancelled(t *testing.T) {
+	assert.Empty(t, exitcode.CancelledPhase(nil))
+	assert.Empty(t, exitcode.CancelledPhase(exitcode.Classify(exitcode.Inconclusive, errors.New("test error"))))
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, "succeeded", exitcode.Success.Outcome())
+	assert.Equal(t, "cancelled", exitcode.Cancelled.Outcome())
+}
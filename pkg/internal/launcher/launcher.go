@@ -25,9 +25,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
 )
 
+// CancelledTeardownTimeout bounds the teardown of a cancelled run.
+// It fits in the default termination grace period of the checkup Pod, 30 seconds.
+const CancelledTeardownTimeout = 25 * time.Second
+
 type checkup interface {
 	Setup(ctx context.Context) error
 	Run(ctx context.Context) error
@@ -51,8 +56,17 @@ func New(checkup checkup, reporter reporter) Launcher {
 	}
 }
 
+// Run runs the checkup and reports its status.
+// The returned error is classified by the exit code of the first failure.
 func (l Launcher) Run(ctx context.Context) (runErr error) {
 	var runStatus status.Status
+	var firstFailure error
+	fail := func(err error) {
+		runStatus.FailureReason = append(runStatus.FailureReason, err.Error())
+		if firstFailure == nil {
+			firstFailure = err
+		}
+	}
 	runStatus.StartTimestamp = time.Now()
 
 	if err := l.reporter.Report(runStatus); err != nil {
@@ -62,25 +76,34 @@ func (l Launcher) Run(ctx context.Context) (runErr error) {
 	defer func() {
 		runStatus.CompletionTimestamp = time.Now()
 		runStatus.Results = l.checkup.Results()
+		runStatus.Results.Outcome = exitcode.Of(firstFailure).Outcome()
+		runStatus.Results.CancelledPhase = exitcode.CancelledPhase(firstFailure)
 		if err := l.reporter.Report(runStatus); err != nil {
-			runStatus.FailureReason = append(runStatus.FailureReason, err.Error())
+			fail(err)
 		}
-		runErr = failureReason(runStatus)
+		runErr = exitcode.Classify(exitcode.Of(firstFailure), failureReason(runStatus))
 	}()
 
 	if err := l.checkup.Setup(ctx); err != nil {
-		runStatus.FailureReason = append(runStatus.FailureReason, err.Error())
+		fail(exitcode.Classify(exitcode.SetupFailure, err))
 		return err
 	}
 
 	defer func() {
-		if err := l.checkup.Teardown(ctx); err != nil {
-			runStatus.FailureReason = append(runStatus.FailureReason, err.Error())
+		teardownCtx := ctx
+		if ctx.Err() != nil {
+			// The run was cancelled or its deadline has passed, the resources are torn down best-effort regardless.
+			var cancel context.CancelFunc
+			teardownCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), CancelledTeardownTimeout)
+			defer cancel()
+		}
+		if err := l.checkup.Teardown(teardownCtx); err != nil {
+			fail(exitcode.Classify(exitcode.SetupFailure, err))
 		}
 	}()
 
 	if err := l.checkup.Run(ctx); err != nil {
-		runStatus.FailureReason = append(runStatus.FailureReason, err.Error())
+		fail(err)
 		return err
 	}
 
@@ -23,9 +23,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	assert "github.com/stretchr/testify/require"
 
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/launcher"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
 )
@@ -105,10 +107,85 @@ func TestLauncherRunShouldFailWhen(t *testing.T) {
 	})
 }
 
+func TestLauncherRunShouldClassifyTheFailure(t *testing.T) {
+	t.Run("by the failed setup", func(t *testing.T) {
+		testLauncher := launcher.New(checkupStub{failSetup: errSetup}, &reporterStub{})
+		assert.Equal(t, exitcode.SetupFailure, exitcode.Of(testLauncher.Run(context.Background())))
+	})
+
+	t.Run("by the failed run, when teardown fails as well", func(t *testing.T) {
+		testLauncher := launcher.New(
+			checkupStub{failRun: exitcode.Classify(exitcode.PerformanceFailure, errRun), failTeardown: errTeardown},
+			&reporterStub{},
+		)
+		err := testLauncher.Run(context.Background())
+		assert.ErrorContains(t, err, errTeardown.Error())
+		assert.Equal(t, exitcode.PerformanceFailure, exitcode.Of(err))
+	})
+
+	t.Run("by the failed teardown", func(t *testing.T) {
+		testLauncher := launcher.New(checkupStub{failTeardown: errTeardown}, &reporterStub{})
+		assert.Equal(t, exitcode.SetupFailure, exitcode.Of(testLauncher.Run(context.Background())))
+	})
+
+	t.Run("as an internal error when the report fails", func(t *testing.T) {
+		testLauncher := launcher.New(checkupStub{}, &reporterStub{failReport: errReport, failOnSecondReport: true})
+		assert.Equal(t, exitcode.InternalError, exitcode.Of(testLauncher.Run(context.Background())))
+	})
+}
+
+func TestLauncherRunShouldReportTheOutcome(t *testing.T) {
+	t.Run("when the checkup succeeds", func(t *testing.T) {
+		testReporter := &reporterStub{}
+		testLauncher := launcher.New(checkupStub{}, testReporter)
+		assert.NoError(t, testLauncher.Run(context.Background()))
+		assert.Equal(t, "succeeded", testReporter.lastStatus.Results.Outcome)
+		assert.Empty(t, testReporter.lastStatus.Results.CancelledPhase)
+	})
+
+	t.Run("when the checkup is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		testReporter := &reporterStub{}
+		testLauncher := launcher.New(
+			checkupStub{failRun: exitcode.Classify(exitcode.Inconclusive, exitcode.CheckCancelled(ctx, "warm-up"))},
+			testReporter,
+		)
+		assert.Equal(t, exitcode.Cancelled, exitcode.Of(testLauncher.Run(ctx)))
+		assert.Equal(t, "cancelled", testReporter.lastStatus.Results.Outcome)
+		assert.Equal(t, "warm-up", testReporter.lastStatus.Results.CancelledPhase)
+	})
+}
+
+func TestLauncherRunShouldTeardownWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	teardown := &teardownCall{}
+	testLauncher := launcher.New(
+		checkupStub{failRun: exitcode.CheckCancelled(ctx, "warm-up"), teardown: teardown},
+		&reporterStub{},
+	)
+	assert.Equal(t, exitcode.Cancelled, exitcode.Of(testLauncher.Run(ctx)))
+
+	assert.NoError(t, teardown.ctxErr)
+	assert.True(t, teardown.hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(launcher.CancelledTeardownTimeout), teardown.deadline, time.Second)
+}
+
 type checkupStub struct {
 	failSetup    error
 	failRun      error
 	failTeardown error
+	teardown     *teardownCall
+}
+
+// teardownCall records the state of the context the teardown was called with.
+type teardownCall struct {
+	ctxErr      error
+	deadline    time.Time
+	hasDeadline bool
 }
 
 func (cs checkupStub) Setup(_ context.Context) error {
@@ -119,7 +196,11 @@ func (cs checkupStub) Run(_ context.Context) error {
 	return cs.failRun
 }
 
-func (cs checkupStub) Teardown(_ context.Context) error {
+func (cs checkupStub) Teardown(ctx context.Context) error {
+	if cs.teardown != nil {
+		cs.teardown.ctxErr = ctx.Err()
+		cs.teardown.deadline, cs.teardown.hasDeadline = ctx.Deadline()
+	}
 	return cs.failTeardown
 }
 
@@ -134,10 +215,12 @@ type reporterStub struct {
 	// then to update the checkup results.
 	// Use this flag to cause the second report to fail.
 	failOnSecondReport bool
+	lastStatus         status.Status
 }
 
-func (rs *reporterStub) Report(_ status.Status) error {
+func (rs *reporterStub) Report(checkupStatus status.Status) error {
 	rs.reportCalls++
+	rs.lastStatus = checkupStatus
 	if rs.failOnSecondReport && rs.reportCalls == 2 {
 		return rs.failReport
 	} else if !rs.failOnSecondReport {
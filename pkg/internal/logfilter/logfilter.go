@@ -0,0 +1,70 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package logfilter filters the checkup logs, so they neither leak secrets nor hold the console control sequences,
+// which make the console output captured in the logs unreadable.
+package logfilter
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces the secrets in the filtered text.
+const Mask = "******"
+
+// controlSequences matches the ANSI CSI and OSC escape sequences, the other two characters escape sequences,
+// and the control characters other than tabs and newlines.
+var controlSequences = regexp.MustCompile(
+	`\x1b\[[0-?]*[ -/]*[@-~]` + `|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)` + `|\x1b[@-Z\\-_]` + `|[\x00-\x08\x0b-\x1f\x7f]`,
+)
+
+// Filter strips the escape sequences and control characters from the given text, and masks the given secrets.
+// Console line endings are kept as plain newlines.
+func Filter(text string, secrets ...string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = controlSequences.ReplaceAllString(text, "")
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, Mask)
+		}
+	}
+
+	return text
+}
+
+// Writer filters the text written to it, before writing it to the underlying writer.
+// As the standard logger writes each message at once, a Writer set as its output filters whole messages.
+type Writer struct {
+	w       io.Writer
+	secrets []string
+}
+
+func NewWriter(w io.Writer, secrets ...string) *Writer {
+	return &Writer{w: w, secrets: secrets}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, Filter(string(p), w.secrets...)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,81 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package logfilter_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/logfilter"
+)
+
+const secret = "s3cr3t"
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		description string
+		text        string
+		expected    string
+	}{
+		{
+			description: "plain text is kept",
+			text:        "testpmd stats:\n\tRX-packets: 10",
+			expected:    "testpmd stats:\n\tRX-packets: 10",
+		},
+		{
+			description: "secret is masked",
+			text:        "Password: s3cr3t\r\n[root@localhost ~]# ",
+			expected:    "Password: " + logfilter.Mask + "\n[root@localhost ~]# ",
+		},
+		{
+			description: "ANSI color and cursor sequences are stripped",
+			text:        "\x1b[1;32mOK\x1b[0m \x1b[2K\x1b[?2004h[root@localhost ~]# ",
+			expected:    "OK [root@localhost ~]# ",
+		},
+		{
+			description: "OSC sequences are stripped",
+			text:        "\x1b]0;root@localhost:~\x07[root@localhost ~]# ",
+			expected:    "[root@localhost ~]# ",
+		},
+		{
+			description: "control characters are stripped",
+			text:        "login:\x00 \x08root\r\rdone\x1b",
+			expected:    "login: rootdone",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, logfilter.Filter(testCase.text, "", secret))
+		})
+	}
+}
+
+func TestWriterFiltersTheLogMessages(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger := log.New(logfilter.NewWriter(output, secret), "", 0)
+
+	logger.Printf("Login attempt failed: \x1b[31m%s\x1b[0m", "Password: s3cr3t")
+
+	assert.Equal(t, "Login attempt failed: Password: "+logfilter.Mask+"\n", output.String())
+}
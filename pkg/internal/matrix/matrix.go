@@ -0,0 +1,231 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package matrix runs the checkup over each pair of the selected nodes, validating the DPDK fabric of the whole
+// node group in a single run.
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+type nodeLister interface {
+	ListNodes(ctx context.Context, labelSelector string) (*k8scorev1.NodeList, error)
+}
+
+// Checkup is the checkup run on a single node pair.
+type Checkup interface {
+	Setup(ctx context.Context) error
+	Run(ctx context.Context) error
+	Teardown(ctx context.Context) error
+	Results() status.Results
+}
+
+// Pair is a pair of nodes, the traffic generator is placed on one and the VM under test on the other.
+type Pair struct {
+	TrafficGenNodeName  string
+	VMUnderTestNodeName string
+}
+
+// ID identifies the pair in the results, e.g. "worker1-to-worker2".
+func (p Pair) ID() string {
+	return p.TrafficGenNodeName + "-to-" + p.VMUnderTestNodeName
+}
+
+type Matrix struct {
+	client     nodeLister
+	params     config.Config
+	newCheckup func(config.Config) Checkup
+	pairs      []Pair
+	current    Checkup
+	results    status.Results
+}
+
+// New returns a matrix over the nodes matching the matrix node selector.
+// The checkup of each pair is created by newCheckup, given a copy of the config targeting the pair nodes.
+func New(client nodeLister, params config.Config, newCheckup func(config.Config) Checkup) *Matrix {
+	return &Matrix{
+		client:     client,
+		params:     params,
+		newCheckup: newCheckup,
+	}
+}
+
+// Setup pairs each of the nodes matching the matrix node selector with each of the other nodes, in both roles.
+func (m *Matrix) Setup(ctx context.Context) error {
+	const errMessagePrefix = "setup"
+
+	nodeList, err := m.client.ListNodes(ctx, m.params.MatrixNodeSelector)
+	if err != nil {
+		return fmt.Errorf("%s: failed to list the nodes matching %q: %w", errMessagePrefix, m.params.MatrixNodeSelector, err)
+	}
+
+	var nodeNames []string
+	for i := range nodeList.Items {
+		nodeNames = append(nodeNames, nodeList.Items[i].Name)
+	}
+	sort.Strings(nodeNames)
+
+	const minNodes = 2
+	if len(nodeNames) < minNodes {
+		return exitcode.Classify(exitcode.ConfigError, fmt.Errorf("%s: %d nodes match %q, at least %d are required",
+			errMessagePrefix, len(nodeNames), m.params.MatrixNodeSelector, minNodes))
+	}
+
+	m.pairs = nodePairs(nodeNames)
+	log.Printf("Checking %d node pairs of the nodes matching %q: %s", len(m.pairs), m.params.MatrixNodeSelector,
+		strings.Join(nodeNames, ", "))
+
+	return nil
+}
+
+// nodePairs returns the ordered pairs of the given distinct nodes.
+func nodePairs(nodeNames []string) []Pair {
+	var pairs []Pair
+	for _, trafficGenNodeName := range nodeNames {
+		for _, vmUnderTestNodeName := range nodeNames {
+			if trafficGenNodeName != vmUnderTestNodeName {
+				pairs = append(pairs, Pair{TrafficGenNodeName: trafficGenNodeName, VMUnderTestNodeName: vmUnderTestNodeName})
+			}
+		}
+	}
+	return pairs
+}
+
+// Run checks the pairs one after the other, reporting each of them as a scenario.
+// It fails when any of the pairs has failed, once all of them were checked.
+func (m *Matrix) Run(ctx context.Context) error {
+	var failedPairs []string
+	var firstErr error
+	for i, pair := range m.pairs {
+		log.Printf("Checking node pair %d/%d: traffic generator on %q, VM under test on %q...",
+			i+1, len(m.pairs), pair.TrafficGenNodeName, pair.VMUnderTestNodeName)
+
+		scenario, err := m.runPair(ctx, pair)
+		m.results.Scenarios = append(m.results.Scenarios, scenario)
+		addCounters(&m.results, scenario.Counters)
+		if ctx.Err() != nil {
+			// The run was cancelled, the pairs left are not checked.
+			if err == nil {
+				err = exitcode.CheckCancelled(ctx, "node pair "+pair.ID())
+			}
+			return err
+		}
+		if err != nil {
+			log.Printf("node pair %s failed: %v", pair.ID(), err)
+			failedPairs = append(failedPairs, fmt.Sprintf("%s: %v", pair.ID(), err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if len(failedPairs) > 0 {
+		return exitcode.Classify(exitcode.Of(firstErr), fmt.Errorf("%d out of %d node pairs failed: %s",
+			len(failedPairs), len(m.pairs), strings.Join(failedPairs, "; ")))
+	}
+
+	return nil
+}
+
+func (m *Matrix) runPair(ctx context.Context, pair Pair) (status.ScenarioResults, error) {
+	pairConfig := m.params
+	pairConfig.TrafficGenTargetNodeName = pair.TrafficGenNodeName
+	pairConfig.VMUnderTestTargetNodeName = pair.VMUnderTestNodeName
+	pairConfig.TestDuration = m.params.MatrixTestDuration
+	pairCheckup := m.newCheckup(pairConfig)
+
+	err := m.checkPair(ctx, pairCheckup)
+
+	scenario := status.ScenarioResults{
+		ID:        pair.ID(),
+		Succeeded: err == nil,
+		Counters:  packetCounters(pairCheckup.Results()),
+	}
+	if err != nil {
+		scenario.FailureReason = err.Error()
+	}
+
+	return scenario, err
+}
+
+func (m *Matrix) checkPair(ctx context.Context, pairCheckup Checkup) error {
+	if err := pairCheckup.Setup(ctx); err != nil {
+		return exitcode.Classify(exitcode.SetupFailure, err)
+	}
+
+	m.current = pairCheckup
+	runErr := pairCheckup.Run(ctx)
+	if ctx.Err() != nil {
+		// The pair is left to Teardown, which is given a fresh context once the run is cancelled.
+		return runErr
+	}
+
+	m.current = nil
+	if err := pairCheckup.Teardown(ctx); err != nil && runErr == nil {
+		return exitcode.Classify(exitcode.SetupFailure, err)
+	}
+
+	return runErr
+}
+
+// Teardown tears down the pair that was being checked when the run was cancelled, if any.
+// The other pairs are torn down by Run, once they were checked.
+func (m *Matrix) Teardown(ctx context.Context) error {
+	if m.current == nil {
+		return nil
+	}
+
+	return m.current.Teardown(ctx)
+}
+
+func (m *Matrix) Results() status.Results {
+	return m.results
+}
+
+func packetCounters(results status.Results) status.PacketCounters {
+	return status.PacketCounters{
+		TrafficGenSentPackets:        results.TrafficGenSentPackets,
+		TrafficGenOutputErrorPackets: results.TrafficGenOutputErrorPackets,
+		TrafficGenInputErrorPackets:  results.TrafficGenInputErrorPackets,
+		VMUnderTestReceivedPackets:   results.VMUnderTestReceivedPackets,
+		VMUnderTestRxDroppedPackets:  results.VMUnderTestRxDroppedPackets,
+		VMUnderTestTxDroppedPackets:  results.VMUnderTestTxDroppedPackets,
+	}
+}
+
+// addCounters sums the counters of the pairs into the matrix results.
+func addCounters(results *status.Results, counters status.PacketCounters) {
+	results.TrafficGenSentPackets += counters.TrafficGenSentPackets
+	results.TrafficGenOutputErrorPackets += counters.TrafficGenOutputErrorPackets
+	results.TrafficGenInputErrorPackets += counters.TrafficGenInputErrorPackets
+	results.VMUnderTestReceivedPackets += counters.VMUnderTestReceivedPackets
+	results.VMUnderTestRxDroppedPackets += counters.VMUnderTestRxDroppedPackets
+	results.VMUnderTestTxDroppedPackets += counters.VMUnderTestTxDroppedPackets
+}
@@ -0,0 +1,182 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package matrix_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/matrix"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+const (
+	testNodeSelector       = "node-role.kubernetes.io/worker-dpdk="
+	testMatrixTestDuration = 30 * time.Second
+	testSentPackets        = 100
+)
+
+func TestMatrixShouldCheckEachNodePair(t *testing.T) {
+	factory := &checkupFactory{}
+	testMatrix := matrix.New(nodeListerStub{nodeNames: []string{"worker2", "worker1", "worker3"}}, newTestConfig(), factory.new)
+
+	assert.NoError(t, testMatrix.Setup(context.Background()))
+	assert.NoError(t, testMatrix.Run(context.Background()))
+	assert.NoError(t, testMatrix.Teardown(context.Background()))
+
+	expectedPairs := []matrix.Pair{
+		{TrafficGenNodeName: "worker1", VMUnderTestNodeName: "worker2"},
+		{TrafficGenNodeName: "worker1", VMUnderTestNodeName: "worker3"},
+		{TrafficGenNodeName: "worker2", VMUnderTestNodeName: "worker1"},
+		{TrafficGenNodeName: "worker2", VMUnderTestNodeName: "worker3"},
+		{TrafficGenNodeName: "worker3", VMUnderTestNodeName: "worker1"},
+		{TrafficGenNodeName: "worker3", VMUnderTestNodeName: "worker2"},
+	}
+	assert.Len(t, factory.checkups, len(expectedPairs))
+	results := testMatrix.Results()
+	for i, pair := range expectedPairs {
+		pairCheckup := factory.checkups[i]
+		assert.Equal(t, pair.TrafficGenNodeName, pairCheckup.config.TrafficGenTargetNodeName)
+		assert.Equal(t, pair.VMUnderTestNodeName, pairCheckup.config.VMUnderTestTargetNodeName)
+		assert.Equal(t, testMatrixTestDuration, pairCheckup.config.TestDuration)
+		assert.True(t, pairCheckup.tornDown)
+
+		assert.Equal(t, pair.ID(), results.Scenarios[i].ID)
+		assert.True(t, results.Scenarios[i].Succeeded)
+		assert.Equal(t, int64(testSentPackets), results.Scenarios[i].Counters.TrafficGenSentPackets)
+	}
+	assert.Equal(t, int64(len(expectedPairs)*testSentPackets), results.TrafficGenSentPackets)
+}
+
+func TestMatrixRunShouldCheckAllPairsAndFailWhenAPairFails(t *testing.T) {
+	errRun := exitcode.Classify(exitcode.PerformanceFailure, errors.New("not all generated packets had reached VM-Under-Test"))
+	factory := &checkupFactory{failRunOnVMUnderTestNode: "worker1", failRun: errRun}
+	testMatrix := matrix.New(nodeListerStub{nodeNames: []string{"worker1", "worker2"}}, newTestConfig(), factory.new)
+
+	assert.NoError(t, testMatrix.Setup(context.Background()))
+	err := testMatrix.Run(context.Background())
+	assert.ErrorContains(t, err, "1 out of 2 node pairs failed: worker2-to-worker1: "+errRun.Error())
+	assert.Equal(t, exitcode.PerformanceFailure, exitcode.Of(err))
+
+	scenarios := testMatrix.Results().Scenarios
+	assert.Len(t, scenarios, 2)
+	assert.True(t, scenarios[0].Succeeded)
+	assert.False(t, scenarios[1].Succeeded)
+	assert.Equal(t, errRun.Error(), scenarios[1].FailureReason)
+	assert.True(t, factory.checkups[1].tornDown)
+}
+
+func TestMatrixSetupShouldFailWhenLessThanTwoNodesMatch(t *testing.T) {
+	testMatrix := matrix.New(nodeListerStub{nodeNames: []string{"worker1"}}, newTestConfig(), (&checkupFactory{}).new)
+
+	err := testMatrix.Setup(context.Background())
+	assert.ErrorContains(t, err, "1 nodes match")
+	assert.Equal(t, exitcode.ConfigError, exitcode.Of(err))
+}
+
+func TestMatrixShouldLeaveTheCancelledPairToTeardown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	factory := &checkupFactory{onRun: cancel}
+	testMatrix := matrix.New(nodeListerStub{nodeNames: []string{"worker1", "worker2"}}, newTestConfig(), factory.new)
+
+	assert.NoError(t, testMatrix.Setup(ctx))
+	assert.Equal(t, exitcode.Cancelled, exitcode.Of(testMatrix.Run(ctx)))
+	assert.Len(t, factory.checkups, 1)
+	assert.False(t, factory.checkups[0].tornDown)
+
+	assert.NoError(t, testMatrix.Teardown(context.Background()))
+	assert.True(t, factory.checkups[0].tornDown)
+}
+
+func newTestConfig() config.Config {
+	return config.Config{
+		MatrixNodeSelector: testNodeSelector,
+		MatrixTestDuration: testMatrixTestDuration,
+		TestDuration:       config.TestDurationDefault,
+	}
+}
+
+type nodeListerStub struct {
+	nodeNames []string
+}
+
+func (n nodeListerStub) ListNodes(_ context.Context, labelSelector string) (*k8scorev1.NodeList, error) {
+	if labelSelector != testNodeSelector {
+		return nil, errors.New("unexpected label selector")
+	}
+
+	nodeList := &k8scorev1.NodeList{}
+	for _, nodeName := range n.nodeNames {
+		nodeList.Items = append(nodeList.Items, k8scorev1.Node{ObjectMeta: k8smetav1.ObjectMeta{Name: nodeName}})
+	}
+	return nodeList, nil
+}
+
+type checkupFactory struct {
+	failRunOnVMUnderTestNode string
+	failRun                  error
+	onRun                    func()
+	checkups                 []*checkupStub
+}
+
+func (f *checkupFactory) new(pairConfig config.Config) matrix.Checkup {
+	pairCheckup := &checkupStub{config: pairConfig, onRun: f.onRun}
+	if pairConfig.VMUnderTestTargetNodeName == f.failRunOnVMUnderTestNode {
+		pairCheckup.failRun = f.failRun
+	}
+	f.checkups = append(f.checkups, pairCheckup)
+	return pairCheckup
+}
+
+type checkupStub struct {
+	config   config.Config
+	failRun  error
+	onRun    func()
+	tornDown bool
+}
+
+func (c *checkupStub) Setup(_ context.Context) error {
+	return nil
+}
+
+func (c *checkupStub) Run(_ context.Context) error {
+	if c.onRun != nil {
+		c.onRun()
+	}
+	return c.failRun
+}
+
+func (c *checkupStub) Teardown(_ context.Context) error {
+	c.tornDown = true
+	return nil
+}
+
+func (c *checkupStub) Results() status.Results {
+	return status.Results{TrafficGenSentPackets: testSentPackets, VMUnderTestReceivedPackets: testSentPackets}
+}
@@ -0,0 +1,59 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"log"
+	"os"
+	"path"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// TimelineOpenMetricsFileName is the name of the file the checkup phases timings are exported to, in the artifacts directory.
+const TimelineOpenMetricsFileName = "timeline.prom"
+
+const artifactFileMode = 0o644
+
+// ArtifactsReporter exports the final checkup phases timings to the artifacts directory, in the OpenMetrics text format.
+// The export is best-effort, as it does not affect the checkup verdict.
+type ArtifactsReporter struct {
+	artifactsDir string
+}
+
+func NewArtifacts(artifactsDir string) *ArtifactsReporter {
+	return &ArtifactsReporter{artifactsDir: artifactsDir}
+}
+
+func (r *ArtifactsReporter) Report(checkupStatus status.Status) error {
+	if checkupStatus.CompletionTimestamp.IsZero() || len(checkupStatus.Results.Timeline) == 0 {
+		return nil
+	}
+
+	fileFullPath := path.Join(r.artifactsDir, TimelineOpenMetricsFileName)
+	content := formatTimelineOpenMetrics(checkupStatus.Results.Timeline)
+	if err := os.WriteFile(fileFullPath, []byte(content), artifactFileMode); err != nil {
+		log.Printf("failed to export %q: %v", fileFullPath, err)
+		return nil
+	}
+	log.Printf("exported %q", fileFullPath)
+
+	return nil
+}
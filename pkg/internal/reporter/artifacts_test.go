@@ -0,0 +1,68 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/reporter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+func TestArtifactsReporterShouldNotExportOnStart(t *testing.T) {
+	artifactsDir := t.TempDir()
+	testReporter := reporter.NewArtifacts(artifactsDir)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+
+	assert.NoError(t, testReporter.Report(checkupStatus))
+	assert.NoFileExists(t, path.Join(artifactsDir, reporter.TimelineOpenMetricsFileName))
+}
+
+func TestArtifactsReporterShouldExportTheTimelineOpenMetrics(t *testing.T) {
+	artifactsDir := t.TempDir()
+	testReporter := reporter.NewArtifacts(artifactsDir)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Date(2023, 8, 20, 10, 0, 0, 0, time.UTC)
+	checkupStatus.CompletionTimestamp = checkupStatus.StartTimestamp.Add(10 * time.Minute)
+	checkupStatus.Results = status.Results{
+		Timeline: status.Timeline{
+			{Phase: "VMIs boot", Start: checkupStatus.StartTimestamp, End: checkupStatus.StartTimestamp.Add(95 * time.Second)},
+			{
+				Phase: "traffic iteration 1/1",
+				Start: checkupStatus.StartTimestamp.Add(2 * time.Minute),
+				End:   checkupStatus.StartTimestamp.Add(7 * time.Minute),
+			},
+		},
+	}
+
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	content, err := os.ReadFile(path.Join(artifactsDir, reporter.TimelineOpenMetricsFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTimelineOpenMetrics, string(content))
+}
@@ -0,0 +1,78 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+type statusReporter interface {
+	Report(status.Status) error
+}
+
+type progressReporter interface {
+	ReportProgress(status.Progress) error
+}
+
+// MultiReporter reports the checkup status to all of the given reporters, in order.
+// A failing reporter does not prevent the following ones from reporting.
+type MultiReporter struct {
+	reporters []statusReporter
+}
+
+func NewMulti(reporters ...statusReporter) *MultiReporter {
+	return &MultiReporter{reporters: reporters}
+}
+
+func (r *MultiReporter) Report(checkupStatus status.Status) error {
+	var reportErrors []string
+	for _, reporter := range r.reporters {
+		if err := reporter.Report(checkupStatus); err != nil {
+			reportErrors = append(reportErrors, err.Error())
+		}
+	}
+
+	if len(reportErrors) > 0 {
+		return errors.New(strings.Join(reportErrors, ", "))
+	}
+
+	return nil
+}
+
+// ReportProgress reports the checkup progress to the given reporters that support progress reporting.
+func (r *MultiReporter) ReportProgress(progress status.Progress) error {
+	var reportErrors []string
+	for _, reporter := range r.reporters {
+		if progressReporter, ok := reporter.(progressReporter); ok {
+			if err := progressReporter.ReportProgress(progress); err != nil {
+				reportErrors = append(reportErrors, err.Error())
+			}
+		}
+	}
+
+	if len(reportErrors) > 0 {
+		return errors.New(strings.Join(reportErrors, ", "))
+	}
+
+	return nil
+}
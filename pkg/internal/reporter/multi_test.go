@@ -0,0 +1,60 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/reporter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+func TestMultiReporterShouldReportToAll(t *testing.T) {
+	first, second := &reporterStub{}, &reporterStub{}
+	testReporter := reporter.NewMulti(first, second)
+
+	assert.NoError(t, testReporter.Report(status.Status{}))
+	assert.Equal(t, 1, first.reportCalls)
+	assert.Equal(t, 1, second.reportCalls)
+}
+
+func TestMultiReporterShouldAggregateFailures(t *testing.T) {
+	errFirst, errSecond := errors.New("first report error"), errors.New("second report error")
+	first, second := &reporterStub{reportErr: errFirst}, &reporterStub{reportErr: errSecond}
+	testReporter := reporter.NewMulti(first, second)
+
+	err := testReporter.Report(status.Status{})
+	assert.ErrorContains(t, err, errFirst.Error())
+	assert.ErrorContains(t, err, errSecond.Error())
+	assert.Equal(t, 1, second.reportCalls)
+}
+
+type reporterStub struct {
+	reportCalls int
+	reportErr   error
+}
+
+func (rs *reporterStub) Report(_ status.Status) error {
+	rs.reportCalls++
+	return rs.reportErr
+}
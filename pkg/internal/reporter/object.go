@@ -0,0 +1,146 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"context"
+	"fmt"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+const (
+	ConfigMapKind = "ConfigMap"
+	SecretKind    = "Secret"
+)
+
+// ObjectReporter persists the final checkup status into a user-owned ConfigMap or Secret,
+// which is created when it does not exist.
+// Unlike the kiagnose ConfigMap, this object is not expected to be recycled between checkup runs.
+type ObjectReporter struct {
+	client      kubernetes.Interface
+	namespace   string
+	kind        string
+	name        string
+	jsonResults bool
+}
+
+func NewObject(c kubernetes.Interface, namespace, kind, name string, jsonResults bool) *ObjectReporter {
+	return &ObjectReporter{
+		client:      c,
+		namespace:   namespace,
+		kind:        kind,
+		name:        name,
+		jsonResults: jsonResults,
+	}
+}
+
+func (r *ObjectReporter) Report(checkupStatus status.Status) error {
+	if checkupStatus.CompletionTimestamp.IsZero() {
+		return nil
+	}
+
+	data, err := formatCompletedStatus(checkupStatus, r.jsonResults)
+	if err != nil {
+		return err
+	}
+
+	switch r.kind {
+	case ConfigMapKind:
+		err = r.reportToConfigMap(data)
+	case SecretKind:
+		err = r.reportToSecret(data)
+	default:
+		err = fmt.Errorf("unsupported results object kind %q", r.kind)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write results to %s %s/%s: %w", r.kind, r.namespace, r.name, err)
+	}
+
+	return nil
+}
+
+func (r *ObjectReporter) reportToConfigMap(data map[string]string) error {
+	configMaps := r.client.CoreV1().ConfigMaps(r.namespace)
+
+	configMap, err := configMaps.Get(context.Background(), r.name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		newConfigMap := &k8scorev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.name},
+			Data:       data,
+		}
+		_, err = configMaps.Create(context.Background(), newConfigMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	for k, v := range data {
+		configMap.Data[k] = v
+	}
+
+	_, err = configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{})
+	return err
+}
+
+func (r *ObjectReporter) reportToSecret(data map[string]string) error {
+	secrets := r.client.CoreV1().Secrets(r.namespace)
+
+	secret, err := secrets.Get(context.Background(), r.name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		newSecret := &k8scorev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.name},
+			Data:       toBytesMap(data),
+		}
+		_, err = secrets.Create(context.Background(), newSecret, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for k, v := range toBytesMap(data) {
+		secret.Data[k] = v
+	}
+
+	_, err = secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+	return err
+}
+
+func toBytesMap(data map[string]string) map[string][]byte {
+	bytesData := make(map[string][]byte, len(data))
+	for k, v := range data {
+		bytesData[k] = []byte(v)
+	}
+	return bytesData
+}
@@ -0,0 +1,110 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/reporter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+const testResultsObjectName = "dpdk-checkup-results"
+
+func TestObjectReporterShouldNotReportOnStart(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	testReporter := reporter.NewObject(fakeClient, testNamespace, reporter.ConfigMapKind, testResultsObjectName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	configMaps, err := fakeClient.CoreV1().ConfigMaps(testNamespace).List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps.Items)
+}
+
+func TestObjectReporterShouldReportToConfigMap(t *testing.T) {
+	t.Run("when it does not exist", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset()
+		testReporter := reporter.NewObject(fakeClient, testNamespace, reporter.ConfigMapKind, testResultsObjectName, false)
+
+		checkupStatus := newCompletedStatus()
+		checkupStatus.Results = status.Results{TrafficGenSentPackets: 100, VMUnderTestReceivedPackets: 100}
+		assert.NoError(t, testReporter.Report(checkupStatus))
+
+		configMap, err := fakeClient.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), testResultsObjectName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, createExpectedReporterConfigmapDataWithResults(true, checkupStatus), configMap.Data)
+	})
+
+	t.Run("when it already exists", func(t *testing.T) {
+		const userKey = "some-user-key"
+		existingConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: testResultsObjectName, Namespace: testNamespace},
+			Data:       map[string]string{userKey: "some-value"},
+		}
+		fakeClient := fake.NewSimpleClientset(existingConfigMap)
+		testReporter := reporter.NewObject(fakeClient, testNamespace, reporter.ConfigMapKind, testResultsObjectName, false)
+
+		checkupStatus := newCompletedStatus()
+		checkupStatus.FailureReason = []string{"some reason"}
+		assert.NoError(t, testReporter.Report(checkupStatus))
+
+		configMap, err := fakeClient.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), testResultsObjectName, metav1.GetOptions{})
+		assert.NoError(t, err)
+
+		expectedData := createBasicExpectedReporterConfigmapData(false, checkupStatus)
+		expectedData[userKey] = "some-value"
+		assert.Equal(t, expectedData, configMap.Data)
+	})
+}
+
+func TestObjectReporterShouldReportToSecret(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	testReporter := reporter.NewObject(fakeClient, testNamespace, reporter.SecretKind, testResultsObjectName, false)
+
+	checkupStatus := newCompletedStatus()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	secret, err := fakeClient.CoreV1().Secrets(testNamespace).Get(context.Background(), testResultsObjectName, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	actualData := map[string]string{}
+	for k, v := range secret.Data {
+		actualData[k] = string(v)
+	}
+	assert.Equal(t, createBasicExpectedReporterConfigmapData(true, checkupStatus), actualData)
+}
+
+func TestObjectReporterShouldFailOnUnsupportedKind(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	testReporter := reporter.NewObject(fakeClient, testNamespace, "Pod", testResultsObjectName, false)
+
+	assert.ErrorContains(t, testReporter.Report(newCompletedStatus()), `unsupported results object kind "Pod"`)
+}
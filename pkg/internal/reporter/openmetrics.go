@@ -0,0 +1,63 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+const (
+	phaseDurationMetricName       = "kubevirt_dpdk_checkup_phase_duration_seconds"
+	phaseStartTimestampMetricName = "kubevirt_dpdk_checkup_phase_start_timestamp_seconds"
+)
+
+// formatTimelineOpenMetrics formats the duration and start time of each checkup phase in the OpenMetrics text format,
+// which the textfile collectors of the Prometheus exporters are able to ingest as well.
+func formatTimelineOpenMetrics(timeline status.Timeline) string {
+	sb := strings.Builder{}
+	writeMetricFamilyHeader(&sb, phaseDurationMetricName, "The duration of each checkup phase.")
+	for _, phase := range timeline {
+		fmt.Fprintf(&sb, "%s{phase=\"%s\"} %.3f\n", phaseDurationMetricName, escapeLabelValue(phase.Phase),
+			phase.End.Sub(phase.Start).Seconds())
+	}
+
+	writeMetricFamilyHeader(&sb, phaseStartTimestampMetricName, "The time each checkup phase has started at, since the epoch.")
+	for _, phase := range timeline {
+		fmt.Fprintf(&sb, "%s{phase=\"%s\"} %.3f\n", phaseStartTimestampMetricName, escapeLabelValue(phase.Phase),
+			float64(phase.Start.UnixMilli())/1000)
+	}
+	sb.WriteString("# EOF\n")
+
+	return sb.String()
+}
+
+func writeMetricFamilyHeader(sb *strings.Builder, name, help string) {
+	fmt.Fprintf(sb, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(sb, "# UNIT %s seconds\n", name)
+	fmt.Fprintf(sb, "# HELP %s %s\n", name, help)
+}
+
+// escapeLabelValue escapes the backslashes, double-quotes and line feeds of a label value, as the exposition format requires.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -20,63 +20,720 @@
 package reporter
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 
+	kconfigmap "github.com/kiagnose/kiagnose/kiagnose/configmap"
 	kreporter "github.com/kiagnose/kiagnose/kiagnose/reporter"
 
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
 )
 
 const (
-	TrafficGenSentPacketsKey        = "trafficGenSentPackets"
-	TrafficGenOutputErrorPacketsKey = "trafficGenOutputErrorPackets"
-	TrafficGenInputErrorPacketsKey  = "trafficGenInputErrorPackets"
-	VMUnderTestReceivedPacketsKey   = "vmUnderTestReceivedPackets"
-	VMUnderTestRxDroppedPacketsKey  = "vmUnderTestRxDroppedPackets"
-	VMUnderTestTxDroppedPacketsKey  = "vmUnderTestTxDroppedPackets"
-	TrafficGenActualNodeNameKey     = "trafficGenActualNodeName"
-	VMUnderTestActualNodeNameKey    = "vmUnderTestActualNodeName"
+	TrafficGenSentPacketsKey         = "trafficGenSentPackets"
+	TrafficGenOutputErrorPacketsKey  = "trafficGenOutputErrorPackets"
+	TrafficGenInputErrorPacketsKey   = "trafficGenInputErrorPackets"
+	VMUnderTestReceivedPacketsKey    = "vmUnderTestReceivedPackets"
+	VMUnderTestRxDroppedPacketsKey   = "vmUnderTestRxDroppedPackets"
+	VMUnderTestTxDroppedPacketsKey   = "vmUnderTestTxDroppedPackets"
+	TrafficGenAvgTxPpsKey            = "trafficGenAvgTxPps"
+	TrafficGenMaxTxPpsKey            = "trafficGenMaxTxPps"
+	TrafficGenAvgRxPpsKey            = "trafficGenAvgRxPps"
+	TrafficGenMaxRxPpsKey            = "trafficGenMaxRxPps"
+	TrafficGenAvgTxGbpsKey           = "trafficGenAvgTxGbps"
+	TrafficGenMaxTxGbpsKey           = "trafficGenMaxTxGbps"
+	TrafficGenAvgRxGbpsKey           = "trafficGenAvgRxGbps"
+	TrafficGenMaxRxGbpsKey           = "trafficGenMaxRxGbps"
+	MaxDropRateBpsKey                = "maxDropRateBps"
+	TrafficGenActualNodeNameKey      = "trafficGenActualNodeName"
+	VMUnderTestActualNodeNameKey     = "vmUnderTestActualNodeName"
+	OwnershipModeKey                 = "ownershipMode"
+	TrafficGenConsoleReconnectsKey   = "trafficGenConsoleReconnects"
+	VMUnderTestConsoleReconnectsKey  = "vmUnderTestConsoleReconnects"
+	SetupRetriesKey                  = "setupRetries"
+	TrafficGenGatewayResolutionKey   = "trafficGenGatewayResolution"
+	TrafficGenPortAddressesKey       = "trafficGenPortAddresses"
+	VMUnderTestTestpmdCommandKey     = "vmUnderTestTestpmdCommand"
+	NodeReadinessKey                 = "nodeReadiness"
+	CPUIsolationKey                  = "cpuIsolation"
+	NUMAAlignmentKey                 = "numaAlignment"
+	VFDriversKey                     = "vfDrivers"
+	VFDriverHintsKey                 = "vfDriverHints"
+	TrafficGenLauncherSecurityKey    = "trafficGenLauncherSecurity"
+	VMUnderTestLauncherSecurityKey   = "vmUnderTestLauncherSecurity"
+	KubeVirtVersionKey               = "kubeVirtVersion"
+	SRIOVDevicePluginImageKey        = "sriovDevicePluginImage"
+	SRIOVCNIImageKey                 = "sriovCNIImage"
+	VMUnderTestKernelKey             = "vmUnderTestKernel"
+	TrafficGenKernelKey              = "trafficGenKernel"
+	DPDKVersionKey                   = "dpdkVersion"
+	TrexVersionKey                   = "trexVersion"
+	OutcomeKey                       = "outcome"
+	CancelledPhaseKey                = "cancelledPhase"
+	AbortRequestedKey                = "abortRequested"
+	TimelineKey                      = "timeline"
+	TimelineOpenMetricsKey           = "timelineOpenMetrics"
+	DiagnosticsKey                   = "diagnostics"
+	StatsReadsKey                    = "statsReads"
+	StatsSkewWarningKey              = "statsSkewWarning"
+	PerQueueStatsKey                 = "perQueueStats"
+	PerStreamStatsKey                = "perStreamStats"
+	TxDurationRequestedSecondsKey    = "txDurationRequestedSeconds"
+	TxDurationActualSecondsKey       = "txDurationActualSeconds"
+	TxDurationDeltaPercentKey        = "txDurationDeltaPercent"
+	MigrationSourceNodeKey           = "migrationSourceNode"
+	MigrationTargetNodeKey           = "migrationTargetNode"
+	MigrationDurationSecondsKey      = "migrationDurationSeconds"
+	MigrationLostPacketsKey          = "migrationLostPackets"
+	BurstMaxSustainedSizeKey         = "burstMaxSustainedSize"
+	BurstFirstDroppingSizeKey        = "burstFirstDroppingSize"
+	RateSweepStepsKey                = "rateSweepSteps"
+	RateSweepFirstDroppingPercentKey = "rateSweepFirstDroppingPercent"
+	SoakSnapshotsCountKey            = "soakSnapshotsCount"
+	SoakLastSnapshotKey              = "soakLastSnapshot"
+	SoakAbortReasonKey               = "soakAbortReason"
+	BaselineVerdictKey               = "baselineVerdict"
+	BaselineAvgRxPpsDeltaPercentKey  = "baselineAvgRxPpsDeltaPercent"
+	BaselineDroppedPacketsDeltaKey   = "baselineDroppedPacketsDelta"
+	FootprintDedicatedCPUsKey        = "footprintDedicatedCPUs"
+	FootprintHugepagesGiBKey         = "footprintHugepagesGiB"
+	FootprintVFsKey                  = "footprintVFs"
+	FootprintDurationSecondsKey      = "footprintDurationSeconds"
+	TestIterationsKey                = "testIterations"
+	IterationsKey                    = "iterations"
+	TrafficGenSentPacketsMinKey      = "trafficGenSentPacketsMin"
+	TrafficGenSentPacketsMaxKey      = "trafficGenSentPacketsMax"
+	TrafficGenSentPacketsAvgKey      = "trafficGenSentPacketsAvg"
+	VMUnderTestReceivedPacketsMinKey = "vmUnderTestReceivedPacketsMin"
+	VMUnderTestReceivedPacketsMaxKey = "vmUnderTestReceivedPacketsMax"
+	VMUnderTestReceivedPacketsAvgKey = "vmUnderTestReceivedPacketsAvg"
+	VMUnderTestDroppedPacketsMinKey  = "vmUnderTestDroppedPacketsMin"
+	VMUnderTestDroppedPacketsMaxKey  = "vmUnderTestDroppedPacketsMax"
+	VMUnderTestDroppedPacketsAvgKey  = "vmUnderTestDroppedPacketsAvg"
+	ScenariosTotalKey                = "scenariosTotal"
+	ScenariosSucceededKey            = "scenariosSucceeded"
+	ScenariosFailedKey               = "scenariosFailed"
+	ScenarioSucceededKey             = "succeeded"
+	ScenarioFailureReasonKey         = "failureReason"
+	DirectionsSummaryKey             = "directionsSummary"
+	CriteriaKey                      = "criteria"
+	ScoreKey                         = "score"
+	JSONKey                          = "json"
+)
+
+// MaxDiagnosticsSize is the size limit of the guests diagnostics attached to the results, in bytes.
+const MaxDiagnosticsSize = 32 * 1024
+
+const (
+	ProgressPrefix             = "status.progress."
+	ProgressPhaseKey           = "phase"
+	ProgressElapsedSecondsKey  = "elapsedSeconds"
+	ProgressTxPpsKey           = "txPps"
+	ProgressRxPpsKey           = "rxPps"
+	ProgressDropBpsKey         = "dropBps"
+	ProgressUpdateTimestampKey = "updateTimestamp"
 )
 
 type Reporter struct {
 	kreporter.Reporter
+	client             kubernetes.Interface
+	configMapNamespace string
+	configMapName      string
+	jsonResults        bool
+	startReported      bool
+	startTimestamp     time.Time
 }
 
-func New(c kubernetes.Interface, configMapNamespace, configMapName string) *Reporter {
+// New returns a reporter updating the given ConfigMap.
+// When jsonResults is set, the results are also written as a single JSON document.
+func New(c kubernetes.Interface, configMapNamespace, configMapName string, jsonResults bool) *Reporter {
 	r := kreporter.New(c, configMapNamespace, configMapName)
-	return &Reporter{*r}
+	return &Reporter{
+		Reporter:           *r,
+		client:             c,
+		configMapNamespace: configMapNamespace,
+		configMapName:      configMapName,
+		jsonResults:        jsonResults,
+	}
 }
 
 func (r *Reporter) Report(checkupStatus status.Status) error {
-	if !r.HasData() {
-		return r.Reporter.Report(checkupStatus.Status)
+	if !r.startReported {
+		if err := r.Reporter.Report(checkupStatus.Status); err != nil {
+			return err
+		}
+		r.startReported = true
+		r.startTimestamp = checkupStatus.StartTimestamp
+		return nil
 	}
 
 	checkupStatus.Succeeded = len(checkupStatus.FailureReason) == 0
 
-	checkupStatus.Status.Results = formatResults(checkupStatus)
+	var err error
+	checkupStatus.Status.Results, err = formatResults(checkupStatus, r.jsonResults)
+	if err != nil {
+		return err
+	}
+
+	// The ConfigMap may have been updated by the user since the start was reported, e.g. to abort the run,
+	// thus it is read again rather than updating a stale copy.
+	r.Reporter = *kreporter.New(r.client, r.configMapNamespace, r.configMapName)
 
 	return r.Reporter.Report(checkupStatus.Status)
 }
 
-func formatResults(checkupStatus status.Status) map[string]string {
-	var emptyResults status.Results
-	if checkupStatus.Results == emptyResults {
-		return map[string]string{}
+// ReportProgress writes the progress of the running checkup to the ConfigMap, under the status.progress keys.
+func (r *Reporter) ReportProgress(progress status.Progress) error {
+	configMap, err := kconfigmap.Get(r.client, r.configMapNamespace, r.configMapName)
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		return kreporter.ErrConfigMapDataIsNil
+	}
+
+	for key, value := range formatProgress(progress, r.startTimestamp, time.Now()) {
+		configMap.Data[ProgressPrefix+key] = value
+	}
+
+	if _, err = kconfigmap.Update(r.client, configMap); err != nil {
+		return err
+	}
+
+	// The ConfigMap was updated behind the kiagnose reporter's back, thus it is reset to read the ConfigMap again.
+	r.Reporter = *kreporter.New(r.client, r.configMapNamespace, r.configMapName)
+
+	return nil
+}
+
+func formatProgress(progress status.Progress, startTimestamp, now time.Time) map[string]string {
+	formattedProgress := map[string]string{
+		ProgressPhaseKey:           progress.Phase,
+		ProgressTxPpsKey:           formatPps(progress.TxPps),
+		ProgressRxPpsKey:           formatPps(progress.RxPps),
+		ProgressDropBpsKey:         fmt.Sprintf("%.0f", progress.DropBps),
+		ProgressUpdateTimestampKey: now.Format(time.RFC3339),
+	}
+
+	if !startTimestamp.IsZero() {
+		formattedProgress[ProgressElapsedSecondsKey] = fmt.Sprintf("%.0f", now.Sub(startTimestamp).Seconds())
+	}
+
+	return formattedProgress
+}
+
+func formatResults(checkupStatus status.Status, jsonResults bool) (map[string]string, error) {
+	checkupStatus.Results.ResourceFootprint = footprintWithDuration(checkupStatus)
+	checkupStatus.Results.Diagnostics = truncateDiagnostics(checkupStatus.Results.Diagnostics)
+
+	formattedResults := map[string]string{}
+	if hasTrafficResults(checkupStatus.Results) {
+		formattedResults = formatTrafficResults(checkupStatus.Results)
+	}
+
+	if len(checkupStatus.Results.NodeReadiness) > 0 {
+		formattedResults[NodeReadinessKey] = formatNodeReadiness(checkupStatus.Results.NodeReadiness)
+	}
+
+	if checkupStatus.Results.CPUIsolation != "" {
+		formattedResults[CPUIsolationKey] = checkupStatus.Results.CPUIsolation
+	}
+
+	if checkupStatus.Results.NumaAlignment != "" {
+		formattedResults[NUMAAlignmentKey] = checkupStatus.Results.NumaAlignment
+	}
+
+	if len(checkupStatus.Results.VFDrivers) > 0 {
+		formattedResults[VFDriversKey] = formatVFDrivers(checkupStatus.Results.VFDrivers)
+	}
+
+	if len(checkupStatus.Results.VFDriverHints) > 0 {
+		formattedResults[VFDriverHintsKey] = strings.Join(checkupStatus.Results.VFDriverHints, "; ")
+	}
+
+	if posture := checkupStatus.Results.TrafficGenLauncherSecurity; posture != nil {
+		formattedResults[TrafficGenLauncherSecurityKey] = formatLauncherSecurityPosture(posture)
+	}
+
+	if posture := checkupStatus.Results.VMUnderTestLauncherSecurity; posture != nil {
+		formattedResults[VMUnderTestLauncherSecurityKey] = formatLauncherSecurityPosture(posture)
+	}
+
+	if footprint := checkupStatus.Results.ResourceFootprint; footprint != nil {
+		formatResourceFootprint(formattedResults, footprint)
+	}
+
+	if environment := checkupStatus.Results.Environment; environment != nil {
+		formatEnvironment(formattedResults, environment)
+	}
+
+	if checkupStatus.Results.Outcome != "" {
+		formattedResults[OutcomeKey] = checkupStatus.Results.Outcome
 	}
 
+	if checkupStatus.Results.CancelledPhase != "" {
+		formattedResults[CancelledPhaseKey] = checkupStatus.Results.CancelledPhase
+	}
+
+	if checkupStatus.Results.AbortRequested {
+		formattedResults[AbortRequestedKey] = "true"
+	}
+
+	if len(checkupStatus.Results.Timeline) > 0 {
+		formattedResults[TimelineKey] = formatTimeline(checkupStatus.Results.Timeline)
+		formattedResults[TimelineOpenMetricsKey] = formatTimelineOpenMetrics(checkupStatus.Results.Timeline)
+	}
+
+	if checkupStatus.Results.Diagnostics != "" {
+		formattedResults[DiagnosticsKey] = checkupStatus.Results.Diagnostics
+	}
+
+	if jsonResults && len(formattedResults) > 0 {
+		jsonData, err := json.Marshal(checkupStatus.Results)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal results: %w", err)
+		}
+		formattedResults[JSONKey] = string(jsonData)
+	}
+
+	return formattedResults, nil
+}
+
+func hasTrafficResults(results status.Results) bool {
+	results.NodeReadiness = nil
+	results.TrafficGenLauncherSecurity = nil
+	results.VMUnderTestLauncherSecurity = nil
+	results.ResourceFootprint = nil
+	results.Environment = nil
+	results.CPUIsolation = ""
+	results.NumaAlignment = ""
+	results.VFDrivers = nil
+	results.VFDriverHints = nil
+	results.Outcome = ""
+	results.CancelledPhase = ""
+	results.Timeline = nil
+	results.Diagnostics = ""
+	return !reflect.DeepEqual(results, status.Results{})
+}
+
+func formatTrafficResults(results status.Results) map[string]string {
 	formattedResults := map[string]string{
-		TrafficGenSentPacketsKey:        fmt.Sprintf("%d", checkupStatus.Results.TrafficGenSentPackets),
-		TrafficGenOutputErrorPacketsKey: fmt.Sprintf("%d", checkupStatus.Results.TrafficGenOutputErrorPackets),
-		TrafficGenInputErrorPacketsKey:  fmt.Sprintf("%d", checkupStatus.Results.TrafficGenInputErrorPackets),
-		VMUnderTestReceivedPacketsKey:   fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestReceivedPackets),
-		VMUnderTestRxDroppedPacketsKey:  fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestRxDroppedPackets),
-		VMUnderTestTxDroppedPacketsKey:  fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestTxDroppedPackets),
-		TrafficGenActualNodeNameKey:     checkupStatus.Results.TrafficGenActualNodeName,
-		VMUnderTestActualNodeNameKey:    checkupStatus.Results.VMUnderTestActualNodeName,
+		TrafficGenSentPacketsKey:        fmt.Sprintf("%d", results.TrafficGenSentPackets),
+		TrafficGenOutputErrorPacketsKey: fmt.Sprintf("%d", results.TrafficGenOutputErrorPackets),
+		TrafficGenInputErrorPacketsKey:  fmt.Sprintf("%d", results.TrafficGenInputErrorPackets),
+		VMUnderTestReceivedPacketsKey:   fmt.Sprintf("%d", results.VMUnderTestReceivedPackets),
+		VMUnderTestRxDroppedPacketsKey:  fmt.Sprintf("%d", results.VMUnderTestRxDroppedPackets),
+		VMUnderTestTxDroppedPacketsKey:  fmt.Sprintf("%d", results.VMUnderTestTxDroppedPackets),
+		TrafficGenAvgTxPpsKey:           formatPps(results.TrafficGenAvgTxPps),
+		TrafficGenMaxTxPpsKey:           formatPps(results.TrafficGenMaxTxPps),
+		TrafficGenAvgRxPpsKey:           formatPps(results.TrafficGenAvgRxPps),
+		TrafficGenMaxRxPpsKey:           formatPps(results.TrafficGenMaxRxPps),
+		TrafficGenAvgTxGbpsKey:          formatGbps(results.TrafficGenAvgTxBps),
+		TrafficGenMaxTxGbpsKey:          formatGbps(results.TrafficGenMaxTxBps),
+		TrafficGenAvgRxGbpsKey:          formatGbps(results.TrafficGenAvgRxBps),
+		TrafficGenMaxRxGbpsKey:          formatGbps(results.TrafficGenMaxRxBps),
+		MaxDropRateBpsKey:               fmt.Sprintf("%.0f", results.MaxDropRateBps),
+		TrafficGenActualNodeNameKey:     results.TrafficGenActualNodeName,
+		VMUnderTestActualNodeNameKey:    results.VMUnderTestActualNodeName,
+		OwnershipModeKey:                results.OwnershipMode,
+		TrafficGenConsoleReconnectsKey:  fmt.Sprintf("%d", results.TrafficGenConsoleReconnects),
+		VMUnderTestConsoleReconnectsKey: fmt.Sprintf("%d", results.VMUnderTestConsoleReconnects),
+	}
+
+	if results.SetupRetries > 0 {
+		formattedResults[SetupRetriesKey] = fmt.Sprintf("%d", results.SetupRetries)
+	}
+
+	if len(results.TrafficGenGatewayResolution) > 0 {
+		formattedResults[TrafficGenGatewayResolutionKey] = formatGatewayResolution(results.TrafficGenGatewayResolution)
+	}
+
+	if len(results.TrafficGenPortAddresses) > 0 {
+		formattedResults[TrafficGenPortAddressesKey] = formatPortAddresses(results.TrafficGenPortAddresses)
+	}
+
+	if results.VMUnderTestTestpmdCommand != "" {
+		formattedResults[VMUnderTestTestpmdCommandKey] = results.VMUnderTestTestpmdCommand
+	}
+
+	if results.IterationsSummary != nil {
+		formatIterations(formattedResults, results.Iterations, results.IterationsSummary)
+	}
+
+	if len(results.Scenarios) > 0 {
+		formatScenarios(formattedResults, results.Scenarios)
+	}
+
+	if results.DirectionsSummary != "" {
+		formattedResults[DirectionsSummaryKey] = results.DirectionsSummary
+	}
+
+	if results.Criteria != nil {
+		formattedResults[CriteriaKey] = formatCriteriaOutcomes(results.Criteria.Outcomes)
+		formattedResults[ScoreKey] = fmt.Sprintf("%d", results.Criteria.Score)
+	}
+
+	if len(results.StatsReads) > 0 {
+		formattedResults[StatsReadsKey] = formatStatsReads(results.StatsReads)
+	}
+
+	if results.StatsSkewWarning != "" {
+		formattedResults[StatsSkewWarningKey] = results.StatsSkewWarning
+	}
+
+	if len(results.PerQueueStats) > 0 {
+		formattedResults[PerQueueStatsKey] = formatPerQueueStats(results.PerQueueStats)
+	}
+
+	if len(results.PerStreamStats) > 0 {
+		formattedResults[PerStreamStatsKey] = formatPerStreamStats(results.PerStreamStats)
+	}
+
+	if txDuration := results.TxDuration; txDuration != nil {
+		formattedResults[TxDurationRequestedSecondsKey] = fmt.Sprintf("%.0f", txDuration.RequestedSeconds)
+		formattedResults[TxDurationActualSecondsKey] = fmt.Sprintf("%.1f", txDuration.ActualSeconds)
+		formattedResults[TxDurationDeltaPercentKey] = fmt.Sprintf("%.1f", txDuration.DeltaPercent)
+	}
+
+	if migration := results.Migration; migration != nil {
+		formattedResults[MigrationSourceNodeKey] = migration.SourceNode
+		formattedResults[MigrationTargetNodeKey] = migration.TargetNode
+		formattedResults[MigrationDurationSecondsKey] = fmt.Sprintf("%.3f", migration.DurationSeconds)
+		formattedResults[MigrationLostPacketsKey] = fmt.Sprintf("%d", migration.LostPackets)
+	}
+
+	if burst := results.Burst; burst != nil {
+		formattedResults[BurstMaxSustainedSizeKey] = fmt.Sprintf("%d", burst.MaxSustainedSize)
+		if burst.FirstDroppingSize > 0 {
+			formattedResults[BurstFirstDroppingSizeKey] = fmt.Sprintf("%d", burst.FirstDroppingSize)
+		}
+	}
+
+	// The snapshots of hours long runs would not fit the ConfigMap, only the last one is reported
+	if soak := results.Soak; soak != nil && len(soak.Snapshots) > 0 {
+		formattedResults[SoakSnapshotsCountKey] = fmt.Sprintf("%d", len(soak.Snapshots))
+		formattedResults[SoakLastSnapshotKey] = formatSoakSnapshot(soak.Snapshots[len(soak.Snapshots)-1])
+		if soak.AbortReason != "" {
+			formattedResults[SoakAbortReasonKey] = soak.AbortReason
+		}
+	}
+
+	if rateSweep := results.RateSweep; rateSweep != nil {
+		formattedResults[RateSweepStepsKey] = formatRateSweepSteps(rateSweep.Steps)
+		if rateSweep.FirstDroppingPercent > 0 {
+			formattedResults[RateSweepFirstDroppingPercentKey] = fmt.Sprintf("%d", rateSweep.FirstDroppingPercent)
+		}
+	}
+
+	if comparison := results.Baseline; comparison != nil {
+		formattedResults[BaselineVerdictKey] = comparison.Verdict
+		formattedResults[BaselineAvgRxPpsDeltaPercentKey] = fmt.Sprintf("%.1f", comparison.AvgRxPpsDeltaPercent)
+		formattedResults[BaselineDroppedPacketsDeltaKey] = fmt.Sprintf("%d", comparison.DroppedPacketsDelta)
 	}
 
 	return formattedResults
 }
+
+func formatGatewayResolution(resolutions []status.GatewayResolution) string {
+	var formattedResolutions []string
+	for _, resolution := range resolutions {
+		formattedResolutions = append(formattedResolutions,
+			fmt.Sprintf("port %d: %s is at %s", resolution.Port, resolution.IP, resolution.MAC))
+	}
+	return strings.Join(formattedResolutions, "; ")
+}
+
+func formatPortAddresses(addresses []status.PortAddress) string {
+	var formattedAddresses []string
+	for _, address := range addresses {
+		formattedAddresses = append(formattedAddresses,
+			fmt.Sprintf("port %d: %s via %s", address.Port, address.IP, address.Gateway))
+	}
+	return strings.Join(formattedAddresses, "; ")
+}
+
+func formatTimeline(timeline status.Timeline) string {
+	var formattedPhases []string
+	for _, phase := range timeline {
+		formattedPhases = append(formattedPhases, fmt.Sprintf("%s: %s - %s (%s)",
+			phase.Phase, phase.Start.Format(time.RFC3339), phase.End.Format(time.RFC3339), phase.End.Sub(phase.Start).Round(time.Second)))
+	}
+	return strings.Join(formattedPhases, "; ")
+}
+
+func formatStatsReads(statsReads []status.StatsRead) string {
+	const timestampFormat = "2006-01-02T15:04:05.000Z07:00"
+	var formattedReads []string
+	for i, statsRead := range statsReads {
+		formattedReads = append(formattedReads, fmt.Sprintf("%d: trafficGen %s, vmUnderTest %s (skew %s)", i+1,
+			statsRead.TrafficGen.Format(timestampFormat), statsRead.VMUnderTest.Format(timestampFormat),
+			statsRead.Skew().Round(time.Millisecond)))
+	}
+	return strings.Join(formattedReads, "; ")
+}
+
+// formatPerQueueStats formats the counters of each forwarding stream, e.g. "0/1->1/1: rx 100, tx 98, txDropped 2".
+func formatPerQueueStats(queues []status.QueueStats) string {
+	var formattedQueues []string
+	for _, queue := range queues {
+		formattedQueues = append(formattedQueues, fmt.Sprintf("%d/%d->%d/%d: rx %d, tx %d, txDropped %d",
+			queue.RXPort, queue.RXQueue, queue.TXPort, queue.TXQueue, queue.RXPackets, queue.TXPackets, queue.TXDropped))
+	}
+	return strings.Join(formattedQueues, "; ")
+}
+
+// formatPerStreamStats formats the counters of each traffic generator stream, e.g. "100 from port 1: tx 100, rx 98".
+func formatPerStreamStats(streams []status.StreamStats) string {
+	var formattedStreams []string
+	for _, stream := range streams {
+		formattedStreams = append(formattedStreams, fmt.Sprintf("%d from port %d: tx %d, rx %d",
+			stream.ID, stream.TXPort, stream.TXPackets, stream.RXPackets))
+	}
+	return strings.Join(formattedStreams, "; ")
+}
+
+// formatSoakSnapshot formats a soak snapshot, e.g. "traffic iteration 1/1 +600s: sent 100, received 100, dropped 0".
+func formatSoakSnapshot(snapshot status.SoakSnapshot) string {
+	return fmt.Sprintf("%s +%.0fs: sent %d, received %d, dropped %d", snapshot.Phase, snapshot.ElapsedSeconds,
+		snapshot.TrafficGenSentPackets, snapshot.TrafficGenReceivedPackets, snapshot.VMUnderTestDroppedPackets)
+}
+
+// formatRateSweepSteps formats the rates and loss of each rate sweep step, e.g. "10%: tx 1000, rx 990, loss 1.00%".
+func formatRateSweepSteps(steps []status.RateSweepStep) string {
+	var formattedSteps []string
+	for _, step := range steps {
+		formattedSteps = append(formattedSteps, fmt.Sprintf("%d%%: tx %s, rx %s, loss %.2f%%",
+			step.LineRatePercent, formatPps(step.TxPps), formatPps(step.RxPps), step.LossPercent))
+	}
+	return strings.Join(formattedSteps, "; ")
+}
+
+// truncateDiagnostics keeps the head of the diagnostics, to keep the ConfigMap well below its size limit.
+func truncateDiagnostics(diagnostics string) string {
+	if len(diagnostics) <= MaxDiagnosticsSize {
+		return diagnostics
+	}
+	return fmt.Sprintf("%s\n... (truncated %d bytes)", diagnostics[:MaxDiagnosticsSize], len(diagnostics)-MaxDiagnosticsSize)
+}
+
+func formatIterations(formattedResults map[string]string, iterations []status.PacketCounters, summary *status.IterationsSummary) {
+	formattedResults[TestIterationsKey] = fmt.Sprintf("%d", len(iterations))
+	formattedResults[TrafficGenSentPacketsMinKey] = fmt.Sprintf("%d", summary.TrafficGenSentPackets.Min)
+	formattedResults[TrafficGenSentPacketsMaxKey] = fmt.Sprintf("%d", summary.TrafficGenSentPackets.Max)
+	formattedResults[TrafficGenSentPacketsAvgKey] = fmt.Sprintf("%.1f", summary.TrafficGenSentPackets.Avg)
+	formattedResults[VMUnderTestReceivedPacketsMinKey] = fmt.Sprintf("%d", summary.VMUnderTestReceivedPackets.Min)
+	formattedResults[VMUnderTestReceivedPacketsMaxKey] = fmt.Sprintf("%d", summary.VMUnderTestReceivedPackets.Max)
+	formattedResults[VMUnderTestReceivedPacketsAvgKey] = fmt.Sprintf("%.1f", summary.VMUnderTestReceivedPackets.Avg)
+	formattedResults[VMUnderTestDroppedPacketsMinKey] = fmt.Sprintf("%d", summary.VMUnderTestDroppedPackets.Min)
+	formattedResults[VMUnderTestDroppedPacketsMaxKey] = fmt.Sprintf("%d", summary.VMUnderTestDroppedPackets.Max)
+	formattedResults[VMUnderTestDroppedPacketsAvgKey] = fmt.Sprintf("%.1f", summary.VMUnderTestDroppedPackets.Avg)
+
+	var formattedIterations []string
+	for i, iteration := range iterations {
+		formattedIterations = append(formattedIterations, fmt.Sprintf("%d: sent %d, received %d, rx dropped %d, tx dropped %d",
+			i+1,
+			iteration.TrafficGenSentPackets,
+			iteration.VMUnderTestReceivedPackets,
+			iteration.VMUnderTestRxDroppedPackets,
+			iteration.VMUnderTestTxDroppedPackets,
+		))
+	}
+	formattedResults[IterationsKey] = strings.Join(formattedIterations, "; ")
+}
+
+// formatScenarios adds a pass/fail rollup of all the scenarios, followed by the verdict and counters of each scenario
+// under its own keys, so the scenarios do not overwrite each other.
+// formatCriteriaOutcomes formats the outcome of each criterion, e.g. "drops (minor): failed, <reason>".
+func formatCriteriaOutcomes(outcomes []status.CriterionOutcome) string {
+	var formattedOutcomes []string
+	for _, outcome := range outcomes {
+		formattedOutcome := fmt.Sprintf("%s (%s): passed", outcome.Name, outcome.Severity)
+		if !outcome.Passed {
+			formattedOutcome = fmt.Sprintf("%s (%s): failed, %s", outcome.Name, outcome.Severity, outcome.FailureReason)
+		}
+		formattedOutcomes = append(formattedOutcomes, formattedOutcome)
+	}
+	return strings.Join(formattedOutcomes, "; ")
+}
+
+func formatScenarios(formattedResults map[string]string, scenarios []status.ScenarioResults) {
+	succeeded := 0
+	for _, scenario := range scenarios {
+		if scenario.Succeeded {
+			succeeded++
+		}
+
+		formattedResults[ScenarioResultKey(scenario.ID, ScenarioSucceededKey)] = fmt.Sprintf("%t", scenario.Succeeded)
+		if scenario.FailureReason != "" {
+			formattedResults[ScenarioResultKey(scenario.ID, ScenarioFailureReasonKey)] = scenario.FailureReason
+		}
+
+		counters := scenario.Counters
+		formattedResults[ScenarioResultKey(scenario.ID, TrafficGenSentPacketsKey)] =
+			fmt.Sprintf("%d", counters.TrafficGenSentPackets)
+		formattedResults[ScenarioResultKey(scenario.ID, TrafficGenOutputErrorPacketsKey)] =
+			fmt.Sprintf("%d", counters.TrafficGenOutputErrorPackets)
+		formattedResults[ScenarioResultKey(scenario.ID, TrafficGenInputErrorPacketsKey)] =
+			fmt.Sprintf("%d", counters.TrafficGenInputErrorPackets)
+		formattedResults[ScenarioResultKey(scenario.ID, VMUnderTestReceivedPacketsKey)] =
+			fmt.Sprintf("%d", counters.VMUnderTestReceivedPackets)
+		formattedResults[ScenarioResultKey(scenario.ID, VMUnderTestRxDroppedPacketsKey)] =
+			fmt.Sprintf("%d", counters.VMUnderTestRxDroppedPackets)
+		formattedResults[ScenarioResultKey(scenario.ID, VMUnderTestTxDroppedPacketsKey)] =
+			fmt.Sprintf("%d", counters.VMUnderTestTxDroppedPackets)
+	}
+
+	formattedResults[ScenariosTotalKey] = fmt.Sprintf("%d", len(scenarios))
+	formattedResults[ScenariosSucceededKey] = fmt.Sprintf("%d", succeeded)
+	formattedResults[ScenariosFailedKey] = fmt.Sprintf("%d", len(scenarios)-succeeded)
+}
+
+var invalidScenarioIDChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// ScenarioResultKey returns the results key of a single scenario, e.g. "scenario.64B.trafficGenSentPackets".
+// Characters that are not allowed in ConfigMap keys are replaced in the scenario ID.
+func ScenarioResultKey(scenarioID, key string) string {
+	return fmt.Sprintf("scenario.%s.%s", invalidScenarioIDChars.ReplaceAllString(scenarioID, "-"), key)
+}
+
+// footprintWithDuration returns a copy of the resource footprint, holding the checkup's wall-clock duration once it has completed.
+func footprintWithDuration(checkupStatus status.Status) *status.ResourceFootprint {
+	if checkupStatus.Results.ResourceFootprint == nil {
+		return nil
+	}
+
+	footprint := *checkupStatus.Results.ResourceFootprint
+	if !checkupStatus.CompletionTimestamp.IsZero() {
+		footprint.DurationSeconds = checkupStatus.CompletionTimestamp.Sub(checkupStatus.StartTimestamp).Seconds()
+	}
+
+	return &footprint
+}
+
+func formatResourceFootprint(formattedResults map[string]string, footprint *status.ResourceFootprint) {
+	formattedResults[FootprintDedicatedCPUsKey] = fmt.Sprintf("%d", footprint.DedicatedCPUs)
+	formattedResults[FootprintHugepagesGiBKey] = fmt.Sprintf("%d", footprint.HugepagesGiB)
+	formattedResults[FootprintVFsKey] = fmt.Sprintf("%d", footprint.VFs)
+	if footprint.DurationSeconds > 0 {
+		formattedResults[FootprintDurationSecondsKey] = fmt.Sprintf("%.0f", footprint.DurationSeconds)
+	}
+}
+
+// formatEnvironment reports the detected versions, leaving out the ones that could not be detected.
+func formatEnvironment(formattedResults map[string]string, environment *status.Environment) {
+	versions := map[string]string{
+		KubeVirtVersionKey:        environment.KubeVirtVersion,
+		SRIOVDevicePluginImageKey: environment.SRIOVDevicePluginImage,
+		SRIOVCNIImageKey:          environment.SRIOVCNIImage,
+		VMUnderTestKernelKey:      environment.VMUnderTestKernel,
+		TrafficGenKernelKey:       environment.TrafficGenKernel,
+		DPDKVersionKey:            environment.DPDKVersion,
+		TrexVersionKey:            environment.TrexVersion,
+	}
+	for key, version := range versions {
+		if version != "" {
+			formattedResults[key] = version
+		}
+	}
+}
+
+// formatNodeReadiness returns the readiness score of each node, followed by its kernel flavor when known,
+// and the names of the checks that failed on it, e.g. "node01: 100% [realtime]; node02: 50% (failed: hugepages, cpuManager)".
+// formatVFDrivers formats the VF drivers by guest, followed by their version when known,
+// e.g. "VM under test: 0000:06:00.0 iavf 4.8.2, 0000:07:00.0 iavf 4.8.2".
+func formatVFDrivers(vfDrivers []status.VFDriver) string {
+	var guests []string
+	nicsByGuest := map[string][]string{}
+	for _, vfDriver := range vfDrivers {
+		if _, exists := nicsByGuest[vfDriver.Guest]; !exists {
+			guests = append(guests, vfDriver.Guest)
+		}
+		nic := vfDriver.PCIAddress + " " + vfDriver.Driver
+		if vfDriver.DriverVersion != "" {
+			nic += " " + vfDriver.DriverVersion
+		}
+		nicsByGuest[vfDriver.Guest] = append(nicsByGuest[vfDriver.Guest], nic)
+	}
+
+	var formattedGuests []string
+	for _, guest := range guests {
+		formattedGuests = append(formattedGuests, guest+": "+strings.Join(nicsByGuest[guest], ", "))
+	}
+	return strings.Join(formattedGuests, "; ")
+}
+
+func formatNodeReadiness(readiness []status.NodeReadiness) string {
+	var nodes []string
+	for _, nodeReadiness := range readiness {
+		var failedChecks []string
+		for _, check := range nodeReadiness.Checks {
+			if !check.Passed {
+				failedChecks = append(failedChecks, check.Name)
+			}
+		}
+
+		formattedNode := fmt.Sprintf("%s: %d%%", nodeReadiness.NodeName, nodeReadiness.Score)
+		if nodeReadiness.KernelFlavor != "" {
+			formattedNode += fmt.Sprintf(" [%s]", nodeReadiness.KernelFlavor)
+		}
+		if len(failedChecks) > 0 {
+			formattedNode += fmt.Sprintf(" (failed: %s)", strings.Join(failedChecks, ", "))
+		}
+		nodes = append(nodes, formattedNode)
+	}
+
+	return strings.Join(nodes, "; ")
+}
+
+// formatLauncherSecurityPosture returns the security context of a virt-launcher Pod in a single line, e.g.
+// "pod: virt-launcher-x; selinux: container_t:s0:c1,c2; seccomp: RuntimeDefault; runAsUser: 107; privileged: false;
+// capabilities: +NET_BIND_SERVICE,-ALL".
+func formatLauncherSecurityPosture(posture *status.LauncherSecurityPosture) string {
+	const unset = "<unset>"
+
+	selinux := unset
+	if posture.SELinuxType != "" || posture.SELinuxLevel != "" {
+		selinux = strings.Trim(posture.SELinuxType+":"+posture.SELinuxLevel, ":")
+	}
+
+	seccomp := unset
+	if posture.SeccompProfile != "" {
+		seccomp = posture.SeccompProfile
+	}
+
+	runAsUser := unset
+	if posture.RunAsUser != nil {
+		runAsUser = fmt.Sprintf("%d", *posture.RunAsUser)
+	}
+
+	var capabilities []string
+	for _, capability := range posture.AddedCapabilities {
+		capabilities = append(capabilities, "+"+capability)
+	}
+	for _, capability := range posture.DroppedCapabilities {
+		capabilities = append(capabilities, "-"+capability)
+	}
+	formattedCapabilities := unset
+	if len(capabilities) > 0 {
+		formattedCapabilities = strings.Join(capabilities, ",")
+	}
+
+	return fmt.Sprintf("pod: %s; selinux: %s; seccomp: %s; runAsUser: %s; privileged: %t; capabilities: %s",
+		posture.PodName, selinux, seccomp, runAsUser, posture.Privileged, formattedCapabilities)
+}
+
+func formatPps(pps float64) string {
+	return fmt.Sprintf("%.0f", pps)
+}
+
+func formatGbps(bps float64) string {
+	const bitsInGigabit = 1e9
+	return fmt.Sprintf("%.3f", bps/bitsInGigabit)
+}
@@ -20,6 +20,7 @@
 package reporter_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -46,7 +47,7 @@ const (
 
 func TestReportShouldSucceed(t *testing.T) {
 	fakeClient := fake.NewSimpleClientset(newConfigMap())
-	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName)
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
 
 	assert.NoError(t, testReporter.Report(status.Status{}))
 }
@@ -66,11 +67,21 @@ func TestReportShouldSuccessfullyReportResults(t *testing.T) {
 			expectedVMUnderTestReceivedPackets   = 100
 			expectedVMUnderTestRxDroppedPackets  = 0
 			expectedVMUnderTestTxDroppedPackets  = 0
+			expectedTrafficGenAvgTxPps           = 7988831.0
+			expectedTrafficGenMaxTxPps           = 7999999.0
+			expectedTrafficGenAvgRxPps           = 7988813.0
+			expectedTrafficGenMaxRxPps           = 7999998.0
+			expectedTrafficGenAvgTxBps           = 4345917952.0
+			expectedTrafficGenMaxTxBps           = 4350000000.0
+			expectedTrafficGenAvgRxBps           = 4090272768.0
+			expectedTrafficGenMaxRxBps           = 4100000000.0
+			expectedMaxDropRateBps               = 128000.0
 			expectedVMUnderTestActualNodeName    = "dpdk-node01"
 			expectedTrafficGenActualNodeName     = "dpdk-node02"
+			expectedOwnershipMode                = "ownerReference"
 		)
 		fakeClient := fake.NewSimpleClientset(newConfigMap())
-		testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName)
+		testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
 
 		var checkupStatus status.Status
 		checkupStatus.StartTimestamp = time.Now()
@@ -85,8 +96,18 @@ func TestReportShouldSuccessfullyReportResults(t *testing.T) {
 			VMUnderTestReceivedPackets:   expectedVMUnderTestReceivedPackets,
 			VMUnderTestRxDroppedPackets:  expectedVMUnderTestRxDroppedPackets,
 			VMUnderTestTxDroppedPackets:  expectedVMUnderTestTxDroppedPackets,
+			TrafficGenAvgTxPps:           expectedTrafficGenAvgTxPps,
+			TrafficGenMaxTxPps:           expectedTrafficGenMaxTxPps,
+			TrafficGenAvgRxPps:           expectedTrafficGenAvgRxPps,
+			TrafficGenMaxRxPps:           expectedTrafficGenMaxRxPps,
+			TrafficGenAvgTxBps:           expectedTrafficGenAvgTxBps,
+			TrafficGenMaxTxBps:           expectedTrafficGenMaxTxBps,
+			TrafficGenAvgRxBps:           expectedTrafficGenAvgRxBps,
+			TrafficGenMaxRxBps:           expectedTrafficGenMaxRxBps,
+			MaxDropRateBps:               expectedMaxDropRateBps,
 			VMUnderTestActualNodeName:    expectedVMUnderTestActualNodeName,
 			TrafficGenActualNodeName:     expectedTrafficGenActualNodeName,
+			OwnershipMode:                expectedOwnershipMode,
 		}
 
 		assert.NoError(t, testReporter.Report(checkupStatus))
@@ -138,7 +159,7 @@ func TestReportShouldSuccessfullyReportResults(t *testing.T) {
 		for _, testCase := range testCases {
 			t.Run(testCase.description, func(t *testing.T) {
 				fakeClient := fake.NewSimpleClientset(newConfigMap())
-				testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName)
+				testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
 
 				var checkupStatus status.Status
 				checkupStatus.StartTimestamp = time.Now()
@@ -160,11 +181,870 @@ func TestReportShouldSuccessfullyReportResults(t *testing.T) {
 	})
 }
 
+func TestReportShouldAddJSONResultsWhenRequested(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, true)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 100,
+		TrafficGenAvgTxPps:         7988831.5,
+		TrafficGenActualNodeName:   "dpdk-node02",
+		VMUnderTestActualNodeName:  "dpdk-node01",
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createExpectedReporterConfigmapDataWithResults(true, checkupStatus)
+	actualReportData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+
+	const jsonResultsKey = "status.result.json"
+	var actualJSONResults status.Results
+	assert.NoError(t, json.Unmarshal([]byte(actualReportData[jsonResultsKey]), &actualJSONResults))
+	assert.Equal(t, checkupStatus.Results, actualJSONResults)
+
+	delete(actualReportData, jsonResultsKey)
+	assert.Equal(t, expectedReportData, actualReportData)
+}
+
+func TestReportProgressShouldWriteTheProgressKeys(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now().Add(-time.Minute)
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	assert.NoError(t, testReporter.ReportProgress(status.Progress{
+		Phase:   "traffic iteration 1/1",
+		TxPps:   7988831.5,
+		RxPps:   7988000,
+		DropBps: 425472,
+	}))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "traffic iteration 1/1", checkupData["status.progress.phase"])
+	assert.Equal(t, "7988832", checkupData["status.progress.txPps"])
+	assert.Equal(t, "7988000", checkupData["status.progress.rxPps"])
+	assert.Equal(t, "425472", checkupData["status.progress.dropBps"])
+	assert.Equal(t, "60", checkupData["status.progress.elapsedSeconds"])
+	assert.NotEmpty(t, checkupData["status.progress.updateTimestamp"])
+	assert.Equal(t, timestamp(checkupStatus.StartTimestamp), checkupData["status.startTimestamp"])
+}
+
+func TestReportShouldSucceedAfterProgressWasReported(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	assert.NoError(t, testReporter.ReportProgress(status.Progress{Phase: "warm-up"}))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{TrafficGenSentPackets: 100}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "warm-up", checkupData["status.progress.phase"])
+	assert.Equal(t, "true", checkupData["status.succeeded"])
+	assert.Equal(t, "100", checkupData["status.result.trafficGenSentPackets"])
+}
+
+func TestReportShouldReportTimeline(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Date(2023, 8, 20, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = checkupStatus.StartTimestamp.Add(10 * time.Minute)
+	checkupStatus.Results = status.Results{
+		Timeline: status.Timeline{
+			{Phase: "VMIs boot", Start: checkupStatus.StartTimestamp, End: checkupStatus.StartTimestamp.Add(95 * time.Second)},
+			{
+				Phase: "traffic iteration 1/1",
+				Start: checkupStatus.StartTimestamp.Add(2 * time.Minute),
+				End:   checkupStatus.StartTimestamp.Add(7 * time.Minute),
+			},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"VMIs boot: 2023-08-20T10:00:00Z - 2023-08-20T10:01:35Z (1m35s); "+
+			"traffic iteration 1/1: 2023-08-20T10:02:00Z - 2023-08-20T10:07:00Z (5m0s)",
+		checkupData["status.result.timeline"])
+	assert.Equal(t, expectedTimelineOpenMetrics, checkupData["status.result.timelineOpenMetrics"])
+	assert.NotContains(t, checkupData, "status.result.trafficGenSentPackets")
+}
+
+const expectedTimelineOpenMetrics = `# TYPE kubevirt_dpdk_checkup_phase_duration_seconds gauge
+# UNIT kubevirt_dpdk_checkup_phase_duration_seconds seconds
+# HELP kubevirt_dpdk_checkup_phase_duration_seconds The duration of each checkup phase.
+kubevirt_dpdk_checkup_phase_duration_seconds{phase="VMIs boot"} 95.000
+kubevirt_dpdk_checkup_phase_duration_seconds{phase="traffic iteration 1/1"} 300.000
+# TYPE kubevirt_dpdk_checkup_phase_start_timestamp_seconds gauge
+# UNIT kubevirt_dpdk_checkup_phase_start_timestamp_seconds seconds
+# HELP kubevirt_dpdk_checkup_phase_start_timestamp_seconds The time each checkup phase has started at, since the epoch.
+kubevirt_dpdk_checkup_phase_start_timestamp_seconds{phase="VMIs boot"} 1692525600.000
+kubevirt_dpdk_checkup_phase_start_timestamp_seconds{phase="traffic iteration 1/1"} 1692525720.000
+# EOF
+`
+
+func TestReportShouldReportTruncatedDiagnostics(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	const sectionHeader = "==== traffic generator dmesg ====\n"
+	const extraBytes = 10
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		Diagnostics: sectionHeader + strings.Repeat("x", reporter.MaxDiagnosticsSize+extraBytes),
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	diagnostics := checkupData["status.result.diagnostics"]
+	assert.True(t, strings.HasPrefix(diagnostics, sectionHeader))
+	assert.True(t, strings.HasSuffix(diagnostics, fmt.Sprintf("\n... (truncated %d bytes)", len(sectionHeader)+extraBytes)))
+	assert.NotContains(t, checkupData, "status.result.trafficGenSentPackets")
+}
+
+func TestReportShouldReportIterations(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:       300,
+		VMUnderTestReceivedPackets:  297,
+		VMUnderTestRxDroppedPackets: 3,
+		Iterations: []status.PacketCounters{
+			{TrafficGenSentPackets: 100, VMUnderTestReceivedPackets: 100},
+			{TrafficGenSentPackets: 200, VMUnderTestReceivedPackets: 197, VMUnderTestRxDroppedPackets: 3},
+		},
+		IterationsSummary: &status.IterationsSummary{
+			TrafficGenSentPackets:      status.CounterSummary{Min: 100, Max: 200, Avg: 150},
+			VMUnderTestReceivedPackets: status.CounterSummary{Min: 100, Max: 197, Avg: 148.5},
+			VMUnderTestDroppedPackets:  status.CounterSummary{Min: 0, Max: 3, Avg: 1.5},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createExpectedReporterConfigmapDataWithResults(true, checkupStatus)
+	expectedReportData["status.result.testIterations"] = "2"
+	expectedReportData["status.result.trafficGenSentPacketsMin"] = "100"
+	expectedReportData["status.result.trafficGenSentPacketsMax"] = "200"
+	expectedReportData["status.result.trafficGenSentPacketsAvg"] = "150.0"
+	expectedReportData["status.result.vmUnderTestReceivedPacketsMin"] = "100"
+	expectedReportData["status.result.vmUnderTestReceivedPacketsMax"] = "197"
+	expectedReportData["status.result.vmUnderTestReceivedPacketsAvg"] = "148.5"
+	expectedReportData["status.result.vmUnderTestDroppedPacketsMin"] = "0"
+	expectedReportData["status.result.vmUnderTestDroppedPacketsMax"] = "3"
+	expectedReportData["status.result.vmUnderTestDroppedPacketsAvg"] = "1.5"
+	expectedReportData["status.result.iterations"] =
+		"1: sent 100, received 100, rx dropped 0, tx dropped 0; 2: sent 200, received 197, rx dropped 3, tx dropped 0"
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportScenarios(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.FailureReason = []string{"1 out of 2 scenarios failed"}
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:       200,
+		VMUnderTestReceivedPackets:  198,
+		VMUnderTestRxDroppedPackets: 2,
+		Scenarios: []status.ScenarioResults{
+			{
+				ID:        "64B",
+				Succeeded: true,
+				Counters:  status.PacketCounters{TrafficGenSentPackets: 100, VMUnderTestReceivedPackets: 100},
+			},
+			{
+				ID:            "node01/node02",
+				FailureReason: "detected packets dropped",
+				Counters: status.PacketCounters{
+					TrafficGenSentPackets:       100,
+					VMUnderTestReceivedPackets:  98,
+					VMUnderTestRxDroppedPackets: 2,
+				},
+			},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createExpectedReporterConfigmapDataWithResults(false, checkupStatus)
+	expectedReportData["status.result.scenariosTotal"] = "2"
+	expectedReportData["status.result.scenariosSucceeded"] = "1"
+	expectedReportData["status.result.scenariosFailed"] = "1"
+	expectedReportData["status.result.scenario.64B.succeeded"] = "true"
+	expectedReportData["status.result.scenario.64B.trafficGenSentPackets"] = "100"
+	expectedReportData["status.result.scenario.64B.trafficGenOutputErrorPackets"] = "0"
+	expectedReportData["status.result.scenario.64B.trafficGenInputErrorPackets"] = "0"
+	expectedReportData["status.result.scenario.64B.vmUnderTestReceivedPackets"] = "100"
+	expectedReportData["status.result.scenario.64B.vmUnderTestRxDroppedPackets"] = "0"
+	expectedReportData["status.result.scenario.64B.vmUnderTestTxDroppedPackets"] = "0"
+	expectedReportData["status.result.scenario.node01-node02.succeeded"] = "false"
+	expectedReportData["status.result.scenario.node01-node02.failureReason"] = "detected packets dropped"
+	expectedReportData["status.result.scenario.node01-node02.trafficGenSentPackets"] = "100"
+	expectedReportData["status.result.scenario.node01-node02.trafficGenOutputErrorPackets"] = "0"
+	expectedReportData["status.result.scenario.node01-node02.trafficGenInputErrorPackets"] = "0"
+	expectedReportData["status.result.scenario.node01-node02.vmUnderTestReceivedPackets"] = "98"
+	expectedReportData["status.result.scenario.node01-node02.vmUnderTestRxDroppedPackets"] = "2"
+	expectedReportData["status.result.scenario.node01-node02.vmUnderTestTxDroppedPackets"] = "0"
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportDirectionsSummary(t *testing.T) {
+	const directionsSummary = "both directions succeeded"
+
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      200,
+		VMUnderTestReceivedPackets: 200,
+		Scenarios: []status.ScenarioResults{
+			{ID: status.EastToWestScenarioID, Succeeded: true, Counters: status.PacketCounters{TrafficGenSentPackets: 100}},
+			{ID: status.WestToEastScenarioID, Succeeded: true, Counters: status.PacketCounters{TrafficGenSentPackets: 100}},
+		},
+		DirectionsSummary: directionsSummary,
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "true", checkupData["status.result.scenario.east-to-west.succeeded"])
+	assert.Equal(t, "true", checkupData["status.result.scenario.west-to-east.succeeded"])
+	assert.Equal(t, directionsSummary, checkupData["status.result.directionsSummary"])
+}
+
+func TestReportShouldReportCriteriaOutcomesAndScore(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:       100,
+		VMUnderTestReceivedPackets:  100,
+		VMUnderTestRxDroppedPackets: 2,
+		Criteria: &status.CriteriaResults{
+			Outcomes: []status.CriterionOutcome{
+				{Name: "errors", Severity: "blocker", Passed: true},
+				{Name: "drops", Severity: "minor", FailureReason: "detected packets dropped on the VM-Under-Test's side: RX: 2; TX: 0"},
+			},
+			Score: 91,
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"errors (blocker): passed; drops (minor): failed, detected packets dropped on the VM-Under-Test's side: RX: 2; TX: 0",
+		checkupData["status.result.criteria"])
+	assert.Equal(t, "91", checkupData["status.result.score"])
+}
+
+func TestReportShouldReportMigration(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 90,
+		Migration: &status.Migration{
+			SourceNode:      "dpdk-node01",
+			TargetNode:      "dpdk-node02",
+			DurationSeconds: 4.5,
+			LostPackets:     10,
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "dpdk-node01", checkupData["status.result.migrationSourceNode"])
+	assert.Equal(t, "dpdk-node02", checkupData["status.result.migrationTargetNode"])
+	assert.Equal(t, "4.500", checkupData["status.result.migrationDurationSeconds"])
+	assert.Equal(t, "10", checkupData["status.result.migrationLostPackets"])
+}
+
+func TestReportShouldReportBurst(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.FailureReason = []string{"1 out of 2 scenarios failed"}
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 100,
+		Burst:                      &status.Burst{MaxSustainedSize: 512, FirstDroppingSize: 1024},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "512", checkupData["status.result.burstMaxSustainedSize"])
+	assert.Equal(t, "1024", checkupData["status.result.burstFirstDroppingSize"])
+}
+
+func TestReportShouldReportRateSweep(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 100,
+		RateSweep: &status.RateSweep{
+			Steps: []status.RateSweepStep{
+				{LineRatePercent: 50, TxPps: 1000, RxPps: 1000, SentPackets: 10000},
+				{LineRatePercent: 100, TxPps: 2000, RxPps: 1980, SentPackets: 20000, LostPackets: 200, LossPercent: 1},
+			},
+			FirstDroppingPercent: 100,
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "50%: tx 1000, rx 1000, loss 0.00%; 100%: tx 2000, rx 1980, loss 1.00%",
+		checkupData["status.result.rateSweepSteps"])
+	assert.Equal(t, "100", checkupData["status.result.rateSweepFirstDroppingPercent"])
+}
+
+func TestReportShouldReportSoak(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.FailureReason = []string{"the traffic was aborted early"}
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      300,
+		VMUnderTestReceivedPackets: 280,
+		Soak: &status.Soak{
+			Snapshots: []status.SoakSnapshot{
+				{Phase: "traffic iteration 1/1", ElapsedSeconds: 60, TrafficGenSentPackets: 100, TrafficGenReceivedPackets: 100},
+				{Phase: "traffic iteration 1/1", ElapsedSeconds: 120, TrafficGenSentPackets: 200, TrafficGenReceivedPackets: 190,
+					VMUnderTestDroppedPackets: 10},
+			},
+			AbortReason: "the VM under test dropped packets grew in 3 consecutive snapshots, to 10",
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "2", checkupData["status.result.soakSnapshotsCount"])
+	assert.Equal(t, "traffic iteration 1/1 +120s: sent 200, received 190, dropped 10", checkupData["status.result.soakLastSnapshot"])
+	assert.Equal(t, "the VM under test dropped packets grew in 3 consecutive snapshots, to 10", checkupData["status.result.soakAbortReason"])
+}
+
+func TestReportShouldReportAbortRequested(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.FailureReason = []string{`the run was aborted by the "spec.abort" ConfigMap key`}
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 100,
+		AbortRequested:             true,
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "true", checkupData["status.result.abortRequested"])
+	assert.Equal(t, "100", checkupData["status.result.trafficGenSentPackets"])
+}
+
+func TestReportShouldKeepTheConfigMapUpdatesMadeWhileRunning(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	configMap, err := kconfigmap.Get(fakeClient, testNamespace, testConfigMapName)
+	assert.NoError(t, err)
+	configMap.Data["spec.abort"] = "true"
+	_, err = kconfigmap.Update(fakeClient, configMap)
+	assert.NoError(t, err)
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "true", checkupData["spec.abort"])
+	assert.Equal(t, "true", checkupData["status.succeeded"])
+}
+
+func TestReportShouldReportTxDuration(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 100,
+		TxDuration: &status.TxDuration{
+			RequestedSeconds: 120,
+			ActualSeconds:    117.25,
+			DeltaPercent:     -2.2917,
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "120", checkupData["status.result.txDurationRequestedSeconds"])
+	assert.Equal(t, "117.2", checkupData["status.result.txDurationActualSeconds"])
+	assert.Equal(t, "-2.3", checkupData["status.result.txDurationDeltaPercent"])
+}
+
+func TestReportShouldReportBaselineComparison(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:       100,
+		VMUnderTestReceivedPackets:  100,
+		VMUnderTestRxDroppedPackets: 3,
+		Baseline: &status.BaselineComparison{
+			ConfigMapName:        "dpdk-checkup-baseline",
+			AvgRxPpsDeltaPercent: -7.25,
+			DroppedPacketsDelta:  3,
+			Verdict:              "regressed: average RX rate -7.2%, dropped packets +3",
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, "regressed: average RX rate -7.2%, dropped packets +3", checkupData["status.result.baselineVerdict"])
+	assert.Equal(t, "-7.2", checkupData["status.result.baselineAvgRxPpsDeltaPercent"])
+	assert.Equal(t, "3", checkupData["status.result.baselineDroppedPacketsDelta"])
+}
+
+func TestReportShouldReportNodeReadinessWithoutTrafficResults(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.FailureReason = []string{"setup: failed to wait for VMI to be ready"}
+	checkupStatus.Results = status.Results{
+		NodeReadiness: []status.NodeReadiness{
+			{
+				NodeName:     "dpdk-node01",
+				Score:        100,
+				KernelFlavor: "realtime",
+				Checks:       []status.PreflightOutcome{{Name: "hugepages", Passed: true}, {Name: "cpuManager", Passed: true}},
+			},
+			{
+				NodeName: "dpdk-node02",
+				Score:    0,
+				Checks:   []status.PreflightOutcome{{Name: "hugepages"}, {Name: "cpuManager"}},
+			},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(false, checkupStatus)
+	expectedReportData["status.result.nodeReadiness"] = "dpdk-node01: 100% [realtime]; dpdk-node02: 0% (failed: hugepages, cpuManager)"
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportCPUIsolationWithoutTrafficResults(t *testing.T) {
+	const cpuIsolation = "traffic generator: the tuned cpu-partitioning profile is not active (\"No current active profile.\")"
+
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.FailureReason = []string{"the guests CPUs are not isolated: " + cpuIsolation}
+	checkupStatus.Results = status.Results{CPUIsolation: cpuIsolation}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(false, checkupStatus)
+	expectedReportData["status.result.cpuIsolation"] = cpuIsolation
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportNUMAAlignment(t *testing.T) {
+	const numaAlignment = "VM under test: aligned on NUMA node 0; " +
+		"traffic generator: misaligned, vCPUs on NUMA nodes 0, hugepages on NUMA nodes 0, NICs on NUMA nodes 1"
+
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Succeeded = true
+	checkupStatus.Results = status.Results{NumaAlignment: numaAlignment}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(true, checkupStatus)
+	expectedReportData["status.result.numaAlignment"] = numaAlignment
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportVFDrivers(t *testing.T) {
+	const hint = "traffic generator: NIC 0000:06:00.0 is a Mellanox VF, which DPDK drives through the bifurcated " +
+		"mlx5_core driver: it is kept bound to mlx5_core rather than to vfio-pci"
+
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Succeeded = true
+	checkupStatus.Results = status.Results{
+		VFDrivers: []status.VFDriver{
+			{Guest: "VM under test", PCIAddress: "0000:06:00.0", Driver: "iavf"},
+			{Guest: "VM under test", PCIAddress: "0000:07:00.0", Driver: "iavf"},
+			{Guest: "traffic generator", PCIAddress: "0000:06:00.0", Driver: "mlx5_core", DriverVersion: "5.14.0-362.el9.x86_64"},
+		},
+		VFDriverHints: []string{hint},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(true, checkupStatus)
+	expectedReportData["status.result.vfDrivers"] = "VM under test: 0000:06:00.0 iavf, 0000:07:00.0 iavf; " +
+		"traffic generator: 0000:06:00.0 mlx5_core 5.14.0-362.el9.x86_64"
+	expectedReportData["status.result.vfDriverHints"] = hint
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportEnvironmentVersions(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Succeeded = true
+	checkupStatus.Results = status.Results{
+		Environment: &status.Environment{
+			KubeVirtVersion:        "v1.1.0",
+			SRIOVDevicePluginImage: "ghcr.io/k8snetworkplumbingwg/sriov-network-device-plugin:v3.6.2",
+			VMUnderTestKernel:      "5.14.0-362.el9.x86_64",
+			TrafficGenKernel:       "5.14.0-362.el9.x86_64",
+			DPDKVersion:            "22.11",
+			TrexVersion:            "v3.03",
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(true, checkupStatus)
+	expectedReportData["status.result.kubeVirtVersion"] = "v1.1.0"
+	expectedReportData["status.result.sriovDevicePluginImage"] = "ghcr.io/k8snetworkplumbingwg/sriov-network-device-plugin:v3.6.2"
+	expectedReportData["status.result.vmUnderTestKernel"] = "5.14.0-362.el9.x86_64"
+	expectedReportData["status.result.trafficGenKernel"] = "5.14.0-362.el9.x86_64"
+	expectedReportData["status.result.dpdkVersion"] = "22.11"
+	expectedReportData["status.result.trexVersion"] = "v3.03"
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportLauncherSecurityPosture(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	const qemuUser = int64(107)
+	runAsUser := qemuUser
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.FailureReason = []string{"run: failed to login to VMI"}
+	checkupStatus.Results = status.Results{
+		TrafficGenLauncherSecurity: &status.LauncherSecurityPosture{
+			PodName:             "virt-launcher-dpdk-traffic-gen-abcde",
+			SELinuxType:         "container_t",
+			SELinuxLevel:        "s0:c1,c2",
+			SeccompProfile:      "RuntimeDefault",
+			RunAsUser:           &runAsUser,
+			AddedCapabilities:   []string{"NET_BIND_SERVICE", "SYS_NICE"},
+			DroppedCapabilities: []string{"ALL"},
+		},
+		VMUnderTestLauncherSecurity: &status.LauncherSecurityPosture{
+			PodName:    "virt-launcher-vmi-under-test-abcde",
+			Privileged: true,
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(false, checkupStatus)
+	expectedReportData["status.result.trafficGenLauncherSecurity"] = "pod: virt-launcher-dpdk-traffic-gen-abcde; " +
+		"selinux: container_t:s0:c1,c2; seccomp: RuntimeDefault; runAsUser: 107; privileged: false; " +
+		"capabilities: +NET_BIND_SERVICE,+SYS_NICE,-ALL"
+	expectedReportData["status.result.vmUnderTestLauncherSecurity"] = "pod: virt-launcher-vmi-under-test-abcde; " +
+		"selinux: <unset>; seccomp: <unset>; runAsUser: <unset>; privileged: true; capabilities: <unset>"
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportResourceFootprint(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	const checkupDuration = 7 * time.Minute
+	checkupStatus.CompletionTimestamp = checkupStatus.StartTimestamp.Add(checkupDuration)
+	checkupStatus.FailureReason = []string{"setup: failed to wait for VMI to be ready"}
+	checkupStatus.Results = status.Results{
+		ResourceFootprint: &status.ResourceFootprint{DedicatedCPUs: 18, HugepagesGiB: 8, VFs: 4},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	expectedReportData := createBasicExpectedReporterConfigmapData(false, checkupStatus)
+	expectedReportData["status.result.footprintDedicatedCPUs"] = "18"
+	expectedReportData["status.result.footprintHugepagesGiB"] = "8"
+	expectedReportData["status.result.footprintVFs"] = "4"
+	expectedReportData["status.result.footprintDurationSeconds"] = "420"
+
+	assert.Equal(t, expectedReportData, getCheckupData(t, fakeClient, testNamespace, testConfigMapName))
+}
+
+func TestReportShouldReportTrafficGenGatewayResolution(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets: 1000,
+		TrafficGenGatewayResolution: []status.GatewayResolution{
+			{Port: 0, IP: "10.10.10.1", MAC: "52:54:00:00:00:01"},
+			{Port: 1, IP: "10.10.20.1", MAC: "52:54:00:00:00:02"},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"port 0: 10.10.10.1 is at 52:54:00:00:00:01; port 1: 10.10.20.1 is at 52:54:00:00:00:02",
+		checkupData["status.result.trafficGenGatewayResolution"])
+}
+
+func TestReportShouldReportTrafficGenPortAddresses(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets: 1000,
+		TrafficGenPortAddresses: []status.PortAddress{
+			{Port: 0, IP: "192.168.100.2", Gateway: "192.168.100.1"},
+			{Port: 1, IP: "192.168.200.2", Gateway: "192.168.200.1"},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"port 0: 192.168.100.2 via 192.168.100.1; port 1: 192.168.200.2 via 192.168.200.1",
+		checkupData["status.result.trafficGenPortAddresses"])
+}
+
+func TestReportShouldReportStatsReads(t *testing.T) {
+	const skewWarning = "the traffic generator and VM under test stats were read more than 5s apart (iteration 1: 7s)"
+
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Date(2023, 8, 20, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	trafficGenRead := checkupStatus.StartTimestamp.Add(5 * time.Minute)
+	checkupStatus.CompletionTimestamp = checkupStatus.StartTimestamp.Add(10 * time.Minute)
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets: 1000,
+		StatsReads:            []status.StatsRead{{TrafficGen: trafficGenRead, VMUnderTest: trafficGenRead.Add(7 * time.Second)}},
+		StatsSkewWarning:      skewWarning,
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"1: trafficGen 2023-08-20T10:05:00.000Z, vmUnderTest 2023-08-20T10:05:07.000Z (skew 7s)",
+		checkupData["status.result.statsReads"])
+	assert.Equal(t, skewWarning, checkupData["status.result.statsSkewWarning"])
+}
+
+func TestReportShouldReportPerQueueStats(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets: 300,
+		PerQueueStats: []status.QueueStats{
+			{RXPort: 0, RXQueue: 0, TXPort: 1, TXQueue: 0, RXPackets: 200, TXPackets: 200},
+			{RXPort: 0, RXQueue: 1, TXPort: 1, TXQueue: 1, RXPackets: 100, TXPackets: 98, TXDropped: 2},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"0/0->1/0: rx 200, tx 200, txDropped 0; 0/1->1/1: rx 100, tx 98, txDropped 2",
+		checkupData["status.result.perQueueStats"])
+}
+
+func TestReportShouldReportPerStreamStats(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets: 1500,
+		PerStreamStats: []status.StreamStats{
+			{ID: 0, TXPort: 0, TXPackets: 1000, RXPackets: 998},
+			{ID: 100, TXPort: 1, TXPackets: 500, RXPackets: 500},
+		},
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t,
+		"0 from port 0: tx 1000, rx 998; 100 from port 1: tx 500, rx 500",
+		checkupData["status.result.perStreamStats"])
+}
+
+func TestReportShouldReportVMUnderTestTestpmdCommand(t *testing.T) {
+	const testpmdCommand = "dpdk-testpmd --lcores 0@2-3,1@4,2@5,3@6,4@7 -a 0000:06:00.0 -a 0000:07:00.0 -n 4 -- -i"
+
+	fakeClient := fake.NewSimpleClientset(newConfigMap())
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupStatus.Succeeded = true
+	checkupStatus.CompletionTimestamp = time.Now()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:     1000,
+		VMUnderTestTestpmdCommand: testpmdCommand,
+	}
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	checkupData := getCheckupData(t, fakeClient, testNamespace, testConfigMapName)
+	assert.Equal(t, testpmdCommand, checkupData["status.result.vmUnderTestTestpmdCommand"])
+}
+
 func TestReportShouldFailWhenCannotUpdateConfigMap(t *testing.T) {
 	// ConfigMap does not exist
 	fakeClient := fake.NewSimpleClientset()
 
-	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName)
+	testReporter := reporter.New(fakeClient, testNamespace, testConfigMapName, false)
 
 	assert.ErrorContains(t, testReporter.Report(status.Status{}), "not found")
 }
@@ -186,8 +1066,20 @@ func createExpectedReporterConfigmapDataWithResults(succeeded bool, checkupStatu
 	results["status.result.vmUnderTestReceivedPackets"] = fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestReceivedPackets)
 	results["status.result.vmUnderTestRxDroppedPackets"] = fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestRxDroppedPackets)
 	results["status.result.vmUnderTestTxDroppedPackets"] = fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestTxDroppedPackets)
+	results["status.result.trafficGenAvgTxPps"] = fmt.Sprintf("%.0f", checkupStatus.Results.TrafficGenAvgTxPps)
+	results["status.result.trafficGenMaxTxPps"] = fmt.Sprintf("%.0f", checkupStatus.Results.TrafficGenMaxTxPps)
+	results["status.result.trafficGenAvgRxPps"] = fmt.Sprintf("%.0f", checkupStatus.Results.TrafficGenAvgRxPps)
+	results["status.result.trafficGenMaxRxPps"] = fmt.Sprintf("%.0f", checkupStatus.Results.TrafficGenMaxRxPps)
+	results["status.result.trafficGenAvgTxGbps"] = fmt.Sprintf("%.3f", checkupStatus.Results.TrafficGenAvgTxBps/1e9)
+	results["status.result.trafficGenMaxTxGbps"] = fmt.Sprintf("%.3f", checkupStatus.Results.TrafficGenMaxTxBps/1e9)
+	results["status.result.trafficGenAvgRxGbps"] = fmt.Sprintf("%.3f", checkupStatus.Results.TrafficGenAvgRxBps/1e9)
+	results["status.result.trafficGenMaxRxGbps"] = fmt.Sprintf("%.3f", checkupStatus.Results.TrafficGenMaxRxBps/1e9)
+	results["status.result.maxDropRateBps"] = fmt.Sprintf("%.0f", checkupStatus.Results.MaxDropRateBps)
 	results["status.result.trafficGenActualNodeName"] = checkupStatus.Results.TrafficGenActualNodeName
 	results["status.result.vmUnderTestActualNodeName"] = checkupStatus.Results.VMUnderTestActualNodeName
+	results["status.result.ownershipMode"] = checkupStatus.Results.OwnershipMode
+	results["status.result.trafficGenConsoleReconnects"] = fmt.Sprintf("%d", checkupStatus.Results.TrafficGenConsoleReconnects)
+	results["status.result.vmUnderTestConsoleReconnects"] = fmt.Sprintf("%d", checkupStatus.Results.VMUnderTestConsoleReconnects)
 	return results
 }
 
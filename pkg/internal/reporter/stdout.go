@@ -0,0 +1,92 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kiagnose/kiagnose/kiagnose/types"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+// StdoutReporter prints the final checkup status to the given writer instead of
+// updating the user-supplied ConfigMap.
+// The verdict and the results JSON are printed as the last two lines, to be consumed by CI tasks.
+type StdoutReporter struct {
+	out         io.Writer
+	jsonResults bool
+}
+
+func NewStdout(out io.Writer, jsonResults bool) *StdoutReporter {
+	return &StdoutReporter{out: out, jsonResults: jsonResults}
+}
+
+func (r *StdoutReporter) Report(checkupStatus status.Status) error {
+	if checkupStatus.CompletionTimestamp.IsZero() {
+		return nil
+	}
+
+	data, err := formatCompletedStatus(checkupStatus, r.jsonResults)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	verdict := "succeeded"
+	if len(checkupStatus.FailureReason) != 0 {
+		verdict = "failed"
+	}
+
+	_, err = fmt.Fprintf(r.out, "checkup verdict: %s\n%s\n", verdict, jsonData)
+	return err
+}
+
+// formatCompletedStatus returns the final checkup status using the same keys the kiagnose ConfigMap is updated with.
+func formatCompletedStatus(checkupStatus status.Status, jsonResults bool) (map[string]string, error) {
+	checkupStatus.Succeeded = len(checkupStatus.FailureReason) == 0
+
+	data := map[string]string{
+		types.SucceededKey:           strconv.FormatBool(checkupStatus.Succeeded),
+		types.FailureReasonKey:       strings.Join(checkupStatus.FailureReason, ","),
+		types.StartTimestampKey:      checkupStatus.StartTimestamp.Format(time.RFC3339),
+		types.CompletionTimestampKey: checkupStatus.CompletionTimestamp.Format(time.RFC3339),
+	}
+
+	results, err := formatResults(checkupStatus, jsonResults)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range results {
+		data[types.ResultsPrefix+k] = v
+	}
+
+	return data, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/reporter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+func TestStdoutReporterShouldNotPrintOnStart(t *testing.T) {
+	var out bytes.Buffer
+	testReporter := reporter.NewStdout(&out, false)
+
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+
+	assert.NoError(t, testReporter.Report(checkupStatus))
+	assert.Empty(t, out.String())
+}
+
+func TestStdoutReporterShouldPrintVerdictAndResults(t *testing.T) {
+	t.Run("on checkup success", func(t *testing.T) {
+		var out bytes.Buffer
+		testReporter := reporter.NewStdout(&out, false)
+
+		checkupStatus := newCompletedStatus()
+		checkupStatus.Results = status.Results{
+			TrafficGenSentPackets:      100,
+			VMUnderTestReceivedPackets: 100,
+		}
+
+		assert.NoError(t, testReporter.Report(checkupStatus))
+
+		verdict, data := parseStdoutReport(t, out.String())
+		assert.Equal(t, "checkup verdict: succeeded", verdict)
+		assert.Equal(t, createExpectedReporterConfigmapDataWithResults(true, checkupStatus), data)
+	})
+
+	t.Run("on checkup failure", func(t *testing.T) {
+		var out bytes.Buffer
+		testReporter := reporter.NewStdout(&out, false)
+
+		checkupStatus := newCompletedStatus()
+		checkupStatus.FailureReason = []string{"some reason"}
+
+		assert.NoError(t, testReporter.Report(checkupStatus))
+
+		verdict, data := parseStdoutReport(t, out.String())
+		assert.Equal(t, "checkup verdict: failed", verdict)
+		assert.Equal(t, createBasicExpectedReporterConfigmapData(false, checkupStatus), data)
+	})
+}
+
+func TestStdoutReporterShouldPrintJSONResultsWhenRequested(t *testing.T) {
+	var out bytes.Buffer
+	testReporter := reporter.NewStdout(&out, true)
+
+	checkupStatus := newCompletedStatus()
+	checkupStatus.Results = status.Results{
+		TrafficGenSentPackets:      100,
+		VMUnderTestReceivedPackets: 100,
+	}
+
+	assert.NoError(t, testReporter.Report(checkupStatus))
+
+	_, data := parseStdoutReport(t, out.String())
+	var actualJSONResults status.Results
+	assert.NoError(t, json.Unmarshal([]byte(data["status.result.json"]), &actualJSONResults))
+	assert.Equal(t, checkupStatus.Results, actualJSONResults)
+}
+
+func newCompletedStatus() status.Status {
+	var checkupStatus status.Status
+	checkupStatus.StartTimestamp = time.Now()
+	checkupStatus.CompletionTimestamp = time.Now()
+	return checkupStatus
+}
+
+func parseStdoutReport(t *testing.T, output string) (verdict string, data map[string]string) {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	assert.Len(t, lines, 2)
+
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &data))
+
+	return lines[0], data
+}
@@ -0,0 +1,78 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package sim
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/guestagent"
+)
+
+// ExecInPod serves the guest agent commands sent by virsh in the virt-launcher Pods. The commands are executed by the
+// scripted guest shell at once, so their status reports them as exited on the first read.
+func (c *Client) ExecInPod(_ context.Context, namespace, podName, _ string, command []string) (string, error) {
+	const agentCommandArgs = 6
+	if len(command) != agentCommandArgs || command[0] != "virsh" || command[3] != "qemu-agent-command" {
+		return "", fmt.Errorf("unsupported command %q in Pod %q", strings.Join(command, " "), podName)
+	}
+	vmiName := strings.TrimPrefix(command[4], guestagent.DomainName(namespace, ""))
+
+	var agentCommand struct {
+		Execute   string `json:"execute"`
+		Arguments struct {
+			Arg []string `json:"arg"`
+			PID int      `json:"pid"`
+		} `json:"arguments"`
+	}
+	if err := json.Unmarshal([]byte(command[5]), &agentCommand); err != nil {
+		return "", err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch agentCommand.Execute {
+	case "guest-exec":
+		args := agentCommand.Arguments.Arg
+		output := c.guest.runShellCommand(vmiName, args[len(args)-1])
+		c.agentExecs = append(c.agentExecs, guestagent.ExecStatus{
+			Exited:  true,
+			OutData: base64.StdEncoding.EncodeToString([]byte(output)),
+		})
+		return agentResponse(map[string]int{"pid": len(c.agentExecs)})
+	case "guest-exec-status":
+		pid := agentCommand.Arguments.PID
+		if pid < 1 || pid > len(c.agentExecs) {
+			return "", fmt.Errorf("no such guest-exec PID %d", pid)
+		}
+		return agentResponse(c.agentExecs[pid-1])
+	}
+
+	return "", fmt.Errorf("unsupported guest agent command %q", agentCommand.Execute)
+}
+
+func agentResponse(result interface{}) (string, error) {
+	response, err := json.Marshal(map[string]interface{}{"return": result})
+	return string(response), err
+}
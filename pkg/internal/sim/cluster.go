@@ -0,0 +1,490 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package sim
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/console"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/guestagent"
+)
+
+const (
+	KubeVirtVersion                 = "v1.1.0"
+	NetworkAttachmentDefinitionName = "sim-sriov-network"
+	SRIOVResourceName               = "sim.io/sriov_vfs"
+)
+
+// NodeNames are the worker nodes of the simulated cluster.
+var NodeNames = []string{"sim-worker1", "sim-worker2"}
+
+const (
+	createdByLabelKey             = "kubevirt.io/created-by"
+	networkResourceNameAnnotation = "k8s.v1.cni.cncf.io/resourceName"
+	hostnameLabelKey              = "kubernetes.io/hostname"
+)
+
+// Client is an in-memory cluster, on which the VMIs are scheduled and become ready as soon as they are created.
+// Its VMIs serial consoles and guest agents are served by a scripted guest.
+// The core Kubernetes objects, e.g. ConfigMaps, Pods and Nodes, are kept by a fake clientset.
+type Client struct {
+	kubernetes.Interface
+	*console.ScriptedClient
+
+	guest      *guest
+	mutex      sync.Mutex
+	vmis       map[string]*kvcorev1.VirtualMachineInstance
+	vms        map[string]*kvcorev1.VirtualMachine
+	migrations map[string]*kvcorev1.VirtualMachineInstanceMigration
+	agentExecs []guestagent.ExecStatus
+}
+
+// NewClient returns a simulated cluster, holding the given objects in addition to its nodes.
+func NewClient(objects ...runtime.Object) *Client {
+	for _, nodeName := range NodeNames {
+		objects = append(objects, newNode(nodeName))
+	}
+
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.PrependReactor("create", "*", generateNameReactor)
+
+	simGuest := newGuest()
+	return &Client{
+		Interface:      clientset,
+		ScriptedClient: console.NewScriptedClient(simGuest),
+		guest:          simGuest,
+		vmis:           map[string]*kvcorev1.VirtualMachineInstance{},
+		vms:            map[string]*kvcorev1.VirtualMachine{},
+		migrations:     map[string]*kvcorev1.VirtualMachineInstanceMigration{},
+	}
+}
+
+func (c *Client) CreateVirtualMachineInstance(ctx context.Context,
+	namespace string,
+	vmi *kvcorev1.VirtualMachineInstance) (*kvcorev1.VirtualMachineInstance, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.createVMI(ctx, namespace, vmi)
+}
+
+func (c *Client) GetVirtualMachineInstance(_ context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstance, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	vmi, exists := c.vmis[fullName(namespace, name)]
+	if !exists {
+		return nil, k8serrors.NewNotFound(kvcorev1.Resource("virtualmachineinstances"), name)
+	}
+
+	return vmi.DeepCopy(), nil
+}
+
+func (c *Client) ListVirtualMachineInstances(_ context.Context,
+	namespace, labelSelector string) (*kvcorev1.VirtualMachineInstanceList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	list := &kvcorev1.VirtualMachineInstanceList{}
+	for _, vmi := range c.vmis {
+		if vmi.Namespace == namespace && selector.Matches(labels.Set(vmi.Labels)) {
+			list.Items = append(list.Items, *vmi.DeepCopy())
+		}
+	}
+
+	return list, nil
+}
+
+// WatchVirtualMachineInstance returns a watch with no events, as the VMIs are ready once created and gone once deleted.
+func (c *Client) WatchVirtualMachineInstance(_ context.Context, _, _, _ string) (watch.Interface, error) {
+	return watch.NewRaceFreeFake(), nil
+}
+
+func (c *Client) DeleteVirtualMachineInstance(ctx context.Context, namespace, name string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.deleteVMI(ctx, namespace, name)
+}
+
+// CreateVirtualMachine creates the VM and starts its VMI, named after it.
+func (c *Client) CreateVirtualMachine(ctx context.Context,
+	namespace string,
+	vm *kvcorev1.VirtualMachine) (*kvcorev1.VirtualMachine, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	createdVM := vm.DeepCopy()
+	createdVM.Namespace = namespace
+	createdVM.Name = generateName(vm.ObjectMeta)
+	createdVM.UID = types.UID(rand.String(16))
+
+	vmi := &kvcorev1.VirtualMachineInstance{
+		ObjectMeta: *vm.Spec.Template.ObjectMeta.DeepCopy(),
+		Spec:       *vm.Spec.Template.Spec.DeepCopy(),
+	}
+	vmi.Name = createdVM.Name
+	if _, err := c.createVMI(ctx, namespace, vmi); err != nil {
+		return nil, err
+	}
+
+	c.vms[fullName(namespace, createdVM.Name)] = createdVM
+
+	return createdVM.DeepCopy(), nil
+}
+
+func (c *Client) ListVirtualMachines(_ context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	list := &kvcorev1.VirtualMachineList{}
+	for _, vm := range c.vms {
+		if vm.Namespace == namespace && selector.Matches(labels.Set(vm.Labels)) {
+			list.Items = append(list.Items, *vm.DeepCopy())
+		}
+	}
+
+	return list, nil
+}
+
+func (c *Client) DeleteVirtualMachine(ctx context.Context, namespace, name string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.vms[fullName(namespace, name)]; !exists {
+		return k8serrors.NewNotFound(kvcorev1.Resource("virtualmachines"), name)
+	}
+	delete(c.vms, fullName(namespace, name))
+
+	return c.deleteVMI(ctx, namespace, name)
+}
+
+// MigrateVirtualMachineInstance moves the VMI to another node at once, the migration is reported as succeeded.
+func (c *Client) MigrateVirtualMachineInstance(_ context.Context,
+	namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	vmi, exists := c.vmis[fullName(namespace, name)]
+	if !exists {
+		return nil, k8serrors.NewNotFound(kvcorev1.Resource("virtualmachineinstances"), name)
+	}
+
+	sourceNode := vmi.Status.NodeName
+	targetNode := otherNode(sourceNode)
+	vmi.Status.NodeName = targetNode
+
+	now := metav1.Now()
+	migration := &kvcorev1.VirtualMachineInstanceMigration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: generateName(metav1.ObjectMeta{GenerateName: name + "-migration-"})},
+		Spec:       kvcorev1.VirtualMachineInstanceMigrationSpec{VMIName: name},
+		Status: kvcorev1.VirtualMachineInstanceMigrationStatus{
+			Phase: kvcorev1.MigrationSucceeded,
+			MigrationState: &kvcorev1.VirtualMachineInstanceMigrationState{
+				SourceNode:     sourceNode,
+				TargetNode:     targetNode,
+				StartTimestamp: &now,
+				EndTimestamp:   &now,
+				Completed:      true,
+			},
+		},
+	}
+	c.migrations[fullName(namespace, migration.Name)] = migration
+
+	return migration.DeepCopy(), nil
+}
+
+func (c *Client) GetVirtualMachineInstanceMigration(_ context.Context,
+	namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	migration, exists := c.migrations[fullName(namespace, name)]
+	if !exists {
+		return nil, k8serrors.NewNotFound(kvcorev1.Resource("virtualmachineinstancemigrations"), name)
+	}
+
+	return migration.DeepCopy(), nil
+}
+
+func (c *Client) CreateConfigMap(ctx context.Context, namespace string, configMap *k8scorev1.ConfigMap) (*k8scorev1.ConfigMap, error) {
+	return c.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+}
+
+func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*k8scorev1.ConfigMap, error) {
+	return c.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Client) DeleteConfigMap(ctx context.Context, namespace, name string) error {
+	return c.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *Client) ListConfigMaps(ctx context.Context, namespace, labelSelector string) (*k8scorev1.ConfigMapList, error) {
+	return c.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (c *Client) CreateEvent(ctx context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error) {
+	return c.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{})
+}
+
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (*k8scorev1.Pod, error) {
+	return c.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Client) ListPods(ctx context.Context, namespace, labelSelector string) (*k8scorev1.PodList, error) {
+	return c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (c *Client) GetNode(ctx context.Context, name string) (*k8scorev1.Node, error) {
+	return c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Client) ListNodes(ctx context.Context, labelSelector string) (*k8scorev1.NodeList, error) {
+	return c.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+// GetNetworkAttachmentDefinition returns an SR-IOV Network-Attachment-Definition, whatever its name is.
+func (c *Client) GetNetworkAttachmentDefinition(_ context.Context,
+	namespace, name string) (*netattdefv1.NetworkAttachmentDefinition, error) {
+	return &netattdefv1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{networkResourceNameAnnotation: SRIOVResourceName},
+		},
+		Spec: netattdefv1.NetworkAttachmentDefinitionSpec{
+			Config: fmt.Sprintf(`{"cniVersion":"0.3.1","name":%q,"type":"sriov"}`, name),
+		},
+	}, nil
+}
+
+func (c *Client) GetKubeVirtVersion(_ context.Context) (string, error) {
+	return KubeVirtVersion, nil
+}
+
+// VMIs returns the names of the VMIs that currently exist, e.g. to verify none is left after the teardown.
+func (c *Client) VMIs() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var names []string
+	for name := range c.vmis {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// createVMI schedules the VMI according to its required affinity, and starts a running virt-launcher Pod for it.
+// The caller must hold the mutex.
+func (c *Client) createVMI(ctx context.Context,
+	namespace string,
+	vmi *kvcorev1.VirtualMachineInstance) (*kvcorev1.VirtualMachineInstance, error) {
+	createdVMI := vmi.DeepCopy()
+	createdVMI.Namespace = namespace
+	createdVMI.Name = generateName(vmi.ObjectMeta)
+	createdVMI.UID = types.UID(rand.String(16))
+	if _, exists := c.vmis[fullName(namespace, createdVMI.Name)]; exists {
+		return nil, k8serrors.NewAlreadyExists(kvcorev1.Resource("virtualmachineinstances"), createdVMI.Name)
+	}
+
+	now := metav1.Now()
+	createdVMI.Status = kvcorev1.VirtualMachineInstanceStatus{
+		Phase:    kvcorev1.Running,
+		NodeName: c.scheduleVMI(createdVMI),
+		Conditions: []kvcorev1.VirtualMachineInstanceCondition{
+			{Type: kvcorev1.VirtualMachineInstanceReady, Status: k8scorev1.ConditionTrue, LastTransitionTime: now},
+			{Type: kvcorev1.VirtualMachineInstanceAgentConnected, Status: k8scorev1.ConditionTrue, LastTransitionTime: now},
+			{Type: kvcorev1.VirtualMachineInstanceIsMigratable, Status: k8scorev1.ConditionTrue, LastTransitionTime: now},
+		},
+	}
+
+	launcherPod := &k8scorev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "virt-launcher-" + createdVMI.Name,
+			Labels:    map[string]string{createdByLabelKey: string(createdVMI.UID)},
+		},
+		Spec: k8scorev1.PodSpec{
+			NodeName:   createdVMI.Status.NodeName,
+			Containers: []k8scorev1.Container{{Name: "compute"}},
+		},
+		Status: k8scorev1.PodStatus{Phase: k8scorev1.PodRunning},
+	}
+	if _, err := c.CoreV1().Pods(namespace).Create(ctx, launcherPod, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+
+	c.vmis[fullName(namespace, createdVMI.Name)] = createdVMI
+	c.guest.attachNICs(createdVMI.Name, createdVMI.Spec.Domain.Devices.Interfaces)
+
+	return createdVMI.DeepCopy(), nil
+}
+
+// deleteVMI deletes the VMI and its virt-launcher Pod at once.
+// The caller must hold the mutex.
+func (c *Client) deleteVMI(ctx context.Context, namespace, name string) error {
+	if _, exists := c.vmis[fullName(namespace, name)]; !exists {
+		return k8serrors.NewNotFound(kvcorev1.Resource("virtualmachineinstances"), name)
+	}
+	delete(c.vmis, fullName(namespace, name))
+
+	err := c.CoreV1().Pods(namespace).Delete(ctx, "virt-launcher-"+name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// scheduleVMI returns the node the VMI is scheduled to: the node its required node affinity selects,
+// the node of the other VMIs when it has a required Pod affinity, or otherwise a node no other VMI runs on, when possible.
+// The caller must hold the mutex.
+func (c *Client) scheduleVMI(vmi *kvcorev1.VirtualMachineInstance) string {
+	if nodeName := requiredNodeName(vmi.Spec.Affinity); nodeName != "" {
+		return nodeName
+	}
+
+	usedNodes := map[string]bool{}
+	for _, existingVMI := range c.vmis {
+		usedNodes[existingVMI.Status.NodeName] = true
+	}
+
+	if vmi.Spec.Affinity != nil && vmi.Spec.Affinity.PodAffinity != nil {
+		for _, nodeName := range NodeNames {
+			if usedNodes[nodeName] {
+				return nodeName
+			}
+		}
+	}
+
+	for _, nodeName := range NodeNames {
+		if !usedNodes[nodeName] {
+			return nodeName
+		}
+	}
+
+	return NodeNames[0]
+}
+
+func requiredNodeName(affinity *k8scorev1.Affinity) string {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == hostnameLabelKey && expression.Operator == k8scorev1.NodeSelectorOpIn && len(expression.Values) > 0 {
+				return expression.Values[0]
+			}
+		}
+	}
+
+	return ""
+}
+
+func otherNode(nodeName string) string {
+	for _, otherNodeName := range NodeNames {
+		if otherNodeName != nodeName {
+			return otherNodeName
+		}
+	}
+
+	return nodeName
+}
+
+// newNode returns a ready node, passing all the checkup pre-flight checks.
+func newNode(name string) *k8scorev1.Node {
+	return &k8scorev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				hostnameLabelKey:         name,
+				kvcorev1.NodeSchedulable: "true",
+				kvcorev1.CPUManager:      "true",
+			},
+		},
+		Status: k8scorev1.NodeStatus{
+			Allocatable: k8scorev1.ResourceList{
+				k8scorev1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("16Gi"),
+				SRIOVResourceName:                         resource.MustParse("8"),
+			},
+			Conditions: []k8scorev1.NodeCondition{{Type: k8scorev1.NodeReady, Status: k8scorev1.ConditionTrue}},
+			NodeInfo:   k8scorev1.NodeSystemInfo{KernelVersion: "5.14.0-284.11.1.rt14.296.el9_2.x86_64"},
+		},
+	}
+}
+
+// generateNameReactor names the created objects by their generated name prefix, as the API server does.
+func generateNameReactor(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+	createAction, ok := action.(k8stesting.CreateAction)
+	if !ok {
+		return false, nil, nil
+	}
+
+	if object, err := meta.Accessor(createAction.GetObject()); err == nil && object.GetName() == "" {
+		object.SetName(generateName(metav1.ObjectMeta{GenerateName: object.GetGenerateName()}))
+	}
+
+	return false, nil, nil
+}
+
+func generateName(objectMeta metav1.ObjectMeta) string {
+	if objectMeta.Name != "" {
+		return objectMeta.Name
+	}
+
+	const randomSuffixLength = 5
+	return objectMeta.GenerateName + rand.String(randomSuffixLength)
+}
+
+func fullName(namespace, name string) string {
+	return namespace + "/" + name
+}
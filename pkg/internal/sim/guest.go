@@ -0,0 +1,501 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor/telemetry"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/version"
+)
+
+const (
+	testpmdPrompt = "testpmd> "
+
+	// guestKernelArgs isolate the guest CPUs the DPDK applications run on.
+	guestKernelArgs = "BOOT_IMAGE=(hd0,gpt3)/vmlinuz root=UUID=sim ro console=ttyS0 default_hugepagesz=1G hugepagesz=1G hugepages=1 " +
+		"isolcpus=managed_irq,domain,2-7 nohz_full=2-7 rcu_nocbs=2-7"
+	guestIsolatedCPUs = "2-7"
+
+	guestKernelRelease = "5.14.0-362.el9.x86_64"
+	guestDPDKVersion   = "22.11"
+
+	// TrexVersion is the TRex version the simulated traffic generator image was built with.
+	TrexVersion = "v3.03"
+
+	// firstNICPCIBus is the bus of the first SR-IOV NIC, as allocated unless pinned.
+	firstNICPCIBus = 0x0a
+
+	// lineRatePps is the line rate of the simulated NICs, i.e. the 10Gb/s line rate of 64 bytes frames.
+	lineRatePps = 14880952
+)
+
+var (
+	// e.g. `cd /opt/trex && echo "verbose on;stats -g" | ./trex-console -q`
+	trexConsoleRegex = regexp.MustCompile(`^cd \S+ && echo "(.*)" \| \./trex-console`)
+	// e.g. `start -f /opt/tests/testpmd.py -m 8mpps -p 0 1 -d 300` or `start -f /opt/tests/testpmd.py -m 50% -p 0 -d 30`
+	trexStartRegex = regexp.MustCompile(`^start .*-m (?:(\d+(?:\.\d+)?)([kmg]?)pps|(\d+)%) -p ([\d ]+) -d (\d+)`)
+	// e.g. `start -f /opt/tests/testpmd_burst.py -m 100% -p 0 -t burst_size=32`
+	trexBurstRegex   = regexp.MustCompile(`^start .*-p ([\d ]+) -t burst_size=(\d+)$`)
+	trexPortRegex    = regexp.MustCompile(`^stats --port (\d+) -p$`)
+	testpmdRegex     = regexp.MustCompile(`testpmd .*--forward-mode=`)
+	testpmdPortRegex = regexp.MustCompile(`(?:^| )-a \S+`)
+	echoRegex        = regexp.MustCompile(`^echo "(.*)"$`)
+	// e.g. `printf '/ethdev/stats,0\n/ethdev/stats,1\n' | dpdk-telemetry.py`
+	telemetryRegex = regexp.MustCompile(`^printf '(.*)' \| ` + regexp.QuoteMeta(telemetry.ScriptName) + `$`)
+	substitution   = regexp.MustCompile(`\$\([^)]*\)`)
+	// e.g. `cat /etc/kubevirt-dpdk-checkup/version 2>/dev/null`
+	catRegex = regexp.MustCompile(`^cat (\S+) 2>/dev/null$`)
+)
+
+// guestFiles holds the files baked into the guest images which the checkup reads, as built for the running checkup.
+var guestFiles = map[string]string{
+	version.ImageManifestPath:                 version.Checkup,
+	"/etc/kubevirt-dpdk-checkup/trex-version": TrexVersion,
+}
+
+// guest plays the shell of the checkup VMIs, the TRex console on the traffic generator and testpmd on the VM under test.
+// The traffic the TRex console starts is forwarded by testpmd without loss, at the requested rate, so both sides
+// count the packets sent during the elapsed part of each traffic run.
+type guest struct {
+	mutex sync.Mutex
+	// testpmdPorts holds the number of ports of the testpmd instance each VMI runs, if any.
+	testpmdPorts map[string]int
+	// pciAddresses holds the PCI addresses file the boot script of each VMI keeps, listing its SR-IOV NICs.
+	pciAddresses     map[string]string
+	runs             []*trafficRun
+	trexClearTime    time.Time
+	testpmdStartTime time.Time
+	testpmdClearTime time.Time
+}
+
+type trafficRun struct {
+	start       time.Time
+	end         time.Time
+	stopped     bool
+	rate        float64
+	sourcePorts []int
+}
+
+func newGuest() *guest {
+	return &guest{testpmdPorts: map[string]int{}, pciAddresses: map[string]string{}}
+}
+
+// attachNICs attaches the SR-IOV NICs of the VMI interfaces, at their pinned PCI addresses, or otherwise at consecutive
+// buses, as the boot script discovers them.
+func (g *guest) attachNICs(vmiName string, interfaces []kvcorev1.Interface) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	sb := strings.Builder{}
+	for idx, iface := range interfaces {
+		if iface.SRIOV == nil {
+			continue
+		}
+		pciAddress := iface.PciAddress
+		if pciAddress == "" {
+			pciAddress = fmt.Sprintf("0000:%02x:00.0", firstNICPCIBus+idx)
+		}
+		sb.WriteString(iface.MacAddress + " " + pciAddress + "\n")
+	}
+	g.pciAddresses[vmiName] = sb.String()
+}
+
+// Execute runs the line on the VMI console, in testpmd when it runs on the VMI, or otherwise in the shell.
+func (g *guest) Execute(vmiName, line string) string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var output string
+	if portsCount, running := g.testpmdPorts[vmiName]; running {
+		output = g.executeTestpmd(vmiName, line, portsCount)
+	} else {
+		output = g.executeShell(vmiName, line)
+	}
+
+	if _, running := g.testpmdPorts[vmiName]; running {
+		return output + testpmdPrompt
+	}
+	return output + fmt.Sprintf("[root@%s ~]# ", vmiName)
+}
+
+// runShellCommand runs the command in the VMI shell, as the guest agent does, regardless of the VMI console state.
+func (g *guest) runShellCommand(vmiName, command string) string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.executeShell(vmiName, command)
+}
+
+func (g *guest) executeShell(vmiName, line string) string {
+	switch {
+	case line == "":
+		return ""
+	case line == "echo $?":
+		return "0\n"
+	case testpmdRegex.MatchString(line):
+		g.testpmdPorts[vmiName] = len(testpmdPortRegex.FindAllString(line, -1))
+		g.testpmdStartTime = time.Now()
+		g.testpmdClearTime = g.testpmdStartTime
+		return "EAL: Detected CPU lcores: 8\nChecking link statuses...\nDone\n"
+	case trexConsoleRegex.MatchString(line):
+		return g.executeTrexConsole(trexConsoleRegex.FindStringSubmatch(line)[1])
+	case telemetryRegex.MatchString(line):
+		if _, running := g.testpmdPorts[vmiName]; running {
+			return g.testpmdTelemetry(telemetryRegex.FindStringSubmatch(line)[1], time.Now())
+		}
+		return "Connecting to /var/run/dpdk/rte/dpdk_telemetry.v2\nNo DPDK apps with telemetry enabled available\n"
+	case echoRegex.MatchString(line):
+		return substitution.ReplaceAllStringFunc(echoRegex.FindStringSubmatch(line)[1], substitute) + "\n"
+	case catRegex.MatchString(line):
+		return guestFiles[catRegex.FindStringSubmatch(line)[1]] + "\n"
+	}
+
+	switch line {
+	case "cat /proc/cmdline":
+		return guestKernelArgs + "\n"
+	case "uname -r":
+		return guestKernelRelease + "\n"
+	case "rpm -q --queryformat '%{VERSION}' dpdk":
+		return guestDPDKVersion + "\n"
+	case "tuned-adm active":
+		return "Current active profile: cpu-partitioning\n"
+	case "cat /sys/devices/system/cpu/isolated":
+		return guestIsolatedCPUs + "\n"
+	case "cat /sys/devices/system/node/online":
+		return "0\n"
+	case "cat /sys/devices/system/node/node0/cpulist":
+		return "0-7\n"
+	case "cat " + config.BootScriptPCIAddressesFileFullPath:
+		return g.pciAddresses[vmiName]
+	}
+
+	switch {
+	case strings.HasPrefix(line, "lspci -nk -s "):
+		return strings.TrimPrefix(line, "lspci -nk -s ") + " 0200: 8086:154c (rev 01)\n" +
+			"\tSubsystem: 8086:0000\n\tKernel driver in use: vfio-pci\n\tKernel modules: iavf\n"
+	case strings.HasPrefix(line, "lspci -k -s "):
+		return strings.TrimPrefix(line, "lspci -k -s ") + " Ethernet controller: Intel Corporation Ethernet Virtual Function 700 Series\n" +
+			"\tKernel driver in use: vfio-pci\n\tKernel modules: iavf\n"
+	case strings.HasSuffix(line, "/nr_hugepages"):
+		return "1\n"
+	case strings.HasSuffix(line, "/numa_node"):
+		return "0\n"
+	}
+
+	// Any other command, e.g. starting the TRex server, succeeds silently.
+	return ""
+}
+
+// substitute returns the output of the given command substitution, which is empty unless it reads a guest file.
+func substitute(commandSubstitution string) string {
+	command := strings.TrimSuffix(strings.TrimPrefix(commandSubstitution, "$("), ")")
+	if matches := catRegex.FindStringSubmatch(command); matches != nil {
+		return guestFiles[matches[1]]
+	}
+	return ""
+}
+
+func (g *guest) executeTrexConsole(commands string) string {
+	sb := strings.Builder{}
+	sb.WriteString("Using 'python3' as Python interpeter\n\n-=TRex Console v3.0=-\n\ntrex>")
+	for _, command := range strings.Split(commands, ";") {
+		sb.WriteString(g.executeTrexCommand(command))
+	}
+	sb.WriteString("trex>Shutting down RPC client\n\n")
+	return sb.String()
+}
+
+func (g *guest) executeTrexCommand(command string) string {
+	now := time.Now()
+
+	switch {
+	case command == "help":
+		return "\nConsole Commands:\n\n"
+	case command == "clear":
+		g.trexClearTime = now
+		return "\nClearing stats: [SUCCESS]\n\n"
+	case strings.HasPrefix(command, "stop"):
+		for _, run := range g.runs {
+			if run.end.After(now) {
+				run.end = now
+			}
+			run.stopped = true
+		}
+		return "\nStopping traffic on port(s): [SUCCESS]\n\n"
+	case trexStartRegex.MatchString(command):
+		g.runs = append(g.runs, newTrafficRun(trexStartRegex.FindStringSubmatch(command), now))
+		return "\nStarting traffic on port(s): [SUCCESS]\n\n"
+	case trexBurstRegex.MatchString(command):
+		g.runs = append(g.runs, newBurstRun(trexBurstRegex.FindStringSubmatch(command), now))
+		return "\nStarting traffic on port(s): [SUCCESS]\n\n"
+	case strings.HasPrefix(command, "service -a"):
+		return "\nPort 0 - Received ARP reply from: 10.10.10.1, hw: 02:00:00:00:10:01\n" +
+			"Port 1 - Received ARP reply from: 10.10.20.1, hw: 02:00:00:00:20:01\n\n"
+	case command == "stats -g":
+		return serverResponse("get_global_stats", map[string]interface{}{"api_h": "sim"}, g.trexGlobalStats(now))
+	case command == "stats -s":
+		return serverResponse("get_active_pgids", map[string]interface{}{}, g.trexActivePgIDs()) +
+			serverResponse("get_pgid_stats", map[string]interface{}{}, g.trexPgIDStats(now))
+	case trexPortRegex.MatchString(command):
+		port, _ := strconv.Atoi(trexPortRegex.FindStringSubmatch(command)[1])
+		return serverResponse("get_port_stats", map[string]interface{}{"api_h": "sim", "port_id": port}, g.trexPortStats(port, now))
+	}
+
+	return "\n"
+}
+
+func newTrafficRun(match []string, now time.Time) *trafficRun {
+	rate, _ := strconv.ParseFloat(match[1], 64)
+	rate *= map[string]float64{"": 1, "k": 1e3, "m": 1e6, "g": 1e9}[match[2]]
+	if match[3] != "" {
+		percent, _ := strconv.Atoi(match[3])
+		rate = float64(lineRatePps*percent) / 100
+	}
+	seconds, _ := strconv.Atoi(match[5])
+
+	run := &trafficRun{start: now, end: now.Add(time.Duration(seconds) * time.Second), rate: rate}
+	for _, field := range strings.Fields(match[4]) {
+		port, _ := strconv.Atoi(field)
+		run.sourcePorts = append(run.sourcePorts, port)
+	}
+
+	return run
+}
+
+// newBurstRun returns the run of the bursts of the burst profile, evenly spread over the bursts and their gaps.
+func newBurstRun(match []string, now time.Time) *trafficRun {
+	burstSize, _ := strconv.Atoi(match[2])
+	duration := trex.BurstCount * trex.BurstGapMicrosecs * time.Microsecond
+
+	run := &trafficRun{start: now, end: now.Add(duration), rate: float64(burstSize*trex.BurstCount) / duration.Seconds()}
+	for _, field := range strings.Fields(match[1]) {
+		port, _ := strconv.Atoi(field)
+		run.sourcePorts = append(run.sourcePorts, port)
+	}
+
+	return run
+}
+
+// packets returns the packets the run has sent from each of its source ports until the given time.
+func (r *trafficRun) packets(now time.Time) int64 {
+	end := r.end
+	if now.Before(end) {
+		end = now
+	}
+	return int64(r.rate * end.Sub(r.start).Seconds())
+}
+
+// counters returns the packets sent from and received on the given port, by the runs started since the given time.
+// The traffic sent from a port is forwarded back to its peer port.
+func (g *guest) counters(port int, since, now time.Time) (sent, received int64) {
+	for _, run := range g.runs {
+		if run.start.Before(since) {
+			continue
+		}
+		for _, sourcePort := range run.sourcePorts {
+			if sourcePort == port {
+				sent += run.packets(now)
+			}
+			if peerPort(sourcePort) == port {
+				received += run.packets(now)
+			}
+		}
+	}
+	return sent, received
+}
+
+// txRate returns the rate the running traffic is currently sent at, from all the ports.
+// As the TRex server smooths the rates it reports, the rate of a run is still reported shortly after it ends,
+// until it is stopped.
+func (g *guest) txRate(now time.Time) float64 {
+	const rateSmoothing = 2 * time.Second
+	var rate float64
+	for _, run := range g.runs {
+		if !run.stopped && now.Before(run.end.Add(rateSmoothing)) {
+			rate += run.rate * float64(len(run.sourcePorts))
+		}
+	}
+	return rate
+}
+
+func peerPort(port int) int {
+	return port ^ 1
+}
+
+func (g *guest) trexPortStats(port int, now time.Time) map[string]interface{} {
+	const packetSize = 64
+	sent, received := g.counters(port, g.trexClearTime, now)
+	return map[string]interface{}{
+		"ibytes":         received * packetSize,
+		"ierrors":        0,
+		"ipackets":       received,
+		"m_cpu_util":     0,
+		"m_total_rx_bps": 0,
+		"m_total_rx_pps": 0,
+		"m_total_tx_bps": 0,
+		"m_total_tx_pps": 0,
+		"obytes":         sent * packetSize,
+		"oerrors":        0,
+		"opackets":       sent,
+	}
+}
+
+func (g *guest) trexGlobalStats(now time.Time) map[string]interface{} {
+	const bitsPerPacket = 64 * 8
+	rate := g.txRate(now)
+	return map[string]interface{}{
+		"m_cpu_util":    10.0,
+		"m_tx_pps":      rate,
+		"m_rx_pps":      rate,
+		"m_tx_bps":      rate * bitsPerPacket,
+		"m_rx_bps":      rate * bitsPerPacket,
+		"m_rx_drop_bps": 0,
+	}
+}
+
+// trexSourcePorts returns the ports the traffic was sent from, since the stats were cleared.
+func (g *guest) trexSourcePorts() []int {
+	var ports []int
+	for _, run := range g.runs {
+		if run.start.Before(g.trexClearTime) {
+			continue
+		}
+		for _, port := range run.sourcePorts {
+			if !slices.Contains(ports, port) {
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
+
+// trexActivePgIDs returns the packet group IDs of the streams, a single stream being sent from each source port.
+func (g *guest) trexActivePgIDs() map[string]interface{} {
+	var pgIDs []int
+	for _, port := range g.trexSourcePorts() {
+		pgIDs = append(pgIDs, port*trex.PGIDPortStride)
+	}
+	return map[string]interface{}{"ids": map[string]interface{}{"flow_stats": pgIDs, "latency": []int{}}}
+}
+
+// trexPgIDStats returns the flow stats of the streams, which are forwarded back to the peer of their source port.
+func (g *guest) trexPgIDStats(now time.Time) map[string]interface{} {
+	flowStats := map[string]interface{}{}
+	for _, port := range g.trexSourcePorts() {
+		sent, _ := g.counters(port, g.trexClearTime, now)
+		flowStats[strconv.Itoa(port*trex.PGIDPortStride)] = map[string]interface{}{
+			"tx_pkts": map[string]int64{strconv.Itoa(port): sent},
+			"rx_pkts": map[string]int64{strconv.Itoa(peerPort(port)): sent},
+		}
+	}
+	return map[string]interface{}{"flow_stats": flowStats, "latency": map[string]interface{}{}}
+}
+
+// serverResponse prints the JSON-RPC response of the TRex server, as the TRex console prints it in verbose mode.
+func serverResponse(method string, params, result map[string]interface{}) string {
+	const indent = "    "
+	request, _ := json.MarshalIndent([]interface{}{
+		map[string]interface{}{"id": "sim", "jsonrpc": "2.0", "method": method, "params": params},
+	}, "", indent)
+	response, _ := json.MarshalIndent(map[string]interface{}{"id": "sim", "jsonrpc": "2.0", "result": result}, "", indent)
+	return fmt.Sprintf("\n[verbose] Sending Request To Server:\n\n%s\n\n[verbose] Server Response:\n\n%s\n\n", request, response)
+}
+
+func (g *guest) executeTestpmd(vmiName, line string, portsCount int) string {
+	switch line {
+	case "start":
+		return "io packet forwarding - ports=" + strconv.Itoa(portsCount) + "\n"
+	case "clear fwd stats all":
+		g.testpmdClearTime = time.Now()
+		return ""
+	case "show fwd stats all":
+		return g.testpmdFwdStats(portsCount, time.Now())
+	case "show port stats all":
+		return g.testpmdPortStats(portsCount, time.Now())
+	case "quit":
+		delete(g.testpmdPorts, vmiName)
+		return "Bye...\n"
+	}
+
+	return ""
+}
+
+func (g *guest) testpmdFwdStats(portsCount int, now time.Time) string {
+	sb := strings.Builder{}
+	var totalRX, totalTX int64
+	for port := 0; port < portsCount; port++ {
+		// testpmd receives what the traffic generator port sends, and sends what it receives.
+		rx, tx := g.counters(port, g.testpmdClearTime, now)
+		totalRX += rx
+		totalTX += tx
+		fmt.Fprintf(&sb, "\n  ---------------------- Forward statistics for port %d  ----------------------\n", port)
+		sb.WriteString(fwdStatsLines(rx, tx))
+		sb.WriteString("  ----------------------------------------------------------------------------\n")
+	}
+
+	sb.WriteString("\n  +++++++++++++++ Accumulated forward statistics for all ports+++++++++++++++\n")
+	sb.WriteString(fwdStatsLines(totalRX, totalTX))
+	sb.WriteString("  ++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++\n")
+	return sb.String()
+}
+
+func fwdStatsLines(rx, tx int64) string {
+	return fmt.Sprintf("  RX-packets: %-14d RX-dropped: %-14d RX-total: %d\n", rx, 0, rx) +
+		fmt.Sprintf("  TX-packets: %-14d TX-dropped: %-14d TX-total: %d\n", tx, 0, tx)
+}
+
+// testpmdTelemetry replies to the telemetry port stats queries, with the counters accumulated since testpmd started,
+// as the telemetry cannot reset them.
+func (g *guest) testpmdTelemetry(queries string, now time.Time) string {
+	sb := strings.Builder{}
+	for _, query := range strings.Split(strings.TrimSuffix(queries, `\n`), `\n`) {
+		port, _ := strconv.Atoi(strings.TrimPrefix(query, "/ethdev/stats,"))
+		rx, tx := g.counters(port, g.testpmdStartTime, now)
+		fmt.Fprintf(&sb, "--> %s\n", query)
+		fmt.Fprintf(&sb, `{"/ethdev/stats": {"ipackets": %d, "opackets": %d, "ibytes": %d, "obytes": %d, `+
+			`"imissed": 0, "ierrors": 0, "oerrors": 0, "rx_nombuf": 0}}`+"\n", rx, tx, rx*64, tx*64)
+	}
+	sb.WriteString("--> \n")
+	return sb.String()
+}
+
+func (g *guest) testpmdPortStats(portsCount int, now time.Time) string {
+	sb := strings.Builder{}
+	for port := 0; port < portsCount; port++ {
+		rx, tx := g.counters(port, g.testpmdClearTime, now)
+		fmt.Fprintf(&sb, "\n  ######################## NIC statistics for port %d  ########################\n", port)
+		fmt.Fprintf(&sb, "  RX-packets: %-10d RX-missed: 0          RX-bytes:  %d\n", rx, rx*64)
+		fmt.Fprintf(&sb, "  TX-packets: %-10d TX-errors: 0          TX-bytes:  %d\n", tx, tx*64)
+		sb.WriteString("  ############################################################################\n")
+	}
+	return sb.String()
+}
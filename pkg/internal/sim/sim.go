@@ -0,0 +1,85 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package sim
+
+import (
+	"os"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// The simulated checkup Pod runs in Namespace, configured by the ConfigMap named ConfigMapName.
+const (
+	Namespace     = "dpdk-checkup-sim"
+	ConfigMapName = "dpdk-checkup-config"
+	PodName       = "dpdk-checkup-sim"
+)
+
+// defaultConfigMapData configures a short run, using the simulated Network-Attachment-Definition.
+var defaultConfigMapData = map[string]string{
+	"spec.timeout": "10m",
+	"spec.param.networkAttachmentDefinitionName": NetworkAttachmentDefinitionName,
+	"spec.param.trafficGenContainerDiskImage":    "quay.io/kiagnose/kubevirt-dpdk-checkup-traffic-gen:main",
+	"spec.param.vmUnderTestContainerDiskImage":   "quay.io/kiagnose/kubevirt-dpdk-checkup-vm:main",
+	"spec.param.testDuration":                    "10s",
+	"spec.param.resultSinks":                     "stdout-only",
+}
+
+// Env returns the environment the checkup Pod is started with.
+func Env() map[string]string {
+	return map[string]string{
+		"CONFIGMAP_NAMESPACE": Namespace,
+		"CONFIGMAP_NAME":      ConfigMapName,
+		"HOSTNAME":            PodName,
+	}
+}
+
+// NewConfigMap returns the checkup ConfigMap, holding the given data over the defaults of a short run.
+func NewConfigMap(data map[string]string) *k8scorev1.ConfigMap {
+	configMapData := map[string]string{}
+	for key, value := range defaultConfigMapData {
+		configMapData[key] = value
+	}
+	for key, value := range data {
+		configMapData[key] = value
+	}
+
+	return &k8scorev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: ConfigMapName},
+		Data:       configMapData,
+	}
+}
+
+// ReadConfigMapData reads the data of a checkup ConfigMap manifest, e.g. the one the checkup is run with on a cluster.
+func ReadConfigMapData(manifestPath string) (map[string]string, error) {
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var configMap k8scorev1.ConfigMap
+	if err := yaml.Unmarshal(manifest, &configMap); err != nil {
+		return nil, err
+	}
+
+	return configMap.Data, nil
+}
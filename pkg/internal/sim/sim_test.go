@@ -0,0 +1,197 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package sim_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	kconfig "github.com/kiagnose/kiagnose/kiagnose/config"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/sim"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
+)
+
+func TestCheckupFlowOnTheSimulatedCluster(t *testing.T) {
+	testCases := map[string]map[string]string{
+		"VMIs":                 {},
+		"VMs":                  {config.UseVirtualMachinesParamName: "true"},
+		"same-node placement":  {config.SameNodePlacementParamName: "true"},
+		"per-direction runs":   {config.PerDirectionRunsParamName: "true"},
+		"NUMA passthrough":     {config.NUMAPassthroughParamName: "true"},
+		"tagged traffic":       {config.TrafficVlanIDParamName: "100"},
+		"several iterations":   {config.TestIterationsParamName: "2"},
+		"guest agent commands": {config.GuestCommandTransportParamName: config.GuestCommandTransportGuestAgent},
+		"TRex RPC stats":       {config.TrexTransportParamName: config.TrexTransportRPC},
+		"flow stats":           {config.TrafficGenFlowStatsParamName: "true"},
+		"flow stats over RPC": {
+			config.TrafficGenFlowStatsParamName: "true",
+			config.TrexTransportParamName:       config.TrexTransportRPC,
+		},
+		"pinned PCI addresses": {config.InterfacesParamName: `[{"pciAddress":"0000:06:00.0"},{"pciAddress":"0000:07:00.0"}]`},
+		"promiscuous scenario": {config.ScenariosParamName: "throughput,promiscuous"},
+		"burst scenario": {
+			config.ScenariosParamName:    "throughput,burst",
+			config.BurstMaxSizeParamName: "100",
+		},
+		"burst scenario first": {
+			config.ScenariosParamName:    "burst,throughput",
+			config.BurstMaxSizeParamName: "100",
+		},
+		"rate sweep scenario": {
+			config.ScenariosParamName:             "throughput,rate-sweep",
+			config.RateSweepStepsParamName:        "2",
+			config.RateSweepStepDurationParamName: "1s",
+		},
+		"rate sweep scenario first": {
+			config.ScenariosParamName:             "rate-sweep,throughput",
+			config.RateSweepStepsParamName:        "2",
+			config.RateSweepStepDurationParamName: "1s",
+		},
+		"soak snapshots": {
+			config.TestDurationParamName:     "25s",
+			config.SnapshotIntervalParamName: "10s",
+		},
+		"section scenarios first": {
+			config.ScenariosParamName:             "burst,rate-sweep,throughput",
+			config.BurstMaxSizeParamName:          "100",
+			config.RateSweepStepsParamName:        "2",
+			config.RateSweepStepDurationParamName: "1s",
+		},
+		"promiscuous scenario first": {
+			config.ScenariosParamName:        "promiscuous,throughput",
+			config.PerDirectionRunsParamName: "true",
+		},
+	}
+
+	for name, params := range testCases {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestConfig(t, params)
+			c := sim.NewClient()
+			testCheckup := checkup.New(c, sim.Namespace, cfg, executor.New(c, sim.Namespace, cfg, nil))
+
+			assert.NoError(t, testCheckup.Setup(context.Background()))
+			assert.NoError(t, testCheckup.Run(context.Background()))
+			assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+			results := testCheckup.Results()
+			assert.NotZero(t, results.TrafficGenSentPackets)
+			assert.Equal(t, results.TrafficGenSentPackets, results.VMUnderTestReceivedPackets)
+			assert.Zero(t, results.VMUnderTestRxDroppedPackets)
+			assert.Empty(t, c.VMIs())
+			assert.Equal(t, sim.TrexVersion, results.Environment.TrexVersion)
+			assert.Len(t, results.VFDrivers, 2*len(cfg.TestInterfaces()))
+			for idx, vfDriver := range results.VFDrivers {
+				iface := cfg.TestInterfaces()[idx%len(cfg.TestInterfaces())]
+				if iface.PCIAddress != "" {
+					assert.Equal(t, iface.PCIAddress, vfDriver.PCIAddress)
+				} else {
+					assert.NotEmpty(t, vfDriver.PCIAddress)
+				}
+			}
+			if cfg.TrafficGenFlowStats {
+				assert.NotEmpty(t, results.PerStreamStats)
+				assert.Equal(t, results.TrafficGenSentPackets, results.PerStreamStats[0].TXPackets)
+			}
+			if slices.Contains(cfg.Scenarios, config.ScenarioBurst) {
+				assert.Equal(t, &status.Burst{MaxSustainedSize: 100}, results.Burst)
+				assert.Len(t, results.Scenarios, 2)
+				assert.Equal(t, status.BurstScenarioID, results.Scenarios[1].ID)
+				assert.True(t, results.Scenarios[1].Succeeded)
+			}
+			if cfg.SnapshotInterval > 0 {
+				assert.NotNil(t, results.Soak)
+				assert.NotEmpty(t, results.Soak.Snapshots)
+				assert.Empty(t, results.Soak.AbortReason)
+			}
+			if slices.Contains(cfg.Scenarios, config.ScenarioRateSweep) {
+				assert.NotNil(t, results.RateSweep)
+				assert.Zero(t, results.RateSweep.FirstDroppingPercent)
+				assert.Len(t, results.RateSweep.Steps, 2)
+				for idx, step := range results.RateSweep.Steps {
+					assert.Equal(t, 50*(idx+1), step.LineRatePercent)
+					assert.NotZero(t, step.SentPackets)
+					assert.Zero(t, step.LostPackets)
+				}
+				assert.Less(t, results.RateSweep.Steps[0].TxPps, results.RateSweep.Steps[1].TxPps)
+			}
+			if slices.Contains(cfg.Scenarios, config.ScenarioPromiscuous) {
+				// the promiscuous-on and promiscuous-off scenarios, along with the unicast traffic of each direction
+				unicastScenarios := 1
+				if cfg.PerDirectionRuns {
+					unicastScenarios = 2
+				}
+				assert.Len(t, results.Scenarios, 2+unicastScenarios)
+				for _, scenario := range results.Scenarios {
+					assert.True(t, scenario.Succeeded, scenario.ID)
+					assert.NotZero(t, scenario.Counters.TrafficGenSentPackets, scenario.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckupAbortOnTheSimulatedCluster(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestConfig(t, map[string]string{config.TestDurationParamName: "1h"})
+	cfg.ConfigMapNamespace = sim.Namespace
+	cfg.ConfigMapName = sim.ConfigMapName
+	c := sim.NewClient(sim.NewConfigMap(map[string]string{config.AbortKey: "true"}))
+	testCheckup := checkup.New(c, sim.Namespace, cfg, executor.New(c, sim.Namespace, cfg, nil))
+
+	assert.NoError(t, testCheckup.Setup(context.Background()))
+	err := testCheckup.Run(context.Background())
+	assert.Equal(t, exitcode.Cancelled, exitcode.Of(err))
+	assert.NoError(t, testCheckup.Teardown(context.Background()))
+
+	results := testCheckup.Results()
+	assert.True(t, results.AbortRequested)
+	assert.Equal(t, results.TrafficGenSentPackets, results.VMUnderTestReceivedPackets)
+	assert.Nil(t, results.TxDuration)
+	assert.Empty(t, c.VMIs())
+}
+
+func newTestConfig(t *testing.T, params map[string]string) config.Config {
+	baseParams := map[string]string{
+		config.NetworkAttachmentDefinitionNameParamName: sim.NetworkAttachmentDefinitionName,
+		config.TrafficGenContainerDiskImageParamName:    "quay.io/kiagnose/kubevirt-dpdk-checkup-traffic-gen:main",
+		config.VMUnderTestContainerDiskImageParamName:   "quay.io/kiagnose/kubevirt-dpdk-checkup-vm:main",
+		config.TestDurationParamName:                    "1s",
+	}
+	for key, value := range params {
+		baseParams[key] = value
+	}
+
+	cfg, err := config.New(kconfig.Config{PodName: sim.PodName, PodUID: "0123456789", Params: baseParams})
+	assert.NoError(t, err)
+
+	return cfg
+}
@@ -0,0 +1,173 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package sim
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"kubevirt.io/client-go/kubecli"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/trex"
+)
+
+// VMIPortForward serves the TRex server JSON-RPC API on the TRex RPC port of the VMIs, over a ZMQ REP socket.
+func (c *Client) VMIPortForward(_, name string, port int) (kubecli.StreamInterface, error) {
+	if port != trex.RPCPort {
+		return nil, fmt.Errorf("port %d of VMI %q is not served", port, name)
+	}
+
+	clientConn, serverConn := net.Pipe()
+	go c.guest.serveTrexRPC(serverConn)
+
+	return pipeStream{conn: clientConn}, nil
+}
+
+type pipeStream struct {
+	conn net.Conn
+}
+
+func (s pipeStream) Stream(options kubecli.StreamOptions) error {
+	errs := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(s.conn, options.In)
+		errs <- err
+	}()
+	go func() {
+		_, err := io.Copy(options.Out, s.conn)
+		errs <- err
+	}()
+	return <-errs
+}
+
+func (s pipeStream) AsConn() net.Conn {
+	return s.conn
+}
+
+// serveTrexRPC serves the JSON-RPC requests, speaking ZMTP 3.0 with the NULL security mechanism.
+// The peer messages are read before writing, as the pipe writes block until they are read.
+func (g *guest) serveTrexRPC(conn net.Conn) {
+	defer conn.Close()
+
+	const greetingSize = 64
+	reader := bufio.NewReader(conn)
+	if _, err := io.ReadFull(reader, make([]byte, greetingSize)); err != nil {
+		return
+	}
+	greeting := make([]byte, greetingSize)
+	greeting[0], greeting[9], greeting[10] = 0xff, 0x7f, 3
+	copy(greeting[12:], "NULL")
+	greeting[32] = 1 // as-server
+	if _, err := conn.Write(greeting); err != nil {
+		return
+	}
+
+	if _, err := readZMTPFrame(reader); err != nil {
+		return
+	}
+	ready := []byte("\x05READY\x0bSocket-Type\x00\x00\x00\x03REP")
+	if _, err := conn.Write(append([]byte{0x04, byte(len(ready))}, ready...)); err != nil {
+		return
+	}
+
+	for {
+		// The REQ socket messages are an empty delimiter frame followed by the request.
+		if _, err := readZMTPFrame(reader); err != nil {
+			return
+		}
+		request, err := readZMTPFrame(reader)
+		if err != nil {
+			return
+		}
+
+		response := g.trexRPCResponse(request)
+		frame := []byte{0x01, 0x00, 0x02}
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(response)))
+		if _, err := conn.Write(append(frame, response...)); err != nil {
+			return
+		}
+	}
+}
+
+func (g *guest) trexRPCResponse(rawRequest []byte) []byte {
+	var request struct {
+		ID     string `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			PortID int `json:"port_id"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(rawRequest, &request); err != nil {
+		return nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	response := map[string]interface{}{"id": request.ID, "jsonrpc": "2.0"}
+	switch request.Method {
+	case "api_sync_v2":
+		response["result"] = map[string]interface{}{"api_h": "sim"}
+	case "get_global_stats":
+		response["result"] = g.trexGlobalStats(time.Now())
+	case "get_port_stats":
+		response["result"] = g.trexPortStats(request.Params.PortID, time.Now())
+	case "get_active_pgids":
+		response["result"] = g.trexActivePgIDs()
+	case "get_pgid_stats":
+		response["result"] = g.trexPgIDStats(time.Now())
+	default:
+		response["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+	}
+
+	rawResponse, _ := json.Marshal(response)
+	return rawResponse
+}
+
+func readZMTPFrame(reader *bufio.Reader) ([]byte, error) {
+	flags, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var size uint64
+	if flags&0x02 != 0 {
+		sizeBytes := make([]byte, 8)
+		if _, err := io.ReadFull(reader, sizeBytes); err != nil {
+			return nil, err
+		}
+		size = binary.BigEndian.Uint64(sizeBytes)
+	} else {
+		sizeByte, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size = uint64(sizeByte)
+	}
+
+	body := make([]byte, size)
+	_, err = io.ReadFull(reader, body)
+	return body, err
+}
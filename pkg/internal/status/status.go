@@ -19,17 +19,355 @@
 
 package status
 
-import kstatus "github.com/kiagnose/kiagnose/kiagnose/status"
+import (
+	"time"
+
+	kstatus "github.com/kiagnose/kiagnose/kiagnose/status"
+)
 
 type Results struct {
-	TrafficGenSentPackets        int64
-	TrafficGenOutputErrorPackets int64
-	TrafficGenInputErrorPackets  int64
-	VMUnderTestReceivedPackets   int64
-	VMUnderTestRxDroppedPackets  int64
-	VMUnderTestTxDroppedPackets  int64
-	TrafficGenActualNodeName     string
-	VMUnderTestActualNodeName    string
+	TrafficGenSentPackets        int64   `json:"trafficGenSentPackets"`
+	TrafficGenOutputErrorPackets int64   `json:"trafficGenOutputErrorPackets"`
+	TrafficGenInputErrorPackets  int64   `json:"trafficGenInputErrorPackets"`
+	VMUnderTestReceivedPackets   int64   `json:"vmUnderTestReceivedPackets"`
+	VMUnderTestRxDroppedPackets  int64   `json:"vmUnderTestRxDroppedPackets"`
+	VMUnderTestTxDroppedPackets  int64   `json:"vmUnderTestTxDroppedPackets"`
+	TrafficGenAvgTxPps           float64 `json:"trafficGenAvgTxPps"`
+	TrafficGenMaxTxPps           float64 `json:"trafficGenMaxTxPps"`
+	TrafficGenAvgRxPps           float64 `json:"trafficGenAvgRxPps"`
+	TrafficGenMaxRxPps           float64 `json:"trafficGenMaxRxPps"`
+	TrafficGenAvgTxBps           float64 `json:"trafficGenAvgTxBps"`
+	TrafficGenMaxTxBps           float64 `json:"trafficGenMaxTxBps"`
+	TrafficGenAvgRxBps           float64 `json:"trafficGenAvgRxBps"`
+	TrafficGenMaxRxBps           float64 `json:"trafficGenMaxRxBps"`
+	MaxDropRateBps               float64 `json:"maxDropRateBps"`
+	TrafficGenActualNodeName     string  `json:"trafficGenActualNodeName"`
+	VMUnderTestActualNodeName    string  `json:"vmUnderTestActualNodeName"`
+	OwnershipMode                string  `json:"ownershipMode"`
+	TrafficGenConsoleReconnects  int     `json:"trafficGenConsoleReconnects"`
+	VMUnderTestConsoleReconnects int     `json:"vmUnderTestConsoleReconnects"`
+	SetupRetries                 int     `json:"setupRetries,omitempty"`
+
+	TrafficGenGatewayResolution []GatewayResolution `json:"trafficGenGatewayResolution,omitempty"`
+
+	TrafficGenPortAddresses []PortAddress `json:"trafficGenPortAddresses,omitempty"`
+
+	VMUnderTestTestpmdCommand string `json:"vmUnderTestTestpmdCommand,omitempty"`
+
+	NodeReadiness []NodeReadiness `json:"nodeReadiness,omitempty"`
+
+	TrafficGenLauncherSecurity  *LauncherSecurityPosture `json:"trafficGenLauncherSecurity,omitempty"`
+	VMUnderTestLauncherSecurity *LauncherSecurityPosture `json:"vmUnderTestLauncherSecurity,omitempty"`
+
+	ResourceFootprint *ResourceFootprint `json:"resourceFootprint,omitempty"`
+
+	Environment *Environment `json:"environment,omitempty"`
+
+	CPUIsolation  string `json:"cpuIsolation,omitempty"`
+	NumaAlignment string `json:"numaAlignment,omitempty"`
+
+	VFDrivers     []VFDriver `json:"vfDrivers,omitempty"`
+	VFDriverHints []string   `json:"vfDriverHints,omitempty"`
+
+	Iterations        []PacketCounters   `json:"iterations,omitempty"`
+	IterationsSummary *IterationsSummary `json:"iterationsSummary,omitempty"`
+
+	StatsReads       []StatsRead `json:"statsReads,omitempty"`
+	StatsSkewWarning string      `json:"statsSkewWarning,omitempty"`
+
+	PerQueueStats []QueueStats `json:"perQueueStats,omitempty"`
+
+	PerStreamStats []StreamStats `json:"perStreamStats,omitempty"`
+
+	Criteria *CriteriaResults `json:"criteria,omitempty"`
+
+	TxDuration *TxDuration `json:"txDuration,omitempty"`
+
+	Migration *Migration `json:"migration,omitempty"`
+
+	Burst *Burst `json:"burst,omitempty"`
+
+	RateSweep *RateSweep `json:"rateSweep,omitempty"`
+
+	Soak *Soak `json:"soak,omitempty"`
+
+	AbortRequested bool `json:"abortRequested,omitempty"`
+
+	Baseline *BaselineComparison `json:"baseline,omitempty"`
+
+	Scenarios         []ScenarioResults `json:"scenarios,omitempty"`
+	DirectionsSummary string            `json:"directionsSummary,omitempty"`
+
+	Outcome        string `json:"outcome,omitempty"`
+	CancelledPhase string `json:"cancelledPhase,omitempty"`
+
+	Timeline Timeline `json:"timeline,omitempty"`
+
+	Diagnostics string `json:"diagnostics,omitempty"`
+}
+
+// Timeline holds the timing of the checkup phases, in the order they have ended.
+type Timeline []PhaseTiming
+
+// PhaseTiming holds the start and end timestamps of a single checkup phase.
+type PhaseTiming struct {
+	Phase string    `json:"phase"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Record adds the given phase to the timeline, as started at the given time and ended now.
+func (t *Timeline) Record(phase string, start time.Time) {
+	*t = append(*t, PhaseTiming{Phase: phase, Start: start, End: time.Now()})
+}
+
+// The IDs of the scenarios of per-direction runs, sending the traffic from the east (even) ports to the west (odd)
+// ports, and vice versa.
+const (
+	EastToWestScenarioID = "east-to-west"
+	WestToEastScenarioID = "west-to-east"
+)
+
+// SameNodeScenarioID is the ID of the scenario of a run whose VMIs were both placed on the same node.
+const SameNodeScenarioID = "same-node"
+
+// The IDs of the scenarios of a run with the promiscuous scenario, sending the unicast traffic,
+// then multicast and broadcast frames with the VM under test ports promiscuous mode enabled and disabled.
+const (
+	UnicastScenarioID        = "unicast"
+	PromiscuousOnScenarioID  = "promiscuous-on"
+	PromiscuousOffScenarioID = "promiscuous-off"
+)
+
+// BurstScenarioID is the ID of the scenario of the burst traffic, summing the counters of all the burst sizes.
+const BurstScenarioID = "burst"
+
+// ScenarioResults holds the verdict and counters of a single scenario of a multi-scenario run,
+// e.g. a single packet size of a packet sizes sweep.
+// CriteriaResults holds the outcome of each built-in verification criterion, and the overall score they weigh to.
+type CriteriaResults struct {
+	Outcomes []CriterionOutcome `json:"outcomes"`
+	// Score is the percentage of the criteria weights that passed, each criterion weighed by its severity.
+	Score int `json:"score"`
+}
+
+type CriterionOutcome struct {
+	Name          string `json:"name"`
+	Severity      string `json:"severity"`
+	Passed        bool   `json:"passed"`
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+type ScenarioResults struct {
+	ID            string         `json:"id"`
+	Succeeded     bool           `json:"succeeded"`
+	FailureReason string         `json:"failureReason,omitempty"`
+	Counters      PacketCounters `json:"counters"`
+}
+
+// PacketCounters holds the packet counters of a single traffic run.
+type PacketCounters struct {
+	TrafficGenSentPackets        int64 `json:"trafficGenSentPackets"`
+	TrafficGenOutputErrorPackets int64 `json:"trafficGenOutputErrorPackets"`
+	TrafficGenInputErrorPackets  int64 `json:"trafficGenInputErrorPackets"`
+	VMUnderTestReceivedPackets   int64 `json:"vmUnderTestReceivedPackets"`
+	VMUnderTestRxDroppedPackets  int64 `json:"vmUnderTestRxDroppedPackets"`
+	VMUnderTestTxDroppedPackets  int64 `json:"vmUnderTestTxDroppedPackets"`
+}
+
+// QueueStats holds the VM under test forward counters of a single forwarding stream, from an RX queue to a TX queue.
+type QueueStats struct {
+	RXPort    int   `json:"rxPort"`
+	RXQueue   int   `json:"rxQueue"`
+	TXPort    int   `json:"txPort"`
+	TXQueue   int   `json:"txQueue"`
+	RXPackets int64 `json:"rxPackets"`
+	TXPackets int64 `json:"txPackets"`
+	TXDropped int64 `json:"txDropped"`
+}
+
+// StreamStats holds the traffic generator counters of a single stream, identified by its TRex packet group ID.
+type StreamStats struct {
+	ID        int   `json:"id"`
+	TXPort    int   `json:"txPort"`
+	TXPackets int64 `json:"txPackets"`
+	RXPackets int64 `json:"rxPackets"`
+}
+
+// StatsRead records when the counters of each side were read, at the end of a traffic run.
+type StatsRead struct {
+	TrafficGen  time.Time `json:"trafficGen"`
+	VMUnderTest time.Time `json:"vmUnderTest"`
+}
+
+// Skew returns the time between the reads of both sides.
+func (r StatsRead) Skew() time.Duration {
+	if skew := r.TrafficGen.Sub(r.VMUnderTest); skew > 0 {
+		return skew
+	}
+	return r.VMUnderTest.Sub(r.TrafficGen)
+}
+
+// IterationsSummary holds the spread of the packet counters across all traffic iterations.
+type IterationsSummary struct {
+	TrafficGenSentPackets      CounterSummary `json:"trafficGenSentPackets"`
+	VMUnderTestReceivedPackets CounterSummary `json:"vmUnderTestReceivedPackets"`
+	VMUnderTestDroppedPackets  CounterSummary `json:"vmUnderTestDroppedPackets"`
+}
+
+type CounterSummary struct {
+	Min int64   `json:"min"`
+	Max int64   `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// GatewayResolution holds the default gateway of a traffic generator port, as resolved in TRex service mode.
+type GatewayResolution struct {
+	Port int    `json:"port"`
+	IP   string `json:"ip"`
+	MAC  string `json:"mac"`
+}
+
+// PortAddress holds the IPv4 address of a traffic generator port and its default gateway, as set in the TRex config.
+type PortAddress struct {
+	Port    int    `json:"port"`
+	IP      string `json:"ip"`
+	Gateway string `json:"gateway"`
+}
+
+// BaselineComparison holds the deltas of the results from the results of an earlier run, and whether they regressed.
+type BaselineComparison struct {
+	ConfigMapName        string  `json:"configMapName"`
+	AvgRxPpsDeltaPercent float64 `json:"avgRxPpsDeltaPercent"`
+	DroppedPacketsDelta  int64   `json:"droppedPacketsDelta"`
+	Verdict              string  `json:"verdict"`
+}
+
+// TxDuration compares the requested traffic duration with the time the traffic generator actually spent transmitting,
+// as implied by its sent packets and its average TX rate.
+type TxDuration struct {
+	RequestedSeconds float64 `json:"requestedSeconds"`
+	ActualSeconds    float64 `json:"actualSeconds"`
+	DeltaPercent     float64 `json:"deltaPercent"`
+}
+
+// Migration holds the outcome of the live migration of the VM under test, performed while the traffic ran.
+// LostPackets are the packets the traffic generator sent and did not receive back from the migration start to its end.
+type Migration struct {
+	SourceNode      string  `json:"sourceNode"`
+	TargetNode      string  `json:"targetNode"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	LostPackets     int64   `json:"lostPackets"`
+}
+
+// Burst holds the outcome of the burst scenario, sending bursts of increasing sizes until a burst size has drops.
+// MaxSustainedSize is the largest burst size forwarded without drops, zero when even the smallest burst size had drops.
+// FirstDroppingSize is the smallest burst size that had drops, zero when all the burst sizes were forwarded without drops.
+type Burst struct {
+	MaxSustainedSize  int `json:"maxSustainedSize"`
+	FirstDroppingSize int `json:"firstDroppingSize,omitempty"`
+}
+
+// RateSweep holds the outcome of the rate sweep scenario, sending the traffic at increasing percentages of the line rate.
+// Steps holds the loss at each rate, in increasing rate order.
+// FirstDroppingPercent is the lowest line rate percentage that had drops, zero when all the rates were forwarded without drops.
+type RateSweep struct {
+	Steps                []RateSweepStep `json:"steps"`
+	FirstDroppingPercent int             `json:"firstDroppingPercent,omitempty"`
+}
+
+// Soak holds the snapshots of the counters taken every snapshot interval during the unicast traffic.
+// AbortReason is set when the traffic was stopped before the end of the test duration, as the snapshots showed a stall
+// or a steady drops growth, so the counters are partial.
+type Soak struct {
+	Snapshots   []SoakSnapshot `json:"snapshots"`
+	AbortReason string         `json:"abortReason,omitempty"`
+}
+
+// SoakSnapshot holds the counters of the current traffic run, since its start, at a single snapshot.
+type SoakSnapshot struct {
+	Phase                     string  `json:"phase"`
+	ElapsedSeconds            float64 `json:"elapsedSeconds"`
+	TrafficGenSentPackets     int64   `json:"trafficGenSentPackets"`
+	TrafficGenReceivedPackets int64   `json:"trafficGenReceivedPackets"`
+	VMUnderTestDroppedPackets int64   `json:"vmUnderTestDroppedPackets"`
+}
+
+// RateSweepStep holds the rates and loss of a single rate of the rate sweep scenario.
+// The rates are the average rates over the step duration, as implied by the packets sent and received.
+// LostPackets counts the packets sent and not received, along with the packets the VM under test dropped.
+type RateSweepStep struct {
+	LineRatePercent int     `json:"lineRatePercent"`
+	TxPps           float64 `json:"txPps"`
+	RxPps           float64 `json:"rxPps"`
+	SentPackets     int64   `json:"sentPackets"`
+	LostPackets     int64   `json:"lostPackets"`
+	LossPercent     float64 `json:"lossPercent"`
+}
+
+// VFDriver is the kernel driver detected for a test NIC of a guest.
+// DriverVersion is the version of the driver module, or the guest kernel release for the modules versioned with it.
+type VFDriver struct {
+	Guest         string `json:"guest"`
+	PCIAddress    string `json:"pciAddress"`
+	Driver        string `json:"driver"`
+	DriverVersion string `json:"driverVersion,omitempty"`
+}
+
+// Environment holds the versions of the software the results depend on, to put the results in context when comparing
+// them across clusters.
+// A version that could not be detected is left empty.
+type Environment struct {
+	KubeVirtVersion        string `json:"kubeVirtVersion,omitempty"`
+	SRIOVDevicePluginImage string `json:"sriovDevicePluginImage,omitempty"`
+	SRIOVCNIImage          string `json:"sriovCNIImage,omitempty"`
+	VMUnderTestKernel      string `json:"vmUnderTestKernel,omitempty"`
+	TrafficGenKernel       string `json:"trafficGenKernel,omitempty"`
+	DPDKVersion            string `json:"dpdkVersion,omitempty"`
+	TrexVersion            string `json:"trexVersion,omitempty"`
+}
+
+// NodeReadiness holds the outcome of the pre-flight checks performed on a single node.
+// Score is the percentage of the checks that passed.
+type NodeReadiness struct {
+	NodeName     string             `json:"nodeName"`
+	Score        int                `json:"score"`
+	KernelFlavor string             `json:"kernelFlavor,omitempty"`
+	Checks       []PreflightOutcome `json:"checks"`
+}
+
+type PreflightOutcome struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// LauncherSecurityPosture holds the security context effectively applied to a virt-launcher Pod.
+type LauncherSecurityPosture struct {
+	PodName             string   `json:"podName"`
+	SELinuxType         string   `json:"seLinuxType,omitempty"`
+	SELinuxLevel        string   `json:"seLinuxLevel,omitempty"`
+	SeccompProfile      string   `json:"seccompProfile,omitempty"`
+	RunAsUser           *int64   `json:"runAsUser,omitempty"`
+	Privileged          bool     `json:"privileged"`
+	AddedCapabilities   []string `json:"addedCapabilities,omitempty"`
+	DroppedCapabilities []string `json:"droppedCapabilities,omitempty"`
+}
+
+// ResourceFootprint holds the node resources dedicated to the checkup VMIs, and how long the checkup held them.
+type ResourceFootprint struct {
+	DedicatedCPUs   int     `json:"dedicatedCPUs"`
+	HugepagesGiB    int64   `json:"hugepagesGiB"`
+	VFs             int     `json:"vfs"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+}
+
+// Progress is an intermediate status of the running checkup, along with the current traffic rates.
+type Progress struct {
+	Phase   string
+	TxPps   float64
+	RxPps   float64
+	DropBps float64
 }
 
 type Status struct {
@@ -0,0 +1,116 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ImageManifestPath is the file baked into the container disk images, holding the checkup version they were built for.
+const ImageManifestPath = "/etc/kubevirt-dpdk-checkup/version"
+
+// MinSupportedImage is the oldest checkup version whose container disk images are supported by this checkup.
+const MinSupportedImage = "0.5.0"
+
+// Checkup is the version of the checkup, it is overridden at build time.
+var Checkup = "0.5.0"
+
+var (
+	ErrInvalidVersion     = errors.New("invalid version")
+	ErrIncompatibleImage  = errors.New("incompatible container disk image")
+	ErrUnsupportedVersion = errors.New("unsupported checkup version")
+)
+
+// Version is a checkup release version. Only the major and minor parts affect the compatibility.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Parse parses a "[v]<major>.<minor>[.<patch>]" version, ignoring any pre-release or build suffix.
+func Parse(rawVersion string) (Version, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(rawVersion), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i != -1 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, rawVersion)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, rawVersion)
+		}
+		numbers[i] = number
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Before reports whether the version precedes the other version, by their major and minor parts.
+func (v Version) Before(other Version) bool {
+	return v.compare(other) < 0
+}
+
+// compare compares the major and minor parts of the versions.
+func (v Version) compare(other Version) int {
+	if v.Major != other.Major {
+		return v.Major - other.Major
+	}
+	return v.Minor - other.Minor
+}
+
+// CheckImage verifies that the given image, built for imageVersion, is supported by the running checkup:
+// it should be built for a checkup version between MinSupportedImage and the running checkup version.
+func CheckImage(image, imageVersion string) error {
+	checkupVersion, err := Parse(Checkup)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedVersion, err)
+	}
+
+	minSupportedVersion, err := Parse(MinSupportedImage)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedVersion, err)
+	}
+
+	builtFor, err := Parse(imageVersion)
+	if err != nil {
+		return fmt.Errorf("%w: image %s: %v", ErrIncompatibleImage, image, err)
+	}
+
+	if builtFor.compare(minSupportedVersion) < 0 || builtFor.compare(checkupVersion) > 0 {
+		return fmt.Errorf("%w: image %s built for checkup v%s, running v%s (supported images: v%s - v%s)",
+			ErrIncompatibleImage, image, builtFor, checkupVersion, minSupportedVersion, checkupVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package version_test
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/version"
+)
+
+func TestParseShouldSucceed(t *testing.T) {
+	testCases := map[string]version.Version{
+		"0.5":            {Major: 0, Minor: 5},
+		"0.5.1":          {Major: 0, Minor: 5, Patch: 1},
+		"v1.2.3":         {Major: 1, Minor: 2, Patch: 3},
+		" v0.6.0\r\n":    {Major: 0, Minor: 6},
+		"v0.6.0-rc1":     {Major: 0, Minor: 6},
+		"0.6.0+abcdef12": {Major: 0, Minor: 6},
+	}
+
+	for rawVersion, expectedVersion := range testCases {
+		actualVersion, err := version.Parse(rawVersion)
+		assert.NoError(t, err, rawVersion)
+		assert.Equal(t, expectedVersion, actualVersion, rawVersion)
+	}
+}
+
+func TestParseShouldFail(t *testing.T) {
+	for _, rawVersion := range []string{"", "1", "v1.x", "1.2.3.4", "1.-2", "latest"} {
+		_, err := version.Parse(rawVersion)
+		assert.ErrorIs(t, err, version.ErrInvalidVersion, rawVersion)
+	}
+}
+
+func TestBefore(t *testing.T) {
+	v0_25 := version.Version{Major: 0, Minor: 25, Patch: 9}
+	v0_26 := version.Version{Major: 0, Minor: 26}
+	v1_0 := version.Version{Major: 1}
+
+	assert.True(t, v0_25.Before(v0_26))
+	assert.True(t, v0_26.Before(v1_0))
+	assert.False(t, v0_26.Before(v0_26))
+	assert.False(t, v0_26.Before(version.Version{Major: 0, Minor: 26, Patch: 3}))
+	assert.False(t, v1_0.Before(v0_25))
+}
+
+func TestCheckImage(t *testing.T) {
+	defer restoreCheckupVersion(version.Checkup)
+	version.Checkup = "0.7.2"
+
+	t.Run("should succeed when the image is built for a supported checkup version", func(t *testing.T) {
+		for _, imageVersion := range []string{version.MinSupportedImage, "0.6.1", "0.7.0", "v0.7.9"} {
+			assert.NoError(t, version.CheckImage("quay.io/kiagnose/vm:latest", imageVersion), imageVersion)
+		}
+	})
+
+	t.Run("should fail when the image is built for an older checkup version", func(t *testing.T) {
+		err := version.CheckImage("quay.io/kiagnose/vm:latest", "0.3.0")
+		assert.ErrorIs(t, err, version.ErrIncompatibleImage)
+		assert.ErrorContains(t, err, "image quay.io/kiagnose/vm:latest built for checkup v0.3, running v0.7")
+	})
+
+	t.Run("should fail when the image is built for a newer checkup version", func(t *testing.T) {
+		err := version.CheckImage("quay.io/kiagnose/vm:latest", "1.0.0")
+		assert.ErrorIs(t, err, version.ErrIncompatibleImage)
+		assert.ErrorContains(t, err, "built for checkup v1.0, running v0.7")
+	})
+
+	t.Run("should fail when the image version is invalid", func(t *testing.T) {
+		assert.ErrorIs(t, version.CheckImage("quay.io/kiagnose/vm:latest", "unknown"), version.ErrIncompatibleImage)
+	})
+}
+
+func restoreCheckupVersion(checkupVersion string) {
+	version.Checkup = checkupVersion
+}
@@ -21,63 +21,295 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"strings"
+	"time"
+
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	kvcorev1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
 
 	kconfig "github.com/kiagnose/kiagnose/kiagnose/config"
 
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/artifacts"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/client"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/events"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/launcher"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/logfilter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/matrix"
 	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/reporter"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/sim"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/status"
 )
 
-func Run(rawEnv map[string]string, namespace string) error {
-	c, err := client.New()
+type statusReporter interface {
+	Report(status.Status) error
+}
+
+type progressReporter interface {
+	ReportProgress(status.Progress) error
+}
+
+// clusterClient is the client the checkup uses to access the cluster, either a real or a simulated one.
+type clusterClient interface {
+	kubernetes.Interface
+	CreateVirtualMachineInstance(ctx context.Context,
+		namespace string,
+		vmi *kvcorev1.VirtualMachineInstance) (*kvcorev1.VirtualMachineInstance, error)
+	GetVirtualMachineInstance(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstance, error)
+	ListVirtualMachineInstances(ctx context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineInstanceList, error)
+	DeleteVirtualMachineInstance(ctx context.Context, namespace, name string) error
+	WatchVirtualMachineInstance(ctx context.Context, namespace, name, resourceVersion string) (watch.Interface, error)
+	CreateVirtualMachine(ctx context.Context, namespace string, vm *kvcorev1.VirtualMachine) (*kvcorev1.VirtualMachine, error)
+	ListVirtualMachines(ctx context.Context, namespace, labelSelector string) (*kvcorev1.VirtualMachineList, error)
+	DeleteVirtualMachine(ctx context.Context, namespace, name string) error
+	MigrateVirtualMachineInstance(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error)
+	GetVirtualMachineInstanceMigration(ctx context.Context, namespace, name string) (*kvcorev1.VirtualMachineInstanceMigration, error)
+	VMISerialConsole(namespace, name string, timeout time.Duration) (kubecli.StreamInterface, error)
+	VMIPortForward(namespace, name string, port int) (kubecli.StreamInterface, error)
+	CreateConfigMap(ctx context.Context, namespace string, configMap *k8scorev1.ConfigMap) (*k8scorev1.ConfigMap, error)
+	GetConfigMap(ctx context.Context, namespace, name string) (*k8scorev1.ConfigMap, error)
+	ListConfigMaps(ctx context.Context, namespace, labelSelector string) (*k8scorev1.ConfigMapList, error)
+	DeleteConfigMap(ctx context.Context, namespace, name string) error
+	GetPod(ctx context.Context, namespace, name string) (*k8scorev1.Pod, error)
+	CreateEvent(ctx context.Context, namespace string, event *k8scorev1.Event) (*k8scorev1.Event, error)
+	ListPods(ctx context.Context, namespace, labelSelector string) (*k8scorev1.PodList, error)
+	GetNode(ctx context.Context, name string) (*k8scorev1.Node, error)
+	ListNodes(ctx context.Context, labelSelector string) (*k8scorev1.NodeList, error)
+	GetNetworkAttachmentDefinition(ctx context.Context, namespace, name string) (*netattdefv1.NetworkAttachmentDefinition, error)
+	GetKubeVirtVersion(ctx context.Context) (string, error)
+	ExecInPod(ctx context.Context, namespace, podName, container string, command []string) (string, error)
+}
+
+// Run runs the checkup until it completes, its timeout passes or the given context is cancelled,
+// e.g. when the checkup Job is deleted.
+// The cluster is accessed with the given kubeconfig file, or when empty, with the in-cluster config, falling back to
+// $KUBECONFIG or ~/.kube/config.
+func Run(ctx context.Context, rawEnv map[string]string, namespace, kubeconfigPath string) error {
+	c, err := client.NewFromKubeconfig(kubeconfigPath)
 	if err != nil {
 		return err
 	}
 
+	return run(ctx, c, rawEnv, namespace)
+}
+
+// RunLocalSim runs the checkup on an in-memory simulated cluster, whose VMIs consoles are played by scripted guests.
+// It exercises the whole Setup, Run and Teardown flow without KubeVirt, and verifies no VMI is left behind.
+// The params of the given checkup ConfigMap manifest, if any, are applied over the defaults of a short run.
+func RunLocalSim(ctx context.Context, configMapManifestPath string) error {
+	var configMapData map[string]string
+	if configMapManifestPath != "" {
+		var err error
+		if configMapData, err = sim.ReadConfigMapData(configMapManifestPath); err != nil {
+			return exitcode.Classify(exitcode.ConfigError, fmt.Errorf("failed to read %q: %w", configMapManifestPath, err))
+		}
+	}
+
+	c := sim.NewClient(sim.NewConfigMap(configMapData))
+
+	err := run(ctx, c, sim.Env(), sim.Namespace)
+	if leftovers := c.VMIs(); len(leftovers) > 0 {
+		return errors.Join(err, fmt.Errorf("VMIs were left after the teardown: %s", strings.Join(leftovers, ", ")))
+	}
+
+	return err
+}
+
+func run(ctx context.Context, c clusterClient, rawEnv map[string]string, namespace string) error {
 	baseConfig, err := kconfig.Read(c, rawEnv)
 	if err != nil {
-		return err
+		return exitcode.Classify(exitcode.ConfigError, err)
 	}
 
+	return launch(ctx, c, baseConfig, namespace)
+}
+
+// launch runs the checkup with the given base config, however it was read.
+func launch(ctx context.Context, c clusterClient, baseConfig kconfig.Config, namespace string) error {
 	cfg, err := config.New(baseConfig)
 	if err != nil {
-		return err
+		return exitcode.Classify(exitcode.ConfigError, err)
 	}
 
+	// The console output captured in the logs is filtered, so it is readable and does not leak the guests password.
+	log.SetOutput(logfilter.NewWriter(os.Stderr, cfg.VMIPassword))
+
 	printConfig(baseConfig, cfg)
 
-	dpdkCheckupExecutor := executor.New(c, namespace, cfg)
+	ctx, cancel := context.WithTimeout(ctx, baseConfig.Timeout)
+	defer cancel()
+
+	if cfg.ArtifactsPVCName != "" {
+		cfg.ArtifactsDir, err = artifacts.PVCMountPath(ctx, c, namespace, cfg.PodName, cfg.ArtifactsPVCName)
+		if err != nil {
+			return exitcode.Classify(exitcode.ConfigError, err)
+		}
+		log.Printf("exporting the artifacts to PVC %q, mounted at %q", cfg.ArtifactsPVCName, cfg.ArtifactsDir)
+	}
+
+	r := newReporter(c, baseConfig, cfg)
+	progress, _ := r.(progressReporter)
 	l := launcher.New(
-		checkup.New(c, namespace, cfg, dpdkCheckupExecutor),
-		reporter.New(c, baseConfig.ConfigMapNamespace, baseConfig.ConfigMapName),
+		newCheckup(c, namespace, cfg, progress),
+		r,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), baseConfig.Timeout)
-	defer cancel()
+	recorder := events.New(c, namespace, cfg.PodName, cfg.PodUID)
+	if err := l.Run(ctx); err != nil {
+		recorder.Warning(events.ReasonCheckupFailed, "the checkup failed: %v", err)
+		return err
+	}
+	recorder.Normal(events.ReasonCheckupSucceeded, "the checkup succeeded")
+
+	return nil
+}
+
+// newCheckup returns the checkup to launch, which runs over the node pairs matrix when a matrix node selector is set.
+func newCheckup(c clusterClient, namespace string, cfg config.Config, progress progressReporter) matrix.Checkup {
+	newPairCheckup := func(pairConfig config.Config) matrix.Checkup {
+		return checkup.New(c, namespace, pairConfig, executor.New(c, namespace, pairConfig, progress))
+	}
+
+	if cfg.MatrixNodeSelector != "" {
+		return matrix.New(c, cfg, newPairCheckup)
+	}
 
-	return l.Run(ctx)
+	return newPairCheckup(cfg)
+}
+
+// ExitCode returns the exit code the checkup binary should exit with, given the error Run returned.
+func ExitCode(err error) int {
+	return int(exitcode.Of(err))
+}
+
+func newReporter(c clusterClient, baseConfig kconfig.Config, cfg config.Config) statusReporter {
+	jsonResults := cfg.ResultsFormat == config.ResultsFormatJSON
+
+	var r statusReporter
+	if cfg.ResultSinks == config.ResultSinksStdoutOnly {
+		r = reporter.NewStdout(os.Stdout, jsonResults)
+	} else {
+		r = reporter.New(c, baseConfig.ConfigMapNamespace, baseConfig.ConfigMapName, jsonResults)
+	}
+
+	if cfg.ResultsObjectName != "" {
+		r = reporter.NewMulti(
+			r,
+			reporter.NewObject(c, baseConfig.ConfigMapNamespace, cfg.ResultsObjectKind, cfg.ResultsObjectName, jsonResults),
+		)
+	}
+
+	if cfg.ArtifactsDir != "" {
+		r = reporter.NewMulti(r, reporter.NewArtifacts(cfg.ArtifactsDir))
+	}
+
+	return r
 }
 
 func printConfig(baseConfig kconfig.Config, checkupConfig config.Config) {
 	log.Println("Using the following config:")
 	log.Printf("%q: %q", "timeout", baseConfig.Timeout)
+	log.Printf("%q: %q", "ownershipMode", checkupConfig.OwnershipMode())
 	log.Printf("%q: %q", config.NetworkAttachmentDefinitionNameParamName, checkupConfig.NetworkAttachmentDefinitionName)
+	log.Printf("%q: %q", config.NetworkAttachmentDefinitionNameEastParamName, checkupConfig.NetworkAttachmentDefinitionNameEast)
+	log.Printf("%q: %q", config.NetworkAttachmentDefinitionNameWestParamName, checkupConfig.NetworkAttachmentDefinitionNameWest)
+	for _, iface := range checkupConfig.TestInterfaces() {
+		log.Printf("%q: %q: NAD %q, trafficGen MAC %q, vmUnderTest MAC %q, PCI %q", config.InterfacesParamName, iface.Name,
+			iface.NetworkAttachmentDefinitionName, iface.TrafficGenMacAddress, iface.VMUnderTestMacAddress, iface.PCIAddress)
+	}
 	log.Printf("%q: %q", config.TrafficGenContainerDiskImageParamName, checkupConfig.TrafficGenContainerDiskImage)
 	log.Printf("%q: %q", config.TrafficGenTargetNodeNameParamName, checkupConfig.TrafficGenTargetNodeName)
+	log.Printf("%q: %q", config.TrafficGenNodeLabelSelectorParamName, checkupConfig.TrafficGenNodeLabelSelector)
 	log.Printf("%q: %q", config.TrafficGenPacketsPerSecondParamName, checkupConfig.TrafficGenPacketsPerSecond)
+	log.Printf("%q: %t", config.TrafficGenServiceModeParamName, checkupConfig.TrafficGenServiceMode)
+	log.Printf("%q: %d", config.TrafficVlanIDParamName, checkupConfig.TrafficVlanID)
+	log.Printf("%q: %d", config.TrafficGenFlowCountParamName, checkupConfig.TrafficGenFlowCount)
+	log.Printf("%q: %t", config.TrafficGenFlowStatsParamName, checkupConfig.TrafficGenFlowStats)
+	log.Printf("%q: %d", config.TrafficGenServerCoresParamName, checkupConfig.TrafficGenServerCores)
+	log.Printf("%q: %d", config.TrafficGenServerIOModeParamName, checkupConfig.TrafficGenServerIOMode)
+	log.Printf("%q: %t", config.TrafficGenScapyServerParamName, checkupConfig.TrafficGenScapyServer)
+	log.Printf("%q: %t", config.TrafficGenSoftwareModeParamName, checkupConfig.TrafficGenSoftwareMode)
+	log.Printf("%q: %q", config.IPFamilyParamName, checkupConfig.IPFamily)
+	log.Printf("%q: %v", config.TrafficGenGatewayMacAddressesParamName, checkupConfig.TrafficGenGatewayMacAddresses)
+	log.Printf("%q: %v", config.TrafficGenPortIPsParamName, checkupConfig.TrafficGenPortIPs)
+	log.Printf("%q: %v", config.TrafficGenPortGatewaysParamName, checkupConfig.TrafficGenPortGateways)
 	log.Printf("%q: %q", "trafficGenEastMacAddress", checkupConfig.TrafficGenEastMacAddress)
 	log.Printf("%q: %q", "trafficGenWestMacAddress", checkupConfig.TrafficGenWestMacAddress)
 	log.Printf("%q: %q", config.VMUnderTestContainerDiskImageParamName, checkupConfig.VMUnderTestContainerDiskImage)
 	log.Printf("%q: %q", config.VMUnderTestTargetNodeNameParamName, checkupConfig.VMUnderTestTargetNodeName)
+	log.Printf("%q: %q", config.VMUnderTestNodeLabelSelectorParamName, checkupConfig.VMUnderTestNodeLabelSelector)
 	log.Printf("%q: %q", "vmUnderTestEastMacAddress", checkupConfig.VMUnderTestEastMacAddress)
 	log.Printf("%q: %q", "vmUnderTestWestMacAddress", checkupConfig.VMUnderTestWestMacAddress)
 	log.Printf("%q: %q", config.TestDurationParamName, checkupConfig.TestDuration)
+	log.Printf("%q: %d", config.TestIterationsParamName, checkupConfig.TestIterations)
+	log.Printf("%q: %t", config.PerDirectionRunsParamName, checkupConfig.PerDirectionRuns)
+	log.Printf("%q: %q", config.ScenariosParamName, strings.Join(checkupConfig.Scenarios, ","))
+	log.Printf("%q: %d", config.BurstMaxSizeParamName, checkupConfig.BurstMaxSize)
+	log.Printf("%q: %d", config.RateSweepStepsParamName, checkupConfig.RateSweepSteps)
+	log.Printf("%q: %q", config.RateSweepStepDurationParamName, checkupConfig.RateSweepStepDuration)
+	log.Printf("%q: %q", config.WarmupDurationParamName, checkupConfig.WarmupDuration)
 	log.Printf("%q: %q", config.PortBandwidthGbpsParamName, fmt.Sprintf("%d", checkupConfig.PortBandwidthGbps))
 	log.Printf("%q: %t", config.VerboseParamName, checkupConfig.Verbose)
+	log.Printf("%q: %q", config.ConsoleTranscriptParamName, checkupConfig.ConsoleTranscript)
+	log.Printf("%q: %t", config.LogConsoleTranscriptsParamName, checkupConfig.LogConsoleTranscripts)
+	log.Printf("%q: %q", config.SkipTeardownParamName, checkupConfig.SkipTeardown)
+	log.Printf("%q: %t", config.AllowConcurrentRunsParamName, checkupConfig.AllowConcurrentRuns)
+	log.Printf("%q: %q", config.PriorityClassNameParamName, checkupConfig.PriorityClassName)
+	log.Printf("%q: %q", config.RuntimeClassNameParamName, checkupConfig.RuntimeClassName)
+	log.Printf("%q: %v", config.VMIExtraLabelsParamName, checkupConfig.VMIExtraLabels)
+	log.Printf("%q: %v", config.VMIExtraAnnotationsParamName, checkupConfig.VMIExtraAnnotations)
+	log.Printf("%q: %+v", config.VMITolerationsParamName, checkupConfig.VMITolerations)
+	log.Printf("%q: %q", config.CPUIsolationCheckParamName, checkupConfig.CPUIsolationCheck)
+	log.Printf("%q: %q", config.MeasurementIsolationParamName, checkupConfig.MeasurementIsolation)
+	log.Printf("%q: %v", config.CriteriaSeveritiesParamName, checkupConfig.CriteriaSeverities)
+	log.Printf("%q: %t", config.UseVirtualMachinesParamName, checkupConfig.UseVirtualMachines)
+	log.Printf("%q: %t", config.RequireRealtimeKernelParamName, checkupConfig.RequireRealtimeKernel)
+	log.Printf("%q: %t", config.NUMAPassthroughParamName, checkupConfig.NUMAPassthrough)
+	log.Printf("%q: %t", config.MigrateVMUnderTestParamName, checkupConfig.MigrateVMUnderTest)
+	log.Printf("%q: %.0f", config.MaxAllowedDropRateBpsParamName, checkupConfig.MaxAllowedDropRateBps)
+	log.Printf("%q: %q", config.SnapshotIntervalParamName, checkupConfig.SnapshotInterval)
+	log.Printf("%q: %q", config.MatrixNodeSelectorParamName, checkupConfig.MatrixNodeSelector)
+	log.Printf("%q: %q", config.MatrixTestDurationParamName, checkupConfig.MatrixTestDuration)
+	log.Printf("%q: %t", config.SameNodePlacementParamName, checkupConfig.SameNodePlacement)
+	log.Printf("%q: %t", config.RequireDifferentNodesParamName, checkupConfig.RequireDifferentNodes)
+	log.Printf("%q: %d", config.SetupRetriesParamName, checkupConfig.SetupRetries)
+	log.Printf("%q: %q", config.SetupAttemptTimeoutParamName, checkupConfig.SetupAttemptTimeout)
+	log.Printf("%q: %q", config.SetupRetryBackoffParamName, checkupConfig.SetupRetryBackoff)
+	log.Printf("%q: %q", config.SetupTimeoutParamName, checkupConfig.SetupTimeout)
+	log.Printf("%q: %q", config.VMIBootTimeoutParamName, checkupConfig.VMIBootTimeout)
+	log.Printf("%q: %q", config.ConsoleCommandTimeoutParamName, checkupConfig.ConsoleCommandTimeout)
+	log.Printf("%q: %q", config.TeardownTimeoutParamName, checkupConfig.TeardownTimeout)
+	log.Printf("%q: %q", config.GuestCommandTransportParamName, checkupConfig.GuestCommandTransport)
+	log.Printf("%q: %q", config.TrexBinaryPathParamName, checkupConfig.TrexBinaryPath)
+	log.Printf("%q: %q", config.TrexTransportParamName, checkupConfig.TrexTransport)
+	log.Printf("%q: %q", config.TestpmdBinaryPathParamName, checkupConfig.TestpmdBinaryPath)
+	log.Printf("%q: %d", config.TestpmdMbufSizeParamName, checkupConfig.TestpmdMbufSize)
+	log.Printf("%q: %d", config.TestpmdTotalNumMbufsParamName, checkupConfig.TestpmdTotalNumMbufs)
+	log.Printf("%q: %d", config.TestpmdMemoryChannelsParamName, checkupConfig.TestpmdMemoryChannels)
+	log.Printf("%q: %v", config.TestpmdCPUsParamName, checkupConfig.TestpmdCPUs)
+	log.Printf("%q: %+v", "trexCPUs", checkupConfig.TrexCPUAssignment())
+	log.Printf("%q: %q", config.VMUnderTestWorkloadParamName, checkupConfig.VMUnderTestWorkload)
+	log.Printf("%q: %q", config.NICDriverModeParamName, checkupConfig.NICDriverMode)
+	log.Printf("%q: %q", config.ResultSinksParamName, checkupConfig.ResultSinks)
+	log.Printf("%q: %q", config.ResultsFormatParamName, checkupConfig.ResultsFormat)
+	log.Printf("%q: %q", config.ResultsObjectNameParamName, checkupConfig.ResultsObjectName)
+	log.Printf("%q: %q", config.ResultsObjectKindParamName, checkupConfig.ResultsObjectKind)
+	log.Printf("%q: %q", config.ArtifactsDirParamName, checkupConfig.ArtifactsDir)
+	log.Printf("%q: %q", config.ArtifactsPVCNameParamName, checkupConfig.ArtifactsPVCName)
+	log.Printf("%q: %t", config.ReproScriptParamName, checkupConfig.ReproScript)
+	log.Printf("%q: %q", config.ProgressIntervalParamName, checkupConfig.ProgressInterval)
+	log.Printf("%q: %q", config.BaselineConfigMapNameParamName, checkupConfig.BaselineConfigMapName)
 }
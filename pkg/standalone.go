@@ -0,0 +1,164 @@
+/*
+ * This file is part of the kiagnose project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	kconfig "github.com/kiagnose/kiagnose/kiagnose/config"
+
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/checkup/executor"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/client"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/config"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/exitcode"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/launcher"
+	"github.com/kiagnose/kubevirt-dpdk-checkup/pkg/internal/version"
+)
+
+// StandaloneOptions configure a run outside of the kiagnose ConfigMap contract, e.g. from a laptop or a CI runner.
+// The params are given directly rather than read from the checkup ConfigMap, and the results are printed to stdout.
+type StandaloneOptions struct {
+	// Kubeconfig is the path of the kubeconfig file of the cluster to run on. When empty, the in-cluster config is used,
+	// falling back to $KUBECONFIG or ~/.kube/config.
+	Kubeconfig string
+	// Namespace is the namespace the checkup VMIs are created in.
+	Namespace string
+	Timeout   time.Duration
+	// Params are the checkup params, keyed by their names, e.g. "testDuration".
+	Params map[string]string
+}
+
+// RunStandalone runs the checkup with the given options, until it completes, its timeout passes or the given context
+// is cancelled.
+func RunStandalone(ctx context.Context, opts StandaloneOptions) error {
+	c, baseConfig, err := newStandalone(opts)
+	if err != nil {
+		return err
+	}
+
+	return launch(ctx, c, baseConfig, opts.Namespace)
+}
+
+// RunPreflight runs the pre-flight checks of the checkup with the given options, without creating any resource:
+// the nodes readiness, and the validation of the Network-Attachment-Definitions and CPU assignments.
+func RunPreflight(ctx context.Context, opts StandaloneOptions) error {
+	c, baseConfig, err := newStandalone(opts)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.New(baseConfig)
+	if err != nil {
+		return exitcode.Classify(exitcode.ConfigError, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, baseConfig.Timeout)
+	defer cancel()
+
+	pairCheckup := checkup.New(c, opts.Namespace, cfg, executor.New(c, opts.Namespace, cfg, nil))
+	return launcher.New(preflightCheckup{pairCheckup}, newReporter(c, baseConfig, cfg)).Run(ctx)
+}
+
+// Version returns the checkup version, as set at build time.
+func Version() string {
+	return version.Checkup
+}
+
+// ParamsEnvPrefix prefixes the names of the environment variables setting the params of a standalone run,
+// e.g. DPDK_CHECKUP_PARAM_testDuration.
+const ParamsEnvPrefix = "DPDK_CHECKUP_PARAM_"
+
+// ParseParams returns the params of a standalone run, set by the given environment variables, and overridden by the
+// given "<name>=<value>" pairs.
+func ParseParams(environ, pairs []string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, env := range environ {
+		if name, value, found := strings.Cut(env, "="); found && strings.HasPrefix(name, ParamsEnvPrefix) {
+			params[strings.TrimPrefix(name, ParamsEnvPrefix)] = value
+		}
+	}
+
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, exitcode.Classify(exitcode.ConfigError, fmt.Errorf("invalid param %q, <name>=<value> is expected", pair))
+		}
+		params[name] = value
+	}
+
+	return params, nil
+}
+
+// ConfigError classifies the given error as a configuration error, e.g. on invalid command line flags.
+func ConfigError(err error) error {
+	return exitcode.Classify(exitcode.ConfigError, err)
+}
+
+func newStandalone(opts StandaloneOptions) (*client.Client, kconfig.Config, error) {
+	params := maps.Clone(opts.Params)
+	if params == nil {
+		params = map[string]string{}
+	}
+	// There is no checkup ConfigMap to report the results to
+	switch params[config.ResultSinksParamName] {
+	case "":
+		params[config.ResultSinksParamName] = config.ResultSinksStdoutOnly
+	case config.ResultSinksConfigMap:
+		return nil, kconfig.Config{}, exitcode.Classify(exitcode.ConfigError,
+			fmt.Errorf("%q %q requires the checkup ConfigMap", config.ResultSinksParamName, config.ResultSinksConfigMap))
+	}
+
+	c, err := client.NewFromKubeconfig(opts.Kubeconfig)
+	if err != nil {
+		return nil, kconfig.Config{}, exitcode.Classify(exitcode.ConfigError, err)
+	}
+
+	baseConfig := kconfig.Config{
+		ConfigMapNamespace: opts.Namespace,
+		UID:                uuid.NewString(),
+		Timeout:            opts.Timeout,
+		Params:             params,
+	}
+	return c, baseConfig, nil
+}
+
+// preflightCheckup runs the pre-flight checks of a checkup as its setup, skipping its run and teardown altogether.
+type preflightCheckup struct {
+	*checkup.Checkup
+}
+
+func (c preflightCheckup) Setup(ctx context.Context) error {
+	return c.Preflight(ctx)
+}
+
+func (c preflightCheckup) Run(context.Context) error {
+	return nil
+}
+
+func (c preflightCheckup) Teardown(context.Context) error {
+	return nil
+}
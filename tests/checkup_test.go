@@ -242,6 +242,7 @@ func newKiagnoseConfigMapAccessRole(configMapAccessRole string) *rbacv1.Role {
 	}
 }
 
+// newKubeVirtDPDKCheckerRole returns the checkup Role, as documented in the README.
 func newKubeVirtDPDKCheckerRole() *rbacv1.Role {
 	return &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
@@ -251,7 +252,12 @@ func newKubeVirtDPDKCheckerRole() *rbacv1.Role {
 			{
 				APIGroups: []string{"kubevirt.io"},
 				Resources: []string{"virtualmachineinstances"},
-				Verbs:     []string{"create", "get", "delete"},
+				Verbs:     []string{"create", "get", "list", "delete", "watch"},
+			},
+			{
+				APIGroups: []string{"kubevirt.io"},
+				Resources: []string{"virtualmachines"},
+				Verbs:     []string{"create", "list", "delete"},
 			},
 			{
 				APIGroups: []string{"subresources.kubevirt.io"},
@@ -261,7 +267,27 @@ func newKubeVirtDPDKCheckerRole() *rbacv1.Role {
 			{
 				APIGroups: []string{""},
 				Resources: []string{"configmaps"},
-				Verbs:     []string{"create", "delete"},
+				Verbs:     []string{"create", "list", "delete"},
+			},
+			{
+				APIGroups: []string{"k8s.cni.cncf.io"},
+				Resources: []string{"network-attachment-definitions"},
+				Verbs:     []string{"get"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/finalizers"},
+				Verbs:     []string{"update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"create"},
 			},
 		},
 	}